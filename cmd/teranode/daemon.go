@@ -13,6 +13,7 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/blob/file"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/merkleproof"
 	"github.com/ordishs/gocore"
 )
 
@@ -64,6 +65,12 @@ func RunDaemon(progname, version, commit string) {
 
 	fmt.Printf("File store semaphores initialized: read=%d, write=%d\n", readLimit, writeLimit)
 
+	// Initialize the node wide validation goroutine cap before any validation starts
+	util.InitValidationLimiter(tSettings.Validator.MaxValidationGoroutines)
+
+	// Initialize the node wide cap on concurrent merkle proof constructions before any proof is served
+	merkleproof.InitLimiter(tSettings.Asset.MaxConcurrentProofs, tSettings.Asset.ProofQueueTimeout)
+
 	logger := ulogger.InitLogger(progname, tSettings)
 
 	util.InitGRPCResolver(logger, tSettings.GRPCResolver)
@@ -45,6 +45,7 @@ var (
 	globalStoreMutex  sync.RWMutex
 	healthRegistered  atomic.Bool
 	metricsRegistered atomic.Bool
+	statsDRegistered  atomic.Bool
 	pprofRegistered   atomic.Bool
 	traceCloser       io.Closer
 )
@@ -362,6 +363,12 @@ func (d *Daemon) Start(logger ulogger.Logger, args []string, appSettings *settin
 
 	logger.Infof("Health check endpoint listening on %s", listenAddress)
 
+	if appSettings.GracefulRestartEnabled {
+		go d.handleGracefulRestart(logger, appSettings, sm)
+	}
+
+	go notifyReplacementReady(logger, sm)
+
 	// Create a channel to receive the wait result
 	waitErr := make(chan error, 1)
 	go func() {
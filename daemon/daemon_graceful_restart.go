@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/servicemanager"
+)
+
+// handleGracefulRestart waits for SIGUSR2 and then starts a replacement process that inherits the listening
+// sockets of all services. Once the replacement reports that its services are started, the daemon is stopped
+// gracefully, finishing the requests it is handling while the replacement accepts the new connections. When the
+// replacement fails to start, the daemon keeps serving.
+func (d *Daemon) handleGracefulRestart(logger ulogger.Logger, appSettings *settings.Settings, sm *servicemanager.ServiceManager) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR2)
+
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-sm.Ctx.Done():
+			return
+		case <-sigs:
+			logger.Infof("🟠 Received graceful restart signal. Handing off listeners...")
+
+			replacement, err := util.StartReplacementProcess(logger, appSettings.Context)
+			if err != nil {
+				logger.Errorf("Graceful restart failed, continuing to serve: %v", err)
+				continue
+			}
+
+			if err = replacement.WaitReady(sm.Ctx, appSettings.GracefulRestartReadyTimeout); err != nil {
+				logger.Errorf("Graceful restart failed, continuing to serve: %v", err)
+				continue
+			}
+
+			logger.Infof("🟠 Replacement process %d is ready. Stopping services...", replacement.Process.Pid)
+
+			if err = d.Stop(); err != nil {
+				logger.Errorf("Error stopping services after graceful restart: %v", err)
+			}
+
+			return
+		}
+	}
+}
+
+// notifyReplacementReady reports to the process this daemon replaces, if any, that all services are started.
+func notifyReplacementReady(logger ulogger.Logger, sm *servicemanager.ServiceManager) {
+	if os.Getenv(util.ReplacementReadyEnv) == "" {
+		return
+	}
+
+	sm.WaitForServiceToBeReady()
+
+	// a service that failed to start cancels the context of the service manager
+	if sm.Ctx.Err() != nil {
+		logger.Errorf("Services failed to start, not reporting ready to the replaced process")
+		return
+	}
+
+	if err := util.NotifyReplacementReady(); err != nil {
+		logger.Errorf("Failed to report ready to the replaced process: %v", err)
+		return
+	}
+
+	logger.Infof("Reported ready to the replaced process")
+}
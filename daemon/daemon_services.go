@@ -30,6 +30,7 @@ import (
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util/kafka"
 	"github.com/bsv-blockchain/teranode/util/servicemanager"
+	"github.com/bsv-blockchain/teranode/util/statsd"
 	"github.com/bsv-blockchain/teranode/util/tracing"
 	"github.com/felixge/fgprof"
 	"github.com/ordishs/gocore"
@@ -84,6 +85,11 @@ func (d *Daemon) startServices(ctx context.Context, logger ulogger.Logger, appSe
 	// start the profiler if enabled
 	startProfilerAndMetrics(logger, appSettings)
 
+	// start the StatsD metrics exporter if enabled
+	if err := startStatsDExporter(ctx, logger, appSettings); err != nil {
+		return err
+	}
+
 	if appSettings.UseDatadogProfiler {
 		deferFn := datadogProfiler()
 		defer deferFn()
@@ -212,6 +218,24 @@ func startProfilerAndMetrics(logger ulogger.Logger, appSettings *settings.Settin
 	}
 }
 
+// startStatsDExporter starts exporting the Prometheus metrics to StatsD if a StatsD endpoint is set in the app settings.
+func startStatsDExporter(ctx context.Context, logger ulogger.Logger, appSettings *settings.Settings) error {
+	if appSettings.StatsDEndpoint == "" || statsDRegistered.Load() {
+		return nil
+	}
+
+	exporter, err := statsd.NewExporter(logger, appSettings.StatsDEndpoint, appSettings.StatsDPrefix,
+		appSettings.StatsDMetrics, appSettings.StatsDFlushInterval, nil)
+	if err != nil {
+		return err
+	}
+
+	statsDRegistered.Store(true)
+	exporter.Start(ctx)
+
+	return nil
+}
+
 // startBlockchainService initializes and starts the Blockchain service.
 func (d *Daemon) startBlockchainService(ctx context.Context, appSettings *settings.Settings,
 	args []string, createLogger func(string) ulogger.Logger) error {
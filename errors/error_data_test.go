@@ -280,3 +280,58 @@ func TestGetErrorData(t *testing.T) {
 		})
 	}
 }
+
+// TestError_WithData tests that WithData annotates a copy of the error, and GetData finds the data in the chain.
+func TestError_WithData(t *testing.T) {
+	t.Run("copy keeps the code and leaves the error unchanged", func(t *testing.T) {
+		inner := NewTxMissingParentError("parent not found")
+		inner.SetData("input_index", 1)
+
+		err := NewTxInvalidError("transaction is invalid", inner)
+		annotated := err.WithData(map[string]interface{}{"txid": "abc", "tx_index": 3})
+
+		require.True(t, Is(annotated, ErrTxInvalid))
+		require.True(t, Is(annotated, ErrTxMissingParent))
+		require.Equal(t, err.Message(), annotated.Message())
+
+		require.Equal(t, "abc", GetData(annotated, "txid"))
+		require.Equal(t, 3, GetData(annotated, "tx_index"))
+		require.Equal(t, 1, GetData(annotated, "input_index"))
+		require.Nil(t, GetData(annotated, "level"))
+
+		require.Nil(t, err.Data())
+		require.Nil(t, GetData(err, "txid"))
+	})
+
+	t.Run("existing data is kept", func(t *testing.T) {
+		err := NewProcessingError("failed")
+		err.SetData("reason", "timeout")
+
+		annotated := err.WithData(map[string]interface{}{"txid": "abc"})
+
+		require.Equal(t, "timeout", annotated.GetData("reason"))
+		require.Equal(t, "abc", annotated.GetData("txid"))
+		require.Nil(t, err.GetData("txid"))
+	})
+
+	t.Run("typed data is kept in the chain", func(t *testing.T) {
+		hash := chainhash.HashH([]byte("spent"))
+
+		err := NewUtxoSpentError(hash, 1, hash, nil)
+		annotated := err.WithData(map[string]interface{}{"txid": "abc"})
+
+		require.True(t, Is(annotated, ErrSpent))
+		require.Equal(t, "abc", GetData(annotated, "txid"))
+
+		var spentData *UtxoSpentErrData
+		require.True(t, AsData(annotated, &spentData))
+		require.Equal(t, uint32(1), spentData.Vout)
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		var err *Error
+
+		require.Nil(t, err.WithData(map[string]interface{}{"txid": "abc"}))
+		require.Nil(t, GetData(nil, "txid"))
+	})
+}
@@ -13,6 +13,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"runtime"
 	"strings"
@@ -251,6 +252,54 @@ func (e *Error) GetData(key string) interface{} {
 	return e.data.GetData(key)
 }
 
+// WithData returns a copy of the error with the key-value pairs added to its data, leaving the error itself
+// unchanged, since it may be shared, e.g. a cached validation result. The copy has the code, message and wrapped
+// error of the error, so it matches the same errors. An error carrying typed data, such as the data of a UTXO spent
+// error, is wrapped by the copy instead, keeping its data in the chain.
+func (e *Error) WithData(data map[string]interface{}) *Error {
+	if e == nil {
+		return nil
+	}
+
+	annotated := *e
+	errData := make(ErrData, len(data))
+
+	switch d := e.data.(type) {
+	case nil:
+	case *ErrData:
+		if d != nil {
+			maps.Copy(errData, *d)
+		}
+	default:
+		annotated.wrappedErr = e
+	}
+
+	maps.Copy(errData, data)
+	annotated.data = &errData
+
+	return &annotated
+}
+
+// GetData returns the value of the data key of the first error in the chain of the error carrying it, or nil when
+// no error of the chain carries the key.
+func GetData(err error, key string) interface{} {
+	if isGRPCWrappedError(err) {
+		err = UnwrapGRPC(err)
+	}
+
+	var castedErr *Error
+
+	for errors.As(err, &castedErr) && castedErr != nil {
+		if value := castedErr.GetData(key); value != nil {
+			return value
+		}
+
+		err = castedErr.wrappedErr
+	}
+
+	return nil
+}
+
 // New creates a new Error instance with the specified code, message, and optional parameters.
 func New(code ERR, message string, params ...interface{}) *Error {
 	var wErr *Error
@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"runtime"
 	"sort"
 	"strings"
@@ -370,14 +371,16 @@ func (b *Block) Valid(ctx context.Context, logger ulogger.Logger, subtreeStore S
 	)
 	defer deferFn()
 
-	// 1. Check that the block header hash is less than the target difficulty.
-	headerValid, _, err := b.Header.HasMetTargetDifficulty()
-	if err != nil {
-		return false, errors.NewProcessingError("[BLOCK][%s] error checking target difficulty", b.String(), err)
-	}
+	// 1. Check that the block header hash is less than the target difficulty, unless the network does not use proof of work.
+	if settings.ProofOfWorkCheckRequired() {
+		headerValid, _, err := b.Header.HasMetTargetDifficulty()
+		if err != nil {
+			return false, errors.NewProcessingError("[BLOCK][%s] error checking target difficulty", b.String(), err)
+		}
 
-	if !headerValid {
-		return false, errors.NewBlockInvalidError("[BLOCK][%s] block header hash is not less than the target difficulty", b.String())
+		if !headerValid {
+			return false, errors.NewBlockInvalidError("[BLOCK][%s] block header hash is not less than the target difficulty", b.String())
+		}
 	}
 
 	// 2. Check that the block timestamp is not more than two hours in the future.
@@ -439,26 +442,9 @@ func (b *Block) Valid(ctx context.Context, logger ulogger.Logger, subtreeStore S
 		return false, errors.NewBlockInvalidError("[BLOCK][%s] block coinbase tx is not a valid coinbase tx", b.String())
 	}
 
-	// We can only calculate the height from coinbase transactions in block versions 2 and higher
-
-	// https://en.bitcoin.it/wiki/BIP_0034
-	// BIP-34 was created to force miners to add the block height to the coinbase tx.
-	// This BIP came into effect at block 227,835, which is after the first halving
-	// at block 210,000.  Therefore, until this happened, we do not know the actual
-	// height of the block we are checking for.
-
-	// TODO - do this another way, if necessary
-
 	// 5. Check that the coinbase transaction includes the correct block height.
-	if b.Header.Version > 1 && b.Height > LastV1Block {
-		height, err := b.ExtractCoinbaseHeight()
-		if err != nil {
-			return false, errors.NewBlockInvalidError("[BLOCK][%s] error extracting coinbase height", b.String(), err)
-		}
-
-		if height != b.Height {
-			return false, errors.NewBlockInvalidError("[BLOCK][%s] block height in coinbase tx (%d) does not match block height in block header (%d)", b.String(), height, b.Height)
-		}
+	if err = b.checkCoinbaseHeight(settings.Block.BIP34ActivationHeight); err != nil {
+		return false, err
 	}
 
 	// only do the subtree checks if we have a subtree store
@@ -529,21 +515,28 @@ func (b *Block) Valid(ctx context.Context, logger ulogger.Logger, subtreeStore S
 	return true, nil
 }
 
-// https://en.bitcoin.it/wiki/BIP_0034
-// BIP-34 was created to force miners to add the block height to the coinbase tx.
-// This BIP came into effect at block 227,835, which is after the first halving
-// at block 210,000.  Therefore, until this happened, we do not know the actual
-// height of the block we are checking for.
-
-// TODO - do this another way, if necessary
+// checkBlockRewardAndFees checks that the coinbase transaction does not claim more than the block subsidy
+// for the height of the block plus the fees of all transactions in the block. A coinbase claiming less than
+// allowed is valid, the difference is simply not created.
+//
+// Parameters:
+// - params: the chain parameters used to calculate the block subsidy
+//
+// Returns:
+// - error: BlockInvalidError if the coinbase claims more than the block subsidy plus fees
 func (b *Block) checkBlockRewardAndFees(params *chaincfg.Params) error {
 	if b.Height == 0 {
 		return nil // Skip this check
 	}
 
-	coinbaseOutputSatoshis := uint64(0)
-	for _, tx := range b.CoinbaseTx.Outputs {
-		coinbaseOutputSatoshis += tx.Satoshis
+	claimedSatoshis := uint64(0)
+
+	for _, output := range b.CoinbaseTx.Outputs {
+		if output.Satoshis > math.MaxUint64-claimedSatoshis {
+			return errors.NewBlockInvalidError("[BLOCK][%s] coinbase output total overflows", b.String())
+		}
+
+		claimedSatoshis += output.Satoshis
 	}
 
 	subtreeFees := uint64(0)
@@ -553,10 +546,11 @@ func (b *Block) checkBlockRewardAndFees(params *chaincfg.Params) error {
 		subtreeFees += subtree.Fees
 	}
 
-	coinbaseReward := util.GetBlockSubsidyForHeight(b.Height, params)
+	blockSubsidy := util.GetBlockSubsidyForHeight(b.Height, params)
+	allowedSatoshis := blockSubsidy + subtreeFees
 
-	if coinbaseOutputSatoshis > subtreeFees+coinbaseReward {
-		return errors.NewBlockInvalidError("[BLOCK][%s] coinbase output (%d) is greater than the fees + block subsidy (%d)", b.String(), coinbaseOutputSatoshis, subtreeFees+coinbaseReward)
+	if claimedSatoshis > allowedSatoshis {
+		return errors.NewBlockInvalidError("[BLOCK][%s] coinbase output claims %d satoshis, allowed %d (block subsidy %d + fees %d)", b.String(), claimedSatoshis, allowedSatoshis, blockSubsidy, subtreeFees)
 	}
 
 	return nil
@@ -614,7 +608,8 @@ func (b *Block) checkDuplicateTransactions(ctx context.Context, checkDuplicateTr
 
 // checkDuplicateTransactionsInSubtree checks for duplicate transactions in a subtree.
 // It uses the block txMap to store the transactions and check for duplicates.
-// If a duplicate transaction is found, it returns an error.
+// If a duplicate transaction is found, it returns a block invalid error naming the repeated txid and the indexes
+// in the block of both occurrences.
 //
 // Parameters:
 // - subtree: the subtree to check for duplicate transactions
@@ -641,6 +636,10 @@ func (b *Block) checkDuplicateTransactionsInSubtree(subtree *subtreepkg.Subtree,
 		// in a tx map, Put is mutually exclusive, can only be called once per key
 		if err = b.txMap.Put(subtreeNode.Hash, idx64); err != nil {
 			if errors.Is(err, errors.ErrTxExists) || strings.Contains(err.Error(), "hash already exists in map") {
+				if firstIdx, ok := b.txMap.Get(subtreeNode.Hash); ok {
+					return errors.NewBlockInvalidError("[BLOCK][%s] block contains duplicate transaction %s at index %d, first seen at index %d", b.String(), subtreeNode.Hash.String(), idx64, firstIdx)
+				}
+
 				return errors.NewBlockInvalidError("[BLOCK][%s] block contains duplicate transaction %s", b.String(), subtreeNode.Hash.String())
 			}
 
@@ -1296,6 +1295,32 @@ func (b *Block) CheckMerkleRoot(ctx context.Context) (err error) {
 	return nil
 }
 
+// checkCoinbaseHeight checks that the coinbase transaction encodes the height of the block, as required by BIP34
+// for the blocks of version 2 and higher from the given activation height. The activation height is configured per
+// network with block_bip34ActivationHeight, the genesis block is never checked.
+//
+// https://github.com/bitcoin/bips/blob/master/bip-0034.mediawiki
+//
+// Returns:
+//   - error: A BlockInvalidError when the height cannot be extracted from the coinbase, or does not match the height
+//     of the block
+func (b *Block) checkCoinbaseHeight(activationHeight uint32) error {
+	if b.Header.Version < 2 || b.Height == 0 || b.Height < activationHeight {
+		return nil
+	}
+
+	height, err := b.ExtractCoinbaseHeight()
+	if err != nil {
+		return errors.NewBlockInvalidError("[BLOCK][%s] error extracting coinbase height", b.String(), err)
+	}
+
+	if height != b.Height {
+		return errors.NewBlockInvalidError("[BLOCK][%s] block height in coinbase tx (%d) does not match block height in block header (%d)", b.String(), height, b.Height)
+	}
+
+	return nil
+}
+
 // ExtractCoinbaseHeight attempts to extract the height of the block from the
 // scriptSig of a coinbase transaction.  Coinbase's heights are only present in
 // blocks of version 2 or later.  This was added as part of BIP0034.
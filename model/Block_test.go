@@ -8,12 +8,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"math"
 	"net/url"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	"github.com/bsv-blockchain/go-chaincfg"
 	subtreepkg "github.com/bsv-blockchain/go-subtree"
@@ -1497,12 +1499,61 @@ func TestCheckDuplicateTransactions(t *testing.T) {
 		123, 0, 0)
 	require.NoError(t, err)
 
+	b.SubtreeSlices = []*subtreepkg.Subtree{subtree}
+
 	err = b.checkDuplicateTransactions(context.Background(), tSettings.Block.CheckDuplicateTransactionsConcurrency)
-	_ = err // To stop lint warning
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrBlockInvalid))
+	assert.Contains(t, err.Error(), "block contains duplicate transaction "+hashes[0].String()+" at index 3, first seen at index 0")
 }
 
-// TODO reactivate this test when we have a way to check for duplicate transactions
-// require.Error(t, err)
+func TestCheckDuplicateTransactions_AcrossSubtrees(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+
+	hashes := make([]chainhash.Hash, 4)
+	for i := range hashes {
+		hashes[i] = chainhash.HashH([]byte{byte(i)})
+	}
+
+	subtree1, err := subtreepkg.NewTreeByLeafCount(2)
+	require.NoError(t, err)
+	require.NoError(t, subtree1.AddNode(hashes[0], 111, 0))
+	require.NoError(t, subtree1.AddNode(hashes[1], 111, 0))
+
+	// the second subtree repeats a transaction of the first subtree
+	subtree2, err := subtreepkg.NewTreeByLeafCount(2)
+	require.NoError(t, err)
+	require.NoError(t, subtree2.AddNode(hashes[2], 111, 0))
+	require.NoError(t, subtree2.AddNode(hashes[1], 111, 0))
+
+	blockHeaderBytes, _ := hex.DecodeString(block1Header)
+	blockHeader, err := NewBlockHeaderFromBytes(blockHeaderBytes)
+	require.NoError(t, err)
+
+	coinbase, err := bt.NewTxFromString(CoinbaseHex)
+	require.NoError(t, err)
+
+	b, err := NewBlock(blockHeader, coinbase, []*chainhash.Hash{subtree1.RootHash(), subtree2.RootHash()}, 4, 123, 0, 0)
+	require.NoError(t, err)
+
+	b.SubtreeSlices = []*subtreepkg.Subtree{subtree1, subtree2}
+
+	// the subtrees are checked concurrently, either occurrence may be found first
+	err = b.checkDuplicateTransactions(context.Background(), tSettings.Block.CheckDuplicateTransactionsConcurrency)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrBlockInvalid))
+	assert.Contains(t, err.Error(), "block contains duplicate transaction "+hashes[1].String())
+
+	// without the duplicate the block is accepted
+	subtree3, err := subtreepkg.NewTreeByLeafCount(2)
+	require.NoError(t, err)
+	require.NoError(t, subtree3.AddNode(hashes[2], 111, 0))
+	require.NoError(t, subtree3.AddNode(hashes[3], 111, 0))
+
+	b.SubtreeSlices = []*subtreepkg.Subtree{subtree1, subtree3}
+
+	require.NoError(t, b.checkDuplicateTransactions(context.Background(), tSettings.Block.CheckDuplicateTransactionsConcurrency))
+}
 
 func TestCheckParentExistsOnChain(t *testing.T) {
 	ctx := context.Background()
@@ -1802,6 +1853,70 @@ func TestBlock_ExtractCoinbaseHeight(t *testing.T) {
 	})
 }
 
+func TestBlock_checkCoinbaseHeight(t *testing.T) {
+	// the coinbase encodes height 1019
+	newBlock := func(t *testing.T, version uint32, height uint32) *Block {
+		coinbaseTx, err := bt.NewTxFromString(CoinbaseHex)
+		require.NoError(t, err)
+
+		blockHeaderBytes, _ := hex.DecodeString(block1Header)
+		blockHeader, err := NewBlockHeaderFromBytes(blockHeaderBytes)
+		require.NoError(t, err)
+
+		blockHeader.Version = version
+
+		b, err := NewBlock(blockHeader, coinbaseTx, []*chainhash.Hash{}, 1, 123, height, 0)
+		require.NoError(t, err)
+
+		return b
+	}
+
+	t.Run("correct encoded height", func(t *testing.T) {
+		require.NoError(t, newBlock(t, 2, 1019).checkCoinbaseHeight(1000))
+	})
+
+	t.Run("correct encoded height at the activation height", func(t *testing.T) {
+		require.NoError(t, newBlock(t, 2, 1019).checkCoinbaseHeight(1019))
+	})
+
+	t.Run("incorrect encoded height", func(t *testing.T) {
+		err := newBlock(t, 2, 1020).checkCoinbaseHeight(1000)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrBlockInvalid)
+		assert.Contains(t, err.Error(), "block height in coinbase tx (1019) does not match block height in block header (1020)")
+	})
+
+	t.Run("not checked before the activation height", func(t *testing.T) {
+		require.NoError(t, newBlock(t, 2, 1020).checkCoinbaseHeight(2000))
+	})
+
+	t.Run("version 1 blocks are not checked", func(t *testing.T) {
+		require.NoError(t, newBlock(t, 1, 1020).checkCoinbaseHeight(1000))
+	})
+
+	t.Run("missing encoded height", func(t *testing.T) {
+		b := newBlock(t, 2, 1019)
+		b.CoinbaseTx.Inputs[0].UnlockingScript = &bscript.Script{}
+
+		err := b.checkCoinbaseHeight(0)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrBlockInvalid)
+	})
+
+	t.Run("checked in block validation", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.BlockValidation.SkipProofOfWorkCheck = true
+		tSettings.Block.BIP34ActivationHeight = 1000
+
+		b := newBlock(t, 2, 1020)
+
+		valid, err := b.Valid(context.Background(), ulogger.TestLogger{}, nil, nil, nil, nil, nil, nil, NewBloomStats(), tSettings)
+		require.Error(t, err)
+		assert.False(t, valid)
+		assert.Contains(t, err.Error(), "does not match block height")
+	})
+}
+
 func TestBlock_SubTreesFromBytes(t *testing.T) {
 	t.Run("valid subtrees bytes", func(t *testing.T) {
 		hash1, _ := chainhash.NewHashFromStr("0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206")
@@ -1927,6 +2042,60 @@ func TestBlock_CheckBlockRewardAndFees(t *testing.T) {
 		err = block.checkBlockRewardAndFees(&chaincfg.MainNetParams)
 		require.NoError(t, err)
 	})
+
+	// newBlockWithCoinbaseValue creates a block at height 1 with 1000 satoshis in fees, whose coinbase claims the given value
+	newBlockWithCoinbaseValue := func(t *testing.T, claimed uint64) *Block {
+		blockHeaderBytes, _ := hex.DecodeString(block1Header)
+		blockHeader, err := NewBlockHeaderFromBytes(blockHeaderBytes)
+		require.NoError(t, err)
+
+		coinbase, err := bt.NewTxFromString(CoinbaseHex)
+		require.NoError(t, err)
+
+		coinbase.Outputs = coinbase.Outputs[:2]
+		coinbase.Outputs[0].Satoshis = claimed / 2
+		coinbase.Outputs[1].Satoshis = claimed - claimed/2
+
+		block, err := NewBlock(blockHeader, coinbase, []*chainhash.Hash{}, 1, 123, 1, 0)
+		require.NoError(t, err)
+
+		block.SubtreeSlices = []*subtreepkg.Subtree{{Fees: 600}, {Fees: 400}}
+
+		return block
+	}
+
+	allowed := util.GetBlockSubsidyForHeight(1, &chaincfg.MainNetParams) + 1000
+
+	t.Run("coinbase claiming exactly subsidy plus fees", func(t *testing.T) {
+		block := newBlockWithCoinbaseValue(t, allowed)
+
+		require.NoError(t, block.checkBlockRewardAndFees(&chaincfg.MainNetParams))
+	})
+
+	t.Run("coinbase claiming less than subsidy plus fees", func(t *testing.T) {
+		block := newBlockWithCoinbaseValue(t, allowed-1)
+
+		require.NoError(t, block.checkBlockRewardAndFees(&chaincfg.MainNetParams))
+	})
+
+	t.Run("coinbase claiming more than subsidy plus fees", func(t *testing.T) {
+		block := newBlockWithCoinbaseValue(t, allowed+1)
+
+		err := block.checkBlockRewardAndFees(&chaincfg.MainNetParams)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrBlockInvalid))
+		assert.Contains(t, err.Error(), fmt.Sprintf("claims %d satoshis, allowed %d", allowed+1, allowed))
+	})
+
+	t.Run("coinbase output total overflowing", func(t *testing.T) {
+		block := newBlockWithCoinbaseValue(t, allowed)
+		block.CoinbaseTx.Outputs[0].Satoshis = math.MaxUint64
+		block.CoinbaseTx.Outputs[1].Satoshis = 1
+
+		err := block.checkBlockRewardAndFees(&chaincfg.MainNetParams)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrBlockInvalid))
+	})
 }
 
 func TestBlock_CheckDuplicateTransactionsInSubtree(t *testing.T) {
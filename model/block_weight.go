@@ -0,0 +1,17 @@
+package model
+
+// WitnessScaleFactor is the factor by which the base, non-witness, size of a transaction is weighted in
+// the block weight formula: weight = base size * (WitnessScaleFactor - 1) + total size.
+const WitnessScaleFactor = 4
+
+// Weight returns the weight of serialized data with the given base size, without witness data, and total
+// size, including witness data.
+func Weight(baseSize, totalSize uint64) uint64 {
+	return baseSize*(WitnessScaleFactor-1) + totalSize
+}
+
+// BlockWeight returns the weight of a block with the given serialized size in bytes. BSV transactions do
+// not carry witness data, so the base size and the total size of a block are the same.
+func BlockWeight(sizeInBytes uint64) uint64 {
+	return Weight(sizeInBytes, sizeInBytes)
+}
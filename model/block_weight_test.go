@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeight(t *testing.T) {
+	t.Run("without witness data", func(t *testing.T) {
+		assert.Equal(t, uint64(4000), Weight(1000, 1000))
+		assert.Equal(t, Weight(1000, 1000), BlockWeight(1000))
+	})
+
+	t.Run("witness data is not scaled", func(t *testing.T) {
+		assert.Equal(t, uint64(4200), Weight(1000, 1200))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, uint64(0), BlockWeight(0))
+	})
+}
@@ -15,7 +15,10 @@ import (
 // instead of a pay to public key hash
 func (mc *MiningCandidate) CreateCoinbaseTxCandidate(tSettings *settings.Settings, p2pk ...bool) (*bt.Tx, error) {
 	// Create a new coinbase transaction
-	arbitraryText := tSettings.Coinbase.ArbitraryText
+	arbitraryText, err := coinbaseText(tSettings)
+	if err != nil {
+		return nil, err
+	}
 
 	coinbasePrivKeys := tSettings.BlockAssembly.MinerWalletPrivateKeys
 
@@ -66,7 +69,10 @@ func (mc *MiningCandidate) CreateCoinbaseTxCandidate(tSettings *settings.Setting
 }
 
 func (mc *MiningCandidate) CreateCoinbaseTxCandidateForAddress(tSettings *settings.Settings, address *string) (*bt.Tx, error) {
-	arbitraryText := tSettings.Coinbase.ArbitraryText
+	arbitraryText, err := coinbaseText(tSettings)
+	if err != nil {
+		return nil, err
+	}
 
 	if address == nil {
 		return nil, errors.NewConfigurationError("address is required for ")
@@ -80,6 +86,23 @@ func (mc *MiningCandidate) CreateCoinbaseTxCandidateForAddress(tSettings *settin
 	return coinbaseTx, nil
 }
 
+// MaxCoinbaseTagLength is the maximum length in bytes of the configurable coinbase tag.
+// Together with the block height (4 bytes) and the extra nonce (12 bytes) this keeps the
+// coinbase scriptSig well within the 100 byte consensus limit, leaving room for the arbitrary text.
+const MaxCoinbaseTagLength = 32
+
+// coinbaseText returns the text to embed in the coinbase scriptSig after the block height.
+// The coinbase tag is placed before the arbitrary text, so it is never cut off when the
+// arbitrary text needs to be truncated to fit in the coinbase.
+func coinbaseText(tSettings *settings.Settings) (string, error) {
+	tag := tSettings.BlockAssembly.CoinbaseTag
+	if len(tag) > MaxCoinbaseTagLength {
+		return "", errors.NewConfigurationError("coinbase tag is %d bytes, exceeds maximum of %d bytes", len(tag), MaxCoinbaseTagLength)
+	}
+
+	return tag + tSettings.Coinbase.ArbitraryText, nil
+}
+
 func CreateCoinbase(height uint32, coinbaseValue uint64, arbitraryText string, addresses []string) (*bt.Tx, error) {
 	a, b, err := GetCoinbaseParts(height, coinbaseValue, arbitraryText, addresses)
 	if err != nil {
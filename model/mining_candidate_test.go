@@ -1,12 +1,15 @@
 package model
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
 	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	"github.com/bsv-blockchain/teranode/settings"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMiningCandidate_CreateCoinbaseTxCandidate(t *testing.T) {
@@ -38,6 +41,39 @@ func TestMiningCandidate_CreateCoinbaseTxCandidate(t *testing.T) {
 		assert.Equal(t, mc.CoinbaseValue, coinbaseTx.Outputs[0].Satoshis)
 	})
 
+	t.Run("CreateCoinbaseWithTag", func(t *testing.T) {
+		taggedSettings := &settings.Settings{
+			Coinbase: settings.CoinbaseSettings{
+				ArbitraryText: "test coinbase",
+			},
+			BlockAssembly: settings.BlockAssemblySettings{
+				MinerWalletPrivateKeys: []string{testPrivKey},
+				CoinbaseTag:            "/pool-tag/",
+			},
+		}
+
+		coinbaseTx, err := mc.CreateCoinbaseTxCandidate(taggedSettings)
+		require.NoError(t, err)
+
+		// the tag must directly follow the block height push (1 byte length + 3 bytes height)
+		scriptSig := coinbaseTx.Inputs[0].UnlockingScript.Bytes()
+		require.Greater(t, len(scriptSig), 4)
+		assert.True(t, bytes.HasPrefix(scriptSig[4:], []byte("/pool-tag/test coinbase")))
+	})
+
+	t.Run("CreateCoinbaseWithTagTooLong", func(t *testing.T) {
+		taggedSettings := &settings.Settings{
+			BlockAssembly: settings.BlockAssemblySettings{
+				MinerWalletPrivateKeys: []string{testPrivKey},
+				CoinbaseTag:            strings.Repeat("A", MaxCoinbaseTagLength+1),
+			},
+		}
+
+		coinbaseTx, err := mc.CreateCoinbaseTxCandidate(taggedSettings)
+		require.Error(t, err)
+		assert.Nil(t, coinbaseTx)
+	})
+
 	t.Run("CreateP2PKCoinbase", func(t *testing.T) {
 		coinbaseTx, err := mc.CreateCoinbaseTxCandidate(tSettings, true)
 
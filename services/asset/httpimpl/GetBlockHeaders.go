@@ -30,7 +30,7 @@ import (
 //   - hash: Starting block hash (hex string)
 //
 // Query Parameters:
-//   - n: Number of headers to retrieve (default: 100, max: 1000)
+//   - n: Number of headers to retrieve (default: 100, max: 1000 or asset_maxBlockHeadersPerRequest when lower)
 //     Example: ?n=50
 //
 // HTTP Response Formats:
@@ -141,8 +141,8 @@ func (h *HTTP) GetBlockHeaders(mode ReadMode) func(c echo.Context) error {
 				numberOfHeaders = 100
 			}
 
-			if numberOfHeaders > 1000 {
-				numberOfHeaders = 1000
+			if maxHeaders := min(1000, h.maxBlockHeadersPerRequest()); numberOfHeaders > maxHeaders {
+				numberOfHeaders = maxHeaders
 			}
 		}
 
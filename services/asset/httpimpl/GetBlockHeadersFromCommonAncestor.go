@@ -24,7 +24,7 @@ import (
 //   - hash: Starting block hash (hex string)
 //
 // Query Parameters: (either n or block_locator_hashes must be provided but not both)
-//   - n: Number of headers to retrieve (default: 100, max: 1000)
+//   - n: Number of headers to retrieve (default: 100, max: asset_maxBlockHeadersPerRequest, 10000 by default)
 //     Example: ?n=50
 //   - block_locator_hashes: Block locator hashes (hex string)
 //     Example: ?block_locator_hashes=000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f
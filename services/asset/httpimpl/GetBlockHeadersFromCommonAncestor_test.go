@@ -152,6 +152,28 @@ func TestGetBlockHeadersFromCommonAncestor(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Number of headers capped at the configured maximum", func(t *testing.T) {
+		httpServer, mockRepo, echoContext, responseRecorder := GetMockHTTP(t, nil)
+		httpServer.settings.Asset.MaxBlockHeadersPerRequest = 500
+
+		// a request over the configured maximum is truncated to the maximum
+		mockRepo.On("GetBlockHeadersFromCommonAncestor", mock.Anything, mock.Anything, uint32(500)).Return([]*model.BlockHeader{testBlockHeader}, []*model.BlockHeaderMeta{testBlockHeaderMeta}, nil)
+
+		echoContext.SetPath("/block/headersFromCommonAncestor/:hash")
+		echoContext.SetParamNames("hash")
+		echoContext.SetParamValues("9d45ad79ad3c6baecae872c0e35022d60c3bbbd024ccce06690321ece15ea995")
+		echoContext.QueryParams().Set("block_locator_hashes", "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f")
+		echoContext.QueryParams().Set("n", "600")
+
+		err := httpServer.GetBlockHeadersFromCommonAncestor(JSON)(echoContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, http.StatusOK, responseRecorder.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Multiple block locator hashes", func(t *testing.T) {
 		httpServer, mockRepo, echoContext, responseRecorder := GetMockHTTP(t, nil)
 
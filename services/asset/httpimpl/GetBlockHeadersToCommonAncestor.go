@@ -13,6 +13,10 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// defaultMaxBlockHeadersPerRequest is the maximum number of block headers returned per request when
+// asset_maxBlockHeadersPerRequest is not set
+const defaultMaxBlockHeadersPerRequest = 10_000
+
 // GetBlockHeadersToCommonAncestor creates an HTTP handler for retrieving multiple consecutive block headers
 // starting from a specific block hash. It supports multiple response formats
 // and pagination.
@@ -27,7 +31,7 @@ import (
 //   - hash: Starting block hash (hex string)
 //
 // Query Parameters: (either n or block_locator_hashes must be provided but not both)
-//   - n: Number of headers to retrieve (default: 100, max: 1000)
+//   - n: Number of headers to retrieve (default: 100, max: asset_maxBlockHeadersPerRequest, 10000 by default)
 //     Example: ?n=50
 //   - block_locator_hashes: Block locator hashes (hex string)
 //     Example: ?block_locator_hashes=000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f
@@ -133,10 +137,6 @@ func (h *HTTP) GetBlockHeadersToCommonAncestor(mode ReadMode) func(c echo.Contex
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 
-		if numberOfHeaders > 10_000 {
-			numberOfHeaders = 10_000
-		}
-
 		var (
 			headers     []*model.BlockHeader
 			headerMetas []*model.BlockHeaderMeta
@@ -199,8 +199,8 @@ func (h *HTTP) parseNumberOfHeaders(nStr string) (int, error) {
 		return 100, nil
 	}
 
-	if n > 10_000 {
-		return 10_000, nil
+	if maxHeaders := h.maxBlockHeadersPerRequest(); n > maxHeaders {
+		return maxHeaders, nil
 	}
 
 	return n, nil
@@ -240,3 +240,13 @@ func (h *HTTP) formatResponse(c echo.Context, mode ReadMode, headers []*model.Bl
 		return echo.NewHTTPError(http.StatusBadRequest, errors.NewInvalidArgumentError("bad read mode").Error())
 	}
 }
+
+// maxBlockHeadersPerRequest returns the maximum number of block headers returned per request, requests for more
+// headers are truncated to it
+func (h *HTTP) maxBlockHeadersPerRequest() int {
+	if h.settings != nil && h.settings.Asset.MaxBlockHeadersPerRequest > 0 {
+		return h.settings.Asset.MaxBlockHeadersPerRequest
+	}
+
+	return defaultMaxBlockHeadersPerRequest
+}
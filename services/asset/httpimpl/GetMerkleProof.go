@@ -91,6 +91,9 @@ type LegacyMerkleProofResponse struct {
 //     Returned when the hash doesn't exist as a transaction or subtree
 //     Example: {"message": "hash not found as transaction or subtree"}
 //
+//   - 429 Too Many Requests:
+//     Returned when the client exceeds asset_proofRateLimitPerClient merkle proof requests per second
+//
 //   - 500 Internal Server Error:
 //     Returned for various internal errors:
 //
@@ -150,6 +153,11 @@ func (h *HTTP) GetMerkleProof(mode ReadMode) func(c echo.Context) error {
 
 		defer deferFn()
 
+		if clientIP := c.RealIP(); !h.proofRateLimiter.Allow(clientIP) {
+			prometheusAssetHTTPGetMerkleProof.WithLabelValues("TooManyRequests", "429").Inc()
+			return echo.NewHTTPError(http.StatusTooManyRequests, errors.NewThresholdExceededError("merkle proof rate limit of %.2f requests/s exceeded for client %s, retry later", h.proofRateLimiter.Limit(), clientIP).Error())
+		}
+
 		if len(hashStr) != 64 {
 			prometheusAssetHTTPGetMerkleProof.WithLabelValues("BadRequest", "400").Inc()
 			return echo.NewHTTPError(http.StatusBadRequest, errors.NewInvalidArgumentError("invalid hash length").Error())
@@ -161,6 +169,15 @@ func (h *HTTP) GetMerkleProof(mode ReadMode) func(c echo.Context) error {
 			return echo.NewHTTPError(http.StatusBadRequest, errors.NewInvalidArgumentError("invalid hash string", err).Error())
 		}
 
+		// Wait for a slot of the node wide cap on concurrent proof constructions
+		releaseSlot, err := merkleproof.AcquireSlot(ctx)
+		if err != nil {
+			prometheusAssetHTTPGetMerkleProof.WithLabelValues("ServiceUnavailable", "503").Inc()
+			return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+		}
+
+		defer releaseSlot()
+
 		// Create adapter to use merkleproof helper functions
 		adapter := newMerkleProofAdapter(ctx, h.repository)
 
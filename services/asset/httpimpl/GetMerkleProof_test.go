@@ -3,10 +3,12 @@ package httpimpl
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
@@ -20,7 +22,9 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
 	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
 	"github.com/bsv-blockchain/teranode/util/bump"
+	"github.com/bsv-blockchain/teranode/util/merkleproof"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -633,3 +637,80 @@ func (m *MockRepositoryForMerkleProof) GetBlockvalidationClient() blockvalidatio
 func (m *MockRepositoryForMerkleProof) GetP2PClient() p2p.ClientI {
 	return nil
 }
+
+func TestGetMerkleProof_RateLimit(t *testing.T) {
+	initPrometheusMetrics()
+
+	h := &HTTP{
+		logger:           ulogger.TestLogger{},
+		settings:         &settings.Settings{},
+		repository:       new(MockRepositoryForMerkleProof),
+		proofRateLimiter: util.NewSourceRateLimiter(0.001, 2),
+	}
+
+	// the hash is too short, a request that is not throttled is rejected as a bad request without using the repository
+	request := func(remoteAddr string) (int, string) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/merkle_proof/abc/json", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("hash")
+		c.SetParamValues("abc")
+
+		err := h.GetMerkleProof(JSON)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+
+		return httpErr.Code, fmt.Sprint(httpErr.Message)
+	}
+
+	for i := 0; i < 2; i++ {
+		code, _ := request("192.0.2.1:1234")
+		assert.Equal(t, http.StatusBadRequest, code)
+	}
+
+	code, message := request("192.0.2.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, code)
+	assert.Contains(t, message, "merkle proof rate limit")
+
+	// other clients are not throttled
+	code, _ = request("192.0.2.2:1234")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGetMerkleProof_ConcurrencyCap(t *testing.T) {
+	initPrometheusMetrics()
+
+	merkleproof.InitLimiter(1, 10*time.Millisecond)
+	defer merkleproof.InitLimiter(0, 0)
+
+	h := &HTTP{
+		logger:     ulogger.TestLogger{},
+		settings:   &settings.Settings{},
+		repository: new(MockRepositoryForMerkleProof),
+	}
+
+	// hold the only proof construction slot
+	release, err := merkleproof.AcquireSlot(context.Background())
+	require.NoError(t, err)
+
+	defer release()
+
+	hash := chainhash.HashH([]byte("proof")).String()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/merkle_proof/"+hash+"/json", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("hash")
+	c.SetParamValues(hash)
+
+	err = h.GetMerkleProof(JSON)(c)
+
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.Code)
+	assert.Contains(t, fmt.Sprint(httpErr.Message), "no merkle proof construction slot available")
+}
@@ -31,6 +31,9 @@ type HTTP struct {
 	e          *echo.Echo
 	startTime  time.Time
 	privKey    crypto.PrivKey
+
+	// proofRateLimiter limits the merkle proof requests per client, nil when asset_proofRateLimitPerClient is not set
+	proofRateLimiter *util.SourceRateLimiter
 }
 
 // New creates and configures a new HTTP server instance with all routes and middleware.
@@ -141,6 +144,8 @@ func New(logger ulogger.Logger, tSettings *settings.Settings, repo *repository.R
 		repository: repo,
 		e:          e,
 		startTime:  time.Now(),
+
+		proofRateLimiter: util.NewSourceRateLimiter(tSettings.Asset.ProofRateLimitPerClient, tSettings.Asset.ProofRateLimitBurst),
 	}
 
 	// add the private key for signing responses
@@ -42,6 +42,10 @@ func (repo *Repository) GetLegacyBlockReader(ctx context.Context, hash *chainhas
 		return nil, err
 	}
 
+	if err = repo.checkBlockPruned(ctx, block); err != nil {
+		return nil, err
+	}
+
 	r, w := io.Pipe()
 
 	g, gCtx := errgroup.WithContext(ctx)
@@ -145,6 +149,41 @@ func (repo *Repository) GetLegacyBlockReader(ctx context.Context, hash *chainhas
 	return r, nil
 }
 
+// checkBlockPruned returns a not found error when the full data of the block has been pruned by the
+// block persister, so callers get a clear answer instead of a failure halfway through streaming the block.
+//
+// A block can only be pruned when pruning is enabled and the block is at least the prune depth below
+// the best block, the existence of its first subtree decides whether it actually has been pruned.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - block: Block to check
+//
+// Returns:
+//   - error: Not found error if the block data has been pruned, nil otherwise
+func (repo *Repository) checkBlockPruned(ctx context.Context, block *model.Block) error {
+	pruneDepth := repo.settings.Block.BlockPersisterPruneDepth
+	if pruneDepth == 0 || len(block.Subtrees) == 0 {
+		return nil
+	}
+
+	bestHeight, _, err := repo.BlockchainClient.GetBestHeightAndTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	if block.Height+pruneDepth > bestHeight {
+		return nil
+	}
+
+	exists, err := repo.SubtreeStore.Exists(ctx, block.Subtrees[0][:], fileformat.FileTypeSubtree)
+	if err != nil || exists {
+		return err
+	}
+
+	return errors.NewNotFoundError("block %s at height %d has been pruned, only its header is available", block.Hash(), block.Height)
+}
+
 // writeLegacyBlockHeader writes a block header in legacy format to the provided writer.
 //
 // Parameters:
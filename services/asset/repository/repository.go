@@ -242,6 +242,8 @@ func (repo *Repository) GetBlockGraphData(ctx context.Context, periodMillis uint
 }
 
 // GetTransactionMeta retrieves metadata for a transaction by its hash.
+// The metadata is only displayed, so it is read with eventual consistency, which allows the UTXO store
+// to serve the read from a replica.
 //
 // Parameters:
 //   - ctx: Context for the operation
@@ -253,7 +255,7 @@ func (repo *Repository) GetBlockGraphData(ctx context.Context, periodMillis uint
 func (repo *Repository) GetTransactionMeta(ctx context.Context, hash *chainhash.Hash) (*meta.Data, error) {
 	repo.logger.Debugf("[Repository] GetTransaction: %s", hash.String())
 
-	txMeta, err := repo.UtxoStore.Get(ctx, hash)
+	txMeta, err := repo.UtxoStore.Get(utxo.WithReadConsistency(ctx, utxo.ReadConsistencyEventual), hash)
 	if err != nil {
 		return nil, err
 	}
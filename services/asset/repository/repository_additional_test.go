@@ -18,10 +18,12 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/blob/options"
 	blockchain_store "github.com/bsv-blockchain/teranode/stores/blockchain"
 	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
 	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -134,6 +136,27 @@ func TestRepository_GetTransactionMeta(t *testing.T) {
 	assert.Nil(t, txMeta)
 }
 
+// Test GetTransactionMeta reads with eventual consistency
+func TestRepository_GetTransactionMeta_ReadConsistency(t *testing.T) {
+	repo := createTestRepository(t)
+
+	txHash, err := chainhash.NewHashFromStr("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+
+	utxoStore := &utxo.MockUtxostore{}
+	utxoStore.On("Get", mock.MatchedBy(func(ctx context.Context) bool {
+		return utxo.ReadConsistencyFromContext(ctx) == utxo.ReadConsistencyEventual
+	}), txHash, mock.Anything).Return(&meta.Data{Fee: 100}, nil)
+
+	repo.UtxoStore = utxoStore
+
+	txMeta, err := repo.GetTransactionMeta(context.Background(), txHash)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), txMeta.Fee)
+
+	utxoStore.AssertExpectations(t)
+}
+
 // Test GetBlockByHeight method
 func TestRepository_GetBlockByHeight(t *testing.T) {
 	repo := createTestRepository(t)
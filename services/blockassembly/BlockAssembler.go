@@ -276,6 +276,26 @@ func (b *BlockAssembler) QueueLength() int64 {
 	return b.subtreeProcessor.QueueLength()
 }
 
+// PrioritiseTxs marks transactions as priority, so they are included ahead of other transactions
+// when they are added to block assembly.
+//
+// Parameters:
+//   - hashes: Hashes of the transactions to prioritise
+//
+// Returns:
+//   - error: Any error encountered, e.g. when the priority list is full
+func (b *BlockAssembler) PrioritiseTxs(hashes []chainhash.Hash) error {
+	return b.subtreeProcessor.PrioritiseTxs(hashes)
+}
+
+// RemovePriorityTxs removes transactions from the priority list.
+//
+// Parameters:
+//   - hashes: Hashes of the transactions to remove from the priority list
+func (b *BlockAssembler) RemovePriorityTxs(hashes []chainhash.Hash) {
+	b.subtreeProcessor.RemovePriorityTxs(hashes)
+}
+
 // SubtreeCount returns the total number of subtrees.
 //
 // Returns:
@@ -1148,10 +1168,8 @@ func (b *BlockAssembler) GetMiningCandidate(ctx context.Context) (*model.MiningC
 
 	_, currentHeight := b.CurrentBlock()
 
-	// Return cached if still valid (same height and within timeout)
-	if !b.settings.ChainCfgParams.ReduceMinDifficulty && b.cachedCandidate.candidate != nil &&
-		b.cachedCandidate.lastHeight == currentHeight &&
-		time.Since(b.cachedCandidate.lastUpdate) < b.settings.BlockAssembly.MiningCandidateCacheTimeout {
+	// Return cached if still valid (same height, within timeout and not stale)
+	if !b.settings.ChainCfgParams.ReduceMinDifficulty && b.cachedCandidateValid(currentHeight) {
 		candidate := b.cachedCandidate.candidate
 		subtrees := b.cachedCandidate.subtrees
 		b.cachedCandidate.mu.RUnlock()
@@ -1258,6 +1276,38 @@ func (b *BlockAssembler) GetMiningCandidate(ctx context.Context) (*model.MiningC
 	return candidate, subtrees, err
 }
 
+// cachedCandidateValid returns whether the cached mining candidate can be returned for the given height. The
+// candidate is valid while it was built for the same height and within MiningCandidateCacheTimeout, unless it is
+// older than MiningCandidateMaxAge, which bounds the staleness of the timestamp of the candidate whatever the cache
+// timeout. The caller must hold the lock of the cached candidate.
+func (b *BlockAssembler) cachedCandidateValid(currentHeight uint32) bool {
+	if b.cachedCandidate.candidate == nil || b.cachedCandidate.lastHeight != currentHeight {
+		return false
+	}
+
+	age := time.Since(b.cachedCandidate.lastUpdate)
+
+	if maxAge := b.settings.BlockAssembly.MiningCandidateMaxAge; maxAge > 0 && age >= maxAge {
+		prometheusBlockAssemblerCacheStale.Inc()
+
+		return false
+	}
+
+	return age < b.settings.BlockAssembly.MiningCandidateCacheTimeout
+}
+
+// withinBlockMaxWeight returns whether a block of the given size, without the coinbase, stays within the
+// configured maximum block weight. Without a weight limit only the block size limit applies.
+func (b *BlockAssembler) withinBlockMaxWeight(blockSize uint64) bool {
+	return b.settings.Policy.BlockMaxWeight == 0 || model.BlockWeight(blockSize) <= b.settings.Policy.BlockMaxWeight
+}
+
+// withinMaxTxPerBlock returns whether a block with the given number of transactions, including the coinbase,
+// stays within the configured max transactions per block. A limit of 0 is not checked.
+func (b *BlockAssembler) withinMaxTxPerBlock(txCount uint64) bool {
+	return b.settings.Policy.MaxTxPerBlock == 0 || txCount <= b.settings.Policy.MaxTxPerBlock
+}
+
 // getMiningCandidate creates a new mining candidate from the current block state.
 // This is an internal method called by GetMiningCandidate.
 //
@@ -1290,6 +1340,18 @@ func (b *BlockAssembler) getMiningCandidate() (*model.MiningCandidate, []*subtre
 		return nil, nil, errors.NewProcessingError("max block size is less than the size of the subtree")
 	}
 
+	if len(subtrees) > 0 && !b.withinBlockMaxWeight(subtrees[0].SizeInBytes) {
+		b.logger.Warnf("[BlockAssembler] max block weight is less than the weight of the subtree: %d < %d", b.settings.Policy.BlockMaxWeight, model.BlockWeight(subtrees[0].SizeInBytes))
+
+		return nil, nil, errors.NewProcessingError("max block weight is less than the weight of the subtree")
+	}
+
+	if len(subtrees) > 0 && !b.withinMaxTxPerBlock(uint64(len(subtrees[0].Nodes))) {
+		b.logger.Warnf("[BlockAssembler] max transactions per block is less than the number of transactions of the subtree: %d < %d", b.settings.Policy.MaxTxPerBlock, len(subtrees[0].Nodes))
+
+		return nil, nil, errors.NewProcessingError("max transactions per block is less than the number of transactions of the subtree")
+	}
+
 	var coinbaseValue uint64
 
 	currentHeight := baBestBlockHeight + 1
@@ -1333,7 +1395,10 @@ func (b *BlockAssembler) getMiningCandidate() (*model.MiningCandidate, []*subtre
 		b.logger.Debugf("Processing %d subtrees for inclusion", len(subtrees))
 
 		for _, subtree := range subtrees {
-			if b.settings.Policy.BlockMaxSize == 0 || currentBlockSize+subtree.SizeInBytes <= blockMaxSizeUint64 {
+			// the subtrees that do not fit in the block roll over to the next block
+			if (b.settings.Policy.BlockMaxSize == 0 || currentBlockSize+subtree.SizeInBytes <= blockMaxSizeUint64) &&
+				b.withinBlockMaxWeight(currentBlockSize+subtree.SizeInBytes) &&
+				b.withinMaxTxPerBlock(uint64(txCount)+uint64(len(subtree.Nodes))) {
 				subtreesToInclude = append(subtreesToInclude, subtree)
 				subtreeBytesToInclude = append(subtreeBytesToInclude, subtree.RootHash().CloneBytes())
 				coinbaseValue += subtree.Fees
@@ -689,6 +689,7 @@ func TestBlockAssembly_ShouldNotAllowMoreThanOneCoinbaseTx(t *testing.T) {
 
 		var wg sync.WaitGroup
 
+		// 7 txs is 1 complete subtree of 4 transactions
 		wg.Add(1)
 
 		go func() {
@@ -981,6 +982,129 @@ func TestBlockAssembly_GetMiningCandidate_MaxBlockSize(t *testing.T) {
 	})
 }
 
+func TestBlockAssembly_GetMiningCandidate_MaxTxPerBlock(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxTxPerBlock uint64
+		subtreeCount  int
+	}{
+		{name: "block at the transaction limit", maxTxPerBlock: 8, subtreeCount: 2},
+		{name: "subtree over the transaction limit rolls over", maxTxPerBlock: 7, subtreeCount: 1},
+		{name: "no transaction limit", maxTxPerBlock: 0, subtreeCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initPrometheusMetrics()
+
+			ctx := context.Background()
+			testItems := setupBlockAssemblyTest(t)
+			require.NotNil(t, testItems)
+			testItems.blockAssembler.settings.Policy.MaxTxPerBlock = tt.maxTxPerBlock
+
+			_, _, _ = setupBlockchainClient(t, testItems)
+
+			go func() {
+				_ = testItems.blockAssembler.startChannelListeners(ctx)
+			}()
+
+			var wg sync.WaitGroup
+
+			// 15 txs is 3 complete subtrees of 4 transactions
+			wg.Add(3)
+
+			go func() {
+				for {
+					select {
+					case subtreeRequest := <-testItems.newSubtreeChan:
+						if subtreeRequest.ErrChan != nil {
+							subtreeRequest.ErrChan <- nil
+						}
+
+						wg.Done()
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			for i := 0; i < 15; i++ {
+				// nolint:gosec // G404: Use of weak random number generator (math/rand instead of crypto/rand) (gosec)
+				tx := newTx(uint32(i))
+				_, err := testItems.utxoStore.Create(ctx, tx, 0)
+				require.NoError(t, err)
+
+				if i == 0 {
+					testItems.blockAssembler.AddTx(subtreepkg.Node{Hash: *subtreepkg.CoinbasePlaceholderHash, Fee: 5000000000, SizeInBytes: 100}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{}})
+				} else {
+					testItems.blockAssembler.AddTx(subtreepkg.Node{Hash: *tx.TxIDChainHash(), Fee: 1000, SizeInBytes: 100}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{}})
+				}
+			}
+
+			wg.Wait()
+
+			miningCandidate, subtrees, err := testItems.blockAssembler.GetMiningCandidate(ctx)
+			require.NoError(t, err)
+
+			assert.Len(t, subtrees, tt.subtreeCount)
+			assert.Equal(t, uint32(tt.subtreeCount), miningCandidate.SubtreeCount) //nolint:gosec // G115: small test value
+		})
+	}
+
+	t.Run("subtree over the transaction limit", func(t *testing.T) {
+		initPrometheusMetrics()
+
+		ctx := context.Background()
+		testItems := setupBlockAssemblyTest(t)
+		require.NotNil(t, testItems)
+		testItems.blockAssembler.settings.Policy.MaxTxPerBlock = 3
+
+		_, _, _ = setupBlockchainClient(t, testItems)
+
+		go func() {
+			_ = testItems.blockAssembler.startChannelListeners(ctx)
+		}()
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+
+		go func() {
+			for {
+				select {
+				case subtreeRequest := <-testItems.newSubtreeChan:
+					if subtreeRequest.ErrChan != nil {
+						subtreeRequest.ErrChan <- nil
+					}
+
+					wg.Done()
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		for i := 0; i < 7; i++ {
+			// nolint:gosec // G404: Use of weak random number generator (math/rand instead of crypto/rand) (gosec)
+			tx := newTx(uint32(i))
+			_, err := testItems.utxoStore.Create(ctx, tx, 0)
+			require.NoError(t, err)
+
+			if i == 0 {
+				testItems.blockAssembler.AddTx(subtreepkg.Node{Hash: *subtreepkg.CoinbasePlaceholderHash, Fee: 5000000000, SizeInBytes: 100}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{}})
+			} else {
+				testItems.blockAssembler.AddTx(subtreepkg.Node{Hash: *tx.TxIDChainHash(), Fee: 1000, SizeInBytes: 100}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{}})
+			}
+		}
+
+		wg.Wait()
+
+		_, _, err := testItems.blockAssembler.GetMiningCandidate(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max transactions per block is less than the number of transactions of the subtree")
+	})
+}
+
 func TestBlockAssembly_GetMiningCandidate_MaxBlockSize_LessThanSubtreeSize(t *testing.T) {
 	t.Run("GetMiningCandidate_MaxBlockSize_LessThanSubtreeSize", func(t *testing.T) {
 		initPrometheusMetrics()
@@ -1354,6 +1478,57 @@ func TestBlockAssembler_CachingFunctionality(t *testing.T) {
 		require.NotNil(t, candidate2)
 	})
 
+	t.Run("Cache Max Age", func(t *testing.T) {
+		initPrometheusMetrics()
+
+		testItems := setupBlockAssemblyTest(t)
+		require.NotNil(t, testItems)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer func() {
+			cancel()
+			time.Sleep(10 * time.Millisecond) // Allow goroutines to exit cleanly
+		}()
+
+		ba := testItems.blockAssembler
+		ba.settings.ChainCfgParams.ReduceMinDifficulty = false
+		ba.settings.BlockAssembly.MiningCandidateCacheTimeout = time.Hour
+		ba.settings.BlockAssembly.MiningCandidateMaxAge = time.Minute
+
+		_, _, _ = setupBlockchainClient(t, testItems)
+
+		currentHeader, _ := ba.CurrentBlock()
+		ba.setBestBlockHeader(currentHeader, 1)
+
+		go func() {
+			_ = ba.startChannelListeners(ctx)
+		}()
+
+		candidate1, _, err := ba.GetMiningCandidate(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, candidate1)
+
+		// within the max age the cached candidate is returned
+		candidate2, _, err := ba.GetMiningCandidate(ctx)
+		require.NoError(t, err)
+		assert.Same(t, candidate1, candidate2)
+
+		// older than the max age, but within the cache timeout, the candidate is rebuilt
+		ba.cachedCandidate.mu.Lock()
+		ba.cachedCandidate.lastUpdate = time.Now().Add(-2 * time.Minute)
+		ba.cachedCandidate.mu.Unlock()
+
+		candidate3, _, err := ba.GetMiningCandidate(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, candidate3)
+		assert.NotSame(t, candidate1, candidate3)
+
+		// the rebuilt candidate is cached again
+		candidate4, _, err := ba.GetMiningCandidate(ctx)
+		require.NoError(t, err)
+		assert.Same(t, candidate3, candidate4)
+	})
+
 	t.Run("Concurrent Generation Prevention", func(t *testing.T) {
 		initPrometheusMetrics()
 
@@ -2528,3 +2703,49 @@ func TestGetLastPersistedHeight(t *testing.T) {
 		assert.Equal(t, uint32(100), height)
 	})
 }
+
+func TestBlockAssembler_withinMaxTxPerBlock(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+	b := &BlockAssembler{settings: tSettings}
+
+	t.Run("no transaction limit", func(t *testing.T) {
+		tSettings.Policy.MaxTxPerBlock = 0
+
+		assert.True(t, b.withinMaxTxPerBlock(1<<40))
+	})
+
+	t.Run("block at the transaction limit", func(t *testing.T) {
+		tSettings.Policy.MaxTxPerBlock = 1000
+
+		assert.True(t, b.withinMaxTxPerBlock(1000))
+	})
+
+	t.Run("block over the transaction limit", func(t *testing.T) {
+		tSettings.Policy.MaxTxPerBlock = 1000
+
+		assert.False(t, b.withinMaxTxPerBlock(1001))
+	})
+}
+
+func TestBlockAssembler_withinBlockMaxWeight(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+	b := &BlockAssembler{settings: tSettings}
+
+	t.Run("no weight limit", func(t *testing.T) {
+		tSettings.Policy.BlockMaxWeight = 0
+
+		assert.True(t, b.withinBlockMaxWeight(1<<40))
+	})
+
+	t.Run("block at the weight limit", func(t *testing.T) {
+		tSettings.Policy.BlockMaxWeight = 4000
+
+		assert.True(t, b.withinBlockMaxWeight(1000))
+	})
+
+	t.Run("block over the weight limit", func(t *testing.T) {
+		tSettings.Policy.BlockMaxWeight = 4000
+
+		assert.False(t, b.withinBlockMaxWeight(1001))
+	})
+}
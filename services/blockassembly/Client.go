@@ -485,3 +485,31 @@ func (s *Client) GetTransactionHashes(ctx context.Context) ([]string, error) {
 
 	return resp.Txs, nil
 }
+
+// PrioritiseTxs marks transactions as priority for inclusion in the next block.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - hashes: Hashes of the transactions to prioritise
+//   - remove: Whether to remove the transactions from the priority list instead
+//
+// Returns:
+//   - error: Any error encountered, e.g. when the priority list is full
+func (s *Client) PrioritiseTxs(ctx context.Context, hashes []chainhash.Hash, remove bool) error {
+	txHashes := make([][]byte, 0, len(hashes))
+	for _, hash := range hashes {
+		txHashes = append(txHashes, hash.CloneBytes())
+	}
+
+	_, err := s.client.PrioritiseTxs(ctx, &blockassembly_api.PrioritiseTxsRequest{
+		TxHashes: txHashes,
+		Remove:   remove,
+	})
+
+	unwrappedErr := errors.UnwrapGRPC(err)
+	if unwrappedErr == nil {
+		return nil
+	}
+
+	return unwrappedErr
+}
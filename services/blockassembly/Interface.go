@@ -146,6 +146,17 @@ type ClientI interface {
 	//   - []*chainhash.Hash: List of transaction hashes
 	//   - error: Any error encountered during retrieval
 	GetTransactionHashes(ctx context.Context) ([]string, error)
+
+	// PrioritiseTxs marks transactions as priority for inclusion in the next block.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - hashes: Hashes of the transactions to prioritise
+	//   - remove: Whether to remove the transactions from the priority list instead
+	//
+	// Returns:
+	//   - error: Any error encountered, e.g. when the priority list is full
+	PrioritiseTxs(ctx context.Context, hashes []chainhash.Hash, remove bool) error
 }
 
 // Store defines the interface for block assembly storage operations.
@@ -730,6 +730,12 @@ func (ba *BlockAssembly) AddTx(ctx context.Context, req *blockassembly_api.AddTx
 			errors.NewProcessingError("invalid txid length: %d for %s", len(req.Txid), utils.ReverseAndHexEncodeSlice(req.Txid)))
 	}
 
+	if ba.settings.TracingCorrelationIDsEnabled {
+		if correlationID := tracing.IncomingCorrelationID(ctx); correlationID != "" {
+			ba.logger.Infof("[AddTx][%s] adding transaction with correlation id %s to block assembly", utils.ReverseAndHexEncodeSlice(req.Txid), correlationID)
+		}
+	}
+
 	txInpoints, err := subtreepkg.NewTxInpointsFromBytes(req.TxInpoints)
 	if err != nil {
 		return nil, errors.WrapGRPC(errors.NewProcessingError("unable to deserialize tx inpoints", err))
@@ -1411,6 +1417,44 @@ func (ba *BlockAssembly) GetBlockAssemblyTxs(ctx context.Context, _ *blockassemb
 	}, nil
 }
 
+// PrioritiseTxs marks transactions as priority for inclusion in the next block, or removes them from the
+// priority list when req.Remove is set. Marked transactions are added to the subtrees ahead of the
+// transactions waiting in the queue when they are added to block assembly.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - req: Request containing the transaction hashes
+//
+// Returns:
+//   - *blockassembly_api.EmptyMessage: Empty response on success
+//   - error: Any error encountered, e.g. when the priority list is full
+func (ba *BlockAssembly) PrioritiseTxs(ctx context.Context, req *blockassembly_api.PrioritiseTxsRequest) (*blockassembly_api.EmptyMessage, error) {
+	_, _, deferFn := tracing.Tracer("blockassembly").Start(ctx, "PrioritiseTxs",
+		tracing.WithParentStat(ba.stats),
+		tracing.WithLogMessage(ba.logger, "[PrioritiseTxs] called for %d transactions, remove: %t", len(req.TxHashes), req.Remove),
+	)
+	defer deferFn()
+
+	hashes := make([]chainhash.Hash, 0, len(req.TxHashes))
+
+	for _, txHash := range req.TxHashes {
+		if len(txHash) != 32 {
+			return nil, errors.WrapGRPC(
+				errors.NewInvalidArgumentError("invalid tx hash length: %d for %s", len(txHash), utils.ReverseAndHexEncodeSlice(txHash)))
+		}
+
+		hashes = append(hashes, chainhash.Hash(txHash))
+	}
+
+	if req.Remove {
+		ba.blockAssembler.RemovePriorityTxs(hashes)
+	} else if err := ba.blockAssembler.PrioritiseTxs(hashes); err != nil {
+		return nil, errors.WrapGRPC(err)
+	}
+
+	return &blockassembly_api.EmptyMessage{}, nil
+}
+
 // GetCurrentDifficulty retrieves the current mining difficulty target.
 //
 // This method provides access to the current difficulty target required for valid
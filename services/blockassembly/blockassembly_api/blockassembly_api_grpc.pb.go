@@ -34,6 +34,7 @@ const (
 	BlockAssemblyAPI_CheckBlockAssembly_FullMethodName             = "/blockassembly_api.BlockAssemblyAPI/CheckBlockAssembly"
 	BlockAssemblyAPI_GetBlockAssemblyBlockCandidate_FullMethodName = "/blockassembly_api.BlockAssemblyAPI/GetBlockAssemblyBlockCandidate"
 	BlockAssemblyAPI_GetBlockAssemblyTxs_FullMethodName            = "/blockassembly_api.BlockAssemblyAPI/GetBlockAssemblyTxs"
+	BlockAssemblyAPI_PrioritiseTxs_FullMethodName                  = "/blockassembly_api.BlockAssemblyAPI/PrioritiseTxs"
 )
 
 // BlockAssemblyAPIClient is the client API for BlockAssemblyAPI service.
@@ -89,6 +90,9 @@ type BlockAssemblyAPIClient interface {
 	// This provides visibility into the transactions that are candidates for inclusion in the next block.
 	// NOTE: this method is primarily for debugging purposes and may not be suitable for production use.
 	GetBlockAssemblyTxs(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*GetBlockAssemblyTxsResponse, error)
+	// PrioritiseTxs marks transactions as priority, or removes them from the priority list.
+	// Priority transactions are included ahead of other transactions when they are added to block assembly.
+	PrioritiseTxs(ctx context.Context, in *PrioritiseTxsRequest, opts ...grpc.CallOption) (*EmptyMessage, error)
 }
 
 type blockAssemblyAPIClient struct {
@@ -239,6 +243,16 @@ func (c *blockAssemblyAPIClient) GetBlockAssemblyTxs(ctx context.Context, in *Em
 	return out, nil
 }
 
+func (c *blockAssemblyAPIClient) PrioritiseTxs(ctx context.Context, in *PrioritiseTxsRequest, opts ...grpc.CallOption) (*EmptyMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyMessage)
+	err := c.cc.Invoke(ctx, BlockAssemblyAPI_PrioritiseTxs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BlockAssemblyAPIServer is the server API for BlockAssemblyAPI service.
 // All implementations must embed UnimplementedBlockAssemblyAPIServer
 // for forward compatibility.
@@ -292,6 +306,9 @@ type BlockAssemblyAPIServer interface {
 	// This provides visibility into the transactions that are candidates for inclusion in the next block.
 	// NOTE: this method is primarily for debugging purposes and may not be suitable for production use.
 	GetBlockAssemblyTxs(context.Context, *EmptyMessage) (*GetBlockAssemblyTxsResponse, error)
+	// PrioritiseTxs marks transactions as priority, or removes them from the priority list.
+	// Priority transactions are included ahead of other transactions when they are added to block assembly.
+	PrioritiseTxs(context.Context, *PrioritiseTxsRequest) (*EmptyMessage, error)
 	mustEmbedUnimplementedBlockAssemblyAPIServer()
 }
 
@@ -344,6 +361,9 @@ func (UnimplementedBlockAssemblyAPIServer) GetBlockAssemblyBlockCandidate(contex
 func (UnimplementedBlockAssemblyAPIServer) GetBlockAssemblyTxs(context.Context, *EmptyMessage) (*GetBlockAssemblyTxsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlockAssemblyTxs not implemented")
 }
+func (UnimplementedBlockAssemblyAPIServer) PrioritiseTxs(context.Context, *PrioritiseTxsRequest) (*EmptyMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrioritiseTxs not implemented")
+}
 func (UnimplementedBlockAssemblyAPIServer) mustEmbedUnimplementedBlockAssemblyAPIServer() {}
 func (UnimplementedBlockAssemblyAPIServer) testEmbeddedByValue()                          {}
 
@@ -617,6 +637,24 @@ func _BlockAssemblyAPI_GetBlockAssemblyTxs_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlockAssemblyAPI_PrioritiseTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrioritiseTxsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockAssemblyAPIServer).PrioritiseTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlockAssemblyAPI_PrioritiseTxs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockAssemblyAPIServer).PrioritiseTxs(ctx, req.(*PrioritiseTxsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BlockAssemblyAPI_ServiceDesc is the grpc.ServiceDesc for BlockAssemblyAPI service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -680,6 +718,10 @@ var BlockAssemblyAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBlockAssemblyTxs",
 			Handler:    _BlockAssemblyAPI_GetBlockAssemblyTxs_Handler,
 		},
+		{
+			MethodName: "PrioritiseTxs",
+			Handler:    _BlockAssemblyAPI_PrioritiseTxs_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/blockassembly/blockassembly_api/blockassembly_api.proto",
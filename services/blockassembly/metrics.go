@@ -32,6 +32,9 @@ var (
 	// prometheusBlockAssemblerCacheMisses tracks cache misses for mining candidates
 	prometheusBlockAssemblerCacheMisses prometheus.Counter
 
+	// prometheusBlockAssemblerCacheStale tracks cached mining candidates rebuilt because they exceeded the max age
+	prometheusBlockAssemblerCacheStale prometheus.Counter
+
 	// prometheusBlockAssemblySubmitMiningSolutionCh tracks mining solution submission queue size
 	prometheusBlockAssemblySubmitMiningSolutionCh prometheus.Gauge
 
@@ -176,6 +179,15 @@ func _initPrometheusMetrics() {
 		},
 	)
 
+	prometheusBlockAssemblerCacheStale = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "blockassembly",
+			Name:      "cache_stale",
+			Help:      "Number of cached mining candidates rebuilt because they exceeded the max age",
+		},
+	)
+
 	prometheusBlockAssemblerTransactions = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: "teranode",
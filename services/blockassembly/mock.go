@@ -166,6 +166,12 @@ func (m *Mock) GetTransactionHashes(ctx context.Context) ([]string, error) {
 	return args.Get(0).([]string), nil
 }
 
+func (m *Mock) PrioritiseTxs(ctx context.Context, hashes []chainhash.Hash, remove bool) error {
+	args := m.Called(ctx, hashes, remove)
+
+	return args.Error(0)
+}
+
 // mockBlockAssemblyAPIClient is a mock implementation of BlockAssemblyAPIClient
 type mockBlockAssemblyAPIClient struct {
 	mock.Mock
@@ -282,3 +288,11 @@ func (m *mockBlockAssemblyAPIClient) GetBlockAssemblyTxs(ctx context.Context, in
 	}
 	return args.Get(0).(*blockassembly_api.GetBlockAssemblyTxsResponse), args.Error(1)
 }
+
+func (m *mockBlockAssemblyAPIClient) PrioritiseTxs(ctx context.Context, in *blockassembly_api.PrioritiseTxsRequest, opts ...grpc.CallOption) (*blockassembly_api.EmptyMessage, error) {
+	args := m.Called(ctx, in, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blockassembly_api.EmptyMessage), args.Error(1)
+}
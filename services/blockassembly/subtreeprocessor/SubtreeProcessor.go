@@ -221,6 +221,38 @@ type SubtreeProcessor struct {
 	// queue manages the transaction processing queue
 	queue *LockFreeQueue
 
+	// priorityQueue holds the transactions marked as priority, which are added to the subtrees
+	// before the transactions in the regular queue
+	priorityQueue *LockFreeQueue
+
+	// priorityTxs holds the hashes of the transactions marked as priority that have not been added to the
+	// priority queue yet, bounded by the blockassembly_maxPriorityTxs setting
+	priorityTxs map[chainhash.Hash]struct{}
+
+	// pendingPriorityMoves holds the hashes of newly marked transactions that may already wait in the regular
+	// queue, they are moved to the priority queue by the processing goroutine
+	pendingPriorityMoves map[chainhash.Hash]struct{}
+
+	// priorityTxsMu protects priorityTxs and pendingPriorityMoves
+	priorityTxsMu sync.RWMutex
+
+	// pendingPriorityMoveCount is the number of entries in pendingPriorityMoves
+	pendingPriorityMoveCount atomic.Int64
+
+	// priorityTxCount is the number of entries in priorityTxs, to skip the lookup when there are none
+	priorityTxCount atomic.Int64
+
+	// preferConfirmedParents holds back the transactions depending on unconfirmed parents, see the
+	// confirmed-parents-first transaction selection strategy
+	preferConfirmedParents bool
+
+	// deferredTxs holds the transactions held back by the confirmed-parents-first strategy, in the order they were
+	// dequeued, only accessed from the processing goroutine
+	deferredTxs []deferredTx
+
+	// deferredTxHashes holds the hashes of deferredTxs
+	deferredTxHashes map[chainhash.Hash]struct{}
+
 	// currentTxMap tracks transactions currently held in the subtree processor
 	currentTxMap *txmap.SyncedMap[chainhash.Hash, subtreepkg.TxInpoints]
 
@@ -323,6 +355,10 @@ func NewSubtreeProcessor(ctx context.Context, logger ulogger.Logger, tSettings *
 	blockchainClient blockchain.ClientI, utxoStore utxostore.Store, newSubtreeChan chan NewSubtreeRequest, options ...Options) (*SubtreeProcessor, error) {
 	initPrometheusMetrics()
 
+	if err := validateTxSelectionStrategy(tSettings.BlockAssembly.TxSelectionStrategy); err != nil {
+		return nil, err
+	}
+
 	initialItemsPerFile := tSettings.BlockAssembly.InitialMerkleItemsPerSubtree
 
 	firstSubtree, err := subtreepkg.NewTreeByLeafCount(initialItemsPerFile)
@@ -370,6 +406,11 @@ func NewSubtreeProcessor(ctx context.Context, logger ulogger.Logger, tSettings *
 		currentSubtree:           firstSubtree,
 		batcher:                  NewTxIDAndFeeBatch(tSettings.BlockAssembly.SubtreeProcessorBatcherSize),
 		queue:                    queue,
+		priorityQueue:            NewLockFreeQueue(),
+		priorityTxs:              make(map[chainhash.Hash]struct{}),
+		pendingPriorityMoves:     make(map[chainhash.Hash]struct{}),
+		preferConfirmedParents:   tSettings.BlockAssembly.TxSelectionStrategy == TxSelectionConfirmedParentsFirst,
+		deferredTxHashes:         make(map[chainhash.Hash]struct{}),
 		currentTxMap:             txmap.NewSyncedMap[chainhash.Hash, subtreepkg.TxInpoints](),
 		removeMap:                txmap.NewSwissMap(0),
 		blockchainClient:         blockchainClient,
@@ -486,6 +527,7 @@ func NewSubtreeProcessor(ctx context.Context, logger ulogger.Logger, tSettings *
 
 			case reorgReq := <-stp.reorgBlockChan:
 				stp.setCurrentRunningState(StateReorg)
+				stp.addDeferredTxs()
 				logger.Infof("[SubtreeProcessor] reorgReq subtree processor: %d, %d", len(reorgReq.moveBackBlocks), len(reorgReq.moveForwardBlocks))
 
 				reorgReq.errChan <- stp.reorgBlocks(ctx, reorgReq.moveBackBlocks, reorgReq.moveForwardBlocks)
@@ -495,6 +537,7 @@ func NewSubtreeProcessor(ctx context.Context, logger ulogger.Logger, tSettings *
 
 			case moveForwardReq := <-stp.moveForwardBlockChan:
 				stp.setCurrentRunningState(StateMoveForwardBlock)
+				stp.addDeferredTxs()
 
 				logger.Infof("[SubtreeProcessor][%s] moveForwardBlock subtree processor", moveForwardReq.block.String())
 
@@ -529,6 +572,7 @@ func NewSubtreeProcessor(ctx context.Context, logger ulogger.Logger, tSettings *
 
 			case resetBlocksMsg := <-stp.resetCh:
 				stp.setCurrentRunningState(StateResetBlocks)
+				stp.addDeferredTxs()
 
 				err = stp.reset(resetBlocksMsg.blockHeader, resetBlocksMsg.moveBackBlocks, resetBlocksMsg.moveForwardBlocks,
 					resetBlocksMsg.isLegacySync, resetBlocksMsg.postProcess)
@@ -563,65 +607,99 @@ func NewSubtreeProcessor(ctx context.Context, logger ulogger.Logger, tSettings *
 			default:
 				stp.setCurrentRunningState(StateDequeue)
 
-				nrProcessed := 0
-				mapLength := stp.removeMap.Length()
 				// set the validFromMillis to the current time minus the double spend window - so in the past
 				validFromMillis := time.Now().Add(-1 * stp.settings.BlockAssembly.DoubleSpendWindow).UnixMilli()
 
-				for {
-					node, txInpoints, _, found := stp.queue.dequeue(validFromMillis)
-					if !found {
-						time.Sleep(1 * time.Millisecond)
-						break
-					}
+				// priority transactions are added ahead of the transactions in the regular queue
+				if stp.pendingPriorityMoveCount.Load() > 0 {
+					stp.moveQueuedPriorityTxs()
+				}
 
-					// check if the tx needs to be removed
-					if mapLength > 0 && stp.removeMap.Exists(node.Hash) {
-						// remove from the map
-						if err = stp.removeMap.Delete(node.Hash); err != nil {
-							stp.logger.Errorf("[SubtreeProcessor] error removing tx from remove map: %s", err.Error())
-						}
+				if stp.priorityQueue.length() > 0 {
+					stp.txCount.Add(stp.dequeuePriorityTxs(validFromMillis, nil, nil, false))
+				}
 
-						continue
-					}
+				if drained := stp.dequeueTxs(validFromMillis); drained {
+					time.Sleep(1 * time.Millisecond)
+				}
 
-					if node.Hash.Equal(*subtreepkg.CoinbasePlaceholderHash) {
-						stp.logger.Errorf("[SubtreeProcessor] error adding node: skipping request to add coinbase tx placeholder")
-						continue
-					}
+				stp.setCurrentRunningState(StateRunning)
+			}
+		}
+	}()
 
-					// check if the tx is already in the currentTxMap
-					if _, ok := stp.currentTxMap.Get(node.Hash); ok {
-						stp.logger.Warnf("[SubtreeProcessor] error adding node: tx %s already in currentTxMap", node.Hash.String())
-						continue
-					}
+	return stp, nil
+}
+
+// dequeueTxs adds the transactions in the queue that are valid from the given time to the subtrees, up to
+// SubtreeProcessorBatcherSize transactions. Under the confirmed-parents-first selection strategy, the transactions
+// depending on unconfirmed parents are held back and added once the queue is drained.
+//
+// Parameters:
+//   - validFromMillis: Only transactions received before this time are added
+//
+// Returns:
+//   - bool: Whether the queue was drained, false when the batch size was reached first
+func (stp *SubtreeProcessor) dequeueTxs(validFromMillis int64) bool {
+	nrProcessed := 0
+	mapLength := stp.removeMap.Length()
 
-					// check txInpoints
-					// for _, parent := range txReq.txInpoints {
-					// 	if _, ok := stp.currentTxMap.Get(parent); !ok {
-					// 		stp.logger.Errorf("[SubtreeProcessor] error adding node: parent %s not found in currentTxMap", parent.String())
-					// 		continue
-					// 	}
-					// }
+	for {
+		node, txInpoints, _, found := stp.queue.dequeue(validFromMillis)
+		if !found {
+			// the transactions depending on unconfirmed parents follow all other transactions
+			stp.addDeferredTxs()
 
-					if err = stp.addNode(node, &txInpoints, false); err != nil {
-						stp.logger.Errorf("[SubtreeProcessor] error adding node: %s", err.Error())
-					} else {
-						stp.txCount.Add(1)
-					}
+			return true
+		}
 
-					nrProcessed++
-					if nrProcessed > stp.settings.BlockAssembly.SubtreeProcessorBatcherSize {
-						break
-					}
-				}
+		// check if the tx needs to be removed
+		if mapLength > 0 && stp.removeMap.Exists(node.Hash) {
+			// remove from the map
+			if err := stp.removeMap.Delete(node.Hash); err != nil {
+				stp.logger.Errorf("[SubtreeProcessor] error removing tx from remove map: %s", err.Error())
+			}
 
-				stp.setCurrentRunningState(StateRunning)
+			continue
+		}
+
+		if node.Hash.Equal(*subtreepkg.CoinbasePlaceholderHash) {
+			stp.logger.Errorf("[SubtreeProcessor] error adding node: skipping request to add coinbase tx placeholder")
+			continue
+		}
+
+		// check if the tx is already in the currentTxMap
+		if _, ok := stp.currentTxMap.Get(node.Hash); ok {
+			stp.logger.Warnf("[SubtreeProcessor] error adding node: tx %s already in currentTxMap", node.Hash.String())
+			continue
+		}
+
+		// check txInpoints
+		// for _, parent := range txReq.txInpoints {
+		// 	if _, ok := stp.currentTxMap.Get(parent); !ok {
+		// 		stp.logger.Errorf("[SubtreeProcessor] error adding node: parent %s not found in currentTxMap", parent.String())
+		// 		continue
+		// 	}
+		// }
+
+		if stp.isDeferred(node.Hash) {
+			continue
+		}
+
+		// the transactions depending on unconfirmed parents are held back under the confirmed-parents-first strategy
+		if !stp.deferTx(node, txInpoints) {
+			if err := stp.addNode(node, &txInpoints, false); err != nil {
+				stp.logger.Errorf("[SubtreeProcessor] error adding node: %s", err.Error())
+			} else {
+				stp.txCount.Add(1)
 			}
 		}
-	}()
 
-	return stp, nil
+		nrProcessed++
+		if nrProcessed > stp.settings.BlockAssembly.SubtreeProcessorBatcherSize {
+			return false
+		}
+	}
 }
 
 // setCurrentRunningState updates the current operational state of the processor.
@@ -825,11 +903,13 @@ func (stp *SubtreeProcessor) reset(blockHeader *model.BlockHeader, moveBackBlock
 
 	validUntilMillis := time.Now().UnixMilli()
 
-	for {
-		_, _, time64, found := stp.queue.dequeue(0)
-		if !found || time64 > validUntilMillis {
-			// we are done
-			break
+	for _, queue := range []*LockFreeQueue{stp.priorityQueue, stp.queue} {
+		for {
+			_, _, time64, found := queue.dequeue(0)
+			if !found || time64 > validUntilMillis {
+				// we are done
+				break
+			}
 		}
 	}
 
@@ -979,7 +1059,7 @@ func (stp *SubtreeProcessor) TxCount() uint64 {
 // Returns:
 //   - int64: Current queue length
 func (stp *SubtreeProcessor) QueueLength() int64 {
-	return stp.queue.length()
+	return stp.queue.length() + stp.priorityQueue.length()
 }
 
 // SubtreeCount returns the total number of subtrees.
@@ -1290,13 +1370,235 @@ func (stp *SubtreeProcessor) processCompleteSubtree(skipNotification bool) (err
 }
 
 // Add adds a transaction node to the processor.
+// Transactions marked as priority are added to the priority queue, to be processed before the regular queue.
 //
 // Parameters:
 //   - node: Transaction node to add
 func (stp *SubtreeProcessor) Add(node subtreepkg.Node, txInpoints subtreepkg.TxInpoints) {
+	if stp.priorityTxCount.Load() > 0 && stp.takePriorityTx(node.Hash) {
+		stp.priorityQueue.enqueue(node, txInpoints)
+		return
+	}
+
 	stp.queue.enqueue(node, txInpoints)
 }
 
+// PrioritiseTxs marks transactions as priority. When a marked transaction is added to block assembly, it is
+// added to the subtrees ahead of the transactions waiting in the regular queue, so it is included first in
+// the next block. Marked transactions already waiting in the regular queue are moved to the priority queue,
+// transactions already in the subtrees keep their position.
+//
+// Parameters:
+//   - hashes: Hashes of the transactions to prioritise
+//
+// Returns:
+//   - error: InvalidArgumentError when prioritisation is disabled or the priority list would exceed
+//     blockassembly_maxPriorityTxs
+func (stp *SubtreeProcessor) PrioritiseTxs(hashes []chainhash.Hash) error {
+	maxPriorityTxs := stp.settings.BlockAssembly.MaxPriorityTxs
+	if maxPriorityTxs <= 0 {
+		return errors.NewInvalidArgumentError("transaction prioritisation is disabled, blockassembly_maxPriorityTxs is 0")
+	}
+
+	stp.priorityTxsMu.Lock()
+	defer stp.priorityTxsMu.Unlock()
+
+	newHashes := make(map[chainhash.Hash]struct{}, len(hashes))
+
+	for _, hash := range hashes {
+		if _, ok := stp.priorityTxs[hash]; !ok {
+			newHashes[hash] = struct{}{}
+		}
+	}
+
+	if len(stp.priorityTxs)+len(newHashes) > maxPriorityTxs {
+		return errors.NewInvalidArgumentError("cannot prioritise %d transactions, %d of max %d priority transactions already marked", len(newHashes), len(stp.priorityTxs), maxPriorityTxs)
+	}
+
+	for hash := range newHashes {
+		stp.priorityTxs[hash] = struct{}{}
+		stp.pendingPriorityMoves[hash] = struct{}{}
+	}
+
+	stp.priorityTxCount.Store(int64(len(stp.priorityTxs)))
+	stp.pendingPriorityMoveCount.Store(int64(len(stp.pendingPriorityMoves)))
+
+	return nil
+}
+
+// RemovePriorityTxs removes transactions from the priority list. Transactions that are not in the list are ignored.
+//
+// Parameters:
+//   - hashes: Hashes of the transactions to remove from the priority list
+func (stp *SubtreeProcessor) RemovePriorityTxs(hashes []chainhash.Hash) {
+	stp.priorityTxsMu.Lock()
+	defer stp.priorityTxsMu.Unlock()
+
+	for _, hash := range hashes {
+		delete(stp.priorityTxs, hash)
+		delete(stp.pendingPriorityMoves, hash)
+	}
+
+	stp.priorityTxCount.Store(int64(len(stp.priorityTxs)))
+	stp.pendingPriorityMoveCount.Store(int64(len(stp.pendingPriorityMoves)))
+}
+
+// takePriorityTx returns whether the transaction is marked as priority, removing it from the priority list
+// to free up the space for other transactions.
+func (stp *SubtreeProcessor) takePriorityTx(hash chainhash.Hash) bool {
+	stp.priorityTxsMu.RLock()
+	_, ok := stp.priorityTxs[hash]
+	stp.priorityTxsMu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	stp.RemovePriorityTxs([]chainhash.Hash{hash})
+
+	return true
+}
+
+// moveQueuedPriorityTxs moves the newly marked transactions that wait in the regular queue to the priority queue.
+// The moved transactions are removed from the priority list, marked transactions that are not queued yet stay
+// in the list until they are added. Must be called from the goroutine that dequeues.
+func (stp *SubtreeProcessor) moveQueuedPriorityTxs() {
+	stp.priorityTxsMu.Lock()
+	pending := stp.pendingPriorityMoves
+	stp.pendingPriorityMoves = make(map[chainhash.Hash]struct{})
+	stp.pendingPriorityMoveCount.Store(0)
+	stp.priorityTxsMu.Unlock()
+
+	if moved := stp.queue.moveTo(pending, stp.priorityQueue); len(moved) > 0 {
+		stp.logger.Debugf("[SubtreeProcessor] moved %d queued priority txs to the priority queue", len(moved))
+		stp.RemovePriorityTxs(moved)
+	}
+}
+
+// priorityTx is a transaction taken from the priority queue
+type priorityTx struct {
+	node       subtreepkg.Node
+	txInpoints subtreepkg.TxInpoints
+	time       int64
+}
+
+// dequeuePriorityTxs adds the transactions in the priority queue to the subtrees. A priority transaction with
+// a parent that is not in the subtrees yet but still waiting in one of the queues is moved to the regular
+// queue instead, since adding it ahead of its parent would break the transaction order.
+//
+// Parameters:
+//   - validFromMillis: Only transactions queued before this time are dequeued
+//   - transactionMap: Optional map of transactions to skip, e.g. because they are in a block
+//   - losingTxHashesMap: Optional map of conflicting transactions to skip
+//   - skipNotification: Whether to skip notification of new subtrees
+//
+// Returns:
+//   - uint64: Number of transactions added to the subtrees
+func (stp *SubtreeProcessor) dequeuePriorityTxs(validFromMillis int64, transactionMap, losingTxHashesMap txmap.TxMap, skipNotification bool) uint64 {
+	// take the priority transactions out of the queue first, so their parents can be checked against each other
+	var txs []priorityTx
+
+	for {
+		node, txInpoints, time64, found := stp.priorityQueue.dequeue(validFromMillis)
+		if !found {
+			break
+		}
+
+		txs = append(txs, priorityTx{node: node, txInpoints: txInpoints, time: time64})
+	}
+
+	queuedParents := stp.queuedPriorityParents(txs)
+
+	nrAdded := uint64(0)
+
+	for _, tx := range txs {
+		node, txInpoints := tx.node, tx.txInpoints
+
+		if stp.removeMap.Length() > 0 && stp.removeMap.Exists(node.Hash) {
+			if err := stp.removeMap.Delete(node.Hash); err != nil {
+				stp.logger.Errorf("[SubtreeProcessor] error removing tx from remove map: %s", err.Error())
+			}
+
+			continue
+		}
+
+		if (transactionMap != nil && transactionMap.Exists(node.Hash)) || (losingTxHashesMap != nil && losingTxHashesMap.Exists(node.Hash)) {
+			continue
+		}
+
+		if _, ok := stp.currentTxMap.Get(node.Hash); ok {
+			continue
+		}
+
+		if !stp.priorityParentsAvailable(&txInpoints, queuedParents) {
+			stp.logger.Debugf("[SubtreeProcessor] priority tx %s has unprocessed parents, moving it to the regular queue", node.Hash.String())
+			stp.queue.enqueueAt(node, txInpoints, tx.time)
+
+			continue
+		}
+
+		if err := stp.addNode(node, &txInpoints, skipNotification); err != nil {
+			stp.logger.Errorf("[SubtreeProcessor] error adding priority node: %s", err.Error())
+			continue
+		}
+
+		nrAdded++
+	}
+
+	return nrAdded
+}
+
+// queuedPriorityParents returns the parents of the priority transactions that are not in the subtrees yet and
+// still wait to be processed, in the taken priority transactions themselves or in one of the queues.
+func (stp *SubtreeProcessor) queuedPriorityParents(txs []priorityTx) map[chainhash.Hash]struct{} {
+	queued := make(map[chainhash.Hash]struct{})
+	unresolved := make(map[chainhash.Hash]struct{})
+
+	for _, tx := range txs {
+		queued[tx.node.Hash] = struct{}{}
+	}
+
+	for _, tx := range txs {
+		for _, parentHash := range tx.txInpoints.ParentTxHashes {
+			if _, ok := queued[parentHash]; ok {
+				continue
+			}
+
+			if _, ok := stp.currentTxMap.Get(parentHash); !ok {
+				unresolved[parentHash] = struct{}{}
+			}
+		}
+	}
+
+	if len(unresolved) == 0 {
+		return queued
+	}
+
+	for _, queue := range []*LockFreeQueue{stp.queue, stp.priorityQueue} {
+		for hash := range queue.queuedHashes(unresolved) {
+			queued[hash] = struct{}{}
+		}
+	}
+
+	return queued
+}
+
+// priorityParentsAvailable returns whether all parents of a transaction are available, a parent is available
+// when it is in the subtrees already or does not wait in one of the queues, i.e. it is mined.
+func (stp *SubtreeProcessor) priorityParentsAvailable(txInpoints *subtreepkg.TxInpoints, queuedParents map[chainhash.Hash]struct{}) bool {
+	for _, parentHash := range txInpoints.ParentTxHashes {
+		if _, ok := stp.currentTxMap.Get(parentHash); ok {
+			continue
+		}
+
+		if _, ok := queuedParents[parentHash]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
 // AddDirectly adds a transaction node directly to the subtree processor without going through the queue.
 // It is used for transactions that are already known to be valid and should be added immediately.
 // This is useful for transactions that are part of the current block being processed.
@@ -1751,39 +2053,11 @@ func (stp *SubtreeProcessor) reorgBlocks(ctx context.Context, moveBackBlocks []*
 	// When moving back, transactions that were in the winning chain need to have their
 	// UTXO spends reverted so they can be properly detected as conflicts when moving forward
 
-	// the processed conflicting hashes map keeps track of all the conflicting hashes we've already processed
-	// this is to avoid processing the same conflicting hash multiple times if it appears in multiple blocks
-	// the map is only used during the reorg process and is not stored in the SubtreeProcessor struct
-	processedConflictingHashesMap := make(map[chainhash.Hash]bool)
-
-	// movedBackBlockTxMap keeps track of all the transactions that were in the blocks we moved back
-	// this is used to determine which transactions need to be marked as on the longest chain when moving forward
-	// if a transaction was in a block we moved back, it means it was on the longest chain before the reorg
-	movedBackBlockTxMap := make(map[chainhash.Hash]bool) // keeps track of all the transactions that were in the blocks we moved back
-
-	for _, block := range moveBackBlocks {
-		// move back the block, getting all the transactions in the block and any conflicting hashes
-		// if we are not moving forward any blocks, we need to make sure we create properly sized subtrees
-		// so we pass in len(moveForwardBlocks) == 0 as the second parameter
-		subtreesNodes, conflictingHashes, err := stp.moveBackBlock(ctx, block, len(moveForwardBlocks) == 0)
-		if err != nil {
-			return err
-		}
-
-		if len(conflictingHashes) > 0 {
-			for _, hash := range conflictingHashes {
-				processedConflictingHashesMap[hash] = true
-			}
-		}
-
-		// add all the transactions in the block to the movedBackBlockTxMap
-		for _, subtreeNodes := range subtreesNodes {
-			for _, node := range subtreeNodes {
-				if !node.Hash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
-					movedBackBlockTxMap[node.Hash] = true
-				}
-			}
-		}
+	// if we are not moving forward any blocks, we need to make sure we create properly sized subtrees
+	// so we pass in len(moveForwardBlocks) == 0 as the second parameter
+	processedConflictingHashesMap, movedBackBlockTxMap, err := stp.moveBackBlocks(ctx, moveBackBlocks, len(moveForwardBlocks) == 0)
+	if err != nil {
+		return err
 	}
 
 	if len(moveBackBlocks) > 0 {
@@ -2038,7 +2312,7 @@ func (stp *SubtreeProcessor) setTxCountFromSubtrees() {
 		return
 	}
 
-	queueLenUint64, err := safeconversion.Int64ToUint64(stp.queue.length())
+	queueLenUint64, err := safeconversion.Int64ToUint64(stp.QueueLength())
 	if err != nil {
 		stp.logger.Errorf("error converting queue length: %s", err)
 		return
@@ -2048,6 +2322,63 @@ func (stp *SubtreeProcessor) setTxCountFromSubtrees() {
 	stp.txCount.Add(queueLenUint64)
 }
 
+// moveBackBlocks moves back the given blocks in order, tip first, adding the transactions of the blocks back
+// into the subtrees. The blocks are moved back one after the other, since each block is moved back onto the
+// state left by the previous one, but when MoveBackBlockPrefetch is set the subtrees of the following blocks
+// are loaded from the subtree store while a block is being moved back.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - blocks: Blocks to move back, tip first
+//   - createProperlySizedSubtrees: Whether to create subtrees of the current size, see moveBackBlock
+//
+// Returns:
+//   - map[chainhash.Hash]bool: The conflicting hashes of the blocks
+//   - map[chainhash.Hash]bool: The transactions of the blocks
+//   - error: Any error encountered while moving back the blocks
+func (stp *SubtreeProcessor) moveBackBlocks(ctx context.Context, blocks []*model.Block, createProperlySizedSubtrees bool) (map[chainhash.Hash]bool, map[chainhash.Hash]bool, error) {
+	// the processed conflicting hashes map keeps track of all the conflicting hashes we've already processed
+	// this is to avoid processing the same conflicting hash multiple times if it appears in multiple blocks
+	// the map is only used during the reorg process and is not stored in the SubtreeProcessor struct
+	processedConflictingHashesMap := make(map[chainhash.Hash]bool)
+
+	// movedBackBlockTxMap keeps track of all the transactions that were in the blocks we moved back
+	// this is used to determine which transactions need to be marked as on the longest chain when moving forward
+	// if a transaction was in a block we moved back, it means it was on the longest chain before the reorg
+	movedBackBlockTxMap := make(map[chainhash.Hash]bool) // keeps track of all the transactions that were in the blocks we moved back
+
+	prefetcher := stp.prefetchMoveBackBlocks(ctx, blocks)
+	defer prefetcher.stop()
+
+	for blockIdx, block := range blocks {
+		blockSubtrees, err := prefetcher.get(ctx, blockIdx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// move back the block, getting all the transactions in the block and any conflicting hashes
+		subtreesNodes, conflictingHashes, err := stp.moveBackBlockWithSubtrees(ctx, block, createProperlySizedSubtrees, blockSubtrees)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, hash := range conflictingHashes {
+			processedConflictingHashesMap[hash] = true
+		}
+
+		// add all the transactions in the block to the movedBackBlockTxMap
+		for _, subtreeNodes := range subtreesNodes {
+			for _, node := range subtreeNodes {
+				if !node.Hash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
+					movedBackBlockTxMap[node.Hash] = true
+				}
+			}
+		}
+	}
+
+	return processedConflictingHashesMap, movedBackBlockTxMap, nil
+}
+
 // moveBackBlock processes a block during downward chain movement.
 //
 // Parameters:
@@ -2057,6 +2388,13 @@ func (stp *SubtreeProcessor) setTxCountFromSubtrees() {
 // Returns:
 //   - error: Any error encountered during processing
 func (stp *SubtreeProcessor) moveBackBlock(ctx context.Context, block *model.Block, createProperlySizedSubtrees bool) (subtreesNodes [][]subtreepkg.Node, conflictingHashes []chainhash.Hash, err error) {
+	return stp.moveBackBlockWithSubtrees(ctx, block, createProperlySizedSubtrees, nil)
+}
+
+// moveBackBlockWithSubtrees moves back a block like moveBackBlock, using the already loaded subtrees of the
+// block when blockSubtrees is not nil.
+func (stp *SubtreeProcessor) moveBackBlockWithSubtrees(ctx context.Context, block *model.Block, createProperlySizedSubtrees bool,
+	blockSubtrees *moveBackBlockSubtrees) (subtreesNodes [][]subtreepkg.Node, conflictingHashes []chainhash.Hash, err error) {
 	if block == nil {
 		return nil, nil, errors.NewProcessingError("[moveBackBlock] you must pass in a block to moveBackBlock")
 	}
@@ -2082,7 +2420,7 @@ func (stp *SubtreeProcessor) moveBackBlock(ctx context.Context, block *model.Blo
 	}
 
 	// create new subtrees and add all the transactions from the block to it
-	if subtreesNodes, conflictingHashes, err = stp.moveBackBlockCreateNewSubtrees(ctx, block, createProperlySizedSubtrees); err != nil {
+	if subtreesNodes, conflictingHashes, err = stp.moveBackBlockCreateNewSubtrees(ctx, block, createProperlySizedSubtrees, blockSubtrees); err != nil {
 		return nil, nil, err
 	}
 
@@ -2138,18 +2476,28 @@ func (stp *SubtreeProcessor) moveBackBlockAddPreviousNodes(ctx context.Context,
 	return nil
 }
 
-func (stp *SubtreeProcessor) moveBackBlockCreateNewSubtrees(ctx context.Context, block *model.Block, createProperlySizedSubtrees bool) ([][]subtreepkg.Node, []chainhash.Hash, error) {
+func (stp *SubtreeProcessor) moveBackBlockCreateNewSubtrees(ctx context.Context, block *model.Block, createProperlySizedSubtrees bool,
+	blockSubtrees *moveBackBlockSubtrees) ([][]subtreepkg.Node, []chainhash.Hash, error) {
 	_, _, deferFn := tracing.Tracer("subtreeprocessor").Start(ctx, "moveBackBlockCreateNewSubtrees",
 		tracing.WithLogMessage(stp.logger, "[moveBackBlock:CreateNewSubtrees][%s] with %d subtrees: create new subtrees", block.String(), len(block.Subtrees)),
 	)
 	defer deferFn()
 
-	// get all the subtrees in the block
-	subtreesNodes, subtreeMetaTxInpoints, conflictingHashes, err := stp.moveBackBlockGetSubtrees(ctx, block)
-	if err != nil {
-		return nil, nil, errors.NewProcessingError("[moveBackBlock:CreateNewSubtrees][%s] error getting subtrees", block.String(), err)
+	// get all the subtrees in the block, unless they were prefetched
+	if blockSubtrees == nil {
+		blockSubtrees = stp.loadMoveBackBlockSubtrees(ctx, block)
+	}
+
+	if blockSubtrees.err != nil {
+		return nil, nil, errors.NewProcessingError("[moveBackBlock:CreateNewSubtrees][%s] error getting subtrees", block.String(), blockSubtrees.err)
 	}
 
+	subtreesNodes := blockSubtrees.subtreesNodes
+	subtreeMetaTxInpoints := blockSubtrees.subtreeMetaTxInpoints
+	conflictingHashes := blockSubtrees.conflictingHashes
+
+	var err error
+
 	// reset the subtree processor
 	subtreeSize := stp.currentItemsPerFile
 	if !createProperlySizedSubtrees {
@@ -2737,7 +3085,7 @@ func (stp *SubtreeProcessor) WaitForPendingBlocks(ctx context.Context) error {
 	return err
 }
 
-// dequeueDuringBlockMovement processes the transaction queue during block movement.
+// dequeueDuringBlockMovement processes the transaction queue during block movement, the priority queue first.
 //
 // Parameters:
 //   - transactionMap: Map of transactions that were in the block and need to be removed
@@ -2747,6 +3095,15 @@ func (stp *SubtreeProcessor) WaitForPendingBlocks(ctx context.Context) error {
 // Returns:
 //   - error: Any error encountered during processing
 func (stp *SubtreeProcessor) dequeueDuringBlockMovement(transactionMap, losingTxHashesMap txmap.TxMap, skipNotification bool) (err error) {
+	if stp.pendingPriorityMoveCount.Load() > 0 {
+		stp.moveQueuedPriorityTxs()
+	}
+
+	if stp.priorityQueue.length() > 0 {
+		validFromMillis := time.Now().Add(-1 * stp.settings.BlockAssembly.DoubleSpendWindow).UnixMilli()
+		stp.dequeuePriorityTxs(validFromMillis, transactionMap, losingTxHashesMap, skipNotification)
+	}
+
 	queueLength := stp.queue.length()
 	if queueLength > 0 {
 		nrProcessed := int64(0)
@@ -2145,6 +2145,178 @@ func Test_removeMap(t *testing.T) {
 	})
 }
 
+func TestSubtreeProcessor_PrioritiseTxs(t *testing.T) {
+	newPriorityTestProcessor := func(t *testing.T, maxPriorityTxs int) *SubtreeProcessor {
+		settings := test.CreateBaseTestSettings(t)
+		settings.BlockAssembly.InitialMerkleItemsPerSubtree = 128
+		settings.BlockAssembly.MaxPriorityTxs = maxPriorityTxs
+		// keep the transactions in the queues, so the processing order can be checked deterministically
+		settings.BlockAssembly.DoubleSpendWindow = time.Hour
+
+		stp, err := NewSubtreeProcessor(context.Background(), ulogger.TestLogger{}, settings, nil, nil, nil, make(chan NewSubtreeRequest, 10))
+		require.NoError(t, err)
+
+		return stp
+	}
+
+	// processQueues processes the queues in the same order as the subtree processor does
+	processQueues := func(t *testing.T, stp *SubtreeProcessor) {
+		validFromMillis := time.Now().Add(time.Second).UnixMilli()
+
+		stp.moveQueuedPriorityTxs()
+		stp.dequeuePriorityTxs(validFromMillis, nil, nil, true)
+
+		for {
+			node, txInpoints, _, found := stp.queue.dequeue(validFromMillis)
+			if !found {
+				break
+			}
+
+			require.NoError(t, stp.addNode(node, &txInpoints, true))
+		}
+	}
+
+	t.Run("priority tx is included ahead of higher fee txs", func(t *testing.T) {
+		stp := newPriorityTestProcessor(t, 10)
+
+		priorityHash := chainhash.HashH([]byte("priority-tx"))
+		require.NoError(t, stp.PrioritiseTxs([]chainhash.Hash{priorityHash}))
+
+		for i := 0; i < 5; i++ {
+			txHash := chainhash.HashH([]byte(fmt.Sprintf("txid-%d", i)))
+			stp.Add(subtreepkg.Node{Hash: txHash, Fee: 1000, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+		}
+
+		stp.Add(subtreepkg.Node{Hash: priorityHash, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+
+		assert.Equal(t, int64(1), stp.priorityQueue.length())
+		assert.Equal(t, int64(6), stp.QueueLength())
+		assert.Equal(t, int64(0), stp.priorityTxCount.Load())
+
+		processQueues(t, stp)
+
+		require.Len(t, stp.currentSubtree.Nodes, 7)
+		assert.Equal(t, *subtreepkg.CoinbasePlaceholderHash, stp.currentSubtree.Nodes[0].Hash)
+		assert.Equal(t, priorityHash, stp.currentSubtree.Nodes[1].Hash)
+	})
+
+	t.Run("priority tx with unprocessed parent is moved to the regular queue", func(t *testing.T) {
+		stp := newPriorityTestProcessor(t, 10)
+
+		parentHash := chainhash.HashH([]byte("parent-tx"))
+		childHash := chainhash.HashH([]byte("child-tx"))
+		require.NoError(t, stp.PrioritiseTxs([]chainhash.Hash{childHash}))
+
+		stp.Add(subtreepkg.Node{Hash: parentHash, Fee: 1000, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+		stp.Add(subtreepkg.Node{Hash: childHash, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{parentHash}})
+
+		processQueues(t, stp)
+
+		require.Len(t, stp.currentSubtree.Nodes, 3)
+		assert.Equal(t, parentHash, stp.currentSubtree.Nodes[1].Hash)
+		assert.Equal(t, childHash, stp.currentSubtree.Nodes[2].Hash)
+	})
+
+	t.Run("queued tx is moved ahead when it is marked", func(t *testing.T) {
+		stp := newPriorityTestProcessor(t, 10)
+
+		priorityHash := chainhash.HashH([]byte("priority-tx"))
+
+		for i := 0; i < 5; i++ {
+			txHash := chainhash.HashH([]byte(fmt.Sprintf("txid-%d", i)))
+			stp.Add(subtreepkg.Node{Hash: txHash, Fee: 1000, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+		}
+
+		stp.Add(subtreepkg.Node{Hash: priorityHash, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+
+		// the transaction is marked after it was queued
+		require.NoError(t, stp.PrioritiseTxs([]chainhash.Hash{priorityHash}))
+
+		stp.moveQueuedPriorityTxs()
+
+		assert.Equal(t, int64(1), stp.priorityQueue.length())
+		assert.Equal(t, int64(5), stp.queue.length())
+		assert.Equal(t, int64(0), stp.priorityTxCount.Load())
+
+		processQueues(t, stp)
+
+		require.Len(t, stp.currentSubtree.Nodes, 7)
+		assert.Equal(t, priorityHash, stp.currentSubtree.Nodes[1].Hash)
+
+		// the moved transaction is not added a second time from the regular queue
+		for _, node := range stp.currentSubtree.Nodes[2:] {
+			assert.NotEqual(t, priorityHash, node.Hash)
+		}
+	})
+
+	t.Run("priority tx with a parent behind it in the priority queue is added after the parent", func(t *testing.T) {
+		stp := newPriorityTestProcessor(t, 10)
+
+		parentHash := chainhash.HashH([]byte("parent-tx"))
+		childHash := chainhash.HashH([]byte("child-tx"))
+
+		stp.Add(subtreepkg.Node{Hash: chainhash.HashH([]byte("other-tx")), Fee: 1000, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+		stp.Add(subtreepkg.Node{Hash: parentHash, Fee: 1000, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+
+		// the child is marked first and added to the priority queue, the queued parent is moved behind it
+		require.NoError(t, stp.PrioritiseTxs([]chainhash.Hash{childHash}))
+		stp.Add(subtreepkg.Node{Hash: childHash, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{parentHash}})
+
+		require.NoError(t, stp.PrioritiseTxs([]chainhash.Hash{parentHash}))
+
+		processQueues(t, stp)
+
+		require.Len(t, stp.currentSubtree.Nodes, 4)
+		assert.Equal(t, parentHash, stp.currentSubtree.Nodes[1].Hash)
+		assert.Equal(t, chainhash.HashH([]byte("other-tx")), stp.currentSubtree.Nodes[2].Hash)
+		assert.Equal(t, childHash, stp.currentSubtree.Nodes[3].Hash)
+	})
+
+	t.Run("priority tx with a mined parent is added first", func(t *testing.T) {
+		stp := newPriorityTestProcessor(t, 10)
+
+		childHash := chainhash.HashH([]byte("child-tx"))
+		require.NoError(t, stp.PrioritiseTxs([]chainhash.Hash{childHash}))
+
+		stp.Add(subtreepkg.Node{Hash: chainhash.HashH([]byte("other-tx")), Fee: 1000, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+
+		// the parent is neither in the subtrees nor queued, so it is mined
+		stp.Add(subtreepkg.Node{Hash: childHash, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{chainhash.HashH([]byte("mined-parent-tx"))}})
+
+		processQueues(t, stp)
+
+		require.Len(t, stp.currentSubtree.Nodes, 3)
+		assert.Equal(t, childHash, stp.currentSubtree.Nodes[1].Hash)
+	})
+
+	t.Run("priority list is bounded", func(t *testing.T) {
+		stp := newPriorityTestProcessor(t, 2)
+
+		hashes := []chainhash.Hash{chainhash.HashH([]byte("tx-1")), chainhash.HashH([]byte("tx-2")), chainhash.HashH([]byte("tx-3"))}
+
+		require.NoError(t, stp.PrioritiseTxs(hashes[:2]))
+
+		// marking the same transactions again does not use up more space
+		require.NoError(t, stp.PrioritiseTxs(hashes[:2]))
+
+		err := stp.PrioritiseTxs(hashes[2:])
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrInvalidArgument))
+
+		stp.RemovePriorityTxs(hashes[:1])
+		require.NoError(t, stp.PrioritiseTxs(hashes[2:]))
+		assert.Equal(t, int64(2), stp.priorityTxCount.Load())
+	})
+
+	t.Run("prioritisation disabled", func(t *testing.T) {
+		stp := newPriorityTestProcessor(t, 0)
+
+		err := stp.PrioritiseTxs([]chainhash.Hash{chainhash.HashH([]byte("tx-1"))})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrInvalidArgument))
+	})
+}
+
 func waitForSubtreeProcessorQueueToEmpty(t *testing.T, stp *SubtreeProcessor) {
 	t.Helper()
 
@@ -3050,7 +3222,7 @@ func TestMoveBackBlockChildrenRemoval(t *testing.T) {
 		}
 
 		// Call moveBackBlockCreateNewSubtrees directly
-		_, _, err = stp.moveBackBlockCreateNewSubtrees(ctx, block, true)
+		_, _, err = stp.moveBackBlockCreateNewSubtrees(ctx, block, true, nil)
 		require.NoError(t, err, "moveBackBlockCreateNewSubtrees should succeed")
 	})
 }
@@ -3415,7 +3587,7 @@ func TestMoveBackBlockCreateNewSubtrees_ErrorRecovery(t *testing.T) {
 		originalState := captureSubtreeProcessorState(stp)
 
 		// Call moveBackBlockCreateNewSubtrees
-		_, _, err = stp.moveBackBlockCreateNewSubtrees(context.Background(), block, true)
+		_, _, err = stp.moveBackBlockCreateNewSubtrees(context.Background(), block, true, nil)
 
 		// Should handle corrupted data gracefully or return appropriate error
 		if err != nil {
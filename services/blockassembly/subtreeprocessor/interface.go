@@ -229,6 +229,22 @@ type Interface interface {
 	//   - int64: Current queue length
 	QueueLength() int64
 
+	// PrioritiseTxs marks transactions as priority, so they are added to the subtrees ahead of the
+	// transactions waiting in the regular queue when they are added to block assembly.
+	//
+	// Parameters:
+	//   - hashes: Hashes of the transactions to prioritise
+	//
+	// Returns:
+	//   - error: Any error encountered, e.g. when the priority list is full
+	PrioritiseTxs(hashes []chainhash.Hash) error
+
+	// RemovePriorityTxs removes transactions from the priority list.
+	//
+	// Parameters:
+	//   - hashes: Hashes of the transactions to remove from the priority list
+	RemovePriorityTxs(hashes []chainhash.Hash)
+
 	// SubtreeCount returns the total number of subtrees managed by the processor.
 	// This metric provides visibility into the processor's organizational state.
 	//
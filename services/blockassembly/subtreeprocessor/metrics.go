@@ -30,6 +30,7 @@ var (
 	prometheusSubtreeProcessorReset                        prometheus.Histogram
 	prometheusSubtreeProcessorDynamicSubtreeSize           prometheus.Gauge
 	prometheusSubtreeProcessorCurrentState                 prometheus.Gauge
+	prometheusSubtreeProcessorDeferredTxs                  prometheus.Counter
 )
 
 var (
@@ -190,4 +191,13 @@ func _initPrometheusMetrics() {
 			Help:      "Current state of the block assembly process",
 		},
 	)
+
+	prometheusSubtreeProcessorDeferredTxs = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreeprocessor",
+			Name:      "deferred_txs",
+			Help:      "Number of transactions depending on unconfirmed parents held back by the confirmed-parents-first selection strategy",
+		},
+	)
 }
@@ -119,6 +119,15 @@ func (m *MockSubtreeProcessor) QueueLength() int64 {
 	return args.Get(0).(int64)
 }
 
+func (m *MockSubtreeProcessor) PrioritiseTxs(hashes []chainhash.Hash) error {
+	args := m.Called(hashes)
+	return args.Error(0)
+}
+
+func (m *MockSubtreeProcessor) RemovePriorityTxs(hashes []chainhash.Hash) {
+	m.Called(hashes)
+}
+
 func (m *MockSubtreeProcessor) SubtreeCount() int {
 	args := m.Called()
 	return args.Int(0)
@@ -0,0 +1,104 @@
+package subtreeprocessor
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/model"
+)
+
+// moveBackBlockSubtrees holds the subtrees of a block, loaded from the subtree store to move the block back
+type moveBackBlockSubtrees struct {
+	subtreesNodes         [][]subtreepkg.Node
+	subtreeMetaTxInpoints [][]subtreepkg.TxInpoints
+	conflictingHashes     []chainhash.Hash
+	err                   error
+}
+
+// loadMoveBackBlockSubtrees loads the subtrees of a block from the subtree store, the error of the load is
+// returned in the result.
+func (stp *SubtreeProcessor) loadMoveBackBlockSubtrees(ctx context.Context, block *model.Block) *moveBackBlockSubtrees {
+	subtreesNodes, subtreeMetaTxInpoints, conflictingHashes, err := stp.moveBackBlockGetSubtrees(ctx, block)
+
+	return &moveBackBlockSubtrees{
+		subtreesNodes:         subtreesNodes,
+		subtreeMetaTxInpoints: subtreeMetaTxInpoints,
+		conflictingHashes:     conflictingHashes,
+		err:                   err,
+	}
+}
+
+// moveBackBlocksPrefetcher loads the subtrees of the blocks being moved back ahead of the block being moved back.
+// Loading the subtrees of a block does not depend on the blocks moved back before it, only adding the transactions
+// back into the subtrees does, so the loading of the following blocks overlaps with moving back the current block.
+type moveBackBlocksPrefetcher struct {
+	results []chan *moveBackBlockSubtrees
+	slots   chan struct{}
+	cancel  context.CancelFunc
+}
+
+// prefetchMoveBackBlocks starts loading the subtrees of the given blocks, in order, keeping at most
+// MoveBackBlockPrefetch blocks loaded ahead of the block being moved back. Nil is returned when prefetching
+// is disabled or there is nothing to prefetch, in which case the subtrees are loaded when moving back a block.
+func (stp *SubtreeProcessor) prefetchMoveBackBlocks(ctx context.Context, blocks []*model.Block) *moveBackBlocksPrefetcher {
+	prefetch := stp.settings.BlockAssembly.MoveBackBlockPrefetch
+	if prefetch <= 0 || len(blocks) < 2 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &moveBackBlocksPrefetcher{
+		results: make([]chan *moveBackBlockSubtrees, len(blocks)),
+		// one slot for the block being moved back, plus the blocks loaded ahead of it
+		slots:  make(chan struct{}, prefetch+1),
+		cancel: cancel,
+	}
+
+	for idx := range blocks {
+		p.results[idx] = make(chan *moveBackBlockSubtrees, 1)
+	}
+
+	go func() {
+		for idx, block := range blocks {
+			select {
+			case p.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			go func() {
+				p.results[idx] <- stp.loadMoveBackBlockSubtrees(ctx, block)
+			}()
+		}
+	}()
+
+	return p
+}
+
+// get returns the loaded subtrees of the block at the given index, waiting for the load to finish. The blocks
+// must be requested in order. Nil is returned for a nil prefetcher.
+func (p *moveBackBlocksPrefetcher) get(ctx context.Context, idx int) (*moveBackBlockSubtrees, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	select {
+	case blockSubtrees := <-p.results[idx]:
+		// free the slot of the block, allowing the next block to be loaded
+		<-p.slots
+
+		return blockSubtrees, nil
+	case <-ctx.Done():
+		return nil, errors.NewContextCanceledError("[moveBackBlocks] context cancelled while waiting for the subtrees of block %d", idx, ctx.Err())
+	}
+}
+
+// stop cancels the loading of the blocks that have not been requested yet.
+func (p *moveBackBlocksPrefetcher) stop() {
+	if p != nil {
+		p.cancel()
+	}
+}
@@ -0,0 +1,195 @@
+package subtreeprocessor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
+	"github.com/bsv-blockchain/teranode/stores/blob"
+	blob_memory "github.com/bsv-blockchain/teranode/stores/blob/memory"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtreeProcessor_moveBackBlocks(t *testing.T) {
+	subtreeStore := blob_memory.New()
+	blocks := createMoveBackTestBlocks(t, subtreeStore, 5, 3, 16)
+
+	utxoStore := newMoveBackTestUtxoStore(t)
+
+	// the reference: the subtrees of each block are loaded when the block is moved back
+	expected := newMoveBackTestProcessor(t, t.Context(), subtreeStore, utxoStore, 0)
+	expectedConflicting, expectedTxs, err := expected.moveBackBlocks(t.Context(), blocks, true)
+	require.NoError(t, err)
+
+	// 5 blocks of 3 subtrees of 16 transactions, minus the coinbase of each block
+	assert.Len(t, expectedTxs, 5*(3*16-1))
+
+	for _, prefetch := range []int{1, 2, 10} {
+		t.Run(fmt.Sprintf("prefetch %d", prefetch), func(t *testing.T) {
+			stp := newMoveBackTestProcessor(t, t.Context(), subtreeStore, utxoStore, prefetch)
+
+			conflicting, txs, err := stp.moveBackBlocks(t.Context(), blocks, true)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedConflicting, conflicting)
+			assert.Equal(t, expectedTxs, txs)
+
+			// the blocks are moved back in the same order, resulting in the same subtrees
+			assert.Equal(t, moveBackTestNodes(expected), moveBackTestNodes(stp))
+			assert.Equal(t, expected.TxCount(), stp.TxCount())
+		})
+	}
+
+	t.Run("missing subtree", func(t *testing.T) {
+		missingSubtreeHash := chainhash.HashH([]byte("missing subtree"))
+
+		brokenBlocks := append([]*model.Block{}, blocks...)
+		brokenBlocks[2] = &model.Block{
+			Header:     brokenBlocks[2].Header,
+			CoinbaseTx: coinbaseTx,
+			Subtrees:   []*chainhash.Hash{&missingSubtreeHash},
+		}
+
+		stp := newMoveBackTestProcessor(t, t.Context(), subtreeStore, utxoStore, 2)
+
+		_, _, err := stp.moveBackBlocks(t.Context(), brokenBlocks, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error getting subtrees")
+	})
+}
+
+func BenchmarkSubtreeProcessor_moveBackBlocks(b *testing.B) {
+	subtreeStore := blob_memory.New()
+	blocks := createMoveBackTestBlocks(b, subtreeStore, 10, 4, 4096)
+
+	utxoStore := newMoveBackTestUtxoStore(b)
+
+	for _, prefetch := range []int{0, 2, 8} {
+		b.Run(fmt.Sprintf("prefetch %d", prefetch), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				stp := newMoveBackTestProcessor(b, ctx, subtreeStore, utxoStore, prefetch)
+
+				b.StartTimer()
+
+				_, _, err := stp.moveBackBlocks(ctx, blocks, false)
+				require.NoError(b, err)
+
+				b.StopTimer()
+				cancel()
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+// createMoveBackTestBlocks stores blockCount blocks of subtreesPerBlock full subtrees of subtreeSize transactions
+// in the subtree store, returning the blocks tip first.
+func createMoveBackTestBlocks(tb testing.TB, subtreeStore blob.Store, blockCount, subtreesPerBlock, subtreeSize int) []*model.Block {
+	blocks := make([]*model.Block, 0, blockCount)
+
+	for blockIdx := 0; blockIdx < blockCount; blockIdx++ {
+		subtreeHashes := make([]*chainhash.Hash, 0, subtreesPerBlock)
+
+		for subtreeIdx := 0; subtreeIdx < subtreesPerBlock; subtreeIdx++ {
+			subtree, err := subtreepkg.NewTreeByLeafCount(subtreeSize)
+			require.NoError(tb, err)
+
+			if subtreeIdx == 0 {
+				require.NoError(tb, subtree.AddCoinbaseNode())
+			}
+
+			for subtree.Length() < subtreeSize {
+				txHash := chainhash.HashH([]byte(fmt.Sprintf("move back %d %d %d", blockIdx, subtreeIdx, subtree.Length())))
+				require.NoError(tb, subtree.AddNode(txHash, 1, 1))
+			}
+
+			subtreeMeta := subtreepkg.NewSubtreeMeta(subtree)
+			parent := chainhash.HashH([]byte("txInpoints"))
+
+			for idx := range subtree.Nodes {
+				_ = subtreeMeta.SetTxInpoints(idx, subtreepkg.TxInpoints{
+					ParentTxHashes: []chainhash.Hash{parent},
+					Idxs:           [][]uint32{{1}},
+				})
+			}
+
+			subtreeBytes, err := subtree.Serialize()
+			require.NoError(tb, err)
+			require.NoError(tb, subtreeStore.Set(context.Background(), subtree.RootHash()[:], fileformat.FileTypeSubtree, subtreeBytes))
+
+			subtreeMetaBytes, err := subtreeMeta.Serialize()
+			require.NoError(tb, err)
+			require.NoError(tb, subtreeStore.Set(context.Background(), subtree.RootHash()[:], fileformat.FileTypeSubtreeMeta, subtreeMetaBytes))
+
+			subtreeHashes = append(subtreeHashes, subtree.RootHash())
+		}
+
+		blocks = append(blocks, &model.Block{
+			Header: &model.BlockHeader{
+				Version:        1,
+				HashPrevBlock:  &chainhash.Hash{},
+				HashMerkleRoot: &chainhash.Hash{},
+				Nonce:          uint32(blockIdx), //nolint:gosec // test block index
+			},
+			CoinbaseTx: coinbaseTx,
+			Subtrees:   subtreeHashes,
+		})
+	}
+
+	return blocks
+}
+
+func newMoveBackTestUtxoStore(tb testing.TB) utxo.Store {
+	utxoStoreURL, err := url.Parse("sqlitememory:///test")
+	require.NoError(tb, err)
+
+	utxoStore, err := sql.New(context.Background(), ulogger.TestLogger{}, test.CreateBaseTestSettings(tb), utxoStoreURL)
+	require.NoError(tb, err)
+
+	return utxoStore
+}
+
+func newMoveBackTestProcessor(tb testing.TB, ctx context.Context, subtreeStore blob.Store, utxoStore utxo.Store, prefetch int) *SubtreeProcessor {
+	tSettings := test.CreateBaseTestSettings(tb)
+	tSettings.BlockAssembly.MoveBackBlockPrefetch = prefetch
+
+	blockchainClient := &blockchain.Mock{}
+	blockchainClient.On("SetBlockProcessedAt", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	stp, err := NewSubtreeProcessor(ctx, ulogger.TestLogger{}, tSettings, subtreeStore, blockchainClient, utxoStore, make(chan NewSubtreeRequest))
+	require.NoError(tb, err)
+
+	return stp
+}
+
+// moveBackTestNodes returns the hashes of all nodes in the subtrees of the processor, in order
+func moveBackTestNodes(stp *SubtreeProcessor) []chainhash.Hash {
+	hashes := make([]chainhash.Hash, 0, stp.TxCount())
+
+	for _, subtree := range stp.chainedSubtrees {
+		for _, node := range subtree.Nodes {
+			hashes = append(hashes, node.Hash)
+		}
+	}
+
+	for _, node := range stp.currentSubtree.Nodes {
+		hashes = append(hashes, node.Hash)
+	}
+
+	return hashes
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	"github.com/bsv-blockchain/go-subtree"
 	"github.com/kpango/fastime"
 )
@@ -63,11 +64,23 @@ func (q *LockFreeQueue) length() int64 {
 // Parameters:
 //   - v: The transaction to add to the queue
 func (q *LockFreeQueue) enqueue(node subtree.Node, txInpoints subtree.TxInpoints) {
+	q.enqueueAt(node, txInpoints, fastime.Now().UnixMilli())
+}
+
+// enqueueAt adds a transaction to the queue with the given queue time, e.g. to keep the time a transaction
+// was first queued at when it is moved between queues.
+//
+// Parameters:
+//   - node: The transaction to add to the queue
+//   - txInpoints: The parent transactions of the transaction
+//   - time: Timestamp in milliseconds the transaction was queued at
+func (q *LockFreeQueue) enqueueAt(node subtree.Node, txInpoints subtree.TxInpoints, time int64) {
 	v := txIDAndFeePool.Get().(*TxIDAndFee)
 
 	v.node = node
 	v.txInpoints = txInpoints
-	v.time = fastime.Now().UnixMilli()
+	v.time = time
+	v.moved = false
 	v.next.Store(nil)
 
 	prev := q.tail.Swap(v)
@@ -93,6 +106,16 @@ func (q *LockFreeQueue) enqueue(node subtree.Node, txInpoints subtree.TxInpoints
 func (q *LockFreeQueue) dequeue(validFromMillis int64) (subtree.Node, subtree.TxInpoints, int64, bool) {
 	next := q.head.next.Load()
 
+	// skip the transactions that were moved to another queue
+	for next != nil && next.moved {
+		oldItem := q.head
+		q.head = next
+
+		txIDAndFeePool.Put(oldItem)
+
+		next = q.head.next.Load()
+	}
+
 	if next == nil {
 		return subtree.Node{}, subtree.TxInpoints{}, 0, false
 	}
@@ -121,3 +144,60 @@ func (q *LockFreeQueue) dequeue(validFromMillis int64) (subtree.Node, subtree.Tx
 func (q *LockFreeQueue) IsEmpty() bool {
 	return q.head.next.Load() == nil
 }
+
+// moveTo moves the queued transactions with the given hashes to the destination queue, keeping the time they
+// were queued at. The transactions are marked as moved and skipped when they are dequeued from this queue.
+// NOTE - This operation is not thread-safe and must be called from the thread that dequeues.
+//
+// Parameters:
+//   - hashes: Hashes of the transactions to move
+//   - dst: Queue to move the transactions to
+//
+// Returns:
+//   - []chainhash.Hash: Hashes of the transactions that were moved
+func (q *LockFreeQueue) moveTo(hashes map[chainhash.Hash]struct{}, dst *LockFreeQueue) []chainhash.Hash {
+	var moved []chainhash.Hash
+
+	for item := q.head.next.Load(); item != nil; item = item.next.Load() {
+		if item.moved {
+			continue
+		}
+
+		if _, ok := hashes[item.node.Hash]; !ok {
+			continue
+		}
+
+		item.moved = true
+		q.queueLength.Add(-1)
+
+		dst.enqueueAt(item.node, item.txInpoints, item.time)
+
+		moved = append(moved, item.node.Hash)
+	}
+
+	return moved
+}
+
+// queuedHashes returns which of the given transaction hashes are in the queue.
+// NOTE - This operation is not thread-safe and must be called from the thread that dequeues.
+//
+// Parameters:
+//   - hashes: Hashes of the transactions to look for
+//
+// Returns:
+//   - map[chainhash.Hash]struct{}: Hashes of the transactions that are in the queue
+func (q *LockFreeQueue) queuedHashes(hashes map[chainhash.Hash]struct{}) map[chainhash.Hash]struct{} {
+	queued := make(map[chainhash.Hash]struct{})
+
+	for item := q.head.next.Load(); item != nil; item = item.next.Load() {
+		if item.moved {
+			continue
+		}
+
+		if _, ok := hashes[item.node.Hash]; ok {
+			queued[item.node.Hash] = struct{}{}
+		}
+	}
+
+	return queued
+}
@@ -0,0 +1,117 @@
+package subtreeprocessor
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+const (
+	// TxSelectionFIFO adds the transactions to the subtrees in the order they are received
+	TxSelectionFIFO = "fifo"
+
+	// TxSelectionConfirmedParentsFirst adds the transactions of which all parents are confirmed ahead of the
+	// transactions depending on unconfirmed parents in block assembly
+	TxSelectionConfirmedParentsFirst = "confirmed-parents-first"
+)
+
+// validateTxSelectionStrategy returns an error when the transaction selection strategy is not known
+func validateTxSelectionStrategy(strategy string) error {
+	switch strategy {
+	case "", TxSelectionFIFO, TxSelectionConfirmedParentsFirst:
+		return nil
+	default:
+		return errors.NewInvalidArgumentError("unknown transaction selection strategy %q, expected %q or %q",
+			strategy, TxSelectionFIFO, TxSelectionConfirmedParentsFirst)
+	}
+}
+
+// deferredTx is a transaction held back by the confirmed-parents-first selection strategy
+type deferredTx struct {
+	node       subtreepkg.Node
+	txInpoints subtreepkg.TxInpoints
+}
+
+// deferTx holds back the transaction when the confirmed-parents-first selection strategy is used and the transaction
+// spends a parent that is not confirmed yet, i.e. a parent in the subtrees of block assembly or held back itself.
+// Held back transactions keep their order, so a parent is always added before its children.
+//
+// Returns:
+//   - bool: Whether the transaction was held back
+func (stp *SubtreeProcessor) deferTx(node subtreepkg.Node, txInpoints subtreepkg.TxInpoints) bool {
+	if !stp.preferConfirmedParents || !stp.hasUnconfirmedParent(&txInpoints) {
+		return false
+	}
+
+	stp.deferredTxs = append(stp.deferredTxs, deferredTx{node: node, txInpoints: txInpoints})
+	stp.deferredTxHashes[node.Hash] = struct{}{}
+
+	prometheusSubtreeProcessorDeferredTxs.Inc()
+
+	return true
+}
+
+// hasUnconfirmedParent returns whether any parent of the transaction is in the subtrees of block assembly, or is held
+// back itself
+func (stp *SubtreeProcessor) hasUnconfirmedParent(txInpoints *subtreepkg.TxInpoints) bool {
+	for _, parentHash := range txInpoints.ParentTxHashes {
+		if _, ok := stp.currentTxMap.Get(parentHash); ok {
+			return true
+		}
+
+		if _, ok := stp.deferredTxHashes[parentHash]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addDeferredTxs adds the held back transactions to the subtrees, in the order they were held back. It is called once
+// the queue holds no more transactions to add, and before block assembly moves to another block, so no transaction
+// is held back across a change of the chain.
+//
+// Returns:
+//   - uint64: The number of transactions added
+func (stp *SubtreeProcessor) addDeferredTxs() uint64 {
+	if len(stp.deferredTxs) == 0 {
+		return 0
+	}
+
+	nrAdded := uint64(0)
+
+	for _, tx := range stp.deferredTxs {
+		if stp.removeMap.Length() > 0 && stp.removeMap.Exists(tx.node.Hash) {
+			if err := stp.removeMap.Delete(tx.node.Hash); err != nil {
+				stp.logger.Errorf("[SubtreeProcessor] error removing tx from remove map: %s", err.Error())
+			}
+
+			continue
+		}
+
+		if _, ok := stp.currentTxMap.Get(tx.node.Hash); ok {
+			continue
+		}
+
+		if err := stp.addNode(tx.node, &tx.txInpoints, false); err != nil {
+			stp.logger.Errorf("[SubtreeProcessor] error adding deferred node: %s", err.Error())
+			continue
+		}
+
+		nrAdded++
+	}
+
+	clear(stp.deferredTxs)
+	stp.deferredTxs = stp.deferredTxs[:0]
+	clear(stp.deferredTxHashes)
+
+	stp.txCount.Add(nrAdded)
+
+	return nrAdded
+}
+
+// isDeferred returns whether the transaction is held back by the confirmed-parents-first selection strategy
+func (stp *SubtreeProcessor) isDeferred(hash chainhash.Hash) bool {
+	_, ok := stp.deferredTxHashes[hash]
+	return ok
+}
@@ -0,0 +1,119 @@
+package subtreeprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	txmap "github.com/bsv-blockchain/go-tx-map"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtreeProcessor_TxSelectionStrategy(t *testing.T) {
+	initPrometheusMetrics()
+
+	// newSelectionTestProcessor creates a subtree processor without its processing goroutine, so the queue is only
+	// dequeued by the test
+	newSelectionTestProcessor := func(t *testing.T, strategy string) *SubtreeProcessor {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.BlockAssembly.TxSelectionStrategy = strategy
+
+		subtree, err := subtreepkg.NewTreeByLeafCount(128)
+		require.NoError(t, err)
+		require.NoError(t, subtree.AddCoinbaseNode())
+
+		return &SubtreeProcessor{
+			settings:               tSettings,
+			logger:                 ulogger.TestLogger{},
+			currentItemsPerFile:    128,
+			currentSubtree:         subtree,
+			queue:                  NewLockFreeQueue(),
+			currentTxMap:           txmap.NewSyncedMap[chainhash.Hash, subtreepkg.TxInpoints](),
+			removeMap:              txmap.NewSwissMap(0),
+			preferConfirmedParents: strategy == TxSelectionConfirmedParentsFirst,
+			deferredTxHashes:       make(map[chainhash.Hash]struct{}),
+		}
+	}
+
+	confirmedParent := chainhash.HashH([]byte("confirmed-parent"))
+	unconfirmedParent := chainhash.HashH([]byte("unconfirmed-parent"))
+	child := chainhash.HashH([]byte("child"))
+	grandchild := chainhash.HashH([]byte("grandchild"))
+	independent1 := chainhash.HashH([]byte("independent-1"))
+	independent2 := chainhash.HashH([]byte("independent-2"))
+
+	// assemble adds the unconfirmed parent to the subtrees, then queues its chain of descendants ahead of two
+	// transactions spending a confirmed parent, and returns the order of the transactions in the subtree
+	assemble := func(t *testing.T, stp *SubtreeProcessor) []chainhash.Hash {
+		require.NoError(t, stp.addNode(subtreepkg.Node{Hash: unconfirmedParent, Fee: 1, SizeInBytes: 250},
+			&subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{confirmedParent}}, true))
+
+		stp.queue.enqueue(subtreepkg.Node{Hash: child, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{unconfirmedParent}})
+		stp.queue.enqueue(subtreepkg.Node{Hash: grandchild, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{child}})
+		stp.queue.enqueue(subtreepkg.Node{Hash: independent1, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{confirmedParent}})
+		stp.queue.enqueue(subtreepkg.Node{Hash: independent2, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{confirmedParent}})
+
+		drained := stp.dequeueTxs(time.Now().Add(time.Second).UnixMilli())
+		require.True(t, drained)
+
+		hashes := make([]chainhash.Hash, 0, len(stp.currentSubtree.Nodes))
+		for _, node := range stp.currentSubtree.Nodes[1:] {
+			hashes = append(hashes, node.Hash)
+		}
+
+		return hashes
+	}
+
+	t.Run("fifo", func(t *testing.T) {
+		stp := newSelectionTestProcessor(t, TxSelectionFIFO)
+
+		assert.Equal(t, []chainhash.Hash{unconfirmedParent, child, grandchild, independent1, independent2}, assemble(t, stp))
+	})
+
+	t.Run("confirmed parents first", func(t *testing.T) {
+		stp := newSelectionTestProcessor(t, TxSelectionConfirmedParentsFirst)
+
+		// the chain depending on the unconfirmed parent follows the transactions spending confirmed parents, in order
+		assert.Equal(t, []chainhash.Hash{unconfirmedParent, independent1, independent2, child, grandchild}, assemble(t, stp))
+		assert.Empty(t, stp.deferredTxs)
+		assert.Empty(t, stp.deferredTxHashes)
+		assert.Equal(t, uint64(4), stp.txCount.Load())
+	})
+
+	t.Run("held back until the queue is drained", func(t *testing.T) {
+		stp := newSelectionTestProcessor(t, TxSelectionConfirmedParentsFirst)
+		stp.settings.BlockAssembly.SubtreeProcessorBatcherSize = 1
+
+		require.NoError(t, stp.addNode(subtreepkg.Node{Hash: unconfirmedParent, Fee: 1, SizeInBytes: 250}, &subtreepkg.TxInpoints{}, true))
+
+		stp.queue.enqueue(subtreepkg.Node{Hash: child, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{ParentTxHashes: []chainhash.Hash{unconfirmedParent}})
+		stp.queue.enqueue(subtreepkg.Node{Hash: independent1, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+		stp.queue.enqueue(subtreepkg.Node{Hash: independent2, Fee: 1, SizeInBytes: 250}, subtreepkg.TxInpoints{})
+
+		validFromMillis := time.Now().Add(time.Second).UnixMilli()
+
+		// the batch ends before the queue is drained, the child is still held back
+		require.False(t, stp.dequeueTxs(validFromMillis))
+		assert.Len(t, stp.deferredTxs, 1)
+		assert.Equal(t, 3, stp.currentSubtree.Length())
+
+		require.True(t, stp.dequeueTxs(validFromMillis))
+		assert.Empty(t, stp.deferredTxs)
+		assert.Equal(t, child, stp.currentSubtree.Nodes[4].Hash)
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.BlockAssembly.TxSelectionStrategy = "highest-fee"
+
+		_, err := NewSubtreeProcessor(context.Background(), ulogger.TestLogger{}, tSettings, nil, nil, nil, make(chan NewSubtreeRequest, 10))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrInvalidArgument))
+	})
+}
@@ -16,6 +16,7 @@ type TxIDAndFee struct {
 	node       subtree.Node               // The transaction node containing hash and fee information
 	txInpoints subtree.TxInpoints         // Slice of parent transaction hashes and their indices
 	time       int64                      // Timestamp of when the transaction was added
+	moved      bool                       // Whether the transaction was moved to another queue and must be skipped
 	next       atomic.Pointer[TxIDAndFee] // Pointer to the next transaction in the queue
 }
 
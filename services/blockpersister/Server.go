@@ -450,6 +450,12 @@ func (u *Server) Start(ctx context.Context, readyCh chan<- struct{}) error {
 					}
 				}
 
+				// Pruning failures are not fatal, the block that could not be pruned keeps its data
+				if err := u.pruneBlockData(ctx, block.Height); err != nil {
+					u.logger.Warnf("[BlockPersister] Failed to prune block data after block %s at height %d: %v",
+						block.Hash().String(), block.Height, err)
+				}
+
 				u.logger.Infof("Successfully processed block %s", block.Hash())
 			}
 		}
@@ -58,6 +58,10 @@ var (
 	// prometheusBlockPersisterSubtreeBatch measures the time taken to process a batch of subtrees
 	// in the block persister service, in milliseconds, helping optimize batch size configurations.
 	prometheusBlockPersisterSubtreeBatch prometheus.Histogram
+
+	// prometheusBlockPersisterPrunedBlocks counts the blocks whose full block data was deleted
+	// because they dropped out of the retention window of a pruned node.
+	prometheusBlockPersisterPrunedBlocks prometheus.Counter
 )
 
 var (
@@ -186,4 +190,13 @@ func _initPrometheusMetrics() {
 			Buckets:   util.MetricsBucketsMilliSeconds,
 		},
 	)
+
+	prometheusBlockPersisterPrunedBlocks = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "blockpersister",
+			Name:      "pruned_blocks",
+			Help:      "Number of blocks whose full block data was pruned",
+		},
+	)
 }
@@ -0,0 +1,78 @@
+package blockpersister
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/stores/blob"
+)
+
+// prunedSubtreeFileTypes are the subtree files deleted for a pruned block.
+var prunedSubtreeFileTypes = []fileformat.FileType{
+	fileformat.FileTypeSubtree,
+	fileformat.FileTypeSubtreeData,
+	fileformat.FileTypeSubtreeMeta,
+}
+
+// pruneBlockData deletes the full block data of the block that dropped out of the retention window
+// after the block at persistedHeight was persisted.
+//
+// Pruning is enabled when BlockPersisterPruneDepth is greater than 0. The block file and the subtree
+// files of the block at persistedHeight - BlockPersisterPruneDepth are deleted. The block header stays
+// in the blockchain store and the UTXO set is not touched, so the node keeps validating new blocks.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - persistedHeight: Height of the block that was just persisted
+//
+// Returns an error if the pruned block could not be retrieved or its data could not be deleted.
+func (u *Server) pruneBlockData(ctx context.Context, persistedHeight uint32) error {
+	pruneDepth := u.settings.Block.BlockPersisterPruneDepth
+	if pruneDepth == 0 || persistedHeight <= pruneDepth {
+		return nil
+	}
+
+	pruneHeight := persistedHeight - pruneDepth
+
+	block, err := u.blockchainClient.GetBlockByHeight(ctx, pruneHeight)
+	if err != nil {
+		return errors.NewProcessingError("[BlockPersister] error getting block at height %d to prune", pruneHeight, err)
+	}
+
+	if err = deleteBlob(ctx, u.blockStore, block.Hash()[:], fileformat.FileTypeBlock); err != nil {
+		return errors.NewStorageError("[BlockPersister] error pruning block %s at height %d", block.Hash(), pruneHeight, err)
+	}
+
+	for _, subtreeHash := range block.Subtrees {
+		for _, fileType := range prunedSubtreeFileTypes {
+			if err = deleteBlob(ctx, u.subtreeStore, subtreeHash[:], fileType); err != nil {
+				return errors.NewStorageError("[BlockPersister] error pruning %s %s of block %s", fileType, subtreeHash, block.Hash(), err)
+			}
+		}
+	}
+
+	prometheusBlockPersisterPrunedBlocks.Inc()
+
+	u.logger.Infof("[BlockPersister] pruned block %s at height %d", block.Hash(), pruneHeight)
+
+	return nil
+}
+
+// deleteBlob deletes a blob from the store, a blob that does not exist is not an error.
+func deleteBlob(ctx context.Context, store blob.Store, key []byte, fileType fileformat.FileType) error {
+	exists, err := store.Exists(ctx, key, fileType)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return nil
+	}
+
+	if err = store.Del(ctx, key, fileType); err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
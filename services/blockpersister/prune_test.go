@@ -0,0 +1,125 @@
+package blockpersister
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
+	"github.com/bsv-blockchain/teranode/stores/blob/memory"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPruneTestBlock(height uint32, nonce uint32) *model.Block {
+	return &model.Block{
+		Header: &model.BlockHeader{
+			Version:        1,
+			HashPrevBlock:  &chainhash.Hash{},
+			HashMerkleRoot: &chainhash.Hash{},
+			Nonce:          nonce,
+		},
+		Height: height,
+		Subtrees: []*chainhash.Hash{
+			{byte(nonce), 1},
+			{byte(nonce), 2},
+		},
+	}
+}
+
+func storePruneTestBlock(t *testing.T, ctx context.Context, blockStore, subtreeStore *memory.Memory, block *model.Block) {
+	require.NoError(t, blockStore.Set(ctx, block.Hash()[:], fileformat.FileTypeBlock, []byte("block")))
+
+	for _, subtreeHash := range block.Subtrees {
+		require.NoError(t, subtreeStore.Set(ctx, subtreeHash[:], fileformat.FileTypeSubtree, []byte("subtree")))
+		require.NoError(t, subtreeStore.Set(ctx, subtreeHash[:], fileformat.FileTypeSubtreeData, []byte("subtreeData")))
+	}
+}
+
+func requirePruneTestBlockStored(t *testing.T, ctx context.Context, blockStore, subtreeStore *memory.Memory, block *model.Block, stored bool) {
+	exists, err := blockStore.Exists(ctx, block.Hash()[:], fileformat.FileTypeBlock)
+	require.NoError(t, err)
+	require.Equal(t, stored, exists)
+
+	for _, subtreeHash := range block.Subtrees {
+		for _, fileType := range []fileformat.FileType{fileformat.FileTypeSubtree, fileformat.FileTypeSubtreeData} {
+			exists, err = subtreeStore.Exists(ctx, subtreeHash[:], fileType)
+			require.NoError(t, err)
+			require.Equal(t, stored, exists)
+		}
+	}
+}
+
+func TestPruneBlockData(t *testing.T) {
+	initPrometheusMetrics()
+
+	ctx := context.Background()
+
+	t.Run("block beyond the prune depth is removed and its header remains", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Block.StateFile = t.TempDir() + "/blocks.dat"
+		tSettings.Block.BlockPersisterPruneDepth = 10
+
+		blockStore := memory.New()
+		subtreeStore := memory.New()
+
+		prunedBlock := newPruneTestBlock(90, 1)
+		retainedBlock := newPruneTestBlock(91, 2)
+
+		storePruneTestBlock(t, ctx, blockStore, subtreeStore, prunedBlock)
+		storePruneTestBlock(t, ctx, blockStore, subtreeStore, retainedBlock)
+
+		mockClient := &blockchain.Mock{}
+		mockClient.On("GetBlockByHeight", mock.Anything, uint32(90)).Return(prunedBlock, nil)
+
+		server := New(ctx, ulogger.TestLogger{}, tSettings, blockStore, subtreeStore, nil, mockClient)
+
+		require.NoError(t, server.pruneBlockData(ctx, 100))
+
+		requirePruneTestBlockStored(t, ctx, blockStore, subtreeStore, prunedBlock, false)
+		requirePruneTestBlockStored(t, ctx, blockStore, subtreeStore, retainedBlock, true)
+
+		// the blockchain store is only read, so the header of the pruned block remains,
+		// any other call on the mock would fail the test
+		mockClient.AssertExpectations(t)
+		mockClient.AssertNumberOfCalls(t, "GetBlockByHeight", 1)
+	})
+
+	t.Run("pruning disabled", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Block.StateFile = t.TempDir() + "/blocks.dat"
+		tSettings.Block.BlockPersisterPruneDepth = 0
+
+		blockStore := memory.New()
+		subtreeStore := memory.New()
+
+		block := newPruneTestBlock(90, 1)
+		storePruneTestBlock(t, ctx, blockStore, subtreeStore, block)
+
+		mockClient := &blockchain.Mock{}
+
+		server := New(ctx, ulogger.TestLogger{}, tSettings, blockStore, subtreeStore, nil, mockClient)
+
+		require.NoError(t, server.pruneBlockData(ctx, 1000))
+
+		requirePruneTestBlockStored(t, ctx, blockStore, subtreeStore, block, true)
+		mockClient.AssertNotCalled(t, "GetBlockByHeight", mock.Anything, mock.Anything)
+	})
+
+	t.Run("chain shorter than the prune depth", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Block.StateFile = t.TempDir() + "/blocks.dat"
+		tSettings.Block.BlockPersisterPruneDepth = 10
+
+		mockClient := &blockchain.Mock{}
+
+		server := New(ctx, ulogger.TestLogger{}, tSettings, memory.New(), memory.New(), nil, mockClient)
+
+		require.NoError(t, server.pruneBlockData(ctx, 10))
+		mockClient.AssertNotCalled(t, "GetBlockByHeight", mock.Anything, mock.Anything)
+	})
+}
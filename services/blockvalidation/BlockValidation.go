@@ -43,6 +43,7 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/failurestream"
 	"github.com/bsv-blockchain/teranode/util/kafka"
 	kafkamessage "github.com/bsv-blockchain/teranode/util/kafka/kafka_message"
 	"github.com/bsv-blockchain/teranode/util/retry"
@@ -152,6 +153,13 @@ type BlockValidation struct {
 	// subtreeExistsCache tracks validated subtree hashes for 10 minutes
 	subtreeExistsCache *expiringmap.ExpiringMap[chainhash.Hash, bool]
 
+	// validationResultCache caches the outcome of recent block validations, so duplicate blocks
+	// received from multiple peers are not validated again, nil when disabled
+	validationResultCache *expiringmap.ExpiringMap[chainhash.Hash, blockValidationResult]
+
+	// lastBestBlockHash is the best block hash last seen, used to detect reorgs that invalidate the validationResultCache
+	lastBestBlockHash atomic.Pointer[chainhash.Hash]
+
 	// subtreeCount tracks the number of subtrees being processed
 	subtreeCount atomic.Int32
 
@@ -179,6 +187,9 @@ type BlockValidation struct {
 	// invalidBlockKafkaProducer publishes invalid block events to Kafka
 	invalidBlockKafkaProducer kafka.KafkaAsyncProducerI
 
+	// failureEmitter emits the invalid blocks to the validation failure stream
+	failureEmitter failurestream.Emitter
+
 	// backgroundTasks tracks background goroutines to ensure proper shutdown
 	backgroundTasks sync.WaitGroup
 }
@@ -224,6 +235,11 @@ func NewBlockValidation(ctx context.Context, logger ulogger.Logger, tSettings *s
 		logger.Infof("No Kafka topic configured for invalid blocks, using interface handler only")
 	}
 
+	failureEmitter, err := failurestream.New(ctx, logger, tSettings, "blockvalidation")
+	if err != nil {
+		logger.Errorf("Failed to create validation failure stream: %v", err)
+	}
+
 	bv := &BlockValidation{
 		logger:                        logger,
 		settings:                      tSettings,
@@ -240,6 +256,7 @@ func NewBlockValidation(ctx context.Context, logger ulogger.Logger, tSettings *s
 		lastValidatedBlocks:           expiringmap.New[chainhash.Hash, *model.Block](2 * time.Minute),
 		blockExistsCache:              expiringmap.New[chainhash.Hash, bool](120 * time.Minute), // we keep this for 2 hours
 		invalidBlockKafkaProducer:     invalidBlockKafkaProducer,
+		failureEmitter:                failureEmitter,
 		subtreeExistsCache:            expiringmap.New[chainhash.Hash, bool](10 * time.Minute), // we keep this for 10 minutes
 		subtreeCount:                  atomic.Int32{},
 		blockHashesCurrentlyValidated: txmap.NewSwissMap(0),
@@ -251,6 +268,10 @@ func NewBlockValidation(ctx context.Context, logger ulogger.Logger, tSettings *s
 		stats:                         gocore.NewStat("blockvalidation"),
 	}
 
+	if tSettings.BlockValidation.ValidationResultCacheTTL > 0 {
+		bv.validationResultCache = expiringmap.New[chainhash.Hash, blockValidationResult](tSettings.BlockValidation.ValidationResultCacheTTL)
+	}
+
 	go func() {
 		// update stats for the expiring maps every 5 seconds
 		ticker := time.NewTicker(5 * time.Second)
@@ -264,6 +285,10 @@ func NewBlockValidation(ctx context.Context, logger ulogger.Logger, tSettings *s
 				prometheusBlockValidationLastValidatedBlocksCache.Set(float64(bv.lastValidatedBlocks.Len()))
 				prometheusBlockValidationBlockExistsCache.Set(float64(bv.blockExistsCache.Len()))
 				prometheusBlockValidationSubtreeExistsCache.Set(float64(bv.subtreeExistsCache.Len()))
+
+				if bv.validationResultCache != nil {
+					prometheusBlockValidationResultCache.Set(float64(bv.validationResultCache.Len()))
+				}
 			}
 		}
 	}()
@@ -330,6 +355,8 @@ func NewBlockValidation(ctx context.Context, logger ulogger.Logger, tSettings *s
 								bv.logger.Infof("[BlockValidation:setMined] received BlockSubtreesSet notification: %s", cHash.String())
 								// push block hash to the setMinedChan
 								bv.setMinedChan <- &cHash
+
+								bv.clearValidationResultsOnReorg(ctx)
 							}
 						}
 					}
@@ -878,7 +905,7 @@ func (u *BlockValidation) setTxMinedStatus(ctx context.Context, blockHash *chain
 		// check whether we got already mined errors and mark the block as invalid
 		if errors.Is(err, errors.ErrBlockInvalid) {
 			// mark the block as invalid in the blockchain
-			return u.markBlockAsInvalid(ctx, block, "contains transactions already on our chain: "+err.Error())
+			return u.markBlockAsInvalid(ctx, block, failureStageSetMined, "contains transactions already on our chain: "+err.Error())
 		}
 
 		return errors.NewProcessingError("[setTxMined][%s] error updating tx mined status", block.Hash().String(), err)
@@ -995,6 +1022,33 @@ func (u *BlockValidation) ValidateBlock(ctx context.Context, block *model.Block,
 	return u.ValidateBlockWithOptions(ctx, block, baseURL, bloomStats, opts)
 }
 
+// checkBlockSizeLimits checks the block against the excessive block size and, when the limits are
+// configured, the excessive block weight and the excessive number of transactions. A limit of 0 is not checked.
+func (u *BlockValidation) checkBlockSizeLimits(block *model.Block) error {
+	if u.settings.Policy.ExcessiveBlockSize > 0 {
+		excessiveBlockSizeUint64, err := safeconversion.IntToUint64(u.settings.Policy.ExcessiveBlockSize)
+		if err != nil {
+			return err
+		}
+
+		if block.SizeInBytes > excessiveBlockSizeUint64 {
+			return errors.NewBlockInvalidError("[ValidateBlock][%s] block size %d exceeds excessiveblocksize %d", block.Header.Hash().String(), block.SizeInBytes, u.settings.Policy.ExcessiveBlockSize)
+		}
+	}
+
+	if u.settings.Policy.ExcessiveBlockWeight > 0 {
+		if weight := model.BlockWeight(block.SizeInBytes); weight > u.settings.Policy.ExcessiveBlockWeight {
+			return errors.NewBlockInvalidError("[ValidateBlock][%s] block weight %d exceeds excessiveblockweight %d", block.Header.Hash().String(), weight, u.settings.Policy.ExcessiveBlockWeight)
+		}
+	}
+
+	if u.settings.Policy.ExcessiveTxPerBlock > 0 && block.TransactionCount > u.settings.Policy.ExcessiveTxPerBlock {
+		return errors.NewBlockInvalidError("[ValidateBlock][%s] block transaction count %d exceeds excessivetxperblock %d", block.Header.Hash().String(), block.TransactionCount, u.settings.Policy.ExcessiveTxPerBlock)
+	}
+
+	return nil
+}
+
 // ValidateBlockWithOptions performs comprehensive validation of a Bitcoin block with additional options.
 // This method provides the same functionality as ValidateBlock but allows for performance optimizations
 // during catchup operations by accepting cached data.
@@ -1018,9 +1072,20 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 	)
 	defer deferFn()
 
-	// Use helper to ensure block is validated only once
 	blockHash := block.Hash()
-	return u.runOncePerBlock(blockHash, opts, func(opts *ValidateBlockOptions) error {
+
+	// Return the outcome of a recent validation of the same block, e.g. when received from multiple peers
+	if !opts.IsRevalidation {
+		if result, ok := u.getCachedValidationResult(blockHash); ok {
+			u.logger.Debugf("[ValidateBlock][%s] returning cached validation result", blockHash.String())
+			prometheusBlockValidationResultCacheHits.Inc()
+
+			return result.err
+		}
+	}
+
+	// Use helper to ensure block is validated only once
+	err := u.runOncePerBlock(blockHash, opts, func(opts *ValidateBlockOptions) error {
 		var err error
 
 		// Check if block already exists to prevent duplicate validation (unless revalidating)
@@ -1061,17 +1126,9 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 			u.logger.Infof("[ValidateBlock][%s] revalidating invalid block", block.Header.Hash().String())
 		}
 
-		// check the size of the block
-		// 0 is unlimited so don't check the size
-		if u.settings.Policy.ExcessiveBlockSize > 0 {
-			excessiveBlockSizeUint64, err := safeconversion.IntToUint64(u.settings.Policy.ExcessiveBlockSize)
-			if err != nil {
-				return err
-			}
-
-			if block.SizeInBytes > excessiveBlockSizeUint64 {
-				return errors.NewBlockInvalidError("[ValidateBlock][%s] block size %d exceeds excessiveblocksize %d", block.Header.Hash().String(), block.SizeInBytes, u.settings.Policy.ExcessiveBlockSize)
-			}
+		// check the size and weight of the block
+		if err = u.checkBlockSizeLimits(block); err != nil {
+			return err
 		}
 
 		if block.CoinbaseTx == nil || block.CoinbaseTx.Inputs == nil || len(block.CoinbaseTx.Inputs) == 0 {
@@ -1167,7 +1224,7 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 			if errors.Is(err, errors.ErrTxInvalid) || errors.Is(err, errors.ErrTxMissingParent) || errors.Is(err, errors.ErrTxNotFound) {
 				u.logger.Warnf("[ValidateBlock][%s] block contains invalid transactions, marking as invalid: %s", block.Hash().String(), err)
 				reason := fmt.Sprintf("block contains invalid transactions: %s", err.Error())
-				u.storeInvalidBlock(ctx, block, baseURL, reason)
+				u.storeInvalidBlock(ctx, block, baseURL, failureStageSubtrees, reason)
 				return errors.NewBlockInvalidError("[ValidateBlock][%s] block contains invalid transactions: %s", block.Hash().String(), err)
 			}
 
@@ -1203,22 +1260,24 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 			// Compare the block's nBits with the expected nBits
 			if expectedNBits != nil && block.Header.Bits != *expectedNBits {
 				reason := fmt.Sprintf("incorrect difficulty bits: got %v, expected %v", block.Header.Bits, *expectedNBits)
-				u.storeInvalidBlock(ctx, block, baseURL, reason)
+				u.storeInvalidBlock(ctx, block, baseURL, failureStageDifficulty, reason)
 
 				return errors.NewBlockInvalidError("[ValidateBlock][%s] block has incorrect difficulty bits: got %v, expected %v",
 					block.Header.Hash().String(), block.Header.Bits, expectedNBits)
 			}
 
-			// Then check that the block hash meets the difficulty target
-			headerValid, _, err := block.Header.HasMetTargetDifficulty()
-			if !headerValid {
-				reason := "block does not meet target difficulty"
-				if err != nil {
-					reason = fmt.Sprintf("block does not meet target difficulty: %s", err.Error())
-				}
-				u.storeInvalidBlock(ctx, block, baseURL, reason)
+			// Then check that the block hash meets the difficulty target, unless the network does not use proof of work
+			if u.settings.ProofOfWorkCheckRequired() {
+				headerValid, _, err := block.Header.HasMetTargetDifficulty()
+				if !headerValid {
+					reason := "block does not meet target difficulty"
+					if err != nil {
+						reason = fmt.Sprintf("block does not meet target difficulty: %s", err.Error())
+					}
+					u.storeInvalidBlock(ctx, block, baseURL, failureStageProofOfWork, reason)
 
-				return errors.NewBlockInvalidError("[ValidateBlock][%s] block does not meet target difficulty: %s", block.Header.Hash().String(), err)
+					return errors.NewBlockInvalidError("[ValidateBlock][%s] block does not meet target difficulty: %s", block.Header.Hash().String(), err)
+				}
 			}
 		}
 
@@ -1279,7 +1338,7 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 
 					if errors.Is(err, errors.ErrBlockInvalid) {
 						reason := p2pconstants.ReasonInvalidBlock.String()
-						if err = u.markBlockAsInvalid(decoupledCtx, block, reason); err != nil {
+						if err = u.markBlockAsInvalid(decoupledCtx, block, failureStageBlock, reason); err != nil {
 							u.logger.Errorf("[ValidateBlock][%s][InvalidateBlock] failed to invalidate block: %v", block.String(), err)
 							// we should try again to re-validate the block, as we failed to mark it as invalid
 							u.ReValidateBlock(block, baseURL)
@@ -1297,6 +1356,10 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 					u.logger.Errorf("[ValidateBlock][%s] failed to check old block IDs: %s", block.String(), err)
 
 					if errors.Is(err, errors.ErrBlockInvalid) {
+						if u.validationResultCache != nil {
+							u.validationResultCache.Delete(*block.Hash())
+						}
+
 						if _, invalidateBlockErr := u.blockchainClient.InvalidateBlock(decoupledCtx, block.Header.Hash()); invalidateBlockErr != nil {
 							u.logger.Errorf("[ValidateBlock][%s][InvalidateBlock] failed to invalidate block: %v", block.String(), invalidateBlockErr)
 						}
@@ -1351,7 +1414,7 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 					reason = err.Error()
 				}
 
-				u.storeInvalidBlock(ctx, block, baseURL, reason)
+				u.storeInvalidBlock(ctx, block, baseURL, failureStageBlock, reason)
 
 				return errors.NewBlockInvalidError("[ValidateBlock][%s] block is not valid", block.String(), err)
 			}
@@ -1359,7 +1422,7 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 			if iterationError := u.checkOldBlockIDs(ctx, oldBlockIDsMap, block); iterationError != nil {
 				if errors.Is(iterationError, errors.ErrBlockInvalid) {
 					reason := iterationError.Error()
-					u.storeInvalidBlock(ctx, block, baseURL, reason)
+					u.storeInvalidBlock(ctx, block, baseURL, failureStageOldBlockIDs, reason)
 				}
 
 				return iterationError
@@ -1445,14 +1508,82 @@ func (u *BlockValidation) ValidateBlockWithOptions(ctx context.Context, block *m
 
 		return nil
 	})
+
+	if !opts.IsRevalidation {
+		u.cacheValidationResult(blockHash, err)
+	}
+
+	return err
+}
+
+// blockValidationResult is the cached outcome of the validation of a block.
+type blockValidationResult struct {
+	err error
 }
 
-func (u *BlockValidation) markBlockAsInvalid(ctx context.Context, block *model.Block, reason string) error {
+// getCachedValidationResult returns the cached outcome of a recent validation of the given block.
+func (u *BlockValidation) getCachedValidationResult(blockHash *chainhash.Hash) (blockValidationResult, bool) {
+	if u.validationResultCache == nil {
+		return blockValidationResult{}, false
+	}
+
+	return u.validationResultCache.Get(*blockHash)
+}
+
+// cacheValidationResult caches the outcome of the validation of the given block. Only definitive
+// outcomes are cached, the block being valid or invalid. Other errors, like storage or network
+// errors, might not occur when the block is validated again and are not cached.
+func (u *BlockValidation) cacheValidationResult(blockHash *chainhash.Hash, err error) {
+	if u.validationResultCache == nil {
+		return
+	}
+
+	if err != nil && !errors.Is(err, errors.ErrBlockInvalid) {
+		return
+	}
+
+	u.validationResultCache.Set(*blockHash, blockValidationResult{err: err})
+}
+
+// clearValidationResultsOnReorg clears the validation result cache when the best block does not
+// extend the best block that was last seen. The outcome of a validation depends on the chain the
+// block was validated against, which changes on a reorg.
+func (u *BlockValidation) clearValidationResultsOnReorg(ctx context.Context) {
+	if u.validationResultCache == nil {
+		return
+	}
+
+	bestBlockHeader, _, err := u.blockchainClient.GetBestBlockHeader(ctx)
+	if err != nil {
+		u.logger.Warnf("[clearValidationResultsOnReorg] failed to get best block header, clearing validation result cache: %v", err)
+		u.validationResultCache.Clear()
+
+		return
+	}
+
+	bestBlockHash := bestBlockHeader.Hash()
+
+	previousBestBlockHash := u.lastBestBlockHash.Swap(bestBlockHash)
+	if previousBestBlockHash == nil || previousBestBlockHash.IsEqual(bestBlockHash) || previousBestBlockHash.IsEqual(bestBlockHeader.HashPrevBlock) {
+		return
+	}
+
+	u.logger.Infof("[clearValidationResultsOnReorg] best block changed from %s to %s, clearing validation result cache", previousBestBlockHash.String(), bestBlockHash.String())
+	u.validationResultCache.Clear()
+}
+
+func (u *BlockValidation) markBlockAsInvalid(ctx context.Context, block *model.Block, stage string, reason string) error {
 	// Log the invalidation event - this is the key entry point for automatic invalidation
 	u.logger.Warnf("[ValidateBlock] Marking block %s as invalid - Reason: %s", block.Hash().String(), reason)
 
+	// the block may have been cached as valid after optimistic mining
+	if u.validationResultCache != nil {
+		u.validationResultCache.Delete(*block.Hash())
+	}
+
 	// Only use Kafka for reporting invalid blocks
 	u.kafkaNotifyBlockInvalid(block, reason)
+	u.emitBlockFailure(block, stage, reason)
 
 	if _, invalidateBlockErr := u.blockchainClient.InvalidateBlock(ctx, block.Header.Hash()); invalidateBlockErr != nil {
 		return errors.NewProcessingError("[ValidateBlock][%s] Failed to invalidate block: %v", block.String(), invalidateBlockErr)
@@ -1463,7 +1594,7 @@ func (u *BlockValidation) markBlockAsInvalid(ctx context.Context, block *model.B
 
 // storeInvalidBlock stores a block marked as invalid in the blockchain database.
 // This helper function centralizes the logic for persisting invalid blocks and updating caches.
-func (u *BlockValidation) storeInvalidBlock(ctx context.Context, block *model.Block, baseURL string, reason string) {
+func (u *BlockValidation) storeInvalidBlock(ctx context.Context, block *model.Block, baseURL string, stage string, reason string) {
 	u.logger.Warnf("[ValidateBlock][%s] storing block as invalid: %s", block.Hash().String(), reason)
 
 	// Store the block marked as invalid so we have a record of it
@@ -1477,6 +1608,7 @@ func (u *BlockValidation) storeInvalidBlock(ctx context.Context, block *model.Bl
 	}
 
 	u.kafkaNotifyBlockInvalid(block, reason)
+	u.emitBlockFailure(block, stage, reason)
 }
 
 // checkParentInvalidAndStore checks if the parent block is invalid and stores
@@ -1495,7 +1627,7 @@ func (u *BlockValidation) storeInvalidBlock(ctx context.Context, block *model.Bl
 func (u *BlockValidation) checkParentInvalidAndStore(ctx context.Context, block *model.Block, baseURL string, parentMeta *model.BlockHeaderMeta) error {
 	if parentMeta != nil && parentMeta.Invalid {
 		reason := fmt.Sprintf("parent block %s is invalid", block.Header.HashPrevBlock.String())
-		u.storeInvalidBlock(ctx, block, baseURL, reason)
+		u.storeInvalidBlock(ctx, block, baseURL, failureStageParent, reason)
 		return errors.NewBlockInvalidError("[ValidateBlock][%s] parent block is invalid", block.Hash().String())
 	}
 	return nil
@@ -1722,10 +1854,12 @@ func (u *BlockValidation) reValidateBlock(blockData revalidateBlockData) error {
 				blockData.block.Header.Hash().String(), blockData.block.Header.Bits, expectedNBits)
 		}
 
-		// Then check that the block hash meets the difficulty target
-		headerValid, _, err := blockData.block.Header.HasMetTargetDifficulty()
-		if !headerValid {
-			return errors.NewBlockInvalidError("[reValidateBlock][%s] block does not meet target difficulty: %s", blockData.block.Header.Hash().String(), err)
+		// Then check that the block hash meets the difficulty target, unless the network does not use proof of work
+		if u.settings.ProofOfWorkCheckRequired() {
+			headerValid, _, err := blockData.block.Header.HasMetTargetDifficulty()
+			if !headerValid {
+				return errors.NewBlockInvalidError("[reValidateBlock][%s] block does not meet target difficulty: %s", blockData.block.Header.Hash().String(), err)
+			}
 		}
 	}
 
@@ -1472,6 +1472,113 @@ func TestBlockValidationExcessiveBlockSize(t *testing.T) {
 	}
 }
 
+func TestBlockValidationResultCache(t *testing.T) {
+	initPrometheusMetrics()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newValidator := func(t *testing.T, cacheTTL time.Duration) (*BlockValidation, *settings.Settings) {
+		utxoStore, subtreeValidationClient, _, txStore, subtreeStore, cleanup := setup(t)
+		t.Cleanup(cleanup)
+
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.ExcessiveBlockSize = 1000000
+		tSettings.BlockValidation.ValidationResultCacheTTL = cacheTTL
+		tSettings.GlobalBlockHeightRetention = uint32(1)
+
+		blockchainStoreURL, err := url.Parse("sqlitememory://")
+		require.NoError(t, err)
+		blockchainStore, err := blockchain_store.NewStore(ulogger.TestLogger{}, blockchainStoreURL, tSettings)
+		require.NoError(t, err)
+
+		blockchainClient, err := blockchain.NewLocalClient(ulogger.TestLogger{}, tSettings, blockchainStore, nil, nil)
+		require.NoError(t, err)
+
+		return NewBlockValidation(ctx, ulogger.TestLogger{}, tSettings, blockchainClient, subtreeStore, txStore, utxoStore, nil, subtreeValidationClient), tSettings
+	}
+
+	newOversizedBlock := func() *model.Block {
+		nBits, _ := model.NewNBitFromString("2000ffff")
+		merkleRoot := chainhash.Hash{}
+
+		return &model.Block{
+			Header: &model.BlockHeader{
+				Version:        1,
+				HashPrevBlock:  chaincfg.RegressionNetParams.GenesisHash,
+				HashMerkleRoot: &merkleRoot,
+				Timestamp:      uint32(time.Now().Unix()), //nolint:gosec
+				Bits:           *nBits,
+				Nonce:          0,
+			},
+			SizeInBytes:      1000001,
+			TransactionCount: 1,
+			CoinbaseTx:       tx1,
+			Subtrees:         []*chainhash.Hash{},
+		}
+	}
+
+	t.Run("duplicate block hits the cache", func(t *testing.T) {
+		blockValidator, tSettings := newValidator(t, time.Minute)
+		block := newOversizedBlock()
+
+		err := blockValidator.ValidateBlock(ctx, block, "peer1", nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errors.ErrBlockInvalid))
+		require.Contains(t, err.Error(), "exceeds excessiveblocksize")
+
+		_, ok := blockValidator.getCachedValidationResult(block.Hash())
+		require.True(t, ok)
+
+		// lift the size limit, the same block from another peer still gets the cached outcome
+		tSettings.Policy.ExcessiveBlockSize = 0
+
+		err = blockValidator.ValidateBlock(ctx, block, "peer2", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exceeds excessiveblocksize")
+
+		// once the cache has been cleared, e.g. on a reorg, the block is validated again
+		blockValidator.validationResultCache.Clear()
+
+		err = blockValidator.ValidateBlock(ctx, block, "peer2", nil)
+		if err != nil {
+			require.NotContains(t, err.Error(), "exceeds excessiveblocksize")
+		}
+	})
+
+	t.Run("revalidation bypasses the cache", func(t *testing.T) {
+		blockValidator, tSettings := newValidator(t, time.Minute)
+		block := newOversizedBlock()
+
+		err := blockValidator.ValidateBlock(ctx, block, "peer1", nil)
+		require.Error(t, err)
+
+		tSettings.Policy.ExcessiveBlockSize = 0
+
+		err = blockValidator.ValidateBlockWithOptions(ctx, block, "peer1", nil, &ValidateBlockOptions{IsRevalidation: true})
+		if err != nil {
+			require.NotContains(t, err.Error(), "exceeds excessiveblocksize")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		blockValidator, tSettings := newValidator(t, 0)
+		require.Nil(t, blockValidator.validationResultCache)
+
+		block := newOversizedBlock()
+
+		err := blockValidator.ValidateBlock(ctx, block, "peer1", nil)
+		require.Error(t, err)
+
+		tSettings.Policy.ExcessiveBlockSize = 0
+
+		err = blockValidator.ValidateBlock(ctx, block, "peer2", nil)
+		if err != nil {
+			require.NotContains(t, err.Error(), "exceeds excessiveblocksize")
+		}
+	})
+}
+
 func Test_validateBlockSubtrees(t *testing.T) {
 	initPrometheusMetrics()
 
@@ -3736,3 +3843,91 @@ func TestBlockValidation_InvalidBlock_PublishesToKafka(t *testing.T) {
 	// Use the thread-safe method to check if Publish was called
 	require.True(t, mockKafka.IsPublishCalled(), "Kafka Publish should be called for invalid block (duplicate transaction)")
 }
+
+func TestBlockValidation_checkBlockSizeLimits(t *testing.T) {
+	newBlock := func(sizeInBytes uint64) *model.Block {
+		return &model.Block{
+			Header: &model.BlockHeader{
+				Version:        1,
+				HashPrevBlock:  &chainhash.Hash{},
+				HashMerkleRoot: &chainhash.Hash{},
+				Bits:           model.NBit{},
+			},
+			SizeInBytes: sizeInBytes,
+		}
+	}
+
+	newBlockValidation := func(excessiveBlockSize int, excessiveBlockWeight uint64) *BlockValidation {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.ExcessiveBlockSize = excessiveBlockSize
+		tSettings.Policy.ExcessiveBlockWeight = excessiveBlockWeight
+
+		return &BlockValidation{settings: tSettings}
+	}
+
+	t.Run("without a weight limit only the size is checked", func(t *testing.T) {
+		u := newBlockValidation(1000, 0)
+
+		require.NoError(t, u.checkBlockSizeLimits(newBlock(1000)))
+
+		err := u.checkBlockSizeLimits(newBlock(1001))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrBlockInvalid))
+		assert.Contains(t, err.Error(), "excessiveblocksize")
+	})
+
+	t.Run("block at the weight limit", func(t *testing.T) {
+		u := newBlockValidation(0, 4000)
+
+		require.NoError(t, u.checkBlockSizeLimits(newBlock(1000)))
+	})
+
+	t.Run("block over the weight limit", func(t *testing.T) {
+		u := newBlockValidation(0, 4000)
+
+		err := u.checkBlockSizeLimits(newBlock(1001))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrBlockInvalid))
+		assert.Contains(t, err.Error(), "block weight 4004 exceeds excessiveblockweight 4000")
+	})
+
+	t.Run("size limit still applies with a weight limit", func(t *testing.T) {
+		u := newBlockValidation(500, 4000)
+
+		err := u.checkBlockSizeLimits(newBlock(501))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "excessiveblocksize")
+	})
+
+	t.Run("block at the transaction limit", func(t *testing.T) {
+		u := newBlockValidation(0, 0)
+		u.settings.Policy.ExcessiveTxPerBlock = 1000
+
+		block := newBlock(1000)
+		block.TransactionCount = 1000
+
+		require.NoError(t, u.checkBlockSizeLimits(block))
+	})
+
+	t.Run("block over the transaction limit", func(t *testing.T) {
+		u := newBlockValidation(0, 0)
+		u.settings.Policy.ExcessiveTxPerBlock = 1000
+
+		block := newBlock(1000)
+		block.TransactionCount = 1001
+
+		err := u.checkBlockSizeLimits(block)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrBlockInvalid))
+		assert.Contains(t, err.Error(), "block transaction count 1001 exceeds excessivetxperblock 1000")
+	})
+
+	t.Run("no transaction limit", func(t *testing.T) {
+		u := newBlockValidation(0, 0)
+
+		block := newBlock(1000)
+		block.TransactionCount = 1 << 40
+
+		require.NoError(t, u.checkBlockSizeLimits(block))
+	})
+}
@@ -160,6 +160,10 @@ type Server struct {
 	// processing of the same subtree from multiple miners
 	processBlockNotify *ttlcache.Cache[chainhash.Hash, bool]
 
+	// blockFetchRetries counts the failed attempts to fetch blocks from the priority queue from all their peers,
+	// a block is given up on once it failed more than the configured number of retries
+	blockFetchRetries blockFetchRetries
+
 	// catchupAlternatives tracks alternative peer sources for blocks in catchup
 	catchupAlternatives *ttlcache.Cache[chainhash.Hash, []processBlockCatchup]
 
@@ -179,6 +183,14 @@ type Server struct {
 	// BlockValidation is running in the same process as the P2P service.
 	p2pClient P2PClientI
 
+	// peerSubtreeLimiter caps the number of concurrent subtree requests to a single peer,
+	// across all blocks being fetched from that peer
+	peerSubtreeLimiter *util.PeerConcurrencyLimiter
+
+	// peerDownloadBudget tracks the bytes of blocks and subtrees downloaded from every peer per interval,
+	// catchup is moved to another peer when the budget of the announcing peer has been used up
+	peerDownloadBudget *util.PeerByteBudget
+
 	// isCatchingUp is an atomic flag to prevent concurrent catchup operations.
 	// When true, indicates that a catchup operation is currently in progress.
 	// This flag ensures only one catchup can run at a time to prevent resource contention.
@@ -304,6 +316,8 @@ func New(
 		peerCircuitBreakers: catchup.NewPeerCircuitBreakers(*cbConfig),
 		headerChainCache:    catchup.NewHeaderChainCache(logger),
 		p2pClient:           p2pClient,
+		peerSubtreeLimiter:  util.NewPeerConcurrencyLimiter(tSettings.BlockValidation.SubtreeFetchConcurrencyPerPeer),
+		peerDownloadBudget:  util.NewPeerByteBudget(tSettings.BlockValidation.PeerDownloadBudgetBytes, tSettings.BlockValidation.PeerDownloadBudgetInterval),
 	}
 
 	return bVal
@@ -504,6 +518,14 @@ func (u *Server) GetCatchupStatus(ctx context.Context, _ *blockvalidation_api.Em
 func (u *Server) Init(ctx context.Context) (err error) {
 	u.logger.Infof("[Init] Starting block validation initialization")
 
+	if u.settings.BlockValidation.SkipProofOfWorkCheck {
+		if !u.settings.ProofOfWorkCheckSkipAllowed() {
+			return errors.NewConfigurationError("[Init] blockvalidation_skipProofOfWorkCheck can only be enabled on regtest or with blockvalidation_privateNetwork, not on %s", u.settings.ChainCfgParams.Name)
+		}
+
+		u.logger.Warnf("[Init] proof of work check of block headers is disabled on %s", u.settings.ChainCfgParams.Name)
+	}
+
 	subtreeValidationClient, err := subtreevalidation.NewClient(ctx, u.logger, u.settings, "blockvalidation")
 	if err != nil {
 		return errors.NewServiceError("[Init] failed to create subtree validation client", err)
@@ -599,6 +621,9 @@ func (u *Server) Init(ctx context.Context) (err error) {
 						continue
 					}
 
+					// Spread downloads across peers when the download budget of the announcing peer has been used up
+					c = u.selectCatchupSource(ctx, c)
+
 					u.logger.Infof("[catchup] Processing catchup request for block %s from peer %s (%s)", c.block.Hash().String(), c.peerID, c.baseURL)
 
 					if err := u.catchup(ctx, c.block, c.peerID, c.baseURL); err != nil {
@@ -1548,31 +1573,17 @@ func (u *Server) blockProcessingWorker(ctx context.Context, workerID int) {
 				}
 
 				// If the error indicates the block couldn't be fetched (network error, malicious node, etc),
-				// we should retry the block later rather than dropping it completely
-				// TODO: We might want to limit the number of retries per block to avoid infinite loops
-				// For now, we'll just re-queue it with deepFork priority to ensure it gets retried eventually
-				// Note: This could lead to blocks being retried indefinitely if they are always failing to fetch
-				// A more robust solution would involve tracking retry counts and eventually giving up after a threshold
-				if errors.IsNetworkError(err) || errors.IsMaliciousResponseError(err) {
-					u.logger.Warnf("[BlockProcessing] Block %s fetch failed, will retry later", blockFound.hash.String())
-					// Re-add the block to the queue for retry
-					// We need to get the block metadata (height and priority) for re-queuing
-					// For now, we'll use deepFork priority as a safe default for retries
-					go func() {
-						// Add a small delay to avoid tight loops
-						time.Sleep(5 * time.Second)
-						// Create a new blockFound without specific peer info so any peer can provide it
-						retryBlock := processBlockFound{
-							hash:    blockFound.hash,
-							baseURL: SourceTypeRetry, // Special marker for retry attempts
-							peerID:  "",              // Clear peer ID so any peer can be used
-							errCh:   nil,             // No error channel for async retry
-						}
-						u.blockPriorityQueue.RequeueForRetry(retryBlock, PriorityDeepFork, 0)
-						u.logger.Infof("[BlockProcessing] Re-queued block %s for retry from any available peer", blockFound.hash.String())
-					}()
+				// we retry the block later rather than dropping it completely, up to the configured number of retries
+				if isBlockFetchFailure(err) {
+					if retryErr := u.retryBlockFetch(ctx, blockFound, err); retryErr != nil {
+						u.logger.Errorf("[BlockProcessing] Worker %d gave up on block %s: %v", workerID, blockFound.hash.String(), retryErr)
+					}
+				} else {
+					u.blockFetchRetries.reset(*blockFound.hash)
 				}
 			} else {
+				u.blockFetchRetries.reset(*blockFound.hash)
+
 				// Update processed metric with success
 				if prometheusBlockPriorityQueueProcessed != nil {
 					prometheusBlockPriorityQueueProcessed.WithLabelValues("unknown", "success").Inc()
@@ -1735,7 +1746,7 @@ func (u *Server) processBlockWithPriority(ctx context.Context, blockFound proces
 		} else {
 			// No alternatives available, we'll need to wait for new announcements
 			u.logger.Warnf("[processBlockWithPriority] No alternative sources available for retry of block %s", blockFound.hash.String())
-			return errors.NewProcessingError("[processBlockWithPriority] no sources available for block %s", blockFound.hash.String())
+			return errors.NewNetworkError("[processBlockWithPriority] no sources available for block %s", blockFound.hash.String())
 		}
 	}
 
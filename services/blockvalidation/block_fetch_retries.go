@@ -0,0 +1,99 @@
+package blockvalidation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// blockFetchRetries counts the consecutive failed attempts to fetch a block from all its known peers. The zero value
+// is ready to use.
+type blockFetchRetries struct {
+	mu       sync.Mutex
+	attempts map[chainhash.Hash]int
+}
+
+// failed records a failed attempt to fetch the block and returns the number of consecutive failed attempts
+func (r *blockFetchRetries) failed(hash chainhash.Hash) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.attempts == nil {
+		r.attempts = make(map[chainhash.Hash]int)
+	}
+
+	r.attempts[hash]++
+
+	return r.attempts[hash]
+}
+
+// reset forgets the failed attempts to fetch the block
+func (r *blockFetchRetries) reset(hash chainhash.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, hash)
+}
+
+// isBlockFetchFailure returns whether the error means the block could not be fetched, rather than that it was invalid
+func isBlockFetchFailure(err error) bool {
+	return errors.IsNetworkError(err) || errors.IsMaliciousResponseError(err)
+}
+
+// retryBlockFetch re-queues a block that could not be fetched from any of its peers, so it is retried from any peer
+// that announced it after the configured delay. Once the block failed to be fetched more than the configured maximum
+// number of retries, it is marked unavailable instead: it is not re-queued, an alert is raised and an error is returned.
+// A later announcement of the block starts over with a new set of retries.
+//
+// Parameters:
+//   - ctx: Context for cancellation of the delayed re-queue
+//   - blockFound: The block that could not be fetched
+//   - fetchErr: The error of the last failed attempt
+//
+// Returns:
+//   - error: A block not found error wrapping the last fetch error when the retries have been exhausted, nil when the
+//     block was re-queued
+func (u *Server) retryBlockFetch(ctx context.Context, blockFound processBlockFound, fetchErr error) error {
+	attempts := u.blockFetchRetries.failed(*blockFound.hash)
+
+	maxRetries := u.settings.BlockValidation.FetchBlockMaxRetries
+	if maxRetries > 0 && attempts > maxRetries {
+		u.blockFetchRetries.reset(*blockFound.hash)
+
+		if prometheusBlockValidationBlockFetchRetriesExhausted != nil {
+			prometheusBlockValidationBlockFetchRetriesExhausted.Inc()
+		}
+
+		u.logger.Errorf("[BlockProcessing] ALERT: block %s is unavailable, fetching it from all peers failed after %d retries: %v", blockFound.hash.String(), maxRetries, fetchErr)
+
+		return errors.NewBlockNotFoundError("[BlockProcessing] block %s unavailable after %d fetch retries", blockFound.hash.String(), maxRetries, fetchErr)
+	}
+
+	u.logger.Warnf("[BlockProcessing] Block %s fetch failed (attempt %d), will retry later", blockFound.hash.String(), attempts)
+
+	go func() {
+		// Add a delay to avoid tight loops
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(u.settings.BlockValidation.FetchBlockRetryDelay):
+		}
+
+		// Create a new blockFound without specific peer info so any peer can provide it
+		retryBlock := processBlockFound{
+			hash:    blockFound.hash,
+			baseURL: SourceTypeRetry, // Special marker for retry attempts
+			peerID:  "",              // Clear peer ID so any peer can be used
+			errCh:   nil,             // No error channel for async retry
+		}
+
+		// We don't have the block metadata (height and priority) for re-queuing, deepFork priority is a safe default
+		u.blockPriorityQueue.RequeueForRetry(retryBlock, PriorityDeepFork, 0)
+		u.logger.Infof("[BlockProcessing] Re-queued block %s for retry from any available peer", blockFound.hash.String())
+	}()
+
+	return nil
+}
@@ -648,3 +648,93 @@ func TestChainExtendingBlocksNotSentToCatchup(t *testing.T) {
 		t.Error("Expected non-chain-extending block to be sent to catchup")
 	}
 }
+
+// TestBlockFetchRetriesExhausted tests that a block that cannot be fetched from any peer is only retried up to the
+// configured number of retries, after which the failure is surfaced
+func TestBlockFetchRetriesExhausted(t *testing.T) {
+	initPrometheusMetrics()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	logger := ulogger.TestLogger{}
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.BlockValidation.FetchBlockMaxRetries = 3
+	tSettings.BlockValidation.FetchBlockRetryDelay = time.Millisecond
+
+	server := &Server{
+		logger:             logger,
+		settings:           tSettings,
+		blockPriorityQueue: NewBlockPriorityQueue(logger),
+	}
+
+	blockHash := chainhash.HashH([]byte("unavailable-block"))
+	blockFound := processBlockFound{
+		hash:    &blockHash,
+		baseURL: "http://failing-peer",
+		peerID:  "failing-peer",
+	}
+
+	fetchErr := errors.NewNetworkError("failed to fetch block")
+
+	t.Run("re-queued until the retries are exhausted", func(t *testing.T) {
+		for retry := 1; retry <= tSettings.BlockValidation.FetchBlockMaxRetries; retry++ {
+			require.NoError(t, server.retryBlockFetch(ctx, blockFound, fetchErr))
+
+			// the block is re-queued for a retry from any peer
+			require.Eventually(t, func() bool { return server.blockPriorityQueue.Contains(blockHash) }, time.Second, time.Millisecond)
+
+			retryBlock, _, ok := server.blockPriorityQueue.Peek()
+			require.True(t, ok)
+			assert.Equal(t, SourceTypeRetry, retryBlock.baseURL)
+			assert.Empty(t, retryBlock.peerID)
+
+			server.blockPriorityQueue.Clear()
+		}
+
+		err := server.retryBlockFetch(ctx, blockFound, fetchErr)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrBlockNotFound))
+		assert.True(t, errors.Is(err, errors.ErrNetworkError))
+
+		// the block is not re-queued again
+		time.Sleep(10 * time.Millisecond)
+		assert.False(t, server.blockPriorityQueue.Contains(blockHash))
+	})
+
+	t.Run("a new announcement starts over", func(t *testing.T) {
+		server.blockPriorityQueue.Clear()
+
+		require.NoError(t, server.retryBlockFetch(ctx, blockFound, fetchErr))
+		require.Eventually(t, func() bool { return server.blockPriorityQueue.Contains(blockHash) }, time.Second, time.Millisecond)
+	})
+
+	t.Run("a processed block resets the retries", func(t *testing.T) {
+		server.blockPriorityQueue.Clear()
+
+		for retry := 1; retry < tSettings.BlockValidation.FetchBlockMaxRetries; retry++ {
+			server.blockFetchRetries.failed(blockHash)
+		}
+
+		server.blockFetchRetries.reset(blockHash)
+
+		require.NoError(t, server.retryBlockFetch(ctx, blockFound, fetchErr))
+		require.NoError(t, server.retryBlockFetch(ctx, blockFound, fetchErr))
+	})
+
+	t.Run("unlimited retries", func(t *testing.T) {
+		unlimitedSettings := test.CreateBaseTestSettings(t)
+		unlimitedSettings.BlockValidation.FetchBlockMaxRetries = 0
+		unlimitedSettings.BlockValidation.FetchBlockRetryDelay = time.Hour
+
+		unlimited := &Server{
+			logger:             logger,
+			settings:           unlimitedSettings,
+			blockPriorityQueue: NewBlockPriorityQueue(logger),
+		}
+
+		for retry := 0; retry < 100; retry++ {
+			require.NoError(t, unlimited.retryBlockFetch(ctx, blockFound, fetchErr))
+		}
+	})
+}
@@ -42,9 +42,10 @@ type CatchupContext struct {
 	currentHeight           uint32
 	blockHeaders            []*model.BlockHeader
 	headersFetchResult      *catchup.Result
-	useQuickValidation      bool   // Whether to use quick validation for checkpointed blocks
-	highestCheckpointHeight uint32 // Highest checkpoint height for validation checks
-	catchupError            error  // Any error encountered during catchup
+	useQuickValidation      bool               // Whether to use quick validation for checkpointed blocks
+	highestCheckpointHeight uint32             // Highest checkpoint height for validation checks
+	catchupError            error              // Any error encountered during catchup
+	blockQueue              *catchupBlockQueue // Bounds the blocks fetched ahead of validation, nil when unbounded
 }
 
 // catchup orchestrates the complete blockchain synchronization process.
@@ -665,6 +666,9 @@ func (u *Server) fetchAndValidateBlocks(ctx context.Context, catchupCtx *Catchup
 	validationBufferSize := min(int(size.Load()), maxValidationBuffer)
 	validateBlocksChan := make(chan *model.Block, validationBufferSize)
 
+	// Bound the total number of blocks fetched ahead of validation, fetching pauses when the queue is full
+	catchupCtx.blockQueue = newCatchupBlockQueue(u.settings.BlockValidation.CatchupMaxQueuedBlocks)
+
 	bestBlockHeader, _, err := u.blockchainClient.GetBestBlockHeader(ctx)
 	if err != nil {
 		return errors.NewProcessingError("failed to get best block header", err)
@@ -929,6 +933,10 @@ func (u *Server) validateBlocksOnChannel(validateBlocksChan chan *model.Block, g
 					// TODO: Consider increasing peer reputation for successful block validations. For now being cautious and only increasing on successful catchup operations.
 				}
 			}
+
+			// Free the slot of the block in the queue, allowing the fetcher to continue
+			catchupCtx.blockQueue.release()
+
 			// Update the remaining block count
 			remaining := size.Add(-1)
 			if remaining%100 == 0 && remaining > 0 {
@@ -1098,11 +1106,13 @@ func (u *Server) validateBatchHeaders(ctx context.Context, headers []*model.Bloc
 		default:
 		}
 
-		// Validate proof of work
-		if err := catchup.ValidateHeaderProofOfWork(header); err != nil {
-			u.logger.Errorf("[catchup:validateBatchHeaders] header %d/%d fails PoW validation: %v",
-				i+1, len(headers), err)
-			return err
+		// Validate proof of work, unless the network does not use proof of work
+		if u.settings.ProofOfWorkCheckRequired() {
+			if err := catchup.ValidateHeaderProofOfWork(header); err != nil {
+				u.logger.Errorf("[catchup:validateBatchHeaders] header %d/%d fails PoW validation: %v",
+					i+1, len(headers), err)
+				return err
+			}
 		}
 
 		// Validate merkle root
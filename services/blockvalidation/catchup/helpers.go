@@ -42,6 +42,7 @@ func ValidateBlockHeaderBytes(headerBytes []byte) error {
 //
 // Parameters:
 //   - headerBytes: Raw header bytes to parse
+//   - checkProofOfWork: Whether to validate the proof of work, false on networks without proof of work
 //
 // Returns:
 //   - []*model.BlockHeader: Successfully parsed headers (nil if error)
@@ -49,7 +50,7 @@ func ValidateBlockHeaderBytes(headerBytes []byte) error {
 //
 // Returns immediately on first error to simplify debugging.
 // Validates proof of work, merkle root, and timestamp for each header.
-func ParseBlockHeaders(headerBytes []byte) ([]*model.BlockHeader, error) {
+func ParseBlockHeaders(headerBytes []byte, checkProofOfWork bool) ([]*model.BlockHeader, error) {
 	if len(headerBytes) == 0 {
 		return nil, nil
 	}
@@ -71,9 +72,11 @@ func ParseBlockHeaders(headerBytes []byte) ([]*model.BlockHeader, error) {
 		}
 
 		// Perform basic header validation
-		if err = ValidateHeaderProofOfWork(header); err != nil {
-			// Return immediately on first validation error
-			return nil, err
+		if checkProofOfWork {
+			if err = ValidateHeaderProofOfWork(header); err != nil {
+				// Return immediately on first validation error
+				return nil, err
+			}
 		}
 
 		if err = ValidateHeaderMerkleRoot(header); err != nil {
@@ -93,7 +93,7 @@ func TestParseBlockHeaders(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to decode header hex: %v", err)
 			}
-			got, err := ParseBlockHeaders(headerBytes)
+			got, err := ParseBlockHeaders(headerBytes, true)
 			if !tt.wantErr(t, err, fmt.Sprintf("ParseBlockHeaders(%x)", tt.args.headerHex)) {
 				return
 			}
@@ -182,7 +182,7 @@ func TestValidateBlockHeaderBytes(t *testing.T) {
 
 func TestParseBlockHeaders_AdditionalCoverage(t *testing.T) {
 	// Test empty bytes - already covered by existing test but adding for completeness
-	headers, err := ParseBlockHeaders([]byte{})
+	headers, err := ParseBlockHeaders([]byte{}, true)
 	require.NoError(t, err)
 	assert.Nil(t, headers)
 
@@ -196,7 +196,7 @@ func TestParseBlockHeaders_AdditionalCoverage(t *testing.T) {
 	invalidHeaderBytes[3] = 0x00
 	// Leave the rest as zeros, which will fail proof of work validation
 
-	headers, err = ParseBlockHeaders(invalidHeaderBytes)
+	headers, err = ParseBlockHeaders(invalidHeaderBytes, true)
 	require.Error(t, err)
 	// The error should be related to validation, not parsing
 	assert.Contains(t, err.Error(), "block header fails proof of work")
@@ -207,6 +207,40 @@ func TestParseBlockHeaders_AdditionalCoverage(t *testing.T) {
 	// so we test with the validation errors instead
 }
 
+func TestParseBlockHeaders_ProofOfWork(t *testing.T) {
+	// a header with all zero bits does not meet its target difficulty
+	noProofOfWorkHeaderBytes := make([]byte, model.BlockHeaderSize)
+	noProofOfWorkHeaderBytes[0] = 0x01
+
+	t.Run("proof of work network rejects header without proof of work", func(t *testing.T) {
+		headers, err := ParseBlockHeaders(noProofOfWorkHeaderBytes, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "block header fails proof of work")
+		assert.Nil(t, headers)
+	})
+
+	t.Run("network without proof of work accepts header without proof of work", func(t *testing.T) {
+		headers, err := ParseBlockHeaders(noProofOfWorkHeaderBytes, false)
+		require.NoError(t, err)
+		require.Len(t, headers, 1)
+		assert.Equal(t, uint32(1), headers[0].Version)
+	})
+
+	t.Run("other header validation is kept without proof of work", func(t *testing.T) {
+		futureHeaderBytes := make([]byte, model.BlockHeaderSize)
+		copy(futureHeaderBytes, noProofOfWorkHeaderBytes)
+		// timestamp far in the future
+		futureHeaderBytes[68] = 0xff
+		futureHeaderBytes[69] = 0xff
+		futureHeaderBytes[70] = 0xff
+		futureHeaderBytes[71] = 0xff
+
+		_, err := ParseBlockHeaders(futureHeaderBytes, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "too far in the future")
+	})
+}
+
 func TestBuildBlockLocatorString(t *testing.T) {
 	tests := []struct {
 		name    string
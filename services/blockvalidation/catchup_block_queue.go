@@ -0,0 +1,81 @@
+package blockvalidation
+
+import (
+	"context"
+)
+
+// catchupBlockQueue bounds the number of blocks that have been fetched during catchup but
+// have not been validated yet. Blocks are fetched much faster than they can be validated, so
+// without a bound the fetched blocks, and their subtree data, would pile up in memory.
+//
+// The fetcher acquires a slot for every block before fetching it and the validator releases the
+// slot once the block has been processed. When all slots are taken, fetching pauses until the
+// validator has drained enough blocks from the queue.
+//
+// A nil queue is unbounded, acquire and release are no-ops in that case.
+type catchupBlockQueue struct {
+	slots chan struct{}
+}
+
+// newCatchupBlockQueue creates a queue that allows up to maxBlocks blocks to be queued for validation.
+// Returns nil, an unbounded queue, when maxBlocks is not positive.
+func newCatchupBlockQueue(maxBlocks int) *catchupBlockQueue {
+	if maxBlocks <= 0 {
+		return nil
+	}
+
+	return &catchupBlockQueue{
+		slots: make(chan struct{}, maxBlocks),
+	}
+}
+
+// acquire reserves a slot for each of the given number of blocks, blocking while the queue is full.
+//
+// Returns:
+//   - error: The context error if the context is cancelled while waiting for a slot
+func (q *catchupBlockQueue) acquire(ctx context.Context, blocks int) error {
+	if q == nil {
+		return nil
+	}
+
+	for i := 0; i < blocks; i++ {
+		select {
+		case q.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// release frees the slot of a single block that has been processed by the validator.
+func (q *catchupBlockQueue) release() {
+	if q == nil {
+		return
+	}
+
+	select {
+	case <-q.slots:
+	default:
+		// nothing to release, the block was not acquired through the queue
+	}
+}
+
+// len returns the number of blocks currently queued.
+func (q *catchupBlockQueue) len() int {
+	if q == nil {
+		return 0
+	}
+
+	return len(q.slots)
+}
+
+// capacity returns the maximum number of blocks that can be queued, 0 when unbounded.
+func (q *catchupBlockQueue) capacity() int {
+	if q == nil {
+		return 0
+	}
+
+	return cap(q.slots)
+}
@@ -0,0 +1,163 @@
+package blockvalidation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/services/blockvalidation/testhelpers"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatchupBlockQueue(t *testing.T) {
+	t.Run("unbounded when max is not positive", func(t *testing.T) {
+		q := newCatchupBlockQueue(0)
+		require.Nil(t, q)
+
+		require.NoError(t, q.acquire(context.Background(), 1000))
+		q.release()
+
+		assert.Equal(t, 0, q.len())
+		assert.Equal(t, 0, q.capacity())
+	})
+
+	t.Run("acquire blocks when full and resumes after release", func(t *testing.T) {
+		q := newCatchupBlockQueue(2)
+		require.NoError(t, q.acquire(context.Background(), 2))
+		assert.Equal(t, 2, q.len())
+
+		acquired := make(chan error, 1)
+
+		go func() {
+			acquired <- q.acquire(context.Background(), 1)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("acquire should block while the queue is full")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		q.release()
+
+		select {
+		case err := <-acquired:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("acquire should resume after a block was released")
+		}
+
+		assert.Equal(t, 2, q.len())
+	})
+
+	t.Run("acquire returns on context cancellation", func(t *testing.T) {
+		q := newCatchupBlockQueue(1)
+		require.NoError(t, q.acquire(context.Background(), 1))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.ErrorIs(t, q.acquire(ctx, 1), context.Canceled)
+	})
+
+	t.Run("release on empty queue is a no-op", func(t *testing.T) {
+		q := newCatchupBlockQueue(1)
+		q.release()
+
+		assert.Equal(t, 0, q.len())
+	})
+}
+
+// TestFetchBlocksConcurrently_BlockQueue verifies that fetching pauses when the queue of blocks
+// waiting for validation is full, and resumes once the validated blocks have been released.
+func TestFetchBlocksConcurrently_BlockQueue(t *testing.T) {
+	suite := NewCatchupTestSuite(t)
+	defer suite.Cleanup()
+
+	const (
+		numBlocks = 6
+		maxQueued = 2
+	)
+
+	blocks := testhelpers.CreateTestBlockChain(t, numBlocks+1)
+	targetBlock := blocks[numBlocks]
+
+	headers := make([]*model.BlockHeader, 0, numBlocks)
+	for i := 1; i <= numBlocks; i++ {
+		headers = append(headers, blocks[i].Header)
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// the batch size is limited to the queue size, so the blocks are fetched in batches of maxQueued,
+	// requested by the last block hash of the batch and returned newest-first
+	for end := maxQueued; end <= numBlocks; end += maxQueued {
+		batchData := bytes.Buffer{}
+
+		for i := end; i > end-maxQueued; i-- {
+			blockBytes, err := blocks[i].Bytes()
+			require.NoError(t, err)
+			batchData.Write(blockBytes)
+		}
+
+		httpmock.RegisterResponder("GET", fmt.Sprintf("http://test-peer/blocks/%s?n=%d", blocks[end].Header.Hash().String(), maxQueued),
+			httpmock.NewBytesResponder(200, batchData.Bytes()))
+	}
+
+	var size atomic.Int64
+	size.Store(numBlocks)
+
+	validateBlocksChan := make(chan *model.Block, numBlocks)
+
+	catchupCtx := &CatchupContext{
+		blockUpTo:    targetBlock,
+		baseURL:      "http://test-peer",
+		blockHeaders: headers,
+		blockQueue:   newCatchupBlockQueue(maxQueued),
+	}
+
+	fetchErr := make(chan error, 1)
+
+	go func() {
+		fetchErr <- suite.Server.fetchBlocksConcurrently(suite.Ctx, catchupCtx, validateBlocksChan, &size)
+	}()
+
+	// nothing is validated yet, so only the first batch can be fetched
+	require.Eventually(t, func() bool {
+		return len(validateBlocksChan) == maxQueued
+	}, time.Second, 10*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	assert.Len(t, validateBlocksChan, maxQueued)
+	assert.Equal(t, maxQueued, catchupCtx.blockQueue.len())
+
+	// drain the queue as the validator would, which resumes the fetching
+	received := make([]*model.Block, 0, numBlocks)
+
+	for len(received) < numBlocks {
+		select {
+		case block := <-validateBlocksChan:
+			received = append(received, block)
+			catchupCtx.blockQueue.release()
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for block %d/%d", len(received)+1, numBlocks)
+		}
+	}
+
+	require.NoError(t, <-fetchErr)
+
+	assert.Equal(t, numBlocks/maxQueued, httpmock.GetTotalCallCount())
+
+	for i, block := range received {
+		assert.Equal(t, blocks[i+1].Header.Hash(), block.Header.Hash())
+	}
+}
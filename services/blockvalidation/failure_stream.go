@@ -0,0 +1,30 @@
+package blockvalidation
+
+import (
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/util/failurestream"
+)
+
+// Stages of the block validation reported on the validation failure stream
+const (
+	failureStageParent      = "parent"
+	failureStageDifficulty  = "difficulty"
+	failureStageProofOfWork = "proof_of_work"
+	failureStageSubtrees    = "subtrees"
+	failureStageBlock       = "block"
+	failureStageOldBlockIDs = "old_block_ids"
+	failureStageSetMined    = "set_mined"
+)
+
+// emitBlockFailure emits an invalid block to the validation failure stream
+func (u *BlockValidation) emitBlockFailure(block *model.Block, stage string, reason string) {
+	if u.failureEmitter == nil {
+		return
+	}
+
+	u.failureEmitter.Emit(&failurestream.Failure{
+		BlockHash: block.Hash().String(),
+		Stage:     stage,
+		Reason:    reason,
+	})
+}
@@ -78,6 +78,11 @@ func (u *Server) fetchBlocksConcurrently(ctx context.Context, catchupCtx *Catchu
 	numWorkers := u.settings.BlockValidation.FetchNumWorkers
 	bufferSize := u.settings.BlockValidation.FetchBufferSize
 
+	// A batch can never be larger than the queue of blocks waiting for validation, otherwise it would never fit
+	if queueCapacity := catchupCtx.blockQueue.capacity(); queueCapacity > 0 && largeBatchSize > queueCapacity {
+		largeBatchSize = queueCapacity
+	}
+
 	// Channels for pipeline stages
 	workQueue := make(chan workItem, bufferSize)
 	resultQueue := make(chan resultItem, bufferSize)
@@ -101,7 +106,7 @@ func (u *Server) fetchBlocksConcurrently(ctx context.Context, catchupCtx *Catchu
 	// Start batch fetching and work distribution
 	g.Go(func() error {
 		defer close(workQueue)
-		return u.batchFetchAndDistribute(gCtx, blockHeaders, workQueue, peerID, baseURL, blockUpTo, largeBatchSize)
+		return u.batchFetchAndDistribute(gCtx, blockHeaders, workQueue, catchupCtx.blockQueue, peerID, baseURL, blockUpTo, largeBatchSize)
 	})
 
 	// Wait for all goroutines to complete
@@ -113,8 +118,10 @@ func (u *Server) fetchBlocksConcurrently(ctx context.Context, catchupCtx *Catchu
 	return g.Wait()
 }
 
-// batchFetchAndDistribute fetches blocks in large batches and immediately distributes them to workers
-func (u *Server) batchFetchAndDistribute(ctx context.Context, blockHeaders []*model.BlockHeader, workQueue chan<- workItem, peerID string, baseURL string, blockUpTo *model.Block, batchSize int) error {
+// batchFetchAndDistribute fetches blocks in large batches and immediately distributes them to workers.
+// Before fetching a batch, a slot is acquired in the blockQueue for every block in it, pausing the
+// fetching while the queue of blocks waiting for validation is full.
+func (u *Server) batchFetchAndDistribute(ctx context.Context, blockHeaders []*model.BlockHeader, workQueue chan<- workItem, blockQueue *catchupBlockQueue, peerID string, baseURL string, blockUpTo *model.Block, batchSize int) error {
 	ctx, _, deferFn := tracing.Tracer("blockvalidation").Start(ctx, "batchFetchAndDistribute",
 		tracing.WithParentStat(u.stats),
 	)
@@ -130,9 +137,22 @@ func (u *Server) batchFetchAndDistribute(ctx context.Context, blockHeaders []*mo
 		}
 
 		batchHeaders := blockHeaders[i:end]
+
+		if blockQueue.capacity() > 0 && blockQueue.len()+len(batchHeaders) > blockQueue.capacity() {
+			u.logger.Debugf("[catchup:batchFetchAndDistribute][%s] block queue full (%d/%d), pausing fetching until blocks are validated",
+				blockUpTo.Hash().String(), blockQueue.len(), blockQueue.capacity())
+		}
+
+		if err := blockQueue.acquire(ctx, len(batchHeaders)); err != nil {
+			return err
+		}
+
 		u.logger.Debugf("[catchup:batchFetchAndDistribute][%s] fetching batch %d-%d (%d blocks)",
 			blockUpTo.Hash().String(), i, end-1, len(batchHeaders))
 
+		// Move to another peer when the download budget of the current peer has been used up
+		peerID, baseURL = u.selectDownloadPeer(ctx, blockUpTo.Height, peerID, baseURL)
+
 		// Fetch entire batch in one HTTP request, from last block, since the data is returned newest-first
 		blocks, err := u.fetchBlocksBatch(ctx, batchHeaders[len(batchHeaders)-1].Hash(), uint32(len(batchHeaders)), peerID, baseURL)
 		if err != nil {
@@ -305,6 +325,20 @@ func (u *Server) fetchSubtreeDataForBlock(gCtx context.Context, block *model.Blo
 		return nil
 	}
 
+	// Move to another peer when the download budget of the current peer has been used up
+	peerID, baseURL = u.selectDownloadPeer(ctx, block.Height, peerID, baseURL)
+
+	// Other peers the subtrees can be fetched from when the current peer is at its concurrency limit
+	subtreePeers := u.selectSubtreePeers(ctx, block.Height, peerID, baseURL)
+
+	subtreeBaseURLs := make([]string, 0, len(subtreePeers))
+	subtreePeerIDs := make(map[string]string, len(subtreePeers))
+
+	for _, p := range subtreePeers {
+		subtreeBaseURLs = append(subtreeBaseURLs, p.DataHubURL)
+		subtreePeerIDs[p.DataHubURL] = p.ID
+	}
+
 	// Create error group for concurrent subtree fetching
 	g, ctx := errgroup.WithContext(ctx)
 	// Limit concurrency to avoid overwhelming the peer
@@ -320,7 +354,12 @@ func (u *Server) fetchSubtreeDataForBlock(gCtx context.Context, block *model.Blo
 		subtreeHashCopy := *subtreeHash // Capture for goroutine
 
 		g.Go(func() error {
-			return u.fetchAndStoreSubtreeAndSubtreeData(ctx, block, &subtreeHashCopy, peerID, baseURL)
+			// Spread the subtrees over the peers, work in excess of the per peer limit of the current peer
+			// moves to the other peers instead of waiting for the current peer
+			subtreeBaseURL, done := u.peerSubtreeLimiter.Assign(subtreeBaseURLs)
+			defer done()
+
+			return u.fetchAndStoreSubtreeAndSubtreeData(ctx, block, &subtreeHashCopy, subtreePeerIDs[subtreeBaseURL], subtreeBaseURL)
 		})
 	}
 
@@ -543,13 +582,27 @@ func (u *Server) fetchSubtreeFromPeer(ctx context.Context, subtreeHash *chainhas
 
 	u.logger.Debugf("[catchup:fetchSubtreeFromPeer] fetching subtree from %s", url)
 
+	if err := u.waitForPeerDownloadBudget(ctx, baseURL); err != nil {
+		return nil, err
+	}
+
+	// Wait for a free request slot of the peer, to not overload a single peer
+	release, err := u.peerSubtreeLimiter.Acquire(ctx, baseURL)
+	if err != nil {
+		return nil, errors.NewContextCanceledError("[catchup:fetchSubtreeFromPeer] cancelled while waiting to fetch subtree from %s", url, err)
+	}
+
 	// Use the existing HTTP utility to fetch subtree
 	subtreeBytes, err := util.DoHTTPRequest(ctx, url)
+
+	release()
 	if err != nil {
 		return nil, errors.NewServiceError("[catchup:fetchSubtreeFromPeer] failed to fetch subtree from %s", url, err)
 	}
 
 	// Track bytes downloaded from peer
+	u.peerDownloadBudget.Record(baseURL, uint64(len(subtreeBytes)))
+
 	if u.p2pClient != nil && peerID != "" {
 		if err := u.p2pClient.RecordBytesDownloaded(ctx, peerID, uint64(len(subtreeBytes))); err != nil {
 			u.logger.Warnf("[fetchSubtreeFromPeer][%s] failed to record %d bytes downloaded from peer %s: %v", subtreeHash.String(), len(subtreeBytes), peerID, err)
@@ -598,18 +651,33 @@ func (u *Server) fetchSubtreeDataFromPeer(ctx context.Context, subtreeHash *chai
 
 	u.logger.Debugf("[catchup:fetchSubtreeDataFromPeer] fetching subtree data from %s", url)
 
+	if err := u.waitForPeerDownloadBudget(ctx, baseURL); err != nil {
+		return nil, err
+	}
+
+	// Wait for a free request slot of the peer, to not overload a single peer
+	release, err := u.peerSubtreeLimiter.Acquire(ctx, baseURL)
+	if err != nil {
+		return nil, errors.NewContextCanceledError("[catchup:fetchSubtreeDataFromPeer] cancelled while waiting to fetch subtree data from %s", url, err)
+	}
+
 	// Use the existing HTTP utility to fetch subtree data
 	subtreeDataReader, err := util.DoHTTPRequestBodyReader(ctx, url)
 	if err != nil {
+		release()
+
 		return nil, errors.NewServiceError("[catchup:fetchSubtreeDataFromPeer] failed to fetch subtree data from %s", url, err)
 	}
 
-	// Wrap with counting reader to track bytes when stream is consumed
+	// Wrap with counting reader to track bytes when stream is consumed, the request slot of the peer
+	// is held until the stream has been consumed and closed
 	countingReader := &countingReadCloser{
-		reader: subtreeDataReader,
+		reader: util.ReleaseOnClose(subtreeDataReader, release),
 		onClose: func(bytesRead uint64) {
 			// Track bytes downloaded from peer when reader is closed (after all data consumed)
 			// Decouple the context to ensure tracking completes even if parent context is cancelled
+			u.peerDownloadBudget.Record(baseURL, bytesRead)
+
 			if u.p2pClient != nil && peerID != "" {
 				trackCtx, _, deferFn := tracing.DecoupleTracingSpan(ctx, "blockvalidation", "recordBytesDownloaded")
 				defer deferFn()
@@ -640,12 +708,18 @@ func (u *Server) fetchBlocksBatch(ctx context.Context, hash *chainhash.Hash, n u
 	)
 	defer deferFn()
 
+	if err := u.waitForPeerDownloadBudget(ctx, baseURL); err != nil {
+		return nil, err
+	}
+
 	blockBytes, err := util.DoHTTPRequest(ctx, fmt.Sprintf("%s/blocks/%s?n=%d", baseURL, hash.String(), n))
 	if err != nil {
 		return nil, errors.NewProcessingError("[catchup:fetchBlocksBatch][%s] failed to get blocks from peer", hash.String(), err)
 	}
 
 	// Track bytes downloaded from peer
+	u.peerDownloadBudget.Record(baseURL, uint64(len(blockBytes)))
+
 	if u.p2pClient != nil && peerID != "" {
 		if err := u.p2pClient.RecordBytesDownloaded(ctx, peerID, uint64(len(blockBytes))); err != nil {
 			u.logger.Warnf("[fetchBlocksBatch][%s] failed to record %d bytes downloaded from peer %s: %v", hash.String(), len(blockBytes), peerID, err)
@@ -689,12 +763,18 @@ func (u *Server) fetchSingleBlock(ctx context.Context, hash *chainhash.Hash, pee
 	)
 	defer deferFn()
 
+	if err := u.waitForPeerDownloadBudget(ctx, baseURL); err != nil {
+		return nil, err
+	}
+
 	blockBytes, err := util.DoHTTPRequest(ctx, fmt.Sprintf("%s/block/%s", baseURL, hash.String()))
 	if err != nil {
 		return nil, errors.NewProcessingError("[catchup:fetchSingleBlock][%s] failed to get block from peer", hash.String(), err)
 	}
 
 	// Track bytes downloaded from peer
+	u.peerDownloadBudget.Record(baseURL, uint64(len(blockBytes)))
+
 	if u.p2pClient != nil && peerID != "" {
 		if err := u.p2pClient.RecordBytesDownloaded(ctx, peerID, uint64(len(blockBytes))); err != nil {
 			u.logger.Warnf("[fetchSingleBlock][%s] failed to record %d bytes downloaded from peer %s: %v", hash.String(), len(blockBytes), peerID, err)
@@ -18,12 +18,16 @@ import (
 	"github.com/bsv-blockchain/teranode/model"
 	"github.com/bsv-blockchain/teranode/pkg/fileformat"
 	"github.com/bsv-blockchain/teranode/services/blockvalidation/testhelpers"
+	"github.com/bsv-blockchain/teranode/services/p2p"
 	"github.com/bsv-blockchain/teranode/stores/blob"
 	"github.com/bsv-blockchain/teranode/stores/blob/memory"
 	"github.com/bsv-blockchain/teranode/test/utils/transactions"
 	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
 	"github.com/bsv-blockchain/teranode/util/test"
 	"github.com/jarcoal/httpmock"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -857,6 +861,113 @@ func TestFetchSingleBlock_CurrentBehavior(t *testing.T) {
 	})
 }
 
+func TestPeerDownloadBudget(t *testing.T) {
+	suite := NewCatchupTestSuite(t)
+	defer suite.Cleanup()
+
+	blocks := testhelpers.CreateTestBlockChain(t, 2)
+	targetHash := blocks[1].Header.Hash()
+
+	blockBytes, err := blocks[1].Bytes()
+	require.NoError(t, err)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, peer := range []string{"http://peer-a", "http://peer-b"} {
+		httpmock.RegisterResponder("GET", fmt.Sprintf("%s/block/%s", peer, targetHash.String()),
+			httpmock.NewBytesResponder(200, blockBytes))
+	}
+
+	// allow a single block to be downloaded per peer per interval
+	suite.Server.peerDownloadBudget = util.NewPeerByteBudget(uint64(len(blockBytes)), time.Minute)
+
+	requested := processBlockCatchup{
+		block:   blocks[1],
+		peerID:  "peer-a",
+		baseURL: "http://peer-a",
+	}
+
+	// peer a has budget left, so it is used
+	assert.Equal(t, requested, suite.Server.selectCatchupSource(suite.Ctx, requested))
+
+	_, err = suite.Server.fetchSingleBlock(suite.Ctx, targetHash, "peer-a", "http://peer-a")
+	require.NoError(t, err)
+
+	assert.True(t, suite.Server.peerDownloadBudget.Exhausted("http://peer-a"))
+
+	t.Run("overflow goes to another peer", func(t *testing.T) {
+		alternative := processBlockCatchup{
+			block:   blocks[1],
+			peerID:  "peer-b",
+			baseURL: "http://peer-b",
+		}
+
+		suite.Server.catchupAlternatives.Set(*targetHash, []processBlockCatchup{alternative}, ttlcache.DefaultTTL)
+		defer suite.Server.catchupAlternatives.Delete(*targetHash)
+
+		selected := suite.Server.selectCatchupSource(suite.Ctx, requested)
+		assert.Equal(t, alternative, selected)
+
+		// the exhausted peer is kept for failover
+		alternatives := suite.Server.catchupAlternatives.Get(*targetHash)
+		require.NotNil(t, alternatives)
+		assert.Equal(t, []processBlockCatchup{requested}, alternatives.Value())
+
+		_, err := suite.Server.fetchSingleBlock(suite.Ctx, targetHash, selected.peerID, selected.baseURL)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, httpmock.GetCallCountInfo()[fmt.Sprintf("GET http://peer-a/block/%s", targetHash.String())])
+		assert.Equal(t, 1, httpmock.GetCallCountInfo()[fmt.Sprintf("GET http://peer-b/block/%s", targetHash.String())])
+	})
+
+	t.Run("exhausted peer is used when no other peer has budget left", func(t *testing.T) {
+		alternative := processBlockCatchup{
+			block:   blocks[1],
+			peerID:  "peer-b",
+			baseURL: "http://peer-b",
+		}
+
+		suite.Server.catchupAlternatives.Set(*targetHash, []processBlockCatchup{alternative}, ttlcache.DefaultTTL)
+		defer suite.Server.catchupAlternatives.Delete(*targetHash)
+
+		// peer b has used its budget in the previous test
+		require.True(t, suite.Server.peerDownloadBudget.Exhausted("http://peer-b"))
+
+		assert.Equal(t, requested, suite.Server.selectCatchupSource(suite.Ctx, requested))
+	})
+
+	t.Run("download from exhausted peer waits for its budget", func(t *testing.T) {
+		// no other peer is available to move the download to
+		peerID, baseURL := suite.Server.selectDownloadPeer(suite.Ctx, blocks[1].Height, "peer-a", "http://peer-a")
+		assert.Equal(t, "peer-a", peerID)
+		assert.Equal(t, "http://peer-a", baseURL)
+
+		ctx, cancel := context.WithTimeout(suite.Ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := suite.Server.fetchSingleBlock(ctx, targetHash, "peer-a", "http://peer-a")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrContextCanceled))
+
+		// the capped peer was not downloaded from again
+		assert.Equal(t, 1, httpmock.GetCallCountInfo()[fmt.Sprintf("GET http://peer-a/block/%s", targetHash.String())])
+	})
+
+	t.Run("download continues when the budget is reset", func(t *testing.T) {
+		suite.Server.peerDownloadBudget = util.NewPeerByteBudget(uint64(len(blockBytes)), 100*time.Millisecond)
+		suite.Server.peerDownloadBudget.Record("http://peer-a", uint64(len(blockBytes)))
+
+		start := time.Now()
+
+		_, err := suite.Server.fetchSingleBlock(suite.Ctx, targetHash, "peer-a", "http://peer-a")
+		require.NoError(t, err)
+
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		assert.Equal(t, 2, httpmock.GetCallCountInfo()[fmt.Sprintf("GET http://peer-a/block/%s", targetHash.String())])
+	})
+}
+
 // Phase 2: Tests for optimized batch fetching and ordered delivery
 func TestFetchBlocksConcurrently_OptimizedBehavior(t *testing.T) {
 	t.Run("Ordered_Delivery_With_Batching", func(t *testing.T) {
@@ -1889,6 +2000,88 @@ func TestFetchSubtreeDataForBlock(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("ExcessSubtreesMoveToOtherPeers", func(t *testing.T) {
+		const maxPerPeer = 2
+
+		peerB, peerC := peer.ID("peer-b"), peer.ID("peer-c")
+
+		spreadServer := &Server{
+			logger:             logger,
+			subtreeStore:       memory.New(),
+			settings:           settings,
+			peerSubtreeLimiter: util.NewPeerConcurrencyLimiter(maxPerPeer),
+			p2pClient: &subtreePeersP2PClient{peers: []*p2p.PeerInfo{
+				{ID: peerB, Height: 100, DataHubURL: "http://peer-b:8080"},
+				{ID: peerC, Height: 100, DataHubURL: "http://peer-c:8080"},
+				{ID: peer.ID("peer-d"), Height: 99, DataHubURL: "http://peer-d:8080"}, // behind the block
+			}},
+		}
+
+		block := &model.Block{
+			Height: 100,
+		}
+
+		for i := 0; i < 12; i++ {
+			block.Subtrees = append(block.Subtrees, createTestHash(fmt.Sprintf("spread-subtree-%d", i)))
+		}
+
+		var nodeHashes []byte
+		nodeHashes = append(nodeHashes, subtreepkg.CoinbasePlaceholderHashValue[:]...)
+		nodeHashes = append(nodeHashes, txs[1].TxIDChainHash()[:]...)
+		nodeHashes = append(nodeHashes, txs[2].TxIDChainHash()[:]...)
+		nodeHashes = append(nodeHashes, txs[3].TxIDChainHash()[:]...)
+
+		var (
+			mu          sync.Mutex
+			inFlight    = make(map[string]int)
+			maxInFlight = make(map[string]int)
+			requests    = make(map[string]int)
+		)
+
+		// responder that tracks the concurrent requests per peer
+		responder := func(peerURL string, body []byte) httpmock.Responder {
+			return func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				inFlight[peerURL]++
+				requests[peerURL]++
+				maxInFlight[peerURL] = max(maxInFlight[peerURL], inFlight[peerURL])
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				inFlight[peerURL]--
+				mu.Unlock()
+
+				return httpmock.NewBytesResponse(200, body), nil
+			}
+		}
+
+		peerURLs := []string{baseURL, "http://peer-b:8080", "http://peer-c:8080", "http://peer-d:8080"}
+
+		for _, peerURL := range peerURLs {
+			for _, hash := range block.Subtrees {
+				httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subtree/%s", peerURL, hash.String()), responder(peerURL, nodeHashes))
+				httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subtree_data/%s", peerURL, hash.String()), responder(peerURL, subtreeDataBytes))
+			}
+		}
+
+		err := spreadServer.fetchSubtreeDataForBlock(ctx, block, "12D3KooWL1NF6fdTJ9cucEuwvuX8V8KtpJZZnUE4umdLBuK15eUZ", baseURL)
+		require.NoError(t, err)
+
+		// requests to a peer respect the per peer cap, while the subtrees are fetched from multiple peers
+		for _, peerURL := range peerURLs[:3] {
+			assert.LessOrEqual(t, maxInFlight[peerURL], maxPerPeer, peerURL)
+			assert.Positive(t, requests[peerURL], peerURL)
+		}
+
+		// every subtree and its data are requested once
+		assert.Equal(t, 2*len(block.Subtrees), requests[peerURLs[0]]+requests[peerURLs[1]]+requests[peerURLs[2]])
+
+		// peers that do not have the block yet are not used
+		assert.Zero(t, requests["http://peer-d:8080"])
+	})
+
 	t.Run("SubtreeFetchError", func(t *testing.T) {
 		subtreeHash := createTestHash("error-subtree")
 		block := &model.Block{
@@ -1950,6 +2143,28 @@ func TestFetchSubtreeDataForBlock(t *testing.T) {
 	})
 }
 
+// subtreePeersP2PClient is a P2P client that returns the given peers for catchup, all of them healthy
+type subtreePeersP2PClient struct {
+	P2PClientI
+	peers []*p2p.PeerInfo
+}
+
+func (c *subtreePeersP2PClient) GetPeersForCatchup(_ context.Context) ([]*p2p.PeerInfo, error) {
+	return c.peers, nil
+}
+
+func (c *subtreePeersP2PClient) IsPeerMalicious(_ context.Context, _ string) (bool, string, error) {
+	return false, "", nil
+}
+
+func (c *subtreePeersP2PClient) IsPeerUnhealthy(_ context.Context, _ string) (bool, string, float32, error) {
+	return false, "", 0, nil
+}
+
+func (c *subtreePeersP2PClient) RecordBytesDownloaded(_ context.Context, _ string, _ uint64) error {
+	return nil
+}
+
 // TestFetchAndStoreSubtreeAndSubtreeData tests the fetchAndStoreSubtreeAndSubtreeData function comprehensively
 func TestFetchAndStoreSubtreeData(t *testing.T) {
 	baseURL := "http://test-peer:8080"
@@ -39,6 +39,8 @@ var (
 	prometheusBlockValidationLastValidatedBlocksCache prometheus.Gauge
 	prometheusBlockValidationBlockExistsCache         prometheus.Gauge
 	prometheusBlockValidationSubtreeExistsCache       prometheus.Gauge
+	prometheusBlockValidationResultCache              prometheus.Gauge
+	prometheusBlockValidationResultCacheHits          prometheus.Counter
 
 	// catchup operation metrics
 	prometheusCatchupDuration       *prometheus.HistogramVec
@@ -52,6 +54,9 @@ var (
 	prometheusBlockPriorityQueueAdded     *prometheus.CounterVec
 	prometheusBlockPriorityQueueProcessed *prometheus.CounterVec
 
+	// block fetch retry metrics
+	prometheusBlockValidationBlockFetchRetriesExhausted prometheus.Counter
+
 	// fork processing metrics
 	prometheusForkCount             prometheus.Gauge
 	prometheusForkProcessingWorkers prometheus.Gauge
@@ -210,6 +215,24 @@ func _initPrometheusMetrics() {
 		},
 	)
 
+	prometheusBlockValidationResultCache = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "teranode",
+			Subsystem: "blockvalidation",
+			Name:      "validation_result_cache",
+			Help:      "Number of blocks in the validation result cache",
+		},
+	)
+
+	prometheusBlockValidationResultCacheHits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "blockvalidation",
+			Name:      "validation_result_cache_hits",
+			Help:      "Number of duplicate blocks that returned the cached validation result",
+		},
+	)
+
 	// Initialize catchup operation metrics
 	prometheusCatchupDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -292,6 +315,15 @@ func _initPrometheusMetrics() {
 		[]string{"priority", "result"},
 	)
 
+	prometheusBlockValidationBlockFetchRetriesExhausted = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "blockvalidation",
+			Name:      "block_fetch_retries_exhausted",
+			Help:      "Number of blocks marked unavailable after fetching them from all peers failed for the maximum number of retries",
+		},
+	)
+
 	// Initialize fork processing metrics
 	prometheusForkCount = promauto.NewGauge(
 		prometheus.GaugeOpts{
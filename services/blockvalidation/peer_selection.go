@@ -2,6 +2,9 @@ package blockvalidation
 
 import (
 	"context"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/jellydator/ttlcache/v3"
 )
 
 // PeerForCatchup represents a peer suitable for catchup operations with its metadata
@@ -112,3 +115,184 @@ func (u *Server) selectBestPeerForBlock(ctx context.Context, targetHeight int32)
 
 	return &peers[0], nil
 }
+
+// selectCatchupSource returns the source to catch up from for the given catchup request. When the download budget
+// of the requested peer has been used up for the current interval, another source of the block with budget left
+// is used instead: first the cached alternatives that announced the same block, then the best peers of the P2P
+// service. The requested peer is kept as an alternative for failover. When no other source with budget left is
+// available, the requested peer is kept and its downloads wait for its budget, see waitForPeerDownloadBudget.
+//
+// Parameters:
+//   - ctx: Context for the gRPC call to the P2P service
+//   - c: The catchup request
+//
+// Returns:
+//   - processBlockCatchup: The catchup request to process, with the peer to download from
+func (u *Server) selectCatchupSource(ctx context.Context, c processBlockCatchup) processBlockCatchup {
+	if !u.peerDownloadBudget.Exhausted(c.baseURL) {
+		return c
+	}
+
+	blockHash := c.block.Hash()
+
+	u.logger.Infof("[catchup][%s] download budget of peer %s (%s) exhausted with %d bytes, looking for another peer", blockHash.String(), c.peerID, c.baseURL, u.peerDownloadBudget.Used(c.baseURL))
+
+	candidates := make([]processBlockCatchup, 0)
+
+	if alternatives := u.catchupAlternatives.Get(*blockHash); alternatives != nil && alternatives.Value() != nil {
+		candidates = append(candidates, alternatives.Value()...)
+	}
+
+	cachedAlternatives := len(candidates)
+
+	bestPeers, err := u.selectBestPeersForCatchup(ctx, int32(c.block.Height))
+	if err != nil {
+		u.logger.Warnf("[catchup][%s] failed to get best peers from P2P service: %v", blockHash.String(), err)
+	}
+
+	for _, p := range bestPeers {
+		candidates = append(candidates, processBlockCatchup{
+			block:   c.block,
+			baseURL: p.DataHubURL,
+			peerID:  p.ID,
+		})
+	}
+
+	for idx, candidate := range candidates {
+		if candidate.peerID == c.peerID || candidate.baseURL == c.baseURL {
+			continue
+		}
+
+		if u.peerDownloadBudget.Exhausted(candidate.baseURL) || u.isPeerBad(candidate.peerID) || u.isPeerMalicious(ctx, candidate.peerID) {
+			continue
+		}
+
+		u.logger.Infof("[catchup][%s] downloading from peer %s (%s) instead of peer %s", blockHash.String(), candidate.peerID, candidate.baseURL, c.peerID)
+
+		// keep the requested peer and the other cached alternatives for failover
+		alternatives := make([]processBlockCatchup, 0, cachedAlternatives+1)
+		alternatives = append(alternatives, c)
+
+		for altIdx, alt := range candidates[:cachedAlternatives] {
+			if altIdx != idx {
+				alternatives = append(alternatives, alt)
+			}
+		}
+
+		u.catchupAlternatives.Set(*blockHash, alternatives, ttlcache.DefaultTTL)
+
+		return candidate
+	}
+
+	u.logger.Infof("[catchup][%s] no other peer with download budget left, waiting for the budget of peer %s (%s)", blockHash.String(), c.peerID, c.baseURL)
+
+	return c
+}
+
+// selectDownloadPeer returns the peer to download the next part of a catchup from. When the download budget of the
+// given peer has been used up for the current interval, the best peer of the P2P service at or above the given
+// height with budget left is returned instead. The given peer is returned when no such peer exists, its downloads
+// then wait for its budget.
+//
+// Parameters:
+//   - ctx: Context for the gRPC call to the P2P service
+//   - height: The height of the block the data is downloaded for
+//   - peerID: The peer ID of the current download peer
+//   - baseURL: The base URL of the current download peer
+//
+// Returns:
+//   - string: The peer ID to download from
+//   - string: The base URL to download from
+func (u *Server) selectDownloadPeer(ctx context.Context, height uint32, peerID, baseURL string) (string, string) {
+	if !u.peerDownloadBudget.Exhausted(baseURL) {
+		return peerID, baseURL
+	}
+
+	bestPeers, err := u.selectBestPeersForCatchup(ctx, int32(height))
+	if err != nil {
+		u.logger.Warnf("[catchup] failed to get best peers from P2P service: %v", err)
+	}
+
+	for _, p := range bestPeers {
+		if p.ID == peerID || p.DataHubURL == baseURL {
+			continue
+		}
+
+		if u.peerDownloadBudget.Exhausted(p.DataHubURL) || u.isPeerBad(p.ID) || u.isPeerMalicious(ctx, p.ID) {
+			continue
+		}
+
+		u.logger.Infof("[catchup] download budget of peer %s (%s) exhausted, downloading from peer %s (%s)", peerID, baseURL, p.ID, p.DataHubURL)
+
+		return p.ID, p.DataHubURL
+	}
+
+	return peerID, baseURL
+}
+
+// selectSubtreePeers returns the peers to fetch the subtrees of a block from, to spread the subtree requests
+// over several peers when the per peer subtree fetch concurrency is limited. The given peer is returned first,
+// followed by the best peers of the P2P service at or above the given height that have download budget left.
+// Only the given peer is returned when the subtree fetch concurrency per peer is not limited.
+//
+// Parameters:
+//   - ctx: Context for the gRPC call to the P2P service
+//   - height: The height of the block the subtrees are fetched for
+//   - peerID: The peer ID of the current download peer
+//   - baseURL: The base URL of the current download peer
+//
+// Returns:
+//   - []PeerForCatchup: The peers to fetch the subtrees from, the current download peer first
+func (u *Server) selectSubtreePeers(ctx context.Context, height uint32, peerID, baseURL string) []PeerForCatchup {
+	peers := []PeerForCatchup{{ID: peerID, DataHubURL: baseURL}}
+
+	if u.peerSubtreeLimiter == nil {
+		return peers
+	}
+
+	bestPeers, err := u.selectBestPeersForCatchup(ctx, int32(height))
+	if err != nil {
+		u.logger.Warnf("[catchup] failed to get best peers from P2P service: %v", err)
+	}
+
+	baseURLs := map[string]struct{}{baseURL: {}}
+
+	for _, p := range bestPeers {
+		if _, ok := baseURLs[p.DataHubURL]; ok || p.ID == peerID {
+			continue
+		}
+
+		if u.peerDownloadBudget.Exhausted(p.DataHubURL) || u.isPeerBad(p.ID) || u.isPeerMalicious(ctx, p.ID) {
+			continue
+		}
+
+		baseURLs[p.DataHubURL] = struct{}{}
+		peers = append(peers, p)
+	}
+
+	return peers
+}
+
+// waitForPeerDownloadBudget waits until the download budget of the peer with the given base URL is no longer
+// exhausted, so no more than the budget is downloaded from a peer per interval, apart from the download that
+// exhausts it.
+//
+// Parameters:
+//   - ctx: Context for cancelling the wait
+//   - baseURL: The base URL of the peer to download from
+//
+// Returns:
+//   - error: If the context is done before the budget of the peer is reset
+func (u *Server) waitForPeerDownloadBudget(ctx context.Context, baseURL string) error {
+	if !u.peerDownloadBudget.Exhausted(baseURL) {
+		return nil
+	}
+
+	u.logger.Infof("[peer_selection] download budget of peer %s exhausted with %d bytes, waiting for the budget to reset", baseURL, u.peerDownloadBudget.Used(baseURL))
+
+	if err := u.peerDownloadBudget.Wait(ctx, baseURL); err != nil {
+		return errors.NewContextCanceledError("[peer_selection] cancelled while waiting for the download budget of peer %s", baseURL, err)
+	}
+
+	return nil
+}
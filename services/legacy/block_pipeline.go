@@ -0,0 +1,70 @@
+package legacy
+
+import (
+	"context"
+)
+
+// blockPipeline overlaps receiving the blocks of a peer with their validation during the initial block download.
+// Without it, the peer stops reading after every block until the block is validated, so the next block is only
+// requested from the network once the previous one is done. The pipeline lets the peer read up to depth blocks
+// ahead of validation instead, while the sync manager still validates the queued blocks one at a time, in the
+// order they were received.
+//
+// A nil pipeline does not read ahead, enqueue then waits for the block to be processed.
+type blockPipeline struct {
+	slots chan struct{}
+}
+
+// newBlockPipeline creates a pipeline reading up to depth blocks ahead of validation. Returns nil, no reading
+// ahead, when depth is not positive.
+func newBlockPipeline(depth int) *blockPipeline {
+	if depth <= 0 {
+		return nil
+	}
+
+	return &blockPipeline{
+		slots: make(chan struct{}, depth),
+	}
+}
+
+// enqueue queues a block for validation with the queue function, which must send the result of the validation
+// on the done channel, and calls processed with that result. When the pipeline has a free slot, enqueue returns
+// once the block is queued and processed is called asynchronously; otherwise it waits for a slot first. A nil
+// pipeline waits for the block to be processed before returning.
+//
+// Returns:
+//   - error: The context error if the context is cancelled while waiting for a slot
+func (p *blockPipeline) enqueue(ctx context.Context, queue func(done chan error), processed func(err error)) error {
+	done := make(chan error, 1)
+
+	if p == nil {
+		queue(done)
+		processed(<-done)
+
+		return nil
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	queue(done)
+
+	go func() {
+		processed(<-done)
+		<-p.slots
+	}()
+
+	return nil
+}
+
+// inFlight returns the number of blocks queued for validation that have not been processed yet.
+func (p *blockPipeline) inFlight() int {
+	if p == nil {
+		return 0
+	}
+
+	return len(p.slots)
+}
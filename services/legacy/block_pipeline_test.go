@@ -0,0 +1,182 @@
+package legacy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipelineTestBlock is a block queued for validation on the fakeBlockValidator
+type pipelineTestBlock struct {
+	height int
+	done   chan error
+}
+
+// fakeBlockValidator validates the queued blocks one at a time, in order, like the block handler of the sync manager
+type fakeBlockValidator struct {
+	queue     chan pipelineTestBlock
+	validate  func(height int)
+	mu        sync.Mutex
+	validated []int
+}
+
+func newFakeBlockValidator(validate func(height int)) *fakeBlockValidator {
+	v := &fakeBlockValidator{
+		queue:    make(chan pipelineTestBlock, 100),
+		validate: validate,
+	}
+
+	go func() {
+		for block := range v.queue {
+			v.validate(block.height)
+
+			v.mu.Lock()
+			v.validated = append(v.validated, block.height)
+			v.mu.Unlock()
+
+			block.done <- nil
+		}
+	}()
+
+	return v
+}
+
+func (v *fakeBlockValidator) queueFunc(height int) func(done chan error) {
+	return func(done chan error) {
+		v.queue <- pipelineTestBlock{height: height, done: done}
+	}
+}
+
+func (v *fakeBlockValidator) validatedBlocks() []int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return append([]int(nil), v.validated...)
+}
+
+func TestBlockPipeline(t *testing.T) {
+	t.Run("validates in order while fetching ahead", func(t *testing.T) {
+		const blocks = 10
+
+		fetched := make([]chan struct{}, blocks+1)
+		for i := range fetched {
+			fetched[i] = make(chan struct{})
+		}
+
+		// the validation of a block only completes once the next block was fetched, which can only happen
+		// when fetching overlaps with validation
+		overlapped := true
+		v := newFakeBlockValidator(func(height int) {
+			select {
+			case <-fetched[height+1]:
+			case <-time.After(5 * time.Second):
+				overlapped = false
+			}
+		})
+		defer close(v.queue)
+
+		pipeline := newBlockPipeline(3)
+
+		var processed sync.WaitGroup
+
+		for height := 0; height < blocks; height++ {
+			processed.Add(1)
+
+			require.NoError(t, pipeline.enqueue(context.Background(), v.queueFunc(height), func(err error) {
+				assert.NoError(t, err)
+				processed.Done()
+			}))
+
+			close(fetched[height])
+			assert.LessOrEqual(t, pipeline.inFlight(), 3)
+		}
+
+		close(fetched[blocks])
+		processed.Wait()
+
+		assert.True(t, overlapped)
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, v.validatedBlocks())
+	})
+
+	t.Run("waits for a slot when the depth is reached", func(t *testing.T) {
+		release := make(chan struct{})
+		v := newFakeBlockValidator(func(int) { <-release })
+		defer close(v.queue)
+
+		pipeline := newBlockPipeline(2)
+		noop := func(error) {}
+
+		require.NoError(t, pipeline.enqueue(context.Background(), v.queueFunc(0), noop))
+		require.NoError(t, pipeline.enqueue(context.Background(), v.queueFunc(1), noop))
+		assert.Equal(t, 2, pipeline.inFlight())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// the third block is not queued while the first two are being validated
+		err := pipeline.enqueue(ctx, v.queueFunc(2), noop)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(release)
+
+		require.Eventually(t, func() bool { return pipeline.inFlight() == 0 }, time.Second, time.Millisecond)
+		assert.Equal(t, []int{0, 1}, v.validatedBlocks())
+	})
+
+	t.Run("a nil pipeline waits for each block", func(t *testing.T) {
+		v := newFakeBlockValidator(func(int) {})
+		defer close(v.queue)
+
+		var pipeline *blockPipeline
+
+		assert.Nil(t, newBlockPipeline(0))
+
+		for height := 0; height < 3; height++ {
+			processed := false
+
+			require.NoError(t, pipeline.enqueue(context.Background(), v.queueFunc(height), func(error) { processed = true }))
+
+			// the block was processed before enqueue returned
+			assert.True(t, processed)
+		}
+
+		assert.Equal(t, []int{0, 1, 2}, v.validatedBlocks())
+	})
+}
+
+func BenchmarkBlockPipeline(b *testing.B) {
+	const (
+		blocks        = 20
+		fetchDelay    = time.Millisecond
+		validateDelay = time.Millisecond
+	)
+
+	for _, depth := range []int{0, 1, 4, 16} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			v := newFakeBlockValidator(func(int) { time.Sleep(validateDelay) })
+			defer close(v.queue)
+
+			for i := 0; i < b.N; i++ {
+				pipeline := newBlockPipeline(depth)
+
+				var processed sync.WaitGroup
+
+				for height := 0; height < blocks; height++ {
+					// receiving the block from the network
+					time.Sleep(fetchDelay)
+
+					processed.Add(1)
+
+					_ = pipeline.enqueue(context.Background(), v.queueFunc(height), func(error) { processed.Done() })
+				}
+
+				processed.Wait()
+			}
+		})
+	}
+}
@@ -889,8 +889,33 @@ func (sm *SyncManager) handleTxMsg(tmsg *txMsg) {
 	// this is a recursive call, but the orphan pool should be limited in size
 	sm.processOrphanTransactions(ctx, btTx.TxIDChainHash(), &acceptedTxs)
 
-	if len(acceptedTxs) > 0 {
-		sm.peerNotifier.AnnounceNewTransactions(acceptedTxs)
+	sm.relayValidatedTransactions(acceptedTxs)
+}
+
+// relayValidatedTransactions announces transactions that have passed local validation to our peers.
+// When the relay is delayed until the transactions have been included in an assembled subtree, nothing is
+// announced here, the transactions will be announced when the subtree notification is received.
+func (sm *SyncManager) relayValidatedTransactions(acceptedTxs []*TxHashAndFee) {
+	if len(acceptedTxs) == 0 || sm.settings.Legacy.TxRelayAfterSubtree {
+		return
+	}
+
+	sm.peerNotifier.AnnounceNewTransactions(acceptedTxs)
+}
+
+// announceSubtreeTransactions queues all the transactions of an assembled subtree for announcement to our peers.
+// The batcher de-duplicates the transactions that have already been announced after validation.
+func (sm *SyncManager) announceSubtreeTransactions(subtree *subtreepkg.Subtree) {
+	for _, subtreeNode := range subtree.Nodes {
+		if subtreeNode.Hash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
+			continue
+		}
+
+		sm.txAnnounceBatcher.Put(&TxHashAndFee{
+			TxHash: subtreeNode.Hash,
+			Fee:    subtreeNode.Fee,
+			Size:   subtreeNode.SizeInBytes,
+		})
 	}
 }
 
@@ -2235,17 +2260,7 @@ func (sm *SyncManager) startKafkaListeners(ctx context.Context, _ error) {
 
 					// announce all the transactions in the subtree
 					// the batcher should de-duplicate the transactions that have already been sent in the last minute
-					for _, subtreeNode := range subtree.Nodes {
-						if subtreeNode.Hash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
-							continue
-						}
-
-						sm.txAnnounceBatcher.Put(&TxHashAndFee{
-							TxHash: subtreeNode.Hash,
-							Fee:    subtreeNode.Fee,
-							Size:   subtreeNode.SizeInBytes,
-						})
-					}
+					sm.announceSubtreeTransactions(subtree)
 				}
 			}
 		}
@@ -2358,7 +2373,8 @@ func (sm *SyncManager) kafkaTXmetaListener(ctx context.Context, kafkaURL *url.UR
 			return errors.New(errors.ERR_INVALID_ARGUMENT, "Failed to parse tx hash from message", err)
 		}
 
-		if kafkaMsg.Action == kafkamessage.KafkaTxMetaActionType_ADD {
+		// when the relay is delayed until the transaction is in an assembled subtree, it will be announced from the subtree notification
+		if kafkaMsg.Action == kafkamessage.KafkaTxMetaActionType_ADD && !sm.settings.Legacy.TxRelayAfterSubtree {
 			sm.logger.Debugf("Received tx message from Kafka: %v", hash)
 
 			var txMeta meta.Data
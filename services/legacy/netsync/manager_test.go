@@ -11,8 +11,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bsv-blockchain/go-batcher"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	"github.com/bsv-blockchain/go-chaincfg"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
 	txmap "github.com/bsv-blockchain/go-tx-map"
 	"github.com/bsv-blockchain/go-wire"
 	"github.com/bsv-blockchain/teranode/errors"
@@ -748,3 +750,85 @@ func createSpendingTx(prevTx *bsvutil.Tx, index uint32, scriptSig []byte, addres
 
 	return bsvutil.NewTx(spendTx), nil
 }
+
+func TestSyncManager_TxRelayTiming(t *testing.T) {
+	newSyncManager := func(t *testing.T, relayAfterSubtree bool) (*SyncManager, *MockPeerNotifier) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Legacy.TxRelayAfterSubtree = relayAfterSubtree
+
+		peerNotifier := &MockPeerNotifier{
+			announceNewTransactionsChan: make(chan *announceNewTransactionsCall, 10),
+		}
+
+		sm := &SyncManager{
+			settings:     tSettings,
+			logger:       ulogger.TestLogger{},
+			peerNotifier: peerNotifier,
+		}
+
+		sm.txAnnounceBatcher = batcher.NewWithDeduplication[TxHashAndFee](100, 10*time.Millisecond, func(batch []*TxHashAndFee) {
+			sm.peerNotifier.AnnounceNewTransactions(batch)
+		}, true)
+
+		return sm, peerNotifier
+	}
+
+	txHash := chainhash.HashH([]byte("validated tx"))
+	acceptedTxs := []*TxHashAndFee{{TxHash: txHash, Fee: 100, Size: 250}}
+
+	subtree, err := subtreepkg.NewTreeByLeafCount(4)
+	require.NoError(t, err)
+	require.NoError(t, subtree.AddCoinbaseNode())
+	require.NoError(t, subtree.AddNode(txHash, 100, 250))
+
+	t.Run("relay after validation", func(t *testing.T) {
+		sm, peerNotifier := newSyncManager(t, false)
+
+		sm.relayValidatedTransactions(acceptedTxs)
+
+		select {
+		case call := <-peerNotifier.announceNewTransactionsChan:
+			require.Len(t, call.newTxs, 1)
+			assert.Equal(t, txHash, call.newTxs[0].TxHash)
+		case <-time.After(time.Second):
+			t.Fatal("expected the validated transaction to be announced")
+		}
+	})
+
+	t.Run("nothing to relay", func(t *testing.T) {
+		sm, peerNotifier := newSyncManager(t, false)
+
+		sm.relayValidatedTransactions(nil)
+
+		select {
+		case <-peerNotifier.announceNewTransactionsChan:
+			t.Fatal("expected no announcement without accepted transactions")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("relay after subtree", func(t *testing.T) {
+		sm, peerNotifier := newSyncManager(t, true)
+
+		// the validated transaction must not be announced before it is in an assembled subtree
+		sm.relayValidatedTransactions(acceptedTxs)
+
+		select {
+		case <-peerNotifier.announceNewTransactionsChan:
+			t.Fatal("expected the transaction not to be announced before it is in a subtree")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		sm.announceSubtreeTransactions(subtree)
+
+		select {
+		case call := <-peerNotifier.announceNewTransactionsChan:
+			// the coinbase placeholder is never announced
+			require.Len(t, call.newTxs, 1)
+			assert.Equal(t, txHash, call.newTxs[0].TxHash)
+			assert.Equal(t, uint64(100), call.newTxs[0].Fee)
+		case <-time.After(time.Second):
+			t.Fatal("expected the subtree transaction to be announced")
+		}
+	})
+}
@@ -91,6 +91,85 @@ var (
 	userAgentVersion = fmt.Sprintf("%d.%d.%d", version.AppMajor, version.AppMinor, version.AppPatch)
 )
 
+// knownServices is the set of all service flags known to the wire protocol. Configured service
+// bits outside of this set are rejected.
+const knownServices = wire.SFNodeNetwork | wire.SFNodeGetUTXO | wire.SFNodeBloom | wire.SFNodeWitness |
+	wire.SFNodeXthin | wire.SFNodeBitcoinCash | wire.SFNodeGraphene | wire.SFNodeWeakBlocks |
+	wire.SFNodeCF | wire.SFNodeXThinner | wire.SFNodeNetworkLimited
+
+// advertisedUserAgent returns the user agent name and version to advertise in the version
+// handshake. The configured name and version override the defaults. They are validated against
+// the BIP 14 format and, together with the user agent comments, against the maximum user agent
+// length of the version message.
+func advertisedUserAgent(tSettings *settings.Settings, comments []string) (string, string, error) {
+	name := userAgentName
+	if tSettings.Legacy.UserAgentName != "" {
+		if err := validateUserAgentPart("name", tSettings.Legacy.UserAgentName); err != nil {
+			return "", "", err
+		}
+
+		name = "/" + tSettings.Legacy.UserAgentName
+	}
+
+	agentVersion := userAgentVersion
+	if tSettings.Legacy.UserAgentVersion != "" {
+		if err := validateUserAgentPart("version", tSettings.Legacy.UserAgentVersion); err != nil {
+			return "", "", err
+		}
+
+		agentVersion = tSettings.Legacy.UserAgentVersion
+	}
+
+	msg := wire.MsgVersion{}
+	if err := msg.AddUserAgent(name, agentVersion, comments...); err != nil {
+		return "", "", fmt.Errorf("invalid user agent: %w", err)
+	}
+
+	return name, agentVersion, nil
+}
+
+// validateUserAgentPart checks that the given part of the user agent only contains printable
+// characters and none of the characters that are used as separators in the user agent.
+func validateUserAgentPart(part, value string) error {
+	if strings.ContainsAny(value, "/:()") {
+		return fmt.Errorf("the user agent %s %q must not contain any of the characters '/', ':', '(', ')'", part, value)
+	}
+
+	for _, r := range value {
+		if r < 0x20 || r > 0x7e {
+			return fmt.Errorf("the user agent %s %q must only contain printable ASCII characters", part, value)
+		}
+	}
+
+	return nil
+}
+
+// advertisedServices returns the configured service bits to advertise in the version handshake,
+// or the given default services when none have been configured.
+func advertisedServices(tSettings *settings.Settings, defaults wire.ServiceFlag) (wire.ServiceFlag, error) {
+	if tSettings.Legacy.Services == 0 {
+		return defaults, nil
+	}
+
+	services := wire.ServiceFlag(tSettings.Legacy.Services)
+	if unknown := services &^ knownServices; unknown != 0 {
+		return 0, fmt.Errorf("the configured services %d contain unknown service bits %s", tSettings.Legacy.Services, unknown)
+	}
+
+	return services, nil
+}
+
+// maxHeadersPerGetHeaders returns the maximum number of headers sent in response to a getheaders
+// message. The configured maximum is capped at wire.MaxBlockHeadersPerMsg, the maximum number of
+// headers a peer accepts in a single headers message.
+func maxHeadersPerGetHeaders(tSettings *settings.Settings) int {
+	if tSettings.Legacy.MaxHeadersPerGetHeaders <= 0 || tSettings.Legacy.MaxHeadersPerGetHeaders > wire.MaxBlockHeadersPerMsg {
+		return wire.MaxBlockHeadersPerMsg
+	}
+
+	return tSettings.Legacy.MaxHeadersPerGetHeaders
+}
+
 // addrMe specifies the server address to send peers.
 var addrMe *wire.NetAddress
 
@@ -301,6 +380,8 @@ type server struct {
 	nat                  NAT
 	timeSource           blockchain2.MedianTimeSource
 	services             wire.ServiceFlag
+	userAgentName        string
+	userAgentVersion     string
 
 	// cfCheckptCaches stores a cached slice of filter headers for cfcheckpt
 	// messages for each filter type.
@@ -320,6 +401,9 @@ type server struct {
 	assetHTTPAddress  string
 	banList           *p2p.BanList
 	banChan           chan p2p.BanEvent
+	// txRateLimiter limits the transactions accepted per peer address, nil when
+	// legacy_txRateLimitPerPeer is not set.
+	txRateLimiter *util.SourceRateLimiter
 }
 
 // serverPeer extends the peer to maintain state shared by the server and
@@ -351,12 +435,15 @@ type serverPeer struct {
 	// The following chans are used to sync blockmanager and server.
 	txProcessed    chan struct{}
 	blockProcessed chan error
+	// blockPipeline lets the peer read blocks ahead of their validation during the
+	// initial block download, nil when legacy_ibdFetchAheadBlocks is not set.
+	blockPipeline *blockPipeline
 }
 
 // newServerPeer returns a new serverPeer instance. The peer needs to be set by
 // the caller.
 func newServerPeer(s *server, isPersistent bool) *serverPeer {
-	return &serverPeer{
+	sp := &serverPeer{
 		ctx:            s.ctx, // set the context to the server, if server dies, all peers die
 		server:         s,
 		persistent:     isPersistent,
@@ -366,6 +453,12 @@ func newServerPeer(s *server, isPersistent bool) *serverPeer {
 		txProcessed:    make(chan struct{}, 1),
 		blockProcessed: make(chan error, 1),
 	}
+
+	if s.settings != nil {
+		sp.blockPipeline = newBlockPipeline(s.settings.Legacy.IBDFetchAheadBlocks)
+	}
+
+	return sp
 }
 
 // newestBlock returns the current best block hash and height using the format
@@ -647,6 +740,16 @@ func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
 	iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
 	sp.AddKnownInventory(iv)
 
+	// Drop the transactions of a peer over its rate. The request of a dropped
+	// transaction expires in the sync manager, so it can still be fetched from
+	// another peer announcing it.
+	if !sp.allowTx() {
+		sp.server.logger.Debugf("[serverPeer.OnTx][%s] Ignoring tx from %s -- transaction rate limit of %.2f tx/s exceeded",
+			tx.Hash(), sp, sp.server.txRateLimiter.Limit())
+
+		return
+	}
+
 	// Queue the transaction up to be handled by the sync manager and
 	// intentionally block further receives until the transaction is fully
 	// processed and known good or bad.  This helps prevent a malicious peer
@@ -655,8 +758,24 @@ func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
 	sp.server.syncManager.QueueTx(tx, sp.Peer, nil)
 }
 
+// allowTx returns whether a transaction received from the peer is within the
+// transaction rate of its address. Whitelisted peers are not limited.
+func (sp *serverPeer) allowTx() bool {
+	if sp.server.txRateLimiter == nil || sp.isWhitelisted {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(sp.Addr())
+	if err != nil {
+		host = sp.Addr()
+	}
+
+	return sp.server.txRateLimiter.Allow(host)
+}
+
 // OnBlock is invoked when a peer receives a block bitcoin message. It
-// blocks until the bitcoin block has been fully processed.
+// blocks until the bitcoin block has been fully processed, unless blocks are
+// read ahead of validation during the initial block download, see blockPipeline.
 func (sp *serverPeer) OnBlock(_ *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	_, _, _ = tracing.Tracer("legacy").Start(sp.ctx, "serverPeer.OnBlock",
 		tracing.WithHistogram(peerServerMetrics["OnBlock"]),
@@ -699,6 +818,23 @@ func (sp *serverPeer) OnBlock(_ *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 		return
 	}
 
+	if !exists && sp.blockPipeline != nil && !sp.server.syncManager.IsCurrent() {
+		// During the initial block download, read the next blocks of the peer while this
+		// block is validated, up to the fetch-ahead depth. The sync manager still
+		// validates the blocks one at a time, in the order they were queued.
+		if err = sp.blockPipeline.enqueue(sp.ctx, func(done chan error) {
+			sp.server.syncManager.QueueBlock(block, sp.Peer, done)
+		}, func(err error) {
+			if err != nil {
+				sp.server.logger.Errorf("block processing failed: %v", err)
+			}
+		}); err != nil {
+			sp.server.logger.Warnf("block %s from %s not queued for processing: %v", block.Hash(), sp, err)
+		}
+
+		return
+	}
+
 	if !exists {
 		// Queue the block up to be handled by the block
 		// manager and intentionally block further receives
@@ -935,7 +1071,7 @@ func (sp *serverPeer) OnGetHeaders(_ *peer.Peer, msg *wire.MsgGetHeaders) {
 
 	// Find the most recent known block in the best chain based on the block
 	// locator and fetch all the headers after it until either
-	// wire.MaxBlockHeadersPerMsg have been fetched or the provided stop
+	// maxHeadersPerGetHeaders have been fetched or the provided stop
 	// hash is encountered.
 	//
 	// Use the block after the genesis block if no other blocks in the
@@ -950,7 +1086,9 @@ func (sp *serverPeer) OnGetHeaders(_ *peer.Peer, msg *wire.MsgGetHeaders) {
 		return
 	}
 
-	blockHeaders, _, err := sp.server.blockchainClient.GetBlockHeadersFromCommonAncestor(sp.ctx, bestHeader.Hash(), util.HashPointersToValues(msg.BlockLocatorHashes), wire.MaxBlockHeadersPerMsg)
+	maxHeaders := maxHeadersPerGetHeaders(sp.server.settings)
+
+	blockHeaders, _, err := sp.server.blockchainClient.GetBlockHeadersFromCommonAncestor(sp.ctx, bestHeader.Hash(), util.HashPointersToValues(msg.BlockLocatorHashes), uint32(maxHeaders)) // nolint:gosec
 	if err != nil {
 		sp.server.logger.Errorf("Failed to fetch block headers from common ancestor: %v", err)
 	}
@@ -968,7 +1106,7 @@ func (sp *serverPeer) OnGetHeaders(_ *peer.Peer, msg *wire.MsgGetHeaders) {
 
 		wireBlockHeaders = append(wireBlockHeaders, blockHeader.ToWireBlockHeader())
 
-		if len(wireBlockHeaders) >= wire.MaxBlockHeadersPerMsg {
+		if len(wireBlockHeaders) >= maxHeaders {
 			break
 		}
 	}
@@ -2099,8 +2237,8 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		NewestBlock:       sp.newestBlock,
 		HostToNetAddress:  sp.server.addrManager.HostToNetAddress,
 		Proxy:             cfg.Proxy,
-		UserAgentName:     userAgentName,
-		UserAgentVersion:  userAgentVersion,
+		UserAgentName:     sp.server.userAgentName,
+		UserAgentVersion:  sp.server.userAgentVersion,
 		UserAgentComments: cfg.UserAgentComments,
 		ChainParams:       sp.server.settings.ChainCfgParams,
 		Services:          sp.server.services,
@@ -2447,6 +2585,105 @@ cleanup:
 	s.wg.Done()
 }
 
+// tipAdvertisementHandler periodically announces the current best block to the
+// connected peers that are behind it, independent of new block events, so peers
+// that are lagging behind or missed the original announcement get another chance
+// to catch up.
+func (s *server) tipAdvertisementHandler(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.advertiseTip()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// advertiseTip sends the current best block to all connected peers whose
+// advertised height is behind the best block.
+//
+// The announcement is queued on the peers directly instead of going through
+// RelayInventory, which skips peers that already know the block inventory. A
+// peer that is behind has most likely seen the announcement before and missed
+// or dropped it, so it would otherwise never be told about the tip again.
+func (s *server) advertiseTip() {
+	// check listen mode - if listen_only, don't advertise the tip
+	if s.settings.P2P.ListenMode == settings.ListenModeListenOnly {
+		return
+	}
+
+	bestBlockHeader, bestBlockMeta, err := s.blockchainClient.GetBestBlockHeader(s.ctx)
+	if err != nil {
+		s.logger.Errorf("[advertiseTip] unable to fetch best block header: %v", err)
+		return
+	}
+
+	height, err := safeconversion.Uint32ToInt32(bestBlockMeta.Height)
+	if err != nil {
+		s.logger.Errorf("[advertiseTip] invalid best block height %d: %v", bestBlockMeta.Height, err)
+		return
+	}
+
+	invVect := wire.NewInvVect(wire.InvTypeBlock, bestBlockHeader.Hash())
+	blockHeader := bestBlockHeader.ToWireBlockHeader()
+
+	advertised := 0
+
+	for _, sp := range s.getPeers() {
+		if s.advertiseTipToPeer(sp, invVect, blockHeader, height) {
+			advertised++
+		}
+	}
+
+	s.logger.Debugf("[advertiseTip] advertised tip %s at height %d to %d peers", bestBlockHeader.Hash(), height, advertised)
+}
+
+type tipAdvertisementPeer interface {
+	Connected() bool
+	LastBlock() int32
+	WantsHeaders() bool
+	QueueMessage(msg wire.Message, doneChan chan<- struct{})
+}
+
+// advertiseTipToPeer sends the best block to the peer when the peer is connected
+// and its advertised height is below the height of the best block. Peers that
+// prefer headers are sent a headers message, other peers an inventory message.
+// It returns whether the best block was sent to the peer.
+func (s *server) advertiseTipToPeer(sp tipAdvertisementPeer, invVect *wire.InvVect, blockHeader *wire.BlockHeader, height int32) bool {
+	if !sp.Connected() || sp.LastBlock() >= height {
+		return false
+	}
+
+	if sp.WantsHeaders() {
+		msgHeaders := wire.NewMsgHeaders()
+		if err := msgHeaders.AddBlockHeader(blockHeader); err != nil {
+			s.logger.Errorf("[advertiseTip] failed to add block header: %v", err)
+			return false
+		}
+
+		sp.QueueMessage(msgHeaders, nil)
+
+		return true
+	}
+
+	msgInv := wire.NewMsgInvSizeHint(1)
+	if err := msgInv.AddInvVect(invVect); err != nil {
+		s.logger.Errorf("[advertiseTip] failed to add block inventory: %v", err)
+		return false
+	}
+
+	sp.QueueMessage(msgInv, nil)
+
+	return true
+}
+
 // Start begins accepting connections from peers.
 func (s *server) Start() {
 	// Already started?
@@ -2454,6 +2691,11 @@ func (s *server) Start() {
 		return
 	}
 
+	if interval := s.settings.Legacy.TipAdvertisementInterval; interval > 0 {
+		s.wg.Add(1)
+		go s.tipAdvertisementHandler(interval)
+	}
+
 	s.wg.Add(1)
 	s.peerHandler()
 
@@ -2747,6 +2989,16 @@ func newServer(ctx context.Context, logger ulogger.Logger, tSettings *settings.S
 		services |= wire.SFNodeNetworkLimited
 	}
 
+	// configured service bits take precedence over the automatically determined ones
+	if services, err = advertisedServices(tSettings, services); err != nil {
+		return nil, err
+	}
+
+	agentName, agentVersion, err := advertisedUserAgent(tSettings, cfg.UserAgentComments)
+	if err != nil {
+		return nil, err
+	}
+
 	peersDir := cfg.DataDir
 	if !tSettings.Legacy.SavePeers {
 		peersDir = ""
@@ -2761,7 +3013,7 @@ func newServer(ctx context.Context, logger ulogger.Logger, tSettings *settings.S
 	if !cfg.DisableListen {
 		var err error
 
-		listeners, nat, err = initListeners(logger, amgr, listenAddrs, services)
+		listeners, nat, err = initListeners(logger, tSettings.Context, amgr, listenAddrs, services)
 		if err != nil {
 			return nil, err
 		}
@@ -2795,6 +3047,8 @@ func newServer(ctx context.Context, logger ulogger.Logger, tSettings *settings.S
 		nat:                  nat,
 		timeSource:           blockchain2.NewMedianTime(),
 		services:             services,
+		userAgentName:        agentName,
+		userAgentVersion:     agentVersion,
 		sigCache:             txscript.NewSigCache(cfg.SigCacheMaxSize),
 		hashCache:            txscript.NewHashCache(cfg.SigCacheMaxSize),
 		cfCheckptCaches:      make(map[wire.FilterType][]cfHeaderKV),
@@ -2815,6 +3069,7 @@ func newServer(ctx context.Context, logger ulogger.Logger, tSettings *settings.S
 		assetHTTPAddress:  assetHTTPAddress,
 		banList:           banList,
 		banChan:           banChan,
+		txRateLimiter:     util.NewSourceRateLimiter(tSettings.Legacy.TxRateLimitPerPeer, tSettings.Legacy.TxRateLimitBurst),
 	}
 
 	s.syncManager, err = netsync.New(
@@ -2952,8 +3207,9 @@ func newServer(ctx context.Context, logger ulogger.Logger, tSettings *settings.S
 
 // initListeners initializes the configured net listeners and adds any bound
 // addresses to the address manager. Returns the listeners and a NAT interface,
-// which is non-nil if UPnP is in use.
-func initListeners(logger ulogger.Logger, amgr *addrmgr.AddrManager, listenAddrs []string, services wire.ServiceFlag) ([]net.Listener, NAT, error) {
+// which is non-nil if UPnP is in use. The listeners are handed off to a
+// replacement process on a graceful restart.
+func initListeners(logger ulogger.Logger, settingsContext string, amgr *addrmgr.AddrManager, listenAddrs []string, services wire.ServiceFlag) ([]net.Listener, NAT, error) {
 	// Listen for TCP connections at the configured addresses
 	netAddrs, err := parseListeners(listenAddrs)
 	if err != nil {
@@ -2963,7 +3219,7 @@ func initListeners(logger ulogger.Logger, amgr *addrmgr.AddrManager, listenAddrs
 	listeners := make([]net.Listener, 0, len(netAddrs))
 
 	for _, addr := range netAddrs {
-		listener, err := net.Listen(addr.Network(), addr.String())
+		listener, err := util.ListenWithHandoff(settingsContext, "legacy", addr.Network(), addr.String())
 		if err != nil {
 			logger.Warnf("Can't listen on %s: %v", addr, err)
 			continue
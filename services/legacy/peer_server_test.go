@@ -1,16 +1,27 @@
 package legacy
 
 import (
+	"context"
 	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	"github.com/bsv-blockchain/go-chaincfg"
 	"github.com/bsv-blockchain/go-wire"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
 	"github.com/bsv-blockchain/teranode/services/legacy/addrmgr"
 	"github.com/bsv-blockchain/teranode/services/legacy/netsync"
+	"github.com/bsv-blockchain/teranode/services/legacy/peer"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestAddKnownAddresses tests that the addKnownAddresses function properly adds
@@ -753,3 +764,406 @@ func TestMergeCheckpointsFunc(t *testing.T) {
 	assert.Equal(t, int32(300), merged[2].Height)
 	assert.Equal(t, int32(400), merged[3].Height)
 }
+
+// TestTipAdvertisementHandler tests that the best block is advertised to peers on schedule
+func TestTipAdvertisementHandler(t *testing.T) {
+	bestBlockHeader := &model.BlockHeader{
+		Version:        1,
+		HashPrevBlock:  &chainhash.Hash{1},
+		HashMerkleRoot: &chainhash.Hash{2},
+		Timestamp:      1234567890,
+		Nonce:          42,
+	}
+
+	blockchainClient := &blockchain.Mock{}
+	blockchainClient.On("GetBestBlockHeader", mock.Anything).Return(bestBlockHeader, &model.BlockHeaderMeta{Height: 100}, nil)
+
+	s := &server{
+		ctx: context.Background(),
+		settings: &settings.Settings{
+			P2P: settings.P2PSettings{ListenMode: settings.ListenModeFull},
+		},
+		logger:           ulogger.TestLogger{},
+		blockchainClient: blockchainClient,
+		query:            make(chan interface{}),
+		quit:             make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.tipAdvertisementHandler(20 * time.Millisecond)
+
+	// expect the peers to be queried for multiple advertisements of the tip, without any new block events
+	for i := 0; i < 3; i++ {
+		select {
+		case qmsg := <-s.query:
+			msg, ok := qmsg.(getPeersMsg)
+			require.True(t, ok)
+			msg.reply <- nil
+		case <-time.After(time.Second):
+			t.Fatalf("expected tip advertisement %d", i+1)
+		}
+	}
+
+	close(s.quit)
+	s.wg.Wait()
+}
+
+// TestTipAdvertisementListenOnly tests that the tip is not advertised in listen only mode
+func TestTipAdvertisementListenOnly(t *testing.T) {
+	blockchainClient := &blockchain.Mock{}
+
+	s := &server{
+		ctx: context.Background(),
+		settings: &settings.Settings{
+			P2P: settings.P2PSettings{ListenMode: settings.ListenModeListenOnly},
+		},
+		logger:           ulogger.TestLogger{},
+		blockchainClient: blockchainClient,
+		query:            make(chan interface{}, 1),
+	}
+
+	s.advertiseTip()
+
+	select {
+	case <-s.query:
+		t.Fatal("expected no tip advertisement in listen only mode")
+	default:
+	}
+
+	blockchainClient.AssertNotCalled(t, "GetBestBlockHeader", mock.Anything)
+}
+
+type testTipAdvertisementPeer struct {
+	connected    bool
+	lastBlock    int32
+	wantsHeaders bool
+	messages     []wire.Message
+}
+
+func (p *testTipAdvertisementPeer) Connected() bool    { return p.connected }
+func (p *testTipAdvertisementPeer) LastBlock() int32   { return p.lastBlock }
+func (p *testTipAdvertisementPeer) WantsHeaders() bool { return p.wantsHeaders }
+
+func (p *testTipAdvertisementPeer) QueueMessage(msg wire.Message, _ chan<- struct{}) {
+	p.messages = append(p.messages, msg)
+}
+
+// TestAdvertiseTipToPeer tests that the tip is sent directly to the peers that are behind it
+func TestAdvertiseTipToPeer(t *testing.T) {
+	bestBlockHeader := &model.BlockHeader{
+		Version:        1,
+		HashPrevBlock:  &chainhash.Hash{1},
+		HashMerkleRoot: &chainhash.Hash{2},
+		Timestamp:      1234567890,
+		Nonce:          42,
+	}
+
+	invVect := wire.NewInvVect(wire.InvTypeBlock, bestBlockHeader.Hash())
+	blockHeader := bestBlockHeader.ToWireBlockHeader()
+
+	s := &server{logger: ulogger.TestLogger{}}
+
+	t.Run("peer behind is sent the inventory", func(t *testing.T) {
+		sp := &testTipAdvertisementPeer{connected: true, lastBlock: 99}
+
+		// sent again on every advertisement, whether the peer knows the inventory or not
+		assert.True(t, s.advertiseTipToPeer(sp, invVect, blockHeader, 100))
+		assert.True(t, s.advertiseTipToPeer(sp, invVect, blockHeader, 100))
+
+		require.Len(t, sp.messages, 2)
+
+		for _, msg := range sp.messages {
+			msgInv, ok := msg.(*wire.MsgInv)
+			require.True(t, ok)
+			require.Len(t, msgInv.InvList, 1)
+			assert.Equal(t, wire.InvTypeBlock, msgInv.InvList[0].Type)
+			assert.Equal(t, *bestBlockHeader.Hash(), msgInv.InvList[0].Hash)
+		}
+	})
+
+	t.Run("peer behind that prefers headers is sent the header", func(t *testing.T) {
+		sp := &testTipAdvertisementPeer{connected: true, lastBlock: 99, wantsHeaders: true}
+
+		assert.True(t, s.advertiseTipToPeer(sp, invVect, blockHeader, 100))
+
+		require.Len(t, sp.messages, 1)
+
+		msgHeaders, ok := sp.messages[0].(*wire.MsgHeaders)
+		require.True(t, ok)
+		require.Len(t, msgHeaders.Headers, 1)
+		assert.Equal(t, *bestBlockHeader.Hash(), msgHeaders.Headers[0].BlockHash())
+	})
+
+	t.Run("peer at the tip is not sent the tip", func(t *testing.T) {
+		sp := &testTipAdvertisementPeer{connected: true, lastBlock: 100}
+
+		assert.False(t, s.advertiseTipToPeer(sp, invVect, blockHeader, 100))
+		assert.Empty(t, sp.messages)
+	})
+
+	t.Run("disconnected peer is not sent the tip", func(t *testing.T) {
+		sp := &testTipAdvertisementPeer{lastBlock: 99}
+
+		assert.False(t, s.advertiseTipToPeer(sp, invVect, blockHeader, 100))
+		assert.Empty(t, sp.messages)
+	})
+}
+
+// TestAdvertisedUserAgent tests the validation of the configured user agent
+func TestAdvertisedUserAgent(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+
+		name, agentVersion, err := advertisedUserAgent(tSettings, nil)
+		require.NoError(t, err)
+		assert.Equal(t, userAgentName, name)
+		assert.Equal(t, userAgentVersion, agentVersion)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Legacy.UserAgentName = "my-node"
+		tSettings.Legacy.UserAgentVersion = "2.0.0"
+
+		name, agentVersion, err := advertisedUserAgent(tSettings, []string{"EB4000.0"})
+		require.NoError(t, err)
+		assert.Equal(t, "/my-node", name)
+		assert.Equal(t, "2.0.0", agentVersion)
+	})
+
+	t.Run("invalid characters", func(t *testing.T) {
+		for _, value := range []string{"my/node", "my:node", "my(node)", "my\nnode", "my-nöde"} {
+			tSettings := test.CreateBaseTestSettings(t)
+			tSettings.Legacy.UserAgentName = value
+
+			_, _, err := advertisedUserAgent(tSettings, nil)
+			require.Error(t, err, value)
+
+			tSettings = test.CreateBaseTestSettings(t)
+			tSettings.Legacy.UserAgentVersion = value
+
+			_, _, err = advertisedUserAgent(tSettings, nil)
+			require.Error(t, err, value)
+		}
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Legacy.UserAgentName = strings.Repeat("a", wire.MaxUserAgentLen-10)
+
+		_, _, err := advertisedUserAgent(tSettings, []string{"EB4000.0"})
+		require.Error(t, err)
+	})
+}
+
+// TestAdvertisedServices tests the validation of the configured service bits
+func TestAdvertisedServices(t *testing.T) {
+	defaults := wire.SFNodeNetwork | wire.SFNodeBitcoinCash
+
+	t.Run("defaults", func(t *testing.T) {
+		services, err := advertisedServices(test.CreateBaseTestSettings(t), defaults)
+		require.NoError(t, err)
+		assert.Equal(t, defaults, services)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Legacy.Services = uint64(wire.SFNodeNetworkLimited | wire.SFNodeBitcoinCash)
+
+		services, err := advertisedServices(tSettings, defaults)
+		require.NoError(t, err)
+		assert.Equal(t, wire.SFNodeNetworkLimited|wire.SFNodeBitcoinCash, services)
+	})
+
+	t.Run("unknown service bits", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Legacy.Services = uint64(wire.SFNodeNetwork) | 1<<40
+
+		_, err := advertisedServices(tSettings, defaults)
+		require.Error(t, err)
+	})
+}
+
+// TestConfiguredUserAgentAndServicesInHandshake tests that the configured user agent and
+// service bits are advertised to the remote peer in the version handshake
+func TestConfiguredUserAgentAndServicesInHandshake(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Legacy.UserAgentName = "my-node"
+	tSettings.Legacy.UserAgentVersion = "2.0.0"
+	tSettings.Legacy.Services = uint64(wire.SFNodeNetworkLimited | wire.SFNodeBitcoinCash)
+
+	name, agentVersion, err := advertisedUserAgent(tSettings, []string{"EB4000.0"})
+	require.NoError(t, err)
+
+	services, err := advertisedServices(tSettings, defaultServices)
+	require.NoError(t, err)
+
+	verack := make(chan struct{}, 2)
+	listeners := peer.MessageListeners{
+		OnVerAck: func(p *peer.Peer, msg *wire.MsgVerAck) {
+			verack <- struct{}{}
+		},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	localPeer := make(chan *peer.Peer, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		p := peer.NewInboundPeer(ulogger.TestLogger{}, tSettings, &peer.Config{
+			Listeners:              listeners,
+			UserAgentName:          name,
+			UserAgentVersion:       agentVersion,
+			UserAgentComments:      []string{"EB4000.0"},
+			ChainParams:            &chaincfg.RegressionNetParams,
+			Services:               services,
+			TrickleInterval:        time.Second * 10,
+			TstAllowSelfConnection: true,
+		})
+		p.AssociateConnection(conn)
+
+		localPeer <- p
+	}()
+
+	remotePeer, err := peer.NewOutboundPeer(ulogger.TestLogger{}, tSettings, &peer.Config{
+		Listeners:              listeners,
+		UserAgentName:          "remote",
+		UserAgentVersion:       "1.0",
+		ChainParams:            &chaincfg.RegressionNetParams,
+		TrickleInterval:        time.Second * 10,
+		TstAllowSelfConnection: true,
+	}, listener.Addr().String())
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	remotePeer.AssociateConnection(conn)
+
+	defer func() {
+		remotePeer.Disconnect()
+		remotePeer.WaitForDisconnect()
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-verack:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for verack")
+		}
+	}
+
+	p := <-localPeer
+	defer p.Disconnect()
+
+	// the remote peer must see the configured values in the version message it received
+	assert.Equal(t, "/my-node:2.0.0(EB4000.0)/", remotePeer.UserAgent())
+	assert.Equal(t, wire.SFNodeNetworkLimited|wire.SFNodeBitcoinCash, remotePeer.Services())
+}
+
+// TestMaxHeadersPerGetHeaders tests that the headers sent in response to a getheaders message are
+// capped at the configured maximum and at the protocol maximum
+func TestMaxHeadersPerGetHeaders(t *testing.T) {
+	t.Run("limits", func(t *testing.T) {
+		tests := []struct {
+			configured int
+			expected   int
+		}{
+			{configured: 0, expected: wire.MaxBlockHeadersPerMsg},
+			{configured: -1, expected: wire.MaxBlockHeadersPerMsg},
+			{configured: 500, expected: 500},
+			{configured: wire.MaxBlockHeadersPerMsg + 1, expected: wire.MaxBlockHeadersPerMsg},
+		}
+
+		for _, tt := range tests {
+			tSettings := test.CreateBaseTestSettings(t)
+			tSettings.Legacy.MaxHeadersPerGetHeaders = tt.configured
+
+			assert.Equal(t, tt.expected, maxHeadersPerGetHeaders(tSettings), tt.configured)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, wire.MaxBlockHeadersPerMsg, maxHeadersPerGetHeaders(test.CreateBaseTestSettings(t)))
+	})
+
+	t.Run("getheaders requests at most the configured maximum", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Legacy.MaxHeadersPerGetHeaders = 10
+
+		bestBlockHeader := &model.BlockHeader{
+			Version:        1,
+			HashPrevBlock:  &chainhash.Hash{1},
+			HashMerkleRoot: &chainhash.Hash{2},
+		}
+
+		blockchainClient := &blockchain.Mock{}
+		blockchainClient.On("GetBestBlockHeader", mock.Anything).Return(bestBlockHeader, &model.BlockHeaderMeta{}, nil)
+		blockchainClient.On("GetBlockHeadersFromCommonAncestor", mock.Anything, mock.Anything, mock.Anything, uint32(10)).
+			Return([]*model.BlockHeader{}, []*model.BlockHeaderMeta{}, nil).Once()
+
+		sp := &serverPeer{
+			ctx: context.Background(),
+			server: &server{
+				settings:         tSettings,
+				logger:           ulogger.TestLogger{},
+				blockchainClient: blockchainClient,
+			},
+		}
+
+		sp.OnGetHeaders(nil, wire.NewMsgGetHeaders())
+
+		blockchainClient.AssertExpectations(t)
+	})
+}
+
+// TestServerPeerAllowTx tests that the transactions of a peer over the rate of its address are not accepted
+func TestServerPeerAllowTx(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+
+	newPeer := func(t *testing.T, s *server, addr string) *serverPeer {
+		p, err := peer.NewOutboundPeer(ulogger.TestLogger{}, tSettings, &peer.Config{
+			ChainParams: &chaincfg.RegressionNetParams,
+		}, addr)
+		require.NoError(t, err)
+
+		return &serverPeer{Peer: p, server: s}
+	}
+
+	t.Run("not limited", func(t *testing.T) {
+		sp := newPeer(t, &server{logger: ulogger.TestLogger{}}, "192.0.2.1:8333")
+
+		for i := 0; i < 100; i++ {
+			assert.True(t, sp.allowTx())
+		}
+	})
+
+	t.Run("limited per address", func(t *testing.T) {
+		s := &server{logger: ulogger.TestLogger{}, txRateLimiter: util.NewSourceRateLimiter(1, 2)}
+
+		sp1 := newPeer(t, s, "192.0.2.1:8333")
+		sp2 := newPeer(t, s, "192.0.2.1:18333")
+		other := newPeer(t, s, "192.0.2.2:8333")
+
+		// the burst is shared by the connections from the same address
+		assert.True(t, sp1.allowTx())
+		assert.True(t, sp2.allowTx())
+		assert.False(t, sp1.allowTx())
+		assert.False(t, sp2.allowTx())
+
+		// other addresses are not affected
+		assert.True(t, other.allowTx())
+
+		// whitelisted peers are not limited
+		sp1.isWhitelisted = true
+		assert.True(t, sp1.allowTx())
+	})
+}
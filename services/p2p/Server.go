@@ -114,6 +114,7 @@ type Server struct {
 	peerSelector                      *PeerSelector    // Stateless peer selection logic
 	syncCoordinator                   *SyncCoordinator // Orchestrates sync operations
 	syncConnectionTimes               sync.Map         // Map to track when we first connected to each sync peer (peerID -> timestamp)
+	dnsSeeder                         *DNSSeeder       // Discovers peers from DNS seeds, nil when DNS seeding is disabled
 
 	// Cleanup configuration
 	peerMapCleanupTicker    *time.Ticker  // Ticker for periodic cleanup of peer maps
@@ -215,6 +216,14 @@ func NewServer(
 
 	staticPeers := tSettings.P2P.StaticPeers
 
+	var dnsSeeder *DNSSeeder
+
+	// The DNS seeds are queried in the background once the server is started, the peers they return are
+	// connected to at runtime by the p2p client
+	if !tSettings.P2P.DisableDNSSeeds && len(tSettings.P2P.DNSSeeds) > 0 {
+		dnsSeeder = NewDNSSeeder(logger, tSettings.P2P.DNSSeeds, nil)
+	}
+
 	privateKey := tSettings.P2P.PrivateKey
 
 	// Attempt to get the private key if not provided in settings
@@ -327,7 +336,7 @@ func NewServer(
 		conf.Port = tSettings.P2P.Port
 	}
 
-	p2pClient, err := p2pMessageBus.NewClient(conf)
+	p2pClient, err := newReconnectableP2PClient(logger, conf, p2pMessageBus.NewClient)
 	if err != nil {
 		return nil, errors.NewServiceError("failed to create p2p client", err)
 	}
@@ -362,6 +371,7 @@ func NewServer(
 		nodeStatusTopicName:               fmt.Sprintf("%s-%s", topicPrefix, nodeStatusTopic),
 		topicPrefix:                       topicPrefix,
 		startTime:                         time.Now(),
+		dnsSeeder:                         dnsSeeder,
 
 		// Initialize cleanup configuration with defaults
 		peerMapMaxSize: defaultPeerMapMaxSize,
@@ -613,6 +623,11 @@ func (s *Server) Start(ctx context.Context, readyCh chan<- struct{}) error {
 	// Start periodic save of peer registry cache
 	s.startPeerRegistryCacheSave(ctx)
 
+	// Query the DNS seeds in the background to learn new peers
+	if s.dnsSeeder != nil {
+		s.startDNSSeeding(ctx)
+	}
+
 	// Start sync coordinator (it handles all sync logic internally)
 	if s.syncCoordinator != nil {
 		s.syncCoordinator.Start(ctx)
@@ -0,0 +1,227 @@
+package p2p
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+const (
+	// dnsAddrPrefix is the prefix of the TXT records holding peer addresses, following the libp2p dnsaddr convention
+	dnsAddrPrefix = "dnsaddr="
+
+	// dnsSeedLookupTimeout is the maximum time spent querying a single DNS seed
+	dnsSeedLookupTimeout = 30 * time.Second
+)
+
+// DNSSeedResolver looks up the TXT records of a DNS seed host, net.Resolver satisfies this interface.
+type DNSSeedResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// peerConnector is implemented by P2P clients that can connect to peers after the client has been created.
+type peerConnector interface {
+	ConnectToPeers(ctx context.Context, addrs []string) error
+}
+
+// DNSSeeder discovers peer addresses by querying DNS seeds.
+//
+// Each DNS seed publishes the multiaddrs of its peers in TXT records of the form
+// "dnsaddr=/ip4/1.2.3.4/tcp/9906/p2p/<peer id>". Records without a valid multiaddr including the
+// peer ID are ignored, as the peer cannot be connected to without it.
+type DNSSeeder struct {
+	logger   ulogger.Logger
+	resolver DNSSeedResolver
+	seeds    []string
+
+	mu    sync.Mutex
+	known map[string]struct{} // addresses returned by previous queries
+}
+
+// NewDNSSeeder creates a DNS seeder for the given seed hosts, using the default resolver when resolver is nil.
+func NewDNSSeeder(logger ulogger.Logger, seeds []string, resolver DNSSeedResolver) *DNSSeeder {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return &DNSSeeder{
+		logger:   logger,
+		resolver: resolver,
+		seeds:    seeds,
+		known:    make(map[string]struct{}),
+	}
+}
+
+// Seed queries all DNS seeds and returns the sorted, de-duplicated peer addresses they returned.
+// A seed that cannot be queried is logged and skipped, the other seeds are still queried.
+func (d *DNSSeeder) Seed(ctx context.Context) []string {
+	addrs := make(map[string]struct{})
+
+	for _, seed := range d.seeds {
+		seed = strings.TrimSpace(seed)
+		if seed == "" {
+			continue
+		}
+
+		lookupCtx, cancel := context.WithTimeout(ctx, dnsSeedLookupTimeout)
+		records, err := d.resolver.LookupTXT(lookupCtx, seed)
+		cancel()
+
+		if err != nil {
+			d.logger.Warnf("[DNSSeeder] failed to query DNS seed %s: %v", seed, err)
+			continue
+		}
+
+		found := 0
+
+		for _, record := range records {
+			addr, ok := parseDNSSeedRecord(record)
+			if !ok {
+				d.logger.Debugf("[DNSSeeder] ignoring invalid record %q from DNS seed %s", record, seed)
+				continue
+			}
+
+			addrs[addr] = struct{}{}
+			found++
+		}
+
+		d.logger.Infof("[DNSSeeder] DNS seed %s returned %d peer addresses", seed, found)
+	}
+
+	result := make([]string, 0, len(addrs))
+	for addr := range addrs {
+		result = append(result, addr)
+	}
+
+	sort.Strings(result)
+
+	d.mu.Lock()
+	for _, addr := range result {
+		d.known[addr] = struct{}{}
+	}
+	d.mu.Unlock()
+
+	return result
+}
+
+// Start periodically queries the DNS seeds until the context is done, calling onNewPeers with the
+// addresses that were not returned by any previous query.
+func (d *DNSSeeder) Start(ctx context.Context, interval time.Duration, onNewPeers func(ctx context.Context, addrs []string)) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				d.logger.Infof("[DNSSeeder] stopping DNS seed queries")
+				return
+			case <-ticker.C:
+				if newPeers := d.seedNew(ctx); len(newPeers) > 0 {
+					onNewPeers(ctx, newPeers)
+				}
+			}
+		}
+	}()
+
+	d.logger.Infof("[DNSSeeder] querying DNS seeds %v every %v", d.seeds, interval)
+}
+
+// seedNew queries the DNS seeds and returns the addresses not returned by any previous query.
+func (d *DNSSeeder) seedNew(ctx context.Context) []string {
+	d.mu.Lock()
+	known := make(map[string]struct{}, len(d.known))
+	for addr := range d.known {
+		known[addr] = struct{}{}
+	}
+	d.mu.Unlock()
+
+	var newPeers []string
+
+	for _, addr := range d.Seed(ctx) {
+		if _, ok := known[addr]; !ok {
+			newPeers = append(newPeers, addr)
+		}
+	}
+
+	return newPeers
+}
+
+// parseDNSSeedRecord returns the peer multiaddr of a DNS seed TXT record, if the record is valid.
+func parseDNSSeedRecord(record string) (string, bool) {
+	record = strings.TrimSpace(record)
+	if !strings.HasPrefix(record, dnsAddrPrefix) {
+		return "", false
+	}
+
+	addr, err := ma.NewMultiaddr(strings.TrimPrefix(record, dnsAddrPrefix))
+	if err != nil {
+		return "", false
+	}
+
+	if _, err = peer.AddrInfoFromP2pAddr(addr); err != nil {
+		return "", false
+	}
+
+	return addr.String(), true
+}
+
+// mergePeerAddresses returns the addresses of base followed by the addresses of extra not already in base.
+func mergePeerAddresses(base []string, extra []string) []string {
+	merged := make([]string, 0, len(base)+len(extra))
+	seen := make(map[string]struct{}, len(base)+len(extra))
+
+	for _, addrs := range [][]string{base, extra} {
+		for _, addr := range addrs {
+			if _, ok := seen[addr]; ok {
+				continue
+			}
+
+			seen[addr] = struct{}{}
+			merged = append(merged, addr)
+		}
+	}
+
+	return merged
+}
+
+// connectDNSSeedPeers connects to the peers discovered by a DNS seed query.
+func (s *Server) connectDNSSeedPeers(ctx context.Context, addrs []string) {
+	connector, ok := s.P2PClient.(peerConnector)
+	if !ok {
+		s.logger.Warnf("[DNSSeeder] discovered %d new peers, the p2p client does not support connecting to peers at runtime", len(addrs))
+		return
+	}
+
+	if err := connector.ConnectToPeers(ctx, addrs); err != nil {
+		s.logger.Errorf("[DNSSeeder] failed to connect to %d DNS seed peers: %v", len(addrs), err)
+		return
+	}
+
+	s.logger.Infof("[DNSSeeder] connecting to %d DNS seed peers: %v", len(addrs), addrs)
+}
+
+// startDNSSeeding queries the DNS seeds in the background and connects to the peers they return, then keeps
+// re-querying the seeds every DNSSeedInterval when the interval is set.
+func (s *Server) startDNSSeeding(ctx context.Context) {
+	go func() {
+		seedPeers := s.dnsSeeder.Seed(ctx)
+		s.logger.Infof("[P2P] Discovered %d peers from DNS seeds %v", len(seedPeers), s.settings.P2P.DNSSeeds)
+
+		if len(seedPeers) > 0 {
+			s.connectDNSSeedPeers(ctx, seedPeers)
+		}
+
+		if s.settings.P2P.DNSSeedInterval > 0 {
+			s.dnsSeeder.Start(ctx, s.settings.P2P.DNSSeedInterval, s.connectDNSSeedPeers)
+		}
+	}()
+}
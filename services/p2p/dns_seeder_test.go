@@ -0,0 +1,146 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	seedPeer1 = "/ip4/10.0.0.1/tcp/9906/p2p/12D3KooWL1NF6fdTJ9cucEuwvuX8V8KtpJZZnUE4umdLBuK15eUZ"
+	seedPeer2 = "/ip4/10.0.0.2/tcp/9906/p2p/12D3KooWEyX7hgdXy8zUjCs9CqvMGpB5dKVFj9MX2nUBLwajdSZH"
+)
+
+// mockDNSSeedResolver returns the configured TXT records per host
+type mockDNSSeedResolver struct {
+	mu      sync.Mutex
+	records map[string][]string
+	errs    map[string]error
+	lookups int
+}
+
+func (m *mockDNSSeedResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lookups++
+
+	if err, ok := m.errs[name]; ok {
+		return nil, err
+	}
+
+	return m.records[name], nil
+}
+
+func (m *mockDNSSeedResolver) setRecords(name string, records ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[name] = records
+}
+
+func TestDNSSeeder_Seed(t *testing.T) {
+	t.Run("returns peer addresses from all seeds", func(t *testing.T) {
+		resolver := &mockDNSSeedResolver{
+			records: map[string][]string{
+				"seed1.example.com": {"dnsaddr=" + seedPeer2, "dnsaddr=" + seedPeer1},
+				"seed2.example.com": {"dnsaddr=" + seedPeer1},
+			},
+		}
+
+		seeder := NewDNSSeeder(ulogger.TestLogger{}, []string{"seed1.example.com", "seed2.example.com"}, resolver)
+
+		assert.Equal(t, []string{seedPeer1, seedPeer2}, seeder.Seed(context.Background()))
+		assert.Equal(t, 2, resolver.lookups)
+	})
+
+	t.Run("ignores invalid records", func(t *testing.T) {
+		resolver := &mockDNSSeedResolver{
+			records: map[string][]string{
+				"seed.example.com": {
+					"v=spf1 -all",
+					"dnsaddr=not-a-multiaddr",
+					"dnsaddr=/ip4/10.0.0.3/tcp/9906", // no peer id
+					"dnsaddr=" + seedPeer1,
+				},
+			},
+		}
+
+		seeder := NewDNSSeeder(ulogger.TestLogger{}, []string{"seed.example.com"}, resolver)
+
+		assert.Equal(t, []string{seedPeer1}, seeder.Seed(context.Background()))
+	})
+
+	t.Run("failing seed does not stop other seeds", func(t *testing.T) {
+		resolver := &mockDNSSeedResolver{
+			records: map[string][]string{
+				"seed2.example.com": {"dnsaddr=" + seedPeer2},
+			},
+			errs: map[string]error{
+				"seed1.example.com": errors.NewNetworkError("no such host"),
+			},
+		}
+
+		seeder := NewDNSSeeder(ulogger.TestLogger{}, []string{"seed1.example.com", "seed2.example.com"}, resolver)
+
+		assert.Equal(t, []string{seedPeer2}, seeder.Seed(context.Background()))
+	})
+
+	t.Run("no seeds", func(t *testing.T) {
+		resolver := &mockDNSSeedResolver{}
+
+		seeder := NewDNSSeeder(ulogger.TestLogger{}, []string{" "}, resolver)
+
+		assert.Empty(t, seeder.Seed(context.Background()))
+		assert.Equal(t, 0, resolver.lookups)
+	})
+}
+
+func TestDNSSeeder_Start(t *testing.T) {
+	resolver := &mockDNSSeedResolver{
+		records: map[string][]string{
+			"seed.example.com": {"dnsaddr=" + seedPeer1},
+		},
+	}
+
+	seeder := NewDNSSeeder(ulogger.TestLogger{}, []string{"seed.example.com"}, resolver)
+	require.Equal(t, []string{seedPeer1}, seeder.Seed(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newPeersCh := make(chan []string, 10)
+
+	seeder.Start(ctx, 10*time.Millisecond, func(_ context.Context, addrs []string) {
+		newPeersCh <- addrs
+	})
+
+	// only peers not returned by a previous query are reported
+	resolver.setRecords("seed.example.com", "dnsaddr="+seedPeer1, "dnsaddr="+seedPeer2)
+
+	select {
+	case addrs := <-newPeersCh:
+		assert.Equal(t, []string{seedPeer2}, addrs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for new DNS seed peers")
+	}
+
+	// the seed returning the same peers again does not report them
+	select {
+	case addrs := <-newPeersCh:
+		t.Fatalf("unexpected new DNS seed peers %v", addrs)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestMergePeerAddresses(t *testing.T) {
+	assert.Equal(t, []string{seedPeer1, seedPeer2}, mergePeerAddresses([]string{seedPeer1}, []string{seedPeer2, seedPeer1}))
+	assert.Equal(t, []string{seedPeer2}, mergePeerAddresses(nil, []string{seedPeer2}))
+	assert.Empty(t, mergePeerAddresses(nil, nil))
+}
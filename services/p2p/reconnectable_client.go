@@ -0,0 +1,232 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+
+	p2pMessageBus "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+// p2pClientFactory creates a p2p client from its configuration, p2pMessageBus.NewClient satisfies this type.
+type p2pClientFactory func(conf p2pMessageBus.Config) (p2pMessageBus.P2PClient, error)
+
+// reconnectableP2PClient wraps a p2p message bus client and adds peers to it at runtime.
+//
+// The message bus client only connects to peers passed as bootstrap peers when it is created. To connect to
+// peers discovered later, ConnectToPeers recreates the client with the new peers added to its bootstrap peers.
+// The client keeps its private key and therefore its peer ID, and the topic subscriptions are moved to the new
+// client, so the channels returned by Subscribe keep receiving messages across a reconnect.
+type reconnectableP2PClient struct {
+	logger    ulogger.Logger
+	newClient p2pClientFactory
+
+	mu            sync.RWMutex
+	conf          p2pMessageBus.Config
+	client        p2pMessageBus.P2PClient
+	subscriptions map[string][]chan p2pMessageBus.Message
+	stop          chan struct{}  // closed when the current client is replaced or closed
+	forwarders    sync.WaitGroup // forwarders of the current client
+	closed        bool
+}
+
+// newReconnectableP2PClient creates the p2p client for the given configuration.
+func newReconnectableP2PClient(logger ulogger.Logger, conf p2pMessageBus.Config, newClient p2pClientFactory) (*reconnectableP2PClient, error) {
+	client, err := newClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reconnectableP2PClient{
+		logger:        logger,
+		newClient:     newClient,
+		conf:          conf,
+		client:        client,
+		subscriptions: make(map[string][]chan p2pMessageBus.Message),
+		stop:          make(chan struct{}),
+	}, nil
+}
+
+// Start starts the current client.
+func (c *reconnectableP2PClient) Start(ctx context.Context, streamHandler func(network.Stream), topicNames ...string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	starter, ok := c.client.(interface {
+		Start(ctx context.Context, streamHandler func(network.Stream), topicNames ...string) error
+	})
+	if !ok {
+		return nil
+	}
+
+	return starter.Start(ctx, streamHandler, topicNames...)
+}
+
+// Close closes the current client and the channels returned by Subscribe.
+func (c *reconnectableP2PClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+
+	c.stopForwarders()
+
+	err := c.client.Close()
+
+	for _, channels := range c.subscriptions {
+		for _, ch := range channels {
+			close(ch)
+		}
+	}
+
+	return err
+}
+
+// Publish publishes a message on the current client.
+func (c *reconnectableP2PClient) Publish(ctx context.Context, topicName string, msgBytes []byte) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.client.Publish(ctx, topicName, msgBytes)
+}
+
+// Subscribe subscribes to a topic, the returned channel receives the messages of the topic from the current
+// client and from every client that replaces it, until Close is called.
+func (c *reconnectableP2PClient) Subscribe(topicName string) <-chan p2pMessageBus.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan p2pMessageBus.Message, 100)
+
+	if c.closed {
+		close(ch)
+		return ch
+	}
+
+	c.subscriptions[topicName] = append(c.subscriptions[topicName], ch)
+	c.forward(c.client.Subscribe(topicName), ch)
+
+	return ch
+}
+
+// GetID returns the peer ID of the client.
+func (c *reconnectableP2PClient) GetID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.client.GetID()
+}
+
+// GetPeers returns the peers of the current client.
+func (c *reconnectableP2PClient) GetPeers() []p2pMessageBus.PeerInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.client.GetPeers()
+}
+
+// ConnectToPeers connects to the given peer addresses by recreating the client with the addresses added to its
+// bootstrap peers. Addresses that already are bootstrap peers are ignored, the client is not recreated when
+// all addresses are known.
+//
+// The old client is closed before the new client is created, as both listen on the same port. When the new
+// client cannot be created, a client with the previous bootstrap peers is created instead.
+func (c *reconnectableP2PClient) ConnectToPeers(_ context.Context, addrs []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return errors.NewServiceError("[P2P] cannot connect to peers, the p2p client is closed")
+	}
+
+	bootstrapPeers := mergePeerAddresses(c.conf.BootstrapPeers, addrs)
+	if len(bootstrapPeers) == len(c.conf.BootstrapPeers) {
+		return nil
+	}
+
+	c.stopForwarders()
+
+	if err := c.client.Close(); err != nil {
+		c.logger.Warnf("[P2P] error closing p2p client before reconnecting: %v", err)
+	}
+
+	conf := c.conf
+	conf.BootstrapPeers = bootstrapPeers
+
+	client, err := c.newClient(conf)
+	if err != nil {
+		connectErr := errors.NewServiceError("[P2P] failed to create p2p client with %d new peers", len(bootstrapPeers)-len(c.conf.BootstrapPeers), err)
+
+		if client, err = c.newClient(c.conf); err != nil {
+			// keep the closed client, publishing fails until a later reconnect succeeds
+			c.logger.Errorf("%v", connectErr)
+			c.stop = make(chan struct{})
+
+			return errors.NewServiceError("[P2P] failed to recreate p2p client", err)
+		}
+
+		c.client = client
+		c.resubscribe()
+
+		return connectErr
+	}
+
+	c.conf = conf
+	c.client = client
+	c.resubscribe()
+
+	return nil
+}
+
+// stopForwarders stops forwarding the messages of the current client and waits for the forwarders to return.
+// Must be called with the write lock held.
+func (c *reconnectableP2PClient) stopForwarders() {
+	close(c.stop)
+	c.forwarders.Wait()
+}
+
+// resubscribe subscribes the current client to all subscribed topics.
+// Must be called with the write lock held, after stopForwarders.
+func (c *reconnectableP2PClient) resubscribe() {
+	c.stop = make(chan struct{})
+
+	for topicName, channels := range c.subscriptions {
+		for _, ch := range channels {
+			c.forward(c.client.Subscribe(topicName), ch)
+		}
+	}
+}
+
+// forward forwards the messages of a topic channel of the current client to a subscriber channel.
+func (c *reconnectableP2PClient) forward(in <-chan p2pMessageBus.Message, out chan<- p2pMessageBus.Message) {
+	stop := c.stop
+
+	c.forwarders.Add(1)
+
+	go func() {
+		defer c.forwarders.Done()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case msg, ok := <-in:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- msg:
+				case <-stop:
+					return
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,212 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	p2pMessageBus "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeP2PClient records its configuration and delivers messages sent with deliver to its subscribers
+type fakeP2PClient struct {
+	conf p2pMessageBus.Config
+
+	mu       sync.Mutex
+	topics   map[string]chan p2pMessageBus.Message
+	closed   bool
+	messages []string
+}
+
+func (f *fakeP2PClient) Start(_ context.Context, _ func(network.Stream), _ ...string) error {
+	return nil
+}
+
+func (f *fakeP2PClient) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+
+	for _, ch := range f.topics {
+		close(ch)
+	}
+
+	return nil
+}
+
+func (f *fakeP2PClient) Publish(_ context.Context, topicName string, _ []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return errors.NewServiceError("client closed")
+	}
+
+	f.messages = append(f.messages, topicName)
+
+	return nil
+}
+
+func (f *fakeP2PClient) Subscribe(topicName string) <-chan p2pMessageBus.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan p2pMessageBus.Message, 10)
+	f.topics[topicName] = ch
+
+	return ch
+}
+
+func (f *fakeP2PClient) GetID() string {
+	return "fake-peer"
+}
+
+func (f *fakeP2PClient) GetPeers() []p2pMessageBus.PeerInfo {
+	return nil
+}
+
+func (f *fakeP2PClient) deliver(topicName string, data string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.topics[topicName] <- p2pMessageBus.Message{Data: []byte(data)}
+}
+
+// fakeP2PClientFactory creates fake clients and keeps them in creation order
+type fakeP2PClientFactory struct {
+	mu      sync.Mutex
+	clients []*fakeP2PClient
+	err     error
+}
+
+func (f *fakeP2PClientFactory) newClient(conf p2pMessageBus.Config) (p2pMessageBus.P2PClient, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	client := &fakeP2PClient{conf: conf, topics: make(map[string]chan p2pMessageBus.Message)}
+	f.clients = append(f.clients, client)
+
+	return client, nil
+}
+
+func (f *fakeP2PClientFactory) client(i int) *fakeP2PClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.clients[i]
+}
+
+func receiveMessage(t *testing.T, ch <-chan p2pMessageBus.Message) string {
+	t.Helper()
+
+	select {
+	case msg := <-ch:
+		return string(msg.Data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+		return ""
+	}
+}
+
+func TestReconnectableP2PClient_ConnectToPeers(t *testing.T) {
+	t.Run("recreates the client with the new peers and keeps subscriptions", func(t *testing.T) {
+		factory := &fakeP2PClientFactory{}
+
+		client, err := newReconnectableP2PClient(ulogger.TestLogger{}, p2pMessageBus.Config{BootstrapPeers: []string{seedPeer1}}, factory.newClient)
+		require.NoError(t, err)
+
+		ch := client.Subscribe("blocks")
+
+		factory.client(0).deliver("blocks", "before")
+		assert.Equal(t, "before", receiveMessage(t, ch))
+
+		require.NoError(t, client.ConnectToPeers(context.Background(), []string{seedPeer1, seedPeer2}))
+
+		require.Len(t, factory.clients, 2)
+		assert.True(t, factory.client(0).closed)
+		assert.Equal(t, []string{seedPeer1, seedPeer2}, factory.client(1).conf.BootstrapPeers)
+
+		factory.client(1).deliver("blocks", "after")
+		assert.Equal(t, "after", receiveMessage(t, ch))
+
+		require.NoError(t, client.Publish(context.Background(), "blocks", nil))
+		assert.Equal(t, []string{"blocks"}, factory.client(1).messages)
+
+		require.NoError(t, client.Close())
+
+		_, ok := <-ch
+		assert.False(t, ok)
+	})
+
+	t.Run("known peers do not recreate the client", func(t *testing.T) {
+		factory := &fakeP2PClientFactory{}
+
+		client, err := newReconnectableP2PClient(ulogger.TestLogger{}, p2pMessageBus.Config{BootstrapPeers: []string{seedPeer1}}, factory.newClient)
+		require.NoError(t, err)
+
+		require.NoError(t, client.ConnectToPeers(context.Background(), []string{seedPeer1}))
+		assert.Len(t, factory.clients, 1)
+	})
+
+	t.Run("failing client is replaced by a client with the previous peers", func(t *testing.T) {
+		factory := &fakeP2PClientFactory{}
+
+		client, err := newReconnectableP2PClient(ulogger.TestLogger{}, p2pMessageBus.Config{BootstrapPeers: []string{seedPeer1}}, factory.newClient)
+		require.NoError(t, err)
+
+		ch := client.Subscribe("blocks")
+
+		calls := 0
+		client.newClient = func(conf p2pMessageBus.Config) (p2pMessageBus.P2PClient, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.NewServiceError("address already in use")
+			}
+
+			return factory.newClient(conf)
+		}
+
+		require.Error(t, client.ConnectToPeers(context.Background(), []string{seedPeer2}))
+
+		require.Len(t, factory.clients, 2)
+		assert.Equal(t, []string{seedPeer1}, factory.client(1).conf.BootstrapPeers)
+
+		factory.client(1).deliver("blocks", "after")
+		assert.Equal(t, "after", receiveMessage(t, ch))
+	})
+
+	t.Run("closed client", func(t *testing.T) {
+		factory := &fakeP2PClientFactory{}
+
+		client, err := newReconnectableP2PClient(ulogger.TestLogger{}, p2pMessageBus.Config{}, factory.newClient)
+		require.NoError(t, err)
+
+		require.NoError(t, client.Close())
+		require.Error(t, client.ConnectToPeers(context.Background(), []string{seedPeer1}))
+	})
+}
+
+func TestServer_ConnectDNSSeedPeers(t *testing.T) {
+	factory := &fakeP2PClientFactory{}
+
+	client, err := newReconnectableP2PClient(ulogger.TestLogger{}, p2pMessageBus.Config{}, factory.newClient)
+	require.NoError(t, err)
+
+	s := &Server{P2PClient: client, logger: ulogger.TestLogger{}}
+
+	s.connectDNSSeedPeers(context.Background(), []string{seedPeer1})
+
+	require.Len(t, factory.clients, 2)
+	assert.Equal(t, []string{seedPeer1}, factory.client(1).conf.BootstrapPeers)
+}
@@ -26,11 +26,14 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	"github.com/bsv-blockchain/go-wire"
 	"github.com/bsv-blockchain/teranode/errors"
 	"github.com/bsv-blockchain/teranode/pkg/fileformat"
@@ -54,6 +57,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
+	grpcpeer "google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -121,6 +125,8 @@ type PropagationServer struct {
 	validatorKafkaProducerClient kafka.KafkaAsyncProducerI
 	httpServer                   *echo.Echo
 	validatorHTTPAddr            *url.URL
+	txRateLimiter                *util.SourceRateLimiter
+	txScheduler                  *txScheduler
 }
 
 // New creates a new PropagationServer instance with the specified dependencies.
@@ -148,6 +154,7 @@ func New(logger ulogger.Logger, tSettings *settings.Settings, txStore blob.Store
 		blockchainClient:             blockchainClient,
 		validatorKafkaProducerClient: validatorKafkaProducerClient,
 		validatorHTTPAddr:            tSettings.Validator.HTTPAddress,
+		txRateLimiter:                util.NewSourceRateLimiter(tSettings.Propagation.TxRateLimitPerSource, tSettings.Propagation.TxRateLimitBurst),
 	}
 }
 
@@ -262,14 +269,29 @@ func (ps *PropagationServer) HealthGRPC(ctx context.Context, _ *propagation_api.
 }
 
 // Init initializes the PropagationServer.
-// Currently a no-op, reserved for future initialization needs.
+// It checks the configured transaction ordering and, in fee priority mode, starts the
+// scheduler validating the transactions of all requests in fee rate order.
 //
 // Parameters:
 //   - ctx: context for initialization (unused)
 //
 // Returns:
-//   - error: always returns nil in current implementation
+//   - error: ConfigurationError when the transaction ordering is not supported
 func (ps *PropagationServer) Init(_ context.Context) (err error) {
+	switch ps.settings.Propagation.TxOrdering {
+	case settings.TxOrderingFIFO:
+	case settings.TxOrderingFeePriority:
+		workers := ps.settings.Propagation.TxOrderingWorkers
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+
+		ps.txScheduler = newTxScheduler(workers, ps.processTransactionInternal, ps.parentOutputs)
+	default:
+		return errors.NewConfigurationError("propagation_txOrdering %q is not supported, expected %q or %q",
+			ps.settings.Propagation.TxOrdering, settings.TxOrderingFIFO, settings.TxOrderingFeePriority)
+	}
+
 	return nil
 }
 
@@ -421,6 +443,11 @@ func (ps *PropagationServer) StartUDP6Listeners(ctx context.Context, ipv6Address
 						continue
 					}
 
+					if err = ps.checkTxRateLimit(src.IP.String(), 1); err != nil {
+						ps.logger.Warnf("%v", err)
+						continue
+					}
+
 					// Process the received bytes
 					go func(txb []byte) {
 						if _, err = ps.ProcessTransaction(ctx, &propagation_api.ProcessTransactionRequest{
@@ -438,7 +465,7 @@ func (ps *PropagationServer) StartUDP6Listeners(ctx context.Context, ipv6Address
 }
 
 // Stop gracefully stops the PropagationServer.
-// Currently a no-op, reserved for future cleanup operations.
+// In fee priority mode it stops the transaction scheduler once the queued transactions are validated.
 //
 // Parameters:
 //   - ctx: context for stop operation (unused)
@@ -446,6 +473,10 @@ func (ps *PropagationServer) StartUDP6Listeners(ctx context.Context, ipv6Address
 // Returns:
 //   - error: always returns nil in current implementation
 func (ps *PropagationServer) Stop(_ context.Context) error {
+	if ps.txScheduler != nil {
+		ps.txScheduler.stop()
+	}
+
 	return nil
 }
 
@@ -474,6 +505,12 @@ func (ps *PropagationServer) handleSingleTx(_ context.Context) echo.HandlerFunc
 		)
 		defer deferFn()
 
+		ctx = ps.correlationContext(ctx, c.Request().Header.Get(tracing.CorrelationIDHeader))
+
+		if err := ps.checkTxRateLimit(c.RealIP(), 1); err != nil {
+			return c.String(http.StatusTooManyRequests, err.Error())
+		}
+
 		body, err := io.ReadAll(c.Request().Body)
 		if err != nil {
 			return c.String(http.StatusBadRequest, "Invalid request body")
@@ -518,12 +555,17 @@ func (ps *PropagationServer) handleMultipleTx(_ context.Context) echo.HandlerFun
 		)
 		defer deferFn()
 
+		ctx = ps.correlationContext(ctx, c.Request().Header.Get(tracing.CorrelationIDHeader))
+
 		processTxs := make(chan *bt.Tx, maxTransactionsPerRequest)
 		processErrors := make(chan error, maxTransactionsPerRequest)
 		processingWg := sync.WaitGroup{}
 		processingErrorWg := sync.WaitGroup{}
 		totalNrTransactions := 0
 		totalBytesRead := int64(0)
+		source := c.RealIP()
+		feePriority := ps.txScheduler != nil
+		pendingTxs := make([]*bt.Tx, 0)
 
 		go func() {
 			// Process transactions in a separate goroutine
@@ -594,11 +636,38 @@ func (ps *PropagationServer) handleMultipleTx(_ context.Context) echo.HandlerFun
 				return c.String(http.StatusBadRequest, "Invalid request body: too much data")
 			}
 
+			if err = ps.checkTxRateLimit(source, 1); err != nil {
+				processingErrorWg.Add(1)
+				processErrors <- err
+
+				continue
+			}
+
+			// in fee priority mode transactions are only queued once the whole request has been read
+			if feePriority {
+				pendingTxs = append(pendingTxs, tx)
+				continue
+			}
+
 			// Send transaction to processing channel
 			processingWg.Add(1)
 			processTxs <- tx
 		}
 
+		if len(pendingTxs) > 0 {
+			ctxs := make([]context.Context, len(pendingTxs))
+			for i := range ctxs {
+				ctxs[i] = ctx
+			}
+
+			for _, err := range ps.txScheduler.submit(ctxs, pendingTxs) {
+				if err != nil {
+					processingErrorWg.Add(1)
+					processErrors <- err
+				}
+			}
+		}
+
 		processingWg.Wait()
 		processingErrorWg.Wait()
 
@@ -725,7 +794,7 @@ func (ps *PropagationServer) startAndMonitorHTTPServer(ctx context.Context, http
 //
 // 1. Validates transaction format and parses it into a Bitcoin transaction
 // 2. Verifies it's not a coinbase transaction (not allowed for propagation)
-// 3. Ensures the transaction is in extended format (required for processing)
+// 3. Checks the serialization format of the transaction, standard or extended
 // 4. Stores the transaction in the configured blob store for persistence
 // 5. Triggers validation through the appropriate channel (validator service or Kafka)
 // 6. Records performance metrics for monitoring and alerting
@@ -750,6 +819,12 @@ func (ps *PropagationServer) ProcessTransaction(ctx context.Context, req *propag
 		ps.logger.Warnf("[ProcessTransaction] Server received INVALID span context")
 	}
 
+	if err := ps.checkTxRateLimit(grpcSource(ctx), 1); err != nil {
+		return nil, errors.WrapGRPC(err)
+	}
+
+	ctx = ps.correlationContext(ctx, tracing.IncomingCorrelationID(ctx))
+
 	if err := ps.processTransaction(ctx, req); err != nil {
 		ps.logger.Errorf("[ProcessTransaction] failed to process transaction: %v", err)
 
@@ -789,10 +864,37 @@ func (ps *PropagationServer) ProcessTransactionBatch(ctx context.Context, req *p
 	)
 	defer endSpan()
 
+	// every transaction of the batch counts against the rate of the source, the transactions over the rate are
+	// rejected one by one, so a batch larger than the burst is partially accepted instead of never passing
+	source := grpcSource(ctx)
+
+	allowed := ps.allowedTxCount(source, len(req.Items))
+	if allowed == 0 {
+		return nil, errors.WrapGRPC(ps.txRateLimitError(source))
+	}
+
+	ctx = ps.correlationContext(ctx, tracing.IncomingCorrelationID(ctx))
+
 	response := &propagation_api.ProcessTransactionBatchResponse{
 		Errors: make([]*errors.TError, len(req.Items)),
 	}
 
+	if allowed < len(req.Items) {
+		rateLimitErr := errors.Wrap(ps.txRateLimitError(source))
+
+		for idx := allowed; idx < len(req.Items); idx++ {
+			response.Errors[idx] = rateLimitErr
+		}
+
+		req = &propagation_api.ProcessTransactionBatchRequest{Items: req.Items[:allowed]}
+	}
+
+	if ps.txScheduler != nil {
+		ps.processTransactionBatchByFee(ctx, req, response)
+
+		return response, nil
+	}
+
 	g, gCtx := errgroup.WithContext(ctx)
 
 	for idx, item := range req.Items {
@@ -836,6 +938,136 @@ func (ps *PropagationServer) ProcessTransactionBatch(ctx context.Context, req *p
 	return response, nil
 }
 
+// processTransactionBatchByFee validates the transactions of a batch in fee priority mode. The whole batch is
+// parsed first and submitted to the transaction scheduler at once, so the transactions of the batch are validated
+// in fee rate order together with the transactions of the other requests, parents before their children.
+//
+// Parameters:
+//   - ctx: Context of the batch
+//   - req: Batch request containing multiple raw transactions
+//   - response: Response in which the error of every transaction is set
+func (ps *PropagationServer) processTransactionBatchByFee(ctx context.Context, req *propagation_api.ProcessTransactionBatchRequest,
+	response *propagation_api.ProcessTransactionBatchResponse) {
+	txCtxs := make([]context.Context, 0, len(req.Items))
+	txs := make([]*bt.Tx, 0, len(req.Items))
+	idxs := make([]int, 0, len(req.Items))
+
+	for idx, item := range req.Items {
+		btTx, err := ps.parseTransaction(item.Tx)
+		if err != nil {
+			ps.logger.Errorf("[ProcessTransactionBatch] failed to process transaction %d: %v", idx, err)
+
+			response.Errors[idx] = errors.Wrap(err)
+
+			continue
+		}
+
+		txCtx := ctx
+		if len(item.TraceContext) > 0 {
+			txCtx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(item.TraceContext))
+		}
+
+		txCtxs = append(txCtxs, txCtx)
+		txs = append(txs, btTx)
+		idxs = append(idxs, idx)
+	}
+
+	for i, err := range ps.txScheduler.submit(txCtxs, txs) {
+		if err != nil {
+			e := errors.Wrap(err)
+			ps.logger.Errorf("[ProcessTransactionBatch] failed to process transaction %d: %v", idxs[i], e)
+
+			response.Errors[idxs[i]] = e
+
+			continue
+		}
+
+		prometheusTransactionSize.Observe(float64(len(req.Items[idxs[i]].Tx)))
+	}
+}
+
+// checkTxRateLimit checks the given number of transactions against the transaction acceptance rate
+// configured per source. Transactions without a source, submitted in-process, are not limited.
+//
+// Parameters:
+//   - source: Address of the client that submitted the transactions
+//   - n: Number of transactions submitted
+//
+// Returns:
+//   - error: ThresholdExceededError when the source has exceeded its rate, nil otherwise
+func (ps *PropagationServer) checkTxRateLimit(source string, n int) error {
+	if source == "" || ps.txRateLimiter.AllowN(source, n) {
+		return nil
+	}
+
+	prometheusRateLimitedTransactions.Add(float64(n))
+
+	return ps.txRateLimitError(source)
+}
+
+// allowedTxCount returns how many of the given number of transactions are accepted within the transaction
+// acceptance rate configured per source, the first ones being accepted. Transactions without a source,
+// submitted in-process, are not limited.
+//
+// Parameters:
+//   - source: Address of the client that submitted the transactions
+//   - n: Number of transactions submitted
+//
+// Returns:
+//   - int: Number of transactions accepted
+func (ps *PropagationServer) allowedTxCount(source string, n int) int {
+	if source == "" {
+		return n
+	}
+
+	allowed := ps.txRateLimiter.AllowUpTo(source, n)
+	if allowed < n {
+		prometheusRateLimitedTransactions.Add(float64(n - allowed))
+	}
+
+	return allowed
+}
+
+// txRateLimitError returns the error of the transactions rejected because the source exceeded its rate.
+func (ps *PropagationServer) txRateLimitError(source string) error {
+	return errors.NewThresholdExceededError("[ProcessTransaction] transaction rate limit of %.2f tx/s exceeded for source %s, retry later",
+		ps.txRateLimiter.Limit(), source)
+}
+
+// correlationContext returns a context carrying the correlation id received with a transaction, which is passed on
+// to the validator and logged at every stage of the processing of the transaction. The id is ignored when
+// correlation ids are disabled.
+//
+// Parameters:
+//   - ctx: Context of the request
+//   - id: Correlation id received with the request, may be empty
+//
+// Returns:
+//   - context.Context: Context carrying the correlation id
+func (ps *PropagationServer) correlationContext(ctx context.Context, id string) context.Context {
+	if !ps.settings.TracingCorrelationIDsEnabled {
+		return ctx
+	}
+
+	return tracing.ContextWithCorrelationID(ctx, id)
+}
+
+// grpcSource returns the IP address of the client of a gRPC request, or an empty string when the
+// request did not arrive over the network.
+func grpcSource(ctx context.Context) string {
+	p, ok := grpcpeer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
 // processTransaction handles the core transaction processing logic.
 // It validates, stores, and triggers async validation of a transaction,
 // updating metrics throughout the process.
@@ -854,8 +1086,38 @@ func (ps *PropagationServer) processTransaction(ctx context.Context, req *propag
 
 	timeStart := time.Now()
 
-	var btTx *bt.Tx
-	var err error
+	btTx, err := ps.parseTransaction(req.Tx)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if ps.txScheduler != nil {
+		err = ps.txScheduler.submit([]context.Context{ctx}, []*bt.Tx{btTx})[0]
+	} else {
+		err = ps.processTransactionInternal(ctx, btTx)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	prometheusTransactionSize.Observe(float64(len(req.Tx)))
+	prometheusProcessedTransactions.Observe(float64(time.Since(timeStart).Microseconds()) / 1_000_000)
+
+	return nil
+}
+
+// parseTransaction parses a transaction from its bytes, in standard or extended format.
+//
+// Parameters:
+//   - txBytes: serialized transaction
+//
+// Returns:
+//   - *bt.Tx: parsed transaction
+//   - error: ProcessingError if the transaction can not be parsed
+func (ps *PropagationServer) parseTransaction(txBytes []byte) (btTx *bt.Tx, err error) {
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -863,27 +1125,43 @@ func (ps *PropagationServer) processTransaction(ctx context.Context, req *propag
 				ps.logger.Errorf("Recovered from panic in bt.NewTxFromBytes: %v", r)
 			}
 		}()
-		btTx, err = bt.NewTxFromBytes(req.Tx)
+		btTx, err = bt.NewTxFromBytes(txBytes)
 	}()
 
 	if err != nil {
 		prometheusInvalidTransactions.Inc()
 
-		err = errors.NewProcessingError("[ProcessTransaction] failed to parse transaction from bytes", err)
-		span.RecordError(err)
+		return nil, errors.NewProcessingError("[ProcessTransaction] failed to parse transaction from bytes", err)
+	}
 
-		return err
+	return btTx, nil
+}
+
+// parentOutputs returns the outputs of a transaction previously propagated, read from the transaction store,
+// used to compute the fee rate of a transaction that is not extended in fee priority mode.
+//
+// Parameters:
+//   - ctx: context for the read
+//   - hash: hash of the parent transaction
+//
+// Returns:
+//   - []*bt.Output: outputs of the parent, nil when it is not in the transaction store
+func (ps *PropagationServer) parentOutputs(ctx context.Context, hash *chainhash.Hash) []*bt.Output {
+	if ps.txStore == nil {
+		return nil
 	}
 
-	if err = ps.processTransactionInternal(ctx, btTx); err != nil {
-		span.RecordError(err)
-		return err
+	txBytes, err := ps.txStore.Get(ctx, hash.CloneBytes(), fileformat.FileTypeTx)
+	if err != nil {
+		return nil
 	}
 
-	prometheusTransactionSize.Observe(float64(len(req.Tx)))
-	prometheusProcessedTransactions.Observe(float64(time.Since(timeStart).Microseconds()) / 1_000_000)
+	parent, err := bt.NewTxFromBytes(txBytes)
+	if err != nil {
+		return nil
+	}
 
-	return nil
+	return parent.Outputs
 }
 
 // processTransactionInternal performs the core business logic for processing a transaction.
@@ -891,7 +1169,7 @@ func (ps *PropagationServer) processTransaction(ctx context.Context, req *propag
 // the following workflow:
 //
 // 1. Validates that the transaction is not a coinbase transaction (not allowed)
-// 2. Verifies the transaction is in extended format (required for proper processing)
+// 2. Checks the serialization format of the transaction, standard or extended, against the accepted format
 // 3. Stores the transaction in the configured blob store with proper tracing context decoupling
 // 4. Routes the transaction to the appropriate validation path based on size and configuration:
 //   - If Kafka is configured, uses size-based routing:
@@ -917,6 +1195,14 @@ func (ps *PropagationServer) processTransactionInternal(ctx context.Context, btT
 		return errors.NewTxInvalidError("[ProcessTransaction][%s] received coinbase transaction", btTx.TxID())
 	}
 
+	if correlationID := tracing.CorrelationIDFromContext(ctx); correlationID != "" {
+		ps.logger.Infof("[ProcessTransaction][%s] received transaction with correlation id %s", btTx.TxID(), correlationID)
+	}
+
+	if err = ps.checkTxFormat(btTx); err != nil {
+		return err
+	}
+
 	// do some very simple sanity checks on the transaction
 	if err = ps.txSanityChecks(btTx); err != nil {
 		return err
@@ -941,7 +1227,7 @@ func (ps *PropagationServer) processTransactionInternal(ctx context.Context, btT
 		}
 
 		// For normal-sized transactions, continue with Kafka
-		return ps.validateTransactionViaKafka(btTx)
+		return ps.validateTransactionViaKafka(ctx, btTx)
 	} else {
 		ps.logger.Debugf("[ProcessTransaction][%s] Calling validate function", btTx.TxID())
 
@@ -1014,6 +1300,10 @@ func (ps *PropagationServer) validateTransactionViaHTTP(ctx context.Context, btT
 		return errors.NewServiceError("[ProcessTransaction][%s] error creating request to validator /tx endpoint", btTx.TxID(), err)
 	}
 
+	if correlationID := tracing.CorrelationIDFromContext(ctx); correlationID != "" {
+		req.Header.Set(tracing.CorrelationIDHeader, correlationID)
+	}
+
 	// Send the request
 	resp, err := client.Do(req)
 	if err != nil {
@@ -1045,12 +1335,15 @@ func (ps *PropagationServer) validateTransactionViaHTTP(ctx context.Context, btT
 // This asynchronous validation path is generally preferred for normal-sized transactions
 // as it provides better throughput and scalability compared to synchronous HTTP validation.
 //
+// The correlation id of the transaction, if any, is sent in the Kafka message headers.
+//
 // Parameters:
+//   - ctx: Context carrying the correlation id of the transaction
 //   - btTx: Bitcoin transaction to validate
 //
 // Returns:
 //   - error: Error if message preparation or publishing fails
-func (ps *PropagationServer) validateTransactionViaKafka(btTx *bt.Tx) error {
+func (ps *PropagationServer) validateTransactionViaKafka(ctx context.Context, btTx *bt.Tx) error {
 	validationOptions := validator.NewDefaultOptions()
 
 	msg := &kafkamessage.KafkaTxValidationTopicMessage{
@@ -1070,9 +1363,15 @@ func (ps *PropagationServer) validateTransactionViaKafka(btTx *bt.Tx) error {
 	}
 
 	ps.logger.Debugf("[ProcessTransaction][%s] sending transaction to validator kafka channel", btTx.TxID())
+	var headers []sarama.RecordHeader
+	if correlationID := tracing.CorrelationIDFromContext(ctx); correlationID != "" {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(tracing.CorrelationIDHeader), Value: []byte(correlationID)})
+	}
+
 	ps.validatorKafkaProducerClient.Publish(&kafka.Message{
-		Key:   []byte(btTx.TxID()),
-		Value: value,
+		Key:     []byte(btTx.TxID()),
+		Value:   value,
+		Headers: headers,
 	})
 
 	return nil
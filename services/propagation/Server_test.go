@@ -7,13 +7,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
 	"github.com/bsv-blockchain/teranode/services/blockassembly"
 	"github.com/bsv-blockchain/teranode/services/propagation/propagation_api"
 	"github.com/bsv-blockchain/teranode/services/validator"
@@ -26,6 +30,7 @@ import (
 	"github.com/bsv-blockchain/teranode/test/utils/aerospike"
 	"github.com/bsv-blockchain/teranode/test/utils/transactions"
 	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
 	"github.com/bsv-blockchain/teranode/util/test"
 	"github.com/bsv-blockchain/teranode/util/testutil"
 	"github.com/bsv-blockchain/teranode/util/tracing"
@@ -34,6 +39,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/metadata"
+	grpcpeer "google.golang.org/grpc/peer"
 )
 
 type panicReadCloser struct{}
@@ -131,6 +138,122 @@ func Test_handleSingleTx_InvalidBody(t *testing.T) {
 	assert.Contains(t, string(body), "Failed to process transaction:")
 }
 
+// Test_handleSingleTx_RateLimited validates that a source over its transaction rate is throttled and recovers
+func Test_handleSingleTx_RateLimited(t *testing.T) {
+	initPrometheusMetrics()
+
+	tSettings := test.CreateBaseTestSettings(t)
+
+	ps := &PropagationServer{
+		logger:        ulogger.TestLogger{},
+		settings:      tSettings,
+		txRateLimiter: util.NewSourceRateLimiter(10, 1),
+	}
+
+	handler := ps.handleSingleTx(t.Context())
+	e := echo.New()
+
+	post := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodPost, "/tx", bytes.NewReader([]byte{0x01, 0x02, 0x03}))
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+
+		require.NoError(t, handler(e.NewContext(req, rec)))
+
+		return rec.Code
+	}
+
+	// the first transaction is processed, it fails because the bytes are invalid
+	assert.Equal(t, http.StatusInternalServerError, post("192.0.2.1:1234"))
+
+	// the second transaction from the same source is throttled
+	assert.Equal(t, http.StatusTooManyRequests, post("192.0.2.1:1235"))
+
+	// other sources are not affected
+	assert.Equal(t, http.StatusInternalServerError, post("192.0.2.2:1234"))
+
+	// the source recovers once its rate allows a new transaction
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, http.StatusInternalServerError, post("192.0.2.1:1234"))
+}
+
+// TestProcessTransaction_RateLimited validates that gRPC clients over their transaction rate are throttled
+func TestProcessTransaction_RateLimited(t *testing.T) {
+	initPrometheusMetrics()
+	tracing.SetupMockTracer()
+
+	tSettings := test.CreateBaseTestSettings(t)
+
+	ps := &PropagationServer{
+		logger:        ulogger.TestLogger{},
+		settings:      tSettings,
+		txRateLimiter: util.NewSourceRateLimiter(1, 1),
+	}
+
+	ctx := grpcpeer.NewContext(context.Background(), &grpcpeer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234},
+	})
+
+	req := &propagation_api.ProcessTransactionRequest{Tx: []byte{0x00, 0x01, 0x02}}
+
+	_, err := ps.ProcessTransaction(ctx, req)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "rate limit")
+
+	_, err = ps.ProcessTransaction(ctx, req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transaction rate limit of 1.00 tx/s exceeded for source 192.0.2.1")
+
+	// batches count every transaction against the rate of the source
+	_, err = ps.ProcessTransactionBatch(ctx, &propagation_api.ProcessTransactionBatchRequest{
+		Items: []*propagation_api.BatchTransactionItem{{Tx: req.Tx}, {Tx: req.Tx}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limit")
+}
+
+// TestProcessTransactionBatch_RateLimited validates that a batch larger than the burst is partially accepted
+func TestProcessTransactionBatch_RateLimited(t *testing.T) {
+	initPrometheusMetrics()
+	tracing.SetupMockTracer()
+
+	tSettings := test.CreateBaseTestSettings(t)
+
+	txStore, err := null.New(ulogger.TestLogger{})
+	require.NoError(t, err)
+
+	ps := &PropagationServer{
+		logger:        ulogger.TestLogger{},
+		settings:      tSettings,
+		txStore:       txStore,
+		txRateLimiter: util.NewSourceRateLimiter(1, 2),
+	}
+
+	ctx := grpcpeer.NewContext(context.Background(), &grpcpeer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234},
+	})
+
+	invalidTx := []byte{0x00, 0x01, 0x02}
+
+	resp, err := ps.ProcessTransactionBatch(ctx, &propagation_api.ProcessTransactionBatchRequest{
+		Items: []*propagation_api.BatchTransactionItem{{Tx: invalidTx}, {Tx: invalidTx}, {Tx: invalidTx}, {Tx: invalidTx}},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Errors, 4)
+
+	// the transactions within the burst are processed, they fail because the bytes are invalid
+	for _, txErr := range resp.Errors[:2] {
+		require.NotNil(t, txErr)
+		assert.NotContains(t, txErr.Error(), "rate limit")
+	}
+
+	// the transactions over the burst are rejected
+	for _, txErr := range resp.Errors[2:] {
+		require.NotNil(t, txErr)
+		assert.Contains(t, txErr.Error(), "transaction rate limit of 1.00 tx/s exceeded for source 192.0.2.1")
+	}
+}
+
 // TestProcessTransaction_InvalidBytes validates gRPC method error path on invalid bytes
 func TestProcessTransaction_InvalidBytes(t *testing.T) {
 	// Initialize tracing for tests
@@ -722,6 +845,162 @@ func Test_handleMultipleTx(t *testing.T) {
 	})
 }
 
+func TestInit_TxOrdering(t *testing.T) {
+	for _, ordering := range []string{settings.TxOrderingFIFO, settings.TxOrderingFeePriority} {
+		t.Run(ordering, func(t *testing.T) {
+			tSettings := test.CreateBaseTestSettings(t)
+			tSettings.Propagation.TxOrdering = ordering
+
+			ps := New(ulogger.TestLogger{}, tSettings, nil, nil, nil, nil)
+			require.NoError(t, ps.Init(t.Context()))
+
+			assert.Equal(t, ordering == settings.TxOrderingFeePriority, ps.txScheduler != nil)
+
+			require.NoError(t, ps.Stop(t.Context()))
+		})
+	}
+
+	t.Run("unknown ordering is rejected", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Propagation.TxOrdering = "fee"
+
+		err := New(ulogger.TestLogger{}, tSettings, nil, nil, nil, nil).Init(t.Context())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrConfiguration)
+	})
+}
+
+// correlationLogger records the info messages logged by all stages of the processing of a transaction
+type correlationLogger struct {
+	ulogger.TestLogger
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *correlationLogger) Infof(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (l *correlationLogger) messagesContaining(s string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	found := make([]string, 0)
+
+	for _, message := range l.messages {
+		if strings.Contains(message, s) {
+			found = append(found, message)
+		}
+	}
+
+	return found
+}
+
+func TestProcessTransaction_CorrelationID(t *testing.T) {
+	initPrometheusMetrics()
+
+	const correlationID = "debug-tx-1234"
+
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Propagation.GRPCListenAddress = ""
+	tSettings.Propagation.HTTPListenAddress = ""
+
+	txs := transactions.CreateTestTransactionChainWithCount(t, 3)
+
+	t.Run("id is logged by propagation, validation and block assembly", func(t *testing.T) {
+		logger := &correlationLogger{}
+
+		utxoStore := testutil.NewSQLiteMemoryUTXOStore(t.Context(), logger, tSettings, t)
+		_ = utxoStore.SetBlockHeight(101)
+
+		_, err := utxoStore.Create(t.Context(), txs[0], 1)
+		require.NoError(t, err)
+
+		blockAssemblyClient := blockassembly.NewMock()
+		blockAssemblyClient.On("Store", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+		validatorInstance, err := validator.New(t.Context(), logger, tSettings, utxoStore, nil, nil, blockAssemblyClient, nil)
+		require.NoError(t, err)
+
+		txStore, err := null.New(logger)
+		require.NoError(t, err)
+
+		ps := &PropagationServer{
+			logger:    logger,
+			validator: validatorInstance,
+			txStore:   txStore,
+			settings:  tSettings,
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/tx", bytes.NewReader(txs[1].ExtendedBytes()))
+		req.Header.Set(tracing.CorrelationIDHeader, correlationID)
+		rec := httptest.NewRecorder()
+
+		require.NoError(t, ps.handleSingleTx(t.Context())(echo.New().NewContext(req, rec)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		txID := txs[1].TxID()
+
+		for _, stage := range []string{
+			"[ProcessTransaction][" + txID + "] received transaction",
+			"[Validate][" + txID + "] accepted transaction",
+			"[Validator][" + txID + "] sending transaction",
+		} {
+			found := logger.messagesContaining(stage)
+			if assert.Len(t, found, 1, stage) {
+				assert.Contains(t, found[0], correlationID)
+			}
+		}
+
+		// the id is passed on to block assembly with the request
+		require.Len(t, blockAssemblyClient.Calls, 1)
+
+		storeCtx, ok := blockAssemblyClient.Calls[0].Arguments.Get(0).(context.Context)
+		require.True(t, ok)
+		assert.Equal(t, correlationID, tracing.CorrelationIDFromContext(storeCtx))
+	})
+
+	t.Run("id is sent to the validator in the kafka message headers", func(t *testing.T) {
+		txStore, err := null.New(ulogger.TestLogger{})
+		require.NoError(t, err)
+
+		producer := &MockKafkaProducer{}
+
+		ps := &PropagationServer{
+			logger:                       ulogger.TestLogger{},
+			txStore:                      txStore,
+			settings:                     tSettings,
+			validatorKafkaProducerClient: producer,
+		}
+
+		ctx := ps.correlationContext(t.Context(), correlationID)
+
+		require.NoError(t, ps.processTransactionInternal(ctx, txs[2]))
+		require.Len(t, producer.PublishedMessages, 1)
+
+		headers := producer.PublishedMessages[0].Headers
+		require.Len(t, headers, 1)
+		assert.Equal(t, tracing.CorrelationIDHeader, string(headers[0].Key))
+		assert.Equal(t, correlationID, string(headers[0].Value))
+	})
+
+	t.Run("id is ignored when disabled", func(t *testing.T) {
+		disabledSettings := test.CreateBaseTestSettings(t)
+		disabledSettings.TracingCorrelationIDsEnabled = false
+
+		ps := &PropagationServer{settings: disabledSettings}
+
+		assert.Empty(t, tracing.CorrelationIDFromContext(ps.correlationContext(t.Context(), correlationID)))
+
+		// the id received in the metadata of a gRPC request is not used either
+		grpcCtx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(tracing.CorrelationIDHeader, correlationID))
+		assert.Empty(t, tracing.CorrelationIDFromContext(ps.correlationContext(grpcCtx, tracing.IncomingCorrelationID(grpcCtx))))
+	})
+}
+
 func testProcessTransactionInternal(t *testing.T, utxoStoreURL string) {
 	initPrometheusMetrics()
 
@@ -43,6 +43,8 @@ var (
 	prometheusProcessedHandleMultipleTx prometheus.Histogram
 	prometheusTransactionSize           prometheus.Histogram
 	prometheusInvalidTransactions       prometheus.Counter
+	prometheusRateLimitedTransactions   prometheus.Counter
+	prometheusTransactionFormat         *prometheus.CounterVec
 )
 
 // Synchronization primitive for ensuring metrics are initialized exactly once.
@@ -130,4 +132,25 @@ func _initPrometheusMetrics() {
 			Help:      "Number of transactions found invalid by the propagation service",
 		},
 	)
+
+	prometheusRateLimitedTransactions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "propagation",
+			Name:      "rate_limited_transactions",
+			Help:      "Number of transactions rejected because their source exceeded the transaction rate limit",
+		},
+	)
+
+	prometheusTransactionFormat = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "propagation",
+			Name:      "transaction_format",
+			Help:      "Number of transactions received per serialization format",
+		},
+		[]string{
+			"format", // standard or extended
+		},
+	)
 }
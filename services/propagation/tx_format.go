@@ -0,0 +1,52 @@
+package propagation
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/settings"
+)
+
+// txFormat returns the serialization format a transaction was received in. The format is detected while parsing,
+// a transaction in extended format carries the satoshis and locking script of the output spent by every input.
+func txFormat(btTx *bt.Tx) string {
+	if btTx.IsExtended() {
+		return settings.TxFormatExtended
+	}
+
+	return settings.TxFormatStandard
+}
+
+// checkTxFormat checks the serialization format of a received transaction against the format accepted by the
+// propagation service.
+//
+// The validator only looks up the previous outputs of a transaction in the utxo store when they are not part of
+// the transaction. When extended transactions are not trusted, the previous outputs are removed from a transaction
+// in extended format, so the validator resolves them from the utxo store like for a transaction in standard format.
+//
+// Parameters:
+//   - btTx: Parsed transaction, its previous outputs are removed when they are not trusted
+//
+// Returns:
+//   - error: TxInvalidError when the transaction is not in an accepted format
+func (ps *PropagationServer) checkTxFormat(btTx *bt.Tx) error {
+	format := txFormat(btTx)
+
+	prometheusTransactionFormat.WithLabelValues(format).Inc()
+
+	accepted := ps.settings.Propagation.TxFormat
+	if accepted != "" && accepted != settings.TxFormatAuto && accepted != format {
+		prometheusInvalidTransactions.Inc()
+		return errors.NewTxInvalidError("[ProcessTransaction][%s] received transaction in %s format, only %s format is accepted", btTx.TxID(), format, accepted)
+	}
+
+	if format == settings.TxFormatExtended && !ps.settings.Propagation.TrustExtendedTx {
+		for _, input := range btTx.Inputs {
+			input.PreviousTxSatoshis = 0
+			input.PreviousTxScript = nil
+		}
+
+		btTx.SetExtended(false)
+	}
+
+	return nil
+}
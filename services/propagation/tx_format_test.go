@@ -0,0 +1,120 @@
+package propagation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/test/utils/transactions"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxFormatRoundTrip(t *testing.T) {
+	txs := transactions.CreateTestTransactionChainWithCount(t, 3)
+	tx := txs[1]
+
+	require.True(t, tx.IsExtended())
+
+	tests := []struct {
+		name    string
+		txBytes []byte
+		format  string
+	}{
+		{name: "standard", txBytes: tx.Bytes(), format: settings.TxFormatStandard},
+		{name: "extended", txBytes: tx.ExtendedBytes(), format: settings.TxFormatExtended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the /tx endpoint and gRPC parse a single transaction from bytes
+			parsedTx, err := bt.NewTxFromBytes(tt.txBytes)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.format, txFormat(parsedTx))
+			assert.Equal(t, tx.TxID(), parsedTx.TxID())
+			assert.Equal(t, tt.txBytes, parsedTx.SerializeBytes())
+
+			// the /txs endpoint reads the transactions from a stream
+			streamedTx := &bt.Tx{}
+
+			_, err = streamedTx.ReadFrom(bytes.NewReader(tt.txBytes))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.format, txFormat(streamedTx))
+			assert.Equal(t, tt.txBytes, streamedTx.SerializeBytes())
+		})
+	}
+}
+
+func TestCheckTxFormat(t *testing.T) {
+	initPrometheusMetrics()
+
+	txs := transactions.CreateTestTransactionChainWithCount(t, 3)
+
+	newServer := func(t *testing.T, format string, trustExtendedTx bool) *PropagationServer {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Propagation.TxFormat = format
+		tSettings.Propagation.TrustExtendedTx = trustExtendedTx
+
+		return &PropagationServer{
+			logger:   ulogger.TestLogger{},
+			settings: tSettings,
+		}
+	}
+
+	parse := func(t *testing.T, txBytes []byte) *bt.Tx {
+		parsedTx, err := bt.NewTxFromBytes(txBytes)
+		require.NoError(t, err)
+
+		return parsedTx
+	}
+
+	t.Run("auto accepts both formats", func(t *testing.T) {
+		ps := newServer(t, settings.TxFormatAuto, true)
+
+		require.NoError(t, ps.checkTxFormat(parse(t, txs[1].Bytes())))
+
+		extendedTx := parse(t, txs[1].ExtendedBytes())
+		require.NoError(t, ps.checkTxFormat(extendedTx))
+
+		// trusted previous outputs are kept
+		assert.True(t, extendedTx.IsExtended())
+		assert.Equal(t, txs[1].ExtendedBytes(), extendedTx.SerializeBytes())
+	})
+
+	t.Run("standard only", func(t *testing.T) {
+		ps := newServer(t, settings.TxFormatStandard, true)
+
+		require.NoError(t, ps.checkTxFormat(parse(t, txs[1].Bytes())))
+
+		err := ps.checkTxFormat(parse(t, txs[1].ExtendedBytes()))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxInvalid))
+	})
+
+	t.Run("extended only", func(t *testing.T) {
+		ps := newServer(t, settings.TxFormatExtended, true)
+
+		require.NoError(t, ps.checkTxFormat(parse(t, txs[1].ExtendedBytes())))
+
+		err := ps.checkTxFormat(parse(t, txs[1].Bytes()))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxInvalid))
+	})
+
+	t.Run("untrusted previous outputs are removed", func(t *testing.T) {
+		ps := newServer(t, settings.TxFormatAuto, false)
+
+		extendedTx := parse(t, txs[1].ExtendedBytes())
+		require.NoError(t, ps.checkTxFormat(extendedTx))
+
+		assert.False(t, extendedTx.IsExtended())
+		assert.Equal(t, txs[1].TxID(), extendedTx.TxID())
+		assert.Equal(t, txs[1].Bytes(), extendedTx.SerializeBytes())
+	})
+}
@@ -0,0 +1,287 @@
+package propagation
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// unknownFeeRate is the fee rate of a transaction spending an output whose value is not known, it is validated
+// after all transactions with a known fee rate
+const unknownFeeRate = -1
+
+// scheduledTx is a transaction waiting in the txScheduler for validation
+type scheduledTx struct {
+	ctx  context.Context
+	tx   *bt.Tx
+	rate float64
+	seq  uint64
+
+	// parents is the number of transactions this transaction spends from that are still waiting for validation,
+	// children are the waiting transactions spending from this transaction
+	parents  int
+	children []*scheduledTx
+
+	done chan error
+}
+
+// txScheduler validates the transactions submitted by all requests in fee priority order, the transaction with
+// the highest fee rate first, keeping the submission order of transactions paying the same rate. A transaction is
+// never validated before a parent submitted before it, or in the same request, has been validated, whatever the
+// fee rates, so children paying a high fee do not fail with a missing parent.
+type txScheduler struct {
+	process       func(ctx context.Context, tx *bt.Tx) error
+	parentOutputs func(ctx context.Context, hash *chainhash.Hash) []*bt.Output
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	ready   scheduledTxHeap
+	pending map[chainhash.Hash]*scheduledTx
+	seq     uint64
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// newTxScheduler creates a scheduler validating the transactions with process, in workers goroutines. The values
+// of the outputs spent by transactions that are not extended are resolved from the waiting transactions first,
+// then with parentOutputs, which returns nil when the parent is not known.
+func newTxScheduler(workers int, process func(ctx context.Context, tx *bt.Tx) error,
+	parentOutputs func(ctx context.Context, hash *chainhash.Hash) []*bt.Output) *txScheduler {
+	s := &txScheduler{
+		process:       process,
+		parentOutputs: parentOutputs,
+		pending:       make(map[chainhash.Hash]*scheduledTx),
+	}
+
+	s.cond = sync.NewCond(&s.mu)
+
+	for i := 0; i < max(workers, 1); i++ {
+		s.wg.Add(1)
+
+		go s.worker()
+	}
+
+	return s
+}
+
+// stop stops the workers once the transactions already submitted have been validated.
+func (s *txScheduler) stop() {
+	s.mu.Lock()
+	s.stopped = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// submit queues the transactions of a request, each validated with its own context, and waits for their
+// validation, returning the error of every transaction in request order.
+func (s *txScheduler) submit(ctxs []context.Context, txs []*bt.Tx) []error {
+	inRequest := make(map[chainhash.Hash]*bt.Tx, len(txs))
+	for _, tx := range txs {
+		inRequest[*tx.TxIDChainHash()] = tx
+	}
+
+	items := make([]*scheduledTx, len(txs))
+
+	for i, tx := range txs {
+		items[i] = &scheduledTx{
+			ctx:  ctxs[i],
+			tx:   tx,
+			rate: s.feeRate(ctxs[i], tx, inRequest),
+			done: make(chan error, 1),
+		}
+	}
+
+	s.mu.Lock()
+
+	if s.stopped {
+		s.mu.Unlock()
+
+		errs := make([]error, len(txs))
+		for i, item := range items {
+			errs[i] = item.ctx.Err()
+			if errs[i] == nil {
+				errs[i] = context.Canceled
+			}
+		}
+
+		return errs
+	}
+
+	// register the whole request first, so a parent following its child in the request is found
+	for _, item := range items {
+		s.seq++
+		item.seq = s.seq
+
+		if _, ok := s.pending[*item.tx.TxIDChainHash()]; !ok {
+			s.pending[*item.tx.TxIDChainHash()] = item
+		}
+	}
+
+	for _, item := range items {
+		seen := make(map[chainhash.Hash]struct{}, len(item.tx.Inputs))
+
+		for _, input := range item.tx.Inputs {
+			parentHash := *input.PreviousTxIDChainHash()
+
+			if _, ok := seen[parentHash]; ok {
+				continue
+			}
+
+			seen[parentHash] = struct{}{}
+
+			if parent, ok := s.pending[parentHash]; ok && parent != item {
+				parent.children = append(parent.children, item)
+				item.parents++
+			}
+		}
+	}
+
+	for _, item := range items {
+		if item.parents == 0 {
+			heap.Push(&s.ready, item)
+			s.cond.Signal()
+		}
+	}
+
+	s.mu.Unlock()
+
+	errs := make([]error, len(items))
+	for i, item := range items {
+		errs[i] = <-item.done
+	}
+
+	return errs
+}
+
+// worker validates the ready transactions with the highest fee rate, until the scheduler is stopped.
+func (s *txScheduler) worker() {
+	defer s.wg.Done()
+
+	for {
+		s.mu.Lock()
+
+		for s.ready.Len() == 0 && !s.stopped {
+			s.cond.Wait()
+		}
+
+		if s.ready.Len() == 0 {
+			s.mu.Unlock()
+			return
+		}
+
+		item := heap.Pop(&s.ready).(*scheduledTx)
+
+		s.mu.Unlock()
+
+		err := s.process(item.ctx, item.tx)
+
+		s.mu.Lock()
+
+		if s.pending[*item.tx.TxIDChainHash()] == item {
+			delete(s.pending, *item.tx.TxIDChainHash())
+		}
+
+		// the children are validated even when the parent failed, their validation reports the missing parent
+		for _, child := range item.children {
+			child.parents--
+
+			if child.parents == 0 {
+				heap.Push(&s.ready, child)
+				s.cond.Signal()
+			}
+		}
+
+		s.mu.Unlock()
+
+		item.done <- err
+	}
+}
+
+// feeRate returns the fee rate of a transaction in satoshis per byte. The values of the outputs spent by a
+// transaction that is not extended are taken from the transactions of the request, the waiting transactions, or
+// the known parents. The fee rate is unknownFeeRate when the value of an output spent is not known, and zero when
+// the transaction spends less than it outputs.
+func (s *txScheduler) feeRate(ctx context.Context, tx *bt.Tx, inRequest map[chainhash.Hash]*bt.Tx) float64 {
+	var inputSatoshis uint64
+
+	if tx.IsExtended() {
+		inputSatoshis = tx.TotalInputSatoshis()
+	} else {
+		parents := make(map[chainhash.Hash][]*bt.Output, len(tx.Inputs))
+
+		for _, input := range tx.Inputs {
+			parentHash := *input.PreviousTxIDChainHash()
+
+			outputs, ok := parents[parentHash]
+			if !ok {
+				outputs = s.outputsOf(ctx, &parentHash, inRequest)
+				parents[parentHash] = outputs
+			}
+
+			if int(input.PreviousTxOutIndex) >= len(outputs) || outputs[input.PreviousTxOutIndex] == nil {
+				return unknownFeeRate
+			}
+
+			inputSatoshis += outputs[input.PreviousTxOutIndex].Satoshis
+		}
+	}
+
+	outputSatoshis := tx.TotalOutputSatoshis()
+	if inputSatoshis <= outputSatoshis {
+		return 0
+	}
+
+	return float64(inputSatoshis-outputSatoshis) / float64(tx.Size())
+}
+
+// outputsOf returns the outputs of a parent transaction, or nil when the parent is not known.
+func (s *txScheduler) outputsOf(ctx context.Context, hash *chainhash.Hash, inRequest map[chainhash.Hash]*bt.Tx) []*bt.Output {
+	if parent, ok := inRequest[*hash]; ok {
+		return parent.Outputs
+	}
+
+	s.mu.Lock()
+	parent, ok := s.pending[*hash]
+	s.mu.Unlock()
+
+	if ok {
+		return parent.tx.Outputs
+	}
+
+	if s.parentOutputs == nil {
+		return nil
+	}
+
+	return s.parentOutputs(ctx, hash)
+}
+
+// scheduledTxHeap orders the ready transactions by fee rate, highest first, then by submission order.
+type scheduledTxHeap []*scheduledTx
+
+func (h scheduledTxHeap) Len() int { return len(h) }
+
+func (h scheduledTxHeap) Less(i, j int) bool {
+	if h[i].rate != h[j].rate {
+		return h[i].rate > h[j].rate
+	}
+
+	return h[i].seq < h[j].seq
+}
+
+func (h scheduledTxHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *scheduledTxHeap) Push(x any) { *h = append(*h, x.(*scheduledTx)) }
+
+func (h *scheduledTxHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+
+	return item
+}
@@ -0,0 +1,206 @@
+package propagation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxScheduler(t *testing.T) {
+	const lockingScript = "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"
+
+	// newTx creates an extended transaction spending an output of the given previous transaction
+	newTx := func(t *testing.T, prevTxID string, inputSatoshis, outputSatoshis uint64) *bt.Tx {
+		tx := bt.NewTx()
+		require.NoError(t, tx.From(prevTxID, 0, lockingScript, inputSatoshis))
+
+		tx.AddOutput(&bt.Output{Satoshis: outputSatoshis, LockingScript: tx.Inputs[0].PreviousTxScript})
+
+		return tx
+	}
+
+	// notExtended returns the transaction in standard format, without the outputs spent by its inputs
+	notExtended := func(t *testing.T, tx *bt.Tx) *bt.Tx {
+		standard, err := bt.NewTxFromBytes(tx.Bytes())
+		require.NoError(t, err)
+		require.False(t, standard.IsExtended())
+
+		return standard
+	}
+
+	// newScheduler creates a scheduler with a single worker recording the order of validation, the first
+	// transaction validated blocks the worker until release is called
+	newScheduler := func(t *testing.T, parentOutputs func(ctx context.Context, hash *chainhash.Hash) []*bt.Output) (s *txScheduler, validated func() []*bt.Tx, release func()) {
+		var (
+			mu      sync.Mutex
+			order   []*bt.Tx
+			started = make(chan struct{})
+			gate    = make(chan struct{})
+			once    sync.Once
+		)
+
+		s = newTxScheduler(1, func(_ context.Context, tx *bt.Tx) error {
+			once.Do(func() {
+				close(started)
+				<-gate
+			})
+
+			mu.Lock()
+			order = append(order, tx)
+			mu.Unlock()
+
+			return nil
+		}, parentOutputs)
+
+		t.Cleanup(s.stop)
+
+		// block the worker with a first transaction, so the following requests are queued together
+		go s.submit([]context.Context{t.Context()}, []*bt.Tx{newTx(t, fmt.Sprintf("%064x", 0xff), 1_000, 1_000)})
+		<-started
+
+		validated = func() []*bt.Tx {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return append([]*bt.Tx(nil), order[1:]...)
+		}
+
+		return s, validated, func() { close(gate) }
+	}
+
+	// submitAsync submits a request without waiting for its validation
+	submitAsync := func(t *testing.T, s *txScheduler, wg *sync.WaitGroup, txs ...*bt.Tx) {
+		s.mu.Lock()
+		queued := s.seq
+		s.mu.Unlock()
+
+		ctxs := make([]context.Context, len(txs))
+		for i := range ctxs {
+			ctxs[i] = t.Context()
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for _, err := range s.submit(ctxs, txs) {
+				assert.NoError(t, err)
+			}
+		}()
+
+		require.Eventually(t, func() bool {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			return s.seq == queued+uint64(len(txs))
+		}, time.Second, time.Millisecond)
+	}
+
+	t.Run("transactions of all requests are validated by fee rate", func(t *testing.T) {
+		s, validated, release := newScheduler(t, nil)
+
+		// all transactions have the same size, so the fee rate follows the fee
+		lowFee := newTx(t, fmt.Sprintf("%064x", 1), 1_000, 990)
+		highFee := newTx(t, fmt.Sprintf("%064x", 2), 1_000, 500)
+		noFee := newTx(t, fmt.Sprintf("%064x", 3), 1_000, 1_000)
+		mediumFee := newTx(t, fmt.Sprintf("%064x", 4), 1_000, 900)
+		sameMediumFee := newTx(t, fmt.Sprintf("%064x", 5), 2_000, 1_900)
+
+		var wg sync.WaitGroup
+
+		submitAsync(t, s, &wg, lowFee, noFee)
+		submitAsync(t, s, &wg, mediumFee, highFee)
+		submitAsync(t, s, &wg, sameMediumFee)
+
+		release()
+		wg.Wait()
+
+		assert.Equal(t, []*bt.Tx{highFee, mediumFee, sameMediumFee, lowFee, noFee}, validated())
+	})
+
+	t.Run("parents are validated before their children", func(t *testing.T) {
+		s, validated, release := newScheduler(t, nil)
+
+		parent := newTx(t, fmt.Sprintf("%064x", 1), 1_000, 990)
+		child := newTx(t, parent.TxID(), 990, 100)
+		grandChild := newTx(t, child.TxID(), 100, 10)
+		other := newTx(t, fmt.Sprintf("%064x", 2), 1_000, 900)
+
+		var wg sync.WaitGroup
+
+		// the child paying the highest fee comes first in the request, its parent pays the lowest fee
+		submitAsync(t, s, &wg, child, parent, other)
+		submitAsync(t, s, &wg, grandChild)
+
+		release()
+		wg.Wait()
+
+		assert.Equal(t, []*bt.Tx{other, parent, child, grandChild}, validated())
+	})
+
+	t.Run("fee rate of transactions not extended is resolved from their parents", func(t *testing.T) {
+		known := newTx(t, fmt.Sprintf("%064x", 1), 1_000, 1_000)
+
+		s, validated, release := newScheduler(t, func(_ context.Context, hash *chainhash.Hash) []*bt.Output {
+			if *hash == *known.TxIDChainHash() {
+				return known.Outputs
+			}
+
+			return nil
+		})
+
+		parent := newTx(t, fmt.Sprintf("%064x", 2), 1_000, 1_000)
+		childOfQueued := notExtended(t, newTx(t, parent.TxID(), 1_000, 700))
+		childOfKnown := notExtended(t, newTx(t, known.TxID(), 1_000, 500))
+		childOfUnknown := notExtended(t, newTx(t, fmt.Sprintf("%064x", 3), 1_000, 100))
+		lowFee := newTx(t, fmt.Sprintf("%064x", 4), 1_000, 990)
+
+		var wg sync.WaitGroup
+
+		submitAsync(t, s, &wg, parent)
+		submitAsync(t, s, &wg, childOfUnknown, lowFee, childOfQueued, childOfKnown)
+
+		release()
+		wg.Wait()
+
+		// the fee of the transaction spending an unknown output is not known, it is validated last
+		assert.Equal(t, []*bt.Tx{childOfKnown, lowFee, parent, childOfQueued, childOfUnknown}, validated())
+	})
+
+	t.Run("errors are returned in request order", func(t *testing.T) {
+		failing := newTx(t, fmt.Sprintf("%064x", 1), 1_000, 900)
+		valid := newTx(t, fmt.Sprintf("%064x", 2), 1_000, 500)
+
+		s := newTxScheduler(2, func(_ context.Context, tx *bt.Tx) error {
+			if tx == failing {
+				return errors.NewTxInvalidError("invalid")
+			}
+
+			return nil
+		}, nil)
+		defer s.stop()
+
+		errs := s.submit([]context.Context{t.Context(), t.Context()}, []*bt.Tx{failing, valid})
+		require.Len(t, errs, 2)
+
+		assert.ErrorIs(t, errs[0], errors.ErrTxInvalid)
+		assert.NoError(t, errs[1])
+	})
+
+	t.Run("stopped scheduler rejects transactions", func(t *testing.T) {
+		s := newTxScheduler(1, func(context.Context, *bt.Tx) error { return nil }, nil)
+		s.stop()
+
+		errs := s.submit([]context.Context{t.Context()}, []*bt.Tx{newTx(t, fmt.Sprintf("%064x", 1), 1_000, 900)})
+		assert.ErrorIs(t, errs[0], context.Canceled)
+	})
+}
@@ -73,6 +73,7 @@ import (
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util"
 	"github.com/bsv-blockchain/teranode/util/health"
+	"github.com/bsv-blockchain/teranode/util/tracing"
 	"github.com/ordishs/gocore"
 	"go.opentelemetry.io/otel"
 	otelPropagation "go.opentelemetry.io/otel/propagation"
@@ -154,63 +155,65 @@ type commandHandler func(context.Context, *RPCServer, interface{}, <-chan struct
 // a dependency loop.
 var rpcHandlers map[string]commandHandler
 var rpcHandlersBeforeInit = map[string]commandHandler{
-	"addnode":               handleUnimplemented,
-	"createrawtransaction":  handleCreateRawTransaction,
-	"debuglevel":            handleUnimplemented,
-	"decoderawtransaction":  handleUnimplemented,
-	"decodescript":          handleUnimplemented,
-	"estimatefee":           handleUnimplemented,
-	"generate":              handleGenerate,
-	"generatetoaddress":     handleGenerateToAddress,
-	"getaddednodeinfo":      handleUnimplemented,
-	"getbestblock":          handleUnimplemented,
-	"getbestblockhash":      handleGetBestBlockHash,
-	"getblock":              handleGetBlock,
-	"getblockbyheight":      handleGetBlockByHeight,
-	"getblockchaininfo":     handleGetblockchaininfo,
-	"getblockcount":         handleUnimplemented,
-	"getblockhash":          handleGetBlockHash,
-	"getblockheader":        handleGetBlockHeader,
-	"getblocktemplate":      handleUnimplemented,
-	"getcfilter":            handleUnimplemented,
-	"getcfilterheader":      handleUnimplemented,
-	"getchaintips":          handleGetchaintips,
-	"getconnectioncount":    handleUnimplemented,
-	"getcurrentnet":         handleUnimplemented,
-	"getdifficulty":         handleGetDifficulty,
-	"getgenerate":           handleUnimplemented,
-	"gethashespersec":       handleUnimplemented,
-	"getheaders":            handleUnimplemented,
-	"getinfo":               handleGetInfo,
-	"getmempoolinfo":        handleUnimplemented,
-	"getmininginfo":         handleGetMiningInfo,
-	"getnettotals":          handleUnimplemented,
-	"getnetworkhashps":      handleUnimplemented,
-	"getpeerinfo":           handleGetpeerinfo,
-	"getrawmempool":         handleGetRawMempool,
-	"getrawtransaction":     handleGetRawTransaction,
-	"gettxout":              handleUnimplemented,
-	"gettxoutproof":         handleUnimplemented,
-	"help":                  handleHelp,
-	"node":                  handleUnimplemented,
-	"ping":                  handleUnimplemented,
-	"invalidateblock":       handleInvalidateBlock,
-	"isbanned":              handleIsBanned,
-	"listbanned":            handleListBanned,
-	"clearbanned":           handleClearBanned,
-	"reconsiderblock":       handleReconsiderBlock,
-	"searchrawtransactions": handleUnimplemented,
-	"sendrawtransaction":    handleSendRawTransaction,
-	"setban":                handleSetBan,
-	"setgenerate":           handleUnimplemented,
-	"stop":                  handleStop,
-	"submitblock":           handleUnimplemented,
-	"uptime":                handleUnimplemented,
-	"validateaddress":       handleUnimplemented,
-	"verifychain":           handleUnimplemented,
-	"verifymessage":         handleUnimplemented,
-	"verifytxoutproof":      handleUnimplemented,
-	"version":               handleVersion,
+	"addnode":                handleUnimplemented,
+	"createrawtransaction":   handleCreateRawTransaction,
+	"debuglevel":             handleUnimplemented,
+	"decoderawtransaction":   handleUnimplemented,
+	"decodescript":           handleUnimplemented,
+	"diagnoserawtransaction": handleDiagnoseRawTransaction,
+	"estimatefee":            handleUnimplemented,
+	"generate":               handleGenerate,
+	"generatetoaddress":      handleGenerateToAddress,
+	"getaddednodeinfo":       handleUnimplemented,
+	"getbestblock":           handleUnimplemented,
+	"getbestblockhash":       handleGetBestBlockHash,
+	"getblock":               handleGetBlock,
+	"getblockbyheight":       handleGetBlockByHeight,
+	"getblockchaininfo":      handleGetblockchaininfo,
+	"getblockcount":          handleUnimplemented,
+	"getblockhash":           handleGetBlockHash,
+	"getblockheader":         handleGetBlockHeader,
+	"getblocktemplate":       handleUnimplemented,
+	"getcfilter":             handleUnimplemented,
+	"getcfilterheader":       handleUnimplemented,
+	"getchaintips":           handleGetchaintips,
+	"getconnectioncount":     handleUnimplemented,
+	"getcurrentnet":          handleUnimplemented,
+	"getdifficulty":          handleGetDifficulty,
+	"getgenerate":            handleUnimplemented,
+	"gethashespersec":        handleUnimplemented,
+	"getheaders":             handleUnimplemented,
+	"getinfo":                handleGetInfo,
+	"getmempoolinfo":         handleUnimplemented,
+	"getmininginfo":          handleGetMiningInfo,
+	"getnettotals":           handleUnimplemented,
+	"getnetworkhashps":       handleUnimplemented,
+	"getoutputsbyvalue":      handleGetOutputsByValue,
+	"getpeerinfo":            handleGetpeerinfo,
+	"getrawmempool":          handleGetRawMempool,
+	"getrawtransaction":      handleGetRawTransaction,
+	"gettxout":               handleUnimplemented,
+	"gettxoutproof":          handleUnimplemented,
+	"help":                   handleHelp,
+	"node":                   handleUnimplemented,
+	"ping":                   handleUnimplemented,
+	"invalidateblock":        handleInvalidateBlock,
+	"isbanned":               handleIsBanned,
+	"listbanned":             handleListBanned,
+	"clearbanned":            handleClearBanned,
+	"reconsiderblock":        handleReconsiderBlock,
+	"searchrawtransactions":  handleUnimplemented,
+	"sendrawtransaction":     handleSendRawTransaction,
+	"setban":                 handleSetBan,
+	"setgenerate":            handleUnimplemented,
+	"stop":                   handleStop,
+	"submitblock":            handleUnimplemented,
+	"uptime":                 handleUnimplemented,
+	"validateaddress":        handleUnimplemented,
+	"verifychain":            handleUnimplemented,
+	"verifymessage":          handleUnimplemented,
+	"verifytxoutproof":       handleUnimplemented,
+	"version":                handleVersion,
 	// BSV mining methods
 	"getminingcandidate":   handleGetMiningCandidate,
 	"submitminingsolution": handleSubmitMiningSolution,
@@ -695,6 +698,61 @@ type RPCServer struct {
 	// validatorClient provides access to the transaction validator service
 	// Used for synchronous transaction validation in sendrawtransaction RPC
 	validatorClient validator.Interface
+
+	// txRateLimiter limits the rate of transactions accepted via sendrawtransaction per client
+	// A nil limiter, the default, does not limit the rate
+	txRateLimiter *util.SourceRateLimiter
+
+	// outputValueIndex indexes the unspent outputs by value for the getoutputsbyvalue command
+	// A nil index, the default, disables the command
+	outputValueIndex *outputValueIndex
+
+	// txDiagnoser validates transactions in diagnostic mode for the diagnoserawtransaction command
+	// Created on first use, see diagnosticValidator
+	txDiagnoser     *validator.Validator
+	txDiagnoserOnce sync.Once
+
+	// unconfirmedTxTracker alerts on transactions sent via sendrawtransaction that are not mined in time,
+	// nil when rpc_unconfirmedTxAlertAge is not set
+	unconfirmedTxTracker *unconfirmedTxTracker
+}
+
+// diagnosticValidator returns the validator used by the diagnoserawtransaction command, creating it
+// with the policy and consensus settings of the node and the UTXO store of the server on first use.
+func (s *RPCServer) diagnosticValidator() *validator.Validator {
+	s.txDiagnoserOnce.Do(func() {
+		if s.txDiagnoser == nil {
+			s.txDiagnoser = validator.NewDiagnosticValidator(s.logger, s.settings, s.utxoStore)
+		}
+	})
+
+	return s.txDiagnoser
+}
+
+// checkTxRateLimit checks a transaction submitted by the client with the given remote address
+// against the transaction rate configured per client.
+//
+// Parameters:
+//   - remoteAddr: The IP address and port of the client submitting the transaction
+//
+// Returns:
+//   - error: *bsvjson.RPCError when the client has exceeded its rate, nil otherwise
+func (s *RPCServer) checkTxRateLimit(remoteAddr string) error {
+	source, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		source = remoteAddr
+	}
+
+	if s.txRateLimiter.Allow(source) {
+		return nil
+	}
+
+	s.logger.Warnf("RPC client %s exceeded the transaction rate limit", source)
+
+	return &bsvjson.RPCError{
+		Code:    bsvjson.ErrRPCMisc,
+		Message: fmt.Sprintf("transaction rate limit of %.2f tx/s exceeded, retry later", s.txRateLimiter.Limit()),
+	}
 }
 
 // httpStatusLine returns a response Status-Line (RFC 2616 Section 6.1)
@@ -1183,6 +1241,11 @@ func (s *RPCServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 			}
 		}
 
+		// Throttle clients that submit transactions faster than their configured rate
+		if jsonErr == nil && request.Method == "sendrawtransaction" {
+			jsonErr = s.checkTxRateLimit(r.RemoteAddr)
+		}
+
 		if jsonErr == nil {
 			// Attempt to parse the JSON-RPC request into a known concrete
 			// command.
@@ -1190,7 +1253,12 @@ func (s *RPCServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 			if parsedCmd.err != nil {
 				jsonErr = parsedCmd.err
 			} else {
-				result, jsonErr = s.standardCmdResult(r.Context(), parsedCmd, closeChan)
+				ctx := r.Context()
+				if s.settings.TracingCorrelationIDsEnabled {
+					ctx = tracing.ContextWithCorrelationID(ctx, r.Header.Get(tracing.CorrelationIDHeader))
+				}
+
+				result, jsonErr = s.standardCmdResult(ctx, parsedCmd, closeChan)
 			}
 		}
 	}
@@ -1282,6 +1350,16 @@ func (s *RPCServer) Start(ctx context.Context, readyCh chan<- struct{}) error {
 
 	s.logger.Infof("Starting RPC server")
 
+	if s.outputValueIndex != nil {
+		if err := s.startOutputValueIndex(ctx); err != nil {
+			return errors.NewServiceError("failed to start output value index", err)
+		}
+	}
+
+	if s.unconfirmedTxTracker != nil {
+		go s.unconfirmedTxTracker.Start(ctx)
+	}
+
 	rpcServeMux := http.NewServeMux()
 	httpServer := &http.Server{
 		Handler: rpcServeMux,
@@ -1413,6 +1491,7 @@ func NewServer(logger ulogger.Logger, tSettings *settings.Settings, blockchainCl
 		p2pClient:              p2pClient,
 		txStore:                txStore,
 		validatorClient:        validatorClient,
+		txRateLimiter:          util.NewSourceRateLimiter(tSettings.RPC.TxRateLimitPerClient, tSettings.RPC.TxRateLimitBurst),
 	}
 
 	rpcUser := tSettings.RPC.RPCUser
@@ -1448,6 +1527,18 @@ func NewServer(logger ulogger.Logger, tSettings *settings.Settings, blockchainCl
 	}
 	// rpc.cfg.Chain.Subscribe(rpc.handleBlockchainNotification)
 
+	if tSettings.RPC.OutputValueIndexEnabled {
+		rpc.outputValueIndex = newOutputValueIndex(outputValueIndexUndoDepth)
+	}
+
+	if tSettings.RPC.UnconfirmedTxAlertAge > 0 {
+		rpc.unconfirmedTxTracker = newUnconfirmedTxTracker(logger, utxoStore, tSettings.RPC.UnconfirmedTxAlertAge)
+
+		if tSettings.RPC.UnconfirmedTxMaxAlerts > 0 {
+			rpc.unconfirmedTxTracker.limiter = newAlertLimiter(tSettings.RPC.UnconfirmedTxMaxAlerts, tSettings.RPC.UnconfirmedTxAlertInterval)
+		}
+	}
+
 	rpc.rpcMaxClients = tSettings.RPC.RPCMaxClients
 
 	rpc.rpcQuirks = tSettings.RPC.RPCQuirks
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"time"
+)
+
+// alertLimiter limits the number of alerts raised per interval, to prevent alert storms during a cascading
+// failure. The alerts over the limit are not raised, they are coalesced into a count per kind of alert, which is
+// reported once the interval ended.
+//
+// An alertLimiter is not safe for concurrent use.
+type alertLimiter struct {
+	maxAlerts int
+	interval  time.Duration
+
+	windowStart time.Time
+	raised      int
+	coalesced   map[string]int
+}
+
+// newAlertLimiter creates a limiter raising at most maxAlerts alerts per interval, a maxAlerts of 0 or less
+// raises every alert.
+func newAlertLimiter(maxAlerts int, interval time.Duration) *alertLimiter {
+	return &alertLimiter{
+		maxAlerts: maxAlerts,
+		interval:  interval,
+		coalesced: make(map[string]int),
+	}
+}
+
+// allow returns whether an alert of the given kind is raised at the given time, or coalesced because the maximum
+// number of alerts of the interval was raised. A nil limiter raises every alert.
+func (l *alertLimiter) allow(kind string, now time.Time) bool {
+	if l == nil || l.maxAlerts <= 0 {
+		return true
+	}
+
+	if l.windowStart.IsZero() || now.Sub(l.windowStart) >= l.interval {
+		l.windowStart = now
+		l.raised = 0
+	}
+
+	if l.raised < l.maxAlerts {
+		l.raised++
+		return true
+	}
+
+	l.coalesced[kind]++
+
+	return false
+}
+
+// flush returns the number of alerts coalesced per kind, once the interval they were coalesced in ended at the
+// given time, or nil when there is nothing to report yet.
+func (l *alertLimiter) flush(now time.Time) map[string]int {
+	if l == nil || len(l.coalesced) == 0 || now.Sub(l.windowStart) < l.interval {
+		return nil
+	}
+
+	coalesced := l.coalesced
+	l.coalesced = make(map[string]int)
+
+	return coalesced
+}
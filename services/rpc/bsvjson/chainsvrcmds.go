@@ -86,6 +86,19 @@ func NewDecodeRawTransactionCmd(hexTx string) *DecodeRawTransactionCmd {
 	}
 }
 
+// DiagnoseRawTransactionCmd defines the diagnoserawtransaction JSON-RPC command.
+type DiagnoseRawTransactionCmd struct {
+	HexTx string
+}
+
+// NewDiagnoseRawTransactionCmd returns a new instance which can be used to issue
+// a diagnoserawtransaction JSON-RPC command.
+func NewDiagnoseRawTransactionCmd(hexTx string) *DiagnoseRawTransactionCmd {
+	return &DiagnoseRawTransactionCmd{
+		HexTx: hexTx,
+	}
+}
+
 // DecodeScriptCmd defines the decodescript JSON-RPC command.
 type DecodeScriptCmd struct {
 	HexScript string
@@ -412,6 +425,28 @@ func NewGetNetworkInfoCmd() *GetNetworkInfoCmd {
 	return &GetNetworkInfoCmd{}
 }
 
+// GetOutputsByValueCmd defines the getoutputsbyvalue JSON-RPC command.
+type GetOutputsByValueCmd struct {
+	Min    uint64
+	Max    uint64
+	Limit  *int
+	Cursor *string
+}
+
+// NewGetOutputsByValueCmd returns a new instance which can be used to issue a
+// getoutputsbyvalue JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetOutputsByValueCmd(minValue, maxValue uint64, limit *int, cursor *string) *GetOutputsByValueCmd {
+	return &GetOutputsByValueCmd{
+		Min:    minValue,
+		Max:    maxValue,
+		Limit:  limit,
+		Cursor: cursor,
+	}
+}
+
 // GetNetTotalsCmd defines the getnettotals JSON-RPC command.
 type GetNetTotalsCmd struct{}
 
@@ -871,6 +906,7 @@ func init() {
 	MustRegisterCmd("createrawtransaction", (*CreateRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("decoderawtransaction", (*DecodeRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("decodescript", (*DecodeScriptCmd)(nil), flags)
+	MustRegisterCmd("diagnoserawtransaction", (*DiagnoseRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("getaddednodeinfo", (*GetAddedNodeInfoCmd)(nil), flags)
 	MustRegisterCmd("getbestblockhash", (*GetBestBlockHashCmd)(nil), flags)
 	MustRegisterCmd("getblock", (*GetBlockCmd)(nil), flags)
@@ -894,6 +930,7 @@ func init() {
 	MustRegisterCmd("getminingcandidate", (*GetMiningCandidateCmd)(nil), flags)
 	MustRegisterCmd("getnetworkinfo", (*GetNetworkInfoCmd)(nil), flags)
 	MustRegisterCmd("getnettotals", (*GetNetTotalsCmd)(nil), flags)
+	MustRegisterCmd("getoutputsbyvalue", (*GetOutputsByValueCmd)(nil), flags)
 	MustRegisterCmd("getnetworkhashps", (*GetNetworkHashPSCmd)(nil), flags)
 	MustRegisterCmd("getpeerinfo", (*GetPeerInfoCmd)(nil), flags)
 	MustRegisterCmd("getrawmempool", (*GetRawMempoolCmd)(nil), flags)
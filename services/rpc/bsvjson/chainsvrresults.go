@@ -308,6 +308,41 @@ type GetTxOutResult struct {
 	Coinbase      bool               `json:"coinbase"`
 }
 
+// GetOutputsByValueResult models the data returned from the getoutputsbyvalue
+// command. Cursor is set when more outputs match, it is passed to the next
+// call to continue after the last output returned.
+type GetOutputsByValueResult struct {
+	Outputs []GetOutputsByValueOutput `json:"outputs"`
+	Cursor  string                    `json:"cursor,omitempty"`
+}
+
+// GetOutputsByValueOutput models an unspent output returned from the
+// getoutputsbyvalue command.
+type GetOutputsByValueOutput struct {
+	TxID   string `json:"txid"`
+	Vout   uint32 `json:"vout"`
+	Value  uint64 `json:"value"`
+	Height uint32 `json:"height"`
+}
+
+// DiagnoseRawTransactionResult models the data returned from the
+// diagnoserawtransaction command.
+type DiagnoseRawTransactionResult struct {
+	TxID     string                        `json:"txid"`
+	Height   uint32                        `json:"height"`
+	Accepted bool                          `json:"accepted"`
+	Stages   []DiagnoseRawTransactionStage `json:"stages"`
+}
+
+// DiagnoseRawTransactionStage models the result of a single validation stage
+// returned from the diagnoserawtransaction command.
+type DiagnoseRawTransactionStage struct {
+	Stage   string `json:"stage"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped"`
+	Error   string `json:"error,omitempty"`
+}
+
 // GetNetTotalsResult models the data returned from the getnettotals command.
 type GetNetTotalsResult struct {
 	TotalBytesRecv uint64 `json:"totalbytesrecv"`
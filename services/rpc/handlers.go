@@ -54,6 +54,7 @@ import (
 	"github.com/bsv-blockchain/teranode/services/legacy/txscript"
 	"github.com/bsv-blockchain/teranode/services/p2p"
 	"github.com/bsv-blockchain/teranode/services/rpc/bsvjson"
+	"github.com/bsv-blockchain/teranode/services/validator"
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/bsv-blockchain/teranode/util/tracing"
 	"github.com/ordishs/go-utils"
@@ -786,6 +787,10 @@ func handleSendRawTransaction(ctx context.Context, s *RPCServer, cmd interface{}
 
 	s.logger.Debugf("tx to send: %v", tx)
 
+	if correlationID := tracing.CorrelationIDFromContext(ctx); correlationID != "" {
+		s.logger.Infof("[handleSendRawTransaction][%s] received transaction with correlation id %s", tx.TxID(), correlationID)
+	}
+
 	// Store the transaction in blob store first (following the pattern from propagation service)
 	if s.txStore != nil {
 		err = s.txStore.Set(ctx, tx.TxIDChainHash().CloneBytes(), fileformat.FileTypeTx, tx.SerializeBytes())
@@ -807,6 +812,10 @@ func handleSendRawTransaction(ctx context.Context, s *RPCServer, cmd interface{}
 		}
 	}
 
+	if s.unconfirmedTxTracker != nil {
+		s.unconfirmedTxTracker.Track(*tx.TxIDChainHash(), time.Now())
+	}
+
 	// Return the transaction ID as a hex string per Bitcoin RPC spec
 	return tx.TxID(), nil
 }
@@ -2659,3 +2668,174 @@ func handleGetchaintips(ctx context.Context, s *RPCServer, cmd interface{}, _ <-
 
 	return result, nil
 }
+
+// handleDiagnoseRawTransaction implements the diagnoserawtransaction command, which validates a raw
+// transaction in diagnostic mode without submitting it.
+//
+// Instead of stopping at the first failing check, every validation stage (inputs, structural, policy,
+// fee and script) is run and the result of each stage is returned, so the full reason a transaction
+// would be rejected can be seen at once. The transaction is validated against the current UTXO set at
+// the next block height, nothing is spent, stored or sent to block assembly.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - s: The RPC server instance providing access to the UTXO store
+//   - cmd: The parsed command arguments (bsvjson.DiagnoseRawTransactionCmd)
+//   - _: Unused channel for close notification
+//
+// Returns:
+//   - interface{}: *bsvjson.DiagnoseRawTransactionResult with the result of every stage
+//   - error: *bsvjson.RPCError when the transaction cannot be decoded
+func handleDiagnoseRawTransaction(ctx context.Context, s *RPCServer, cmd interface{}, _ <-chan struct{}) (interface{}, error) {
+	ctx, _, deferFn := tracing.Tracer("rpc").Start(ctx, "handleDiagnoseRawTransaction",
+		tracing.WithParentStat(RPCStat),
+		tracing.WithHistogram(prometheusHandleDiagnoseRawTransaction),
+		tracing.WithLogMessage(s.logger, "[handleDiagnoseRawTransaction] called"),
+	)
+	defer deferFn()
+
+	c, ok := cmd.(*bsvjson.DiagnoseRawTransactionCmd)
+	if !ok {
+		return nil, bsvjson.ErrRPCInternal
+	}
+
+	hexStr := c.HexTx
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+
+	serializedTx, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+
+	tx, err := bt.NewTxFromBytes(serializedTx)
+	if err != nil {
+		return nil, &bsvjson.RPCError{
+			Code:    bsvjson.ErrRPCDeserialization,
+			Message: "TX decode failed: " + err.Error(),
+		}
+	}
+
+	diagnostics := s.diagnosticValidator().DiagnoseTransaction(ctx, tx)
+
+	result := &bsvjson.DiagnoseRawTransactionResult{
+		TxID:     tx.TxID(),
+		Height:   diagnostics.BlockHeight,
+		Accepted: diagnostics.Accepted,
+		Stages:   make([]bsvjson.DiagnoseRawTransactionStage, 0, len(diagnostics.Stages)),
+	}
+
+	for _, stage := range diagnostics.Stages {
+		result.Stages = append(result.Stages, bsvjson.DiagnoseRawTransactionStage{
+			Stage:   stage.Stage,
+			Passed:  stage.Passed,
+			Skipped: stage.Skipped,
+			Error:   stage.Error,
+		})
+	}
+
+	return result, nil
+}
+
+// handleGetOutputsByValue implements the getoutputsbyvalue command, which returns a page of the
+// unspent outputs with a value between min and max (inclusive) from the optional output value index.
+//
+// The index is maintained in memory by the RPC service as blocks are connected and disconnected,
+// it is only available when rpc_outputValueIndexEnabled is set. The outputs are ordered by block
+// height, transaction ID and output index. A page holds at most limit outputs, capped by
+// rpc_outputValueIndexMaxResults, and a cursor when more outputs match, which is passed to the
+// next call to return the following page.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - s: The RPC server instance providing access to the output value index
+//   - cmd: The parsed command arguments (bsvjson.GetOutputsByValueCmd)
+//   - _: Unused channel for close notification
+//
+// Returns:
+//   - interface{}: *bsvjson.GetOutputsByValueResult with the matching outputs and the next cursor
+//   - error: *bsvjson.RPCError when the index is disabled or the range, limit or cursor is invalid
+func handleGetOutputsByValue(ctx context.Context, s *RPCServer, cmd interface{}, _ <-chan struct{}) (interface{}, error) {
+	_, _, deferFn := tracing.Tracer("rpc").Start(ctx, "handleGetOutputsByValue",
+		tracing.WithParentStat(RPCStat),
+		tracing.WithHistogram(prometheusHandleGetOutputsByValue),
+		tracing.WithLogMessage(s.logger, "[handleGetOutputsByValue] called"),
+	)
+	defer deferFn()
+
+	c, ok := cmd.(*bsvjson.GetOutputsByValueCmd)
+	if !ok {
+		return nil, bsvjson.ErrRPCInternal
+	}
+
+	if s.outputValueIndex == nil {
+		return nil, &bsvjson.RPCError{
+			Code:    bsvjson.ErrRPCMisc,
+			Message: "Output value index is not enabled (rpc_outputValueIndexEnabled)",
+		}
+	}
+
+	if c.Min > c.Max {
+		return nil, &bsvjson.RPCError{
+			Code:    bsvjson.ErrRPCInvalidParameter,
+			Message: "Minimum value is greater than maximum value",
+		}
+	}
+
+	limit := s.settings.RPC.OutputValueIndexMaxResults
+
+	if c.Limit != nil {
+		if *c.Limit <= 0 {
+			return nil, &bsvjson.RPCError{
+				Code:    bsvjson.ErrRPCInvalidParameter,
+				Message: "Limit must be greater than zero",
+			}
+		}
+
+		if limit <= 0 || *c.Limit < limit {
+			limit = *c.Limit
+		}
+	}
+
+	var after *indexedOutput
+
+	if c.Cursor != nil && *c.Cursor != "" {
+		cursor, err := parseOutputValueCursor(*c.Cursor)
+		if err != nil {
+			return nil, &bsvjson.RPCError{
+				Code:    bsvjson.ErrRPCInvalidParameter,
+				Message: "Invalid cursor: " + err.Error(),
+			}
+		}
+
+		after = &cursor
+	}
+
+	// one more output than the page holds tells whether a next page exists
+	queryLimit := 0
+	if limit > 0 {
+		queryLimit = limit + 1
+	}
+
+	outputs := s.outputValueIndex.query(c.Min, c.Max, after, queryLimit)
+
+	result := &bsvjson.GetOutputsByValueResult{}
+
+	if limit > 0 && len(outputs) > limit {
+		outputs = outputs[:limit]
+		result.Cursor = formatOutputValueCursor(outputs[limit-1])
+	}
+
+	result.Outputs = make([]bsvjson.GetOutputsByValueOutput, 0, len(outputs))
+	for _, output := range outputs {
+		result.Outputs = append(result.Outputs, bsvjson.GetOutputsByValueOutput{
+			TxID:   output.outpoint.hash.String(),
+			Vout:   output.outpoint.index,
+			Value:  output.value,
+			Height: output.height,
+		})
+	}
+
+	return result, nil
+}
@@ -5707,6 +5707,9 @@ func (m *mockBlockAssemblyClient) GetTransactionHashes(ctx context.Context) ([]s
 	}
 	return nil, nil
 }
+func (m *mockBlockAssemblyClient) PrioritiseTxs(ctx context.Context, hashes []chainhash.Hash, remove bool) error {
+	return nil
+}
 
 // TestHandleGetMiningInfoComprehensive tests the complete handleGetMiningInfo functionality
 func TestHandleGetMiningInfoComprehensive(t *testing.T) {
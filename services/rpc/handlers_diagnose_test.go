@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"testing"
+
+	"github.com/bsv-blockchain/teranode/services/rpc/bsvjson"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/stores/utxo/tests"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/bsv-blockchain/teranode/util/test/mocklogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleDiagnoseRawTransaction(t *testing.T) {
+	newServer := func(t *testing.T) *RPCServer {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.MinMiningTxFee = 1
+
+		utxoStoreURL, err := url.Parse("sqlitememory:///test")
+		require.NoError(t, err)
+
+		utxoStore, err := sql.New(t.Context(), ulogger.TestLogger{}, tSettings, utxoStoreURL)
+		require.NoError(t, err)
+
+		require.NoError(t, utxoStore.SetBlockHeight(122))
+
+		_, err = utxoStore.Create(t.Context(), tests.ParentTx, 122)
+		require.NoError(t, err)
+
+		return &RPCServer{
+			logger:    mocklogger.NewTestLogger(),
+			settings:  tSettings,
+			utxoStore: utxoStore,
+		}
+	}
+
+	t.Run("returns the result of every stage", func(t *testing.T) {
+		s := newServer(t)
+
+		cmd := &bsvjson.DiagnoseRawTransactionCmd{
+			HexTx: hex.EncodeToString(tests.Tx.Bytes()),
+		}
+
+		result, err := handleDiagnoseRawTransaction(context.Background(), s, cmd, nil)
+		require.NoError(t, err)
+
+		diagnostics, ok := result.(*bsvjson.DiagnoseRawTransactionResult)
+		require.True(t, ok)
+
+		assert.Equal(t, tests.Tx.TxID(), diagnostics.TxID)
+		assert.Equal(t, uint32(123), diagnostics.Height)
+		assert.False(t, diagnostics.Accepted)
+		require.Len(t, diagnostics.Stages, 5)
+
+		for _, stage := range diagnostics.Stages {
+			if stage.Stage == validator.DiagnosticStageFee {
+				assert.False(t, stage.Passed)
+				assert.Contains(t, stage.Error, "fee is too low")
+
+				continue
+			}
+
+			assert.True(t, stage.Passed, "%s: %s", stage.Stage, stage.Error)
+		}
+	})
+
+	t.Run("invalid transaction", func(t *testing.T) {
+		s := newServer(t)
+
+		cmd := &bsvjson.DiagnoseRawTransactionCmd{
+			HexTx: "123",
+		}
+
+		_, err := handleDiagnoseRawTransaction(context.Background(), s, cmd, nil)
+		require.Error(t, err)
+
+		rpcErr, ok := err.(*bsvjson.RPCError)
+		require.True(t, ok)
+		assert.Equal(t, bsvjson.ErrRPCDeserialization, rpcErr.Code)
+	})
+}
@@ -45,35 +45,39 @@ import (
 // typically ranging from sub-millisecond to several seconds depending on command complexity.
 var (
 	// prometheusHealth                     prometheus.Counter
-	prometheusHandleGetBlock             prometheus.Histogram
-	prometheusHandleGetBlockByHeight     prometheus.Histogram
-	prometheusHandleGetBlockHash         prometheus.Histogram
-	prometheusHandleGetBlockHeader       prometheus.Histogram
-	prometheusHandleGetBestBlockHash     prometheus.Histogram
-	prometheusHandleGetRawTransaction    prometheus.Histogram
-	prometheusHandleCreateRawTransaction prometheus.Histogram
-	prometheusHandleSendRawTransaction   prometheus.Histogram
-	prometheusHandleGenerate             prometheus.Histogram
-	prometheusHandleGenerateToAddress    prometheus.Histogram
-	prometheusHandleGetMiningCandidate   prometheus.Histogram
-	prometheusHandleSubmitMiningSolution prometheus.Histogram
-	prometheusHandleGetpeerinfo          prometheus.Histogram
-	prometheusHandleGetRawmempool        prometheus.Histogram
-	prometheusHandleGetblockchaininfo    prometheus.Histogram
-	prometheusHandleGetinfo              prometheus.Histogram
-	prometheusHandleGetDifficulty        prometheus.Histogram
-	prometheusHandleInvalidateBlock      prometheus.Histogram
-	prometheusHandleReconsiderBlock      prometheus.Histogram
-	prometheusHandleHelp                 prometheus.Histogram
-	prometheusHandleSetBan               prometheus.Histogram
-	prometheusHandleIsBanned             prometheus.Histogram
-	prometheusHandleListBanned           prometheus.Histogram
-	prometheusHandleClearBanned          prometheus.Histogram
-	prometheusHandleGetMiningInfo        prometheus.Histogram
-	prometheusHandleFreeze               prometheus.Histogram
-	prometheusHandleUnfreeze             prometheus.Histogram
-	prometheusHandleReassign             prometheus.Histogram
-	prometheusHandleGetchaintips         prometheus.Histogram
+	prometheusHandleGetBlock               prometheus.Histogram
+	prometheusHandleGetBlockByHeight       prometheus.Histogram
+	prometheusHandleGetBlockHash           prometheus.Histogram
+	prometheusHandleGetBlockHeader         prometheus.Histogram
+	prometheusHandleGetBestBlockHash       prometheus.Histogram
+	prometheusHandleGetRawTransaction      prometheus.Histogram
+	prometheusHandleCreateRawTransaction   prometheus.Histogram
+	prometheusHandleSendRawTransaction     prometheus.Histogram
+	prometheusHandleGenerate               prometheus.Histogram
+	prometheusHandleGenerateToAddress      prometheus.Histogram
+	prometheusHandleGetMiningCandidate     prometheus.Histogram
+	prometheusHandleSubmitMiningSolution   prometheus.Histogram
+	prometheusHandleGetpeerinfo            prometheus.Histogram
+	prometheusHandleGetRawmempool          prometheus.Histogram
+	prometheusHandleGetblockchaininfo      prometheus.Histogram
+	prometheusHandleGetinfo                prometheus.Histogram
+	prometheusHandleGetDifficulty          prometheus.Histogram
+	prometheusHandleInvalidateBlock        prometheus.Histogram
+	prometheusHandleReconsiderBlock        prometheus.Histogram
+	prometheusHandleHelp                   prometheus.Histogram
+	prometheusHandleSetBan                 prometheus.Histogram
+	prometheusHandleIsBanned               prometheus.Histogram
+	prometheusHandleListBanned             prometheus.Histogram
+	prometheusHandleClearBanned            prometheus.Histogram
+	prometheusHandleGetMiningInfo          prometheus.Histogram
+	prometheusHandleFreeze                 prometheus.Histogram
+	prometheusHandleUnfreeze               prometheus.Histogram
+	prometheusHandleReassign               prometheus.Histogram
+	prometheusHandleGetchaintips           prometheus.Histogram
+	prometheusHandleGetOutputsByValue      prometheus.Histogram
+	prometheusHandleDiagnoseRawTransaction prometheus.Histogram
+	prometheusUnconfirmedTxAlerts          prometheus.Counter
+	prometheusCoalescedAlerts              prometheus.Counter
 )
 
 var (
@@ -346,4 +350,38 @@ func _initPrometheusMetrics() {
 			Buckets:   util.MetricsBucketsMilliSeconds,
 		},
 	)
+	prometheusHandleGetOutputsByValue = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "rpc",
+			Name:      "get_outputs_by_value",
+			Help:      "Histogram of calls to handleGetOutputsByValue in the rpc service",
+			Buckets:   util.MetricsBucketsMilliSeconds,
+		},
+	)
+	prometheusHandleDiagnoseRawTransaction = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "rpc",
+			Name:      "diagnose_raw_transaction",
+			Help:      "Histogram of calls to handleDiagnoseRawTransaction in the rpc service",
+			Buckets:   util.MetricsBucketsMilliSeconds,
+		},
+	)
+	prometheusUnconfirmedTxAlerts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "rpc",
+			Name:      "unconfirmed_tx_alerts",
+			Help:      "Number of transactions sent via sendrawtransaction still unconfirmed after the alert age",
+		},
+	)
+	prometheusCoalescedAlerts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "rpc",
+			Name:      "coalesced_alerts",
+			Help:      "Number of alerts not raised individually because the maximum number of alerts per interval was reached",
+		},
+	)
 }
@@ -0,0 +1,874 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-wire"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
+	"github.com/bsv-blockchain/teranode/ulogger"
+)
+
+const (
+	// outputValueIndexUndoDepth is the number of most recent blocks for which undo data is kept,
+	// a reorg deeper than this re-indexes the outputs from the configured start height
+	outputValueIndexUndoDepth = 288
+
+	// outputValueIndexHeaderBatchSize is the number of block headers requested at once while catching up
+	outputValueIndexHeaderBatchSize = 1000
+
+	// outputValueIndexSyncInterval is the interval at which the index is synced when no block notification is received
+	outputValueIndexSyncInterval = time.Minute
+
+	// outputValueIndexSaveInterval is the minimum interval between two saves of the index while syncing
+	outputValueIndexSaveInterval = 10 * time.Minute
+
+	// outputValueIndexCompactMinStale is the minimum number of spent outputs left in a bucket before it is compacted
+	outputValueIndexCompactMinStale = 1024
+
+	// outputValueIndexMaxTxPrealloc is the maximum number of transactions preallocated when reading a block, the
+	// transaction count is read from the response and is not trusted before the transactions are read
+	outputValueIndexMaxTxPrealloc = 100_000
+
+	// outputValueIndexFileName is the name of the file the index is saved to in the data folder by default
+	outputValueIndexFileName = "rpc_output_value_index.gob"
+
+	// outputValueIndexFileVersion is the version of the saved index format, a saved index of another version is discarded
+	outputValueIndexFileVersion = 1
+)
+
+// indexedOutpoint identifies a transaction output in the output value index
+type indexedOutpoint struct {
+	hash  chainhash.Hash
+	index uint32
+}
+
+// indexedOutput is an unspent output in the output value index
+type indexedOutput struct {
+	outpoint indexedOutpoint
+	value    uint64
+	height   uint32
+}
+
+// before reports whether the output comes before the other output in the order of the index,
+// by height, transaction ID and output index
+func (o indexedOutput) before(other indexedOutput) bool {
+	if o.height != other.height {
+		return o.height < other.height
+	}
+
+	if cmp := bytes.Compare(o.outpoint.hash[:], other.outpoint.hash[:]); cmp != 0 {
+		return cmp < 0
+	}
+
+	return o.outpoint.index < other.outpoint.index
+}
+
+// sortIndexedOutputs sorts the outputs in the order of the index
+func sortIndexedOutputs(outputs []indexedOutput) {
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].before(outputs[j])
+	})
+}
+
+// formatOutputValueCursor returns the cursor continuing a query after the given output, in the format height:txid:vout
+func formatOutputValueCursor(output indexedOutput) string {
+	return fmt.Sprintf("%d:%s:%d", output.height, output.outpoint.hash, output.outpoint.index)
+}
+
+// parseOutputValueCursor parses a cursor returned by formatOutputValueCursor
+func parseOutputValueCursor(cursor string) (indexedOutput, error) {
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 3 {
+		return indexedOutput{}, errors.NewInvalidArgumentError("cursor %q is not in the format height:txid:vout", cursor)
+	}
+
+	height, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return indexedOutput{}, errors.NewInvalidArgumentError("invalid cursor height %q", parts[0], err)
+	}
+
+	hash, err := chainhash.NewHashFromStr(parts[1])
+	if err != nil {
+		return indexedOutput{}, errors.NewInvalidArgumentError("invalid cursor txid %q", parts[1], err)
+	}
+
+	index, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return indexedOutput{}, errors.NewInvalidArgumentError("invalid cursor vout %q", parts[2], err)
+	}
+
+	return indexedOutput{
+		outpoint: indexedOutpoint{hash: *hash, index: uint32(index)}, //nolint:gosec // parsed as a 32 bit value
+		height:   uint32(height),                                     //nolint:gosec // parsed as a 32 bit value
+	}, nil
+}
+
+// indexedBlock holds the undo data of a block connected to the output value index
+type indexedBlock struct {
+	hash     chainhash.Hash
+	prevHash chainhash.Hash
+	height   uint32
+	removed  []indexedOutput // outputs of earlier blocks spent in the block
+}
+
+// outputBucket holds the outputs of a value bucket in the order of the index. Spent outputs are removed
+// lazily, an entry is live while the outputs of the index hold its outpoint at the same height.
+type outputBucket struct {
+	entries []indexedOutput
+	stale   int // number of entries of spent outputs
+}
+
+// outputValueIndex is an in-memory index of the unspent transaction outputs by value.
+//
+// Outputs are grouped in buckets of the bit length of their value, a query for a value range
+// only visits the buckets overlapping the range. The outputs of a bucket are kept in height,
+// transaction ID and output index order, so a query stops reading a bucket once it has found
+// enough outputs, and continues after a cursor with a binary search. Blocks are connected to
+// the tip of the index and can be disconnected again, in reverse order, for the most recent
+// maxUndoDepth blocks.
+type outputValueIndex struct {
+	mu           sync.RWMutex
+	maxUndoDepth int
+	buckets      map[int]*outputBucket
+	outputs      map[indexedOutpoint]indexedOutput
+	blocks       []*indexedBlock // connected blocks with undo data, in ascending height order
+	hasTip       bool
+	tipHash      chainhash.Hash
+	tipHeight    uint32
+	baseHeight   uint32 // height of the first block connected to the empty index
+}
+
+// newOutputValueIndex creates an empty output value index keeping undo data for maxUndoDepth blocks
+func newOutputValueIndex(maxUndoDepth int) *outputValueIndex {
+	return &outputValueIndex{
+		maxUndoDepth: maxUndoDepth,
+		buckets:      make(map[int]*outputBucket),
+		outputs:      make(map[indexedOutpoint]indexedOutput),
+	}
+}
+
+// valueBucket returns the bucket of the given output value
+func valueBucket(value uint64) int {
+	return bits.Len64(value)
+}
+
+// tipBlock returns the hash and height of the last block connected to the index, ok is false when
+// no block has been connected
+func (idx *outputValueIndex) tipBlock() (hash chainhash.Hash, height uint32, ok bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.tipHash, idx.tipHeight, idx.hasTip
+}
+
+// connectBlock adds the outputs created in the block to the index and removes the outputs spent in the block.
+// The block must be the child of the tip of the index, any block can be connected to an empty index.
+func (idx *outputValueIndex) connectBlock(hash, prevHash chainhash.Hash, height uint32, txs []*bt.Tx) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.hasTip && (height != idx.tipHeight+1 || !prevHash.IsEqual(&idx.tipHash)) {
+		return errors.NewProcessingError("block %s at height %d does not connect to output value index tip %s at height %d", hash, height, idx.tipHash, idx.tipHeight)
+	}
+
+	if !idx.hasTip {
+		idx.baseHeight = height
+	}
+
+	block := &indexedBlock{
+		hash:     hash,
+		prevHash: prevHash,
+		height:   height,
+	}
+
+	addedInBlock := make(map[indexedOutpoint]indexedOutput)
+
+	for _, tx := range txs {
+		if !tx.IsCoinbase() {
+			for _, input := range tx.Inputs {
+				outpoint := indexedOutpoint{hash: *input.PreviousTxIDChainHash(), index: input.PreviousTxOutIndex}
+
+				output, found := idx.outputs[outpoint]
+				if !found {
+					continue
+				}
+
+				delete(idx.outputs, outpoint)
+
+				if _, ok := addedInBlock[outpoint]; ok {
+					delete(addedInBlock, outpoint)
+					continue
+				}
+
+				idx.bucket(output.value).stale++
+				block.removed = append(block.removed, output)
+			}
+		}
+
+		txHash := *tx.TxIDChainHash()
+
+		for i, output := range tx.Outputs {
+			outpoint := indexedOutpoint{hash: txHash, index: uint32(i)} //nolint:gosec // output index fits in uint32
+
+			indexed := indexedOutput{outpoint: outpoint, value: output.Satoshis, height: height}
+
+			idx.outputs[outpoint] = indexed
+			addedInBlock[outpoint] = indexed
+		}
+	}
+
+	// the outputs of the block are higher than all outputs of the buckets, appending them in order keeps the buckets sorted
+	added := make([]indexedOutput, 0, len(addedInBlock))
+	for _, output := range addedInBlock {
+		added = append(added, output)
+	}
+
+	sortIndexedOutputs(added)
+
+	for _, output := range added {
+		bucket := idx.bucket(output.value)
+		bucket.entries = append(bucket.entries, output)
+	}
+
+	for _, bucket := range idx.buckets {
+		if bucket.stale >= outputValueIndexCompactMinStale && bucket.stale*2 > len(bucket.entries) {
+			idx.compact(bucket)
+		}
+	}
+
+	idx.blocks = append(idx.blocks, block)
+	idx.hasTip, idx.tipHash, idx.tipHeight = true, hash, height
+
+	if len(idx.blocks) > idx.maxUndoDepth {
+		idx.blocks = idx.blocks[len(idx.blocks)-idx.maxUndoDepth:]
+	}
+
+	return nil
+}
+
+// disconnectTip removes the last connected block from the index, restoring the outputs it spent.
+// Returns an error when there is no undo data for the tip of the index.
+func (idx *outputValueIndex) disconnectTip() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if len(idx.blocks) == 0 {
+		return errors.NewProcessingError("no undo data to disconnect the output value index tip")
+	}
+
+	block := idx.blocks[len(idx.blocks)-1]
+
+	// the outputs created in the tip block are the last entries of their buckets
+	for value, bucket := range idx.buckets {
+		n := len(bucket.entries)
+
+		for n > 0 && bucket.entries[n-1].height >= block.height {
+			n--
+
+			if idx.isLive(bucket.entries[n]) {
+				delete(idx.outputs, bucket.entries[n].outpoint)
+			} else {
+				bucket.stale--
+			}
+		}
+
+		bucket.entries = bucket.entries[:n]
+
+		if n == 0 {
+			delete(idx.buckets, value)
+		}
+	}
+
+	// the entries of the restored outputs are still in their buckets, unless the bucket was compacted since
+	missing := make(map[int][]indexedOutput)
+
+	for _, output := range block.removed {
+		idx.outputs[output.outpoint] = output
+
+		bucket := idx.bucket(output.value)
+
+		i := sort.Search(len(bucket.entries), func(i int) bool { return !bucket.entries[i].before(output) })
+		if i < len(bucket.entries) && bucket.entries[i].outpoint == output.outpoint && bucket.entries[i].height == output.height {
+			bucket.stale--
+			continue
+		}
+
+		missing[valueBucket(output.value)] = append(missing[valueBucket(output.value)], output)
+	}
+
+	for value, outputs := range missing {
+		bucket := idx.buckets[value]
+
+		sortIndexedOutputs(outputs)
+		bucket.entries = mergeIndexedOutputs(bucket.entries, outputs)
+	}
+
+	idx.blocks = idx.blocks[:len(idx.blocks)-1]
+
+	if block.height == idx.baseHeight {
+		idx.hasTip = false
+	} else {
+		idx.tipHash, idx.tipHeight = block.prevHash, block.height-1
+	}
+
+	return nil
+}
+
+// reset removes all outputs and blocks from the index
+func (idx *outputValueIndex) reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.buckets = make(map[int]*outputBucket)
+	idx.outputs = make(map[indexedOutpoint]indexedOutput)
+	idx.blocks = nil
+	idx.hasTip = false
+}
+
+// query returns the unspent outputs with a value between minValue and maxValue inclusive, ordered by
+// height, transaction ID and output index, starting after the given output when after is not nil.
+// At most limit outputs are returned when limit is positive, each bucket is only read until it has
+// provided limit outputs.
+func (idx *outputValueIndex) query(minValue, maxValue uint64, after *indexedOutput, limit int) []indexedOutput {
+	if minValue > maxValue {
+		return nil
+	}
+
+	idx.mu.RLock()
+
+	var result []indexedOutput
+
+	for value := valueBucket(minValue); value <= valueBucket(maxValue); value++ {
+		bucket := idx.buckets[value]
+		if bucket == nil {
+			continue
+		}
+
+		start := 0
+		if after != nil {
+			start = sort.Search(len(bucket.entries), func(i int) bool { return after.before(bucket.entries[i]) })
+		}
+
+		found := 0
+
+		for _, output := range bucket.entries[start:] {
+			if limit > 0 && found == limit {
+				break
+			}
+
+			if output.value < minValue || output.value > maxValue || !idx.isLive(output) {
+				continue
+			}
+
+			result = append(result, output)
+			found++
+		}
+	}
+
+	idx.mu.RUnlock()
+
+	sortIndexedOutputs(result)
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
+
+// bucket returns the bucket of the given output value, creating it when needed, the caller must hold the write lock
+func (idx *outputValueIndex) bucket(value uint64) *outputBucket {
+	bucket, ok := idx.buckets[valueBucket(value)]
+	if !ok {
+		bucket = &outputBucket{}
+		idx.buckets[valueBucket(value)] = bucket
+	}
+
+	return bucket
+}
+
+// isLive reports whether the entry of a bucket is an unspent output, the caller must hold the lock
+func (idx *outputValueIndex) isLive(output indexedOutput) bool {
+	current, ok := idx.outputs[output.outpoint]
+
+	return ok && current.height == output.height
+}
+
+// compact removes the entries of the spent outputs from the bucket, the caller must hold the write lock
+func (idx *outputValueIndex) compact(bucket *outputBucket) {
+	entries := make([]indexedOutput, 0, len(bucket.entries)-bucket.stale)
+
+	for _, output := range bucket.entries {
+		if idx.isLive(output) {
+			entries = append(entries, output)
+		}
+	}
+
+	bucket.entries = entries
+	bucket.stale = 0
+}
+
+// mergeIndexedOutputs merges two lists of outputs sorted in the order of the index
+func mergeIndexedOutputs(a, b []indexedOutput) []indexedOutput {
+	merged := make([]indexedOutput, 0, len(a)+len(b))
+
+	for len(a) > 0 && len(b) > 0 {
+		if b[0].before(a[0]) {
+			merged = append(merged, b[0])
+			b = b[1:]
+		} else {
+			merged = append(merged, a[0])
+			a = a[1:]
+		}
+	}
+
+	merged = append(merged, a...)
+
+	return append(merged, b...)
+}
+
+// outputValueIndexFile is the saved state of the output value index
+type outputValueIndexFile struct {
+	Version     int
+	StartHeight uint32
+	HasTip      bool
+	TipHash     chainhash.Hash
+	TipHeight   uint32
+	BaseHeight  uint32
+	Outputs     []outputValueIndexFileOutput
+	Blocks      []outputValueIndexFileBlock
+}
+
+// outputValueIndexFileOutput is an unspent output in the saved output value index
+type outputValueIndexFileOutput struct {
+	Hash   chainhash.Hash
+	Index  uint32
+	Value  uint64
+	Height uint32
+}
+
+// outputValueIndexFileBlock is the undo data of a block in the saved output value index
+type outputValueIndexFileBlock struct {
+	Hash     chainhash.Hash
+	PrevHash chainhash.Hash
+	Height   uint32
+	Removed  []outputValueIndexFileOutput
+}
+
+// toFileOutputs converts the outputs to their saved format
+func toFileOutputs(outputs []indexedOutput) []outputValueIndexFileOutput {
+	result := make([]outputValueIndexFileOutput, 0, len(outputs))
+	for _, output := range outputs {
+		result = append(result, outputValueIndexFileOutput{
+			Hash:   output.outpoint.hash,
+			Index:  output.outpoint.index,
+			Value:  output.value,
+			Height: output.height,
+		})
+	}
+
+	return result
+}
+
+// fromFileOutputs converts the saved outputs back to index outputs
+func fromFileOutputs(outputs []outputValueIndexFileOutput) []indexedOutput {
+	result := make([]indexedOutput, 0, len(outputs))
+	for _, output := range outputs {
+		result = append(result, indexedOutput{
+			outpoint: indexedOutpoint{hash: output.Hash, index: output.Index},
+			value:    output.Value,
+			height:   output.Height,
+		})
+	}
+
+	return result
+}
+
+// save writes the index, indexed from startHeight, to the given file, through a temporary file renamed
+// once complete so an interrupted save does not corrupt the saved index
+func (idx *outputValueIndex) save(file string, startHeight uint32) error {
+	idx.mu.RLock()
+
+	state := &outputValueIndexFile{
+		Version:     outputValueIndexFileVersion,
+		StartHeight: startHeight,
+		HasTip:      idx.hasTip,
+		TipHash:     idx.tipHash,
+		TipHeight:   idx.tipHeight,
+		BaseHeight:  idx.baseHeight,
+		Outputs:     make([]outputValueIndexFileOutput, 0, len(idx.outputs)),
+		Blocks:      make([]outputValueIndexFileBlock, 0, len(idx.blocks)),
+	}
+
+	for _, output := range idx.outputs {
+		state.Outputs = append(state.Outputs, outputValueIndexFileOutput{
+			Hash:   output.outpoint.hash,
+			Index:  output.outpoint.index,
+			Value:  output.value,
+			Height: output.height,
+		})
+	}
+
+	for _, block := range idx.blocks {
+		state.Blocks = append(state.Blocks, outputValueIndexFileBlock{
+			Hash:     block.hash,
+			PrevHash: block.prevHash,
+			Height:   block.height,
+			Removed:  toFileOutputs(block.removed),
+		})
+	}
+
+	idx.mu.RUnlock()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return errors.NewProcessingError("failed to create output value index folder", err)
+	}
+
+	// use a unique temp file name to avoid concurrent write conflicts
+	tempFile := fmt.Sprintf("%s.tmp.%d", file, time.Now().UnixNano())
+
+	f, err := os.OpenFile(tempFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.NewProcessingError("failed to create output value index file", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	err = gob.NewEncoder(w).Encode(state)
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(tempFile)
+		return errors.NewProcessingError("failed to write output value index file", err)
+	}
+
+	if err = os.Rename(tempFile, file); err != nil {
+		_ = os.Remove(tempFile)
+		return errors.NewProcessingError("failed to finalize output value index file", err)
+	}
+
+	return nil
+}
+
+// load replaces the content of the index with the index saved to the given file. A missing file is not an
+// error, a file of another version or indexed from another start height is discarded with an error.
+func (idx *outputValueIndex) load(file string, startHeight uint32) error {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return errors.NewProcessingError("failed to open output value index file", err)
+	}
+	defer f.Close()
+
+	var state outputValueIndexFile
+	if err = gob.NewDecoder(bufio.NewReader(f)).Decode(&state); err != nil {
+		return errors.NewProcessingError("failed to read output value index file (will re-index)", err)
+	}
+
+	if state.Version != outputValueIndexFileVersion {
+		return errors.NewProcessingError("output value index file version mismatch (expected %d, got %d), will re-index", outputValueIndexFileVersion, state.Version)
+	}
+
+	if state.StartHeight != startHeight {
+		return errors.NewProcessingError("output value index file indexed from height %d instead of %d, will re-index", state.StartHeight, startHeight)
+	}
+
+	outputs := fromFileOutputs(state.Outputs)
+	sortIndexedOutputs(outputs)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.buckets = make(map[int]*outputBucket)
+	idx.outputs = make(map[indexedOutpoint]indexedOutput, len(outputs))
+	idx.blocks = nil
+
+	for _, output := range outputs {
+		idx.outputs[output.outpoint] = output
+
+		bucket := idx.bucket(output.value)
+		bucket.entries = append(bucket.entries, output)
+	}
+
+	for _, block := range state.Blocks {
+		idx.blocks = append(idx.blocks, &indexedBlock{
+			hash:     block.Hash,
+			prevHash: block.PrevHash,
+			height:   block.Height,
+			removed:  fromFileOutputs(block.Removed),
+		})
+	}
+
+	if len(idx.blocks) > idx.maxUndoDepth {
+		idx.blocks = idx.blocks[len(idx.blocks)-idx.maxUndoDepth:]
+	}
+
+	idx.hasTip, idx.tipHash, idx.tipHeight, idx.baseHeight = state.HasTip, state.TipHash, state.TipHeight, state.BaseHeight
+
+	return nil
+}
+
+// outputValueIndexer keeps the output value index in sync with the best chain of the blockchain service.
+// The index is saved to file periodically and when stopping, and loaded from it on start, so only the
+// blocks connected since the last save are indexed again after a restart.
+type outputValueIndexer struct {
+	logger           ulogger.Logger
+	blockchainClient blockchain.ClientI
+	index            *outputValueIndex
+	startHeight      uint32
+	file             string // file the index is saved to, the index is not saved when empty
+	getBlockTxs      func(ctx context.Context, hash *chainhash.Hash) ([]*bt.Tx, error)
+	lastSave         time.Time
+}
+
+// Start loads the saved index, syncs the index with the best chain and re-syncs it on every block
+// notification until the context is done.
+func (i *outputValueIndexer) Start(ctx context.Context) error {
+	if i.file != "" {
+		if err := i.index.load(i.file, i.startHeight); err != nil {
+			i.logger.Warnf("[OutputValueIndex] failed to load output value index from %s: %v", i.file, err)
+			i.index.reset()
+		} else if hash, height, ok := i.index.tipBlock(); ok {
+			i.logger.Infof("[OutputValueIndex] loaded output value index at block %s at height %d", hash, height)
+		}
+
+		i.lastSave = time.Now()
+	}
+
+	ch, err := i.blockchainClient.Subscribe(ctx, "rpc-output-value-index")
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			if err := i.sync(ctx); err != nil && ctx.Err() == nil {
+				i.logger.Errorf("[OutputValueIndex] failed to sync output value index: %v", err)
+			}
+
+			if ctx.Err() == nil && time.Since(i.lastSave) >= outputValueIndexSaveInterval {
+				i.save()
+			}
+
+			select {
+			case <-ctx.Done():
+				i.logger.Infof("[OutputValueIndex] stopping output value index")
+				i.save()
+
+				return
+			case notification := <-ch:
+				if notification == nil || notification.Type != model.NotificationType_Block {
+					continue
+				}
+			case <-time.After(outputValueIndexSyncInterval):
+			}
+		}
+	}()
+
+	return nil
+}
+
+// save saves the index to the index file, when one is configured
+func (i *outputValueIndexer) save() {
+	if i.file == "" {
+		return
+	}
+
+	if err := i.index.save(i.file, i.startHeight); err != nil {
+		i.logger.Errorf("[OutputValueIndex] failed to save output value index to %s: %v", i.file, err)
+	}
+
+	i.lastSave = time.Now()
+}
+
+// sync disconnects the indexed blocks no longer on the best chain and connects the blocks of the
+// best chain not yet indexed.
+func (i *outputValueIndexer) sync(ctx context.Context) error {
+	_, bestMeta, err := i.blockchainClient.GetBestBlockHeader(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = i.disconnectStaleBlocks(ctx, bestMeta.Height); err != nil {
+		return err
+	}
+
+	tipHash, tipHeight, ok := i.index.tipBlock()
+
+	nextHeight := i.startHeight
+	if ok {
+		nextHeight = tipHeight + 1
+	}
+
+	for nextHeight <= bestMeta.Height {
+		endHeight := bestMeta.Height
+		if endHeight-nextHeight >= outputValueIndexHeaderBatchSize {
+			endHeight = nextHeight + outputValueIndexHeaderBatchSize - 1
+		}
+
+		headers, _, err := i.blockchainClient.GetBlockHeadersByHeight(ctx, nextHeight, endHeight)
+		if err != nil {
+			return err
+		}
+
+		if len(headers) == 0 {
+			return nil
+		}
+
+		for _, header := range headers {
+			if ok && !header.HashPrevBlock.IsEqual(&tipHash) {
+				// the best chain changed while syncing, the next sync disconnects the stale blocks
+				return nil
+			}
+
+			hash := header.Hash()
+
+			txs, err := i.getBlockTxs(ctx, hash)
+			if err != nil {
+				return errors.NewProcessingError("failed to get transactions of block %s", hash, err)
+			}
+
+			if err = i.index.connectBlock(*hash, *header.HashPrevBlock, nextHeight, txs); err != nil {
+				return err
+			}
+
+			tipHash, ok = *hash, true
+			nextHeight++
+		}
+	}
+
+	return nil
+}
+
+// disconnectStaleBlocks disconnects the indexed blocks that are not on the best chain. When the fork point
+// is deeper than the undo data kept, the index is reset and rebuilt from the start height by the sync.
+func (i *outputValueIndexer) disconnectStaleBlocks(ctx context.Context, bestHeight uint32) error {
+	for {
+		tipHash, tipHeight, ok := i.index.tipBlock()
+		if !ok {
+			return nil
+		}
+
+		if tipHeight <= bestHeight {
+			headers, _, err := i.blockchainClient.GetBlockHeadersByHeight(ctx, tipHeight, tipHeight)
+			if err != nil {
+				return err
+			}
+
+			if len(headers) == 1 && headers[0].Hash().IsEqual(&tipHash) {
+				return nil
+			}
+		}
+
+		i.logger.Infof("[OutputValueIndex] disconnecting block %s at height %d", tipHash, tipHeight)
+
+		if err := i.index.disconnectTip(); err != nil {
+			i.logger.Warnf("[OutputValueIndex] reorg deeper than %d blocks, re-indexing outputs from height %d", outputValueIndexUndoDepth, i.startHeight)
+			i.index.reset()
+
+			return nil
+		}
+	}
+}
+
+// startOutputValueIndex starts keeping the output value index of the server in sync with the best chain,
+// reading the transactions of the blocks from the asset service.
+func (s *RPCServer) startOutputValueIndex(ctx context.Context) error {
+	indexer := &outputValueIndexer{
+		logger:           s.logger,
+		blockchainClient: s.blockchainClient,
+		index:            s.outputValueIndex,
+		startHeight:      s.settings.RPC.OutputValueIndexStartHeight,
+		file:             s.outputValueIndexFile(),
+		getBlockTxs: func(ctx context.Context, hash *chainhash.Hash) ([]*bt.Tx, error) {
+			return getBlockTxsFromAsset(ctx, s.assetHTTPURL, hash)
+		},
+	}
+
+	return indexer.Start(ctx)
+}
+
+// outputValueIndexFile returns the file the output value index is saved to, rpc_outputValueIndexFile
+// or a file in the data folder by default
+func (s *RPCServer) outputValueIndexFile() string {
+	if s.settings.RPC.OutputValueIndexFile != "" {
+		return s.settings.RPC.OutputValueIndexFile
+	}
+
+	return filepath.Join(s.settings.DataFolder, outputValueIndexFileName)
+}
+
+// getBlockTxsFromAsset returns the transactions of the block, read from the legacy block endpoint of the asset service.
+func getBlockTxsFromAsset(ctx context.Context, assetHTTPURL *url.URL, hash *chainhash.Hash) ([]*bt.Tx, error) {
+	fullURL := assetHTTPURL.ResolveReference(&url.URL{Path: fmt.Sprintf("/api/v1/block_legacy/%s", hash), RawQuery: "wire=1"})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL.String(), nil)
+	if err != nil {
+		return nil, errors.NewServiceError("error creating request", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.NewServiceError("error getting block %s", hash, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NewServiceError("unexpected status code %d getting block %s", resp.StatusCode, hash)
+	}
+
+	return readWireBlockTxs(bufio.NewReader(resp.Body))
+}
+
+// readWireBlockTxs reads the transactions of a block in wire format, skipping the block header
+func readWireBlockTxs(r io.Reader) ([]*bt.Tx, error) {
+	if _, err := io.CopyN(io.Discard, r, model.BlockHeaderSize); err != nil {
+		return nil, errors.NewProcessingError("error reading block header", err)
+	}
+
+	txCount, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, errors.NewProcessingError("error reading transaction count", err)
+	}
+
+	txs := make([]*bt.Tx, 0, min(txCount, outputValueIndexMaxTxPrealloc))
+
+	for j := uint64(0); j < txCount; j++ {
+		tx := &bt.Tx{}
+		if _, err = tx.ReadFrom(r); err != nil {
+			return nil, errors.NewProcessingError("error reading transaction %d", j, err)
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
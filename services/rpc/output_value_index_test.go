@@ -0,0 +1,354 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-wire"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newValueIndexTestTx creates a transaction spending the given outpoints, with an output per value
+func newValueIndexTestTx(t *testing.T, spends []indexedOutpoint, values ...uint64) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+
+	for _, spend := range spends {
+		input := &bt.Input{
+			PreviousTxOutIndex: spend.index,
+			SequenceNumber:     0xffffffff,
+		}
+		require.NoError(t, input.PreviousTxIDAdd(&spend.hash))
+
+		tx.Inputs = append(tx.Inputs, input)
+	}
+
+	for _, value := range values {
+		require.NoError(t, tx.AddP2PKHOutputFromAddress("1JzfqRyLUiMn2MTwWsqXEXRu4ujNhqPqWD", value))
+	}
+
+	return tx
+}
+
+// outpointOf returns the outpoint of the output of the transaction with the given index
+func outpointOf(tx *bt.Tx, index uint32) indexedOutpoint {
+	return indexedOutpoint{hash: *tx.TxIDChainHash(), index: index}
+}
+
+// values returns the values of the outputs
+func values(outputs []indexedOutput) []uint64 {
+	result := make([]uint64, 0, len(outputs))
+	for _, output := range outputs {
+		result = append(result, output.value)
+	}
+
+	return result
+}
+
+func TestOutputValueIndex(t *testing.T) {
+	unindexed := []indexedOutpoint{{hash: chainhash.Hash{1}}}
+
+	tx1 := newValueIndexTestTx(t, unindexed, 500, 1_000, 50_000)
+	tx2 := newValueIndexTestTx(t, []indexedOutpoint{outpointOf(tx1, 1)}, 2_000, 100_000_000)
+
+	blockHash1 := chainhash.Hash{0x11}
+	blockHash2 := chainhash.Hash{0x12}
+
+	t.Run("query within and outside value ranges", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+
+		assert.Equal(t, []uint64{500, 1_000}, values(idx.query(500, 1_000, nil, 0)))
+		assert.Equal(t, []uint64{1_000, 50_000}, values(idx.query(501, 50_000, nil, 0)))
+		assert.Equal(t, []uint64{500, 1_000, 50_000}, values(idx.query(0, 1<<63, nil, 0)))
+		assert.Empty(t, idx.query(1_001, 49_999, nil, 0))
+		assert.Empty(t, idx.query(50_001, 1<<63, nil, 0))
+		assert.Empty(t, idx.query(0, 499, nil, 0))
+		assert.Empty(t, idx.query(1_000, 500, nil, 0))
+
+		outputs := idx.query(1_000, 1_000, nil, 0)
+		require.Len(t, outputs, 1)
+		assert.Equal(t, outpointOf(tx1, 1), outputs[0].outpoint)
+		assert.Equal(t, uint32(1), outputs[0].height)
+	})
+
+	t.Run("query limit", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+
+		assert.Equal(t, []uint64{500, 1_000}, values(idx.query(0, 1<<63, nil, 2)))
+	})
+
+	t.Run("spent outputs are removed", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+		require.NoError(t, idx.connectBlock(blockHash2, blockHash1, 2, []*bt.Tx{tx2}))
+
+		assert.Equal(t, []uint64{500, 50_000, 2_000, 100_000_000}, values(idx.query(0, 1<<63, nil, 0)))
+		assert.Empty(t, idx.query(1_000, 1_000, nil, 0))
+	})
+
+	t.Run("output created and spent in the same block", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1, tx2}))
+
+		assert.Empty(t, idx.query(1_000, 1_000, nil, 0))
+
+		require.NoError(t, idx.disconnectTip())
+		assert.Empty(t, idx.query(0, 1<<63, nil, 0))
+	})
+
+	t.Run("block not connecting to the tip", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+
+		require.Error(t, idx.connectBlock(blockHash2, chainhash.Hash{0xff}, 2, []*bt.Tx{tx2}))
+		require.Error(t, idx.connectBlock(blockHash2, blockHash1, 3, []*bt.Tx{tx2}))
+	})
+
+	t.Run("disconnect restores spent outputs", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+		require.NoError(t, idx.connectBlock(blockHash2, blockHash1, 2, []*bt.Tx{tx2}))
+
+		require.NoError(t, idx.disconnectTip())
+
+		hash, height, ok := idx.tipBlock()
+		require.True(t, ok)
+		assert.Equal(t, blockHash1, hash)
+		assert.Equal(t, uint32(1), height)
+
+		assert.Equal(t, []uint64{500, 1_000, 50_000}, values(idx.query(0, 1<<63, nil, 0)))
+		assert.Empty(t, idx.query(2_000, 2_000, nil, 0))
+	})
+
+	t.Run("query after cursor", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+		require.NoError(t, idx.connectBlock(blockHash2, blockHash1, 2, []*bt.Tx{tx2}))
+
+		var (
+			pages [][]uint64
+			after *indexedOutput
+		)
+
+		for {
+			page := idx.query(0, 1<<63, after, 2)
+			if len(page) == 0 {
+				break
+			}
+
+			pages = append(pages, values(page))
+
+			cursor, err := parseOutputValueCursor(formatOutputValueCursor(page[len(page)-1]))
+			require.NoError(t, err)
+
+			after = &cursor
+		}
+
+		assert.Equal(t, [][]uint64{{500, 50_000}, {2_000, 100_000_000}}, pages)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, cursor := range []string{"", "1", "x:" + blockHash1.String() + ":0", "1:zz:0", "1:" + blockHash1.String() + ":-1"} {
+			_, err := parseOutputValueCursor(cursor)
+			assert.Error(t, err, cursor)
+		}
+	})
+
+	t.Run("disconnect restores outputs of compacted buckets", func(t *testing.T) {
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+		require.NoError(t, idx.connectBlock(blockHash2, blockHash1, 2, []*bt.Tx{tx2}))
+
+		bucket := idx.buckets[valueBucket(1_000)]
+		require.Equal(t, 1, bucket.stale)
+
+		idx.compact(bucket)
+		assert.Empty(t, bucket.entries)
+
+		require.NoError(t, idx.disconnectTip())
+
+		assert.Equal(t, []uint64{500, 1_000, 50_000}, values(idx.query(0, 1<<63, nil, 0)))
+		assert.Equal(t, []uint64{1_000}, values(idx.query(1_000, 1_000, nil, 0)))
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), outputValueIndexFileName)
+
+		idx := newOutputValueIndex(10)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+		require.NoError(t, idx.connectBlock(blockHash2, blockHash1, 2, []*bt.Tx{tx2}))
+		require.NoError(t, idx.save(file, 1))
+
+		loaded := newOutputValueIndex(10)
+		require.NoError(t, loaded.load(file, 1))
+
+		hash, height, ok := loaded.tipBlock()
+		require.True(t, ok)
+		assert.Equal(t, blockHash2, hash)
+		assert.Equal(t, uint32(2), height)
+		assert.Equal(t, values(idx.query(0, 1<<63, nil, 0)), values(loaded.query(0, 1<<63, nil, 0)))
+
+		// the undo data is loaded too
+		require.NoError(t, loaded.disconnectTip())
+		assert.Equal(t, []uint64{500, 1_000, 50_000}, values(loaded.query(0, 1<<63, nil, 0)))
+
+		// an index saved from another start height is discarded
+		require.Error(t, newOutputValueIndex(10).load(file, 0))
+
+		// a missing file leaves the index empty
+		empty := newOutputValueIndex(10)
+		require.NoError(t, empty.load(filepath.Join(t.TempDir(), "missing.gob"), 1))
+
+		_, _, ok = empty.tipBlock()
+		assert.False(t, ok)
+	})
+
+	t.Run("no undo data beyond max undo depth", func(t *testing.T) {
+		idx := newOutputValueIndex(1)
+		require.NoError(t, idx.connectBlock(blockHash1, chainhash.Hash{}, 1, []*bt.Tx{tx1}))
+		require.NoError(t, idx.connectBlock(blockHash2, blockHash1, 2, []*bt.Tx{tx2}))
+
+		require.NoError(t, idx.disconnectTip())
+		require.Error(t, idx.disconnectTip())
+	})
+}
+
+// outputValueIndexTestChain is a blockchain client serving the best chain and block transactions of a test
+type outputValueIndexTestChain struct {
+	blockchain.ClientI
+	headers []*model.BlockHeader // best chain by height
+	txs     map[chainhash.Hash][]*bt.Tx
+}
+
+// addBlock adds a block with the given transactions on top of the block at height-1
+func (c *outputValueIndexTestChain) addBlock(height uint32, nonce uint32, txs ...*bt.Tx) {
+	prevHash := &chainhash.Hash{}
+	if height > 0 {
+		prevHash = c.headers[height-1].Hash()
+	}
+
+	header := &model.BlockHeader{
+		Version:        1,
+		HashPrevBlock:  prevHash,
+		HashMerkleRoot: &chainhash.Hash{},
+		Timestamp:      height,
+		Nonce:          nonce,
+	}
+
+	c.headers = append(c.headers[:height], header)
+	c.txs[*header.Hash()] = txs
+}
+
+func (c *outputValueIndexTestChain) GetBestBlockHeader(_ context.Context) (*model.BlockHeader, *model.BlockHeaderMeta, error) {
+	height := uint32(len(c.headers) - 1) //nolint:gosec // test chain is small
+
+	return c.headers[height], &model.BlockHeaderMeta{Height: height}, nil
+}
+
+func (c *outputValueIndexTestChain) GetBlockHeadersByHeight(_ context.Context, startHeight, endHeight uint32) ([]*model.BlockHeader, []*model.BlockHeaderMeta, error) {
+	var headers []*model.BlockHeader
+
+	for height := startHeight; height <= endHeight && int(height) < len(c.headers); height++ {
+		headers = append(headers, c.headers[height])
+	}
+
+	return headers, nil, nil
+}
+
+func (c *outputValueIndexTestChain) getBlockTxs(_ context.Context, hash *chainhash.Hash) ([]*bt.Tx, error) {
+	return c.txs[*hash], nil
+}
+
+func TestOutputValueIndexer_Sync(t *testing.T) {
+	unindexed := []indexedOutpoint{{hash: chainhash.Hash{1}}}
+
+	tx1 := newValueIndexTestTx(t, unindexed, 1_000, 5_000)
+	tx2 := newValueIndexTestTx(t, []indexedOutpoint{outpointOf(tx1, 0)}, 2_000)
+	tx2Fork := newValueIndexTestTx(t, []indexedOutpoint{outpointOf(tx1, 1)}, 3_000)
+	tx3Fork := newValueIndexTestTx(t, []indexedOutpoint{outpointOf(tx2Fork, 0)}, 4_000)
+
+	chain := &outputValueIndexTestChain{txs: make(map[chainhash.Hash][]*bt.Tx)}
+	chain.addBlock(0, 0)
+	chain.addBlock(1, 0, tx1)
+	chain.addBlock(2, 0, tx2)
+
+	index := newOutputValueIndex(10)
+	indexer := &outputValueIndexer{
+		logger:           ulogger.TestLogger{},
+		blockchainClient: chain,
+		index:            index,
+		getBlockTxs:      chain.getBlockTxs,
+	}
+
+	ctx := context.Background()
+
+	require.NoError(t, indexer.sync(ctx))
+	assert.Equal(t, []uint64{5_000, 2_000}, values(index.query(0, 10_000, nil, 0)))
+
+	// reorg replacing block 2 with a longer fork spending the other output of tx1
+	chain.addBlock(2, 1, tx2Fork)
+	chain.addBlock(3, 1, tx3Fork)
+
+	require.NoError(t, indexer.sync(ctx))
+
+	hash, height, ok := index.tipBlock()
+	require.True(t, ok)
+	assert.Equal(t, *chain.headers[3].Hash(), hash)
+	assert.Equal(t, uint32(3), height)
+
+	assert.Equal(t, []uint64{1_000, 4_000}, values(index.query(0, 10_000, nil, 0)))
+	assert.Empty(t, index.query(2_000, 3_000, nil, 0))
+
+	t.Run("reorg deeper than the undo data re-indexes from the start height", func(t *testing.T) {
+		index := newOutputValueIndex(1)
+		indexer := &outputValueIndexer{
+			logger:           ulogger.TestLogger{},
+			blockchainClient: chain,
+			index:            index,
+			startHeight:      1,
+			getBlockTxs:      chain.getBlockTxs,
+		}
+
+		require.NoError(t, indexer.sync(ctx))
+		assert.Equal(t, []uint64{1_000, 4_000}, values(index.query(0, 10_000, nil, 0)))
+
+		chain.addBlock(1, 2, tx1)
+		chain.addBlock(2, 2, tx2)
+
+		require.NoError(t, indexer.sync(ctx))
+		assert.Equal(t, []uint64{5_000, 2_000}, values(index.query(0, 10_000, nil, 0)))
+	})
+}
+
+func TestReadWireBlockTxs(t *testing.T) {
+	tx := newValueIndexTestTx(t, nil, 1_000)
+
+	block := bytes.NewBuffer(make([]byte, model.BlockHeaderSize))
+	require.NoError(t, wire.WriteVarInt(block, 0, 1))
+	block.Write(tx.Bytes())
+
+	txs, err := readWireBlockTxs(block)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, tx.TxID(), txs[0].TxID())
+
+	t.Run("transaction count exceeding the transactions", func(t *testing.T) {
+		block := bytes.NewBuffer(make([]byte, model.BlockHeaderSize))
+		require.NoError(t, wire.WriteVarInt(block, 0, math.MaxUint64))
+
+		_, err := readWireBlockTxs(block)
+		require.Error(t, err)
+	})
+}
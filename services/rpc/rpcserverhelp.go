@@ -718,6 +718,39 @@ var helpDescsEnUS = map[string]string{
 	"setban-bantime":  "time in seconds how long (or until when if [absolute] is set) the ip is banned (0 or empty means using the default time of 24h which can also be overwritten by the -bantime startup argument)",
 	"setban-absolute": "If set, the bantime must be a absolute timestamp in seconds since epoch (Jan 1 1970 GMT)",
 
+	// DiagnoseRawTransactionCmd help
+	"diagnoserawtransaction-synopsis": "Validates a raw transaction in diagnostic mode without submitting it, returning the result of every validation stage.",
+	"diagnoserawtransaction-hextx":    "Serialized, hex-encoded signed transaction",
+
+	// DiagnoseRawTransactionResult help
+	"diagnoserawtransactionresult-txid":     "The hash of the transaction",
+	"diagnoserawtransactionresult-height":   "The block height the transaction was validated at",
+	"diagnoserawtransactionresult-accepted": "Whether the transaction passed all validation stages",
+	"diagnoserawtransactionresult-stages":   "The result of every validation stage, in the order the stages were run",
+
+	// DiagnoseRawTransactionStage help
+	"diagnoserawtransactionstage-stage":   "The name of the stage (inputs, structural, policy, fee or script)",
+	"diagnoserawtransactionstage-passed":  "Whether the transaction passed all checks of the stage",
+	"diagnoserawtransactionstage-skipped": "Whether the stage was skipped because the outputs spent by the transaction are not available",
+	"diagnoserawtransactionstage-error":   "The reason the stage failed or was skipped",
+
+	// GetOutputsByValueCmd help
+	"getoutputsbyvalue-synopsis": "Returns a page of the unspent outputs with a value in the given range, requires the output value index to be enabled.",
+	"getoutputsbyvalue-min":      "The minimum output value in satoshis (inclusive)",
+	"getoutputsbyvalue-max":      "The maximum output value in satoshis (inclusive)",
+	"getoutputsbyvalue-limit":    "The maximum number of outputs returned, capped by rpc_outputValueIndexMaxResults",
+	"getoutputsbyvalue-cursor":   "The cursor returned by the previous call, to return the outputs following the previous page",
+
+	// GetOutputsByValueResult help
+	"getoutputsbyvalueresult-outputs": "The matching outputs, ordered by block height, transaction ID and output index",
+	"getoutputsbyvalueresult-cursor":  "The cursor to pass to the next call when more outputs match, omitted on the last page",
+
+	// GetOutputsByValueOutput help
+	"getoutputsbyvalueoutput-txid":   "The hash of the transaction",
+	"getoutputsbyvalueoutput-vout":   "The index of the output",
+	"getoutputsbyvalueoutput-value":  "The value of the output in satoshis",
+	"getoutputsbyvalueoutput-height": "The height of the block containing the transaction",
+
 	// GetBlockByHeightCmd help
 	"getblockbyheight-synopsis":  "Returns information about a block by block height.",
 	"getblockbyheight-height":    "The block height",
@@ -742,56 +775,58 @@ var helpDescsEnUS = map[string]string{
 // This information is used to generate the help.  Each result type must be a
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[string][]interface{}{
-	"addnode":               nil,
-	"createrawtransaction":  {(*string)(nil)},
-	"debuglevel":            {(*string)(nil), (*string)(nil)},
-	"decoderawtransaction":  {(*bsvjson.TxRawDecodeResult)(nil)},
-	"decodescript":          {(*bsvjson.DecodeScriptResult)(nil)},
-	"estimatefee":           {(*float64)(nil)},
-	"generate":              {(*[]string)(nil)},
-	"getaddednodeinfo":      {(*[]string)(nil), (*[]bsvjson.GetAddedNodeInfoResult)(nil)},
-	"getbestblock":          {(*bsvjson.GetBestBlockResult)(nil)},
-	"getbestblockhash":      {(*string)(nil)},
-	"getblock":              {(*string)(nil), (*bsvjson.GetBlockVerboseResult)(nil), (*bsvjson.GetBlockVerboseTxResult)(nil)},
-	"getblockcount":         {(*int64)(nil)},
-	"getblockhash":          {(*string)(nil)},
-	"getblockheader":        {(*string)(nil), (*bsvjson.GetBlockHeaderVerboseResult)(nil)},
-	"getblocktemplate":      {(*bsvjson.GetBlockTemplateResult)(nil), (*string)(nil), nil},
-	"getblockchaininfo":     {(*bsvjson.GetBlockChainInfoResult)(nil)},
-	"getcfilter":            {(*string)(nil)},
-	"getcfilterheader":      {(*string)(nil)},
-	"getconnectioncount":    {(*int32)(nil)},
-	"getcurrentnet":         {(*uint32)(nil)},
-	"getdifficulty":         {(*float64)(nil)},
-	"getgenerate":           {(*bool)(nil)},
-	"gethashespersec":       {(*float64)(nil)},
-	"getheaders":            {(*[]string)(nil)},
-	"getinfo":               {(*bsvjson.InfoChainResult)(nil)},
-	"getmempoolinfo":        {(*bsvjson.GetMempoolInfoResult)(nil)},
-	"getmininginfo":         {(*bsvjson.GetMiningInfoResult)(nil)},
-	"getnettotals":          {(*bsvjson.GetNetTotalsResult)(nil)},
-	"getnetworkhashps":      {(*float64)(nil)},
-	"getpeerinfo":           {(*[]bsvjson.GetPeerInfoResult)(nil)},
-	"getrawmempool":         {(*[]string)(nil), (*bsvjson.GetRawMempoolVerboseResult)(nil)},
-	"getrawtransaction":     {(*string)(nil), (*bsvjson.TxRawResult)(nil)},
-	"gettxout":              {(*bsvjson.GetTxOutResult)(nil)},
-	"gettxoutproof":         {(*string)(nil)},
-	"node":                  nil,
-	"help":                  {(*string)(nil), (*string)(nil)},
-	"ping":                  nil,
-	"reconsiderblock":       nil,
-	"searchrawtransactions": {(*string)(nil), (*[]bsvjson.SearchRawTransactionsResult)(nil)},
-	"sendrawtransaction":    {(*string)(nil)},
-	"setban":                nil,
-	"setgenerate":           nil,
-	"stop":                  {(*string)(nil)},
-	"submitblock":           {nil, (*string)(nil)},
-	"uptime":                {(*int64)(nil)},
-	"validateaddress":       {(*bsvjson.ValidateAddressChainResult)(nil)},
-	"verifychain":           {(*bool)(nil)},
-	"verifymessage":         {(*bool)(nil)},
-	"verifytxoutproof":      {(*[]string)(nil)},
-	"version":               {(*map[string]bsvjson.VersionResult)(nil)},
+	"addnode":                nil,
+	"createrawtransaction":   {(*string)(nil)},
+	"debuglevel":             {(*string)(nil), (*string)(nil)},
+	"decoderawtransaction":   {(*bsvjson.TxRawDecodeResult)(nil)},
+	"decodescript":           {(*bsvjson.DecodeScriptResult)(nil)},
+	"diagnoserawtransaction": {(*bsvjson.DiagnoseRawTransactionResult)(nil)},
+	"estimatefee":            {(*float64)(nil)},
+	"generate":               {(*[]string)(nil)},
+	"getaddednodeinfo":       {(*[]string)(nil), (*[]bsvjson.GetAddedNodeInfoResult)(nil)},
+	"getbestblock":           {(*bsvjson.GetBestBlockResult)(nil)},
+	"getbestblockhash":       {(*string)(nil)},
+	"getblock":               {(*string)(nil), (*bsvjson.GetBlockVerboseResult)(nil), (*bsvjson.GetBlockVerboseTxResult)(nil)},
+	"getblockcount":          {(*int64)(nil)},
+	"getblockhash":           {(*string)(nil)},
+	"getblockheader":         {(*string)(nil), (*bsvjson.GetBlockHeaderVerboseResult)(nil)},
+	"getblocktemplate":       {(*bsvjson.GetBlockTemplateResult)(nil), (*string)(nil), nil},
+	"getblockchaininfo":      {(*bsvjson.GetBlockChainInfoResult)(nil)},
+	"getcfilter":             {(*string)(nil)},
+	"getcfilterheader":       {(*string)(nil)},
+	"getconnectioncount":     {(*int32)(nil)},
+	"getcurrentnet":          {(*uint32)(nil)},
+	"getdifficulty":          {(*float64)(nil)},
+	"getgenerate":            {(*bool)(nil)},
+	"gethashespersec":        {(*float64)(nil)},
+	"getheaders":             {(*[]string)(nil)},
+	"getinfo":                {(*bsvjson.InfoChainResult)(nil)},
+	"getmempoolinfo":         {(*bsvjson.GetMempoolInfoResult)(nil)},
+	"getmininginfo":          {(*bsvjson.GetMiningInfoResult)(nil)},
+	"getnettotals":           {(*bsvjson.GetNetTotalsResult)(nil)},
+	"getnetworkhashps":       {(*float64)(nil)},
+	"getoutputsbyvalue":      {(*bsvjson.GetOutputsByValueResult)(nil)},
+	"getpeerinfo":            {(*[]bsvjson.GetPeerInfoResult)(nil)},
+	"getrawmempool":          {(*[]string)(nil), (*bsvjson.GetRawMempoolVerboseResult)(nil)},
+	"getrawtransaction":      {(*string)(nil), (*bsvjson.TxRawResult)(nil)},
+	"gettxout":               {(*bsvjson.GetTxOutResult)(nil)},
+	"gettxoutproof":          {(*string)(nil)},
+	"node":                   nil,
+	"help":                   {(*string)(nil), (*string)(nil)},
+	"ping":                   nil,
+	"reconsiderblock":        nil,
+	"searchrawtransactions":  {(*string)(nil), (*[]bsvjson.SearchRawTransactionsResult)(nil)},
+	"sendrawtransaction":     {(*string)(nil)},
+	"setban":                 nil,
+	"setgenerate":            nil,
+	"stop":                   {(*string)(nil)},
+	"submitblock":            {nil, (*string)(nil)},
+	"uptime":                 {(*int64)(nil)},
+	"validateaddress":        {(*bsvjson.ValidateAddressChainResult)(nil)},
+	"verifychain":            {(*bool)(nil)},
+	"verifymessage":          {(*bool)(nil)},
+	"verifytxoutproof":       {(*[]string)(nil)},
+	"version":                {(*map[string]bsvjson.VersionResult)(nil)},
 
 	// Websocket commands.
 	"loadtxfilter":              nil,
@@ -16,6 +16,7 @@ import (
 
 	"github.com/bsv-blockchain/teranode/services/rpc/bsvjson"
 	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/util"
 	"github.com/bsv-blockchain/teranode/util/test/mocklogger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1062,3 +1063,40 @@ func TestStart(t *testing.T) {
 		assert.Equal(t, int32(3), atomic.LoadInt32(&s.started))
 	})
 }
+
+// TestCheckTxRateLimit tests the per client rate limiting of sendrawtransaction
+func TestCheckTxRateLimit(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		s := &RPCServer{logger: mocklogger.NewTestLogger()}
+
+		for i := 0; i < 100; i++ {
+			require.NoError(t, s.checkTxRateLimit("192.0.2.1:1234"))
+		}
+	})
+
+	t.Run("throttled and recovers", func(t *testing.T) {
+		s := &RPCServer{
+			logger:        mocklogger.NewTestLogger(),
+			txRateLimiter: util.NewSourceRateLimiter(10, 2),
+		}
+
+		require.NoError(t, s.checkTxRateLimit("192.0.2.1:1234"))
+		require.NoError(t, s.checkTxRateLimit("192.0.2.1:1235"))
+
+		// the client is throttled, regardless of the port it connects from
+		err := s.checkTxRateLimit("192.0.2.1:1236")
+		require.Error(t, err)
+
+		var rpcErr *bsvjson.RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, bsvjson.ErrRPCMisc, rpcErr.Code)
+		assert.Contains(t, rpcErr.Message, "transaction rate limit of 10.00 tx/s exceeded")
+
+		// other clients are not affected
+		require.NoError(t, s.checkTxRateLimit("192.0.2.2:1234"))
+
+		// the client recovers once its rate allows a new transaction
+		time.Sleep(150 * time.Millisecond)
+		require.NoError(t, s.checkTxRateLimit("192.0.2.1:1234"))
+	})
+}
@@ -0,0 +1,179 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/ulogger"
+)
+
+const (
+	// unconfirmedTxMinCheckInterval and unconfirmedTxMaxCheckInterval bound the interval at which the
+	// tracked transactions are checked, a tenth of the alert age
+	unconfirmedTxMinCheckInterval = time.Second
+	unconfirmedTxMaxCheckInterval = time.Minute
+
+	// unconfirmedTxAlertKind is the kind of the alerts raised by the tracker, all coalesced together
+	unconfirmedTxAlertKind = "unconfirmed transaction"
+)
+
+// unconfirmedTxTracker tracks the transactions sent via sendrawtransaction and raises an alert, once per
+// transaction, when a transaction is still not mined after the configured alert age.
+//
+// A transaction is only looked up in the UTXO store once its alert age is reached, after which it is no
+// longer tracked, whether it was mined or an alert was raised. When an alert limiter is set, the alerts over its
+// limit are coalesced into a single warning reporting their count.
+type unconfirmedTxTracker struct {
+	logger    ulogger.Logger
+	utxoStore utxo.Store
+	alertAge  time.Duration
+
+	mu  sync.Mutex
+	txs map[chainhash.Hash]time.Time // time the transaction was sent, by transaction hash
+
+	// limiter optionally limits the number of alerts raised per interval, only used by check
+	limiter *alertLimiter
+
+	// onAlert is called for every alert raised, after the alert is logged and counted
+	onAlert func(hash chainhash.Hash, age time.Duration)
+
+	// onAlertsCoalesced is called with the number of alerts coalesced in an interval, after it is logged
+	onAlertsCoalesced func(count int)
+}
+
+// newUnconfirmedTxTracker creates a tracker alerting on transactions not mined after alertAge.
+func newUnconfirmedTxTracker(logger ulogger.Logger, utxoStore utxo.Store, alertAge time.Duration) *unconfirmedTxTracker {
+	return &unconfirmedTxTracker{
+		logger:    logger,
+		utxoStore: utxoStore,
+		alertAge:  alertAge,
+		txs:       make(map[chainhash.Hash]time.Time),
+	}
+}
+
+// Track starts tracking the transaction, sent at the given time.
+func (t *unconfirmedTxTracker) Track(hash chainhash.Hash, sentAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.txs[hash]; !ok {
+		t.txs[hash] = sentAt
+	}
+}
+
+// Len returns the number of tracked transactions.
+func (t *unconfirmedTxTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.txs)
+}
+
+// Start checks the tracked transactions at a regular interval until the context is done.
+func (t *unconfirmedTxTracker) Start(ctx context.Context) {
+	interval := t.alertAge / 10
+	if interval < unconfirmedTxMinCheckInterval {
+		interval = unconfirmedTxMinCheckInterval
+	} else if interval > unconfirmedTxMaxCheckInterval {
+		interval = unconfirmedTxMaxCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			t.check(ctx, now)
+		}
+	}
+}
+
+// check looks up the tracked transactions that reached the alert age at the given time, raising an
+// alert for every transaction that is not mined.
+func (t *unconfirmedTxTracker) check(ctx context.Context, now time.Time) {
+	type dueTx struct {
+		hash   chainhash.Hash
+		sentAt time.Time
+	}
+
+	t.reportCoalescedAlerts(now)
+
+	t.mu.Lock()
+
+	due := make([]dueTx, 0)
+
+	for hash, sentAt := range t.txs {
+		if now.Sub(sentAt) >= t.alertAge {
+			due = append(due, dueTx{hash: hash, sentAt: sentAt})
+		}
+	}
+
+	t.mu.Unlock()
+
+	for _, tx := range due {
+		mined, err := t.isMined(ctx, &tx.hash)
+		if err != nil {
+			// checked again in the next round
+			t.logger.Warnf("[unconfirmedTxTracker][%s] failed to check whether the transaction is mined: %v", tx.hash.String(), err)
+			continue
+		}
+
+		t.mu.Lock()
+		delete(t.txs, tx.hash)
+		t.mu.Unlock()
+
+		if mined {
+			continue
+		}
+
+		age := now.Sub(tx.sentAt)
+
+		prometheusUnconfirmedTxAlerts.Inc()
+
+		if !t.limiter.allow(unconfirmedTxAlertKind, now) {
+			continue
+		}
+
+		t.logger.Warnf("[unconfirmedTxTracker][%s] transaction is still unconfirmed %s after it was sent, alert age is %s", tx.hash.String(), age, t.alertAge)
+
+		if t.onAlert != nil {
+			t.onAlert(tx.hash, age)
+		}
+	}
+}
+
+// reportCoalescedAlerts raises a single alert with the number of alerts coalesced by the alert limiter, once the
+// interval they were coalesced in ended.
+func (t *unconfirmedTxTracker) reportCoalescedAlerts(now time.Time) {
+	for kind, count := range t.limiter.flush(now) {
+		t.logger.Warnf("[unconfirmedTxTracker] %d more %s alerts were coalesced, at most %d alerts are raised per %s", count, kind, t.limiter.maxAlerts, t.limiter.interval)
+		prometheusCoalescedAlerts.Add(float64(count))
+
+		if t.onAlertsCoalesced != nil {
+			t.onAlertsCoalesced(count)
+		}
+	}
+}
+
+// isMined returns whether the transaction is mined in at least one block. A transaction that is not
+// found in the UTXO store, e.g. because it was removed as a conflict, is not mined.
+func (t *unconfirmedTxTracker) isMined(ctx context.Context, hash *chainhash.Hash) (bool, error) {
+	txMeta, err := t.utxoStore.Get(ctx, hash, fields.BlockIDs)
+	if err != nil {
+		if errors.Is(err, errors.ErrTxNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return len(txMeta.BlockIDs) > 0, nil
+}
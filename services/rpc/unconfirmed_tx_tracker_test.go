@@ -0,0 +1,140 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnconfirmedTxTracker(t *testing.T) {
+	initPrometheusMetrics()
+
+	ctx := context.Background()
+	alertAge := 10 * time.Minute
+	sentAt := time.Unix(1_700_000_000, 0)
+
+	unconfirmedTx := chainhash.HashH([]byte("unconfirmed"))
+	minedTx := chainhash.HashH([]byte("mined"))
+
+	newTracker := func() (*unconfirmedTxTracker, *utxo.MockUtxostore, *[]chainhash.Hash) {
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("Get", mock.Anything, &unconfirmedTx, mock.Anything).Return(&meta.Data{}, nil)
+		mockUtxoStore.On("Get", mock.Anything, &minedTx, mock.Anything).Return(&meta.Data{BlockIDs: []uint32{42}}, nil)
+
+		tracker := newUnconfirmedTxTracker(ulogger.TestLogger{}, mockUtxoStore, alertAge)
+
+		alerts := make([]chainhash.Hash, 0)
+		tracker.onAlert = func(hash chainhash.Hash, age time.Duration) {
+			assert.GreaterOrEqual(t, age, alertAge)
+
+			alerts = append(alerts, hash)
+		}
+
+		return tracker, mockUtxoStore, &alerts
+	}
+
+	t.Run("alert fires at the threshold and not before", func(t *testing.T) {
+		tracker, mockUtxoStore, alerts := newTracker()
+		tracker.Track(unconfirmedTx, sentAt)
+
+		tracker.check(ctx, sentAt.Add(alertAge-time.Nanosecond))
+		assert.Empty(t, *alerts)
+		mockUtxoStore.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+
+		tracker.check(ctx, sentAt.Add(alertAge))
+		assert.Equal(t, []chainhash.Hash{unconfirmedTx}, *alerts)
+		assert.Equal(t, 0, tracker.Len())
+	})
+
+	t.Run("alert fires only once", func(t *testing.T) {
+		tracker, _, alerts := newTracker()
+		tracker.Track(unconfirmedTx, sentAt)
+
+		tracker.check(ctx, sentAt.Add(alertAge))
+		tracker.check(ctx, sentAt.Add(2*alertAge))
+
+		assert.Len(t, *alerts, 1)
+	})
+
+	t.Run("mined transaction does not alert", func(t *testing.T) {
+		tracker, _, alerts := newTracker()
+		tracker.Track(minedTx, sentAt)
+		tracker.Track(unconfirmedTx, sentAt.Add(time.Minute))
+
+		tracker.check(ctx, sentAt.Add(alertAge))
+		assert.Empty(t, *alerts)
+		assert.Equal(t, 1, tracker.Len(), "only the mined transaction is done")
+
+		tracker.check(ctx, sentAt.Add(time.Minute+alertAge))
+		assert.Equal(t, []chainhash.Hash{unconfirmedTx}, *alerts)
+	})
+
+	t.Run("store error retries in the next round", func(t *testing.T) {
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("Get", mock.Anything, &unconfirmedTx, mock.Anything).Return(nil, errors.NewStorageUnavailableError("connection refused")).Once()
+		mockUtxoStore.On("Get", mock.Anything, &unconfirmedTx, mock.Anything).Return(nil, errors.NewTxNotFoundError("not found"))
+
+		tracker := newUnconfirmedTxTracker(ulogger.TestLogger{}, mockUtxoStore, alertAge)
+
+		alerted := 0
+		tracker.onAlert = func(chainhash.Hash, time.Duration) { alerted++ }
+
+		tracker.Track(unconfirmedTx, sentAt)
+
+		tracker.check(ctx, sentAt.Add(alertAge))
+		assert.Equal(t, 0, alerted)
+		require.Equal(t, 1, tracker.Len())
+
+		tracker.check(ctx, sentAt.Add(alertAge+time.Second))
+		assert.Equal(t, 1, alerted)
+		assert.Equal(t, 0, tracker.Len())
+	})
+
+	t.Run("repeated alerts are coalesced within the window", func(t *testing.T) {
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(&meta.Data{}, nil)
+
+		tracker := newUnconfirmedTxTracker(ulogger.TestLogger{}, mockUtxoStore, alertAge)
+		tracker.limiter = newAlertLimiter(2, time.Minute)
+
+		alerted := 0
+		tracker.onAlert = func(chainhash.Hash, time.Duration) { alerted++ }
+
+		coalesced := make([]int, 0)
+		tracker.onAlertsCoalesced = func(count int) { coalesced = append(coalesced, count) }
+
+		for i := 0; i < 5; i++ {
+			tracker.Track(chainhash.HashH([]byte{byte(i)}), sentAt)
+		}
+
+		tracker.check(ctx, sentAt.Add(alertAge))
+		assert.Equal(t, 2, alerted)
+		assert.Empty(t, coalesced, "the coalesced alerts are reported once the window ended")
+
+		// a later alert within the window is coalesced as well
+		tracker.Track(chainhash.HashH([]byte("late")), sentAt.Add(30*time.Second))
+
+		tracker.check(ctx, sentAt.Add(alertAge+30*time.Second))
+		assert.Equal(t, 2, alerted)
+		assert.Empty(t, coalesced)
+
+		// the next window reports the count of the coalesced alerts, and raises alerts again
+		tracker.Track(chainhash.HashH([]byte("next")), sentAt.Add(time.Minute))
+
+		tracker.check(ctx, sentAt.Add(alertAge+time.Minute))
+		assert.Equal(t, []int{4}, coalesced)
+		assert.Equal(t, 3, alerted)
+
+		tracker.check(ctx, sentAt.Add(alertAge+2*time.Minute))
+		assert.Equal(t, []int{4}, coalesced, "the coalesced alerts are reported once")
+	})
+}
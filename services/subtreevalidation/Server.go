@@ -77,6 +77,9 @@ type Server struct {
 	// It's used during transaction validation to verify input spending
 	utxoStore utxo.Store
 
+	// utxoResolver resolves the transaction metadata and parents, the utxoStore is used when it is nil
+	utxoResolver UTXOResolver
+
 	// validatorClient provides transaction validation services
 	// It's used to validate transactions against consensus rules
 	validatorClient validator.Interface
@@ -110,6 +113,10 @@ type Server struct {
 	// invalidSubtreeKafkaProducer publishes invalid subtree events to Kafka
 	invalidSubtreeKafkaProducer kafka.KafkaAsyncProducerI
 
+	// subtreeValidationResultKafkaProducer publishes an audit record of every subtree validation to Kafka,
+	// nil when kafka_subtreeValidationResultsConfig is not set
+	subtreeValidationResultKafkaProducer kafka.KafkaAsyncProducerI
+
 	// invalidSubtreeLock is used to synchronize access to the invalid subtree producer
 	invalidSubtreeLock sync.Mutex
 
@@ -134,6 +141,24 @@ type Server struct {
 	// p2pClient interfaces with the P2P service
 	// Used to report successful subtree fetches to improve peer reputation
 	p2pClient P2PClientI
+
+	// peerSubtreeLimiter caps the number of concurrent subtree requests to a single peer
+	peerSubtreeLimiter *util.PeerConcurrencyLimiter
+
+	// preValidatedTxs is the allowlist of pre-validated transactions, of which the scripts are not verified again,
+	// nil when PreValidatedAllowlistTTL is 0
+	preValidatedTxs *expiringmap.ExpiringMap[chainhash.Hash, struct{}]
+
+	// subtreeResultCache caches the verdicts of validated subtrees by merkle root, so a subtree presented again is
+	// not validated again, nil when ResultCacheSize is 0
+	subtreeResultCache *subtreeResultCache
+
+	// parentFetcher fetches the parents outside a subtree missing from the UTXO store, nil when not set
+	parentFetcher ParentFetcher
+
+	// subtreeBacklog is the number of subtrees awaiting validation, announced subtrees are not validated when it
+	// would exceed the configured maximum, see acquireSubtreeBacklog
+	subtreeBacklog atomic.Int64
 }
 
 var (
@@ -197,6 +222,15 @@ func New(
 		txmetaConsumerClient:              txmetaConsumerClient,
 		invalidSubtreeDeDuplicateMap:      expiringmap.New[string, struct{}](time.Minute * 1),
 		p2pClient:                         p2pClient,
+		peerSubtreeLimiter:                util.NewPeerConcurrencyLimiter(tSettings.SubtreeValidation.SubtreeFetchConcurrencyPerPeer),
+	}
+
+	if tSettings.SubtreeValidation.PreValidatedAllowlistTTL > 0 {
+		u.preValidatedTxs = expiringmap.New[chainhash.Hash, struct{}](tSettings.SubtreeValidation.PreValidatedAllowlistTTL)
+	}
+
+	if tSettings.SubtreeValidation.ResultCacheSize > 0 {
+		u.subtreeResultCache = newSubtreeResultCache(tSettings.SubtreeValidation.ResultCacheSize)
 	}
 
 	var err error
@@ -207,6 +241,8 @@ func New(
 		return nil, errors.NewConfigurationError("Failed to create orphanage: %v", err)
 	}
 
+	u.orphanage.SetMaxPerPeer(tSettings.SubtreeValidation.OrphanageMaxPerPeer)
+
 	once.Do(func() {
 		quorumPath := tSettings.SubtreeValidation.QuorumPath
 		if quorumPath == "" {
@@ -259,6 +295,18 @@ func New(
 		logger.Infof("No Kafka topic configured for invalid subtrees")
 	}
 
+	// Initialize Kafka producer for the subtree validation audit records if configured
+	if subtreeValidationResultsURL := tSettings.Kafka.SubtreeValidationResultsConfig; subtreeValidationResultsURL != nil {
+		logger.Infof("Initializing Kafka producer for subtree validation results topic: %s", subtreeValidationResultsURL.Path)
+
+		u.subtreeValidationResultKafkaProducer, err = kafka.NewKafkaAsyncProducerFromURL(ctx, logger, subtreeValidationResultsURL, &tSettings.Kafka)
+		if err != nil {
+			return nil, errors.NewServiceError("could not create subtree validation results kafka producer", err)
+		}
+
+		go u.subtreeValidationResultKafkaProducer.Start(ctx, make(chan *kafka.Message, 1_000))
+	}
+
 	// get and set the initial best block
 	if err = u.updateBestBlock(ctx); err != nil {
 		logger.Errorf("[SubtreeValidation] failed to get initial best block: %s", err)
@@ -347,7 +395,13 @@ func (u *Server) updateBestBlock(ctx context.Context) error {
 		return errors.NewProcessingError("[SubtreeValidation:blockchainSubscriptionListener] failed to get best block header: %s", err)
 	}
 
-	u.bestBlockHeader.Store(bestBlockHeader)
+	// a best block not building on the previous best block is a reorg, the transactions of the cached subtrees may
+	// have been double spent by the new chain
+	if previousBestBlockHeader := u.bestBlockHeader.Swap(bestBlockHeader); previousBestBlockHeader != nil &&
+		!bestBlockHeader.HashPrevBlock.IsEqual(previousBestBlockHeader.Hash()) && !bestBlockHeader.Hash().IsEqual(previousBestBlockHeader.Hash()) {
+		u.invalidateSubtreeResults()
+	}
+
 	u.bestBlockHeaderMeta.Store(bestBlockHeaderMeta)
 	u.subtreeStore.SetCurrentBlockHeight(bestBlockHeaderMeta.Height)
 
@@ -593,20 +647,31 @@ func (u *Server) Stop(_ context.Context) error {
 //     block height, and block hash information
 //
 // Returns:
-//   - *CheckSubtreeFromBlockResponse: Response indicating validation success or failure
+//   - *CheckSubtreeFromBlockResponse: Response indicating validation success or failure, with the max level and
+//     the number of transactions of each dependency level of the transactions validated for the subtree
 //   - error: Any error encountered during validation with appropriate gRPC status codes
 //
 // The method will retry lock acquisition for up to 20 seconds with exponential backoff,
 // making it resilient to temporary contention when multiple services attempt to validate
 // the same subtree simultaneously.
 func (u *Server) CheckSubtreeFromBlock(ctx context.Context, request *subtreevalidation_api.CheckSubtreeFromBlockRequest) (*subtreevalidation_api.CheckSubtreeFromBlockResponse, error) {
+	ctx, stats := contextWithLevelStats(ctx)
+
+	// the subtree of a block counts towards the backlog but is never rejected, the block cannot be processed without it
+	u.addSubtreeBacklog(1)
+	defer u.releaseSubtreeBacklog(1)
+
 	subtreeBlessed, err := u.checkSubtreeFromBlock(ctx, request)
 	if err != nil {
 		return nil, errors.WrapGRPC(err)
 	}
 
+	maxLevel, txsPerLevel := stats.get()
+
 	return &subtreevalidation_api.CheckSubtreeFromBlockResponse{
-		Blessed: subtreeBlessed,
+		Blessed:     subtreeBlessed,
+		MaxLevel:    maxLevel,
+		TxsPerLevel: txsPerLevel,
 	}, nil
 }
 
@@ -769,13 +834,15 @@ func (u *Server) checkSubtreeFromBlock(ctx context.Context, request *subtreevali
 
 		// Call the validateSubtreeInternal method
 		// making sure to skip policy checks, since we are validating a block that has already been mined
-		if _, err = u.ValidateSubtreeInternal(
-			ctx,
-			v,
-			request.BlockHeight,
-			blockIds,
-			validatorOptions...,
-		); err != nil {
+		if _, err = u.validateBlockSubtreeWithRetry(ctx, *hash, func() (*subtreepkg.Subtree, error) {
+			return u.ValidateSubtreeInternal(
+				ctx,
+				v,
+				request.BlockHeight,
+				blockIds,
+				validatorOptions...,
+			)
+		}); err != nil {
 			return false, errors.NewProcessingError("[CheckSubtree] Failed to validate legacy subtree %s", hash.String(), err)
 		}
 
@@ -791,16 +858,18 @@ func (u *Server) checkSubtreeFromBlock(ctx context.Context, request *subtreevali
 		AllowFailFast: false,
 	}
 
-	// Call the ValidateSubtreeInternal method
-	if subtree, err = u.ValidateSubtreeInternal(
-		ctx,
-		v,
-		request.BlockHeight,
-		blockIds,
-		validator.WithSkipPolicyChecks(true),
-		validator.WithCreateConflicting(true),
-		validator.WithIgnoreLocked(true),
-	); err != nil {
+	// Call the ValidateSubtreeInternal method, retrying transient store or network errors
+	if subtree, err = u.validateBlockSubtreeWithRetry(ctx, *hash, func() (*subtreepkg.Subtree, error) {
+		return u.ValidateSubtreeInternal(
+			ctx,
+			v,
+			request.BlockHeight,
+			blockIds,
+			validator.WithSkipPolicyChecks(true),
+			validator.WithCreateConflicting(true),
+			validator.WithIgnoreLocked(true),
+		)
+	}); err != nil {
 		return false, errors.NewProcessingError("[CheckSubtree] Failed to validate subtree %s", hash.String(), err)
 	}
 
@@ -836,7 +905,7 @@ func (u *Server) processOrphans(ctx context.Context, blockHash chainhash.Hash, b
 		u.logger.Infof("[CheckSubtreeFromBlock] Processing orphaned transactions after subtree validation, count: %d", u.orphanage.Len())
 
 		processedOrphans := atomic.Uint32{}
-		processedValidatorOptions := validator.ProcessOptions()
+		processedValidatorOptions := validator.ProcessOptions(validator.WithBacklogExempt(true))
 		orphanTxs := u.orphanage.Items()
 
 		// first we need to process all the orphans into levels, making sure we process them
@@ -854,16 +923,18 @@ func (u *Server) processOrphans(ctx context.Context, blockHash chainhash.Hash, b
 			return
 		}
 
+		defer u.releaseTxsPerLevel(txsPerLevel)
+
 		for level := uint32(0); level <= maxLevel; level++ {
 			// we process each level of transactions in parallel
 			g, gCtx := errgroup.WithContext(ctx)
-			util.SafeSetLimit(g, u.settings.SubtreeValidation.SpendBatcherSize*2)
+			util.SafeSetLimit(g, u.levelValidationConcurrency())
 
 			for _, mTx := range txsPerLevel[level] {
 				tx := mTx.tx
 
-				g.Go(func() error {
-					txMeta, txErr := u.blessMissingTransaction(gCtx, blockHash, tx, blockHeight+1, blockIds, processedValidatorOptions)
+				g.Go(recoverLevelValidation(func() error {
+					txMeta, txErr := u.blessLevelTx(gCtx, blockHash, mTx, blockHeight+1, blockIds, processedValidatorOptions)
 					if txErr == nil && txMeta != nil {
 						// transaction was successfully blessed, now remove it from the orphanage
 						u.orphanage.Delete(*tx.TxIDChainHash())
@@ -873,7 +944,7 @@ func (u *Server) processOrphans(ctx context.Context, blockHash chainhash.Hash, b
 					}
 
 					return nil
-				})
+				}))
 			}
 
 			if err := g.Wait(); err != nil {
@@ -899,6 +970,56 @@ func initialiseInvalidSubtreeKafkaProducer(ctx context.Context, logger ulogger.L
 	return invalidSubtreeKafkaProducer, nil
 }
 
+// subtreeValidationOutcome returns the outcome of a subtree validation that ended with the given error. A
+// validation that was cancelled is not an error of the subtree, it did not complete.
+func subtreeValidationOutcome(ctx context.Context, validationErr error) kafkamessage.SubtreeValidationOutcome {
+	switch {
+	case validationErr == nil:
+		return kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_VALID
+	case ctx.Err() != nil,
+		errors.Is(validationErr, errors.ErrContextCanceled),
+		errors.Is(validationErr, context.Canceled),
+		errors.Is(validationErr, context.DeadlineExceeded):
+		return kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_CANCELLED
+	case errors.Is(validationErr, errors.ErrSubtreeInvalid), errors.Is(validationErr, errors.ErrTxInvalid):
+		return kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_INVALID
+	default:
+		return kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_ERROR
+	}
+}
+
+// publishSubtreeValidationResult publishes an audit record of a subtree validation to Kafka, with the number of
+// transactions in the subtree, the outcome and the duration of the validation.
+func (u *Server) publishSubtreeValidationResult(ctx context.Context, subtreeHash *chainhash.Hash, txCount int, start time.Time, validationErr error) {
+	if u.subtreeValidationResultKafkaProducer == nil {
+		return
+	}
+
+	msg := &kafkamessage.KafkaSubtreeValidationResultTopicMessage{
+		SubtreeHash:    subtreeHash.String(),
+		TxCount:        uint64(txCount), //nolint:gosec // G115: the tx count is never negative
+		Valid:          validationErr == nil,
+		DurationMillis: time.Since(start).Milliseconds(),
+		Timestamp:      time.Now().UnixMilli(),
+		Outcome:        subtreeValidationOutcome(ctx, validationErr),
+	}
+
+	if validationErr != nil {
+		msg.Reason = validationErr.Error()
+	}
+
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		u.logger.Errorf("[publishSubtreeValidationResult][%s] failed to marshal subtree validation result message: %v", subtreeHash.String(), err)
+		return
+	}
+
+	u.subtreeValidationResultKafkaProducer.Publish(&kafka.Message{
+		Key:   subtreeHash[:],
+		Value: msgBytes,
+	})
+}
+
 // publishInvalidSubtree publishes an invalid subtree event to Kafka
 func (u *Server) publishInvalidSubtree(ctx context.Context, subtreeHash, peerURL, reason string) {
 	if u.invalidSubtreeKafkaProducer == nil {
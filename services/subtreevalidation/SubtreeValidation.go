@@ -34,11 +34,14 @@ package subtreevalidation
 
 import (
 	"bufio"
+	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"math"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -57,6 +60,7 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
 	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/retry"
 	"github.com/bsv-blockchain/teranode/util/tracing"
 	"github.com/ordishs/gocore"
 	"golang.org/x/sync/errgroup"
@@ -77,6 +81,10 @@ type missingTx struct {
 
 	// idx is the original position of this transaction in the subtree's transaction list
 	idx int
+
+	// coinbaseHeight is the height the youngest coinbase transaction spent by this transaction was mined at,
+	// 0 when the transaction spends no coinbase or the height is not known
+	coinbaseHeight uint32
 }
 
 // SetSubtreeExists marks a subtree as existing in the local storage.
@@ -201,6 +209,35 @@ func (u *Server) DelTxMetaCacheMulti(ctx context.Context, hash *chainhash.Hash)
 	return nil
 }
 
+// doPeerRequestBodyReader does an HTTP request to a peer and returns the response body. It first waits
+// for a free request slot of the peer, which is held until the returned body has been closed, to not
+// overload a single peer with concurrent subtree requests.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - baseURL: Base URL of the peer, used to limit the concurrent requests per peer
+//   - url: URL to request
+//   - requestBody: Optional body, the request is a POST when given
+//
+// Returns:
+//   - io.ReadCloser: The response body, must be closed by the caller
+//   - error: Any error encountered while waiting for a slot or doing the request
+func (u *Server) doPeerRequestBodyReader(ctx context.Context, baseURL string, url string, requestBody ...[]byte) (io.ReadCloser, error) {
+	release, err := u.peerSubtreeLimiter.Acquire(ctx, baseURL)
+	if err != nil {
+		return nil, errors.NewContextCanceledError("cancelled while waiting for a request slot of peer %s", baseURL, err)
+	}
+
+	body, err := util.DoHTTPRequestBodyReader(ctx, url, requestBody...)
+	if err != nil {
+		release()
+
+		return nil, err
+	}
+
+	return util.ReleaseOnClose(body, release), nil
+}
+
 // getMissingTransactionsBatch retrieves a batch of transactions from the network.
 // Note: The returned transactions may not be in the same order as the input hashes.
 //
@@ -244,7 +281,7 @@ func (u *Server) getMissingTransactionsBatch(ctx context.Context, subtreeHash ch
 	url := fmt.Sprintf("%s/subtree/%s/txs", baseURL, subtreeHash.String())
 	u.logger.Debugf("[getMissingTransactionsBatch][%s] getting %d txs from peer %s", subtreeHash.String(), len(txHashes), url)
 
-	body, err := util.DoHTTPRequestBodyReader(ctx, url, txIDBytes)
+	body, err := u.doPeerRequestBodyReader(ctx, baseURL, url, txIDBytes)
 	if err != nil {
 		// Peer cannot provide requested transactions - report as invalid subtree
 		u.publishInvalidSubtree(ctx, subtreeHash.String(), baseURL, "peer_cannot_provide_transactions")
@@ -367,7 +404,7 @@ func (u *Server) blessMissingTransaction(ctx context.Context, subtreeHash chainh
 
 	// validate the transaction in the validation service
 	// this should spend utxos, create the tx meta and create new utxos
-	txMeta, err = u.validatorClient.ValidateWithOptions(ctx, tx, blockHeight, validationOptions)
+	txMeta, err = u.validateWithParentRetry(ctx, tx, blockHeight, u.preValidatedOptions(tx, validationOptions))
 	if err != nil {
 		if errors.Is(err, errors.ErrTxConflicting) {
 			// conflicting transaction, which has been saved, but not spent
@@ -400,6 +437,37 @@ func (u *Server) blessMissingTransaction(ctx context.Context, subtreeHash chainh
 	return txMeta, nil
 }
 
+// blessLevelTx validates a transaction of a dependency level with blessMissingTransaction, after checking that the
+// coinbase outputs it spends, as classified when the levels were prepared, are mature at the block height.
+func (u *Server) blessLevelTx(ctx context.Context, subtreeHash chainhash.Hash, mTx missingTx, blockHeight uint32,
+	blockIds map[uint32]bool, validationOptions *validator.Options) (*meta.Data, error) {
+	if err := u.checkCoinbaseMaturity(subtreeHash, mTx, blockHeight); err != nil {
+		return nil, err
+	}
+
+	return u.blessMissingTransaction(ctx, subtreeHash, mTx.tx, blockHeight, blockIds, validationOptions)
+}
+
+// checkCoinbaseMaturity returns an ErrTxCoinbaseImmature error when the transaction spends the output of a coinbase
+// transaction that does not have CoinbaseMaturity confirmations at the block height, i.e. when the coinbase was mined
+// less than CoinbaseMaturity blocks before the block of the transaction. The UTXO store enforces the same rule when
+// the outputs are spent, the check rejects the transaction before it is validated.
+func (u *Server) checkCoinbaseMaturity(subtreeHash chainhash.Hash, mTx missingTx, blockHeight uint32) error {
+	if mTx.coinbaseHeight == 0 || u.settings == nil || u.settings.ChainCfgParams == nil {
+		return nil
+	}
+
+	maturity := uint32(u.settings.ChainCfgParams.CoinbaseMaturity)
+	if blockHeight >= mTx.coinbaseHeight+maturity {
+		return nil
+	}
+
+	prometheusSubtreeValidationImmatureCoinbaseTxs.Inc()
+
+	return errors.NewTxCoinbaseImmatureError("[blessMissingTransaction][%s][%s] spends coinbase mined at height %d, not spendable before height %d, block height %d",
+		subtreeHash.String(), mTx.tx.TxID(), mTx.coinbaseHeight, mTx.coinbaseHeight+maturity, blockHeight)
+}
+
 // checkCounterConflictingOnCurrentChain checks if the counter-conflicting transactions of a given transaction have
 // already been mined on the current chain. If they have, it returns an error indicating that the transaction is invalid.
 // Parameters:
@@ -431,7 +499,7 @@ func (u *Server) checkCounterConflictingOnCurrentChain(ctx context.Context, txHa
 				return errors.NewProcessingError("[checkCounterConflictingOnCurrentChain][%s] counter conflicting tx is frozen", txHash.String())
 			}
 
-			counterConflictingTxMeta, err := u.utxoStore.GetMeta(gCtx, &counterConflictingTxHash)
+			counterConflictingTxMeta, err := u.resolver().GetMeta(gCtx, &counterConflictingTxHash)
 			if err != nil {
 				return errors.NewProcessingError("[checkCounterConflictingOnCurrentChain][%s] failed to get counter conflicting tx meta", txHash.String(), err)
 			}
@@ -500,6 +568,75 @@ type ValidateSubtree struct {
 	// When true, validation stops at the first error for quick failure detection.
 	// When false, validation attempts to process all transactions to collect comprehensive error information.
 	AllowFailFast bool
+
+	// SkipRootCheck skips recomputing the merkle root over TxHashes before the validation, for callers that
+	// already verified that the transaction hashes match SubtreeHash. Fetched transaction hashes are always checked.
+	SkipRootCheck bool
+
+	// ProgressFunc optionally receives the progress of the validation of the missing transactions of the subtree,
+	// as every dependency level completes and periodically within large levels
+	ProgressFunc ProgressFunc
+
+	// UTXODelta optionally receives the outpoints spent and created in the UTXO store by the validation of the
+	// subtree. It is emptied when the validation starts and only filled when the validation succeeds.
+	UTXODelta *UTXODelta
+}
+
+// isTransientSubtreeValidationError returns whether a subtree validation error is caused by a temporary failure
+// of a store or the network, rather than by the subtree or its transactions being invalid. An error that reports
+// invalidity anywhere in its chain is never considered transient. UTXO errors are not transient either, the UTXO
+// stores return them for UTXOs that cannot be spent, e.g. double spends.
+func isTransientSubtreeValidationError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, errors.ErrSubtreeInvalid) ||
+		errors.Is(err, errors.ErrSubtreeInvalidFormat) ||
+		errors.Is(err, errors.ErrTxInvalid) ||
+		errors.Is(err, errors.ErrTxConsensus) ||
+		errors.Is(err, errors.ErrBlockInvalid) ||
+		errors.Is(err, errors.ErrNetworkPeerMalicious) {
+		return false
+	}
+
+	return errors.Is(err, errors.ErrStorageError) ||
+		errors.Is(err, errors.ErrStorageUnavailable) ||
+		errors.Is(err, errors.ErrBlobError) ||
+		errors.Is(err, errors.ErrServiceUnavailable) ||
+		errors.Is(err, errors.ErrNetworkError) ||
+		errors.Is(err, errors.ErrNetworkTimeout) ||
+		errors.Is(err, errors.ErrNetworkConnectionRefused)
+}
+
+// validateBlockSubtreeWithRetry calls validate for a subtree of a block, retrying with a linear backoff when it
+// fails on a transient error, up to subtreevalidation_transientErrorMaxRetries times. Any other error is returned
+// immediately, so genuinely invalid subtrees still fail the block without delay.
+//
+// Parameters:
+//   - ctx: Context for cancellation of the backoff
+//   - subtreeHash: Hash of the subtree being validated, used for logging
+//   - validate: Function performing the subtree validation
+//
+// Returns:
+//   - *subtreepkg.Subtree: The validated subtree returned by validate
+//   - error: The last error returned by validate, or a context error when cancelled during the backoff
+func (u *Server) validateBlockSubtreeWithRetry(ctx context.Context, subtreeHash chainhash.Hash,
+	validate func() (*subtreepkg.Subtree, error)) (*subtreepkg.Subtree, error) {
+	maxRetries := u.settings.SubtreeValidation.TransientErrorMaxRetries
+
+	for attempt := 0; ; attempt++ {
+		subtree, err := validate()
+		if err == nil || attempt >= maxRetries || !isTransientSubtreeValidationError(err) {
+			return subtree, err
+		}
+
+		u.logger.Warnf("[validateBlockSubtreeWithRetry][%s] transient error validating subtree (attempt %d of %d), retrying: %v", subtreeHash.String(), attempt+1, maxRetries+1, err)
+
+		if err = retry.BackoffAndSleep(ctx, attempt, 2, u.settings.SubtreeValidation.TransientErrorRetryBackoff); err != nil {
+			return nil, errors.NewContextCanceledError("[validateBlockSubtreeWithRetry][%s] context cancelled while waiting to retry subtree validation", subtreeHash.String(), err)
+		}
+	}
 }
 
 // ValidateSubtreeInternal performs the actual validation of a subtree.
@@ -554,11 +691,24 @@ func (u *Server) ValidateSubtreeInternal(ctx context.Context, v ValidateSubtree,
 		endSpan(err)
 	}()
 
-	start := gocore.CurrentTime()
-
 	// Get the subtree hashes if they were passed in
 	txHashes := v.TxHashes
 
+	// record the outcome of every validation for auditing, the number of transactions is 0 when the
+	// transactions of the subtree could not be retrieved
+	defer func() {
+		u.publishSubtreeValidationResult(ctx, &v.SubtreeHash, len(txHashes), startTotal, err)
+	}()
+
+	v.UTXODelta.reset()
+
+	// a subtree validated before is not validated again
+	if cachedSubtree, found, cachedErr := u.cachedSubtreeResult(ctx, &v.SubtreeHash, v.UTXODelta); found {
+		return cachedSubtree, cachedErr
+	}
+
+	start := gocore.CurrentTime()
+
 	if txHashes == nil {
 		subtreeExists, err := u.GetSubtreeExists(ctx, &v.SubtreeHash)
 
@@ -578,8 +728,18 @@ func (u *Server) ValidateSubtreeInternal(ctx context.Context, v ValidateSubtree,
 		if err != nil {
 			return nil, errors.NewServiceError("[ValidateSubtreeInternal][%s] failed to get subtree from network", v.SubtreeHash.String(), err)
 		}
+	} else if !v.SkipRootCheck {
+		// the supplied transaction hashes must hash to the subtree root, before any effort is spent on them
+		if err = u.verifySubtreeRoot(ctx, &v.SubtreeHash, txHashes, v.BaseURL); err != nil {
+			return nil, err
+		}
 	}
 
+	// cache the verdict of the validation, once the transactions of the subtree are known
+	defer func() {
+		u.cacheSubtreeResult(&v.SubtreeHash, err, v.UTXODelta)
+	}()
+
 	// create the empty subtree
 	height := math.Ceil(math.Log2(float64(len(txHashes))))
 
@@ -611,6 +771,12 @@ func (u *Server) ValidateSubtreeInternal(ctx context.Context, v ValidateSubtree,
 	// in the retry attempts, only the tx hashes that are missing will be retried, not the whole subtree
 	txMetaSlice := make([]*meta.Data, len(txHashes))
 
+	// validatedTxs will be populated with the transactions written to the utxo store, when the utxo delta is requested
+	var validatedTxs []*bt.Tx
+	if v.UTXODelta != nil {
+		validatedTxs = make([]*bt.Tx, len(txHashes))
+	}
+
 	for attempt := 1; attempt <= maxRetries+1; attempt++ {
 		prometheusSubtreeValidationValidateSubtreeRetry.Inc()
 
@@ -636,6 +802,8 @@ func (u *Server) ValidateSubtreeInternal(ctx context.Context, v ValidateSubtree,
 		if err != nil {
 			if errors.Is(err, errors.ErrThresholdExceeded) {
 				u.logger.Warnf("[ValidateSubtreeInternal][%s] [attempt #%d] too many missing txmeta entries in cache (fail fast check only, will retry)", v.SubtreeHash.String(), attempt)
+				prometheusSubtreeValidationTxNotFoundRetries.WithLabelValues(subtreeSizeBucket(len(txHashes))).Inc()
+
 				select {
 				case <-ctx.Done():
 					break
@@ -698,8 +866,10 @@ func (u *Server) ValidateSubtreeInternal(ctx context.Context, v ValidateSubtree,
 				txHashes,
 				v.BaseURL,
 				txMetaSlice,
+				validatedTxs,
 				blockHeight,
 				blockIds,
+				v.ProgressFunc,
 				validationOptions...,
 			)
 			if err != nil {
@@ -833,6 +1003,8 @@ func (u *Server) ValidateSubtreeInternal(ctx context.Context, v ValidateSubtree,
 	// only set this on no errors
 	prometheusSubtreeValidationValidateSubtreeDuration.Observe(float64(time.Since(startTotal).Microseconds()) / 1_000_000)
 
+	v.UTXODelta.add(validatedTxs, blockHeight)
+
 	// Increase peer's reputation for providing a valid subtree
 	if u.p2pClient != nil && v.PeerID != "" {
 		if err := u.p2pClient.ReportValidSubtree(ctx, v.PeerID, v.SubtreeHash.String()); err != nil {
@@ -863,11 +1035,19 @@ func (u *Server) getSubtreeTxHashes(spanCtx context.Context, stat *gocore.Stat,
 			return nil, errors.NewProcessingError("[getSubtreeTxHashes][%s] failed to create subtree from subtreeToCheck bytes", subtreeHash.String(), err)
 		}
 
+		if err = u.checkSubtreeTxCount(len(subtree.Nodes)); err != nil {
+			return nil, errors.NewSubtreeInvalidError("[getSubtreeTxHashes][%s] subtree rejected", subtreeHash.String(), err)
+		}
+
 		// return the transaction hashes from the subtree
 		for _, node := range subtree.Nodes {
 			txHashes = append(txHashes, node.Hash)
 		}
 
+		if err = u.verifySubtreeRoot(spanCtx, subtreeHash, txHashes, baseURL); err != nil {
+			return nil, err
+		}
+
 		return txHashes, nil
 	}
 
@@ -876,7 +1056,7 @@ func (u *Server) getSubtreeTxHashes(spanCtx context.Context, stat *gocore.Stat,
 	u.logger.Debugf("[getSubtreeTxHashes][%s] getting subtree from %s", subtreeHash.String(), url)
 
 	// TODO add the metric for how long this takes
-	body, err := util.DoHTTPRequestBodyReader(spanCtx, url)
+	body, err := u.doPeerRequestBodyReader(spanCtx, baseURL, url)
 	if err != nil {
 		// check whether this is a 404 error
 		if errors.Is(err, errors.ErrNotFound) {
@@ -909,6 +1089,13 @@ func (u *Server) getSubtreeTxHashes(spanCtx context.Context, stat *gocore.Stat,
 		n, err := io.ReadFull(bufferedReader, buffer)
 		if n > 0 {
 			txHashes = append(txHashes, chainhash.Hash(buffer))
+
+			// stop reading as soon as the subtree has too many transactions
+			if limitErr := u.checkSubtreeTxCount(len(txHashes)); limitErr != nil {
+				u.publishInvalidSubtree(spanCtx, subtreeHash.String(), baseURL, "subtree_too_many_transactions")
+
+				return nil, errors.NewSubtreeInvalidError("[getSubtreeTxHashes][%s] subtree from %s rejected", subtreeHash.String(), baseURL, limitErr)
+			}
 		}
 
 		if err != nil {
@@ -926,6 +1113,10 @@ func (u *Server) getSubtreeTxHashes(spanCtx context.Context, stat *gocore.Stat,
 
 	stat.NewStat("3. createTxHashes").AddTime(start)
 
+	if err = u.verifySubtreeRoot(spanCtx, subtreeHash, txHashes, baseURL); err != nil {
+		return nil, err
+	}
+
 	u.logger.Debugf("[getSubtreeTxHashes][%s] done with subtree response", subtreeHash.String())
 
 	// TODO: Report successful subtree fetch to improve peer reputation
@@ -940,6 +1131,51 @@ func (u *Server) getSubtreeTxHashes(spanCtx context.Context, stat *gocore.Stat,
 	return txHashes, nil
 }
 
+// ErrSubtreeRootMismatch is wrapped by the error of a subtree whose transactions do not hash to its merkle root
+var ErrSubtreeRootMismatch = errors.NewSubtreeInvalidError("subtree root mismatch")
+
+// verifySubtreeRoot recomputes the merkle root over the transaction hashes of a received subtree and
+// checks it against the hash the subtree was announced with, before any of its transactions are fetched
+// or validated. A subtree that does not match is reported as invalid to the peer it was received from.
+//
+// The check is skipped when subtreevalidation_verifySubtreeRootOnIngress is disabled.
+func (u *Server) verifySubtreeRoot(ctx context.Context, subtreeHash *chainhash.Hash, txHashes []chainhash.Hash, baseURL string) error {
+	if !u.settings.SubtreeValidation.VerifySubtreeRootOnIngress {
+		return nil
+	}
+
+	if len(txHashes) == 0 {
+		u.publishInvalidSubtree(ctx, subtreeHash.String(), baseURL, "subtree_root_mismatch")
+
+		return errors.NewSubtreeInvalidError("[verifySubtreeRoot][%s] subtree from %s does not contain any transactions", subtreeHash.String(), baseURL, ErrSubtreeRootMismatch)
+	}
+
+	subtree, err := subtreepkg.NewIncompleteTreeByLeafCount(len(txHashes))
+	if err != nil {
+		return errors.NewProcessingError("[verifySubtreeRoot][%s] failed to create subtree structure", subtreeHash.String(), err)
+	}
+
+	for _, txHash := range txHashes {
+		if txHash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
+			err = subtree.AddCoinbaseNode()
+		} else {
+			err = subtree.AddNode(txHash, 0, 0)
+		}
+
+		if err != nil {
+			return errors.NewProcessingError("[verifySubtreeRoot][%s] failed to add node to subtree", subtreeHash.String(), err)
+		}
+	}
+
+	if rootHash := subtree.RootHash(); !rootHash.IsEqual(subtreeHash) {
+		u.publishInvalidSubtree(ctx, subtreeHash.String(), baseURL, "subtree_root_mismatch")
+
+		return errors.NewSubtreeInvalidError("[verifySubtreeRoot][%s] subtree root hash mismatch, merkle root of the %d transactions from %s is %s", subtreeHash.String(), len(txHashes), baseURL, rootHash.String(), ErrSubtreeRootMismatch)
+	}
+
+	return nil
+}
+
 // processMissingTransactions handles the retrieval and validation of missing transactions
 // in a subtree, coordinating both the retrieval process and the validation workflow.
 //
@@ -967,6 +1203,7 @@ func (u *Server) getSubtreeTxHashes(spanCtx context.Context, stat *gocore.Stat,
 //   - allTxs: Complete list of all transaction hashes in the subtree
 //   - baseURL: Source URL for retrieving missing transactions
 //   - txMetaSlice: Pre-allocated slice to store transaction metadata results
+//   - validatedTxs: Optional slice to store the transactions written to the utxo store, by subtree index
 //   - blockHeight: Height of the block containing the subtree
 //   - blockIds: Map of block IDs to check if transactions are already mined
 //   - validationOptions: Additional options for transaction validation behavior
@@ -974,8 +1211,8 @@ func (u *Server) getSubtreeTxHashes(spanCtx context.Context, stat *gocore.Stat,
 // Returns:
 //   - error: Any error encountered during retrieval or validation
 func (u *Server) processMissingTransactions(ctx context.Context, subtreeHash chainhash.Hash, subtree *subtreepkg.Subtree,
-	missingTxHashes []utxo.UnresolvedMetaData, allTxs []chainhash.Hash, baseURL string, txMetaSlice []*meta.Data, blockHeight uint32,
-	blockIds map[uint32]bool, validationOptions ...validator.Option) (err error) {
+	missingTxHashes []utxo.UnresolvedMetaData, allTxs []chainhash.Hash, baseURL string, txMetaSlice []*meta.Data, validatedTxs []*bt.Tx, blockHeight uint32,
+	blockIds map[uint32]bool, progressFn ProgressFunc, validationOptions ...validator.Option) (err error) {
 	ctx, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "SubtreeValidation:processMissingTransactions",
 		tracing.WithDebugLogMessage(u.logger, "[processMissingTransactions][%s] processing %d missing txs", subtreeHash.String(), len(missingTxHashes)),
 		tracing.WithNewRoot(), // decouple tracing from the parent context, otherwise it will explode with too many spans
@@ -1008,13 +1245,30 @@ func (u *Server) processMissingTransactions(ctx context.Context, subtreeHash cha
 	// process the transactions in parallel, based on the number of parents in the list
 	maxLevel, txsPerLevel, err := u.prepareTxsPerLevel(ctx, missingTxs)
 	if err != nil {
-		return errors.NewProcessingError("[processMissingTransactions][%s] failed to prepare transactions per level: %v", subtreeHash.String(), err)
+		return errors.NewProcessingError("[processMissingTransactions][%s] failed to prepare transactions per level", subtreeHash.String(), err)
 	}
 
+	defer u.releaseTxsPerLevel(txsPerLevel)
+
 	u.logger.Debugf("[processMissingTransactions][%s] maxLevel: %d", subtreeHash.String(), maxLevel)
 
-	// pre-process the validation options into a struct
+	sizeBucket := subtreeSizeBucket(len(allTxs))
+	observeTxsPerLevel(sizeBucket, maxLevel, txsPerLevel)
+	recordLevelStats(ctx, maxLevel, txsPerLevel)
+
+	// the transactions held back for unavailable parents are not in the levels and are not validated
+	levelTxCount := 0
+	for _, levelTxs := range txsPerLevel {
+		levelTxCount += len(levelTxs)
+	}
+
+	progress := newProgressReporter(progressFn, levelTxCount, u.settings.SubtreeValidation.ProgressInterval)
+	defer progress.stop()
+
+	// pre-process the validation options into a struct, the transactions of a subtree are counted in the subtree
+	// validation backlog and are never rejected by the validation backlog of the validator
 	processedValidatorOptions := validator.ProcessOptions(validationOptions...)
+	processedValidatorOptions.BacklogExempt = true
 
 	var (
 		errorsFound      = atomic.Uint64{}
@@ -1024,12 +1278,22 @@ func (u *Server) processMissingTransactions(ctx context.Context, subtreeHash cha
 	)
 
 	for level := uint32(0); level <= maxLevel; level++ {
+		// stop before the level when the context is done, instead of pushing through the remaining levels
+		if err = levelContextError(ctx, "processMissingTransactions", level, maxLevel); err != nil {
+			return err
+		}
+
+		levelStart := time.Now()
+
+		progress.startLevel(level)
+
 		g, gCtx := errgroup.WithContext(ctx)
-		util.SafeSetLimit(g, u.settings.SubtreeValidation.SpendBatcherSize*2)
+		util.SafeSetLimit(g, u.levelValidationConcurrency())
 
 		u.logger.Debugf("[processMissingTransactions][%s] processing level %d/%d with %d transactions", subtreeHash.String(), level+1, maxLevel+1, len(txsPerLevel[level]))
 
 		for _, mTx = range txsPerLevel[level] {
+			levelTx := mTx
 			tx := mTx.tx
 			txIdx := mTx.idx
 
@@ -1037,10 +1301,25 @@ func (u *Server) processMissingTransactions(ctx context.Context, subtreeHash cha
 				return errors.NewProcessingError("[validateSubtree][%s] missing transaction is nil", subtreeHash.String())
 			}
 
+			// stop starting transactions once the context is done, wait for the transactions already started
+			if gCtx.Err() != nil {
+				break
+			}
+
 			// process each transaction in the background, since the transactions are all batched into the utxo store
-			g.Go(func() error {
-				txMeta, err := u.blessMissingTransaction(gCtx, subtreeHash, tx, blockHeight, blockIds, processedValidatorOptions)
+			g.Go(recoverLevelValidation(func() error {
+				// the context may be done while the goroutine waited to be scheduled
+				if gCtx.Err() != nil {
+					return nil
+				}
+
+				txMeta, err := u.blessLevelTx(gCtx, subtreeHash, levelTx, blockHeight, blockIds, processedValidatorOptions)
+
+				progress.txDone()
+
 				if err != nil {
+					err = annotateTxError(err, subtreeHash, *tx.TxIDChainHash(), txIdx, -1, &level)
+
 					// Log the error, but do not return it, since we want to process all transactions in the subtree
 					u.logger.Debugf("[validateSubtree][%s] failed to bless missing transaction: %s: %v", subtreeHash.String(), tx.TxIDChainHash().String(), err)
 					errorsFound.Add(1)
@@ -1055,7 +1334,7 @@ func (u *Server) processMissingTransactions(ctx context.Context, subtreeHash cha
 						if isRunning {
 							// add tx to the orphanage
 							u.logger.Debugf("[validateSubtree][%s] transaction %s is missing parent, adding to orphanage", subtreeHash.String(), tx.TxIDChainHash().String())
-							if u.orphanage.Set(*tx.TxIDChainHash(), tx) {
+							if u.orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, baseURL) {
 								addedToOrphanage.Add(1)
 							} else {
 								u.logger.Warnf("[validateSubtree][%s] Failed to add transaction %s to orphanage - orphanage is full", subtreeHash.String(), tx.TxIDChainHash().String())
@@ -1096,16 +1375,32 @@ func (u *Server) processMissingTransactions(ctx context.Context, subtreeHash cha
 					}
 
 					txMetaSlice[txIdx] = txMeta
+
+					// conflicting transactions are stored without spending their inputs
+					if validatedTxs != nil && !txMeta.Conflicting {
+						validatedTxs[txIdx] = tx
+					}
 				}
 
 				return nil
-			})
+			}))
 		}
 
 		// wait for each level to process separately
-		if err = g.Wait(); err != nil {
+		err = g.Wait()
+
+		observeLevelDuration(sizeBucket, levelStart)
+
+		if err != nil {
 			return err
 		}
+
+		// the transactions not started because the context is done are not validated
+		if err = levelContextError(ctx, "processMissingTransactions", level, maxLevel); err != nil {
+			return err
+		}
+
+		progress.levelDone(level)
 	}
 
 	if errorsFound.Load() > 0 {
@@ -1170,7 +1465,7 @@ func (u *Server) getSubtreeMissingTxs(ctx context.Context, subtreeHash chainhash
 			// get the whole subtree from the other peer
 			url := fmt.Sprintf("%s/subtree_data/%s", baseURL, subtreeHash.String())
 
-			body, subtreeDataErr := util.DoHTTPRequestBodyReader(ctx, url)
+			body, subtreeDataErr := u.doPeerRequestBodyReader(ctx, baseURL, url)
 			if subtreeDataErr != nil {
 				// Peer cannot provide subtree data - report as invalid subtree
 				u.publishInvalidSubtree(ctx, subtreeHash.String(), baseURL, "peer_cannot_provide_subtree_data")
@@ -1270,30 +1565,6 @@ func (u *Server) getSubtreeMissingTxs(ctx context.Context, subtreeHash chainhash
 	return missingTxs, nil
 }
 
-// txMapWrapper contains transaction metadata used during validation processing.
-//
-// This structure wraps transaction data with additional metadata required for the validation
-// process, particularly for tracking dependency relationships and block-level information.
-// It serves as an internal data structure to maintain context during the complex validation
-// workflow where transactions need to be processed in dependency order.
-//
-// The wrapper is used in the prepareTxsPerLevel method to organize transactions by their
-// dependency levels, ensuring proper validation ordering while maintaining performance
-// through parallel processing of independent transactions.
-type txMapWrapper struct {
-	// missingTx contains the transaction data and its original position in the subtree
-	missingTx missingTx
-	// someParentsInBlock indicates whether some of this transaction's parents are already in a block.
-	// This flag is used to optimize validation by skipping certain checks for transactions
-	// whose parents have already been validated and included in the blockchain.
-	someParentsInBlock bool
-	// childLevelInBlock represents the dependency level of this transaction within the block structure.
-	// Lower levels indicate transactions with fewer dependencies, allowing for optimized
-	// parallel processing during validation. Level 0 transactions have no dependencies
-	// within the current subtree and can be validated first.
-	childLevelInBlock uint32
-}
-
 // prepareTxsPerLevel organizes transactions by their dependency level for ordered processing.
 //
 // This method implements a topological sorting algorithm to organize transactions based on their
@@ -1305,21 +1576,34 @@ type txMapWrapper struct {
 // - Level 1: Transactions with parents only in level 0
 // - Level n: Transactions with parents in levels 0 through n-1
 //
+// The levels are calculated from the dependency graph of the transactions, see BuildDependencyGraph.
+// Within a level, the transactions are sorted by their index in the subtree, then by txid, so the levels are
+// the same for the same transactions whatever order they are given in.
+//
 // This approach enables efficient parallel processing while maintaining correct validation order,
 // ensuring that parent transactions are always validated before their children. The implementation
 // is optimized for large subtrees with complex dependency graphs.
 //
+// Parents outside the subtree are looked up in the UTXO store. A transaction spending an external
+// parent that is not (yet) available, or descending from such a transaction within the subtree, is
+// held back: it is placed after all other levels, so it is validated last instead of at level 0.
+//
 // Note: This code is conceptually similar to the transaction ordering logic in the legacy
 // netsync/handle_block handler but is adapted for the subtree validation context and data structures.
 //
+// To protect against resource exhaustion, more transactions than MaxSubtreeTxCount are rejected before the
+// dependency graph is built, and the levelling stops as soon as a transaction is found at a level beyond
+// MaxDependencyDepth, since a deeply chained subtree serializes its validation. Both fail with ErrSubtreeInvalid.
+//
 // Parameters:
 //   - ctx: Context for cancellation and tracing
 //   - transactions: List of transactions to organize by level
 //
 // Returns:
 //   - uint32: The maximum dependency level found
-//   - map[uint32][]missingTx: Map of dependency levels to transactions at that level
-//   - error: Any error encountered during the processing
+//   - [][]missingTx: The transactions of every dependency level, to be released with releaseTxsPerLevel once
+//     the levels have been validated
+//   - error: Any error encountered during the processing, ErrSubtreeInvalid when a limit is exceeded
 func (u *Server) prepareTxsPerLevel(ctx context.Context, transactions []missingTx) (uint32, [][]missingTx, error) {
 	_, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "prepareTxsPerLevel",
 		tracing.WithDebugLogMessage(u.logger, "[prepareTxsPerLevel] preparing %d transactions per level", len(transactions)),
@@ -1327,117 +1611,211 @@ func (u *Server) prepareTxsPerLevel(ctx context.Context, transactions []missingT
 
 	defer deferFn()
 
-	// Build dependency graph with adjacency lists for efficient lookups
-	txMap := make(map[chainhash.Hash]*txMapWrapper, len(transactions))
+	if err := u.checkSubtreeTxCount(len(transactions)); err != nil {
+		return 0, nil, err
+	}
+
+	// Build the dependency graph of the transactions, re-using pooled memory where possible
+	arena := u.acquireLevelArena(len(transactions))
+	defer u.releaseLevelArena(arena)
+
+	graph := &arena.graph
+	if err := graph.build(ctx, transactions); err != nil {
+		return 0, nil, err
+	}
+
+	levels := arena.levels
+	levelKnown := arena.levelKnown
 	maxLevel := uint32(0)
-	sizePerLevel := make(map[uint32]uint64)
-
-	// First pass: create all nodes and initialize structures
-	for _, mTx := range transactions {
-		if mTx.tx != nil && !mTx.tx.IsCoinbase() {
-			hash := *mTx.tx.TxIDChainHash()
-			txMap[hash] = &txMapWrapper{
-				missingTx:         mTx,
-				childLevelInBlock: 0,
+	sizePerLevel := arena.sizePerLevel
+
+	// Calculate levels using recursive approach with memoization
+	var calculateLevel func(idx int) uint32
+	calculateLevel = func(idx int) uint32 {
+		if levelKnown[idx] {
+			return levels[idx]
+		}
+
+		// If no dependencies in subtree, level is 0, otherwise level is 1 + max(parent levels)
+		level := uint32(0)
+
+		for _, parentIdx := range graph.Parents[idx] {
+			if parentLevel := calculateLevel(parentIdx) + 1; parentLevel > level {
+				level = parentLevel
 			}
 		}
-	}
 
-	// Second pass: calculate dependency levels using topological approach
-	// Build dependency graph first
-	dependencies := make(map[chainhash.Hash][]chainhash.Hash) // child -> parents
-	childrenMap := make(map[chainhash.Hash][]chainhash.Hash)  // parent -> children
+		levels[idx] = level
+		levelKnown[idx] = true
 
-	for _, mTx := range transactions {
-		if mTx.tx == nil || mTx.tx.IsCoinbase() {
+		return level
+	}
+
+	// Calculate levels for all transactions in the graph
+	for idx := range transactions {
+		if !graph.Contains(idx) {
 			continue
 		}
 
-		txHash := *mTx.tx.TxIDChainHash()
-		dependencies[txHash] = make([]chainhash.Hash, 0)
+		level := calculateLevel(idx)
+		if err := u.checkDependencyDepth(level); err != nil {
+			return 0, nil, err
+		}
 
-		// Check each input of the transaction to find its parents
-		for _, input := range mTx.tx.Inputs {
-			parentHash := *input.PreviousTxIDChainHash()
+		sizePerLevel[level]++
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
 
-			// check if parentHash exists in the map, which means it is part of the subtree
-			if _, exists := txMap[parentHash]; exists {
-				dependencies[txHash] = append(dependencies[txHash], parentHash)
+	// look up all parents outside the subtree in one go, the parents that are found are satisfied and the
+	// transactions spending them are levelled on their dependencies within the subtree only
+	precheck, err := u.precheckExternalParents(ctx, graph, transactions)
+	if err != nil {
+		u.logger.Warnf("[prepareTxsPerLevel] not holding back any transactions: %v", err)
+	} else {
+		// acquire the missing parents from the network, the transactions spending a parent that is stored are
+		// validated as usual
+		precheck = u.fetchMissingParents(ctx, precheck)
 
-				if childrenMap[parentHash] == nil {
-					childrenMap[parentHash] = make([]chainhash.Hash, 0)
-				}
-				childrenMap[parentHash] = append(childrenMap[parentHash], txHash)
+		if len(precheck.missingParents) > 0 {
+			u.logger.Debugf("[prepareTxsPerLevel] %d of %d external parent outpoints missing, in %d parent transactions",
+				len(precheck.missingOutpoints), precheck.outpoints, len(precheck.missingParents))
+
+			maxLevel = holdBackTxsWithUnavailableParents(graph, levels, precheck.missingParents, sizePerLevel)
+
+			// the held back transactions are validated after all other levels, deepening the subtree
+			if err = u.checkDependencyDepth(maxLevel); err != nil {
+				return 0, nil, err
 			}
 		}
 	}
 
-	// Calculate levels using recursive approach with memoization
-	levelCache := make(map[chainhash.Hash]uint32)
+	// group the transactions per level in pooled buffers, in a deterministic order within a level
+	arena.groupPositionsPerLevel(maxLevel)
 
-	var calculateLevel func(chainhash.Hash) uint32
-	calculateLevel = func(txHash chainhash.Hash) uint32 {
-		if level, exists := levelCache[txHash]; exists {
-			return level
-		}
+	txsPerLevel := u.acquireTxsPerLevel(arena.levelStart)
 
-		// If no dependencies in subtree, level is 0
-		parents := dependencies[txHash]
-		if len(parents) == 0 {
-			levelCache[txHash] = 0
-			return 0
+	for level := uint32(0); level <= maxLevel; level++ {
+		positions := arena.levelPositions(level)
+		if len(positions) == 0 {
+			continue
 		}
 
-		// Level is 1 + max(parent levels)
-		maxParentLevel := uint32(0)
-		for _, parentHash := range parents {
-			parentLevel := calculateLevel(parentHash)
-			if parentLevel > maxParentLevel {
-				maxParentLevel = parentLevel
-			}
+		sortLevelPositions(positions, transactions, graph.TxHashes)
+
+		for i, idx := range positions {
+			txsPerLevel[level][i] = transactions[idx]
 		}
+	}
 
-		level := maxParentLevel + 1
-		levelCache[txHash] = level
-		return level
+	return maxLevel, txsPerLevel, nil
+}
+
+// checkSubtreeTxCount returns an ErrSubtreeInvalid error when the number of transactions exceeds MaxSubtreeTxCount
+func (u *Server) checkSubtreeTxCount(txCount int) error {
+	if u.settings == nil {
+		return nil
 	}
 
-	// Calculate levels for all transactions and update wrappers
-	for _, mTx := range transactions {
-		if mTx.tx == nil || mTx.tx.IsCoinbase() {
-			continue
+	if maxTxCount := u.settings.SubtreeValidation.MaxSubtreeTxCount; maxTxCount > 0 && txCount > maxTxCount {
+		return errors.NewSubtreeInvalidError("[checkSubtreeTxCount] %d transactions exceed the maximum of %d transactions", txCount, maxTxCount)
+	}
+
+	return nil
+}
+
+// checkDependencyDepth returns an ErrSubtreeInvalid error when the dependency level exceeds MaxDependencyDepth
+func (u *Server) checkDependencyDepth(level uint32) error {
+	if u.settings == nil {
+		return nil
+	}
+
+	if maxDepth := u.settings.SubtreeValidation.MaxDependencyDepth; maxDepth > 0 && level > uint32(maxDepth) { //nolint:gosec // G115: maxDepth is positive
+		return errors.NewSubtreeInvalidError("[checkDependencyDepth] dependency level %d exceeds the maximum dependency depth of %d", level, maxDepth)
+	}
+
+	return nil
+}
+
+// sortLevelPositions sorts the positions of the transactions of a level by the index of the transactions in the
+// subtree, and by txid for the transactions with the same index, e.g. orphans that have no index in a subtree.
+// This makes the order of the transactions within a level independent of the order they were gathered in.
+func sortLevelPositions(positions []int, transactions []missingTx, txHashes []chainhash.Hash) {
+	slices.SortFunc(positions, func(a, b int) int {
+		if c := cmp.Compare(transactions[a].idx, transactions[b].idx); c != 0 {
+			return c
 		}
 
-		txHash := *mTx.tx.TxIDChainHash()
-		wrapper := txMap[txHash]
-		if wrapper == nil {
-			continue
+		return bytes.Compare(txHashes[a][:], txHashes[b][:])
+	})
+}
+
+// holdBackTxsWithUnavailableParents moves the transactions spending an unavailable parent, and their
+// descendants in the subtree, after all other levels, keeping their relative levels. It recomputes the
+// number of transactions per level and returns the new maximum level.
+func holdBackTxsWithUnavailableParents(graph *DependencyGraph, levels []uint32, unavailableParents map[chainhash.Hash]struct{},
+	sizePerLevel map[uint32]uint64) uint32 {
+	heldBackCache := make(map[int]bool, graph.Len())
+
+	var isHeldBack func(int) bool
+	isHeldBack = func(idx int) bool {
+		if heldBack, exists := heldBackCache[idx]; exists {
+			return heldBack
 		}
 
-		level := calculateLevel(txHash)
-		wrapper.childLevelInBlock = level
-		wrapper.someParentsInBlock = len(dependencies[txHash]) > 0
+		heldBack := false
 
-		sizePerLevel[level]++
-		if level > maxLevel {
-			maxLevel = level
+		for _, parentHash := range graph.ExternalParents[idx] {
+			if _, unavailable := unavailableParents[parentHash]; unavailable {
+				heldBack = true
+				break
+			}
+		}
+
+		if !heldBack {
+			for _, parentIdx := range graph.Parents[idx] {
+				if isHeldBack(parentIdx) {
+					heldBack = true
+					break
+				}
+			}
+		}
+
+		heldBackCache[idx] = heldBack
+
+		return heldBack
+	}
+
+	// the held back transactions start after the highest level of the transactions that are not held back
+	offset := uint32(0)
+
+	for idx := range graph.TxHashes {
+		if graph.Contains(idx) && !isHeldBack(idx) && levels[idx]+1 > offset {
+			offset = levels[idx] + 1
 		}
 	}
 
-	blocksPerLevelSlice := make([][]missingTx, maxLevel+1)
+	clear(sizePerLevel)
 
-	// Build result map with pre-allocated slices
-	for _, wrapper := range txMap {
-		level := wrapper.childLevelInBlock
-		if blocksPerLevelSlice[level] == nil {
-			// Initialize the slice for this level if it doesn't exist
-			blocksPerLevelSlice[level] = make([]missingTx, 0, sizePerLevel[level])
+	maxLevel := uint32(0)
+
+	for idx := range graph.TxHashes {
+		if !graph.Contains(idx) {
+			continue
 		}
 
-		blocksPerLevelSlice[level] = append(blocksPerLevelSlice[level], wrapper.missingTx)
+		if isHeldBack(idx) {
+			levels[idx] += offset
+		}
+
+		sizePerLevel[levels[idx]]++
+		if levels[idx] > maxLevel {
+			maxLevel = levels[idx]
+		}
 	}
 
-	return maxLevel, blocksPerLevelSlice, nil
+	return maxLevel
 }
 
 // getMissingTransactionsFromPeer retrieves missing transactions from either the network or local store.
@@ -10,11 +10,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"os"
 	"regexp"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2"
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
@@ -28,6 +31,7 @@ import (
 	"github.com/bsv-blockchain/teranode/services/blockchain"
 	"github.com/bsv-blockchain/teranode/services/legacy/testdata"
 	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/settings"
 	"github.com/bsv-blockchain/teranode/stores/blob"
 	blobmemory "github.com/bsv-blockchain/teranode/stores/blob/memory"
 	blockchainstore "github.com/bsv-blockchain/teranode/stores/blockchain"
@@ -35,10 +39,12 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util/kafka" //nolint:gci
+	kafkamessage "github.com/bsv-blockchain/teranode/util/kafka/kafka_message"
 	"github.com/bsv-blockchain/teranode/util/test"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -114,6 +120,109 @@ func TestBlockValidationValidateSubtree(t *testing.T) {
 	})
 }
 
+func TestValidateSubtreeInternal_PublishesValidationResult(t *testing.T) {
+	InitPrometheusMetrics()
+
+	txMetaStore, validatorClient, txStore, subtreeStore, blockchainClient, deferFunc := setup(t)
+	defer deferFunc()
+
+	subtree, err := subtreepkg.NewTreeByLeafCount(2)
+	require.NoError(t, err)
+	require.NoError(t, subtree.AddNode(*hash1, 121, 0))
+	require.NoError(t, subtree.AddNode(*hash2, 122, 0))
+
+	_, err = txMetaStore.Create(context.Background(), tx1, 0)
+	require.NoError(t, err)
+
+	_, err = txMetaStore.Create(context.Background(), tx2, 0)
+	require.NoError(t, err)
+
+	nodeBytes, err := subtree.SerializeNodes()
+	require.NoError(t, err)
+
+	httpmock.RegisterResponder(
+		"GET",
+		`=~^/subtree/[a-z0-9]+\z`,
+		httpmock.NewBytesResponder(200, nodeBytes),
+	)
+
+	nilConsumer := &kafka.KafkaConsumerGroup{}
+	tSettings := test.CreateBaseTestSettings(t)
+
+	subtreeValidation, err := New(context.Background(), ulogger.TestLogger{}, tSettings, subtreeStore, txStore, txMetaStore, validatorClient, blockchainClient, nilConsumer, nilConsumer, nil)
+	require.NoError(t, err)
+
+	producer := &mockKafkaProducer{}
+	subtreeValidation.subtreeValidationResultKafkaProducer = producer
+
+	v := ValidateSubtree{
+		SubtreeHash: *subtree.RootHash(),
+		BaseURL:     "http://localhost:8000",
+	}
+
+	before := time.Now().UnixMilli()
+
+	_, err = subtreeValidation.ValidateSubtreeInternal(context.Background(), v, chaincfg.GenesisActivationHeight, nil)
+	require.NoError(t, err)
+
+	require.Len(t, producer.messages, 1)
+	assert.Equal(t, subtree.RootHash()[:], producer.messages[0].Key)
+
+	var msg kafkamessage.KafkaSubtreeValidationResultTopicMessage
+	require.NoError(t, proto.Unmarshal(producer.messages[0].Value, &msg))
+
+	assert.Equal(t, subtree.RootHash().String(), msg.SubtreeHash)
+	assert.Equal(t, uint64(2), msg.TxCount)
+	assert.True(t, msg.Valid)
+	assert.Equal(t, kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_VALID, msg.Outcome)
+	assert.Empty(t, msg.Reason)
+	assert.GreaterOrEqual(t, msg.DurationMillis, int64(0))
+	assert.GreaterOrEqual(t, msg.Timestamp, before)
+}
+
+func TestSubtreeValidationOutcome(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected kafkamessage.SubtreeValidationOutcome
+	}{
+		{"valid", context.Background(), nil, kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_VALID},
+		{"invalid subtree", context.Background(), errors.NewSubtreeInvalidError("invalid"), kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_INVALID},
+		{"invalid transaction", context.Background(), errors.NewProcessingError("failed", errors.NewTxInvalidError("invalid")), kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_INVALID},
+		{"storage error", context.Background(), errors.NewStorageError("failed"), kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_ERROR},
+		{"cancelled error", context.Background(), errors.NewContextCanceledError("cancelled"), kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_CANCELLED},
+		{"cancelled context", cancelledCtx, errors.NewServiceError("failed"), kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_CANCELLED},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, subtreeValidationOutcome(tt.ctx, tt.err))
+		})
+	}
+}
+
+func TestPublishSubtreeValidationResult_Error(t *testing.T) {
+	producer := &mockKafkaProducer{}
+
+	u := &Server{logger: ulogger.TestLogger{}, subtreeValidationResultKafkaProducer: producer}
+
+	u.publishSubtreeValidationResult(context.Background(), hash1, 0, time.Now(), errors.NewServiceError("failed to get subtree from network"))
+
+	require.Len(t, producer.messages, 1)
+
+	var msg kafkamessage.KafkaSubtreeValidationResultTopicMessage
+	require.NoError(t, proto.Unmarshal(producer.messages[0].Value, &msg))
+
+	assert.False(t, msg.Valid)
+	assert.Equal(t, kafkamessage.SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_ERROR, msg.Outcome)
+	assert.Equal(t, uint64(0), msg.TxCount)
+	assert.Contains(t, msg.Reason, "failed to get subtree from network")
+}
+
 func setup(t *testing.T) (utxo.Store, *validator.MockValidatorClient, blob.Store, blob.Store, blockchain.ClientI, func()) {
 	// we only need the httpClient, utxoStore and validatorClient when blessing a transaction
 	httpmock.Activate()
@@ -205,6 +314,111 @@ func TestBlockValidationValidateSubtreeInternalWithMissingTx(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestValidateSubtreeInternal_ReportsProgress(t *testing.T) {
+	InitPrometheusMetrics()
+
+	utxoStore, validatorClient, txStore, subtreeStore, blockchainClient, deferFunc := setup(t)
+	defer deferFunc()
+
+	subtree, err := subtreepkg.NewTreeByLeafCount(1)
+	require.NoError(t, err)
+	require.NoError(t, subtree.AddNode(*hash1, 121, 0))
+
+	nodeBytes, err := subtree.SerializeNodes()
+	require.NoError(t, err)
+
+	httpmock.RegisterResponder(
+		"GET",
+		`=~^/subtree/[a-z0-9]+\z`,
+		httpmock.NewBytesResponder(200, nodeBytes),
+	)
+
+	nilConsumer := &kafka.KafkaConsumerGroup{}
+
+	tSettings := test.CreateBaseTestSettings(t)
+
+	subtreeValidation, err := New(context.Background(), ulogger.TestLogger{}, tSettings, subtreeStore, txStore, utxoStore, validatorClient, blockchainClient, nilConsumer, nilConsumer, nil)
+	require.NoError(t, err)
+
+	var (
+		calls    []progressCall
+		returned bool
+	)
+
+	v := ValidateSubtree{
+		SubtreeHash: *hash1,
+		BaseURL:     "http://localhost:8000",
+		ProgressFunc: func(level int, done int, total int) {
+			// the callback is never called after the validation returned
+			assert.False(t, returned)
+
+			calls = append(calls, progressCall{level: level, done: done, total: total})
+		},
+	}
+
+	_, err = subtreeValidation.ValidateSubtreeInternal(context.Background(), v, chaincfg.GenesisActivationHeight, nil)
+	returned = true
+
+	require.NoError(t, err)
+
+	// the single missing transaction is validated in a single level
+	require.NotEmpty(t, calls)
+	assert.Equal(t, progressCall{level: 0, done: 1, total: 1}, calls[len(calls)-1])
+}
+
+func TestValidateSubtreeInternal_ReturnsUTXODelta(t *testing.T) {
+	InitPrometheusMetrics()
+
+	utxoStore, validatorClient, txStore, subtreeStore, blockchainClient, deferFunc := setup(t)
+	defer deferFunc()
+
+	subtree, err := subtreepkg.NewTreeByLeafCount(1)
+	require.NoError(t, err)
+	require.NoError(t, subtree.AddNode(*hash1, 121, 0))
+
+	nodeBytes, err := subtree.SerializeNodes()
+	require.NoError(t, err)
+
+	httpmock.RegisterResponder(
+		"GET",
+		`=~^/subtree/[a-z0-9]+\z`,
+		httpmock.NewBytesResponder(200, nodeBytes),
+	)
+
+	nilConsumer := &kafka.KafkaConsumerGroup{}
+
+	tSettings := test.CreateBaseTestSettings(t)
+
+	subtreeValidation, err := New(context.Background(), ulogger.TestLogger{}, tSettings, subtreeStore, txStore, utxoStore, validatorClient, blockchainClient, nilConsumer, nilConsumer, nil)
+	require.NoError(t, err)
+
+	// stale outpoints of an earlier validation are dropped
+	delta := &UTXODelta{Spent: []Outpoint{{TxHash: *hash4}}}
+
+	v := ValidateSubtree{
+		SubtreeHash: *hash1,
+		BaseURL:     "http://localhost:8000",
+		UTXODelta:   delta,
+	}
+
+	_, err = subtreeValidation.ValidateSubtreeInternal(context.Background(), v, chaincfg.GenesisActivationHeight, nil)
+	require.NoError(t, err)
+
+	// the missing transaction was written to the utxo store
+	expected := &UTXODelta{}
+	expected.add([]*bt.Tx{tx1}, chaincfg.GenesisActivationHeight)
+
+	require.Len(t, expected.Spent, len(tx1.Inputs))
+	assert.Equal(t, expected, delta)
+
+	// nothing is written when the subtree was validated before
+	_, err = subtreeValidation.ValidateSubtreeInternal(context.Background(), v, chaincfg.GenesisActivationHeight, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, delta.Spent)
+	assert.Empty(t, delta.Created)
+}
+
 func TestBlockValidationValidateSubtreeInternalLegacy(t *testing.T) {
 	InitPrometheusMetrics()
 
@@ -430,6 +644,313 @@ func Benchmark_prepareTxsPerLevel(b *testing.B) {
 	}
 }
 
+func Benchmark_prepareTxsPerLevelArenaReuse(b *testing.B) {
+	transactions := loadSubtreeTestTransactions(b)
+
+	for _, reuse := range []bool{false, true} {
+		b.Run(fmt.Sprintf("reuse=%v", reuse), func(b *testing.B) {
+			s := &Server{
+				settings: &settings.Settings{
+					SubtreeValidation: settings.SubtreeValidationSettings{
+						ReuseValidationArena: reuse,
+					},
+				},
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _, _ = s.prepareTxsPerLevel(context.Background(), transactions)
+			}
+		})
+	}
+}
+
+func Benchmark_prepareTxsPerLevel100k(b *testing.B) {
+	const (
+		txCount     = 100_000
+		chainLength = 10
+	)
+
+	// 10,000 chains of 10 transactions, each chain spending an output outside the subtree, so the transactions are
+	// spread over 10 levels
+	transactions := make([]missingTx, 0, txCount)
+
+	for chain := 0; chain < txCount/chainLength; chain++ {
+		parentHash := chainhash.HashH([]byte(fmt.Sprintf("external-%d", chain)))
+
+		for i := 0; i < chainLength; i++ {
+			tx := bt.NewTx()
+			require.NoError(b, tx.From(parentHash.String(), 0, "51", 1000))
+			tx.AddOutput(&bt.Output{Satoshis: 100, LockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE})})
+			tx.SetTxHash(tx.TxIDChainHash()) // ensure the tx hash is set, so it is not calculated in the benchmark
+
+			transactions = append(transactions, missingTx{tx: tx, idx: len(transactions)})
+			parentHash = *tx.TxIDChainHash()
+		}
+	}
+
+	for _, reuse := range []bool{false, true} {
+		b.Run(fmt.Sprintf("reuse=%v", reuse), func(b *testing.B) {
+			s := &Server{
+				settings: &settings.Settings{
+					SubtreeValidation: settings.SubtreeValidationSettings{
+						ReuseValidationArena: reuse,
+					},
+				},
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, txsPerLevel, err := s.prepareTxsPerLevel(context.Background(), transactions)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				s.releaseTxsPerLevel(txsPerLevel)
+			}
+		})
+	}
+}
+
+func TestServer_prepareTxsPerLevelLimits(t *testing.T) {
+	external := chainhash.HashH([]byte("external"))
+
+	// a chain of 5 transactions, at levels 0 to 4
+	transactions := make([]missingTx, 0, 5)
+	parent := bt.UTXO{TxIDHash: &external, Vout: 0}
+
+	for i := 0; i < 5; i++ {
+		tx := newDependencyGraphTestTx(t, 1, parent)
+		transactions = append(transactions, missingTx{tx: tx, idx: i})
+		parent = bt.UTXO{TxIDHash: tx.TxIDChainHash(), Vout: 0}
+	}
+
+	newServer := func(maxTxCount, maxDepth int) *Server {
+		return &Server{
+			settings: &settings.Settings{
+				SubtreeValidation: settings.SubtreeValidationSettings{
+					MaxSubtreeTxCount:  maxTxCount,
+					MaxDependencyDepth: maxDepth,
+				},
+			},
+		}
+	}
+
+	t.Run("within the limits", func(t *testing.T) {
+		maxLevel, _, err := newServer(5, 4).prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(4), maxLevel)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		maxLevel, _, err := newServer(0, 0).prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(4), maxLevel)
+	})
+
+	t.Run("too many transactions", func(t *testing.T) {
+		_, _, err := newServer(4, 0).prepareTxsPerLevel(context.Background(), transactions)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrSubtreeInvalid))
+		assert.Contains(t, err.Error(), "5 transactions exceed the maximum of 4 transactions")
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		_, _, err := newServer(0, 3).prepareTxsPerLevel(context.Background(), transactions)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrSubtreeInvalid))
+		assert.Contains(t, err.Error(), "dependency level 4 exceeds the maximum dependency depth of 3")
+	})
+}
+
+func TestServer_prepareTxsPerLevelArenaReuse(t *testing.T) {
+	transactions := loadSubtreeTestTransactions(t)
+
+	unpooled := &Server{
+		settings: &settings.Settings{
+			SubtreeValidation: settings.SubtreeValidationSettings{ReuseValidationArena: false},
+		},
+	}
+
+	pooled := &Server{
+		settings: &settings.Settings{
+			SubtreeValidation: settings.SubtreeValidationSettings{ReuseValidationArena: true},
+		},
+	}
+
+	expectedMaxLevel, expectedTxsPerLevel, err := unpooled.prepareTxsPerLevel(context.Background(), transactions)
+	require.NoError(t, err)
+
+	// run multiple times, to make sure the arena is re-used from the pool without leaking state between runs
+	for i := 0; i < 3; i++ {
+		maxLevel, txsPerLevel, err := pooled.prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedMaxLevel, maxLevel)
+		require.Len(t, txsPerLevel, len(expectedTxsPerLevel))
+
+		for level := range expectedTxsPerLevel {
+			assert.ElementsMatch(t, expectedTxsPerLevel[level], txsPerLevel[level], "level %d should contain the same transactions", level)
+		}
+
+		pooled.releaseTxsPerLevel(txsPerLevel)
+	}
+
+	// a smaller set of transactions after a large one must not return stale entries from the arena
+	maxLevel, txsPerLevel, err := pooled.prepareTxsPerLevel(context.Background(), transactions[:1])
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), maxLevel)
+	require.Len(t, txsPerLevel, 1)
+	assert.Len(t, txsPerLevel[0], 1)
+}
+
+func TestServer_releaseTxsPerLevel(t *testing.T) {
+	transactions := loadSubtreeTestTransactions(t)
+
+	s := &Server{}
+
+	_, txsPerLevel, err := s.prepareTxsPerLevel(context.Background(), transactions)
+	require.NoError(t, err)
+
+	levels := slices.Clone(txsPerLevel)
+
+	s.releaseTxsPerLevel(txsPerLevel)
+
+	// no transaction of the validation is referenced by the pooled slices anymore
+	for level, txs := range levels {
+		for i, mTx := range txs[:cap(txs)] {
+			require.Nil(t, mTx.tx, "level %d position %d still references a transaction", level, i)
+		}
+	}
+
+	// the arena is cleared before it is returned to the pool
+	arena := newLevelArena(len(transactions))
+	require.NoError(t, arena.graph.build(context.Background(), transactions))
+
+	for idx := range transactions {
+		if arena.graph.Contains(idx) {
+			arena.levelKnown[idx] = true
+			arena.sizePerLevel[0]++
+		}
+	}
+
+	arena.groupPositionsPerLevel(0)
+	require.Len(t, arena.levelPositions(0), int(arena.sizePerLevel[0]))
+
+	arena.reset()
+
+	assert.Empty(t, arena.graph.TxHashes)
+	assert.Empty(t, arena.sizePerLevel)
+	assert.Empty(t, arena.positions)
+	assert.Empty(t, arena.levelStart)
+	assert.Empty(t, arena.levelFill)
+	assert.NotContains(t, arena.levelKnown[:cap(arena.levelKnown)], true)
+	assert.Equal(t, make([]int, cap(arena.positions)), arena.positions[:cap(arena.positions)])
+	assert.Equal(t, make([]int, cap(arena.levelStart)), arena.levelStart[:cap(arena.levelStart)])
+}
+
+func TestServer_prepareTxsPerLevelDeterministicOrder(t *testing.T) {
+	transactions := loadSubtreeTestTransactions(t)
+
+	s := &Server{}
+
+	// levelBytes serializes the levels, the index and bytes of every transaction in the order of its level
+	levelBytes := func(t *testing.T, txsPerLevel [][]missingTx) [][]byte {
+		result := make([][]byte, len(txsPerLevel))
+
+		for level, txs := range txsPerLevel {
+			var buf bytes.Buffer
+
+			for _, mTx := range txs {
+				require.NoError(t, binary.Write(&buf, binary.LittleEndian, int64(mTx.idx)))
+				buf.Write(mTx.tx.Bytes())
+			}
+
+			result[level] = buf.Bytes()
+		}
+
+		return result
+	}
+
+	shuffled := func(transactions []missingTx, seed uint64) []missingTx {
+		result := slices.Clone(transactions)
+		rand.New(rand.NewPCG(seed, seed)).Shuffle(len(result), func(i, j int) {
+			result[i], result[j] = result[j], result[i]
+		})
+
+		return result
+	}
+
+	t.Run("same levels whatever the order of the transactions", func(t *testing.T) {
+		expectedMaxLevel, expectedTxsPerLevel, err := s.prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+
+		expected := levelBytes(t, expectedTxsPerLevel)
+
+		for seed := uint64(1); seed <= 3; seed++ {
+			maxLevel, txsPerLevel, err := s.prepareTxsPerLevel(context.Background(), shuffled(transactions, seed))
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedMaxLevel, maxLevel)
+			assert.Equal(t, expected, levelBytes(t, txsPerLevel))
+		}
+
+		for _, txs := range expectedTxsPerLevel {
+			assert.True(t, slices.IsSortedFunc(txs, func(a, b missingTx) int { return a.idx - b.idx }))
+		}
+	})
+
+	t.Run("transactions without an index are ordered by txid", func(t *testing.T) {
+		orphans := make([]missingTx, len(transactions))
+		for i, mTx := range transactions {
+			orphans[i] = missingTx{tx: mTx.tx}
+		}
+
+		_, expectedTxsPerLevel, err := s.prepareTxsPerLevel(context.Background(), orphans)
+		require.NoError(t, err)
+
+		_, txsPerLevel, err := s.prepareTxsPerLevel(context.Background(), shuffled(orphans, 42))
+		require.NoError(t, err)
+
+		assert.Equal(t, levelBytes(t, expectedTxsPerLevel), levelBytes(t, txsPerLevel))
+	})
+}
+
+// loadSubtreeTestTransactions reads the transactions of the test subtree data file as missingTx entries.
+func loadSubtreeTestTransactions(tb testing.TB) []missingTx {
+	subtreeBytes, err := os.ReadFile("testdata/4d22d3ea8d618c6de784855bf4facd0760f4012852242adfd399cff700665f3d.subtree")
+	require.NoError(tb, err)
+
+	subtree, err := subtreepkg.NewSubtreeFromBytes(subtreeBytes[8:]) // trim the magic bytes
+	require.NoError(tb, err)
+
+	subtreeDataBytes, err := os.ReadFile("testdata/4d22d3ea8d618c6de784855bf4facd0760f4012852242adfd399cff700665f3d.subtreeData")
+	require.NoError(tb, err)
+
+	subtreeData, err := subtreepkg.NewSubtreeDataFromBytes(subtree, subtreeDataBytes)
+	require.NoError(tb, err)
+
+	transactions := make([]missingTx, 0, len(subtreeData.Txs))
+
+	for idx, tx := range subtreeData.Txs {
+		if tx == nil {
+			continue
+		}
+
+		tx.SetTxHash(tx.TxIDChainHash())
+		transactions = append(transactions, missingTx{
+			tx:  tx,
+			idx: idx,
+		})
+	}
+
+	return transactions
+}
+
 func createSpendingTx(t *testing.T, prevTx *bt.Tx, vout uint32, amount uint64, address *bscript.Address, privateKey *bec.PrivateKey) *bt.Tx {
 	tx := bt.NewTx()
 	err := tx.FromUTXOs(&bt.UTXO{
@@ -1310,3 +1831,146 @@ func Test_getSubtreeMissingTxs_InvalidSubtreeData(t *testing.T) {
 		t.Logf("Test completed without panic. Error (if any): %v", err)
 	})
 }
+
+func TestValidateBlockSubtreeWithRetry(t *testing.T) {
+	newRetryTestServer := func(t *testing.T, maxRetries int) *Server {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.SubtreeValidation.TransientErrorMaxRetries = maxRetries
+		tSettings.SubtreeValidation.TransientErrorRetryBackoff = time.Millisecond
+
+		return &Server{
+			logger:   ulogger.TestLogger{},
+			settings: tSettings,
+		}
+	}
+
+	subtreeHash := chainhash.HashH([]byte("subtree"))
+
+	t.Run("transient error is retried and succeeds", func(t *testing.T) {
+		server := newRetryTestServer(t, 3)
+		expectedSubtree := &subtreepkg.Subtree{}
+
+		calls := 0
+		subtree, err := server.validateBlockSubtreeWithRetry(context.Background(), subtreeHash, func() (*subtreepkg.Subtree, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.NewServiceError("failed to validate subtree", errors.NewStorageError("utxo store unavailable"))
+			}
+
+			return expectedSubtree, nil
+		})
+		require.NoError(t, err)
+		assert.Same(t, expectedSubtree, subtree)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("consensus error fails immediately", func(t *testing.T) {
+		server := newRetryTestServer(t, 3)
+
+		calls := 0
+		_, err := server.validateBlockSubtreeWithRetry(context.Background(), subtreeHash, func() (*subtreepkg.Subtree, error) {
+			calls++
+
+			return nil, errors.NewSubtreeInvalidError("subtree is invalid", errors.NewTxInvalidError("tx is invalid"))
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrSubtreeInvalid))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("invalidity wrapping a storage error fails immediately", func(t *testing.T) {
+		server := newRetryTestServer(t, 3)
+
+		calls := 0
+		_, err := server.validateBlockSubtreeWithRetry(context.Background(), subtreeHash, func() (*subtreepkg.Subtree, error) {
+			calls++
+
+			return nil, errors.NewTxInvalidError("tx is invalid", errors.NewStorageError("parent lookup failed"))
+		})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("utxo error fails immediately", func(t *testing.T) {
+		server := newRetryTestServer(t, 3)
+
+		calls := 0
+		_, err := server.validateBlockSubtreeWithRetry(context.Background(), subtreeHash, func() (*subtreepkg.Subtree, error) {
+			calls++
+
+			return nil, errors.NewProcessingError("failed to spend", errors.NewUtxoError("utxo already spent"))
+		})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		server := newRetryTestServer(t, 2)
+
+		calls := 0
+		_, err := server.validateBlockSubtreeWithRetry(context.Background(), subtreeHash, func() (*subtreepkg.Subtree, error) {
+			calls++
+
+			return nil, errors.NewNetworkTimeoutError("peer timed out")
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrNetworkTimeout))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("retries disabled", func(t *testing.T) {
+		server := newRetryTestServer(t, 0)
+
+		calls := 0
+		_, err := server.validateBlockSubtreeWithRetry(context.Background(), subtreeHash, func() (*subtreepkg.Subtree, error) {
+			calls++
+
+			return nil, errors.NewStorageError("utxo store unavailable")
+		})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestServer_checkCoinbaseMaturity(t *testing.T) {
+	InitPrometheusMetrics()
+
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.ChainCfgParams.CoinbaseMaturity = 100
+
+	// the validator is not set, an immature coinbase spend is rejected before the transaction is validated
+	server := &Server{settings: tSettings}
+
+	coinbaseHash := chainhash.HashH([]byte("coinbase"))
+	tx := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &coinbaseHash, Vout: 0})
+
+	tests := []struct {
+		name           string
+		coinbaseHeight uint32
+		blockHeight    uint32
+		immature       bool
+	}{
+		{name: "no coinbase spent", coinbaseHeight: 0, blockHeight: 10},
+		{name: "mature", coinbaseHeight: 200, blockHeight: 300},
+		{name: "mature after 100 confirmations", coinbaseHeight: 200, blockHeight: 350},
+		{name: "immature", coinbaseHeight: 200, blockHeight: 250, immature: true},
+		{name: "one block short", coinbaseHeight: 200, blockHeight: 299, immature: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := server.checkCoinbaseMaturity(chainhash.Hash{}, missingTx{tx: tx, coinbaseHeight: tt.coinbaseHeight}, tt.blockHeight)
+
+			if !tt.immature {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, errors.ErrTxCoinbaseImmature))
+
+			_, err = server.blessLevelTx(context.Background(), chainhash.Hash{}, missingTx{tx: tx, coinbaseHeight: tt.coinbaseHeight}, tt.blockHeight, nil, nil)
+			assert.True(t, errors.Is(err, errors.ErrTxCoinbaseImmature))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package subtreevalidation
+
+import (
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// acquireSubtreeBacklog adds an announced subtree to the backlog of subtrees awaiting validation. When
+// subtreevalidation_maxBacklog is set and the backlog is full, the subtree is not added and a
+// ServiceUnavailableError is returned. A subtree that is not validated when it is announced is validated when the
+// block containing it is received.
+//
+// Every successful call must be followed by a call to releaseSubtreeBacklog once the subtree is validated.
+//
+// Returns:
+//   - error: ServiceUnavailableError when the backlog is full, nil otherwise
+func (u *Server) acquireSubtreeBacklog() error {
+	InitPrometheusMetrics()
+
+	maxBacklog := int64(u.settings.SubtreeValidation.MaxBacklog)
+
+	for {
+		backlog := u.subtreeBacklog.Load()
+
+		if maxBacklog > 0 && backlog+1 > maxBacklog {
+			prometheusSubtreeValidationBacklogRejected.Inc()
+
+			return errors.NewServiceUnavailableError("[SubtreeValidation] subtree validation backlog is full, %d subtrees awaiting validation, max backlog %d", backlog, maxBacklog)
+		}
+
+		if u.subtreeBacklog.CompareAndSwap(backlog, backlog+1) {
+			prometheusSubtreeValidationBacklog.Inc()
+
+			return nil
+		}
+	}
+}
+
+// addSubtreeBacklog adds n subtrees to the backlog of subtrees awaiting validation without checking the maximum
+// backlog. It is used for the subtrees of blocks, which must be validated to process the block.
+func (u *Server) addSubtreeBacklog(n int) {
+	InitPrometheusMetrics()
+
+	u.subtreeBacklog.Add(int64(n))
+	prometheusSubtreeValidationBacklog.Add(float64(n))
+}
+
+// releaseSubtreeBacklog removes n validated subtrees from the backlog of subtrees awaiting validation.
+func (u *Server) releaseSubtreeBacklog(n int) {
+	u.subtreeBacklog.Add(-int64(n))
+	prometheusSubtreeValidationBacklog.Sub(float64(n))
+}
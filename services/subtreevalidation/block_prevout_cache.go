@@ -0,0 +1,66 @@
+package subtreevalidation
+
+import (
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// blockPrevoutCache holds the outputs of the transactions of a block that have been processed, so the inputs
+// of transactions spending outputs created earlier in the same block can be extended from memory, instead of
+// the validator looking up the previous outputs in the utxo store.
+//
+// The cache is populated level by level while the transactions of the block are processed in dependency order,
+// a transaction is only added after it has been validated successfully.
+type blockPrevoutCache struct {
+	mu      sync.RWMutex
+	outputs map[chainhash.Hash][]*bt.Output
+}
+
+// newBlockPrevoutCache creates a prevout cache sized for the given number of transactions.
+func newBlockPrevoutCache(size int) *blockPrevoutCache {
+	return &blockPrevoutCache{
+		outputs: make(map[chainhash.Hash][]*bt.Output, size),
+	}
+}
+
+// add adds the outputs of a processed transaction to the cache.
+func (c *blockPrevoutCache) add(tx *bt.Tx) {
+	c.mu.Lock()
+	c.outputs[*tx.TxIDChainHash()] = tx.Outputs
+	c.mu.Unlock()
+}
+
+// extend extends the inputs of a transaction from the cache. The transaction is only changed when all its
+// inputs spend outputs in the cache, a transaction that also spends outputs from before the block is left
+// for the validator to extend from the utxo store.
+//
+// Returns:
+//   - bool: true if the transaction was extended from the cache
+func (c *blockPrevoutCache) extend(tx *bt.Tx) bool {
+	if tx.IsCoinbase() || tx.IsExtended() {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, input := range tx.Inputs {
+		outputs, ok := c.outputs[*input.PreviousTxIDChainHash()]
+		if !ok || int(input.PreviousTxOutIndex) >= len(outputs) {
+			return false
+		}
+	}
+
+	for _, input := range tx.Inputs {
+		output := c.outputs[*input.PreviousTxIDChainHash()][input.PreviousTxOutIndex]
+
+		input.PreviousTxSatoshis = output.Satoshis
+		input.PreviousTxScript = output.LockingScript
+	}
+
+	tx.SetExtended(true)
+
+	return true
+}
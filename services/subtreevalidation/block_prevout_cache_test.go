@@ -0,0 +1,91 @@
+package subtreevalidation
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNonExtendedSpend creates a transaction spending the given outputs, without the previous output data set
+func newNonExtendedSpend(t *testing.T, parentHash *chainhash.Hash, vouts ...uint32) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+
+	for _, vout := range vouts {
+		input := &bt.Input{
+			PreviousTxOutIndex: vout,
+			SequenceNumber:     0xffffffff,
+		}
+		require.NoError(t, input.PreviousTxIDAdd(parentHash))
+
+		tx.Inputs = append(tx.Inputs, input)
+	}
+
+	require.NoError(t, tx.AddP2PKHOutputFromAddress("1JzfqRyLUiMn2MTwWsqXEXRu4ujNhqPqWD", 1000))
+
+	return tx
+}
+
+func TestBlockPrevoutCache(t *testing.T) {
+	t.Run("child spending parent in the same block is extended from the cache", func(t *testing.T) {
+		cache := newBlockPrevoutCache(2)
+		cache.add(parentTx1)
+
+		child := newNonExtendedSpend(t, parentTx1.TxIDChainHash(), 0, 2)
+		require.False(t, child.IsExtended())
+
+		assert.True(t, cache.extend(child))
+		assert.True(t, child.IsExtended())
+
+		assert.Equal(t, parentTx1.Outputs[0].Satoshis, child.Inputs[0].PreviousTxSatoshis)
+		assert.Equal(t, parentTx1.Outputs[0].LockingScript, child.Inputs[0].PreviousTxScript)
+		assert.Equal(t, parentTx1.Outputs[2].Satoshis, child.Inputs[1].PreviousTxSatoshis)
+		assert.Equal(t, parentTx1.Outputs[2].LockingScript, child.Inputs[1].PreviousTxScript)
+	})
+
+	t.Run("parent not in the cache", func(t *testing.T) {
+		cache := newBlockPrevoutCache(1)
+
+		child := newNonExtendedSpend(t, parentTx1.TxIDChainHash(), 0)
+
+		assert.False(t, cache.extend(child))
+		assert.False(t, child.IsExtended())
+		assert.Nil(t, child.Inputs[0].PreviousTxScript)
+	})
+
+	t.Run("output index out of range", func(t *testing.T) {
+		cache := newBlockPrevoutCache(1)
+		cache.add(parentTx1)
+
+		child := newNonExtendedSpend(t, parentTx1.TxIDChainHash(), uint32(len(parentTx1.Outputs)))
+
+		assert.False(t, cache.extend(child))
+		assert.False(t, child.IsExtended())
+	})
+
+	t.Run("only some inputs in the cache leaves the transaction untouched", func(t *testing.T) {
+		cache := newBlockPrevoutCache(1)
+		cache.add(parentTx1)
+
+		child := newNonExtendedSpend(t, parentTx1.TxIDChainHash(), 0)
+
+		unknown := &bt.Input{PreviousTxOutIndex: 0, SequenceNumber: 0xffffffff}
+		require.NoError(t, unknown.PreviousTxIDAdd(&chainhash.Hash{1}))
+		child.Inputs = append(child.Inputs, unknown)
+
+		assert.False(t, cache.extend(child))
+		assert.False(t, child.IsExtended())
+		assert.Nil(t, child.Inputs[0].PreviousTxScript)
+	})
+
+	t.Run("already extended transaction is not touched", func(t *testing.T) {
+		cache := newBlockPrevoutCache(1)
+		cache.add(parentTx1)
+
+		assert.False(t, cache.extend(tx1))
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
@@ -62,6 +63,11 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 	// Extract PeerID from request for tracking
 	peerID := request.PeerId
 
+	// the subtrees of a block count towards the backlog but are never rejected, the block cannot be processed
+	// without them
+	u.addSubtreeBacklog(len(block.Subtrees))
+	defer u.releaseSubtreeBacklog(len(block.Subtrees))
+
 	ctx, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "CheckBlockSubtrees",
 		tracing.WithParentStat(u.stats),
 		tracing.WithHistogram(prometheusSubtreeValidationCheckSubtree),
@@ -223,7 +229,15 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 				// get the subtree from the peer
 				url := fmt.Sprintf("%s/subtree/%s", request.BaseUrl, subtreeHash.String())
 
+				// wait for a free request slot of the peer, to not overload a single peer
+				release, err := u.peerSubtreeLimiter.Acquire(gCtx, request.BaseUrl)
+				if err != nil {
+					return errors.NewContextCanceledError("[CheckBlockSubtrees][%s] cancelled while waiting to get subtree from %s", subtreeHash.String(), url, err)
+				}
+
 				subtreeNodeBytes, err := util.DoHTTPRequest(gCtx, url)
+
+				release()
 				if err != nil {
 					return errors.NewServiceError("[CheckBlockSubtrees][%s] failed to get subtree from %s", subtreeHash.String(), url, err)
 				}
@@ -280,7 +294,7 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 				// get the subtree data from the peer and process it directly
 				url := fmt.Sprintf("%s/subtree_data/%s", request.BaseUrl, subtreeHash.String())
 
-				body, subtreeDataErr := util.DoHTTPRequestBodyReader(gCtx, url)
+				body, subtreeDataErr := u.doPeerRequestBodyReader(gCtx, request.BaseUrl, url)
 				if subtreeDataErr != nil {
 					return errors.NewServiceError("[CheckBlockSubtrees][%s] failed to get subtree data from %s", subtreeHash.String(), url, subtreeDataErr)
 				}
@@ -352,7 +366,7 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 	} else {
 		u.logger.Infof("[CheckBlockSubtrees] Processing %d transactions from %d subtrees using level-based validation", len(allTransactions), len(missingSubtrees))
 
-		if err = u.processTransactionsInLevels(ctx, allTransactions, block.Height, blockIds); err != nil {
+		if err = u.processTransactionsInLevels(ctx, allTransactions, block.Height, blockIds, request.BaseUrl); err != nil {
 			return nil, errors.NewProcessingError("[CheckBlockSubtreesRequest] Failed to process transactions in levels", err)
 		}
 
@@ -362,6 +376,11 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 		var revalidateSubtreesMutex sync.Mutex
 		revalidateSubtrees := make([]chainhash.Hash, 0, len(missingSubtrees))
 
+		// when the block is validated under a deadline, every subtree gets its share of the remaining time, so a
+		// single slow subtree cannot use up the time of the whole block; it is validated again below
+		subtreeBudget, subtreeBudgetLimited := subtreeValidationBudget(ctx, time.Now(), len(missingSubtrees),
+			u.settings.SubtreeValidation.CheckBlockSubtreesConcurrency, u.settings.SubtreeValidation.SubtreeDeadlineFactor)
+
 		// validate all the subtrees in parallel, since we already validated all transactions
 		for _, subtreeHash := range missingSubtrees {
 			subtreeHash := subtreeHash
@@ -375,8 +394,11 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 					PeerID:        peerID,
 				}
 
+				subtreeCtx, subtreeCancel := withSubtreeBudget(ctx, subtreeBudget, subtreeBudgetLimited)
+				defer subtreeCancel()
+
 				subtree, err := u.ValidateSubtreeInternal(
-					ctx,
+					subtreeCtx,
 					v,
 					block.Height,
 					blockIds,
@@ -385,7 +407,12 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 					validator.WithIgnoreLocked(true),
 				)
 				if err != nil {
-					u.logger.Debugf("[CheckBlockSubtreesRequest] Failed to validate subtree %s", subtreeHash.String(), err)
+					if subtreeCtx.Err() != nil && ctx.Err() == nil {
+						u.logger.Warnf("[CheckBlockSubtreesRequest] Validation of subtree %s exceeded its deadline of %s, validating it again after the other subtrees", subtreeHash.String(), subtreeBudget)
+					} else {
+						u.logger.Debugf("[CheckBlockSubtreesRequest] Failed to validate subtree %s", subtreeHash.String(), err)
+					}
+
 					revalidateSubtreesMutex.Lock()
 					revalidateSubtrees = append(revalidateSubtrees, subtreeHash)
 					revalidateSubtreesMutex.Unlock()
@@ -418,15 +445,18 @@ func (u *Server) CheckBlockSubtrees(ctx context.Context, request *subtreevalidat
 				PeerID:        peerID,
 			}
 
-			subtree, err := u.ValidateSubtreeInternal(
-				ctx,
-				v,
-				block.Height,
-				blockIds,
-				validator.WithSkipPolicyChecks(true),
-				validator.WithCreateConflicting(true),
-				validator.WithIgnoreLocked(true),
-			)
+			// transient store or network errors are retried, instead of failing the whole block
+			subtree, err := u.validateBlockSubtreeWithRetry(ctx, subtreeHash, func() (*subtreepkg.Subtree, error) {
+				return u.ValidateSubtreeInternal(
+					ctx,
+					v,
+					block.Height,
+					blockIds,
+					validator.WithSkipPolicyChecks(true),
+					validator.WithCreateConflicting(true),
+					validator.WithIgnoreLocked(true),
+				)
+			})
 			if err != nil {
 				return nil, errors.WrapGRPC(errors.NewProcessingError("[CheckBlockSubtreesRequest] Failed to validate subtree %s", subtreeHash.String(), err))
 			}
@@ -524,98 +554,19 @@ func (u *Server) processSubtreeDataStream(ctx context.Context, subtree *subtreep
 	return nil
 }
 
-// readTransactionsFromSubtreeDataStream reads transactions directly from subtreeData stream
-// This follows the same pattern as go-subtree's serializeFromReader but appends directly to the shared collection
-func (u *Server) readTransactionsFromSubtreeDataStream(subtree *subtreepkg.Subtree, reader io.Reader, subtreeTransactions *[]*bt.Tx) (int, error) {
-	txIndex := 0
-
-	if len(subtree.Nodes) > 0 && subtree.Nodes[0].Hash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
-		txIndex = 1
-	}
-
-	for {
-		tx := &bt.Tx{}
-
-		_, err := tx.ReadFrom(reader)
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				// End of stream reached
-				break
-			}
-			return txIndex, errors.NewProcessingError("[readTransactionsFromSubtreeDataStream] error reading transaction", err)
-		}
-
-		if tx.IsCoinbase() && txIndex == 1 {
-			// we did get an unexpected coinbase transaction
-			// reset the index to 0 to check the coinbase
-			txIndex = 0
-		}
-
-		tx.SetTxHash(tx.TxIDChainHash()) // Cache the transaction hash to avoid recomputing it
-
-		// Basic sanity check: ensure the transaction hash matches the expected hash from the subtree
-		if txIndex < subtree.Length() {
-			expectedHash := subtree.Nodes[txIndex].Hash
-			if !expectedHash.Equal(*tx.TxIDChainHash()) {
-				return txIndex, errors.NewProcessingError("[readTransactionsFromSubtreeDataStream] transaction hash mismatch at index %d: expected %s, got %s", txIndex, expectedHash.String(), tx.TxIDChainHash().String())
-			}
-		} else {
-			return txIndex, errors.NewProcessingError("[readTransactionsFromSubtreeDataStream] more transactions than expected in subtreeData")
-		}
-
-		*subtreeTransactions = append(*subtreeTransactions, tx)
-		txIndex++
-	}
-
-	return txIndex, nil
-}
-
-// processTransactionsInLevels processes all transactions from all subtrees using level-based validation
-// This ensures transactions are processed in dependency order while maximizing parallelism
-func (u *Server) processTransactionsInLevels(ctx context.Context, allTransactions []*bt.Tx,
-	blockHeight uint32, blockIds map[uint32]bool) error {
-	ctx, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "processTransactionsInLevels",
-		tracing.WithParentStat(u.stats),
-		tracing.WithLogMessage(u.logger, "[processTransactionsInLevels] Processing %d transactions at block height %d", len(allTransactions), blockHeight),
-	)
-	defer deferFn()
-
-	if len(allTransactions) == 0 {
-		return nil
-	}
-
-	u.logger.Infof("[processTransactionsInLevels] Organizing %d transactions into dependency levels", len(allTransactions))
-
-	// Convert transactions to missingTx format for prepareTxsPerLevel
-	missingTxs := make([]missingTx, len(allTransactions))
-	for i, tx := range allTransactions {
-		if tx == nil {
-			return errors.NewProcessingError("[processTransactionsInLevels] transaction is nil at index %d", i)
-		}
-
-		missingTxs[i] = missingTx{
-			tx:  tx,
-			idx: i,
-		}
-	}
-
-	// Use the existing prepareTxsPerLevel logic to organize transactions by dependency levels
-	maxLevel, txsPerLevel, err := u.prepareTxsPerLevel(ctx, missingTxs)
-	if err != nil {
-		return errors.NewProcessingError("[processTransactionsInLevels] Failed to prepare transactions per level", err)
-	}
-
-	u.logger.Infof("[processTransactionsInLevels] Processing transactions across %d levels", maxLevel+1)
-
+// levelValidatorOptions returns the validator options of the transactions of a block validated level by level.
+// Transactions are not added to block assembly during legacy syncing or catching up.
+func (u *Server) levelValidatorOptions(ctx context.Context) (*validator.Options, error) {
 	validatorOptions := []validator.Option{
 		validator.WithSkipPolicyChecks(true),
 		validator.WithCreateConflicting(true),
 		validator.WithIgnoreLocked(true),
+		validator.WithBacklogExempt(true),
 	}
 
 	currentState, err := u.blockchainClient.GetFSMCurrentState(ctx)
 	if err != nil {
-		return errors.NewProcessingError("[processTransactionsInLevels] Failed to get FSM current state", err)
+		return nil, errors.NewProcessingError("[processTransactionsInLevels] Failed to get FSM current state", err)
 	}
 
 	// During legacy syncing or catching up, disable adding transactions to block assembly
@@ -624,16 +575,61 @@ func (u *Server) processTransactionsInLevels(ctx context.Context, allTransaction
 	}
 
 	// Pre-process validation options
-	processedValidatorOptions := validator.ProcessOptions(validatorOptions...)
+	return validator.ProcessOptions(validatorOptions...), nil
+}
 
-	// Track validation results
-	var (
-		errorsFound      atomic.Uint64
-		addedToOrphanage atomic.Uint64
-	)
+// levelValidationResults tracks the results of the transactions validated level by level
+type levelValidationResults struct {
+	errorsFound      atomic.Uint64
+	addedToOrphanage atomic.Uint64
+	prevoutCacheHits atomic.Uint64
+
+	// txResults records the outcome of every transaction by its index in the subtree when set, the validation then
+	// continues past invalid transactions instead of failing early
+	txResults *SubtreeValidationResult
+}
+
+// record records the outcome of the validation of the transaction at the given index, when the outcome of every
+// transaction is recorded
+func (r *levelValidationResults) record(idx int, err error) {
+	if r.txResults != nil {
+		r.txResults.record(idx, err)
+	}
+}
+
+// recordPrevoutCacheHits records the prevout cache hits in the metrics
+func (r *levelValidationResults) recordPrevoutCacheHits() {
+	if r.prevoutCacheHits.Load() > 0 {
+		prometheusSubtreeValidationBlockPrevoutCacheHits.Add(float64(r.prevoutCacheHits.Load()))
+	}
+}
+
+// err records the prevout cache hits and returns an error when any transaction failed validation
+func (r *levelValidationResults) err() error {
+	r.recordPrevoutCacheHits()
+
+	if r.errorsFound.Load() > 0 {
+		return errors.NewProcessingError("[processTransactionsInLevels] Completed processing with %d errors, %d transactions added to orphanage", r.errorsFound.Load(), r.addedToOrphanage.Load())
+	}
+
+	return nil
+}
 
-	// Process each level in series, but all transactions within a level in parallel
+// validateLevels validates the transactions level by level, each level in series, but all transactions within a
+// level in parallel. Transactions with missing parents are added to the orphanage when the node is running, the
+// validation fails early on an invalid transaction, unless the results record the outcome of every transaction,
+// all other failures are counted in the results.
+//
+// The context is checked before every level and before every transaction is started, see levelContextError, so a
+// cancelled or expired context stops the validation promptly instead of pushing through the remaining levels.
+func (u *Server) validateLevels(ctx context.Context, maxLevel uint32, txsPerLevel [][]missingTx, sizeBucket string,
+	blockHeight uint32, blockIds map[uint32]bool, baseURL string, processedValidatorOptions *validator.Options,
+	prevoutCache *blockPrevoutCache, results *levelValidationResults) error {
 	for level := uint32(0); level <= maxLevel; level++ {
+		if err := levelContextError(ctx, "processTransactionsInLevels", level, maxLevel); err != nil {
+			return err
+		}
+
 		levelTxs := txsPerLevel[level]
 		if len(levelTxs) == 0 {
 			continue
@@ -641,9 +637,11 @@ func (u *Server) processTransactionsInLevels(ctx context.Context, allTransaction
 
 		u.logger.Debugf("[processTransactionsInLevels] Processing level %d/%d with %d transactions", level+1, maxLevel+1, len(levelTxs))
 
+		levelStart := time.Now()
+
 		// Process all transactions at this level in parallel
 		g, gCtx := errgroup.WithContext(ctx)
-		util.SafeSetLimit(g, u.settings.SubtreeValidation.SpendBatcherSize*2)
+		util.SafeSetLimit(g, u.levelValidationConcurrency())
 
 		for _, mTx := range levelTxs {
 			tx := mTx.tx
@@ -651,28 +649,50 @@ func (u *Server) processTransactionsInLevels(ctx context.Context, allTransaction
 				return errors.NewProcessingError("[processTransactionsInLevels] transaction is nil at level %d", level)
 			}
 
-			g.Go(func() error {
+			// stop starting transactions once the context is done, wait for the transactions already started
+			if gCtx.Err() != nil {
+				break
+			}
+
+			g.Go(recoverLevelValidation(func() error {
+				// the context may be done while the goroutine waited to be scheduled
+				if gCtx.Err() != nil {
+					return nil
+				}
+
+				if prevoutCache != nil && prevoutCache.extend(tx) {
+					results.prevoutCacheHits.Add(1)
+				}
+
 				// Use existing blessMissingTransaction logic for validation
-				txMeta, err := u.blessMissingTransaction(gCtx, chainhash.Hash{}, tx, blockHeight, blockIds, processedValidatorOptions)
+				txMeta, err := u.blessLevelTx(gCtx, chainhash.Hash{}, mTx, blockHeight, blockIds, processedValidatorOptions)
 				if err != nil {
 					u.logger.Debugf("[processTransactionsInLevels] Failed to validate transaction %s: %v", tx.TxIDChainHash().String(), err)
 
 					// TX_EXISTS is not an error - transaction was already validated
 					if errors.Is(err, errors.ErrTxExists) {
 						u.logger.Debugf("[processTransactionsInLevels] Transaction %s already exists, skipping", tx.TxIDChainHash().String())
+
+						if prevoutCache != nil {
+							prevoutCache.add(tx)
+						}
+
+						results.record(mTx.idx, nil)
+
 						return nil
 					}
 
 					// Count all other errors
-					errorsFound.Add(1)
+					results.errorsFound.Add(1)
+					results.record(mTx.idx, err)
 
 					// Handle missing parent transactions by adding to orphanage
 					if errors.Is(err, errors.ErrTxMissingParent) {
 						isRunning, runningErr := u.blockchainClient.IsFSMCurrentState(gCtx, blockchain.FSMStateRUNNING)
 						if runningErr == nil && isRunning {
 							u.logger.Debugf("[processTransactionsInLevels] Transaction %s missing parent, adding to orphanage", tx.TxIDChainHash().String())
-							if u.orphanage.Set(*tx.TxIDChainHash(), tx) {
-								addedToOrphanage.Add(1)
+							if u.orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, baseURL) {
+								results.addedToOrphanage.Add(1)
 							} else {
 								u.logger.Warnf("[processTransactionsInLevels] Failed to add transaction %s to orphanage - orphanage is full", tx.TxIDChainHash().String())
 							}
@@ -683,7 +703,7 @@ func (u *Server) processTransactionsInLevels(ctx context.Context, allTransaction
 						// Log truly invalid transactions
 						u.logger.Warnf("[processTransactionsInLevels] Invalid transaction detected: %s: %v", tx.TxIDChainHash().String(), err)
 
-						if errors.Is(err, errors.ErrTxInvalid) {
+						if results.txResults == nil {
 							return err
 						}
 					} else {
@@ -699,20 +719,119 @@ func (u *Server) processTransactionsInLevels(ctx context.Context, allTransaction
 					u.logger.Debugf("[processTransactionsInLevels] Successfully validated transaction %s", tx.TxIDChainHash().String())
 				}
 
+				if prevoutCache != nil {
+					prevoutCache.add(tx)
+				}
+
+				results.record(mTx.idx, nil)
+
 				return nil
-			})
+			}))
 		}
 
 		// Fail early if we get an actual tx error thrown
-		if err = g.Wait(); err != nil {
+		err := g.Wait()
+
+		observeLevelDuration(sizeBucket, levelStart)
+
+		if err != nil {
 			return errors.NewProcessingError("[processTransactionsInLevels] Failed to process level %d", level+1, err)
 		}
 
+		// the transactions not started because the context is done are not validated
+		if err = levelContextError(ctx, "processTransactionsInLevels", level, maxLevel); err != nil {
+			return err
+		}
+
 		u.logger.Debugf("[processTransactionsInLevels] Processing level %d/%d with %d transactions DONE", level+1, maxLevel+1, len(levelTxs))
 	}
 
-	if errorsFound.Load() > 0 {
-		return errors.NewProcessingError("[processTransactionsInLevels] Completed processing with %d errors, %d transactions added to orphanage", errorsFound.Load(), addedToOrphanage.Load())
+	return nil
+}
+
+// readTransactionsFromSubtreeDataStream reads transactions directly from subtreeData stream
+// This follows the same pattern as go-subtree's serializeFromReader but appends directly to the shared collection
+func (u *Server) readTransactionsFromSubtreeDataStream(subtree *subtreepkg.Subtree, reader io.Reader, subtreeTransactions *[]*bt.Tx) (int, error) {
+	dataReader := newSubtreeDataReader(subtree, reader)
+
+	for {
+		tx, err := dataReader.next()
+		if err == io.EOF {
+			// End of stream reached
+			break
+		}
+
+		if err != nil {
+			return dataReader.txIndex, err
+		}
+
+		*subtreeTransactions = append(*subtreeTransactions, tx)
+	}
+
+	return dataReader.txIndex, nil
+}
+
+// processTransactionsInLevels processes all transactions from all subtrees using level-based validation
+// This ensures transactions are processed in dependency order while maximizing parallelism
+func (u *Server) processTransactionsInLevels(ctx context.Context, allTransactions []*bt.Tx,
+	blockHeight uint32, blockIds map[uint32]bool, baseURL string) error {
+	ctx, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "processTransactionsInLevels",
+		tracing.WithParentStat(u.stats),
+		tracing.WithLogMessage(u.logger, "[processTransactionsInLevels] Processing %d transactions at block height %d", len(allTransactions), blockHeight),
+	)
+	defer deferFn()
+
+	if len(allTransactions) == 0 {
+		return nil
+	}
+
+	u.logger.Infof("[processTransactionsInLevels] Organizing %d transactions into dependency levels", len(allTransactions))
+
+	// Convert transactions to missingTx format for prepareTxsPerLevel
+	missingTxs := make([]missingTx, len(allTransactions))
+	for i, tx := range allTransactions {
+		if tx == nil {
+			return errors.NewProcessingError("[processTransactionsInLevels] transaction is nil at index %d", i)
+		}
+
+		missingTxs[i] = missingTx{
+			tx:  tx,
+			idx: i,
+		}
+	}
+
+	// Use the existing prepareTxsPerLevel logic to organize transactions by dependency levels
+	maxLevel, txsPerLevel, err := u.prepareTxsPerLevel(ctx, missingTxs)
+	if err != nil {
+		return errors.NewProcessingError("[processTransactionsInLevels] Failed to prepare transactions per level", err)
+	}
+
+	defer u.releaseTxsPerLevel(txsPerLevel)
+
+	u.logger.Infof("[processTransactionsInLevels] Processing transactions across %d levels", maxLevel+1)
+
+	sizeBucket := subtreeSizeBucket(len(allTransactions))
+	observeTxsPerLevel(sizeBucket, maxLevel, txsPerLevel)
+
+	processedValidatorOptions, err := u.levelValidatorOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var results levelValidationResults
+
+	// the prevout cache extends transactions spending outputs created earlier in the block, without a store round-trip
+	var prevoutCache *blockPrevoutCache
+	if u.settings.SubtreeValidation.BlockPrevoutCacheEnabled {
+		prevoutCache = newBlockPrevoutCache(len(allTransactions))
+	}
+
+	if err = u.validateLevels(ctx, maxLevel, txsPerLevel, sizeBucket, blockHeight, blockIds, baseURL, processedValidatorOptions, prevoutCache, &results); err != nil {
+		return err
+	}
+
+	if err = results.err(); err != nil {
+		return err
 	}
 
 	u.logger.Infof("[processTransactionsInLevels] Successfully processed all %d transactions", len(allTransactions))
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	subtreepkg "github.com/bsv-blockchain/go-subtree"
 	"github.com/bsv-blockchain/teranode/errors"
@@ -816,6 +817,155 @@ func TestPrepareTxsPerLevel(t *testing.T) {
 	})
 }
 
+func TestPrepareTxsPerLevel_ExternalParents(t *testing.T) {
+	availableParent := chainhash.HashH([]byte("available parent"))
+	unavailableParent := chainhash.HashH([]byte("unavailable parent"))
+
+	newTx := func(parentHash chainhash.Hash) *bt.Tx {
+		tx := bt.NewTx()
+
+		input := &bt.Input{PreviousTxOutIndex: 0, SequenceNumber: bt.DefaultSequenceNumber}
+		require.NoError(t, input.PreviousTxIDAdd(&parentHash))
+
+		tx.Inputs = append(tx.Inputs, input)
+		tx.AddOutput(&bt.Output{Satoshis: 1000, LockingScript: bscript.NewFromBytes([]byte{0x51})})
+
+		return tx
+	}
+
+	parentTx := newTx(availableParent)
+	childTx := newTx(*parentTx.TxIDChainHash())
+	orphanTx := newTx(unavailableParent)
+	orphanChildTx := newTx(*orphanTx.TxIDChainHash())
+
+	missingTxs := []missingTx{
+		{tx: orphanChildTx, idx: 0},
+		{tx: childTx, idx: 1},
+		{tx: orphanTx, idx: 2},
+		{tx: parentTx, idx: 3},
+	}
+
+	levelOf := func(txsPerLevel [][]missingTx, tx *bt.Tx) int {
+		for level, txs := range txsPerLevel {
+			for _, mTx := range txs {
+				if mTx.tx == tx {
+					return level
+				}
+			}
+		}
+
+		return -1
+	}
+
+	t.Run("unavailable external parent is held back", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("BatchDecorate", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				for _, item := range args.Get(1).([]*utxo.UnresolvedMetaData) {
+					// only the parents outside the subtree are looked up
+					require.Contains(t, []chainhash.Hash{availableParent, unavailableParent}, item.Hash)
+
+					if item.Hash.Equal(unavailableParent) {
+						item.Err = errors.NewTxNotFoundError("not found")
+					} else {
+						item.Data = &utxometa.Data{BlockIDs: []uint32{1}}
+					}
+				}
+			}).
+			Return(nil).Once()
+
+		server.utxoStore = mockUtxoStore
+
+		maxLevel, txsPerLevel, err := server.prepareTxsPerLevel(context.Background(), missingTxs)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint32(3), maxLevel)
+		assert.Equal(t, 0, levelOf(txsPerLevel, parentTx))
+		assert.Equal(t, 1, levelOf(txsPerLevel, childTx))
+		assert.Equal(t, 2, levelOf(txsPerLevel, orphanTx))
+		assert.Equal(t, 3, levelOf(txsPerLevel, orphanChildTx))
+
+		mockUtxoStore.AssertExpectations(t)
+	})
+
+	t.Run("all external parents available", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		maxLevel, txsPerLevel, err := server.prepareTxsPerLevel(context.Background(), missingTxs)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint32(1), maxLevel)
+		assert.Equal(t, 0, levelOf(txsPerLevel, parentTx))
+		assert.Equal(t, 0, levelOf(txsPerLevel, orphanTx))
+		assert.Equal(t, 1, levelOf(txsPerLevel, childTx))
+		assert.Equal(t, 1, levelOf(txsPerLevel, orphanChildTx))
+	})
+
+	t.Run("coinbase spending transaction stays at level 0", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		coinbaseParent := chainhash.HashH([]byte("coinbase parent"))
+		coinbaseSpendingTx := newTx(coinbaseParent)
+
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("BatchDecorate", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				for _, item := range args.Get(1).([]*utxo.UnresolvedMetaData) {
+					switch {
+					case item.Hash.Equal(unavailableParent):
+						item.Err = errors.NewTxNotFoundError("not found")
+					case item.Hash.Equal(coinbaseParent):
+						item.Data = &utxometa.Data{IsCoinbase: true, BlockIDs: []uint32{5}, BlockHeights: []uint32{5}}
+					default:
+						item.Data = &utxometa.Data{BlockIDs: []uint32{1}}
+					}
+				}
+			}).
+			Return(nil).Once()
+
+		server.utxoStore = mockUtxoStore
+
+		transactions := append([]missingTx{{tx: coinbaseSpendingTx, idx: 4}}, missingTxs...)
+
+		_, txsPerLevel, err := server.prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+
+		// the transaction spending the coinbase is not held back, the transaction spending the unavailable parent is
+		assert.Equal(t, 0, levelOf(txsPerLevel, coinbaseSpendingTx))
+		assert.Equal(t, 2, levelOf(txsPerLevel, orphanTx))
+
+		for _, mTx := range txsPerLevel[0] {
+			if mTx.tx == coinbaseSpendingTx {
+				assert.Equal(t, uint32(5), mTx.coinbaseHeight)
+			} else {
+				assert.Zero(t, mTx.coinbaseHeight)
+			}
+		}
+	})
+
+	t.Run("lookup failure does not hold back", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("BatchDecorate", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.NewStorageUnavailableError("connection refused"))
+
+		server.utxoStore = mockUtxoStore
+
+		maxLevel, txsPerLevel, err := server.prepareTxsPerLevel(context.Background(), missingTxs)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint32(1), maxLevel)
+		assert.Equal(t, 0, levelOf(txsPerLevel, orphanTx))
+	})
+}
+
 func TestProcessTransactionsInLevels(t *testing.T) {
 	t.Run("EmptyTransactions", func(t *testing.T) {
 		server, cleanup := setupTestServer(t)
@@ -824,7 +974,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 		var allTransactions []*bt.Tx
 		blockIds := make(map[uint32]bool)
 
-		err := server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err := server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.NoError(t, err)
 	})
 
@@ -848,7 +998,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 			mock.Anything, blockchain.FSMStateRUNNING).
 			Return(true, nil)
 
-		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.NoError(t, err)
 	})
 
@@ -875,7 +1025,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 			Return(true, nil)
 
 		// Should fail with validation errors (errors are logged but not returned)
-		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.Error(t, err)
 	})
 
@@ -902,7 +1052,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 			Return(true, nil)
 
 		// Should fail because transaction has missing parent
-		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "processTransactionsInLevels")
 
@@ -932,7 +1082,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 			Return(false, nil)
 
 		// Should fail because transaction has validation errors and blockchain not running
-		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "processTransactionsInLevels")
 
@@ -962,7 +1112,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 			Return(false, errors.NewServiceError("blockchain client error"))
 
 		// Should fail because transaction has validation errors and blockchain client error
-		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "processTransactionsInLevels")
 
@@ -979,7 +1129,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 		blockIds := make(map[uint32]bool)
 
 		// Should fail with nil transaction
-		err := server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err := server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "transaction is nil")
 	})
@@ -1014,7 +1164,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 			mock.Anything, blockchain.FSMStateRUNNING).
 			Return(true, nil)
 
-		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err = server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.NoError(t, err)
 	})
 
@@ -1047,7 +1197,7 @@ func TestProcessTransactionsInLevels(t *testing.T) {
 			Return(true, nil)
 
 		// Should return error even some validation failures
-		err := server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds)
+		err := server.processTransactionsInLevels(context.Background(), allTransactions, 100, blockIds, "")
 		require.Error(t, err)
 	})
 }
@@ -1385,6 +1535,8 @@ func (m *MockBlobStore) Close(ctx context.Context) error {
 
 // Helper function to setup test server
 func setupTestServer(t *testing.T) (*Server, func()) {
+	InitPrometheusMetrics()
+
 	logger := &ulogger.TestLogger{}
 
 	// Create test settings
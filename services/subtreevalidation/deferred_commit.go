@@ -0,0 +1,282 @@
+package subtreevalidation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/ordishs/gocore"
+)
+
+// changeSetState is the state of a UTXOChangeSet
+type changeSetState int
+
+const (
+	// changeSetOpen accepts more validated subtrees
+	changeSetOpen changeSetState = iota
+
+	// changeSetCommitted was written to the UTXO store
+	changeSetCommitted
+
+	// changeSetRolledBack was discarded, nothing of it is in the UTXO store
+	changeSetRolledBack
+)
+
+// UTXOChangeSet gathers the UTXO changes of the subtrees of a block validated in deferred commit mode, so they are
+// only written to the UTXO store when the block is accepted, and never when it is rejected.
+//
+// The subtrees must be validated in block order, later subtrees may spend the outputs created by earlier ones.
+// A change set is committed or rolled back once, it can not be reused.
+type UTXOChangeSet struct {
+	mu          sync.Mutex
+	blockHeight uint32
+	state       changeSetState
+
+	// txs holds the validated transactions, in block order
+	txs []*bt.Tx
+
+	// created and spent overlay the UTXO store with the outputs created and spent by the validated transactions
+	created map[chainhash.Hash]*bt.Tx
+	spent   map[dryRunOutpoint]int
+}
+
+// NewUTXOChangeSet creates an empty change set for the subtrees of a block at the given height.
+func NewUTXOChangeSet(blockHeight uint32) *UTXOChangeSet {
+	return &UTXOChangeSet{
+		blockHeight: blockHeight,
+		created:     make(map[chainhash.Hash]*bt.Tx),
+		spent:       make(map[dryRunOutpoint]int),
+	}
+}
+
+// Len returns the number of transactions in the change set.
+func (c *UTXOChangeSet) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.txs)
+}
+
+// Delta returns the outpoints the change set spends and creates in the UTXO store, in block order.
+func (c *UTXOChangeSet) Delta() *UTXODelta {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delta := &UTXODelta{}
+	delta.add(c.txs, c.blockHeight)
+
+	return delta
+}
+
+// Rollback discards the change set when the block is rejected. Nothing was written to the UTXO store, so the UTXO
+// set is left unchanged. Rolling back a committed change set is an error.
+func (c *UTXOChangeSet) Rollback() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == changeSetCommitted {
+		return errors.NewProcessingError("[UTXOChangeSet] can not roll back a committed change set")
+	}
+
+	c.state = changeSetRolledBack
+	c.txs = nil
+	c.created = nil
+	c.spent = nil
+
+	return nil
+}
+
+// ValidateSubtreeDeferred validates the transactions of a subtree without writing them to the UTXO store, gathering
+// the UTXO changes in the change set instead. The change set is written to the UTXO store with CommitUTXOChangeSet
+// when the block is accepted, or discarded with Rollback when it is rejected.
+//
+// The transactions are validated in subtree order against the UTXO store, overlaid with the change set, and only
+// the consensus rules are checked, like a regular subtree validation. The transactions of the subtree that are
+// already in the UTXO store are not validated again. The change set is left unchanged when a transaction fails.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - v: The subtree to validate, AllowFailFast and ProgressFunc are ignored
+//   - changeSet: The change set of the block of the subtree
+//
+// Returns:
+//   - error: A subtree invalid error when a transaction fails, or any error retrieving the subtree
+func (u *Server) ValidateSubtreeDeferred(ctx context.Context, v ValidateSubtree, changeSet *UTXOChangeSet) (err error) {
+	ctx, _, endSpan := tracing.Tracer("subtreevalidation").Start(ctx, "ValidateSubtreeDeferred",
+		tracing.WithDebugLogMessage(u.logger, "[ValidateSubtreeDeferred][%s] called", v.SubtreeHash.String()),
+	)
+
+	defer func() {
+		endSpan(err)
+	}()
+
+	txHashes := v.TxHashes
+	if txHashes == nil {
+		if txHashes, err = u.getSubtreeTxHashes(ctx, gocore.NewStat("ValidateSubtreeDeferred"), &v.SubtreeHash, v.BaseURL); err != nil {
+			return errors.NewServiceError("[ValidateSubtreeDeferred][%s] failed to get subtree", v.SubtreeHash.String(), err)
+		}
+	}
+
+	txs, err := u.getDryRunTransactions(ctx, v, txHashes)
+	if err != nil {
+		return err
+	}
+
+	changeSet.mu.Lock()
+	defer changeSet.mu.Unlock()
+
+	if changeSet.state != changeSetOpen {
+		return errors.NewProcessingError("[ValidateSubtreeDeferred][%s] change set was already committed or rolled back", v.SubtreeHash.String())
+	}
+
+	txCount := len(changeSet.txs)
+
+	if failure := u.addToChangeSet(ctx, u.utxoStore, txs, changeSet); failure != nil {
+		return changeSetFailureError("ValidateSubtreeDeferred", v.SubtreeHash, failure)
+	}
+
+	u.logger.Debugf("[ValidateSubtreeDeferred][%s] added %d transactions to the change set", v.SubtreeHash.String(), len(changeSet.txs)-txCount)
+
+	return nil
+}
+
+// addToChangeSet validates the transactions of a subtree, in subtree order, against the UTXO store read through the
+// reader and overlaid with the change set, adding them to the change set. It returns the first failing transaction,
+// leaving the change set unchanged, or nil when all transactions are valid. The caller must hold the lock.
+func (u *Server) addToChangeSet(ctx context.Context, reader dryRunReader, txs []missingTx, changeSet *UTXOChangeSet) *DryRunTxResult {
+	blockState := u.utxoStore.GetBlockState()
+	tv := validator.NewTxValidator(u.logger, u.settings)
+	txCount := len(changeSet.txs)
+
+	for _, mTx := range txs {
+		txHash := *mTx.tx.TxIDChainHash()
+
+		var failure *DryRunTxResult
+
+		if _, duplicate := changeSet.created[txHash]; duplicate {
+			failure = &DryRunTxResult{Index: mTx.idx, TxHash: txHash, InputIndex: -1, Category: DryRunErrorTxInvalid, Error: "duplicate transaction in block"}
+		} else {
+			failure = u.dryRunTransaction(ctx, reader, tv, mTx, changeSet.blockHeight, blockState.MedianTime, changeSet.created, map[chainhash.Hash]int{}, changeSet.spent)
+		}
+
+		if failure != nil {
+			changeSet.truncate(txCount)
+
+			return failure
+		}
+
+		changeSet.txs = append(changeSet.txs, mTx.tx)
+		changeSet.created[txHash] = mTx.tx
+
+		for _, input := range mTx.tx.Inputs {
+			changeSet.spent[dryRunOutpoint{hash: *input.PreviousTxIDChainHash(), vout: input.PreviousTxOutIndex}] = mTx.idx
+		}
+	}
+
+	return nil
+}
+
+// changeSetFailureError returns the error of a subtree with a transaction failing validation: a processing error
+// when the transaction could not be checked, a subtree invalid error otherwise. The error is annotated with the
+// context of the failing transaction.
+func changeSetFailureError(caller string, subtreeHash chainhash.Hash, failure *DryRunTxResult) error {
+	var err error
+
+	if failure.Category == DryRunErrorProcessing {
+		err = errors.NewProcessingError("[%s][%s] failed to validate transaction %s at index %d: %s", caller, subtreeHash.String(), failure.TxHash.String(), failure.Index, failure.Error)
+	} else {
+		err = errors.NewSubtreeInvalidError("[%s][%s] transaction %s at index %d is invalid: %s", caller, subtreeHash.String(), failure.TxHash.String(), failure.Index, failure.Error)
+	}
+
+	return annotateTxError(err, subtreeHash, failure.TxHash, failure.Index, failure.InputIndex, nil)
+}
+
+// truncate removes the transactions added to the change set after the first txCount transactions. The caller must
+// hold the lock.
+func (c *UTXOChangeSet) truncate(txCount int) {
+	for _, tx := range c.txs[txCount:] {
+		delete(c.created, *tx.TxIDChainHash())
+
+		for _, input := range tx.Inputs {
+			delete(c.spent, dryRunOutpoint{hash: *input.PreviousTxIDChainHash(), vout: input.PreviousTxOutIndex})
+		}
+	}
+
+	c.txs = c.txs[:txCount]
+}
+
+// CommitUTXOChangeSet writes the change set to the UTXO store when the block is accepted, spending the inputs and
+// creating the outputs of every transaction in block order, like the validator does.
+//
+// The commit is atomic: when any write fails, the transactions already written are deleted and their spends are
+// reverted, leaving the UTXO set as it was before the commit. The change set can not be committed again after a
+// failure.
+func (u *Server) CommitUTXOChangeSet(ctx context.Context, changeSet *UTXOChangeSet) (err error) {
+	ctx, _, endSpan := tracing.Tracer("subtreevalidation").Start(ctx, "CommitUTXOChangeSet")
+
+	defer func() {
+		endSpan(err)
+	}()
+
+	changeSet.mu.Lock()
+	defer changeSet.mu.Unlock()
+
+	if changeSet.state != changeSetOpen {
+		return errors.NewProcessingError("[CommitUTXOChangeSet] change set was already committed or rolled back")
+	}
+
+	// the change set is never committed twice, even when the commit fails
+	changeSet.state = changeSetRolledBack
+
+	var (
+		spends  [][]*utxo.Spend
+		created []*chainhash.Hash
+	)
+
+	for _, tx := range changeSet.txs {
+		txSpends, err := u.utxoStore.Spend(ctx, tx, changeSet.blockHeight)
+		if err != nil {
+			u.revertUTXOChangeSet(ctx, spends, created)
+
+			return errors.NewStorageError("[CommitUTXOChangeSet] failed to spend the inputs of transaction %s", tx.TxIDChainHash().String(), err)
+		}
+
+		spends = append(spends, txSpends)
+
+		if _, err = u.utxoStore.Create(ctx, tx, changeSet.blockHeight); err != nil {
+			u.revertUTXOChangeSet(ctx, spends, created)
+
+			return errors.NewStorageError("[CommitUTXOChangeSet] failed to create transaction %s", tx.TxIDChainHash().String(), err)
+		}
+
+		created = append(created, tx.TxIDChainHash())
+	}
+
+	changeSet.state = changeSetCommitted
+
+	u.logger.Infof("[CommitUTXOChangeSet] committed %d transactions at height %d", len(changeSet.txs), changeSet.blockHeight)
+
+	return nil
+}
+
+// revertUTXOChangeSet reverts the writes of a failed commit, one transaction at a time in reverse order, so the
+// outputs of a parent are unspent before the parent is deleted. A failure to revert is logged, since there is
+// nothing left to undo it.
+func (u *Server) revertUTXOChangeSet(ctx context.Context, spends [][]*utxo.Spend, created []*chainhash.Hash) {
+	for i := len(spends) - 1; i >= 0; i-- {
+		if i < len(created) {
+			if err := u.utxoStore.Delete(ctx, created[i]); err != nil {
+				u.logger.Errorf("[CommitUTXOChangeSet] failed to delete transaction %s while reverting the commit: %v", created[i].String(), err)
+			}
+		}
+
+		if err := u.utxoStore.Unspend(ctx, spends[i]); err != nil {
+			u.logger.Errorf("[CommitUTXOChangeSet] failed to unspend %d outputs while reverting the commit: %v", len(spends[i]), err)
+		}
+	}
+}
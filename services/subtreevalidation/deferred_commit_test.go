@@ -0,0 +1,238 @@
+package subtreevalidation
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/stores/blob"
+	blobmemory "github.com/bsv-blockchain/teranode/stores/blob/memory"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferredUTXOCommit(t *testing.T) {
+	const blockHeight = 123
+
+	opTrue := bscript.NewFromBytes([]byte{bscript.OpTRUE})
+
+	// spendTx creates a transaction spending an output locked with OP_TRUE
+	spendTx := func(t *testing.T, parent *bt.Tx, vout uint32, satoshis uint64) *bt.Tx {
+		tx := bt.NewTx()
+
+		input := &bt.Input{PreviousTxOutIndex: vout, UnlockingScript: &bscript.Script{}, SequenceNumber: bt.DefaultSequenceNumber}
+		require.NoError(t, input.PreviousTxIDAdd(parent.TxIDChainHash()))
+
+		tx.Inputs = append(tx.Inputs, input)
+		tx.AddOutput(&bt.Output{Satoshis: satoshis, LockingScript: opTrue})
+
+		return tx
+	}
+
+	setup := func(t *testing.T) (*Server, utxo.Store, *bt.Tx) {
+		tSettings := test.CreateBaseTestSettings(t)
+
+		utxoStoreURL, err := url.Parse("sqlitememory:///test")
+		require.NoError(t, err)
+
+		utxoStore, err := sql.New(t.Context(), ulogger.TestLogger{}, tSettings, utxoStoreURL)
+		require.NoError(t, err)
+
+		require.NoError(t, utxoStore.SetBlockHeight(blockHeight-1))
+
+		// a parent with two outputs locked with OP_TRUE, already in the utxo store
+		parent := bt.NewTx()
+
+		input := &bt.Input{PreviousTxOutIndex: 0, UnlockingScript: &bscript.Script{}, SequenceNumber: bt.DefaultSequenceNumber}
+		grandParentHash := chainhash.HashH([]byte("grand parent"))
+		require.NoError(t, input.PreviousTxIDAdd(&grandParentHash))
+
+		parent.Inputs = append(parent.Inputs, input)
+		parent.AddOutput(&bt.Output{Satoshis: 10_000, LockingScript: opTrue})
+		parent.AddOutput(&bt.Output{Satoshis: 10_000, LockingScript: opTrue})
+
+		_, err = utxoStore.Create(t.Context(), parent, blockHeight-1)
+		require.NoError(t, err)
+
+		u := &Server{
+			logger:    ulogger.TestLogger{},
+			settings:  tSettings,
+			utxoStore: utxoStore,
+		}
+
+		return u, utxoStore, parent
+	}
+
+	// validate validates the transactions as a subtree in deferred commit mode
+	validate := func(t *testing.T, u *Server, changeSet *UTXOChangeSet, txs ...*bt.Tx) error {
+		u.subtreeStore = blobmemory.New()
+		subtreeHash, txHashes := storeSubtreeData(t, u.subtreeStore, txs)
+
+		return u.ValidateSubtreeDeferred(t.Context(), ValidateSubtree{SubtreeHash: subtreeHash, TxHashes: txHashes}, changeSet)
+	}
+
+	// spendStatus returns the status of an output of a transaction in the utxo store
+	spendStatus := func(t *testing.T, utxoStore utxo.Store, tx *bt.Tx, vout uint32) utxo.Status {
+		utxoHash, err := util.UTXOHashFromOutput(tx.TxIDChainHash(), tx.Outputs[vout], vout)
+		require.NoError(t, err)
+
+		spend, err := utxoStore.GetSpend(t.Context(), &utxo.Spend{TxID: tx.TxIDChainHash(), Vout: vout, UTXOHash: utxoHash})
+		require.NoError(t, err)
+
+		return utxo.Status(spend.Status)
+	}
+
+	assertNotInStore := func(t *testing.T, utxoStore utxo.Store, txs ...*bt.Tx) {
+		for _, tx := range txs {
+			_, err := utxoStore.Get(t.Context(), tx.TxIDChainHash())
+			assert.True(t, errors.Is(err, errors.ErrTxNotFound), "transaction %s should not be in the utxo store", tx.TxIDChainHash().String())
+		}
+	}
+
+	t.Run("accepted block is committed", func(t *testing.T) {
+		u, utxoStore, parent := setup(t)
+
+		child := spendTx(t, parent, 0, 9_000)
+		grandChild := spendTx(t, child, 0, 8_000)
+
+		changeSet := NewUTXOChangeSet(blockHeight)
+
+		// the second subtree of the block spends an output created by the first one
+		require.NoError(t, validate(t, u, changeSet, child))
+		require.NoError(t, validate(t, u, changeSet, grandChild))
+		require.Equal(t, 2, changeSet.Len())
+
+		// nothing is written before the block is accepted
+		assertNotInStore(t, utxoStore, child, grandChild)
+		assert.Equal(t, utxo.Status_OK, spendStatus(t, utxoStore, parent, 0))
+
+		delta := changeSet.Delta()
+		assert.Equal(t, []Outpoint{
+			{TxHash: *parent.TxIDChainHash(), Index: 0},
+			{TxHash: *child.TxIDChainHash(), Index: 0},
+		}, delta.Spent)
+		assert.Equal(t, []Outpoint{
+			{TxHash: *child.TxIDChainHash(), Index: 0},
+			{TxHash: *grandChild.TxIDChainHash(), Index: 0},
+		}, delta.Created)
+
+		require.NoError(t, u.CommitUTXOChangeSet(t.Context(), changeSet))
+
+		assert.Equal(t, utxo.Status_SPENT, spendStatus(t, utxoStore, parent, 0))
+		assert.Equal(t, utxo.Status_SPENT, spendStatus(t, utxoStore, child, 0))
+		assert.Equal(t, utxo.Status_OK, spendStatus(t, utxoStore, grandChild, 0))
+
+		// a committed change set can not be committed again, nor rolled back
+		require.Error(t, u.CommitUTXOChangeSet(t.Context(), changeSet))
+		require.Error(t, changeSet.Rollback())
+	})
+
+	t.Run("rejected block leaves the utxo set unchanged", func(t *testing.T) {
+		u, utxoStore, parent := setup(t)
+
+		child1 := spendTx(t, parent, 0, 9_000)
+		child2 := spendTx(t, parent, 1, 9_000)
+
+		changeSet := NewUTXOChangeSet(blockHeight)
+
+		require.NoError(t, validate(t, u, changeSet, child1, child2))
+		require.NoError(t, changeSet.Rollback())
+
+		assertNotInStore(t, utxoStore, child1, child2)
+		assert.Equal(t, utxo.Status_OK, spendStatus(t, utxoStore, parent, 0))
+		assert.Equal(t, utxo.Status_OK, spendStatus(t, utxoStore, parent, 1))
+
+		// a rolled back change set can not be used anymore
+		require.Error(t, validate(t, u, changeSet, child1))
+		require.Error(t, u.CommitUTXOChangeSet(t.Context(), changeSet))
+
+		assertNotInStore(t, utxoStore, child1, child2)
+	})
+
+	t.Run("invalid subtree leaves the change set unchanged", func(t *testing.T) {
+		u, utxoStore, parent := setup(t)
+
+		child := spendTx(t, parent, 0, 9_000)
+		doubleSpend := spendTx(t, parent, 0, 8_000)
+
+		changeSet := NewUTXOChangeSet(blockHeight)
+
+		require.NoError(t, validate(t, u, changeSet, child))
+
+		// the second subtree double spends an output spent by the first one
+		err := validate(t, u, changeSet, spendTx(t, parent, 1, 9_000), doubleSpend)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrSubtreeInvalid)
+
+		require.Equal(t, 1, changeSet.Len())
+		require.NoError(t, changeSet.Rollback())
+
+		assertNotInStore(t, utxoStore, child, doubleSpend)
+	})
+
+	t.Run("failed commit is reverted", func(t *testing.T) {
+		u, utxoStore, parent := setup(t)
+
+		child1 := spendTx(t, parent, 0, 9_000)
+		child2 := spendTx(t, parent, 1, 9_000)
+
+		changeSet := NewUTXOChangeSet(blockHeight)
+
+		require.NoError(t, validate(t, u, changeSet, child1, child2))
+
+		// the output spent by the second transaction is spent by another transaction before the commit
+		conflicting := spendTx(t, parent, 1, 7_000)
+		conflicting.Inputs[0].PreviousTxSatoshis = parent.Outputs[1].Satoshis
+		conflicting.Inputs[0].PreviousTxScript = parent.Outputs[1].LockingScript
+
+		_, err := utxoStore.Spend(t.Context(), conflicting, blockHeight)
+		require.NoError(t, err)
+
+		err = u.CommitUTXOChangeSet(t.Context(), changeSet)
+		require.Error(t, err)
+
+		// the first transaction was written and reverted
+		assertNotInStore(t, utxoStore, child1, child2)
+		assert.Equal(t, utxo.Status_OK, spendStatus(t, utxoStore, parent, 0))
+		assert.Equal(t, utxo.Status_SPENT, spendStatus(t, utxoStore, parent, 1))
+
+		// a change set is never committed twice
+		require.Error(t, u.CommitUTXOChangeSet(t.Context(), changeSet))
+	})
+}
+
+// storeSubtreeData stores the subtree data of a subtree of the transactions in the subtree store
+func storeSubtreeData(t *testing.T, subtreeStore blob.Store, txs []*bt.Tx) (chainhash.Hash, []chainhash.Hash) {
+	subtree, err := subtreepkg.NewIncompleteTreeByLeafCount(len(txs))
+	require.NoError(t, err)
+
+	txHashes := make([]chainhash.Hash, 0, len(txs))
+
+	for _, tx := range txs {
+		require.NoError(t, subtree.AddNode(*tx.TxIDChainHash(), 0, 0))
+		txHashes = append(txHashes, *tx.TxIDChainHash())
+	}
+
+	subtreeData := subtreepkg.NewSubtreeData(subtree)
+
+	for idx, tx := range txs {
+		require.NoError(t, subtreeData.AddTx(tx, idx))
+	}
+
+	subtreeDataBytes, err := subtreeData.Serialize()
+	require.NoError(t, err)
+
+	require.NoError(t, subtreeStore.Set(t.Context(), subtree.RootHash()[:], fileformat.FileTypeSubtreeData, subtreeDataBytes))
+
+	return *subtree.RootHash(), txHashes
+}
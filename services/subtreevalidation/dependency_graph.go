@@ -0,0 +1,202 @@
+package subtreevalidation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// dependencyGraphCancelCheckInterval is the number of transactions added to a dependency graph between checks
+// of the context for cancellation
+const dependencyGraphCancelCheckInterval = 4096
+
+// DependencyGraph is the parent/child adjacency of a set of transactions, e.g. the missing transactions of
+// a subtree. All slices are indexed by the position of the transaction in the set the graph was built from.
+//
+// Nil and coinbase transactions are not part of the graph. When a transaction occurs more than once in the
+// set, only its last occurrence is part of the graph. Transactions that are not part of the graph have no
+// parents, use Contains to check whether a transaction is part of the graph.
+type DependencyGraph struct {
+	// TxHashes holds the hash of every transaction, the zero hash for nil and coinbase transactions
+	TxHashes []chainhash.Hash
+
+	// Parents holds the indices of the parents of every transaction that are part of the graph themselves
+	Parents [][]int
+
+	// ExternalParents holds the txids of the parents of every transaction that are not part of the graph
+	ExternalParents [][]chainhash.Hash
+
+	// indexOf maps the hash of every transaction that is part of the graph to its index
+	indexOf map[chainhash.Hash]int
+
+	// parentStore and externalStore are the flat backing stores of Parents and ExternalParents
+	parentStore   []int
+	externalStore []chainhash.Hash
+}
+
+// BuildDependencyGraph builds the dependency graph of the given transactions, linking every transaction to
+// the parents it spends from. The graph is built in a single pass over the inputs of the transactions and
+// does not look up anything in the stores.
+func BuildDependencyGraph(ctx context.Context, transactions []missingTx) (*DependencyGraph, error) {
+	g := &DependencyGraph{}
+	g.prepare(len(transactions))
+
+	if err := g.build(ctx, transactions); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// prepare makes sure the graph can hold the given number of transactions without re-allocating.
+func (g *DependencyGraph) prepare(size int) {
+	if g.indexOf == nil {
+		g.indexOf = make(map[chainhash.Hash]int, size)
+	}
+
+	if cap(g.TxHashes) < size {
+		g.TxHashes = make([]chainhash.Hash, 0, size)
+		g.Parents = make([][]int, 0, size)
+		g.ExternalParents = make([][]chainhash.Hash, 0, size)
+	}
+}
+
+// reset clears the graph, keeping the allocated capacity for re-use.
+func (g *DependencyGraph) reset() {
+	clear(g.Parents)
+	clear(g.ExternalParents)
+	clear(g.indexOf)
+
+	g.TxHashes = g.TxHashes[:0]
+	g.Parents = g.Parents[:0]
+	g.ExternalParents = g.ExternalParents[:0]
+	g.parentStore = g.parentStore[:0]
+	g.externalStore = g.externalStore[:0]
+}
+
+// build adds the given transactions to the empty graph.
+func (g *DependencyGraph) build(ctx context.Context, transactions []missingTx) error {
+	for idx, mTx := range transactions {
+		var txHash chainhash.Hash
+
+		if mTx.tx != nil && !mTx.tx.IsCoinbase() {
+			txHash = *mTx.tx.TxIDChainHash()
+			g.indexOf[txHash] = idx
+		}
+
+		g.TxHashes = append(g.TxHashes, txHash)
+	}
+
+	for idx, mTx := range transactions {
+		if idx%dependencyGraphCancelCheckInterval == 0 && ctx.Err() != nil {
+			return errors.NewContextCanceledError("[BuildDependencyGraph] cancelled after %d of %d transactions", idx, len(transactions), ctx.Err())
+		}
+
+		if !g.Contains(idx) {
+			g.Parents = append(g.Parents, nil)
+			g.ExternalParents = append(g.ExternalParents, nil)
+
+			continue
+		}
+
+		parentsStart := len(g.parentStore)
+		externalStart := len(g.externalStore)
+
+		for _, input := range mTx.tx.Inputs {
+			parentHash := *input.PreviousTxIDChainHash()
+
+			if parentIdx, exists := g.indexOf[parentHash]; exists {
+				if !slices.Contains(g.parentStore[parentsStart:], parentIdx) {
+					g.parentStore = append(g.parentStore, parentIdx)
+				}
+			} else if !parentHash.Equal(chainhash.Hash{}) {
+				if !slices.Contains(g.externalStore[externalStart:], parentHash) {
+					g.externalStore = append(g.externalStore, parentHash)
+				}
+			}
+		}
+
+		// use full slice expressions, so appending to the backing stores never overwrites the parents of this tx
+		g.Parents = append(g.Parents, g.parentStore[parentsStart:len(g.parentStore):len(g.parentStore)])
+		g.ExternalParents = append(g.ExternalParents, g.externalStore[externalStart:len(g.externalStore):len(g.externalStore)])
+	}
+
+	return nil
+}
+
+// Len returns the number of transactions the graph was built from, including those not part of the graph.
+func (g *DependencyGraph) Len() int {
+	return len(g.TxHashes)
+}
+
+// Contains returns whether the transaction at the given index is part of the graph.
+func (g *DependencyGraph) Contains(idx int) bool {
+	if idx < 0 || idx >= len(g.TxHashes) {
+		return false
+	}
+
+	graphIdx, exists := g.indexOf[g.TxHashes[idx]]
+
+	return exists && graphIdx == idx
+}
+
+// externalParentSet returns the distinct external parents of all transactions in the graph.
+func (g *DependencyGraph) externalParentSet() map[chainhash.Hash]struct{} {
+	externalParents := make(map[chainhash.Hash]struct{})
+
+	for _, parents := range g.ExternalParents {
+		for _, parentHash := range parents {
+			externalParents[parentHash] = struct{}{}
+		}
+	}
+
+	return externalParents
+}
+
+// WriteDOT writes the graph in the DOT language of graphviz. Every transaction is a node with an edge to each
+// of its children; the external parents are drawn as dashed nodes.
+func (g *DependencyGraph) WriteDOT(w io.Writer) error {
+	if _, err := io.WriteString(w, "digraph dependencies {\n"); err != nil {
+		return err
+	}
+
+	externalWritten := make(map[chainhash.Hash]struct{})
+
+	for idx, txHash := range g.TxHashes {
+		if !g.Contains(idx) {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "  %q [label=\"%d\\n%s\"];\n", txHash.String(), idx, txHash.String()); err != nil {
+			return err
+		}
+
+		for _, parentIdx := range g.Parents[idx] {
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", g.TxHashes[parentIdx].String(), txHash.String()); err != nil {
+				return err
+			}
+		}
+
+		for _, parentHash := range g.ExternalParents[idx] {
+			if _, written := externalWritten[parentHash]; !written {
+				externalWritten[parentHash] = struct{}{}
+
+				if _, err := fmt.Fprintf(w, "  %q [style=dashed];\n", parentHash.String()); err != nil {
+					return err
+				}
+			}
+
+			if _, err := fmt.Fprintf(w, "  %q -> %q [style=dashed];\n", parentHash.String(), txHash.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, "}\n")
+
+	return err
+}
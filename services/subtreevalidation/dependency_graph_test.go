@@ -0,0 +1,136 @@
+package subtreevalidation
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDependencyGraph(t *testing.T) {
+	externalA := chainhash.HashH([]byte("external a"))
+	externalB := chainhash.HashH([]byte("external b"))
+
+	root := newDependencyGraphTestTx(t, 2, bt.UTXO{TxIDHash: &externalA, Vout: 0}, bt.UTXO{TxIDHash: &externalA, Vout: 1})
+	child := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: root.TxIDChainHash(), Vout: 0}, bt.UTXO{TxIDHash: root.TxIDChainHash(), Vout: 1})
+	grandchild := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: child.TxIDChainHash(), Vout: 0}, bt.UTXO{TxIDHash: &externalB, Vout: 0})
+
+	coinbase := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &chainhash.Hash{}, Vout: 0xffffffff})
+	require.True(t, coinbase.IsCoinbase())
+
+	transactions := []missingTx{
+		{tx: grandchild, idx: 10},
+		{tx: root, idx: 11},
+		{tx: child, idx: 12},
+		{tx: nil, idx: 13},
+		{tx: coinbase, idx: 14},
+		{tx: child, idx: 15}, // duplicate of the child, only the last occurrence is part of the graph
+	}
+
+	graph, err := BuildDependencyGraph(context.Background(), transactions)
+	require.NoError(t, err)
+	require.Equal(t, len(transactions), graph.Len())
+
+	assert.True(t, graph.Contains(0))
+	assert.True(t, graph.Contains(1))
+	assert.False(t, graph.Contains(2), "earlier duplicate")
+	assert.False(t, graph.Contains(3), "nil transaction")
+	assert.False(t, graph.Contains(4), "coinbase")
+	assert.True(t, graph.Contains(5))
+	assert.False(t, graph.Contains(-1))
+	assert.False(t, graph.Contains(6))
+
+	assert.Equal(t, *grandchild.TxIDChainHash(), graph.TxHashes[0])
+	assert.Equal(t, chainhash.Hash{}, graph.TxHashes[3])
+
+	// grandchild spends the child, which is part of the graph at its last occurrence, and external b
+	assert.Equal(t, []int{5}, graph.Parents[0])
+	assert.Equal(t, []chainhash.Hash{externalB}, graph.ExternalParents[0])
+
+	// root spends two outputs of external a, which is only listed once
+	assert.Empty(t, graph.Parents[1])
+	assert.Equal(t, []chainhash.Hash{externalA}, graph.ExternalParents[1])
+
+	// child spends two outputs of root, which is only listed once
+	assert.Equal(t, []int{1}, graph.Parents[5])
+	assert.Empty(t, graph.ExternalParents[5])
+
+	for _, idx := range []int{2, 3, 4} {
+		assert.Nil(t, graph.Parents[idx])
+		assert.Nil(t, graph.ExternalParents[idx])
+	}
+
+	t.Run("dot", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, graph.WriteDOT(&buf))
+
+		dot := buf.String()
+		assert.Contains(t, dot, "digraph dependencies {")
+		assert.Contains(t, dot, "\""+root.TxID()+"\" -> \""+child.TxID()+"\";")
+		assert.Contains(t, dot, "\""+child.TxID()+"\" -> \""+grandchild.TxID()+"\";")
+		assert.Contains(t, dot, "\""+externalB.String()+"\" -> \""+grandchild.TxID()+"\" [style=dashed];")
+		assert.NotContains(t, dot, coinbase.TxID())
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := BuildDependencyGraph(ctx, transactions)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrContextCanceled))
+	})
+}
+
+func TestBuildDependencyGraph_Subtree(t *testing.T) {
+	transactions := loadSubtreeTestTransactions(t)
+
+	graph, err := BuildDependencyGraph(context.Background(), transactions)
+	require.NoError(t, err)
+
+	for idx, mTx := range transactions {
+		if !graph.Contains(idx) {
+			continue
+		}
+
+		inputs := make(map[chainhash.Hash]struct{}, len(mTx.tx.Inputs))
+		for _, input := range mTx.tx.Inputs {
+			if parentHash := *input.PreviousTxIDChainHash(); !parentHash.Equal(chainhash.Hash{}) {
+				inputs[parentHash] = struct{}{}
+			}
+		}
+
+		// every input is either a parent in the graph or an external parent
+		assert.Len(t, inputs, len(graph.Parents[idx])+len(graph.ExternalParents[idx]))
+
+		for _, parentIdx := range graph.Parents[idx] {
+			assert.True(t, graph.Contains(parentIdx))
+			assert.Contains(t, inputs, graph.TxHashes[parentIdx])
+		}
+
+		for _, parentHash := range graph.ExternalParents[idx] {
+			assert.Contains(t, inputs, parentHash)
+		}
+	}
+}
+
+// newDependencyGraphTestTx creates a transaction spending the given outputs, with the given number of outputs
+func newDependencyGraphTestTx(t *testing.T, outputs int, spends ...bt.UTXO) *bt.Tx {
+	tx := bt.NewTx()
+
+	for _, spend := range spends {
+		require.NoError(t, tx.From(spend.TxIDHash.String(), spend.Vout, "51", 1000))
+	}
+
+	for i := 0; i < outputs; i++ {
+		tx.AddOutput(&bt.Output{Satoshis: 100, LockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE})})
+	}
+
+	return tx
+}
@@ -0,0 +1,357 @@
+package subtreevalidation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/ordishs/gocore"
+)
+
+// Error categories of the transactions failing a dry run of a subtree validation
+const (
+	// DryRunErrorTxNotFound is an input spending a transaction, or an output, that is not known
+	DryRunErrorTxNotFound = "TX_NOT_FOUND"
+
+	// DryRunErrorDoubleSpend is an input spending an output that is already spent, in the UTXO store or by an
+	// earlier transaction of the subtree
+	DryRunErrorDoubleSpend = "DOUBLE_SPEND"
+
+	// DryRunErrorScript is an input failing script verification
+	DryRunErrorScript = "SCRIPT"
+
+	// DryRunErrorTxInvalid is a transaction breaking a consensus rule other than the scripts
+	DryRunErrorTxInvalid = "TX_INVALID"
+
+	// DryRunErrorProcessing is a transaction that could not be checked, e.g. because of a UTXO store error
+	DryRunErrorProcessing = "PROCESSING"
+)
+
+// DryRunTxResult is a transaction failing a dry run of a subtree validation
+type DryRunTxResult struct {
+	// Index is the index of the transaction in the subtree
+	Index int
+
+	// TxHash is the hash of the transaction
+	TxHash chainhash.Hash
+
+	// InputIndex is the index of the failing input, -1 when the failure is not specific to one input
+	InputIndex int
+
+	// Category is the error category, one of the DryRunError constants
+	Category string
+
+	// Error is the reason the transaction fails
+	Error string
+}
+
+// DryRunResult is the result of a dry run of a subtree validation
+type DryRunResult struct {
+	// SubtreeHash is the hash of the validated subtree
+	SubtreeHash chainhash.Hash
+
+	// TxCount is the number of transactions in the subtree, including the coinbase placeholder
+	TxCount int
+
+	// ValidatedCount is the number of transactions validated in the dry run, the other transactions are
+	// already in the UTXO store
+	ValidatedCount int
+
+	// Failed holds every failing transaction, in subtree order
+	Failed []DryRunTxResult
+}
+
+// Valid returns whether all transactions of the subtree passed the dry run.
+func (r *DryRunResult) Valid() bool {
+	return len(r.Failed) == 0
+}
+
+// dryRunOutpoint identifies an output spent in a dry run
+type dryRunOutpoint struct {
+	hash chainhash.Hash
+	vout uint32
+}
+
+// dryRunReader reads the parents of the transactions of a dry run and the state of the outputs they spend, the UTXO
+// store, or a cache in front of it shared by the subtrees of a SubtreeValidationBatch.
+type dryRunReader interface {
+	Get(ctx context.Context, hash *chainhash.Hash, fields ...fields.FieldName) (*meta.Data, error)
+	GetSpend(ctx context.Context, spend *utxo.Spend) (*utxo.SpendResponse, error)
+}
+
+// DryRunSubtree validates the transactions of a subtree without any side effects on the UTXO store or block
+// assembly, returning every failing transaction instead of stopping at the first failure. Like a regular subtree
+// validation, only the consensus rules are checked.
+//
+// The transactions of the subtree that are already in the UTXO store are not validated again. All other
+// transactions are validated in subtree order against the UTXO store, overlaid with the outputs created and spent
+// by the transactions of the subtree that passed, so chained transactions and double spends within the subtree are
+// detected. The transactions are read from the subtree data in the subtree store when available, otherwise they
+// are requested from the peer at the base URL of the subtree.
+//
+// When the UTXO delta is requested, it receives the outpoints the transactions that passed would have spent and
+// created in the UTXO store, even when other transactions of the subtree failed.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - v: The subtree to validate, AllowFailFast is ignored
+//   - blockHeight: Height of the block the transactions are validated for
+//
+// Returns:
+//   - *DryRunResult: The failing transactions of the subtree
+//   - error: An error when the subtree or its transactions could not be retrieved
+func (u *Server) DryRunSubtree(ctx context.Context, v ValidateSubtree, blockHeight uint32) (*DryRunResult, error) {
+	ctx, _, endSpan := tracing.Tracer("subtreevalidation").Start(ctx, "DryRunSubtree",
+		tracing.WithLogMessage(u.logger, "[DryRunSubtree][%s] called", v.SubtreeHash.String()),
+	)
+	defer endSpan()
+
+	v.UTXODelta.reset()
+
+	txHashes := v.TxHashes
+	if txHashes == nil {
+		var err error
+
+		if txHashes, err = u.getSubtreeTxHashes(ctx, gocore.NewStat("DryRunSubtree"), &v.SubtreeHash, v.BaseURL); err != nil {
+			return nil, errors.NewServiceError("[DryRunSubtree][%s] failed to get subtree", v.SubtreeHash.String(), err)
+		}
+	}
+
+	result := &DryRunResult{
+		SubtreeHash: v.SubtreeHash,
+		TxCount:     len(txHashes),
+		Failed:      make([]DryRunTxResult, 0),
+	}
+
+	txs, err := u.getDryRunTransactions(ctx, v, txHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ValidatedCount = len(txs)
+
+	blockState := u.utxoStore.GetBlockState()
+	tv := validator.NewTxValidator(u.logger, u.settings)
+
+	created := make(map[chainhash.Hash]*bt.Tx, len(txs))
+	failed := make(map[chainhash.Hash]int)
+	spent := make(map[dryRunOutpoint]int)
+
+	var passedTxs []*bt.Tx
+	if v.UTXODelta != nil {
+		passedTxs = make([]*bt.Tx, 0, len(txs))
+	}
+
+	for _, mTx := range txs {
+		txHash := *mTx.tx.TxIDChainHash()
+
+		failure := u.dryRunTransaction(ctx, u.utxoStore, tv, mTx, blockHeight, blockState.MedianTime, created, failed, spent)
+		if failure != nil {
+			failed[txHash] = mTx.idx
+			result.Failed = append(result.Failed, *failure)
+
+			continue
+		}
+
+		created[txHash] = mTx.tx
+
+		if v.UTXODelta != nil {
+			passedTxs = append(passedTxs, mTx.tx)
+		}
+
+		for _, input := range mTx.tx.Inputs {
+			spent[dryRunOutpoint{hash: *input.PreviousTxIDChainHash(), vout: input.PreviousTxOutIndex}] = mTx.idx
+		}
+	}
+
+	v.UTXODelta.add(passedTxs, blockHeight)
+
+	u.logger.Infof("[DryRunSubtree][%s] validated %d of %d transactions, %d failed", v.SubtreeHash.String(), result.ValidatedCount, result.TxCount, len(result.Failed))
+
+	return result, nil
+}
+
+// getDryRunTransactions returns the transactions of the subtree that are not in the UTXO store, in subtree order.
+func (u *Server) getDryRunTransactions(ctx context.Context, v ValidateSubtree, txHashes []chainhash.Hash) ([]missingTx, error) {
+	unresolved := make([]*utxo.UnresolvedMetaData, 0, len(txHashes))
+
+	for idx, txHash := range txHashes {
+		if txHash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
+			continue
+		}
+
+		unresolved = append(unresolved, &utxo.UnresolvedMetaData{Hash: txHash, Idx: idx})
+	}
+
+	if err := u.resolver().BatchDecorate(ctx, unresolved, fields.BlockIDs); err != nil {
+		return nil, errors.NewProcessingError("[DryRunSubtree][%s] failed to look up the subtree transactions", v.SubtreeHash.String(), err)
+	}
+
+	missingTxHashes := make([]utxo.UnresolvedMetaData, 0)
+
+	for _, item := range unresolved {
+		if item.Err == nil {
+			continue
+		}
+
+		if !errors.Is(item.Err, errors.ErrTxNotFound) {
+			return nil, errors.NewProcessingError("[DryRunSubtree][%s] failed to look up transaction %s", v.SubtreeHash.String(), item.Hash.String(), item.Err)
+		}
+
+		missingTxHashes = append(missingTxHashes, utxo.UnresolvedMetaData{Hash: item.Hash, Idx: item.Idx})
+	}
+
+	if len(missingTxHashes) == 0 {
+		return nil, nil
+	}
+
+	// never store the subtree data retrieved from the peer, unlike a regular validation
+	subtreeDataExists, err := u.subtreeStore.Exists(ctx, v.SubtreeHash[:], fileformat.FileTypeSubtreeData)
+	if err != nil {
+		return nil, errors.NewStorageError("[DryRunSubtree][%s] failed to check if subtree data exists", v.SubtreeHash.String(), err)
+	}
+
+	var txs []missingTx
+
+	if subtreeDataExists {
+		txs, err = u.getMissingTransactionsFromFile(ctx, v.SubtreeHash, missingTxHashes, txHashes)
+	}
+
+	if !subtreeDataExists || err != nil {
+		if txs, err = u.getMissingTransactionsFromPeer(ctx, v.SubtreeHash, missingTxHashes, v.BaseURL); err != nil {
+			return nil, errors.NewServiceError("[DryRunSubtree][%s] failed to get the subtree transactions", v.SubtreeHash.String(), err)
+		}
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].idx < txs[j].idx
+	})
+
+	return txs, nil
+}
+
+// dryRunTransaction validates a single transaction of a dry run, returning the failure or nil when it is valid.
+//
+// The outputs spent by the transaction are taken from the transactions of the subtree that passed, created, or
+// from the UTXO store through the reader. An output spent by an earlier transaction of the subtree, spent, is a
+// double spend.
+func (u *Server) dryRunTransaction(ctx context.Context, reader dryRunReader, tv *validator.TxValidator, mTx missingTx, blockHeight uint32, medianTime uint32,
+	created map[chainhash.Hash]*bt.Tx, failed map[chainhash.Hash]int, spent map[dryRunOutpoint]int) *DryRunTxResult {
+	tx := mTx.tx
+
+	fail := func(inputIdx int, category string, err error) *DryRunTxResult {
+		return &DryRunTxResult{
+			Index:      mTx.idx,
+			TxHash:     *tx.TxIDChainHash(),
+			InputIndex: inputIdx,
+			Category:   category,
+			Error:      err.Error(),
+		}
+	}
+
+	utxoHeights := make([]uint32, len(tx.Inputs))
+	extend := !tx.IsExtended()
+
+	for idx, input := range tx.Inputs {
+		parentHash := *input.PreviousTxIDChainHash()
+
+		if spentBy, ok := spent[dryRunOutpoint{hash: parentHash, vout: input.PreviousTxOutIndex}]; ok {
+			return fail(idx, DryRunErrorDoubleSpend, errors.NewTxConflictingError("output %s:%d is already spent by the transaction at index %d", parentHash.String(), input.PreviousTxOutIndex, spentBy))
+		}
+
+		parent, inSubtree := created[parentHash]
+		if inSubtree {
+			// not mined yet, like the transaction itself
+			utxoHeights[idx] = blockHeight
+		} else {
+			if parentIdx, ok := failed[parentHash]; ok {
+				return fail(idx, DryRunErrorTxNotFound, errors.NewTxMissingParentError("parent transaction %s at index %d failed validation", parentHash.String(), parentIdx))
+			}
+
+			txMeta, err := reader.Get(ctx, &parentHash, fields.Tx, fields.BlockHeights)
+			if err != nil {
+				if errors.Is(err, errors.ErrTxNotFound) {
+					return fail(idx, DryRunErrorTxNotFound, errors.NewTxMissingParentError("parent transaction %s not found", parentHash.String(), err))
+				}
+
+				return fail(idx, DryRunErrorProcessing, errors.NewProcessingError("failed to get parent transaction %s", parentHash.String(), err))
+			}
+
+			parent = txMeta.Tx
+
+			if len(txMeta.BlockHeights) > 0 {
+				utxoHeights[idx] = txMeta.BlockHeights[0]
+			} else {
+				utxoHeights[idx] = blockHeight
+			}
+		}
+
+		if parent == nil || int(input.PreviousTxOutIndex) >= len(parent.Outputs) || parent.Outputs[input.PreviousTxOutIndex] == nil {
+			return fail(idx, DryRunErrorTxNotFound, errors.NewTxInvalidError("output %s:%d does not exist", parentHash.String(), input.PreviousTxOutIndex))
+		}
+
+		if extend {
+			input.PreviousTxSatoshis = parent.Outputs[input.PreviousTxOutIndex].Satoshis
+			input.PreviousTxScript = parent.Outputs[input.PreviousTxOutIndex].LockingScript
+		}
+
+		if !inSubtree {
+			if failure := dryRunCheckUnspent(ctx, reader, input, idx, fail); failure != nil {
+				return failure
+			}
+		}
+	}
+
+	if extend {
+		tx.SetExtended(true)
+	}
+
+	stage, inputIdx, err := tv.DiagnoseConsensus(tx, blockHeight, medianTime, utxoHeights)
+	if err != nil {
+		if stage == validator.DiagnosticStageScript {
+			return fail(inputIdx, DryRunErrorScript, err)
+		}
+
+		return fail(-1, DryRunErrorTxInvalid, err)
+	}
+
+	return nil
+}
+
+// dryRunCheckUnspent checks that the output spent by the input is unspent in the UTXO store, read through the reader.
+func dryRunCheckUnspent(ctx context.Context, reader dryRunReader, input *bt.Input, idx int, fail func(int, string, error) *DryRunTxResult) *DryRunTxResult {
+	utxoHash, err := util.UTXOHashFromInput(input)
+	if err != nil {
+		return fail(idx, DryRunErrorProcessing, errors.NewProcessingError("failed to calculate utxo hash", err))
+	}
+
+	spend, err := reader.GetSpend(ctx, &utxo.Spend{
+		TxID:     input.PreviousTxIDChainHash(),
+		Vout:     input.PreviousTxOutIndex,
+		UTXOHash: utxoHash,
+	})
+	if err != nil {
+		return fail(idx, DryRunErrorProcessing, errors.NewProcessingError("failed to get the state of the spent output", err))
+	}
+
+	switch utxo.Status(spend.Status) {
+	case utxo.Status_OK:
+		return nil
+	case utxo.Status_SPENT:
+		return fail(idx, DryRunErrorDoubleSpend, errors.NewTxConflictingError("output %s:%d is already spent", input.PreviousTxIDChainHash().String(), input.PreviousTxOutIndex))
+	case utxo.Status_NOT_FOUND:
+		return fail(idx, DryRunErrorTxNotFound, errors.NewTxNotFoundError("output %s:%d not found", input.PreviousTxIDChainHash().String(), input.PreviousTxOutIndex))
+	default:
+		return fail(idx, DryRunErrorTxInvalid, errors.NewUtxoError("output %s:%d has status %s", input.PreviousTxIDChainHash().String(), input.PreviousTxOutIndex, utxo.Status(spend.Status).String()))
+	}
+}
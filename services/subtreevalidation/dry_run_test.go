@@ -0,0 +1,133 @@
+package subtreevalidation
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	blobmemory "github.com/bsv-blockchain/teranode/stores/blob/memory"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/stores/utxo/tests"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunSubtree(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+
+	utxoStoreURL, err := url.Parse("sqlitememory:///test")
+	require.NoError(t, err)
+
+	utxoStore, err := sql.New(t.Context(), ulogger.TestLogger{}, tSettings, utxoStoreURL)
+	require.NoError(t, err)
+
+	require.NoError(t, utxoStore.SetBlockHeight(122))
+
+	_, err = utxoStore.Create(t.Context(), tests.ParentTx, 122)
+	require.NoError(t, err)
+
+	newTx := func(parentHash *chainhash.Hash, vout uint32, satoshis uint64) *bt.Tx {
+		tx := bt.NewTx()
+
+		input := &bt.Input{PreviousTxOutIndex: vout, UnlockingScript: &bscript.Script{}, SequenceNumber: bt.DefaultSequenceNumber}
+		require.NoError(t, input.PreviousTxIDAdd(parentHash))
+
+		tx.Inputs = append(tx.Inputs, input)
+		tx.AddOutput(&bt.Output{Satoshis: satoshis, LockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE})})
+
+		return tx
+	}
+
+	// a different transaction spending the same output as the valid test transaction
+	doubleSpend := tests.Tx.Clone()
+	doubleSpend.Outputs[0].Satoshis--
+
+	unknownParent := chainhash.HashH([]byte("unknown parent"))
+
+	txs := []*bt.Tx{
+		tests.ParentTx,                           // already in the utxo store
+		tests.Tx,                                 // valid
+		doubleSpend,                              // spends the same output as the previous transaction
+		newTx(&unknownParent, 0, 1000),           // spends an unknown parent
+		newTx(tests.Tx.TxIDChainHash(), 0, 1000), // spends an output of the subtree without a valid signature
+	}
+
+	subtree, err := subtreepkg.NewIncompleteTreeByLeafCount(len(txs))
+	require.NoError(t, err)
+
+	txHashes := make([]chainhash.Hash, 0, len(txs))
+
+	for _, tx := range txs {
+		require.NoError(t, subtree.AddNode(*tx.TxIDChainHash(), 0, 0))
+		txHashes = append(txHashes, *tx.TxIDChainHash())
+	}
+
+	subtreeData := subtreepkg.NewSubtreeData(subtree)
+
+	for idx, tx := range txs {
+		require.NoError(t, subtreeData.AddTx(tx, idx))
+	}
+
+	subtreeDataBytes, err := subtreeData.Serialize()
+	require.NoError(t, err)
+
+	subtreeStore := blobmemory.New()
+	require.NoError(t, subtreeStore.Set(t.Context(), subtree.RootHash()[:], fileformat.FileTypeSubtreeData, subtreeDataBytes))
+
+	u := &Server{
+		logger:       ulogger.TestLogger{},
+		settings:     tSettings,
+		utxoStore:    utxoStore,
+		subtreeStore: subtreeStore,
+	}
+
+	delta := &UTXODelta{}
+
+	result, err := u.DryRunSubtree(t.Context(), ValidateSubtree{
+		SubtreeHash: *subtree.RootHash(),
+		TxHashes:    txHashes,
+		UTXODelta:   delta,
+	}, 123)
+	require.NoError(t, err)
+
+	assert.False(t, result.Valid())
+	assert.Equal(t, 5, result.TxCount)
+	assert.Equal(t, 4, result.ValidatedCount)
+
+	require.Len(t, result.Failed, 3)
+
+	for i, expected := range []struct {
+		index    int
+		category string
+	}{
+		{index: 2, category: DryRunErrorDoubleSpend},
+		{index: 3, category: DryRunErrorTxNotFound},
+		{index: 4, category: DryRunErrorScript},
+	} {
+		failed := result.Failed[i]
+
+		assert.Equal(t, expected.index, failed.Index)
+		assert.Equal(t, txHashes[expected.index], failed.TxHash)
+		assert.Equal(t, 0, failed.InputIndex)
+		assert.Equal(t, expected.category, failed.Category, failed.Error)
+		assert.NotEmpty(t, failed.Error)
+	}
+
+	// only the valid transaction would have been written to the utxo store
+	expectedDelta := &UTXODelta{}
+	expectedDelta.add([]*bt.Tx{tests.Tx}, 123)
+
+	require.NotEmpty(t, expectedDelta.Spent)
+	assert.Equal(t, expectedDelta, delta)
+
+	// nothing was written to the utxo store
+	_, err = utxoStore.Get(t.Context(), tests.Tx.TxIDChainHash())
+	assert.True(t, errors.Is(err, errors.ErrTxNotFound))
+}
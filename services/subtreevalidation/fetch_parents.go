@@ -0,0 +1,147 @@
+package subtreevalidation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/util"
+	"golang.org/x/sync/errgroup"
+)
+
+// ParentFetcher fetches transactions from the network, e.g. from the peers of the propagation or legacy service.
+// It is used to acquire the parents outside a subtree that are not in the UTXO store, before the transactions
+// of the subtree are validated.
+type ParentFetcher interface {
+	// FetchTransaction returns the transaction with the given hash, or an error when it cannot be obtained.
+	FetchTransaction(ctx context.Context, txHash chainhash.Hash) (*bt.Tx, error)
+}
+
+// SetParentFetcher sets the fetcher of the parents outside a subtree missing from the UTXO store, nil disables
+// fetching the missing parents, the transactions spending them are then held back and fail on their missing parent.
+func (u *Server) SetParentFetcher(fetcher ParentFetcher) {
+	u.parentFetcher = fetcher
+}
+
+// fetchMissingParents fetches the external parents the precheck found missing with the parent fetcher, and
+// validates them into the UTXO store, so the transactions spending them can be levelled and validated as usual.
+// Parents depending on other missing parents are stored once those are, so the fetching is repeated up to
+// FetchMissingParentsMaxAttempts times while parents are still missing and the previous attempt stored any.
+// The parents that cannot be obtained stay missing in the returned precheck; nothing is fetched when no parent
+// fetcher is set.
+func (u *Server) fetchMissingParents(ctx context.Context, precheck *parentPrecheck) *parentPrecheck {
+	if u.parentFetcher == nil || u.settings == nil || len(precheck.missingParents) == 0 {
+		return precheck
+	}
+
+	maxAttempts := u.settings.SubtreeValidation.FetchMissingParentsMaxAttempts
+
+	for attempt := 1; attempt <= maxAttempts && len(precheck.missingParents) > 0; attempt++ {
+		stored, err := u.fetchAndStoreParents(ctx, precheck.missingParents)
+		if err != nil {
+			u.logger.Warnf("[fetchMissingParents] attempt #%d: %v", attempt, err)
+			break
+		}
+
+		if len(stored) == 0 {
+			break
+		}
+
+		for _, parentHash := range stored {
+			delete(precheck.missingParents, parentHash)
+		}
+
+		u.logger.Debugf("[fetchMissingParents] attempt #%d: stored %d missing parents, %d still missing", attempt, len(stored), len(precheck.missingParents))
+	}
+
+	missingOutpoints := precheck.missingOutpoints[:0]
+
+	for _, outpoint := range precheck.missingOutpoints {
+		if _, missing := precheck.missingParents[outpoint.hash]; missing {
+			missingOutpoints = append(missingOutpoints, outpoint)
+		}
+	}
+
+	precheck.missingOutpoints = missingOutpoints
+
+	return precheck
+}
+
+// fetchAndStoreParents fetches the missing parents concurrently, each within FetchMissingParentsTimeout, and
+// validates the fetched parents into the UTXO store. It returns the hashes of the parents that were stored.
+func (u *Server) fetchAndStoreParents(ctx context.Context, missingParents map[chainhash.Hash]struct{}) ([]chainhash.Hash, error) {
+	validatorOptions, err := u.levelValidatorOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHeight := uint32(0)
+	if u.utxoStore != nil {
+		blockHeight = u.utxoStore.GetBlockHeight() + 1
+	}
+
+	var (
+		mu     sync.Mutex
+		stored = make([]chainhash.Hash, 0, len(missingParents))
+	)
+
+	g, gCtx := errgroup.WithContext(ctx)
+	util.SafeSetLimit(g, u.levelValidationConcurrency())
+
+	for parentHash := range missingParents {
+		g.Go(func() error {
+			if err := u.fetchAndStoreParent(gCtx, parentHash, blockHeight, validatorOptions); err != nil {
+				prometheusSubtreeValidationFetchedParents.WithLabelValues("failed").Inc()
+				u.logger.Debugf("[fetchMissingParents] failed to fetch missing parent %s: %v", parentHash.String(), err)
+
+				return nil
+			}
+
+			prometheusSubtreeValidationFetchedParents.WithLabelValues("stored").Inc()
+
+			mu.Lock()
+			stored = append(stored, parentHash)
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return stored, nil
+}
+
+// fetchAndStoreParent fetches a single missing parent within FetchMissingParentsTimeout and validates it into
+// the UTXO store
+func (u *Server) fetchAndStoreParent(ctx context.Context, parentHash chainhash.Hash, blockHeight uint32, validatorOptions *validator.Options) error {
+	fetchCtx := ctx
+
+	if timeout := u.settings.SubtreeValidation.FetchMissingParentsTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+
+		fetchCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	tx, err := u.parentFetcher.FetchTransaction(fetchCtx, parentHash)
+	if err != nil {
+		return errors.NewServiceError("failed to fetch transaction after %s", time.Since(start), err)
+	}
+
+	if tx == nil || !tx.TxIDChainHash().IsEqual(&parentHash) {
+		return errors.NewProcessingError("fetched transaction does not match the requested transaction")
+	}
+
+	if _, err = u.blessMissingTransaction(ctx, chainhash.Hash{}, tx, blockHeight, nil, validatorOptions); err != nil && !errors.Is(err, errors.ErrTxExists) {
+		return err
+	}
+
+	return nil
+}
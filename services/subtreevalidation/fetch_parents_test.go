@@ -0,0 +1,152 @@
+package subtreevalidation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeParentFetcher returns the transactions it was given, and fails for all other transactions
+type fakeParentFetcher struct {
+	mu      sync.Mutex
+	txs     map[chainhash.Hash]*bt.Tx
+	fetched []chainhash.Hash
+	delay   time.Duration
+}
+
+func newFakeParentFetcher(txs ...*bt.Tx) *fakeParentFetcher {
+	f := &fakeParentFetcher{txs: make(map[chainhash.Hash]*bt.Tx)}
+
+	for _, tx := range txs {
+		f.txs[*tx.TxIDChainHash()] = tx
+	}
+
+	return f
+}
+
+func (f *fakeParentFetcher) FetchTransaction(ctx context.Context, txHash chainhash.Hash) (*bt.Tx, error) {
+	f.mu.Lock()
+	f.fetched = append(f.fetched, txHash)
+	f.mu.Unlock()
+
+	if f.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(f.delay):
+		}
+	}
+
+	if tx, ok := f.txs[txHash]; ok {
+		return tx, nil
+	}
+
+	return nil, errors.NewTxNotFoundError("transaction %s not found", txHash.String())
+}
+
+// storingValidator records the validated transactions
+type storingValidator struct {
+	validator.MockValidatorClient
+
+	mu        sync.Mutex
+	validated []chainhash.Hash
+}
+
+func (v *storingValidator) ValidateWithOptions(_ context.Context, tx *bt.Tx, _ uint32, _ *validator.Options) (*meta.Data, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.validated = append(v.validated, *tx.TxIDChainHash())
+
+	return &meta.Data{}, nil
+}
+
+func TestFetchMissingParents(t *testing.T) {
+	external := chainhash.HashH([]byte("external"))
+
+	parent := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external, Vout: 0})
+	child := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: parent.TxIDChainHash(), Vout: 0})
+	grandchild := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: child.TxIDChainHash(), Vout: 0})
+	other := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external, Vout: 1})
+
+	// the parent is not part of the subtree and not in the UTXO store, the external transaction is
+	transactions := []missingTx{
+		{tx: other, idx: 0},
+		{tx: child, idx: 1},
+		{tx: grandchild, idx: 2},
+	}
+
+	prepare := func(t *testing.T, fetcher ParentFetcher) (*storingValidator, uint32, [][]missingTx) {
+		server, cleanup := setupTestServer(t)
+		t.Cleanup(cleanup)
+
+		server.settings.SubtreeValidation.FetchMissingParentsMaxAttempts = 2
+		server.settings.SubtreeValidation.FetchMissingParentsTimeout = 50 * time.Millisecond
+
+		v := &storingValidator{}
+		server.validatorClient = v
+
+		server.SetUTXOResolver(newFakeUTXOResolver(external))
+		server.SetParentFetcher(fetcher)
+
+		maxLevel, txsPerLevel, err := server.prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+
+		return v, maxLevel, txsPerLevel
+	}
+
+	t.Run("fetched parents are stored before levelling", func(t *testing.T) {
+		fetcher := newFakeParentFetcher(parent)
+
+		v, maxLevel, txsPerLevel := prepare(t, fetcher)
+
+		assert.Equal(t, []chainhash.Hash{*parent.TxIDChainHash()}, fetcher.fetched)
+		assert.Equal(t, []chainhash.Hash{*parent.TxIDChainHash()}, v.validated)
+
+		// the child is not held back after the fetched parent was stored
+		assert.Equal(t, uint32(1), maxLevel)
+		require.Len(t, txsPerLevel[0], 2)
+		assert.Same(t, child, txsPerLevel[0][1].tx)
+	})
+
+	t.Run("without a fetcher the child is held back", func(t *testing.T) {
+		v, maxLevel, txsPerLevel := prepare(t, nil)
+
+		assert.Empty(t, v.validated)
+		assert.Equal(t, uint32(2), maxLevel)
+		require.Len(t, txsPerLevel[0], 1)
+		assert.Same(t, child, txsPerLevel[1][0].tx)
+	})
+
+	t.Run("parents that cannot be obtained are held back", func(t *testing.T) {
+		fetcher := newFakeParentFetcher()
+
+		v, maxLevel, _ := prepare(t, fetcher)
+
+		// nothing was stored in the first attempt, so the fetching is not repeated
+		assert.Len(t, fetcher.fetched, 1)
+		assert.Empty(t, v.validated)
+		assert.Equal(t, uint32(2), maxLevel)
+	})
+
+	t.Run("fetching a parent times out", func(t *testing.T) {
+		fetcher := newFakeParentFetcher(parent)
+		fetcher.delay = time.Second
+
+		start := time.Now()
+		v, maxLevel, _ := prepare(t, fetcher)
+
+		assert.Less(t, time.Since(start), fetcher.delay)
+		assert.Empty(t, v.validated)
+		assert.Equal(t, uint32(2), maxLevel)
+	})
+}
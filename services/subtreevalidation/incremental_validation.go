@@ -0,0 +1,232 @@
+package subtreevalidation
+
+import (
+	"context"
+	"io"
+	"slices"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+)
+
+// subtreeDependencies is the dependency graph of a whole subtree, recorded during a full validation of the subtree,
+// together with the context the subtree was validated in, so the subtree can be re-validated incrementally later
+type subtreeDependencies struct {
+	// children holds the indices of the children in the subtree of every transaction, by its index in the subtree
+	children [][]int
+
+	// indexOf maps the hash of every transaction of the subtree to its index
+	indexOf map[chainhash.Hash]int
+
+	blockHeight uint32
+	blockIds    map[uint32]bool
+	baseURL     string
+}
+
+// newSubtreeDependencies creates the dependency graph of the subtree with the given transaction hashes, without any
+// dependencies yet
+func newSubtreeDependencies(txHashes []chainhash.Hash, blockHeight uint32, blockIds map[uint32]bool, baseURL string) *subtreeDependencies {
+	d := &subtreeDependencies{
+		children:    make([][]int, len(txHashes)),
+		indexOf:     make(map[chainhash.Hash]int, len(txHashes)),
+		blockHeight: blockHeight,
+		blockIds:    blockIds,
+		baseURL:     baseURL,
+	}
+
+	for idx, txHash := range txHashes {
+		if !txHash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
+			d.indexOf[txHash] = idx
+		}
+	}
+
+	return d
+}
+
+// addTx links the transaction at the given index to its parents within the subtree
+func (d *subtreeDependencies) addTx(mTx missingTx) {
+	for _, input := range mTx.tx.Inputs {
+		parentIdx, ok := d.indexOf[*input.PreviousTxIDChainHash()]
+		if !ok || parentIdx >= mTx.idx {
+			continue
+		}
+
+		// a transaction spending several outputs of the same parent is a single child of it
+		if children := d.children[parentIdx]; len(children) == 0 || children[len(children)-1] != mTx.idx {
+			d.children[parentIdx] = append(children, mTx.idx)
+		}
+	}
+}
+
+// descendants returns the indices of the given transactions and all their descendants within the subtree, in
+// subtree order. Hashes of transactions that are not in the subtree are ignored.
+func (d *subtreeDependencies) descendants(txHashes []chainhash.Hash) []int {
+	affected := make(map[int]struct{}, len(txHashes))
+	queue := make([]int, 0, len(txHashes))
+
+	for _, txHash := range txHashes {
+		if idx, ok := d.indexOf[txHash]; ok {
+			if _, seen := affected[idx]; !seen {
+				affected[idx] = struct{}{}
+				queue = append(queue, idx)
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		idx := queue[0]
+		queue = queue[1:]
+
+		for _, childIdx := range d.children[idx] {
+			if _, seen := affected[childIdx]; !seen {
+				affected[childIdx] = struct{}{}
+				queue = append(queue, childIdx)
+			}
+		}
+	}
+
+	indices := make([]int, 0, len(affected))
+	for idx := range affected {
+		indices = append(indices, idx)
+	}
+
+	slices.Sort(indices)
+
+	return indices
+}
+
+// IncrementalValidate re-validates the given changed transactions of a subtree validated before, and all their
+// descendants within the subtree, instead of the whole subtree. This cuts the cost of re-validating a subtree when
+// only a few of its transactions change state, e.g. on a reorg.
+//
+// The previous result must come from ValidateSubtreeStreamResult, which records the dependency graph of the subtree,
+// or from an earlier IncrementalValidate. The transactions are read from the subtreeData of the subtree in the
+// subtree store, and re-validated with the block height, block IDs and base URL of the full validation. Changed
+// transactions that are not in the subtree are ignored.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - prevResult: The result of the previous validation of the subtree
+//   - changedTxids: The hashes of the transactions that changed state
+//
+// Returns:
+//   - *SubtreeValidationResult: A new result, with the outcome of the re-validated transactions replaced and the
+//     outcome of all other transactions carried over from the previous result
+//   - error: An error if the previous result has no dependency graph, or the subtreeData cannot be read
+func (u *Server) IncrementalValidate(ctx context.Context, prevResult *SubtreeValidationResult, changedTxids []chainhash.Hash) (*SubtreeValidationResult, error) {
+	if prevResult == nil || prevResult.dependencies == nil {
+		return nil, errors.NewInvalidArgumentError("[IncrementalValidate] previous result has no dependency graph, validate the subtree with ValidateSubtreeStreamResult first")
+	}
+
+	ctx, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "IncrementalValidate",
+		tracing.WithParentStat(u.stats),
+		tracing.WithDebugLogMessage(u.logger, "[IncrementalValidate] called for subtree %s with %d changed transactions", prevResult.SubtreeHash.String(), len(changedTxids)),
+	)
+	defer deferFn()
+
+	result := prevResult.clone()
+
+	affected := prevResult.dependencies.descendants(changedTxids)
+	if len(affected) == 0 {
+		return result, nil
+	}
+
+	for _, idx := range affected {
+		result.Transactions[idx].Status = TxStatusNotValidated
+		result.Transactions[idx].Err = nil
+	}
+
+	transactions, err := u.readAffectedTransactions(ctx, result, affected)
+	if err != nil {
+		return result, err
+	}
+
+	processedValidatorOptions, err := u.levelValidatorOptions(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	maxLevel, txsPerLevel, err := u.prepareTxsPerLevel(ctx, transactions)
+	if err != nil {
+		return result, errors.NewProcessingError("[IncrementalValidate] failed to prepare transactions per level", err)
+	}
+
+	defer u.releaseTxsPerLevel(txsPerLevel)
+
+	var (
+		dependencies = prevResult.dependencies
+		sizeBucket   = subtreeSizeBucket(len(result.Transactions))
+		results      = levelValidationResults{txResults: result}
+	)
+
+	err = u.validateLevels(ctx, maxLevel, txsPerLevel, sizeBucket, dependencies.blockHeight, dependencies.blockIds,
+		dependencies.baseURL, processedValidatorOptions, nil, &results)
+
+	// the failing transactions are reported in the result, not as an error
+	results.recordPrevoutCacheHits()
+
+	u.logger.Debugf("[IncrementalValidate] re-validated %d of %d transactions of subtree %s", len(transactions), len(result.Transactions), result.SubtreeHash.String())
+
+	return result, err
+}
+
+// readAffectedTransactions reads the transactions at the given indices, in subtree order, from the subtreeData of the
+// subtree of the result in the subtree store
+func (u *Server) readAffectedTransactions(ctx context.Context, result *SubtreeValidationResult, affected []int) ([]missingTx, error) {
+	subtree, err := subtreepkg.NewIncompleteTreeByLeafCount(len(result.Transactions))
+	if err != nil {
+		return nil, errors.NewProcessingError("[IncrementalValidate] failed to create subtree", err)
+	}
+
+	for _, txResult := range result.Transactions {
+		if txResult.TxHash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
+			if err = subtree.AddCoinbaseNode(); err != nil {
+				return nil, errors.NewProcessingError("[IncrementalValidate] failed to add coinbase placeholder node to subtree", err)
+			}
+
+			continue
+		}
+
+		if err = subtree.AddNode(txResult.TxHash, 0, 0); err != nil {
+			return nil, errors.NewProcessingError("[IncrementalValidate] failed to add node to subtree", err)
+		}
+	}
+
+	subtreeDataReader, err := u.subtreeStore.GetIoReader(ctx, result.SubtreeHash[:], fileformat.FileTypeSubtreeData)
+	if err != nil {
+		return nil, errors.NewStorageError("[IncrementalValidate] failed to get subtreeData of subtree %s from store", result.SubtreeHash.String(), err)
+	}
+	defer subtreeDataReader.Close()
+
+	var (
+		dataReader   = newSubtreeDataReader(subtree, subtreeDataReader)
+		transactions = make([]missingTx, 0, len(affected))
+		next         = 0
+	)
+
+	// the affected transactions are in subtree order, the stream is read until the last of them
+	for next < len(affected) {
+		tx, err := dataReader.next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.NewProcessingError("[IncrementalValidate] failed to read transactions of subtree %s", result.SubtreeHash.String(), err)
+		}
+
+		if idx := dataReader.txIndex - 1; idx == affected[next] {
+			transactions = append(transactions, missingTx{tx: tx, idx: idx})
+			next++
+		}
+	}
+
+	if next < len(affected) {
+		return nil, errors.NewProcessingError("[IncrementalValidate] subtreeData of subtree %s ends before transaction %d", result.SubtreeHash.String(), affected[next])
+	}
+
+	return transactions, nil
+}
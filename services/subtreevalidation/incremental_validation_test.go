@@ -0,0 +1,132 @@
+package subtreevalidation
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncrementalValidate(t *testing.T) {
+	external1 := chainhash.HashH([]byte("external 1"))
+	external2 := chainhash.HashH([]byte("external 2"))
+
+	txA := newDependencyGraphTestTx(t, 2, bt.UTXO{TxIDHash: &external1, Vout: 0})
+	txB := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: txA.TxIDChainHash(), Vout: 0})
+	txC := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: txB.TxIDChainHash(), Vout: 0})
+	txD := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external2, Vout: 0})
+	txE := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: txA.TxIDChainHash(), Vout: 1}, bt.UTXO{TxIDHash: txD.TxIDChainHash(), Vout: 0})
+
+	txs := []*bt.Tx{txA, txB, txC, txD, txE}
+
+	subtree, err := subtreepkg.NewTreeByLeafCount(8)
+	require.NoError(t, err)
+	require.NoError(t, subtree.AddCoinbaseNode())
+
+	var subtreeData bytes.Buffer
+
+	for i, tx := range txs {
+		require.NoError(t, subtree.AddNode(*tx.TxIDChainHash(), uint64(i), uint64(tx.Size()))) //nolint:gosec // G115: small test values
+		subtreeData.Write(tx.Bytes())
+	}
+
+	statuses := func(result *SubtreeValidationResult) []TxValidationStatus {
+		s := make([]TxValidationStatus, 0, len(result.Transactions))
+		for _, txResult := range result.Transactions {
+			s = append(s, txResult.Status)
+		}
+
+		return s
+	}
+
+	t.Run("descendants of the changed transactions", func(t *testing.T) {
+		txHashes := make([]chainhash.Hash, subtree.Length())
+		for idx, node := range subtree.Nodes {
+			txHashes[idx] = node.Hash
+		}
+
+		dependencies := newSubtreeDependencies(txHashes, 100, nil, "")
+		for idx, tx := range txs {
+			dependencies.addTx(missingTx{tx: tx, idx: idx + 1})
+		}
+
+		assert.Equal(t, []int{1, 2, 3, 5}, dependencies.descendants([]chainhash.Hash{*txA.TxIDChainHash()}))
+		assert.Equal(t, []int{2, 3}, dependencies.descendants([]chainhash.Hash{*txB.TxIDChainHash()}))
+		assert.Equal(t, []int{4, 5}, dependencies.descendants([]chainhash.Hash{*txD.TxIDChainHash(), *txE.TxIDChainHash()}))
+		assert.Empty(t, dependencies.descendants([]chainhash.Hash{external1, subtreepkg.CoinbasePlaceholderHashValue}))
+	})
+
+	t.Run("re-validates the changed transactions and their descendants", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		require.NoError(t, server.subtreeStore.Set(context.Background(), subtree.RootHash()[:], fileformat.FileTypeSubtreeData, subtreeData.Bytes()))
+
+		v := newOrderCheckingValidator(txs, txB)
+		server.validatorClient = v
+		server.blockchainClient.(*blockchain.Mock).On("IsFSMCurrentState", mock.Anything, blockchain.FSMStateRUNNING).
+			Return(true, nil)
+
+		prevResult, err := server.ValidateSubtreeStreamResult(context.Background(), subtree, bytes.NewReader(subtreeData.Bytes()), 100, map[uint32]bool{}, "")
+		require.NoError(t, err)
+		require.Equal(t, []TxValidationStatus{
+			TxStatusNotValidated, TxStatusValid, TxStatusInvalid, TxStatusTxNotFound, TxStatusValid, TxStatusValid,
+		}, statuses(prevResult))
+
+		// txB becomes valid, e.g. after the transaction it conflicted with was reorged out
+		delete(v.invalid, *txB.TxIDChainHash())
+		delete(v.validated, *txE.TxIDChainHash())
+
+		result, err := server.IncrementalValidate(context.Background(), prevResult, []chainhash.Hash{*txB.TxIDChainHash()})
+		require.NoError(t, err)
+
+		assert.Equal(t, []TxValidationStatus{
+			TxStatusNotValidated, TxStatusValid, TxStatusValid, TxStatusValid, TxStatusValid, TxStatusValid,
+		}, statuses(result))
+		assert.False(t, result.HasFailures())
+
+		// txE is not a descendant of txB and was not validated again
+		assert.NotContains(t, v.validated, *txE.TxIDChainHash())
+		assert.Contains(t, v.validated, *txC.TxIDChainHash())
+
+		// the previous result is not changed
+		assert.Equal(t, TxStatusInvalid, prevResult.Transactions[2].Status)
+
+		// the new result can be re-validated incrementally again
+		result, err = server.IncrementalValidate(context.Background(), result, nil)
+		require.NoError(t, err)
+		assert.False(t, result.HasFailures())
+	})
+
+	t.Run("a result without dependency graph", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		_, err := server.IncrementalValidate(context.Background(), &SubtreeValidationResult{}, []chainhash.Hash{*txA.TxIDChainHash()})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrInvalidArgument))
+	})
+
+	t.Run("subtreeData missing from the store", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.validatorClient = newOrderCheckingValidator(txs)
+
+		prevResult, err := server.ValidateSubtreeStreamResult(context.Background(), subtree, bytes.NewReader(subtreeData.Bytes()), 100, map[uint32]bool{}, "")
+		require.NoError(t, err)
+
+		_, err = server.IncrementalValidate(context.Background(), prevResult, []chainhash.Hash{*txA.TxIDChainHash()})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrStorageError))
+	})
+}
@@ -15,7 +15,9 @@ import (
 
 	"github.com/bsv-blockchain/go-bt/v2"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
 	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
 	"github.com/bsv-blockchain/teranode/stores/blob/memory"
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/bsv-blockchain/teranode/ulogger"
@@ -369,3 +371,130 @@ func TestPublishInvalidSubtree_DirectCall(t *testing.T) {
 	// verify the Kafka message key is the subtree hash
 	assert.Equal(t, []byte(subtreeHash), kafkaProducer.messages[0].Key)
 }
+
+// TestInvalidSubtreeReporting_TamperedSubtree tests that a subtree whose transaction hashes do not
+// give the merkle root it was announced with is rejected when it is received
+func TestInvalidSubtreeReporting_TamperedSubtree(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	txHashes := []chainhash.Hash{
+		chainhash.HashH([]byte("tx1")),
+		chainhash.HashH([]byte("tx2")),
+		chainhash.HashH([]byte("tx3")),
+		chainhash.HashH([]byte("tx4")),
+	}
+
+	subtree, err := subtreepkg.NewTreeByLeafCount(len(txHashes))
+	require.NoError(t, err)
+
+	for _, txHash := range txHashes {
+		require.NoError(t, subtree.AddNode(txHash, 0, 0))
+	}
+
+	subtreeHash := *subtree.RootHash()
+
+	newServer := func(t *testing.T, verify bool) *Server {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.SubtreeValidation.VerifySubtreeRootOnIngress = verify
+
+		return &Server{
+			logger:                       ulogger.TestLogger{},
+			settings:                     tSettings,
+			subtreeStore:                 memory.New(),
+			invalidSubtreeKafkaProducer:  &mockKafkaProducer{},
+			invalidSubtreeDeDuplicateMap: expiringmap.New[string, struct{}](time.Minute * 1),
+		}
+	}
+
+	respondWith := func(hashes []chainhash.Hash) {
+		body := make([]byte, 0, len(hashes)*chainhash.HashSize)
+		for _, hash := range hashes {
+			body = append(body, hash[:]...)
+		}
+
+		httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subtree/%s", testPeerURL, subtreeHash.String()),
+			httpmock.NewBytesResponder(http.StatusOK, body))
+	}
+
+	// the peer replaces one of the transactions of the subtree
+	tamperedTxHashes := append([]chainhash.Hash{}, txHashes...)
+	tamperedTxHashes[2] = chainhash.HashH([]byte("tampered"))
+
+	t.Run("untampered subtree is accepted", func(t *testing.T) {
+		server := newServer(t, true)
+		respondWith(txHashes)
+
+		hashes, err := server.getSubtreeTxHashes(context.Background(), gocore.NewStat("test"), &subtreeHash, testPeerURL)
+		require.NoError(t, err)
+		assert.Equal(t, txHashes, hashes)
+		assert.Empty(t, server.invalidSubtreeKafkaProducer.(*mockKafkaProducer).messages)
+	})
+
+	t.Run("tampered subtree is rejected", func(t *testing.T) {
+		server := newServer(t, true)
+		respondWith(tamperedTxHashes)
+
+		_, err := server.getSubtreeTxHashes(context.Background(), gocore.NewStat("test"), &subtreeHash, testPeerURL)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrSubtreeInvalid))
+		assert.Contains(t, err.Error(), "subtree root hash mismatch")
+		assert.True(t, errors.Is(err, ErrSubtreeRootMismatch))
+
+		kafkaProducer := server.invalidSubtreeKafkaProducer.(*mockKafkaProducer)
+		require.Len(t, kafkaProducer.messages, 1)
+
+		var msg kafkamessage.KafkaInvalidSubtreeTopicMessage
+		require.NoError(t, proto.Unmarshal(kafkaProducer.messages[0].Value, &msg))
+
+		assert.Equal(t, subtreeHash.String(), msg.SubtreeHash)
+		assert.Equal(t, testPeerURL, msg.PeerUrl)
+		assert.Equal(t, "subtree_root_mismatch", msg.Reason)
+	})
+
+	t.Run("tampered subtreeToCheck file is rejected", func(t *testing.T) {
+		server := newServer(t, true)
+
+		tampered, err := subtreepkg.NewTreeByLeafCount(len(tamperedTxHashes))
+		require.NoError(t, err)
+
+		for _, txHash := range tamperedTxHashes {
+			require.NoError(t, tampered.AddNode(txHash, 0, 0))
+		}
+
+		tamperedBytes, err := tampered.Serialize()
+		require.NoError(t, err)
+
+		require.NoError(t, server.subtreeStore.Set(context.Background(), subtreeHash[:], fileformat.FileTypeSubtreeToCheck, tamperedBytes))
+
+		_, err = server.getSubtreeTxHashes(context.Background(), gocore.NewStat("test"), &subtreeHash, testPeerURL)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrSubtreeInvalid))
+	})
+
+	t.Run("tampered supplied transaction hashes are rejected before validating", func(t *testing.T) {
+		InitPrometheusMetrics()
+
+		server := newServer(t, true)
+
+		_, err := server.ValidateSubtreeInternal(context.Background(), ValidateSubtree{
+			SubtreeHash: subtreeHash,
+			BaseURL:     testPeerURL,
+			TxHashes:    tamperedTxHashes,
+		}, 100, nil)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrSubtreeInvalid))
+		assert.True(t, errors.Is(err, ErrSubtreeRootMismatch))
+
+		require.Len(t, server.invalidSubtreeKafkaProducer.(*mockKafkaProducer).messages, 1)
+	})
+
+	t.Run("verification disabled", func(t *testing.T) {
+		server := newServer(t, false)
+		respondWith(tamperedTxHashes)
+
+		hashes, err := server.getSubtreeTxHashes(context.Background(), gocore.NewStat("test"), &subtreeHash, testPeerURL)
+		require.NoError(t, err)
+		assert.Equal(t, tamperedTxHashes, hashes)
+	})
+}
@@ -0,0 +1,228 @@
+package subtreevalidation
+
+import (
+	"sync"
+)
+
+// levelArenaPool reduces GC pressure by reusing the transient maps and slices that are built
+// while organising the transactions of a subtree into dependency levels. A subtree can contain
+// up to a million transactions, so re-allocating these structures for every validation creates
+// a lot of short-lived garbage.
+var levelArenaPool = sync.Pool{
+	New: func() interface{} {
+		return &levelArena{}
+	},
+}
+
+// levelArena holds the transient data structures used by prepareTxsPerLevel.
+//
+// The arena is only valid for the duration of a single prepareTxsPerLevel call. Nothing that
+// is returned to the caller may reference memory owned by the arena, since it is reset and
+// handed out to the next validation as soon as it is released.
+type levelArena struct {
+	// graph is the dependency graph of the transactions being levelled
+	graph DependencyGraph
+	// levels holds the dependency level of every transaction, by index in the graph
+	levels []uint32
+	// levelKnown marks the transactions of which the level has been calculated
+	levelKnown []bool
+
+	sizePerLevel map[uint32]uint64
+	// positions holds the indices of the transactions grouped per level, levelStart[level] is the start of a level
+	positions  []int
+	levelStart []int
+	// levelFill is the next free position of every level while the positions are grouped
+	levelFill []int
+}
+
+// newLevelArena creates an arena sized for the given number of transactions.
+func newLevelArena(size int) *levelArena {
+	a := &levelArena{}
+	a.prepare(size)
+
+	return a
+}
+
+// prepare sizes the arena for the given number of transactions.
+func (a *levelArena) prepare(size int) {
+	a.graph.prepare(size)
+
+	if cap(a.levels) < size {
+		a.levels = make([]uint32, 0, size)
+		a.levelKnown = make([]bool, 0, size)
+	}
+
+	// reset cleared the previously used part of the slices, so they are all zero
+	a.levels = a.levels[:size]
+	a.levelKnown = a.levelKnown[:size]
+
+	if a.sizePerLevel == nil {
+		a.sizePerLevel = make(map[uint32]uint64)
+	}
+}
+
+// groupPositionsPerLevel groups the indices of the transactions in the graph per level, in the order of their
+// indices, using the sizes of the levels in sizePerLevel. The positions of a level are
+// positions[levelStart[level]:levelStart[level+1]].
+func (a *levelArena) groupPositionsPerLevel(maxLevel uint32) {
+	levelCount := int(maxLevel) + 1
+
+	a.levelStart = resizeCleared(a.levelStart, levelCount+1)
+	a.levelFill = resizeCleared(a.levelFill, levelCount)
+
+	for level := 0; level < levelCount; level++ {
+		a.levelStart[level+1] = a.levelStart[level] + int(a.sizePerLevel[uint32(level)]) //nolint:gosec // G115: level is at most maxLevel
+	}
+
+	copy(a.levelFill, a.levelStart[:levelCount])
+
+	a.positions = resizeCleared(a.positions, a.levelStart[levelCount])
+
+	for idx := range a.graph.TxHashes {
+		if !a.graph.Contains(idx) {
+			continue
+		}
+
+		level := a.levels[idx]
+		a.positions[a.levelFill[level]] = idx
+		a.levelFill[level]++
+	}
+}
+
+// levelPositions returns the indices of the transactions of the given level, grouped by groupPositionsPerLevel.
+func (a *levelArena) levelPositions(level uint32) []int {
+	return a.positions[a.levelStart[level]:a.levelStart[level+1]]
+}
+
+// resizeCleared returns the slice resized to the given length, re-using its capacity when possible. Since the
+// arena clears the used part of its slices on reset, the returned slice is all zero.
+func resizeCleared[T any](s []T, size int) []T {
+	if cap(s) < size {
+		return make([]T, size)
+	}
+
+	return s[:size]
+}
+
+// reset clears all the data in the arena, keeping the allocated capacity for re-use.
+func (a *levelArena) reset() {
+	a.graph.reset()
+
+	clear(a.levels)
+	clear(a.levelKnown)
+
+	a.levels = a.levels[:0]
+	a.levelKnown = a.levelKnown[:0]
+
+	clear(a.sizePerLevel)
+
+	clear(a.positions)
+	clear(a.levelStart)
+	clear(a.levelFill)
+
+	a.positions = a.positions[:0]
+	a.levelStart = a.levelStart[:0]
+	a.levelFill = a.levelFill[:0]
+}
+
+// acquireLevelArena returns an arena for the given number of transactions, taken from the pool
+// when arena reuse is enabled in the settings.
+func (u *Server) acquireLevelArena(size int) *levelArena {
+	if !u.reuseValidationArena() {
+		return newLevelArena(size)
+	}
+
+	a := levelArenaPool.Get().(*levelArena)
+	a.prepare(size)
+
+	return a
+}
+
+// releaseLevelArena resets the arena and returns it to the pool when arena reuse is enabled.
+func (u *Server) releaseLevelArena(a *levelArena) {
+	if !u.reuseValidationArena() {
+		return
+	}
+
+	a.reset()
+	levelArenaPool.Put(a)
+}
+
+// txsPerLevelPool re-uses the slices of the transactions per level returned by prepareTxsPerLevel. The pooled
+// slices are kept with their per level slices, all cleared, so no transactions of a previous validation are
+// referenced.
+var txsPerLevelPool = sync.Pool{
+	New: func() interface{} {
+		return new([][]missingTx)
+	},
+}
+
+// acquireTxsPerLevel returns the slices for the transactions per level, the size of a level is
+// levelStart[level+1] - levelStart[level]. The slices of the levels without transactions are nil. The slices are
+// taken from the pool when arena reuse is enabled in the settings.
+func (u *Server) acquireTxsPerLevel(levelStart []int) [][]missingTx {
+	levelCount := len(levelStart) - 1
+
+	if !u.reuseValidationArena() {
+		txsPerLevel := make([][]missingTx, levelCount)
+
+		for level := range txsPerLevel {
+			if size := levelStart[level+1] - levelStart[level]; size > 0 {
+				txsPerLevel[level] = make([]missingTx, size)
+			}
+		}
+
+		return txsPerLevel
+	}
+
+	txsPerLevel := *(txsPerLevelPool.Get().(*[][]missingTx))
+
+	if cap(txsPerLevel) < levelCount {
+		// keep the per level slices of the pooled slice for re-use
+		txsPerLevel = append(txsPerLevel[:cap(txsPerLevel)], make([][]missingTx, levelCount-cap(txsPerLevel))...)
+	}
+
+	txsPerLevel = txsPerLevel[:levelCount]
+
+	for level := range txsPerLevel {
+		size := levelStart[level+1] - levelStart[level]
+
+		switch {
+		case size == 0:
+			txsPerLevel[level] = nil
+		case cap(txsPerLevel[level]) < size:
+			txsPerLevel[level] = make([]missingTx, size)
+		default:
+			// the pooled slice was cleared when it was released
+			txsPerLevel[level] = txsPerLevel[level][:size]
+		}
+	}
+
+	return txsPerLevel
+}
+
+// releaseTxsPerLevel clears the transactions per level returned by prepareTxsPerLevel and returns them to the pool
+// when arena reuse is enabled. The transactions per level must not be used anymore once they are released.
+func (u *Server) releaseTxsPerLevel(txsPerLevel [][]missingTx) {
+	if !u.reuseValidationArena() || txsPerLevel == nil {
+		return
+	}
+
+	for level := range txsPerLevel {
+		clear(txsPerLevel[level])
+		txsPerLevel[level] = txsPerLevel[level][:0]
+	}
+
+	txsPerLevel = txsPerLevel[:0]
+	txsPerLevelPool.Put(&txsPerLevel)
+}
+
+// reuseValidationArena returns whether the transient validation data structures should be pooled.
+// Pooling is the default when no settings have been provided.
+func (u *Server) reuseValidationArena() bool {
+	if u.settings == nil {
+		return true
+	}
+
+	return u.settings.SubtreeValidation.ReuseValidationArena
+}
@@ -0,0 +1,146 @@
+package subtreevalidation
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// subtreeSizeBuckets are the upper bounds of the subtree_size label of the level validation metrics
+var subtreeSizeBuckets = []int{1024, 16384, 131072, 1048576}
+
+// levelValidationConcurrency returns the number of transactions of a single dependency level that are
+// validated concurrently. The levels themselves are always validated one after the other, since the
+// transactions of a level can only be validated once their parents in the previous levels are.
+//
+// The value is taken from the ValidationConcurrency setting, defaulting to GOMAXPROCS, and is never
+// less than 1.
+func (u *Server) levelValidationConcurrency() int {
+	concurrency := 0
+	if u.settings != nil {
+		concurrency = u.settings.SubtreeValidation.ValidationConcurrency
+	}
+
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	return max(concurrency, 1)
+}
+
+// recoverLevelValidation wraps the validation of a transaction of a dependency level, returning a panic
+// inside the validation as an error instead of crashing the server. The error fails the level, cancelling
+// the validation of the other transactions of the level.
+func recoverLevelValidation(validate func() error) func() error {
+	return func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.NewProcessingError("panic while validating transaction: %v", r)
+			}
+		}()
+
+		return validate()
+	}
+}
+
+// levelContextError returns an error wrapping the context error when the context is done before or during the
+// validation of a dependency level, so a validation with a deadline stops promptly instead of validating the
+// remaining levels. The error matches context.Canceled or context.DeadlineExceeded with errors.Is.
+//
+// Cancellation never interrupts the validation of a single transaction: the validator spends the inputs and creates
+// the outputs of a transaction on a context decoupled from the caller, and reverses the spends when the outputs
+// cannot be created, so the UTXO mutations of a transaction are applied completely or not at all. A cancelled
+// validation only stops starting transactions; the transactions already started complete, the others are not
+// validated. The spends of a transaction are therefore applied at most once per validation attempt, and exactly once
+// across attempts: validating the subtree again re-spends the outputs spent by the same transaction, which the
+// UTXO store accepts as a no-op, and validates the transactions that were not started.
+func levelContextError(ctx context.Context, caller string, level, maxLevel uint32) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	return errors.NewContextCanceledError("[%s] stopped at level %d/%d", caller, level+1, maxLevel+1, ctx.Err())
+}
+
+// subtreeSizeBucket returns the subtree_size label of the level validation metrics for the given number of
+// transactions, so the metrics of small and large subtrees can be told apart without a label per size.
+func subtreeSizeBucket(txCount int) string {
+	for _, bucket := range subtreeSizeBuckets {
+		if txCount <= bucket {
+			return "le_" + strconv.Itoa(bucket)
+		}
+	}
+
+	return "gt_" + strconv.Itoa(subtreeSizeBuckets[len(subtreeSizeBuckets)-1])
+}
+
+// observeTxsPerLevel records the max level and the number of transactions of each dependency level of a
+// validation. A high max level with few transactions per level points at deep dependency chains, which
+// serialize the validation.
+func observeTxsPerLevel(sizeBucket string, maxLevel uint32, txsPerLevel [][]missingTx) {
+	prometheusSubtreeValidationMaxLevel.WithLabelValues(sizeBucket).Observe(float64(maxLevel))
+
+	levelTxs := prometheusSubtreeValidationLevelTxs.WithLabelValues(sizeBucket)
+
+	for _, txs := range txsPerLevel {
+		if len(txs) > 0 {
+			levelTxs.Observe(float64(len(txs)))
+		}
+	}
+}
+
+// levelStatsKey is the context key of the levelStats of a validation
+type levelStatsKey struct{}
+
+// levelStats collects the dependency levels computed while validating a subtree, for the callers that report them,
+// e.g. the CheckSubtreeFromBlock response. When the validation is retried, the levels of the last attempt are kept.
+type levelStats struct {
+	mu          sync.Mutex
+	maxLevel    uint32
+	txsPerLevel []uint32
+}
+
+// contextWithLevelStats returns a context collecting the dependency levels of the validations run with it
+func contextWithLevelStats(ctx context.Context) (context.Context, *levelStats) {
+	stats := &levelStats{}
+
+	return context.WithValue(ctx, levelStatsKey{}, stats), stats
+}
+
+// recordLevelStats records the max level and the number of transactions of each dependency level on the
+// levelStats of the context, when the context collects them
+func recordLevelStats(ctx context.Context, maxLevel uint32, txsPerLevel [][]missingTx) {
+	stats, ok := ctx.Value(levelStatsKey{}).(*levelStats)
+	if !ok {
+		return
+	}
+
+	counts := make([]uint32, 0, len(txsPerLevel))
+
+	for level := uint32(0); level <= maxLevel && int(level) < len(txsPerLevel); level++ {
+		counts = append(counts, uint32(len(txsPerLevel[level]))) //nolint:gosec // the number of transactions of a subtree fits a uint32
+	}
+
+	stats.mu.Lock()
+	stats.maxLevel = maxLevel
+	stats.txsPerLevel = counts
+	stats.mu.Unlock()
+}
+
+// get returns the max level and the number of transactions of each dependency level, no levels when no
+// transactions needed validating
+func (s *levelStats) get() (uint32, []uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.maxLevel, s.txsPerLevel
+}
+
+// observeLevelDuration records the wall-clock time spent validating a dependency level.
+func observeLevelDuration(sizeBucket string, start time.Time) {
+	prometheusSubtreeValidationLevelDuration.WithLabelValues(sizeBucket).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,215 @@
+package subtreevalidation
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestLevelValidationConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		setting  int
+		expected int
+	}{
+		{name: "default uses GOMAXPROCS", setting: 0, expected: runtime.GOMAXPROCS(0)},
+		{name: "negative uses GOMAXPROCS", setting: -1, expected: runtime.GOMAXPROCS(0)},
+		{name: "configured", setting: 3, expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tSettings := settings.NewSettings()
+			tSettings.SubtreeValidation.ValidationConcurrency = tt.setting
+
+			u := &Server{settings: tSettings}
+			assert.Equal(t, tt.expected, u.levelValidationConcurrency())
+		})
+	}
+}
+
+func TestRecoverLevelValidation(t *testing.T) {
+	t.Run("panic is returned as an error", func(t *testing.T) {
+		err := recoverLevelValidation(func() error {
+			panic("script engine exploded")
+		})()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrProcessing))
+		assert.Contains(t, err.Error(), "script engine exploded")
+	})
+
+	t.Run("result is passed through", func(t *testing.T) {
+		require.NoError(t, recoverLevelValidation(func() error { return nil })())
+
+		txErr := errors.NewTxInvalidError("invalid")
+		assert.Equal(t, txErr, recoverLevelValidation(func() error { return txErr })())
+	})
+
+	t.Run("panic cancels the rest of the level", func(t *testing.T) {
+		g, gCtx := errgroup.WithContext(context.Background())
+		g.SetLimit(2)
+
+		g.Go(recoverLevelValidation(func() error {
+			panic("boom")
+		}))
+
+		g.Go(recoverLevelValidation(func() error {
+			<-gCtx.Done()
+			return gCtx.Err()
+		}))
+
+		err := g.Wait()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+}
+
+func TestSubtreeSizeBucket(t *testing.T) {
+	assert.Equal(t, "le_1024", subtreeSizeBucket(0))
+	assert.Equal(t, "le_1024", subtreeSizeBucket(1024))
+	assert.Equal(t, "le_16384", subtreeSizeBucket(1025))
+	assert.Equal(t, "le_131072", subtreeSizeBucket(131072))
+	assert.Equal(t, "le_1048576", subtreeSizeBucket(1048576))
+	assert.Equal(t, "gt_1048576", subtreeSizeBucket(1048577))
+}
+
+func TestLevelValidationMetrics(t *testing.T) {
+	InitPrometheusMetrics()
+
+	sizeBucket := subtreeSizeBucket(1048577)
+
+	observeTxsPerLevel(sizeBucket, 2, [][]missingTx{make([]missingTx, 3), nil, make([]missingTx, 1)})
+	observeLevelDuration(sizeBucket, time.Now())
+
+	assert.Positive(t, testutil.CollectAndCount(prometheusSubtreeValidationMaxLevel, "teranode_subtreevalidation_max_level"))
+	assert.Positive(t, testutil.CollectAndCount(prometheusSubtreeValidationLevelTxs, "teranode_subtreevalidation_level_txs"))
+	assert.Positive(t, testutil.CollectAndCount(prometheusSubtreeValidationLevelDuration, "teranode_subtreevalidation_level_duration"))
+
+	retries := prometheusSubtreeValidationTxNotFoundRetries.WithLabelValues(sizeBucket)
+	before := testutil.ToFloat64(retries)
+	retries.Inc()
+	assert.Equal(t, before+1, testutil.ToFloat64(retries))
+}
+
+// cancellingValidator cancels the validation context when it validates its first transaction, and records the
+// validated transactions
+type cancellingValidator struct {
+	validator.MockValidatorClient
+
+	cancel    context.CancelFunc
+	mu        sync.Mutex
+	validated []chainhash.Hash
+}
+
+func (v *cancellingValidator) ValidateWithOptions(_ context.Context, tx *bt.Tx, _ uint32, _ *validator.Options) (*meta.Data, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.validated = append(v.validated, *tx.TxIDChainHash())
+	v.cancel()
+
+	return &meta.Data{}, nil
+}
+
+func TestLevelContextError(t *testing.T) {
+	t.Run("context not done", func(t *testing.T) {
+		assert.NoError(t, levelContextError(context.Background(), "test", 0, 1))
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		err := levelContextError(ctx, "test", 1, 3)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrContextCanceled))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Contains(t, err.Error(), "stopped at level 2/4")
+	})
+}
+
+func TestValidateLevels_StopsWhenContextDone(t *testing.T) {
+	external := chainhash.HashH([]byte("external"))
+
+	parent := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external, Vout: 0})
+	child := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: parent.TxIDChainHash(), Vout: 0})
+	grandchild := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: child.TxIDChainHash(), Vout: 0})
+
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	v := &cancellingValidator{cancel: cancel}
+	server.validatorClient = v
+
+	maxLevel, txsPerLevel, err := server.prepareTxsPerLevel(ctx, []missingTx{
+		{tx: parent, idx: 0},
+		{tx: child, idx: 1},
+		{tx: grandchild, idx: 2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), maxLevel)
+
+	processedValidatorOptions, err := server.levelValidatorOptions(ctx)
+	require.NoError(t, err)
+
+	var results levelValidationResults
+
+	err = server.validateLevels(ctx, maxLevel, txsPerLevel, subtreeSizeBucket(3), 100, map[uint32]bool{}, "", processedValidatorOptions, nil, &results)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// the context is cancelled while validating the first level, the remaining levels are abandoned
+	assert.Equal(t, []chainhash.Hash{*parent.TxIDChainHash()}, v.validated)
+	assert.Zero(t, results.errorsFound.Load())
+}
+
+func TestRecordLevelStats(t *testing.T) {
+	txsPerLevel := [][]missingTx{
+		{{idx: 0}, {idx: 1}, {idx: 2}},
+		{{idx: 3}},
+		{{idx: 4}, {idx: 5}},
+	}
+
+	t.Run("records the levels on the context", func(t *testing.T) {
+		ctx, stats := contextWithLevelStats(context.Background())
+
+		maxLevel, counts := stats.get()
+		assert.Zero(t, maxLevel)
+		assert.Empty(t, counts)
+
+		recordLevelStats(ctx, 2, txsPerLevel)
+
+		maxLevel, counts = stats.get()
+		assert.Equal(t, uint32(2), maxLevel)
+		assert.Equal(t, []uint32{3, 1, 2}, counts)
+
+		// a retried validation replaces the levels
+		recordLevelStats(ctx, 0, txsPerLevel[:1])
+
+		maxLevel, counts = stats.get()
+		assert.Equal(t, uint32(0), maxLevel)
+		assert.Equal(t, []uint32{3}, counts)
+	})
+
+	t.Run("ignores a context not collecting levels", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			recordLevelStats(context.Background(), 2, txsPerLevel)
+		})
+	})
+}
@@ -78,11 +78,68 @@ var (
 	// from Kafka messages, which helps identify issues with the cache or Kafka connection.
 	prometheusSubtreeValidationSetTXMetaCacheKafkaErrors prometheus.Counter
 
+	// prometheusSubtreeValidationBlockPrevoutCacheHits counts transactions extended from the block prevout cache.
+	// These are transactions of a block that only spend outputs created earlier in the same block,
+	// for which the previous outputs did not have to be looked up in the utxo store.
+	prometheusSubtreeValidationBlockPrevoutCacheHits prometheus.Counter
+
 	// prometheusSubtreeValidationPauseDuration tracks the duration of subtree processing pauses.
 	// This histogram measures how long the distributed pause lock is held during block validation,
 	// which is critical for detecting when pauses exceed expected durations and may indicate
 	// issues with block validation or lock release mechanisms.
 	prometheusSubtreeValidationPauseDuration prometheus.Histogram
+
+	// prometheusSubtreeValidationLevelTxs tracks the number of transactions per dependency level.
+	// The transactions of a level are validated in parallel, so small levels limit the parallelism
+	// of the validation.
+	prometheusSubtreeValidationLevelTxs *prometheus.HistogramVec
+
+	// prometheusSubtreeValidationMaxLevel tracks the highest dependency level of the validated transactions.
+	// The levels are validated one after the other, so a high max level, caused by deep dependency chains,
+	// serializes the validation.
+	prometheusSubtreeValidationMaxLevel *prometheus.HistogramVec
+
+	// prometheusSubtreeValidationLevelDuration tracks the wall-clock time spent validating a dependency level.
+	prometheusSubtreeValidationLevelDuration *prometheus.HistogramVec
+
+	// prometheusSubtreeValidationTxNotFoundRetries counts the subtree validation attempts that are retried
+	// because transactions of the subtree were not found.
+	prometheusSubtreeValidationTxNotFoundRetries *prometheus.CounterVec
+
+	// prometheusSubtreeValidationParentRetries counts the transaction validations retried because a parent
+	// of the transaction was not found.
+	prometheusSubtreeValidationParentRetries prometheus.Counter
+
+	// prometheusSubtreeValidationMissingParentOutpoints counts the external parent outpoints found missing by
+	// the batched lookup before the level validation.
+	prometheusSubtreeValidationMissingParentOutpoints prometheus.Counter
+
+	// prometheusSubtreeValidationCoinbaseSpendingTxs counts the transactions spending the output of a coinbase
+	// transaction, classified by the batched lookup before the level validation.
+	prometheusSubtreeValidationCoinbaseSpendingTxs prometheus.Counter
+
+	// prometheusSubtreeValidationImmatureCoinbaseTxs counts the transactions rejected for spending the output of
+	// a coinbase transaction before it matured.
+	prometheusSubtreeValidationImmatureCoinbaseTxs prometheus.Counter
+
+	// prometheusSubtreeValidationBacklog tracks the number of subtrees awaiting validation.
+	prometheusSubtreeValidationBacklog prometheus.Gauge
+
+	// prometheusSubtreeValidationBacklogRejected counts the announced subtrees not validated because the backlog of
+	// subtrees awaiting validation was full.
+	prometheusSubtreeValidationBacklogRejected prometheus.Counter
+
+	// prometheusSubtreeValidationPreValidatedTxs counts the transactions validated without verifying their scripts,
+	// because they are on the allowlist of pre-validated transactions.
+	prometheusSubtreeValidationPreValidatedTxs prometheus.Counter
+
+	// prometheusSubtreeValidationResultCache counts the lookups of subtrees in the subtree result cache, by hit or
+	// miss.
+	prometheusSubtreeValidationResultCache *prometheus.CounterVec
+
+	// prometheusSubtreeValidationFetchedParents counts the missing external parents fetched with the parent
+	// fetcher, by whether they were stored or failed.
+	prometheusSubtreeValidationFetchedParents *prometheus.CounterVec
 )
 
 var (
@@ -192,6 +249,15 @@ func _initPrometheusMetrics() {
 		},
 	)
 
+	prometheusSubtreeValidationBlockPrevoutCacheHits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "block_prevout_cache_hits",
+			Help:      "Number of block transactions extended from the block prevout cache",
+		},
+	)
+
 	prometheusSubtreeValidationPauseDuration = promauto.NewHistogram(
 		prometheus.HistogramOpts{
 			Namespace: "teranode",
@@ -201,4 +267,138 @@ func _initPrometheusMetrics() {
 			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12), // 0.1s to ~6.8 minutes
 		},
 	)
+
+	prometheusSubtreeValidationLevelTxs = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "level_txs",
+			Help:      "Number of transactions per dependency level",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 11), // 1 to ~1M transactions
+		},
+		[]string{
+			"subtree_size", // size bucket of the validated transactions, see subtreeSizeBucket
+		},
+	)
+
+	prometheusSubtreeValidationMaxLevel = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "max_level",
+			Help:      "Highest dependency level of the validated transactions",
+			Buckets:   []float64{0, 1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024},
+		},
+		[]string{
+			"subtree_size", // size bucket of the validated transactions, see subtreeSizeBucket
+		},
+	)
+
+	prometheusSubtreeValidationLevelDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "level_duration",
+			Help:      "Duration of the validation of a dependency level (in seconds)",
+			Buckets:   util.MetricsBucketsMilliSeconds,
+		},
+		[]string{
+			"subtree_size", // size bucket of the validated transactions, see subtreeSizeBucket
+		},
+	)
+
+	prometheusSubtreeValidationTxNotFoundRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "tx_not_found_retries",
+			Help:      "Number of subtree validation attempts retried because transactions were not found",
+		},
+		[]string{
+			"subtree_size", // size bucket of the subtree, see subtreeSizeBucket
+		},
+	)
+
+	prometheusSubtreeValidationParentRetries = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "parent_retries",
+			Help:      "Number of transaction validations retried because a parent was not found",
+		},
+	)
+
+	prometheusSubtreeValidationMissingParentOutpoints = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "missing_parent_outpoints",
+			Help:      "Number of external parent outpoints found missing before the level validation",
+		},
+	)
+
+	prometheusSubtreeValidationCoinbaseSpendingTxs = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "coinbase_spending_txs",
+			Help:      "Number of transactions spending a coinbase output found before the level validation",
+		},
+	)
+
+	prometheusSubtreeValidationImmatureCoinbaseTxs = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "immature_coinbase_txs",
+			Help:      "Number of transactions rejected for spending a coinbase output before it matured",
+		},
+	)
+
+	prometheusSubtreeValidationBacklog = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "backlog",
+			Help:      "Number of subtrees awaiting validation",
+		},
+	)
+
+	prometheusSubtreeValidationBacklogRejected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "backlog_rejected",
+			Help:      "Number of announced subtrees not validated because the subtree validation backlog was full",
+		},
+	)
+
+	prometheusSubtreeValidationPreValidatedTxs = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "pre_validated_txs",
+			Help:      "Number of transactions validated without verifying their scripts, because they were validated before",
+		},
+	)
+
+	prometheusSubtreeValidationResultCache = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "result_cache",
+			Help:      "Number of lookups of subtrees in the subtree result cache, by hit or miss",
+		},
+		[]string{"result"},
+	)
+
+	prometheusSubtreeValidationFetchedParents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "subtreevalidation",
+			Name:      "fetched_parents",
+			Help:      "Number of missing external parents fetched before the level validation, by stored or failed",
+		},
+		[]string{"result"},
+	)
 }
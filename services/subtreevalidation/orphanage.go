@@ -12,7 +12,8 @@ import (
 )
 
 // Orphanage manages orphaned transactions that are missing their parent transactions.
-// It provides a size-limited storage mechanism with TTL-based expiration.
+// It provides a size-limited storage mechanism with TTL-based expiration. The number of transactions
+// a single peer can add can be capped, so one peer cannot fill the orphanage with its transactions.
 type Orphanage struct {
 	// txMap stores the orphaned transactions with TTL support
 	txMap *expiringmap.ExpiringMap[chainhash.Hash, *bt.Tx]
@@ -20,6 +21,15 @@ type Orphanage struct {
 	// maxSize is the maximum number of transactions that can be stored
 	maxSize int
 
+	// maxPerPeer is the maximum number of transactions a single peer can have in the orphanage, 0 is unlimited
+	maxPerPeer int
+
+	// peerOrphans tracks the transactions added by every peer, only used when maxPerPeer is set
+	peerOrphans map[string]*peerOrphans
+
+	// orphanPeers holds the peer that added each tracked transaction
+	orphanPeers map[chainhash.Hash]string
+
 	// lock protects concurrent access to the orphanage
 	lock sync.Mutex
 
@@ -27,6 +37,16 @@ type Orphanage struct {
 	logger ulogger.Logger
 }
 
+// peerOrphans tracks the transactions a peer has in the orphanage.
+type peerOrphans struct {
+	// hashes holds the hashes of the transactions added by the peer, oldest first. Hashes of transactions
+	// that were removed from the orphanage are dropped when they reach the front.
+	hashes []chainhash.Hash
+
+	// count is the number of transactions of the peer in the orphanage
+	count int
+}
+
 // NewOrphanage creates a new Orphanage instance with the specified configuration.
 // Returns an error if the parameters are invalid.
 func NewOrphanage(timeout time.Duration, maxSize int, logger ulogger.Logger) (*Orphanage, error) {
@@ -41,9 +61,11 @@ func NewOrphanage(timeout time.Duration, maxSize int, logger ulogger.Logger) (*O
 	}
 
 	orphanage := &Orphanage{
-		txMap:   expiringmap.New[chainhash.Hash, *bt.Tx](timeout),
-		maxSize: maxSize,
-		logger:  logger,
+		txMap:       expiringmap.New[chainhash.Hash, *bt.Tx](timeout),
+		maxSize:     maxSize,
+		peerOrphans: make(map[string]*peerOrphans),
+		orphanPeers: make(map[chainhash.Hash]string),
+		logger:      logger,
 	}
 
 	// Set up eviction function to log when transactions expire
@@ -55,9 +77,31 @@ func NewOrphanage(timeout time.Duration, maxSize int, logger ulogger.Logger) (*O
 	return orphanage, nil
 }
 
+// SetMaxPerPeer sets the maximum number of transactions a single peer can have in the orphanage.
+// A value of 0 or less does not limit the transactions per peer.
+func (o *Orphanage) SetMaxPerPeer(maxPerPeer int) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if maxPerPeer < 0 {
+		maxPerPeer = 0
+	}
+
+	o.maxPerPeer = maxPerPeer
+}
+
 // Set adds a transaction to the orphanage if there's space.
 // Returns true if the transaction was added, false if the orphanage is full.
 func (o *Orphanage) Set(txHash chainhash.Hash, tx *bt.Tx) bool {
+	return o.SetFromPeer(txHash, tx, "")
+}
+
+// SetFromPeer adds a transaction received from the given peer to the orphanage if there's space.
+// When the peer already has the maximum number of transactions per peer in the orphanage, its oldest
+// transactions are evicted to make room, so a peer can only replace its own orphans. Transactions
+// without a peer are only limited by the size of the orphanage.
+// Returns true if the transaction was added, false if the orphanage is full.
+func (o *Orphanage) SetFromPeer(txHash chainhash.Hash, tx *bt.Tx, peer string) bool {
 	if tx == nil {
 		o.logger.Warnf("[Orphanage] Cannot add nil transaction for hash %s", txHash.String())
 		return false
@@ -66,13 +110,60 @@ func (o *Orphanage) Set(txHash chainhash.Hash, tx *bt.Tx) bool {
 	o.lock.Lock()
 	defer o.lock.Unlock()
 
-	// Check if orphanage is full - if so, reject the new entry
-	if o.txMap.Len() >= o.maxSize {
+	_, exists := o.txMap.Get(txHash)
+	if !exists {
+		// an expired transaction that is added again no longer counts for the peer that added it before
+		o.releasePeerOrphan(txHash)
+	}
+
+	trackPeer := o.maxPerPeer > 0 && peer != "" && !exists
+
+	var (
+		orphans *peerOrphans
+		evict   bool
+	)
+
+	if trackPeer {
+		orphans = o.peerOrphans[peer]
+		if orphans == nil {
+			orphans = &peerOrphans{}
+			o.peerOrphans[peer] = orphans
+		}
+
+		o.pruneExpiredPeerOrphans(peer, orphans)
+
+		evict = orphans.count >= o.maxPerPeer
+	}
+
+	// Check if orphanage is full - if so, reject the new entry. Evicting an orphan of the peer makes room,
+	// the orphans of the peer are only evicted when the new entry is added.
+	size := o.txMap.Len()
+	if evict {
+		size--
+	}
+
+	if size >= o.maxSize {
 		o.logger.Warnf("[Orphanage] Rejecting transaction %s - orphanage is full (%d/%d)",
 			txHash.String(), o.txMap.Len(), o.maxSize)
+
+		if trackPeer && orphans.count == 0 {
+			delete(o.peerOrphans, peer)
+		}
+
 		return false
 	}
 
+	if trackPeer {
+		// Evict the oldest transactions of the peer to stay within the per peer limit
+		for orphans.count >= o.maxPerPeer {
+			o.evictOldestPeerOrphan(peer, orphans)
+		}
+
+		orphans.hashes = append(orphans.hashes, txHash)
+		orphans.count++
+		o.orphanPeers[txHash] = peer
+	}
+
 	// Add the transaction
 	o.txMap.Set(txHash, tx)
 	o.logger.Debugf("[Orphanage] Added transaction %s (size: %d/%d)",
@@ -81,6 +172,101 @@ func (o *Orphanage) Set(txHash chainhash.Hash, tx *bt.Tx) bool {
 	return true
 }
 
+// isPeerOrphan returns whether the transaction is in the orphanage as a transaction of the peer.
+// Must be called with the lock held.
+func (o *Orphanage) isPeerOrphan(hash chainhash.Hash, peer string) bool {
+	owner, ok := o.orphanPeers[hash]
+	return ok && owner == peer
+}
+
+// releasePeerOrphan stops counting the transaction for the peer that added it.
+// Must be called with the lock held.
+func (o *Orphanage) releasePeerOrphan(hash chainhash.Hash) {
+	peer, ok := o.orphanPeers[hash]
+	if !ok {
+		return
+	}
+
+	delete(o.orphanPeers, hash)
+
+	orphans := o.peerOrphans[peer]
+	if orphans == nil {
+		return
+	}
+
+	orphans.count--
+	if orphans.count <= 0 {
+		delete(o.peerOrphans, peer)
+	}
+}
+
+// pruneExpiredPeerOrphans drops the removed and expired transactions at the front of the transactions of
+// the peer. As all transactions have the same timeout, the expired transactions are the oldest ones.
+// Must be called with the lock held.
+func (o *Orphanage) pruneExpiredPeerOrphans(peer string, orphans *peerOrphans) {
+	for len(orphans.hashes) > 0 {
+		hash := orphans.hashes[0]
+
+		if o.isPeerOrphan(hash, peer) {
+			if _, ok := o.txMap.Get(hash); ok {
+				return
+			}
+
+			o.txMap.Delete(hash)
+			delete(o.orphanPeers, hash)
+			orphans.count--
+		}
+
+		orphans.hashes = orphans.hashes[1:]
+	}
+}
+
+// evictOldestPeerOrphan removes the oldest transaction of the peer from the orphanage.
+// Must be called with the lock held.
+func (o *Orphanage) evictOldestPeerOrphan(peer string, orphans *peerOrphans) {
+	for len(orphans.hashes) > 0 {
+		hash := orphans.hashes[0]
+		orphans.hashes = orphans.hashes[1:]
+
+		if !o.isPeerOrphan(hash, peer) {
+			continue
+		}
+
+		o.txMap.Delete(hash)
+		delete(o.orphanPeers, hash)
+		orphans.count--
+
+		o.logger.Debugf("[Orphanage] Evicted transaction %s of peer %s - peer has %d/%d transactions in orphanage",
+			hash.String(), peer, orphans.count, o.maxPerPeer)
+
+		return
+	}
+
+	// no transactions of the peer are left to evict
+	orphans.count = 0
+}
+
+// PeerLen returns the number of transactions of the given peer in the orphanage.
+// Always returns 0 when the transactions per peer are not limited.
+func (o *Orphanage) PeerLen(peer string) int {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	orphans := o.peerOrphans[peer]
+	if orphans == nil {
+		return 0
+	}
+
+	o.pruneExpiredPeerOrphans(peer, orphans)
+
+	count := orphans.count
+	if count == 0 {
+		delete(o.peerOrphans, peer)
+	}
+
+	return count
+}
+
 // Get retrieves a transaction from the orphanage.
 func (o *Orphanage) Get(txHash chainhash.Hash) (*bt.Tx, bool) {
 	o.lock.Lock()
@@ -94,6 +280,7 @@ func (o *Orphanage) Delete(txHash chainhash.Hash) {
 	defer o.lock.Unlock()
 
 	o.txMap.Delete(txHash)
+	o.releasePeerOrphan(txHash)
 	o.logger.Debugf("[Orphanage] Removed transaction %s (size: %d/%d)",
 		txHash.String(), o.txMap.Len(), o.maxSize)
 }
@@ -126,7 +313,16 @@ func (o *Orphanage) Cleanup() {
 	o.lock.Lock()
 	defer o.lock.Unlock()
 
-	o.logger.Infof("[Orphanage] Cleanup: current size: %d/%d", o.txMap.Len(), o.maxSize)
+	// Drop the hashes of transactions that expired or were removed from the peer tracking
+	for peer, orphans := range o.peerOrphans {
+		o.pruneExpiredPeerOrphans(peer, orphans)
+
+		if orphans.count == 0 {
+			delete(o.peerOrphans, peer)
+		}
+	}
+
+	o.logger.Infof("[Orphanage] Cleanup: current size: %d/%d, peers: %d", o.txMap.Len(), o.maxSize, len(o.peerOrphans))
 }
 
 // MaxSize returns the maximum size limit of the orphanage.
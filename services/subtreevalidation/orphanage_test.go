@@ -110,6 +110,127 @@ func TestOrphanageSizeLimit(t *testing.T) {
 	assert.Equal(t, maxSize, server.orphanage.Len(), "Orphanage should still be at maxSize")
 }
 
+// TestOrphanageMaxPerPeer tests that a single peer cannot monopolize the orphanage
+func TestOrphanageMaxPerPeer(t *testing.T) {
+	logger := &ulogger.TestLogger{}
+
+	orphanage, err := NewOrphanage(15*time.Minute, 10, logger)
+	require.NoError(t, err)
+
+	orphanage.SetMaxPerPeer(3)
+
+	baseTx, err := createTestTransaction("tx1")
+	require.NoError(t, err)
+
+	// a flooding peer adds many more orphans than its share
+	floodTxs := make([]*bt.Tx, 0, 20)
+
+	for i := 0; i < 20; i++ {
+		tx := createUniqueTx(baseTx, uint32(i+1))
+		floodTxs = append(floodTxs, tx)
+
+		assert.True(t, orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, "http://peer-a"))
+	}
+
+	assert.Equal(t, 3, orphanage.PeerLen("http://peer-a"))
+	assert.Equal(t, 3, orphanage.Len())
+
+	// only the newest orphans of the peer are kept
+	for i, tx := range floodTxs {
+		_, ok := orphanage.Get(*tx.TxIDChainHash())
+		assert.Equal(t, i >= len(floodTxs)-3, ok, "orphan %d", i)
+	}
+
+	// other peers can still add their orphans
+	for i := 0; i < 3; i++ {
+		tx := createUniqueTx(baseTx, uint32(i+100))
+		assert.True(t, orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, "http://peer-b"))
+	}
+
+	assert.Equal(t, 3, orphanage.PeerLen("http://peer-b"))
+	assert.Equal(t, 6, orphanage.Len())
+
+	t.Run("adding the same orphan again does not evict", func(t *testing.T) {
+		tx := floodTxs[len(floodTxs)-1]
+		assert.True(t, orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, "http://peer-a"))
+
+		assert.Equal(t, 3, orphanage.PeerLen("http://peer-a"))
+		assert.Equal(t, 6, orphanage.Len())
+	})
+
+	t.Run("removed orphans free the share of the peer", func(t *testing.T) {
+		orphanage.Delete(*floodTxs[len(floodTxs)-1].TxIDChainHash())
+		assert.Equal(t, 2, orphanage.PeerLen("http://peer-a"))
+
+		tx := createUniqueTx(baseTx, 200)
+		assert.True(t, orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, "http://peer-a"))
+
+		// the oldest remaining orphan of the peer was not evicted
+		_, ok := orphanage.Get(*floodTxs[len(floodTxs)-3].TxIDChainHash())
+		assert.True(t, ok)
+		assert.Equal(t, 3, orphanage.PeerLen("http://peer-a"))
+	})
+
+	t.Run("orphans without a peer are not limited per peer", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			tx := createUniqueTx(baseTx, uint32(i+300))
+			assert.True(t, orphanage.Set(*tx.TxIDChainHash(), tx))
+		}
+
+		assert.Equal(t, 10, orphanage.Len())
+	})
+
+	t.Run("a full orphanage rejects new peers without evicting", func(t *testing.T) {
+		tx := createUniqueTx(baseTx, 400)
+		assert.False(t, orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, "http://peer-c"))
+
+		assert.Equal(t, 0, orphanage.PeerLen("http://peer-c"))
+		assert.Equal(t, 3, orphanage.PeerLen("http://peer-a"))
+		assert.Equal(t, 3, orphanage.PeerLen("http://peer-b"))
+		assert.Equal(t, 10, orphanage.Len())
+	})
+
+	t.Run("a peer at its limit replaces its own orphans in a full orphanage", func(t *testing.T) {
+		tx := createUniqueTx(baseTx, 500)
+		assert.True(t, orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, "http://peer-b"))
+
+		// the oldest orphan of the peer was evicted
+		_, ok := orphanage.Get(*createUniqueTx(baseTx, 100).TxIDChainHash())
+		assert.False(t, ok)
+
+		assert.Equal(t, 3, orphanage.PeerLen("http://peer-b"))
+		assert.Equal(t, 10, orphanage.Len())
+	})
+}
+
+// TestOrphanageMaxPerPeerManyOrphans tests that the orphans of a peer with a large limit are tracked correctly
+func TestOrphanageMaxPerPeerManyOrphans(t *testing.T) {
+	orphanage, err := NewOrphanage(15*time.Minute, 100_000, &ulogger.TestLogger{})
+	require.NoError(t, err)
+
+	orphanage.SetMaxPerPeer(10_000)
+
+	baseTx, err := createTestTransaction("tx1")
+	require.NoError(t, err)
+
+	for i := 0; i < 25_000; i++ {
+		tx := createUniqueTx(baseTx, uint32(i+1))
+		require.True(t, orphanage.SetFromPeer(*tx.TxIDChainHash(), tx, "http://peer-a"))
+
+		// remove every other orphan, the removed orphans do not count for the peer
+		if i%2 == 0 {
+			orphanage.Delete(*tx.TxIDChainHash())
+		}
+	}
+
+	assert.Equal(t, 10_000, orphanage.PeerLen("http://peer-a"))
+	assert.Equal(t, 10_000, orphanage.Len())
+
+	// the newest orphan that was kept is still in the orphanage
+	_, ok := orphanage.Get(*createUniqueTx(baseTx, 25_000).TxIDChainHash())
+	assert.True(t, ok)
+}
+
 // TestOrphanageRejectionPreventsParentRemoval tests that rejecting new entries when full
 // prevents removing parent transactions that child transactions depend on
 func TestOrphanageRejectionPreventsParentRemoval(t *testing.T) {
@@ -0,0 +1,187 @@
+package subtreevalidation
+
+import (
+	"context"
+	"slices"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+)
+
+// parentOutpoint is an output of a transaction outside the subtree, spent by a transaction of the subtree
+type parentOutpoint struct {
+	hash chainhash.Hash
+	vout uint32
+}
+
+// parentPrecheck is the result of the batched lookup of the parents outside the subtree
+type parentPrecheck struct {
+	// outpoints is the number of distinct external parent outpoints spent by the transactions
+	outpoints int
+
+	// missingParents are the external parent transactions that are not found in the UTXO store
+	missingParents map[chainhash.Hash]struct{}
+
+	// missingOutpoints are the distinct external parent outpoints of which the transaction is not found
+	missingOutpoints []parentOutpoint
+
+	// coinbaseParents are the external parent transactions that are coinbase transactions, by the lowest height
+	// they were mined at, 0 when the height is not known
+	coinbaseParents map[chainhash.Hash]uint32
+
+	// coinbaseSpendingTxs is the number of transactions spending the output of a coinbase transaction
+	coinbaseSpendingTxs int
+}
+
+// precheckExternalParents looks up all parents outside the graph in the UTXO store up front, before the
+// transactions are validated level by level, instead of discovering the missing parents one transaction at a
+// time during validation. The outpoints spent by more than one transaction are checked once.
+//
+// The UTXO store keeps the outputs of a transaction in the record of the transaction, so the outpoints are
+// looked up by their distinct parent transactions, in batches of ParentPrecheckBatchSize transactions to
+// respect the maximum number of keys per request of the store. An outpoint is missing when its transaction
+// is not found by the UTXO resolver.
+//
+// The parents that are coinbase transactions are classified separately: a coinbase has no parent itself and is
+// always in the store once its block is, so a transaction spending it legitimately has no parent in the subtree and
+// stays at level 0, unlike a transaction of which the external parent is not available yet. The height of the
+// youngest coinbase spent is recorded on the transaction, for the coinbase maturity check of its validation.
+func (u *Server) precheckExternalParents(ctx context.Context, graph *DependencyGraph, transactions []missingTx) (*parentPrecheck, error) {
+	precheck := &parentPrecheck{
+		missingParents:  make(map[chainhash.Hash]struct{}),
+		coinbaseParents: make(map[chainhash.Hash]uint32),
+	}
+
+	resolver := u.resolver()
+
+	externalParents := graph.externalParentSet()
+	if resolver == nil || len(externalParents) == 0 {
+		return precheck, nil
+	}
+
+	outpoints := make(map[parentOutpoint]struct{})
+
+	for idx, mTx := range transactions {
+		if !graph.Contains(idx) {
+			continue
+		}
+
+		for _, input := range mTx.tx.Inputs {
+			parentHash := *input.PreviousTxIDChainHash()
+
+			if _, inGraph := graph.indexOf[parentHash]; inGraph || parentHash.Equal(chainhash.Hash{}) {
+				continue
+			}
+
+			outpoints[parentOutpoint{hash: parentHash, vout: input.PreviousTxOutIndex}] = struct{}{}
+		}
+	}
+
+	precheck.outpoints = len(outpoints)
+
+	batchSize := 1024
+	if u.settings != nil && u.settings.SubtreeValidation.ParentPrecheckBatchSize > 0 {
+		batchSize = u.settings.SubtreeValidation.ParentPrecheckBatchSize
+	}
+
+	batch := make([]*utxo.UnresolvedMetaData, 0, min(batchSize, len(externalParents)))
+
+	lookupBatch := func() error {
+		if err := resolver.BatchDecorate(ctx, batch, fields.BlockIDs, fields.BlockHeights, fields.IsCoinbase); err != nil {
+			return errors.NewStorageError("[precheckExternalParents] failed to look up %d external parents", len(batch), err)
+		}
+
+		for _, item := range batch {
+			if item.Err != nil {
+				if errors.Is(item.Err, errors.ErrTxNotFound) {
+					precheck.missingParents[item.Hash] = struct{}{}
+				}
+
+				continue
+			}
+
+			if item.Data != nil && item.Data.IsCoinbase {
+				precheck.coinbaseParents[item.Hash] = lowestBlockHeight(item.Data.BlockHeights)
+			}
+		}
+
+		batch = batch[:0]
+
+		return nil
+	}
+
+	for parentHash := range externalParents {
+		batch = append(batch, &utxo.UnresolvedMetaData{
+			Hash: parentHash,
+			Idx:  len(batch),
+		})
+
+		if len(batch) == batchSize {
+			if err := lookupBatch(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := lookupBatch(); err != nil {
+			return nil, err
+		}
+	}
+
+	for outpoint := range outpoints {
+		if _, missing := precheck.missingParents[outpoint.hash]; missing {
+			precheck.missingOutpoints = append(precheck.missingOutpoints, outpoint)
+		}
+	}
+
+	prometheusSubtreeValidationMissingParentOutpoints.Add(float64(len(precheck.missingOutpoints)))
+
+	if len(precheck.coinbaseParents) > 0 {
+		precheck.classifyCoinbaseSpendingTxs(graph, transactions)
+	}
+
+	return precheck, nil
+}
+
+// classifyCoinbaseSpendingTxs counts the transactions spending the output of a coinbase transaction, and records on
+// every such transaction the height of the youngest coinbase it spends
+func (p *parentPrecheck) classifyCoinbaseSpendingTxs(graph *DependencyGraph, transactions []missingTx) {
+	for idx := range transactions {
+		if !graph.Contains(idx) {
+			continue
+		}
+
+		spendsCoinbase := false
+
+		for _, input := range transactions[idx].tx.Inputs {
+			coinbaseHeight, ok := p.coinbaseParents[*input.PreviousTxIDChainHash()]
+			if !ok {
+				continue
+			}
+
+			spendsCoinbase = true
+
+			if coinbaseHeight > transactions[idx].coinbaseHeight {
+				transactions[idx].coinbaseHeight = coinbaseHeight
+			}
+		}
+
+		if spendsCoinbase {
+			p.coinbaseSpendingTxs++
+		}
+	}
+
+	prometheusSubtreeValidationCoinbaseSpendingTxs.Add(float64(p.coinbaseSpendingTxs))
+}
+
+// lowestBlockHeight returns the lowest of the block heights, 0 when there are none
+func lowestBlockHeight(blockHeights []uint32) uint32 {
+	if len(blockHeights) == 0 {
+		return 0
+	}
+
+	return slices.Min(blockHeights)
+}
@@ -0,0 +1,152 @@
+package subtreevalidation
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	utxometa "github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecheckExternalParents(t *testing.T) {
+	external1 := chainhash.HashH([]byte("external 1"))
+	external2 := chainhash.HashH([]byte("external 2"))
+	external3 := chainhash.HashH([]byte("external 3"))
+
+	txA := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external1, Vout: 0}, bt.UTXO{TxIDHash: &external1, Vout: 1})
+	// txB spends an outpoint also spent by txA, which is only checked once
+	txB := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external1, Vout: 0}, bt.UTXO{TxIDHash: &external2, Vout: 0})
+	// txC spends an output of txA in the subtree, which is not looked up
+	txC := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external3, Vout: 0}, bt.UTXO{TxIDHash: txA.TxIDChainHash(), Vout: 0})
+
+	transactions := []missingTx{
+		{tx: txA, idx: 0},
+		{tx: txB, idx: 1},
+		{tx: txC, idx: 2},
+	}
+
+	graph, err := BuildDependencyGraph(context.Background(), transactions)
+	require.NoError(t, err)
+
+	t.Run("missing outpoints", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.settings.SubtreeValidation.ParentPrecheckBatchSize = 2
+
+		var (
+			batchSizes []int
+			looked     []chainhash.Hash
+		)
+
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("BatchDecorate", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				batch := args.Get(1).([]*utxo.UnresolvedMetaData)
+				batchSizes = append(batchSizes, len(batch))
+
+				for _, item := range batch {
+					looked = append(looked, item.Hash)
+
+					if item.Hash.Equal(external2) {
+						item.Err = errors.NewTxNotFoundError("not found")
+					} else {
+						item.Data = &utxometa.Data{BlockIDs: []uint32{1}}
+					}
+				}
+			}).
+			Return(nil)
+
+		server.utxoStore = mockUtxoStore
+
+		precheck, err := server.precheckExternalParents(context.Background(), graph, transactions)
+		require.NoError(t, err)
+
+		// the distinct parents are looked up in batches of at most 2
+		assert.ElementsMatch(t, []int{2, 1}, batchSizes)
+		assert.ElementsMatch(t, []chainhash.Hash{external1, external2, external3}, looked)
+
+		assert.Equal(t, 4, precheck.outpoints)
+		assert.Equal(t, map[chainhash.Hash]struct{}{external2: {}}, precheck.missingParents)
+		assert.Equal(t, []parentOutpoint{{hash: external2, vout: 0}}, precheck.missingOutpoints)
+	})
+
+	t.Run("coinbase parents", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("BatchDecorate", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				for _, item := range args.Get(1).([]*utxo.UnresolvedMetaData) {
+					switch {
+					case item.Hash.Equal(external1):
+						// a coinbase mined on two competing chains
+						item.Data = &utxometa.Data{IsCoinbase: true, BlockIDs: []uint32{7, 8}, BlockHeights: []uint32{150, 120}}
+					case item.Hash.Equal(external2):
+						item.Err = errors.NewTxNotFoundError("not found")
+					default:
+						item.Data = &utxometa.Data{BlockIDs: []uint32{1}, BlockHeights: []uint32{90}}
+					}
+				}
+			}).
+			Return(nil)
+
+		server.utxoStore = mockUtxoStore
+
+		// the coinbase height is recorded on the transactions, the shared slice is not changed
+		classified := slices.Clone(transactions)
+
+		precheck, err := server.precheckExternalParents(context.Background(), graph, classified)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[chainhash.Hash]uint32{external1: 120}, precheck.coinbaseParents)
+		assert.Equal(t, map[chainhash.Hash]struct{}{external2: {}}, precheck.missingParents)
+
+		// txA and txB spend the coinbase, txC does not
+		assert.Equal(t, 2, precheck.coinbaseSpendingTxs)
+		assert.Equal(t, uint32(120), classified[0].coinbaseHeight)
+		assert.Equal(t, uint32(120), classified[1].coinbaseHeight)
+		assert.Zero(t, classified[2].coinbaseHeight)
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		mockUtxoStore := &utxo.MockUtxostore{}
+		mockUtxoStore.On("BatchDecorate", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.NewStorageUnavailableError("connection refused"))
+
+		server.utxoStore = mockUtxoStore
+
+		_, err := server.precheckExternalParents(context.Background(), graph, transactions)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrStorageError))
+	})
+
+	t.Run("no external parents", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		mockUtxoStore := &utxo.MockUtxostore{}
+		server.utxoStore = mockUtxoStore
+
+		emptyGraph, err := BuildDependencyGraph(context.Background(), nil)
+		require.NoError(t, err)
+
+		precheck, err := server.precheckExternalParents(context.Background(), emptyGraph, nil)
+		require.NoError(t, err)
+		assert.Empty(t, precheck.missingParents)
+		assert.Zero(t, precheck.outpoints)
+
+		mockUtxoStore.AssertNotCalled(t, "BatchDecorate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,63 @@
+package subtreevalidation
+
+import (
+	"context"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+)
+
+// isParentNotFoundError returns whether the validation error is caused by a parent transaction or output that
+// was not found in the UTXO store, which can resolve itself when the parent is stored concurrently.
+func isParentNotFoundError(err error) bool {
+	return errors.Is(err, errors.ErrTxMissingParent) || errors.Is(err, errors.ErrTxNotFound)
+}
+
+// validateWithParentRetry validates the transaction in the validation service, validating it again with an
+// exponential backoff when it fails because a parent was not found, up to ParentRetryMaxAttempts attempts for
+// the transaction. All other errors are returned immediately. When the attempts are exhausted, the error of the
+// last attempt is returned, annotated with the number of attempts made.
+func (u *Server) validateWithParentRetry(ctx context.Context, tx *bt.Tx, blockHeight uint32,
+	validationOptions *validator.Options) (*meta.Data, error) {
+	maxAttempts := 1
+	delay := time.Duration(0)
+
+	if u.settings != nil {
+		maxAttempts = max(u.settings.SubtreeValidation.ParentRetryMaxAttempts, 1)
+		delay = u.settings.SubtreeValidation.ParentRetryBaseDelay
+	}
+
+	for attempt := 1; ; attempt++ {
+		txMeta, err := u.validatorClient.ValidateWithOptions(ctx, tx, blockHeight, validationOptions)
+		if err == nil || !isParentNotFoundError(err) || maxAttempts == 1 {
+			return txMeta, err
+		}
+
+		if attempt == maxAttempts {
+			if errors.Is(err, errors.ErrTxMissingParent) {
+				return nil, errors.NewTxMissingParentError("[validateWithParentRetry][%s] parent not found after %d attempts", tx.TxID(), attempt, err)
+			}
+
+			return nil, errors.NewTxNotFoundError("[validateWithParentRetry][%s] parent not found after %d attempts", tx.TxID(), attempt, err)
+		}
+
+		prometheusSubtreeValidationParentRetries.Inc()
+
+		u.logger.Debugf("[validateWithParentRetry][%s] parent not found on attempt %d, retrying in %s: %v", tx.TxID(), attempt, delay, err)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, errors.NewContextCanceledError("[validateWithParentRetry][%s] cancelled after %d attempts", tx.TxID(), attempt, err)
+		case <-timer.C:
+		}
+
+		delay *= 2
+	}
+}
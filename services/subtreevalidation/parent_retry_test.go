@@ -0,0 +1,98 @@
+package subtreevalidation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWithParentRetry(t *testing.T) {
+	tx, err := createTestTransaction("tx1")
+	require.NoError(t, err)
+
+	setup := func(t *testing.T, maxAttempts int, validationErrors ...error) (*Server, *validator.MockValidatorClient) {
+		server, cleanup := setupTestServer(t)
+		t.Cleanup(cleanup)
+
+		server.settings.SubtreeValidation.ParentRetryMaxAttempts = maxAttempts
+		server.settings.SubtreeValidation.ParentRetryBaseDelay = time.Millisecond
+
+		mockValidator := server.validatorClient.(*validator.MockValidatorClient)
+		mockValidator.UtxoStore = server.utxoStore
+		mockValidator.Errors = validationErrors
+
+		return server, mockValidator
+	}
+
+	t.Run("parent found on retry", func(t *testing.T) {
+		server, mockValidator := setup(t, 3,
+			errors.NewTxMissingParentError("missing parent"),
+			errors.NewTxNotFoundError("parent output not found"),
+		)
+
+		txMeta, err := server.validateWithParentRetry(context.Background(), tx, 100, validator.NewDefaultOptions())
+		require.NoError(t, err)
+		assert.NotNil(t, txMeta)
+		assert.Empty(t, mockValidator.Errors)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		server, mockValidator := setup(t, 2,
+			errors.NewTxMissingParentError("missing parent"),
+			errors.NewTxMissingParentError("missing parent"),
+			errors.NewTxMissingParentError("missing parent"),
+		)
+
+		_, err := server.validateWithParentRetry(context.Background(), tx, 100, validator.NewDefaultOptions())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxMissingParent))
+		assert.Contains(t, err.Error(), "after 2 attempts")
+		assert.Len(t, mockValidator.Errors, 1)
+	})
+
+	t.Run("retries disabled", func(t *testing.T) {
+		server, mockValidator := setup(t, 1,
+			errors.NewTxMissingParentError("missing parent"),
+		)
+
+		_, err := server.validateWithParentRetry(context.Background(), tx, 100, validator.NewDefaultOptions())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxMissingParent))
+		assert.NotContains(t, err.Error(), "attempts")
+		assert.Empty(t, mockValidator.Errors)
+	})
+
+	t.Run("invalid transaction not retried", func(t *testing.T) {
+		for _, validationErr := range []error{
+			errors.NewTxInvalidError("script failed"),
+			errors.NewTxInvalidDoubleSpendError("double spend"),
+		} {
+			server, mockValidator := setup(t, 3, validationErr)
+
+			_, err := server.validateWithParentRetry(context.Background(), tx, 100, validator.NewDefaultOptions())
+			require.Error(t, err)
+			assert.Equal(t, validationErr, err)
+			assert.Empty(t, mockValidator.Errors)
+		}
+	})
+
+	t.Run("backoff cancelled", func(t *testing.T) {
+		server, _ := setup(t, 3,
+			errors.NewTxMissingParentError("missing parent"),
+		)
+		server.settings.SubtreeValidation.ParentRetryBaseDelay = time.Hour
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := server.validateWithParentRetry(ctx, tx, 100, validator.NewDefaultOptions())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrContextCanceled))
+		assert.True(t, errors.Is(err, errors.ErrTxMissingParent))
+	})
+}
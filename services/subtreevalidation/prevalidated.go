@@ -0,0 +1,57 @@
+package subtreevalidation
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/services/validator"
+)
+
+// AddPreValidatedTransactions adds transactions to the allowlist of pre-validated transactions, e.g. the
+// transactions just created and validated by this node. The scripts of the allowlisted transactions are trusted and
+// not verified again when the transactions are validated as part of a subtree, the outputs they spend are still
+// checked. The transactions expire from the allowlist after PreValidatedAllowlistTTL, nothing is added when the
+// allowlist is disabled.
+//
+// Parameters:
+//   - txHashes: The hashes of the pre-validated transactions
+func (u *Server) AddPreValidatedTransactions(txHashes ...chainhash.Hash) {
+	if u.preValidatedTxs == nil {
+		return
+	}
+
+	for _, txHash := range txHashes {
+		u.preValidatedTxs.Set(txHash, struct{}{})
+	}
+}
+
+// removePreValidatedTransaction removes a transaction from the allowlist of pre-validated transactions
+func (u *Server) removePreValidatedTransaction(txHash chainhash.Hash) {
+	if u.preValidatedTxs == nil {
+		return
+	}
+
+	u.preValidatedTxs.Delete(txHash)
+}
+
+// preValidatedOptions returns the validation options of the transaction, skipping the verification of its scripts
+// when the transaction is on the allowlist of pre-validated transactions
+func (u *Server) preValidatedOptions(tx *bt.Tx, validationOptions *validator.Options) *validator.Options {
+	if u.preValidatedTxs == nil {
+		return validationOptions
+	}
+
+	if _, ok := u.preValidatedTxs.Get(*tx.TxIDChainHash()); !ok {
+		return validationOptions
+	}
+
+	preValidatedOptions := validator.NewDefaultOptions()
+	if validationOptions != nil {
+		*preValidatedOptions = *validationOptions
+	}
+
+	preValidatedOptions.SkipScriptVerification = true
+
+	prometheusSubtreeValidationPreValidatedTxs.Inc()
+
+	return preValidatedOptions
+}
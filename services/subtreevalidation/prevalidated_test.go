@@ -0,0 +1,96 @@
+package subtreevalidation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/ordishs/go-utils/expiringmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptSkipRecordingValidator records per transaction whether the verification of its scripts was skipped
+type scriptSkipRecordingValidator struct {
+	validator.MockValidatorClient
+
+	mu            sync.Mutex
+	skippedScript map[chainhash.Hash]bool
+}
+
+func (v *scriptSkipRecordingValidator) ValidateWithOptions(_ context.Context, tx *bt.Tx, _ uint32, opts *validator.Options) (*meta.Data, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.skippedScript[*tx.TxIDChainHash()] = opts.SkipScriptVerification
+
+	return &meta.Data{}, nil
+}
+
+func TestPreValidatedAllowlist(t *testing.T) {
+	external := chainhash.HashH([]byte("external"))
+
+	allowlistedTx := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external, Vout: 0})
+	otherTx := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external, Vout: 1})
+
+	validatorOptions := validator.ProcessOptions(validator.WithSkipPolicyChecks(true), validator.WithCreateConflicting(true))
+
+	bless := func(t *testing.T, server *Server) map[chainhash.Hash]bool {
+		v := &scriptSkipRecordingValidator{skippedScript: make(map[chainhash.Hash]bool)}
+		server.validatorClient = v
+
+		for _, tx := range []*bt.Tx{allowlistedTx, otherTx} {
+			_, err := server.blessMissingTransaction(context.Background(), chainhash.Hash{}, tx, 100, nil, validatorOptions)
+			require.NoError(t, err)
+		}
+
+		return v.skippedScript
+	}
+
+	t.Run("allowlisted transactions skip script verification", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.preValidatedTxs = expiringmap.New[chainhash.Hash, struct{}](time.Minute)
+		server.AddPreValidatedTransactions(*allowlistedTx.TxIDChainHash())
+
+		skippedScript := bless(t, server)
+
+		assert.True(t, skippedScript[*allowlistedTx.TxIDChainHash()])
+		assert.False(t, skippedScript[*otherTx.TxIDChainHash()])
+
+		// the options shared by all transactions are not changed
+		assert.False(t, validatorOptions.SkipScriptVerification)
+		assert.True(t, validatorOptions.SkipPolicyChecks)
+	})
+
+	t.Run("removed transactions are verified again", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.preValidatedTxs = expiringmap.New[chainhash.Hash, struct{}](time.Minute)
+		server.AddPreValidatedTransactions(*allowlistedTx.TxIDChainHash())
+		server.removePreValidatedTransaction(*allowlistedTx.TxIDChainHash())
+
+		skippedScript := bless(t, server)
+
+		assert.False(t, skippedScript[*allowlistedTx.TxIDChainHash()])
+	})
+
+	t.Run("allowlist disabled", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.AddPreValidatedTransactions(*allowlistedTx.TxIDChainHash())
+
+		skippedScript := bless(t, server)
+
+		assert.False(t, skippedScript[*allowlistedTx.TxIDChainHash()])
+		assert.False(t, skippedScript[*otherTx.TxIDChainHash()])
+	})
+}
@@ -84,7 +84,7 @@ func (u *Server) processTxMetaUsingStore(ctx context.Context, txHashes []chainha
 					}
 				}
 
-				if err := u.utxoStore.BatchDecorate(gCtx, missingTxHashesCompacted, fields.Fee, fields.SizeInBytes, fields.TxInpoints, fields.Conflicting, fields.BlockIDs); err != nil {
+				if err := u.resolver().BatchDecorate(gCtx, missingTxHashesCompacted, fields.Fee, fields.SizeInBytes, fields.TxInpoints, fields.Conflicting, fields.BlockIDs); err != nil {
 					return errors.NewStorageError("error running batch decorate on utxo store for missing transactions", err)
 				}
 
@@ -165,7 +165,7 @@ func (u *Server) processTxMetaUsingStore(ctx context.Context, txHashes []chainha
 						}
 
 						if txMetaSlice[i+j] == nil {
-							txMeta, err := u.utxoStore.GetMeta(gCtx, &txHash)
+							txMeta, err := u.resolver().GetMeta(gCtx, &txHash)
 							if err != nil {
 								return errors.NewStorageError("error getting tx meta from utxo store", err)
 							}
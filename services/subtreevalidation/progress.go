@@ -0,0 +1,132 @@
+package subtreevalidation
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ProgressFunc receives the progress of a subtree validation, to show the progress of large subtrees and detect
+// stalled validations. It is called when a dependency level of the transactions completes, and periodically while a
+// level is still being validated.
+//
+// The callback is always called from a single goroutine, never concurrently, and never after the validation returned.
+// A slow callback delays the validation, it should hand the progress off rather than block.
+//
+// Parameters:
+//   - level: The zero based dependency level being validated
+//   - done: The number of transactions validated so far, valid or not, over all levels
+//   - total: The number of transactions to validate
+type ProgressFunc func(level int, done int, total int)
+
+// progressUpdate is the progress of a completed level
+type progressUpdate struct {
+	level int
+	done  int
+}
+
+// progressReporter reports the progress of a subtree validation to a ProgressFunc from a single goroutine. All
+// methods are no-ops on a nil reporter.
+type progressReporter struct {
+	fn             ProgressFunc
+	total          int
+	level          atomic.Int64
+	done           atomic.Int64
+	levelCompleted chan progressUpdate
+	stopCh         chan struct{}
+	stopped        chan struct{}
+}
+
+// newProgressReporter starts reporting the progress of a validation of the given number of transactions to the
+// callback, reporting the progress of the current level every interval, or only completed levels when the interval
+// is 0. The reporter must be stopped before the validation returns.
+//
+// Returns:
+//   - *progressReporter: The reporter, nil when no callback is given
+func newProgressReporter(fn ProgressFunc, total int, interval time.Duration) *progressReporter {
+	if fn == nil {
+		return nil
+	}
+
+	p := &progressReporter{
+		fn:             fn,
+		total:          total,
+		levelCompleted: make(chan progressUpdate),
+		stopCh:         make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+
+	go p.run(interval)
+
+	return p
+}
+
+// run calls the callback on every completed level and every interval, until the reporter is stopped
+func (p *progressReporter) run(interval time.Duration) {
+	defer close(p.stopped)
+
+	var tick <-chan time.Time
+
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	lastDone := -1
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+
+		case update := <-p.levelCompleted:
+			p.fn(update.level, update.done, p.total)
+			lastDone = update.done
+
+		case <-tick:
+			// only report a level while it makes progress, a stalled validation shows as progress not changing
+			if done := int(p.done.Load()); done != lastDone {
+				p.fn(int(p.level.Load()), done, p.total)
+				lastDone = done
+			}
+		}
+	}
+}
+
+// startLevel marks the start of the validation of the given level
+func (p *progressReporter) startLevel(level uint32) {
+	if p == nil {
+		return
+	}
+
+	p.level.Store(int64(level))
+}
+
+// txDone counts a validated transaction, safe to call concurrently
+func (p *progressReporter) txDone() {
+	if p == nil {
+		return
+	}
+
+	p.done.Add(1)
+}
+
+// levelDone reports the completion of the given level, it returns once the callback returned
+func (p *progressReporter) levelDone(level uint32) {
+	if p == nil {
+		return
+	}
+
+	p.levelCompleted <- progressUpdate{level: int(level), done: int(p.done.Load())}
+}
+
+// stop stops the reporting, once stop returns the callback is not called anymore
+func (p *progressReporter) stop() {
+	if p == nil {
+		return
+	}
+
+	close(p.stopCh)
+	<-p.stopped
+}
@@ -0,0 +1,102 @@
+package subtreevalidation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressCall is a single call of a ProgressFunc
+type progressCall struct {
+	level int
+	done  int
+	total int
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Run("reports completed levels", func(t *testing.T) {
+		var calls []progressCall
+
+		p := newProgressReporter(func(level int, done int, total int) {
+			calls = append(calls, progressCall{level: level, done: done, total: total})
+		}, 5, 0)
+
+		p.startLevel(0)
+		p.txDone()
+		p.txDone()
+		p.levelDone(0)
+
+		p.startLevel(1)
+		p.txDone()
+		p.txDone()
+		p.txDone()
+		p.levelDone(1)
+
+		p.stop()
+
+		assert.Equal(t, []progressCall{{level: 0, done: 2, total: 5}, {level: 1, done: 5, total: 5}}, calls)
+	})
+
+	t.Run("reports periodically within a level", func(t *testing.T) {
+		var calls []progressCall
+
+		p := newProgressReporter(func(level int, done int, total int) {
+			calls = append(calls, progressCall{level: level, done: done, total: total})
+		}, 1000, time.Millisecond)
+
+		p.startLevel(3)
+
+		for i := 0; i < 10; i++ {
+			p.txDone()
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		p.levelDone(3)
+		p.stop()
+
+		require.Greater(t, len(calls), 2)
+
+		// the progress only moves forward, and a level is only reported while it makes progress
+		for i := 1; i < len(calls); i++ {
+			assert.Greater(t, calls[i].done, calls[i-1].done)
+		}
+
+		for _, call := range calls {
+			assert.Equal(t, 3, call.level)
+			assert.Equal(t, 1000, call.total)
+		}
+
+		assert.Equal(t, progressCall{level: 3, done: 10, total: 1000}, calls[len(calls)-1])
+	})
+
+	t.Run("not called after stop", func(t *testing.T) {
+		var calls int
+
+		p := newProgressReporter(func(int, int, int) { calls++ }, 10, time.Millisecond)
+
+		p.startLevel(0)
+		p.txDone()
+		p.levelDone(0)
+		p.stop()
+
+		callsAtStop := calls
+
+		p.txDone()
+		time.Sleep(10 * time.Millisecond)
+
+		assert.Equal(t, callsAtStop, calls)
+	})
+
+	t.Run("nil callback", func(t *testing.T) {
+		p := newProgressReporter(nil, 10, time.Millisecond)
+		require.Nil(t, p)
+
+		// all methods are no-ops on a nil reporter
+		p.startLevel(0)
+		p.txDone()
+		p.levelDone(0)
+		p.stop()
+	})
+}
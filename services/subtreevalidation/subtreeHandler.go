@@ -63,6 +63,13 @@ func (u *Server) subtreeMessageHandler(ctx context.Context) func(msg *kafka.Kafk
 				return nil
 			}
 
+			if errors.Is(err, errors.ErrServiceUnavailable) {
+				// if the subtree validation backlog is full, then return nil, so that the kafka message is marked as
+				// committed. The subtree is validated when the block containing it is received.
+				u.logger.Warnf("[subtreeMessageHandler] Skipping subtree: %v", err)
+				return nil
+			}
+
 			if errors.Is(err, errors.ErrContextCanceled) {
 				// if the error is context canceled, then return nil, so that the kafka message is marked as committed.
 				// So the message will not be consumed again.
@@ -128,6 +135,11 @@ func (u *Server) subtreesHandler(msg *kafka.KafkaMessage) error {
 		u.logger.Infof("Received subtree message for %s from %s", hash.String(), baseURL.String())
 		defer u.logger.Infof("Finished processing subtree message for %s", hash.String())
 
+		if err = u.acquireSubtreeBacklog(); err != nil {
+			return err
+		}
+		defer u.releaseSubtreeBacklog(1)
+
 		gotLock, _, releaseLockFunc, err := q.TryLockIfFileNotExists(ctx, hash, fileformat.FileTypeSubtree)
 		if err != nil {
 			u.logger.Infof("error getting lock for Subtree %s", hash.String())
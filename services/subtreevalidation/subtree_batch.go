@@ -0,0 +1,294 @@
+package subtreevalidation
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/ordishs/gocore"
+	"golang.org/x/sync/errgroup"
+)
+
+// SubtreeBatchResult is the result of the validation of a subtree of a SubtreeValidationBatch
+type SubtreeBatchResult struct {
+	// SubtreeHash is the merkle root of the subtree
+	SubtreeHash chainhash.Hash
+
+	// ValidatedCount is the number of transactions validated, the other transactions are already in the UTXO store
+	ValidatedCount int
+
+	// Delta holds the outpoints spent and created in the UTXO store by the subtree, nil when the subtree failed
+	Delta *UTXODelta
+
+	// Err is the reason the subtree failed, nil when it is valid and was written to the UTXO store
+	Err error
+}
+
+// SubtreeValidationBatch validates a set of independent subtrees concurrently, sharing a read-through cache of the
+// UTXO store for the duration of the batch, so a parent spent by transactions of several subtrees is only read once.
+//
+// The transactions of every subtree are first validated concurrently, without writing anything, like a subtree
+// validated in deferred commit mode. The valid subtrees are then written to the UTXO store one at a time, in the
+// order they were added to the batch. A subtree spending an output already spent by a subtree written before it is a
+// cross-subtree double spend, and is rejected without writing any of its transactions.
+//
+// The subtrees must be independent: the outputs created by the transactions of a subtree are not visible to the
+// other subtrees of the batch, a subtree spending them fails with a missing parent. Subtrees depending on each other
+// are validated in order, with ValidateSubtreeDeferred.
+type SubtreeValidationBatch struct {
+	server      *Server
+	blockHeight uint32
+	subtrees    []ValidateSubtree
+	validated   atomic.Bool
+	cache       *batchUTXOCache
+}
+
+// NewSubtreeValidationBatch creates a batch validating the subtrees for a block at the given height. The subtrees
+// are written to the UTXO store in the given order, a subtree added twice is validated once.
+func (u *Server) NewSubtreeValidationBatch(blockHeight uint32, subtrees ...ValidateSubtree) *SubtreeValidationBatch {
+	return &SubtreeValidationBatch{
+		server:      u,
+		blockHeight: blockHeight,
+		subtrees:    subtrees,
+		cache:       newBatchUTXOCache(u.utxoStore),
+	}
+}
+
+// Validate validates the subtrees of the batch and writes the valid ones to the UTXO store, returning the result of
+// every subtree keyed by its merkle root. The subtrees are validated concurrently, up to the validation concurrency
+// of the server. A batch is validated once.
+//
+// Returns:
+//   - map[chainhash.Hash]*SubtreeBatchResult: The result of every subtree of the batch
+//   - error: An error when the batch was already validated, or the context was cancelled before any subtree was
+//     written
+func (b *SubtreeValidationBatch) Validate(ctx context.Context) (results map[chainhash.Hash]*SubtreeBatchResult, err error) {
+	u := b.server
+
+	ctx, _, endSpan := tracing.Tracer("subtreevalidation").Start(ctx, "SubtreeValidationBatch.Validate",
+		tracing.WithDebugLogMessage(u.logger, "[SubtreeValidationBatch] validating %d subtrees", len(b.subtrees)),
+	)
+
+	defer func() {
+		endSpan(err)
+	}()
+
+	if b.validated.Swap(true) {
+		return nil, errors.NewProcessingError("[SubtreeValidationBatch] batch was already validated")
+	}
+
+	subtrees := make([]ValidateSubtree, 0, len(b.subtrees))
+	results = make(map[chainhash.Hash]*SubtreeBatchResult, len(b.subtrees))
+
+	for _, v := range b.subtrees {
+		if _, duplicate := results[v.SubtreeHash]; duplicate {
+			continue
+		}
+
+		subtrees = append(subtrees, v)
+		results[v.SubtreeHash] = &SubtreeBatchResult{SubtreeHash: v.SubtreeHash}
+	}
+
+	// validate the subtrees concurrently, without writing anything
+	changeSets := make([]*UTXOChangeSet, len(subtrees))
+
+	g, gCtx := errgroup.WithContext(ctx)
+	util.SafeSetLimit(g, u.levelValidationConcurrency())
+
+	for i, v := range subtrees {
+		g.Go(func() error {
+			result := results[v.SubtreeHash]
+			changeSets[i], result.Err = b.validateSubtree(gCtx, v)
+
+			if changeSets[i] != nil {
+				result.ValidatedCount = len(changeSets[i].txs)
+			}
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return nil, errors.NewContextCanceledError("[SubtreeValidationBatch] validation cancelled", err)
+	}
+
+	// write the valid subtrees in batch order, rejecting the subtrees spending outputs spent by an earlier subtree
+	spentBy := make(map[dryRunOutpoint]chainhash.Hash)
+
+	for i, v := range subtrees {
+		result := results[v.SubtreeHash]
+		if result.Err != nil {
+			continue
+		}
+
+		if result.Err = claimSpentOutputs(v.SubtreeHash, changeSets[i], spentBy); result.Err != nil {
+			continue
+		}
+
+		if result.Err = u.CommitUTXOChangeSet(ctx, changeSets[i]); result.Err != nil {
+			continue
+		}
+
+		result.Delta = changeSets[i].Delta()
+	}
+
+	hits, misses := b.cache.stats()
+	u.logger.Infof("[SubtreeValidationBatch] validated %d subtrees, %d UTXO reads served from the shared cache, %d from the UTXO store", len(subtrees), hits, misses)
+
+	return results, nil
+}
+
+// validateSubtree validates the transactions of a subtree of the batch against the shared cache, returning the
+// change set holding them.
+func (b *SubtreeValidationBatch) validateSubtree(ctx context.Context, v ValidateSubtree) (*UTXOChangeSet, error) {
+	u := b.server
+
+	txHashes := v.TxHashes
+	if txHashes == nil {
+		var err error
+
+		if txHashes, err = u.getSubtreeTxHashes(ctx, gocore.NewStat("SubtreeValidationBatch"), &v.SubtreeHash, v.BaseURL); err != nil {
+			return nil, errors.NewServiceError("[SubtreeValidationBatch][%s] failed to get subtree", v.SubtreeHash.String(), err)
+		}
+	}
+
+	txs, err := u.getDryRunTransactions(ctx, v, txHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet := NewUTXOChangeSet(b.blockHeight)
+
+	changeSet.mu.Lock()
+	defer changeSet.mu.Unlock()
+
+	if failure := u.addToChangeSet(ctx, b.cache, txs, changeSet); failure != nil {
+		return nil, changeSetFailureError("SubtreeValidationBatch", v.SubtreeHash, failure)
+	}
+
+	return changeSet, nil
+}
+
+// claimSpentOutputs records the outputs spent by the change set of a subtree in spentBy, returning a subtree
+// invalid error without recording any output when one of them is already spent by another subtree of the batch.
+func claimSpentOutputs(subtreeHash chainhash.Hash, changeSet *UTXOChangeSet, spentBy map[dryRunOutpoint]chainhash.Hash) error {
+	for _, tx := range changeSet.txs {
+		for _, input := range tx.Inputs {
+			outpoint := dryRunOutpoint{hash: *input.PreviousTxIDChainHash(), vout: input.PreviousTxOutIndex}
+
+			if otherSubtree, ok := spentBy[outpoint]; ok {
+				return errors.NewSubtreeInvalidError("[SubtreeValidationBatch][%s] transaction %s spends output %s:%d, already spent by subtree %s", subtreeHash.String(),
+					tx.TxIDChainHash().String(), outpoint.hash.String(), outpoint.vout, otherSubtree.String(),
+					errors.NewTxConflictingError("cross-subtree double spend"))
+			}
+		}
+	}
+
+	for outpoint := range changeSet.spent {
+		spentBy[outpoint] = subtreeHash
+	}
+
+	return nil
+}
+
+// batchUTXOCache is the read-through cache of the UTXO store shared by the subtrees of a batch. Concurrent reads of
+// the same parent, or of the state of the same output, wait for a single read of the UTXO store.
+//
+// Nothing is written to the UTXO store while the subtrees are validated, so the cached results stay valid for the
+// duration of the validation. The parents are always read with the same fields by the dry run, the fields are not
+// part of the key. Errors other than a transaction not found are not cached, the next read tries again.
+type batchUTXOCache struct {
+	store   utxo.Store
+	parents readThroughCache[chainhash.Hash, *meta.Data]
+	spends  readThroughCache[dryRunOutpoint, *utxo.SpendResponse]
+}
+
+// newBatchUTXOCache creates an empty cache in front of the UTXO store.
+func newBatchUTXOCache(store utxo.Store) *batchUTXOCache {
+	return &batchUTXOCache{
+		store:   store,
+		parents: readThroughCache[chainhash.Hash, *meta.Data]{entries: make(map[chainhash.Hash]*readThroughEntry[*meta.Data])},
+		spends:  readThroughCache[dryRunOutpoint, *utxo.SpendResponse]{entries: make(map[dryRunOutpoint]*readThroughEntry[*utxo.SpendResponse])},
+	}
+}
+
+// Get returns the metadata of a parent, reading it from the UTXO store once.
+func (c *batchUTXOCache) Get(ctx context.Context, hash *chainhash.Hash, fields ...fields.FieldName) (*meta.Data, error) {
+	return c.parents.get(ctx, *hash, func() (*meta.Data, error) {
+		return c.store.Get(ctx, hash, fields...)
+	})
+}
+
+// GetSpend returns the state of an output, reading it from the UTXO store once.
+func (c *batchUTXOCache) GetSpend(ctx context.Context, spend *utxo.Spend) (*utxo.SpendResponse, error) {
+	return c.spends.get(ctx, dryRunOutpoint{hash: *spend.TxID, vout: spend.Vout}, func() (*utxo.SpendResponse, error) {
+		return c.store.GetSpend(ctx, spend)
+	})
+}
+
+// stats returns the number of reads served from the cache and from the UTXO store.
+func (c *batchUTXOCache) stats() (hits, misses uint64) {
+	return c.parents.hits.Load() + c.spends.hits.Load(), c.parents.misses.Load() + c.spends.misses.Load()
+}
+
+// readThroughEntry is a value of a readThroughCache, done is closed once the value is read
+type readThroughEntry[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// readThroughCache caches the values read by the first reader of a key, the other readers of the key wait for it.
+type readThroughCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]*readThroughEntry[V]
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+// get returns the cached value of the key, reading it with read when it is not cached yet.
+func (c *readThroughCache[K, V]) get(ctx context.Context, key K, read func() (V, error)) (V, error) {
+	c.mu.Lock()
+
+	if entry, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			var zero V
+			return zero, errors.NewContextCanceledError("read cancelled", ctx.Err())
+		}
+
+		c.hits.Add(1)
+
+		return entry.value, entry.err
+	}
+
+	entry := &readThroughEntry[V]{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	c.misses.Add(1)
+
+	entry.value, entry.err = read()
+
+	if entry.err != nil && !errors.Is(entry.err, errors.ErrTxNotFound) {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+	}
+
+	close(entry.done)
+
+	return entry.value, entry.err
+}
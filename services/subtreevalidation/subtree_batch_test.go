@@ -0,0 +1,205 @@
+package subtreevalidation
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	blobmemory "github.com/bsv-blockchain/teranode/stores/blob/memory"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingUTXOStore counts the reads of the parents and of the state of the outputs in the UTXO store
+type countingUTXOStore struct {
+	utxo.Store
+
+	mu        sync.Mutex
+	gets      map[chainhash.Hash]int
+	getSpends map[dryRunOutpoint]int
+}
+
+func (s *countingUTXOStore) Get(ctx context.Context, hash *chainhash.Hash, fields ...fields.FieldName) (*meta.Data, error) {
+	s.mu.Lock()
+	s.gets[*hash]++
+	s.mu.Unlock()
+
+	return s.Store.Get(ctx, hash, fields...)
+}
+
+func (s *countingUTXOStore) GetSpend(ctx context.Context, spend *utxo.Spend) (*utxo.SpendResponse, error) {
+	s.mu.Lock()
+	s.getSpends[dryRunOutpoint{hash: *spend.TxID, vout: spend.Vout}]++
+	s.mu.Unlock()
+
+	return s.Store.GetSpend(ctx, spend)
+}
+
+func TestSubtreeValidationBatch(t *testing.T) {
+	const blockHeight = 123
+
+	opTrue := bscript.NewFromBytes([]byte{bscript.OpTRUE})
+
+	// spendTx creates a transaction spending an output locked with OP_TRUE
+	spendTx := func(t *testing.T, parentHash *chainhash.Hash, vout uint32, satoshis uint64) *bt.Tx {
+		tx := bt.NewTx()
+
+		input := &bt.Input{PreviousTxOutIndex: vout, UnlockingScript: &bscript.Script{}, SequenceNumber: bt.DefaultSequenceNumber}
+		require.NoError(t, input.PreviousTxIDAdd(parentHash))
+
+		tx.Inputs = append(tx.Inputs, input)
+		tx.AddOutput(&bt.Output{Satoshis: satoshis, LockingScript: opTrue})
+
+		return tx
+	}
+
+	setup := func(t *testing.T) (*Server, *countingUTXOStore, *bt.Tx) {
+		tSettings := test.CreateBaseTestSettings(t)
+
+		utxoStoreURL, err := url.Parse("sqlitememory:///test")
+		require.NoError(t, err)
+
+		sqlStore, err := sql.New(t.Context(), ulogger.TestLogger{}, tSettings, utxoStoreURL)
+		require.NoError(t, err)
+
+		require.NoError(t, sqlStore.SetBlockHeight(blockHeight-1))
+
+		// a parent with three outputs locked with OP_TRUE, already in the utxo store
+		grandParentHash := chainhash.HashH([]byte("grand parent"))
+		parent := spendTx(t, &grandParentHash, 0, 10_000)
+		parent.AddOutput(&bt.Output{Satoshis: 10_000, LockingScript: opTrue})
+		parent.AddOutput(&bt.Output{Satoshis: 10_000, LockingScript: opTrue})
+
+		_, err = sqlStore.Create(t.Context(), parent, blockHeight-1)
+		require.NoError(t, err)
+
+		utxoStore := &countingUTXOStore{
+			Store:     sqlStore,
+			gets:      make(map[chainhash.Hash]int),
+			getSpends: make(map[dryRunOutpoint]int),
+		}
+
+		u := &Server{
+			logger:       ulogger.TestLogger{},
+			settings:     tSettings,
+			utxoStore:    utxoStore,
+			subtreeStore: blobmemory.New(),
+		}
+
+		return u, utxoStore, parent
+	}
+
+	// subtree stores a subtree of the transactions in the subtree store of the server
+	subtree := func(t *testing.T, u *Server, txs ...*bt.Tx) ValidateSubtree {
+		subtreeHash, txHashes := storeSubtreeData(t, u.subtreeStore, txs)
+
+		return ValidateSubtree{SubtreeHash: subtreeHash, TxHashes: txHashes}
+	}
+
+	spendStatus := func(t *testing.T, utxoStore utxo.Store, tx *bt.Tx, vout uint32) utxo.Status {
+		utxoHash, err := util.UTXOHashFromOutput(tx.TxIDChainHash(), tx.Outputs[vout], vout)
+		require.NoError(t, err)
+
+		spend, err := utxoStore.GetSpend(t.Context(), &utxo.Spend{TxID: tx.TxIDChainHash(), Vout: vout, UTXOHash: utxoHash})
+		require.NoError(t, err)
+
+		return utxo.Status(spend.Status)
+	}
+
+	t.Run("shared parent is read once and cross-subtree double spend is rejected", func(t *testing.T) {
+		u, utxoStore, parent := setup(t)
+
+		child1 := spendTx(t, parent.TxIDChainHash(), 0, 9_000)
+		child2 := spendTx(t, parent.TxIDChainHash(), 1, 9_000)
+		doubleSpend := spendTx(t, parent.TxIDChainHash(), 0, 8_000)
+
+		subtree1 := subtree(t, u, child1)
+		subtree2 := subtree(t, u, child2)
+		subtree3 := subtree(t, u, spendTx(t, parent.TxIDChainHash(), 2, 9_000), doubleSpend)
+
+		results, err := u.NewSubtreeValidationBatch(blockHeight, subtree1, subtree2, subtree3).Validate(t.Context())
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		// the parent and the state of the output spent twice are read once from the utxo store
+		assert.Equal(t, 1, utxoStore.gets[*parent.TxIDChainHash()])
+		assert.Equal(t, 1, utxoStore.getSpends[dryRunOutpoint{hash: *parent.TxIDChainHash(), vout: 0}])
+
+		require.NoError(t, results[subtree1.SubtreeHash].Err)
+		require.NoError(t, results[subtree2.SubtreeHash].Err)
+		assert.Equal(t, 1, results[subtree1.SubtreeHash].ValidatedCount)
+		assert.Equal(t, []Outpoint{{TxHash: *parent.TxIDChainHash(), Index: 1}}, results[subtree2.SubtreeHash].Delta.Spent)
+		assert.Equal(t, []Outpoint{{TxHash: *child2.TxIDChainHash(), Index: 0}}, results[subtree2.SubtreeHash].Delta.Created)
+
+		// the subtree added last spends an output already spent by the first subtree
+		err = results[subtree3.SubtreeHash].Err
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrSubtreeInvalid)
+		assert.ErrorIs(t, err, errors.ErrTxConflicting)
+		assert.Contains(t, err.Error(), "already spent by subtree "+subtree1.SubtreeHash.String())
+		assert.Nil(t, results[subtree3.SubtreeHash].Delta)
+
+		assert.Equal(t, utxo.Status_SPENT, spendStatus(t, utxoStore, parent, 0))
+		assert.Equal(t, utxo.Status_SPENT, spendStatus(t, utxoStore, parent, 1))
+
+		// none of the transactions of the rejected subtree is written
+		assert.Equal(t, utxo.Status_OK, spendStatus(t, utxoStore, parent, 2))
+
+		_, err = utxoStore.Get(t.Context(), doubleSpend.TxIDChainHash())
+		assert.True(t, errors.Is(err, errors.ErrTxNotFound))
+	})
+
+	t.Run("invalid subtree does not claim its outputs", func(t *testing.T) {
+		u, utxoStore, parent := setup(t)
+
+		missingParentHash := chainhash.HashH([]byte("missing parent"))
+
+		invalid := subtree(t, u, spendTx(t, parent.TxIDChainHash(), 0, 9_000), spendTx(t, &missingParentHash, 0, 1_000))
+		valid := subtree(t, u, spendTx(t, parent.TxIDChainHash(), 0, 8_000))
+
+		results, err := u.NewSubtreeValidationBatch(blockHeight, invalid, valid).Validate(t.Context())
+		require.NoError(t, err)
+
+		require.Error(t, results[invalid.SubtreeHash].Err)
+		assert.ErrorIs(t, results[invalid.SubtreeHash].Err, errors.ErrSubtreeInvalid)
+		assert.Contains(t, results[invalid.SubtreeHash].Err.Error(), "not found")
+
+		require.NoError(t, results[valid.SubtreeHash].Err)
+		assert.Equal(t, utxo.Status_SPENT, spendStatus(t, utxoStore, parent, 0))
+	})
+
+	t.Run("subtree added twice is validated once", func(t *testing.T) {
+		u, _, parent := setup(t)
+
+		v := subtree(t, u, spendTx(t, parent.TxIDChainHash(), 0, 9_000))
+
+		results, err := u.NewSubtreeValidationBatch(blockHeight, v, v).Validate(t.Context())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NoError(t, results[v.SubtreeHash].Err)
+	})
+
+	t.Run("batch is validated once", func(t *testing.T) {
+		u, _, parent := setup(t)
+
+		batch := u.NewSubtreeValidationBatch(blockHeight, subtree(t, u, spendTx(t, parent.TxIDChainHash(), 0, 9_000)))
+
+		_, err := batch.Validate(t.Context())
+		require.NoError(t, err)
+
+		_, err = batch.Validate(t.Context())
+		require.Error(t, err)
+	})
+}
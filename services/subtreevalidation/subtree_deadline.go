@@ -0,0 +1,60 @@
+package subtreevalidation
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// subtreeValidationBudget returns how long the validation of a single subtree of a block may take, derived from the
+// deadline of the block validation. The remaining time until the block deadline is divided over the rounds needed
+// to validate all subtrees with the given concurrency, and multiplied by factor to leave room for subtrees that are
+// slower than average.
+//
+// Returns false when the block validation has no deadline, or factor is not positive, in which case the subtree
+// validations are only bound by the block deadline.
+//
+// Parameters:
+//   - ctx: Context of the block validation
+//   - now: The current time
+//   - subtrees: Number of subtrees to validate
+//   - concurrency: Number of subtrees validated in parallel, not positive when unlimited
+//   - factor: Multiple of its proportional share of the remaining time a subtree may use
+//
+// Returns:
+//   - time.Duration: The maximum duration of the validation of a single subtree
+//   - bool: Whether the validation of a single subtree is limited
+func subtreeValidationBudget(ctx context.Context, now time.Time, subtrees, concurrency int, factor float64) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok || factor <= 0 || subtrees <= 0 {
+		return 0, false
+	}
+
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return 0, false
+	}
+
+	rounds := 1
+	if concurrency > 0 {
+		rounds = (subtrees + concurrency - 1) / concurrency
+	}
+
+	budget := float64(remaining) / float64(rounds) * factor
+	if budget >= float64(remaining) || budget > math.MaxInt64 {
+		// a subtree cannot use more than the remaining time of the block anyway
+		return 0, false
+	}
+
+	return time.Duration(budget), true
+}
+
+// withSubtreeBudget returns a context for the validation of a single subtree that is cancelled after budget, or
+// the context itself when the validation is not limited.
+func withSubtreeBudget(ctx context.Context, budget time.Duration, limited bool) (context.Context, context.CancelFunc) {
+	if !limited {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, budget)
+}
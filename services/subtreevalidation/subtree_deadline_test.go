@@ -0,0 +1,99 @@
+package subtreevalidation
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestSubtreeValidationBudget(t *testing.T) {
+	now := time.Now()
+
+	blockCtx, cancel := context.WithDeadline(context.Background(), now.Add(8*time.Second))
+	defer cancel()
+
+	t.Run("divided over the rounds", func(t *testing.T) {
+		// 8 subtrees with a concurrency of 2 need 4 rounds of 2 seconds each
+		budget, limited := subtreeValidationBudget(blockCtx, now, 8, 2, 1)
+		require.True(t, limited)
+		assert.Equal(t, 2*time.Second, budget)
+
+		// a partial round counts as a full round
+		budget, limited = subtreeValidationBudget(blockCtx, now, 7, 2, 1)
+		require.True(t, limited)
+		assert.Equal(t, 2*time.Second, budget)
+	})
+
+	t.Run("factor gives room for slow subtrees", func(t *testing.T) {
+		budget, limited := subtreeValidationBudget(blockCtx, now, 8, 2, 1.5)
+		require.True(t, limited)
+		assert.Equal(t, 3*time.Second, budget)
+	})
+
+	t.Run("not limited", func(t *testing.T) {
+		// no deadline on the block validation
+		_, limited := subtreeValidationBudget(context.Background(), now, 8, 2, 1)
+		assert.False(t, limited)
+
+		// disabled
+		_, limited = subtreeValidationBudget(blockCtx, now, 8, 2, 0)
+		assert.False(t, limited)
+
+		// all subtrees are validated in a single round, the budget would be the remaining time of the block
+		_, limited = subtreeValidationBudget(blockCtx, now, 8, 32, 1)
+		assert.False(t, limited)
+
+		// unlimited concurrency
+		_, limited = subtreeValidationBudget(blockCtx, now, 8, 0, 1)
+		assert.False(t, limited)
+
+		// the block deadline has passed
+		_, limited = subtreeValidationBudget(blockCtx, now.Add(9*time.Second), 8, 2, 1)
+		assert.False(t, limited)
+	})
+}
+
+func TestSubtreeValidationBudgetCancelsSlowSubtree(t *testing.T) {
+	blockCtx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+
+	// 4 subtrees validated one at a time, each gets about a second
+	budget, limited := subtreeValidationBudget(blockCtx, time.Now(), 4, 1, 1)
+	require.True(t, limited)
+
+	durations := []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, time.Minute, 10 * time.Millisecond}
+	results := make([]error, len(durations))
+
+	var validated atomic.Int32
+
+	g := errgroup.Group{}
+	g.SetLimit(1)
+
+	for i, duration := range durations {
+		g.Go(func() error {
+			subtreeCtx, subtreeCancel := withSubtreeBudget(blockCtx, budget, limited)
+			defer subtreeCancel()
+
+			select {
+			case <-subtreeCtx.Done():
+				results[i] = subtreeCtx.Err()
+			case <-time.After(duration):
+				validated.Add(1)
+			}
+
+			return nil
+		})
+	}
+
+	require.NoError(t, g.Wait())
+
+	// the slow subtree was cancelled after its budget, the others were validated within the block deadline
+	assert.ErrorIs(t, results[2], context.DeadlineExceeded)
+	assert.Equal(t, int32(3), validated.Load())
+	assert.NoError(t, blockCtx.Err())
+}
@@ -0,0 +1,191 @@
+package subtreevalidation
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+)
+
+// subtreeVerdict is the cached outcome of the validation of a subtree, a nil err is a valid subtree
+type subtreeVerdict struct {
+	err error
+
+	// delta is the UTXO delta of the validation of a valid subtree, nil when the validation did not record it
+	delta *UTXODelta
+}
+
+// subtreeResultCacheEntry is an entry of the subtreeResultCache
+type subtreeResultCacheEntry struct {
+	hash    chainhash.Hash
+	verdict subtreeVerdict
+}
+
+// subtreeResultCache is a fixed size LRU cache of subtree validation verdicts, keyed by the merkle root of the
+// subtree. When the cache is full, adding a subtree evicts the least recently used subtree.
+type subtreeResultCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[chainhash.Hash]*list.Element
+	order   *list.List
+}
+
+// newSubtreeResultCache creates a subtree result cache holding the verdicts of at most size subtrees
+func newSubtreeResultCache(size int) *subtreeResultCache {
+	return &subtreeResultCache{
+		size:    size,
+		entries: make(map[chainhash.Hash]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// get returns the verdict of the subtree and marks it as recently used
+func (c *subtreeResultCache) get(hash chainhash.Hash) (subtreeVerdict, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[hash]
+	if !ok {
+		return subtreeVerdict{}, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(*subtreeResultCacheEntry).verdict, true
+}
+
+// set adds or replaces the verdict of the subtree, evicting the least recently used subtree when the cache is full
+func (c *subtreeResultCache) set(hash chainhash.Hash, verdict subtreeVerdict) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[hash]; ok {
+		element.Value.(*subtreeResultCacheEntry).verdict = verdict
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		if oldest := c.order.Back(); oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*subtreeResultCacheEntry).hash)
+		}
+	}
+
+	c.entries[hash] = c.order.PushFront(&subtreeResultCacheEntry{hash: hash, verdict: verdict})
+}
+
+// delete removes the verdict of the subtree
+func (c *subtreeResultCache) delete(hash chainhash.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[hash]; ok {
+		c.order.Remove(element)
+		delete(c.entries, hash)
+	}
+}
+
+// clear removes the verdicts of all subtrees
+func (c *subtreeResultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[chainhash.Hash]*list.Element, c.size)
+	c.order.Init()
+}
+
+// len returns the number of cached subtree verdicts
+func (c *subtreeResultCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// cachedSubtreeResult returns the outcome of an earlier validation of the subtree, when cached. A valid subtree is
+// loaded from the subtree store, a subtree that is no longer in the store is removed from the cache and validated
+// again.
+//
+// The UTXO delta of the earlier validation is copied into delta, when not nil. A valid subtree validated without
+// recording its delta is not returned to a caller requesting the delta, it is validated again.
+func (u *Server) cachedSubtreeResult(ctx context.Context, subtreeHash *chainhash.Hash, delta *UTXODelta) (*subtreepkg.Subtree, bool, error) {
+	if u.subtreeResultCache == nil {
+		return nil, false, nil
+	}
+
+	verdict, ok := u.subtreeResultCache.get(*subtreeHash)
+	if !ok || (verdict.err == nil && delta != nil && verdict.delta == nil) {
+		prometheusSubtreeValidationResultCache.WithLabelValues("miss").Inc()
+		return nil, false, nil
+	}
+
+	if verdict.err != nil {
+		prometheusSubtreeValidationResultCache.WithLabelValues("hit").Inc()
+		return nil, true, verdict.err
+	}
+
+	subtreeBytes, err := u.subtreeStore.Get(ctx, subtreeHash[:], fileformat.FileTypeSubtree)
+	if err != nil {
+		u.logger.Debugf("[cachedSubtreeResult][%s] validated subtree not found in store, validating it again: %v", subtreeHash.String(), err)
+		u.subtreeResultCache.delete(*subtreeHash)
+		prometheusSubtreeValidationResultCache.WithLabelValues("miss").Inc()
+
+		return nil, false, nil
+	}
+
+	subtree, err := subtreepkg.NewSubtreeFromBytes(subtreeBytes)
+	if err != nil {
+		u.subtreeResultCache.delete(*subtreeHash)
+		prometheusSubtreeValidationResultCache.WithLabelValues("miss").Inc()
+
+		return nil, false, nil
+	}
+
+	delta.set(verdict.delta)
+
+	prometheusSubtreeValidationResultCache.WithLabelValues("hit").Inc()
+
+	return subtree, true, nil
+}
+
+// cacheSubtreeResult caches the outcome of the validation of the subtree, with a copy of its UTXO delta when the
+// validation recorded it. Only final outcomes are cached, a valid subtree or a subtree that is invalid in itself;
+// transient failures, e.g. missing transactions, are validated again, and so are invalid transactions, whose
+// verdict can depend on the height of the block the subtree is validated for, e.g. lock times and coinbase maturity.
+func (u *Server) cacheSubtreeResult(subtreeHash *chainhash.Hash, err error, delta *UTXODelta) {
+	if u.subtreeResultCache == nil {
+		return
+	}
+
+	switch {
+	case err == nil:
+		u.subtreeResultCache.set(*subtreeHash, subtreeVerdict{delta: delta.clone()})
+	case isFinalSubtreeInvalidError(err):
+		u.subtreeResultCache.set(*subtreeHash, subtreeVerdict{err: err})
+	}
+}
+
+// isFinalSubtreeInvalidError returns whether the error reports a subtree that is invalid regardless of the block it
+// is validated for, e.g. a subtree that does not match its merkle root or exceeds a limit. The errors of the
+// transactions of a subtree are annotated with the failing transaction and are not final.
+func isFinalSubtreeInvalidError(err error) bool {
+	return errors.Is(err, errors.ErrSubtreeInvalid) &&
+		!errors.Is(err, errors.ErrTxInvalid) &&
+		errors.GetData(err, ErrDataTxID) == nil
+}
+
+// invalidateSubtreeResults removes all cached subtree verdicts, when the best chain reorganised. Transactions of
+// a validated subtree may have been double spent by the blocks of the new chain, so the subtrees are validated again.
+func (u *Server) invalidateSubtreeResults() {
+	if u.subtreeResultCache == nil {
+		return
+	}
+
+	u.subtreeResultCache.clear()
+}
@@ -0,0 +1,279 @@
+package subtreevalidation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtreeResultCache(t *testing.T) {
+	hash1 := chainhash.HashH([]byte("subtree 1"))
+	hash2 := chainhash.HashH([]byte("subtree 2"))
+	hash3 := chainhash.HashH([]byte("subtree 3"))
+
+	t.Run("evicts the least recently used subtree", func(t *testing.T) {
+		cache := newSubtreeResultCache(2)
+
+		cache.set(hash1, subtreeVerdict{})
+		cache.set(hash2, subtreeVerdict{})
+
+		// using subtree 1 makes subtree 2 the least recently used
+		_, ok := cache.get(hash1)
+		require.True(t, ok)
+
+		cache.set(hash3, subtreeVerdict{})
+
+		assert.Equal(t, 2, cache.len())
+
+		_, ok = cache.get(hash1)
+		assert.True(t, ok)
+
+		_, ok = cache.get(hash2)
+		assert.False(t, ok)
+
+		_, ok = cache.get(hash3)
+		assert.True(t, ok)
+	})
+
+	t.Run("replaces the verdict", func(t *testing.T) {
+		cache := newSubtreeResultCache(2)
+
+		cache.set(hash1, subtreeVerdict{})
+		cache.set(hash1, subtreeVerdict{err: errors.NewSubtreeInvalidError("invalid")})
+
+		verdict, ok := cache.get(hash1)
+		require.True(t, ok)
+		assert.Error(t, verdict.err)
+		assert.Equal(t, 1, cache.len())
+	})
+
+	t.Run("delete and clear", func(t *testing.T) {
+		cache := newSubtreeResultCache(2)
+
+		cache.set(hash1, subtreeVerdict{})
+		cache.set(hash2, subtreeVerdict{})
+
+		cache.delete(hash1)
+
+		_, ok := cache.get(hash1)
+		assert.False(t, ok)
+		assert.Equal(t, 1, cache.len())
+
+		cache.clear()
+		assert.Equal(t, 0, cache.len())
+
+		cache.set(hash3, subtreeVerdict{})
+		assert.Equal(t, 1, cache.len())
+	})
+}
+
+func TestServer_SubtreeResultCache(t *testing.T) {
+	newStoredSubtree := func(t *testing.T, server *Server) *subtreepkg.Subtree {
+		subtree, err := subtreepkg.NewTreeByLeafCount(2)
+		require.NoError(t, err)
+
+		require.NoError(t, subtree.AddNode(chainhash.HashH([]byte("tx 1")), 1, 100))
+		require.NoError(t, subtree.AddNode(chainhash.HashH([]byte("tx 2")), 1, 100))
+
+		subtreeBytes, err := subtree.Serialize()
+		require.NoError(t, err)
+
+		require.NoError(t, server.subtreeStore.Set(context.Background(), subtree.RootHash()[:], fileformat.FileTypeSubtree, subtreeBytes))
+
+		return subtree
+	}
+
+	t.Run("a validated subtree short-circuits", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.subtreeResultCache = newSubtreeResultCache(10)
+
+		subtree := newStoredSubtree(t, server)
+
+		_, found, err := server.cachedSubtreeResult(context.Background(), subtree.RootHash(), nil)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		server.cacheSubtreeResult(subtree.RootHash(), nil, nil)
+
+		cachedSubtree, found, err := server.cachedSubtreeResult(context.Background(), subtree.RootHash(), nil)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, subtree.RootHash(), cachedSubtree.RootHash())
+		assert.Len(t, cachedSubtree.Nodes, 2)
+	})
+
+	t.Run("a validated subtree no longer stored is validated again", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.subtreeResultCache = newSubtreeResultCache(10)
+
+		subtreeHash := chainhash.HashH([]byte("not stored"))
+		server.cacheSubtreeResult(&subtreeHash, nil, nil)
+
+		_, found, err := server.cachedSubtreeResult(context.Background(), &subtreeHash, nil)
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, 0, server.subtreeResultCache.len())
+	})
+
+	t.Run("only final verdicts are cached", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.subtreeResultCache = newSubtreeResultCache(10)
+
+		invalidHash := chainhash.HashH([]byte("invalid"))
+		server.cacheSubtreeResult(&invalidHash, errors.NewSubtreeInvalidError("subtree root hash does not match"), nil)
+
+		transientHash := chainhash.HashH([]byte("transient"))
+		server.cacheSubtreeResult(&transientHash, errors.NewProcessingError("missing transactions", errors.ErrTxMissingParent), nil)
+
+		_, found, err := server.cachedSubtreeResult(context.Background(), &invalidHash, nil)
+		require.True(t, found)
+		assert.True(t, errors.Is(err, errors.ErrSubtreeInvalid))
+
+		_, found, err = server.cachedSubtreeResult(context.Background(), &transientHash, nil)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("invalid transactions are validated again", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.subtreeResultCache = newSubtreeResultCache(10)
+
+		subtreeHash := chainhash.HashH([]byte("invalid transaction"))
+
+		// the lock time of the transaction can be valid in a later block
+		failure := &DryRunTxResult{TxHash: chainhash.HashH([]byte("tx")), InputIndex: -1, Category: DryRunErrorTxInvalid, Error: "lock time not final"}
+		server.cacheSubtreeResult(&subtreeHash, changeSetFailureError("test", subtreeHash, failure), nil)
+
+		_, found, err := server.cachedSubtreeResult(context.Background(), &subtreeHash, nil)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		txErr := annotateTxError(errors.NewTxInvalidError("coinbase immature"), subtreeHash, failure.TxHash, 0, -1, nil)
+		server.cacheSubtreeResult(&subtreeHash, errors.NewSubtreeInvalidError("invalid transaction", txErr), nil)
+
+		_, found, err = server.cachedSubtreeResult(context.Background(), &subtreeHash, nil)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("a validated subtree returns its utxo delta", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.subtreeResultCache = newSubtreeResultCache(10)
+
+		subtree := newStoredSubtree(t, server)
+
+		validationDelta := &UTXODelta{
+			Spent:   []Outpoint{{TxHash: chainhash.HashH([]byte("parent")), Index: 1}},
+			Created: []Outpoint{{TxHash: chainhash.HashH([]byte("tx 1"))}},
+		}
+		server.cacheSubtreeResult(subtree.RootHash(), nil, validationDelta)
+
+		// the cache holds a copy of the delta
+		validationDelta.reset()
+
+		delta := &UTXODelta{Spent: []Outpoint{{TxHash: chainhash.HashH([]byte("stale"))}}}
+
+		_, found, err := server.cachedSubtreeResult(context.Background(), subtree.RootHash(), delta)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		assert.Equal(t, []Outpoint{{TxHash: chainhash.HashH([]byte("parent")), Index: 1}}, delta.Spent)
+		assert.Equal(t, []Outpoint{{TxHash: chainhash.HashH([]byte("tx 1"))}}, delta.Created)
+	})
+
+	t.Run("a validated subtree without its utxo delta is validated again for the delta", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.subtreeResultCache = newSubtreeResultCache(10)
+
+		subtree := newStoredSubtree(t, server)
+		server.cacheSubtreeResult(subtree.RootHash(), nil, nil)
+
+		_, found, err := server.cachedSubtreeResult(context.Background(), subtree.RootHash(), &UTXODelta{})
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		// callers not requesting the delta use the cached verdict
+		_, found, err = server.cachedSubtreeResult(context.Background(), subtree.RootHash(), nil)
+		require.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		subtree := newStoredSubtree(t, server)
+		server.cacheSubtreeResult(subtree.RootHash(), nil, nil)
+
+		_, found, err := server.cachedSubtreeResult(context.Background(), subtree.RootHash(), nil)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("a reorg invalidates the cache", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.subtreeResultCache = newSubtreeResultCache(10)
+
+		newHeader := func(prev *chainhash.Hash, nonce uint32) *model.BlockHeader {
+			return &model.BlockHeader{
+				Version:        1,
+				HashPrevBlock:  prev,
+				HashMerkleRoot: &chainhash.Hash{},
+				Bits:           model.NBit{},
+				Nonce:          nonce,
+			}
+		}
+
+		header1 := newHeader(&chainhash.Hash{}, 1)
+		header2 := newHeader(header1.Hash(), 2)
+		forkHeader := newHeader(header1.Hash(), 3)
+
+		blockchainMock := server.blockchainClient.(*blockchain.Mock)
+		blockchainMock.On("GetBlockHeaderIDs", mock.Anything, mock.Anything, mock.Anything).Return([]uint32{1}, nil)
+
+		updateBestBlock := func(header *model.BlockHeader) {
+			blockchainMock.On("GetBestBlockHeader", mock.Anything).Return(header, &model.BlockHeaderMeta{Height: 1}, nil).Once()
+			require.NoError(t, server.updateBestBlock(context.Background()))
+		}
+
+		subtreeHash := chainhash.HashH([]byte("subtree"))
+
+		updateBestBlock(header1)
+		server.cacheSubtreeResult(&subtreeHash, nil, nil)
+
+		// extending the best chain keeps the cache
+		updateBestBlock(header2)
+		assert.Equal(t, 1, server.subtreeResultCache.len())
+
+		// the same best block keeps the cache
+		updateBestBlock(header2)
+		assert.Equal(t, 1, server.subtreeResultCache.len())
+
+		// a best block on another fork clears the cache
+		updateBestBlock(forkHeader)
+		assert.Equal(t, 0, server.subtreeResultCache.len())
+	})
+}
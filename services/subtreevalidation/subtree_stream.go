@@ -0,0 +1,280 @@
+package subtreevalidation
+
+import (
+	"context"
+	"io"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+)
+
+// countingReader wraps an io.Reader and counts the bytes read
+type countingReader struct {
+	reader    io.Reader
+	bytesRead uint64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.bytesRead += uint64(n)
+
+	return n, err
+}
+
+// subtreeDataReader reads the transactions of a subtree one by one from its subtreeData stream, checking each
+// transaction against the hash of the subtree node at its index
+type subtreeDataReader struct {
+	subtree *subtreepkg.Subtree
+	reader  *countingReader
+
+	// txIndex is the index of the next transaction in the subtree, which is also the number of transactions read,
+	// including the coinbase placeholder
+	txIndex int
+}
+
+func newSubtreeDataReader(subtree *subtreepkg.Subtree, reader io.Reader) *subtreeDataReader {
+	r := &subtreeDataReader{
+		subtree: subtree,
+		reader:  &countingReader{reader: reader},
+	}
+
+	if len(subtree.Nodes) > 0 && subtree.Nodes[0].Hash.Equal(subtreepkg.CoinbasePlaceholderHashValue) {
+		r.txIndex = 1
+	}
+
+	return r
+}
+
+// next reads the next transaction from the stream.
+//
+// Returns:
+//   - *bt.Tx: The transaction, with its hash cached
+//   - error: io.EOF at the end of the stream, an error if the stream ends within a transaction, cannot be parsed,
+//     or the transaction does not match the subtree
+func (r *subtreeDataReader) next() (*bt.Tx, error) {
+	tx := &bt.Tx{}
+
+	bytesRead := r.reader.bytesRead
+
+	if _, err := tx.ReadFrom(r.reader); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			if r.reader.bytesRead == bytesRead {
+				// End of stream reached, between two transactions
+				return nil, io.EOF
+			}
+
+			// the cause is not wrapped, the truncated stream must not be mistaken for the end of the stream
+			return nil, errors.NewProcessingError("[readTransactionsFromSubtreeDataStream] error reading transaction at index %d: stream truncated after %d of its bytes: %v",
+				r.txIndex, r.reader.bytesRead-bytesRead, err)
+		}
+
+		return nil, errors.NewProcessingError("[readTransactionsFromSubtreeDataStream] error reading transaction", err)
+	}
+
+	if tx.IsCoinbase() && r.txIndex == 1 {
+		// we did get an unexpected coinbase transaction
+		// reset the index to 0 to check the coinbase
+		r.txIndex = 0
+	}
+
+	tx.SetTxHash(tx.TxIDChainHash()) // Cache the transaction hash to avoid recomputing it
+
+	// Basic sanity check: ensure the transaction hash matches the expected hash from the subtree
+	if r.txIndex >= r.subtree.Length() {
+		return nil, errors.NewProcessingError("[readTransactionsFromSubtreeDataStream] more transactions than expected in subtreeData")
+	}
+
+	if expectedHash := r.subtree.Nodes[r.txIndex].Hash; !expectedHash.Equal(*tx.TxIDChainHash()) {
+		return nil, errors.NewProcessingError("[readTransactionsFromSubtreeDataStream] transaction hash mismatch at index %d: expected %s, got %s", r.txIndex, expectedHash.String(), tx.TxIDChainHash().String())
+	}
+
+	r.txIndex++
+
+	return tx, nil
+}
+
+// ValidateSubtreeStream validates the transactions of a subtree of a block, reading them incrementally from the
+// subtreeData stream of the subtree instead of holding all transactions of the subtree in memory.
+//
+// The transactions are read in windows of StreamWindowSize transactions. The transactions of a window are
+// organized in dependency levels and validated level by level, the same way the transactions of the in-memory
+// path are, and released before the next window is read. The subtreeData is in the order of the subtree, in which
+// parents precede their children, so the parents of a window are validated in the window itself or in an earlier
+// window, and every transaction gets the same validation result as in the in-memory path.
+//
+// The stream is checked against the subtree: every transaction must match the hash of its subtree node, and the
+// stream must hold all transactions of the subtree. A stream truncated within a transaction fails with an error
+// naming the index of the transaction. The transactions of the windows before a read error are validated already.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - subtree: The subtree the transactions belong to
+//   - r: The subtreeData stream of the subtree
+//   - blockHeight: Height of the block the subtree belongs to
+//   - blockIds: IDs of the blocks on the current chain, to check the conflicting transactions against
+//   - baseURL: URL of the peer the subtree was received from, recorded for orphaned transactions
+//
+// Returns:
+//   - error: An error if the stream cannot be read or does not match the subtree, or any transaction failed validation
+func (u *Server) ValidateSubtreeStream(ctx context.Context, subtree *subtreepkg.Subtree, r io.Reader, blockHeight uint32,
+	blockIds map[uint32]bool, baseURL string) error {
+	ctx, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "ValidateSubtreeStream",
+		tracing.WithParentStat(u.stats),
+		tracing.WithDebugLogMessage(u.logger, "[ValidateSubtreeStream] called for subtree %s", subtree.RootHash().String()),
+	)
+	defer deferFn()
+
+	var results levelValidationResults
+
+	if err := u.validateSubtreeStream(ctx, subtree, r, blockHeight, blockIds, baseURL, &results); err != nil {
+		return err
+	}
+
+	return results.err()
+}
+
+// ValidateSubtreeStreamResult validates the transactions of a subtree of a block from the subtreeData stream of the
+// subtree, like ValidateSubtreeStream, but records the outcome of every transaction instead of failing on the first
+// invalid transaction. This lets the caller decide whether a single failing transaction fails the whole subtree or
+// is quarantined.
+//
+// The children of a failing transaction fail as well, they spend outputs that are not known, and are recorded as
+// TxStatusTxNotFound.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - subtree: The subtree the transactions belong to
+//   - r: The subtreeData stream of the subtree
+//   - blockHeight: Height of the block the subtree belongs to
+//   - blockIds: IDs of the blocks on the current chain, to check the conflicting transactions against
+//   - baseURL: URL of the peer the subtree was received from, recorded for orphaned transactions
+//
+// Returns:
+//   - *SubtreeValidationResult: The outcome of every transaction of the subtree
+//   - error: An error if the stream cannot be read or does not match the subtree, the outcome of the transactions
+//     validated before the error is returned with it
+func (u *Server) ValidateSubtreeStreamResult(ctx context.Context, subtree *subtreepkg.Subtree, r io.Reader, blockHeight uint32,
+	blockIds map[uint32]bool, baseURL string) (*SubtreeValidationResult, error) {
+	ctx, _, deferFn := tracing.Tracer("subtreevalidation").Start(ctx, "ValidateSubtreeStreamResult",
+		tracing.WithParentStat(u.stats),
+		tracing.WithDebugLogMessage(u.logger, "[ValidateSubtreeStreamResult] called for subtree %s", subtree.RootHash().String()),
+	)
+	defer deferFn()
+
+	txHashes := make([]chainhash.Hash, subtree.Length())
+	for idx, node := range subtree.Nodes {
+		txHashes[idx] = node.Hash
+	}
+
+	results := levelValidationResults{
+		txResults: newSubtreeValidationResult(*subtree.RootHash(), txHashes),
+	}
+
+	// record the dependency graph of the whole subtree, so the subtree can be re-validated incrementally
+	results.txResults.dependencies = newSubtreeDependencies(txHashes, blockHeight, blockIds, baseURL)
+
+	err := u.validateSubtreeStream(ctx, subtree, r, blockHeight, blockIds, baseURL, &results)
+
+	// the failing transactions are reported in the result, not as an error
+	results.recordPrevoutCacheHits()
+
+	return results.txResults, err
+}
+
+// validateSubtreeStream reads the transactions of the subtree from the stream in windows and validates them level
+// by level, tracking their outcome in the results
+func (u *Server) validateSubtreeStream(ctx context.Context, subtree *subtreepkg.Subtree, r io.Reader, blockHeight uint32,
+	blockIds map[uint32]bool, baseURL string, results *levelValidationResults) error {
+	// the windows are levelled one at a time, the transaction count of the whole subtree is checked up front
+	if err := u.checkSubtreeTxCount(subtree.Length()); err != nil {
+		return err
+	}
+
+	processedValidatorOptions, err := u.levelValidatorOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	windowSize := u.settings.SubtreeValidation.StreamWindowSize
+	if windowSize <= 0 {
+		// a single window holds all transactions of the subtree, as the in-memory path does
+		windowSize = max(1, subtree.Length())
+	}
+
+	var (
+		dataReader = newSubtreeDataReader(subtree, r)
+		sizeBucket = subtreeSizeBucket(subtree.Length())
+		window     = make([]missingTx, 0, min(windowSize, subtree.Length()))
+		txCount    int
+	)
+
+	validateWindow := func() error {
+		maxLevel, txsPerLevel, err := u.prepareTxsPerLevel(ctx, window)
+		if err != nil {
+			return errors.NewProcessingError("[ValidateSubtreeStream] failed to prepare transactions per level", err)
+		}
+
+		defer u.releaseTxsPerLevel(txsPerLevel)
+
+		observeTxsPerLevel(sizeBucket, maxLevel, txsPerLevel)
+
+		// the prevout cache only holds the transactions of the window, the parents in earlier windows are in the store
+		var prevoutCache *blockPrevoutCache
+		if u.settings.SubtreeValidation.BlockPrevoutCacheEnabled {
+			prevoutCache = newBlockPrevoutCache(len(window))
+		}
+
+		if err = u.validateLevels(ctx, maxLevel, txsPerLevel, sizeBucket, blockHeight, blockIds, baseURL, processedValidatorOptions, prevoutCache, results); err != nil {
+			return err
+		}
+
+		txCount += len(window)
+
+		// release the transactions of the window
+		clear(window)
+		window = window[:0]
+
+		return nil
+	}
+
+	for {
+		tx, err := dataReader.next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return errors.NewProcessingError("[ValidateSubtreeStream] failed to read transactions of subtree %s", subtree.RootHash().String(), err)
+		}
+
+		// the index of the transaction in the subtree, the reader already moved past the transaction
+		window = append(window, missingTx{tx: tx, idx: dataReader.txIndex - 1})
+
+		if results.txResults != nil && results.txResults.dependencies != nil {
+			results.txResults.dependencies.addTx(window[len(window)-1])
+		}
+
+		if len(window) >= windowSize {
+			if err = validateWindow(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(window) > 0 {
+		if err = validateWindow(); err != nil {
+			return err
+		}
+	}
+
+	if dataReader.txIndex != subtree.Length() {
+		return errors.NewProcessingError("[ValidateSubtreeStream] transaction count mismatch: expected %d, got %d", subtree.Length(), dataReader.txIndex)
+	}
+
+	u.logger.Debugf("[ValidateSubtreeStream] validated %d transactions of subtree %s", txCount, subtree.RootHash().String())
+
+	return nil
+}
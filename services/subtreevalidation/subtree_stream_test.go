@@ -0,0 +1,236 @@
+package subtreevalidation
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	subtreepkg "github.com/bsv-blockchain/go-subtree"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// orderCheckingValidator fails a transaction with a missing parent error when one of its parents in the subtree
+// was not validated before it, and records the validated transactions
+type orderCheckingValidator struct {
+	validator.MockValidatorClient
+
+	mu        sync.Mutex
+	inSubtree map[chainhash.Hash]struct{}
+	invalid   map[chainhash.Hash]struct{}
+	validated map[chainhash.Hash]struct{}
+}
+
+func newOrderCheckingValidator(txs []*bt.Tx, invalid ...*bt.Tx) *orderCheckingValidator {
+	v := &orderCheckingValidator{
+		inSubtree: make(map[chainhash.Hash]struct{}),
+		invalid:   make(map[chainhash.Hash]struct{}),
+		validated: make(map[chainhash.Hash]struct{}),
+	}
+
+	for _, tx := range txs {
+		v.inSubtree[*tx.TxIDChainHash()] = struct{}{}
+	}
+
+	for _, tx := range invalid {
+		v.invalid[*tx.TxIDChainHash()] = struct{}{}
+	}
+
+	return v
+}
+
+func (v *orderCheckingValidator) ValidateWithOptions(_ context.Context, tx *bt.Tx, _ uint32, _ *validator.Options) (*meta.Data, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, input := range tx.Inputs {
+		parentHash := *input.PreviousTxIDChainHash()
+
+		if _, inSubtree := v.inSubtree[parentHash]; !inSubtree {
+			continue
+		}
+
+		if _, validated := v.validated[parentHash]; !validated {
+			return nil, errors.NewTxMissingParentError("parent %s not validated", parentHash)
+		}
+	}
+
+	if _, invalid := v.invalid[*tx.TxIDChainHash()]; invalid {
+		return nil, errors.NewTxInvalidError("invalid transaction for testing")
+	}
+
+	v.validated[*tx.TxIDChainHash()] = struct{}{}
+
+	return &meta.Data{}, nil
+}
+
+func TestValidateSubtreeStream(t *testing.T) {
+	external1 := chainhash.HashH([]byte("external 1"))
+	external2 := chainhash.HashH([]byte("external 2"))
+
+	txA := newDependencyGraphTestTx(t, 2, bt.UTXO{TxIDHash: &external1, Vout: 0})
+	txB := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: txA.TxIDChainHash(), Vout: 0})
+	txC := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: txB.TxIDChainHash(), Vout: 0})
+	txD := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &external2, Vout: 0})
+	txE := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: txA.TxIDChainHash(), Vout: 1}, bt.UTXO{TxIDHash: txD.TxIDChainHash(), Vout: 0})
+
+	txs := []*bt.Tx{txA, txB, txC, txD, txE}
+
+	subtree, err := subtreepkg.NewTreeByLeafCount(8)
+	require.NoError(t, err)
+	require.NoError(t, subtree.AddCoinbaseNode())
+
+	var subtreeData bytes.Buffer
+
+	for i, tx := range txs {
+		require.NoError(t, subtree.AddNode(*tx.TxIDChainHash(), uint64(i), uint64(tx.Size()))) //nolint:gosec // G115: small test values
+		subtreeData.Write(tx.Bytes())
+	}
+
+	// validateInMemory validates the subtree the way the in-memory path does, reading all transactions first
+	validateInMemory := func(t *testing.T, v *orderCheckingValidator) error {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.validatorClient = v
+
+		var allTransactions []*bt.Tx
+
+		txCount, err := server.readTransactionsFromSubtreeDataStream(subtree, bytes.NewReader(subtreeData.Bytes()), &allTransactions)
+		require.NoError(t, err)
+		require.Equal(t, subtree.Length(), txCount)
+
+		return server.processTransactionsInLevels(context.Background(), allTransactions, 100, map[uint32]bool{}, "")
+	}
+
+	validateStream := func(t *testing.T, v *orderCheckingValidator, windowSize int, data []byte) error {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.validatorClient = v
+		server.settings.SubtreeValidation.StreamWindowSize = windowSize
+
+		return server.ValidateSubtreeStream(context.Background(), subtree, bytes.NewReader(data), 100, map[uint32]bool{}, "")
+	}
+
+	t.Run("identical results to the in-memory path", func(t *testing.T) {
+		inMemory := newOrderCheckingValidator(txs)
+		require.NoError(t, validateInMemory(t, inMemory))
+		require.Len(t, inMemory.validated, len(txs))
+
+		for _, windowSize := range []int{0, 1, 2, 3, len(txs), 100} {
+			streamed := newOrderCheckingValidator(txs)
+			require.NoError(t, validateStream(t, streamed, windowSize, subtreeData.Bytes()), "window size %d", windowSize)
+
+			assert.Equal(t, inMemory.validated, streamed.validated, "window size %d", windowSize)
+		}
+	})
+
+	t.Run("invalid transaction fails both paths", func(t *testing.T) {
+		err := validateInMemory(t, newOrderCheckingValidator(txs, txC))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxInvalid))
+
+		for _, windowSize := range []int{0, 1, 2} {
+			err = validateStream(t, newOrderCheckingValidator(txs, txC), windowSize, subtreeData.Bytes())
+			require.Error(t, err, "window size %d", windowSize)
+			assert.True(t, errors.Is(err, errors.ErrTxInvalid), "window size %d", windowSize)
+		}
+	})
+
+	t.Run("structured result records every transaction", func(t *testing.T) {
+		for _, windowSize := range []int{0, 1, 2} {
+			server, cleanup := setupTestServer(t)
+
+			server.validatorClient = newOrderCheckingValidator(txs, txB)
+			server.settings.SubtreeValidation.StreamWindowSize = windowSize
+			server.blockchainClient.(*blockchain.Mock).On("IsFSMCurrentState", mock.Anything, blockchain.FSMStateRUNNING).
+				Return(true, nil)
+
+			result, err := server.ValidateSubtreeStreamResult(context.Background(), subtree, bytes.NewReader(subtreeData.Bytes()), 100, map[uint32]bool{}, "")
+
+			cleanup()
+
+			require.NoError(t, err, "window size %d", windowSize)
+			require.Len(t, result.Transactions, subtree.Length())
+
+			statuses := make([]TxValidationStatus, 0, len(result.Transactions))
+			for idx, txResult := range result.Transactions {
+				assert.Equal(t, idx, txResult.Index)
+				assert.Equal(t, subtree.Nodes[idx].Hash, txResult.TxHash)
+
+				statuses = append(statuses, txResult.Status)
+			}
+
+			// the validation continues past the invalid txB, its child txC misses its parent
+			assert.Equal(t, []TxValidationStatus{
+				TxStatusNotValidated, TxStatusValid, TxStatusInvalid, TxStatusTxNotFound, TxStatusValid, TxStatusValid,
+			}, statuses, "window size %d", windowSize)
+
+			require.True(t, result.HasFailures())
+
+			firstFailure := result.FirstFailure()
+			require.NotNil(t, firstFailure)
+			assert.Equal(t, 2, firstFailure.Index)
+			assert.Equal(t, *txB.TxIDChainHash(), firstFailure.TxHash)
+			assert.True(t, errors.Is(firstFailure.Err, errors.ErrTxInvalid))
+
+			assert.Len(t, result.Failures(), 2)
+			assert.Error(t, result.Err())
+		}
+	})
+
+	t.Run("structured result of a valid subtree", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.validatorClient = newOrderCheckingValidator(txs)
+
+		result, err := server.ValidateSubtreeStreamResult(context.Background(), subtree, bytes.NewReader(subtreeData.Bytes()), 100, map[uint32]bool{}, "")
+		require.NoError(t, err)
+
+		assert.False(t, result.HasFailures())
+		assert.Nil(t, result.FirstFailure())
+		assert.NoError(t, result.Err())
+		assert.Equal(t, *subtree.RootHash(), result.SubtreeHash)
+	})
+
+	t.Run("stream truncated mid-transaction", func(t *testing.T) {
+		// the stream ends within txC, at index 3 of the subtree after the coinbase placeholder, txA and txB
+		truncatedLength := len(txA.Bytes()) + len(txB.Bytes()) + 10
+
+		v := newOrderCheckingValidator(txs)
+
+		err := validateStream(t, v, 1, subtreeData.Bytes()[:truncatedLength])
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error reading transaction at index 3: stream truncated after 10 of its bytes")
+
+		// the transactions of the windows before the truncation are validated
+		assert.Len(t, v.validated, 2)
+	})
+
+	t.Run("stream missing transactions", func(t *testing.T) {
+		truncatedLength := len(txA.Bytes()) + len(txB.Bytes())
+
+		err := validateStream(t, newOrderCheckingValidator(txs), 0, subtreeData.Bytes()[:truncatedLength])
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transaction count mismatch: expected 6, got 3")
+	})
+
+	t.Run("transaction not in the subtree", func(t *testing.T) {
+		var data bytes.Buffer
+		data.Write(txB.Bytes())
+
+		err := validateStream(t, newOrderCheckingValidator(txs), 0, data.Bytes())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transaction hash mismatch at index 1")
+	})
+}
@@ -209,7 +209,11 @@ func (x *CheckSubtreeFromBlockRequest) GetPreviousBlockHash() []byte {
 type CheckSubtreeFromBlockResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// blessed indicates if the subtree passes all validation criteria
-	Blessed       bool `protobuf:"varint,1,opt,name=blessed,proto3" json:"blessed,omitempty"`
+	Blessed bool `protobuf:"varint,1,opt,name=blessed,proto3" json:"blessed,omitempty"`
+	// max_level is the deepest dependency level of the transactions validated for the subtree
+	MaxLevel uint32 `protobuf:"varint,2,opt,name=max_level,json=maxLevel,proto3" json:"max_level,omitempty"`
+	// txs_per_level holds the number of transactions validated at each dependency level, empty when no transactions needed validating
+	TxsPerLevel   []uint32 `protobuf:"varint,3,rep,packed,name=txs_per_level,json=txsPerLevel,proto3" json:"txs_per_level,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -251,6 +255,20 @@ func (x *CheckSubtreeFromBlockResponse) GetBlessed() bool {
 	return false
 }
 
+func (x *CheckSubtreeFromBlockResponse) GetMaxLevel() uint32 {
+	if x != nil {
+		return x.MaxLevel
+	}
+	return 0
+}
+
+func (x *CheckSubtreeFromBlockResponse) GetTxsPerLevel() []uint32 {
+	if x != nil {
+		return x.TxsPerLevel
+	}
+	return nil
+}
+
 // CheckBlockSubtreesRequest defines the input parameters for checking subtrees in a block.
 type CheckBlockSubtreesRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -377,9 +395,11 @@ const file_services_subtreevalidation_subtreevalidation_api_subtreevalidation_ap
 	"\fblock_height\x18\x03 \x01(\rR\vblockHeight\x12\x1d\n" +
 	"\n" +
 	"block_hash\x18\x04 \x01(\fR\tblockHash\x12.\n" +
-	"\x13previous_block_hash\x18\x05 \x01(\fR\x11previousBlockHash\"9\n" +
+	"\x13previous_block_hash\x18\x05 \x01(\fR\x11previousBlockHash\"z\n" +
 	"\x1dCheckSubtreeFromBlockResponse\x12\x18\n" +
-	"\ablessed\x18\x01 \x01(\bR\ablessed\"e\n" +
+	"\ablessed\x18\x01 \x01(\bR\ablessed\x12\x1b\n" +
+	"\tmax_level\x18\x02 \x01(\rR\bmaxLevel\x12\"\n" +
+	"\rtxs_per_level\x18\x03 \x03(\rR\vtxsPerLevel\"e\n" +
 	"\x19CheckBlockSubtreesRequest\x12\x14\n" +
 	"\x05block\x18\x01 \x01(\fR\x05block\x12\x19\n" +
 	"\bbase_url\x18\x02 \x01(\tR\abaseUrl\x12\x17\n" +
@@ -47,6 +47,8 @@ func (u *Server) txmetaHandler(ctx context.Context, msg *kafka.KafkaMessage) err
 	txMetaBytes := m.Content
 
 	if delete {
+		u.removePreValidatedTransaction(*hash)
+
 		if err := u.DelTxMetaCache(ctx, hash); err != nil {
 			prometheusSubtreeValidationSetTXMetaCacheKafkaErrors.Inc()
 
@@ -82,6 +84,9 @@ func (u *Server) txmetaHandler(ctx context.Context, msg *kafka.KafkaMessage) err
 		return wrappedErr
 	}
 
+	// the transaction was validated by the validator of this node
+	u.AddPreValidatedTransactions(*hash)
+
 	prometheusSubtreeValidationSetTXMetaCacheKafka.Observe(float64(time.Since(startTime).Microseconds()) / 1_000_000)
 
 	return nil
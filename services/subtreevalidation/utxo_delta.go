@@ -0,0 +1,96 @@
+package subtreevalidation
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+)
+
+// Outpoint is an output of a transaction
+type Outpoint struct {
+	// TxHash is the hash of the transaction of the output
+	TxHash chainhash.Hash
+
+	// Index is the index of the output in the transaction
+	Index uint32
+}
+
+// UTXODelta holds the outpoints spent and created in the UTXO store by the validation of a subtree, so downstream
+// services do not need to derive them from the transactions of the subtree again.
+//
+// Only the transactions written to the UTXO store by the validation are part of the delta: the transactions of the
+// subtree that were already in the UTXO store were written before, and conflicting transactions are stored without
+// spending their inputs. Outputs that are not stored as UTXOs, e.g. zero satoshi OP_RETURN outputs, are not created.
+type UTXODelta struct {
+	// Spent holds the outpoints spent by the transactions, in subtree order and in input order within a transaction
+	Spent []Outpoint
+
+	// Created holds the outpoints created by the transactions, in subtree order and in output order within a
+	// transaction
+	Created []Outpoint
+}
+
+// reset empties the delta, it is a no-op on a nil delta
+func (d *UTXODelta) reset() {
+	if d == nil {
+		return
+	}
+
+	d.Spent = d.Spent[:0]
+	d.Created = d.Created[:0]
+}
+
+// set replaces the outpoints of the delta with the outpoints of other, an empty delta for a nil other. It is a
+// no-op on a nil delta.
+func (d *UTXODelta) set(other *UTXODelta) {
+	if d == nil {
+		return
+	}
+
+	d.reset()
+
+	if other != nil {
+		d.Spent = append(d.Spent, other.Spent...)
+		d.Created = append(d.Created, other.Created...)
+	}
+}
+
+// clone returns a copy of the delta, nil for a nil delta
+func (d *UTXODelta) clone() *UTXODelta {
+	if d == nil {
+		return nil
+	}
+
+	clone := &UTXODelta{}
+	clone.set(d)
+
+	return clone
+}
+
+// add adds the outpoints spent and created by the transactions, skipping the nil transactions. The transactions
+// must be in subtree order. It is a no-op on a nil delta.
+func (d *UTXODelta) add(txs []*bt.Tx, blockHeight uint32) {
+	if d == nil {
+		return
+	}
+
+	for _, tx := range txs {
+		if tx == nil {
+			continue
+		}
+
+		for _, input := range tx.Inputs {
+			d.Spent = append(d.Spent, Outpoint{TxHash: *input.PreviousTxIDChainHash(), Index: input.PreviousTxOutIndex})
+		}
+
+		txHash := *tx.TxIDChainHash()
+
+		for vout, output := range tx.Outputs {
+			if output == nil || !utxo.ShouldStoreOutputAsUTXO(tx.IsCoinbase(), output, blockHeight) {
+				continue
+			}
+
+			d.Created = append(d.Created, Outpoint{TxHash: txHash, Index: uint32(vout)}) //nolint:gosec // G115: the number of outputs fits a uint32
+		}
+	}
+}
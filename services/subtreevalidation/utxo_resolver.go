@@ -0,0 +1,42 @@
+package subtreevalidation
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+)
+
+// UTXOResolver resolves the metadata of the transactions of a subtree and of their parents, without depending on
+// a full UTXO store. Every utxo.Store implements it, the UTXO store of the server is the default resolver. Tests and
+// alternative stores can supply their own resolver with SetUTXOResolver, e.g. returning specific parents as present
+// or missing.
+type UTXOResolver interface {
+	// GetMeta returns the metadata of a single transaction, or an error wrapping ErrTxNotFound when it is not known.
+	GetMeta(ctx context.Context, hash *chainhash.Hash) (*meta.Data, error)
+
+	// BatchDecorate resolves the requested fields of a batch of transactions, setting the data, or the error of
+	// each transaction not found, on the items of the batch.
+	BatchDecorate(ctx context.Context, unresolvedMetaDataSlice []*utxo.UnresolvedMetaData, fields ...fields.FieldName) error
+}
+
+// SetUTXOResolver replaces the resolver of the transaction metadata and parents, nil restores the default of
+// resolving them in the UTXO store.
+func (u *Server) SetUTXOResolver(resolver UTXOResolver) {
+	u.utxoResolver = resolver
+}
+
+// resolver returns the resolver of the transaction metadata and parents, the UTXO store when none was set
+func (u *Server) resolver() UTXOResolver {
+	if u.utxoResolver != nil {
+		return u.utxoResolver
+	}
+
+	if u.utxoStore == nil {
+		return nil
+	}
+
+	return u.utxoStore
+}
@@ -0,0 +1,121 @@
+package subtreevalidation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUTXOResolver resolves the transactions it was given as present, all other transactions as missing
+type fakeUTXOResolver struct {
+	present map[chainhash.Hash]*meta.Data
+}
+
+func newFakeUTXOResolver(present ...chainhash.Hash) *fakeUTXOResolver {
+	r := &fakeUTXOResolver{present: make(map[chainhash.Hash]*meta.Data)}
+
+	for _, hash := range present {
+		r.present[hash] = &meta.Data{BlockIDs: []uint32{1}}
+	}
+
+	return r
+}
+
+func (r *fakeUTXOResolver) GetMeta(_ context.Context, hash *chainhash.Hash) (*meta.Data, error) {
+	if data, ok := r.present[*hash]; ok {
+		return data, nil
+	}
+
+	return nil, errors.NewTxNotFoundError("transaction %s not found", hash.String())
+}
+
+func (r *fakeUTXOResolver) BatchDecorate(ctx context.Context, unresolvedMetaDataSlice []*utxo.UnresolvedMetaData, _ ...fields.FieldName) error {
+	for _, item := range unresolvedMetaDataSlice {
+		item.Data, item.Err = r.GetMeta(ctx, &item.Hash)
+	}
+
+	return nil
+}
+
+func TestUTXOResolver(t *testing.T) {
+	presentParent := chainhash.HashH([]byte("present parent"))
+	absentParent := chainhash.HashH([]byte("absent parent"))
+
+	childOfPresent := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &presentParent, Vout: 0})
+	childOfAbsent := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: &absentParent, Vout: 0})
+	grandchildOfAbsent := newDependencyGraphTestTx(t, 1, bt.UTXO{TxIDHash: childOfAbsent.TxIDChainHash(), Vout: 0})
+
+	transactions := []missingTx{
+		{tx: childOfPresent, idx: 0},
+		{tx: childOfAbsent, idx: 1},
+		{tx: grandchildOfAbsent, idx: 2},
+	}
+
+	levelOf := func(txsPerLevel [][]missingTx, tx *bt.Tx) int {
+		for level, levelTxs := range txsPerLevel {
+			for _, mTx := range levelTxs {
+				if mTx.tx == tx {
+					return level
+				}
+			}
+		}
+
+		return -1
+	}
+
+	t.Run("child of an absent external parent is deferred", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.SetUTXOResolver(newFakeUTXOResolver(presentParent))
+
+		maxLevel, txsPerLevel, err := server.prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, levelOf(txsPerLevel, childOfPresent))
+
+		// the transactions depending on the absent parent are validated after all other transactions
+		assert.Equal(t, 1, levelOf(txsPerLevel, childOfAbsent))
+		assert.Equal(t, 2, levelOf(txsPerLevel, grandchildOfAbsent))
+		assert.Equal(t, uint32(2), maxLevel)
+	})
+
+	t.Run("all external parents present", func(t *testing.T) {
+		server, cleanup := setupTestServer(t)
+		defer cleanup()
+
+		server.SetUTXOResolver(newFakeUTXOResolver(presentParent, absentParent))
+
+		maxLevel, txsPerLevel, err := server.prepareTxsPerLevel(context.Background(), transactions)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, levelOf(txsPerLevel, childOfPresent))
+		assert.Equal(t, 0, levelOf(txsPerLevel, childOfAbsent))
+		assert.Equal(t, 1, levelOf(txsPerLevel, grandchildOfAbsent))
+		assert.Equal(t, uint32(1), maxLevel)
+	})
+
+	t.Run("the UTXO store is the default resolver", func(t *testing.T) {
+		server := &Server{}
+		assert.Nil(t, server.resolver())
+
+		store := &utxo.MockUtxostore{}
+		server.utxoStore = store
+		assert.Same(t, store, server.resolver())
+
+		resolver := newFakeUTXOResolver()
+		server.SetUTXOResolver(resolver)
+		assert.Same(t, resolver, server.resolver())
+
+		server.SetUTXOResolver(nil)
+		assert.Same(t, store, server.resolver())
+	})
+}
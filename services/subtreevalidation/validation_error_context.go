@@ -0,0 +1,55 @@
+package subtreevalidation
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+)
+
+// Error data keys of the context annotated on the errors of the transactions of a subtree, the index of the failing
+// input is annotated with validator.ErrDataInputIndex
+const (
+	// ErrDataSubtreeHash is the merkle root of the subtree of the failing transaction
+	ErrDataSubtreeHash = "subtree_hash"
+
+	// ErrDataTxID is the txid of the failing transaction
+	ErrDataTxID = "txid"
+
+	// ErrDataTxIndex is the index of the failing transaction in the subtree
+	ErrDataTxIndex = "tx_index"
+
+	// ErrDataLevel is the dependency level the failing transaction was assigned to
+	ErrDataLevel = "level"
+)
+
+// annotateTxError returns the error of a transaction of a subtree annotated with the subtree merkle root, the txid
+// and subtree index of the transaction, the index of the failing input when known, and the dependency level of the
+// transaction when it was assigned one, level is nil otherwise.
+//
+// The error is copied, not changed, and keeps its code, so it matches the same errors. Errors that are not teranode
+// errors are returned unchanged.
+func annotateTxError(err error, subtreeHash chainhash.Hash, txHash chainhash.Hash, txIdx int, inputIdx int, level *uint32) error {
+	var tErr *errors.Error
+	if !errors.As(err, &tErr) || tErr == nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		ErrDataSubtreeHash: subtreeHash.String(),
+		ErrDataTxID:        txHash.String(),
+		ErrDataTxIndex:     txIdx,
+	}
+
+	// the input index is set by the validator on the error of the input, deeper in the chain
+	if inputIdx >= 0 {
+		data[validator.ErrDataInputIndex] = inputIdx
+	} else if errInputIdx := errors.GetData(err, validator.ErrDataInputIndex); errInputIdx != nil {
+		data[validator.ErrDataInputIndex] = errInputIdx
+	}
+
+	if level != nil {
+		data[ErrDataLevel] = *level
+	}
+
+	return tErr.WithData(data)
+}
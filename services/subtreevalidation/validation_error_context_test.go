@@ -0,0 +1,69 @@
+package subtreevalidation
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotateTxError(t *testing.T) {
+	subtreeHash := chainhash.HashH([]byte("subtree"))
+	txHash := chainhash.HashH([]byte("tx"))
+
+	t.Run("validator error is annotated with the context of the transaction", func(t *testing.T) {
+		inputErr := errors.NewTxNotFoundError("parent not found")
+		inputErr.SetData(validator.ErrDataInputIndex, 2)
+
+		err := errors.NewTxMissingParentError("missing parent", inputErr)
+		level := uint32(4)
+
+		annotated := annotateTxError(err, subtreeHash, txHash, 7, -1, &level)
+
+		// the error type is unchanged
+		require.Error(t, annotated)
+		assert.ErrorIs(t, annotated, errors.ErrTxMissingParent)
+		assert.ErrorIs(t, annotated, errors.ErrTxNotFound)
+
+		assert.Equal(t, subtreeHash.String(), errors.GetData(annotated, ErrDataSubtreeHash))
+		assert.Equal(t, txHash.String(), errors.GetData(annotated, ErrDataTxID))
+		assert.Equal(t, 7, errors.GetData(annotated, ErrDataTxIndex))
+		assert.Equal(t, 2, errors.GetData(annotated, validator.ErrDataInputIndex))
+		assert.Equal(t, uint32(4), errors.GetData(annotated, ErrDataLevel))
+
+		// the original error, which may be shared, is not changed
+		assert.Nil(t, errors.GetData(err, ErrDataSubtreeHash))
+	})
+
+	t.Run("known input index and no level", func(t *testing.T) {
+		err := errors.NewSubtreeInvalidError("subtree is invalid")
+
+		annotated := annotateTxError(err, subtreeHash, txHash, 1, 0, nil)
+
+		assert.ErrorIs(t, annotated, errors.ErrSubtreeInvalid)
+		assert.Equal(t, 0, errors.GetData(annotated, validator.ErrDataInputIndex))
+		assert.Nil(t, errors.GetData(annotated, ErrDataLevel))
+	})
+
+	t.Run("deferred commit failure is annotated", func(t *testing.T) {
+		err := changeSetFailureError("ValidateSubtreeDeferred", subtreeHash, &DryRunTxResult{
+			Index:      3,
+			TxHash:     txHash,
+			InputIndex: 1,
+			Category:   DryRunErrorTxNotFound,
+			Error:      "parent transaction not found",
+		})
+
+		assert.ErrorIs(t, err, errors.ErrSubtreeInvalid)
+		assert.Equal(t, subtreeHash.String(), errors.GetData(err, ErrDataSubtreeHash))
+		assert.Equal(t, 3, errors.GetData(err, ErrDataTxIndex))
+		assert.Equal(t, 1, errors.GetData(err, validator.ErrDataInputIndex))
+	})
+
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		assert.Nil(t, annotateTxError(nil, subtreeHash, txHash, 0, -1, nil))
+	})
+}
@@ -0,0 +1,196 @@
+package subtreevalidation
+
+import (
+	"slices"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator"
+)
+
+// TxValidationStatus is the outcome of the validation of a single transaction of a subtree
+type TxValidationStatus int
+
+const (
+	// TxStatusNotValidated is a transaction that was not validated, e.g. the coinbase placeholder, or a transaction
+	// after the validation of the subtree stopped
+	TxStatusNotValidated TxValidationStatus = iota
+
+	// TxStatusValid is a transaction that passed validation, or was already validated before
+	TxStatusValid
+
+	// TxStatusTxNotFound is a transaction spending a transaction, or an output, that is not known
+	TxStatusTxNotFound
+
+	// TxStatusScriptInvalid is a transaction failing the verification of its scripts and signatures
+	TxStatusScriptInvalid
+
+	// TxStatusDoubleSpend is a transaction spending an output that is already spent
+	TxStatusDoubleSpend
+
+	// TxStatusPolicyViolation is a transaction breaking a policy rule
+	TxStatusPolicyViolation
+
+	// TxStatusInvalid is a transaction breaking a consensus rule other than the scripts
+	TxStatusInvalid
+
+	// TxStatusError is a transaction that could not be validated, e.g. because of a UTXO store error
+	TxStatusError
+)
+
+// String returns the name of the status.
+func (s TxValidationStatus) String() string {
+	switch s {
+	case TxStatusNotValidated:
+		return "not-validated"
+	case TxStatusValid:
+		return "valid"
+	case TxStatusTxNotFound:
+		return "tx-not-found"
+	case TxStatusScriptInvalid:
+		return "script-invalid"
+	case TxStatusDoubleSpend:
+		return "double-spend"
+	case TxStatusPolicyViolation:
+		return "policy-violation"
+	case TxStatusInvalid:
+		return "invalid"
+	case TxStatusError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Failed returns whether the status is a validation failure. Transactions that were not validated did not fail.
+func (s TxValidationStatus) Failed() bool {
+	return s != TxStatusNotValidated && s != TxStatusValid
+}
+
+// txValidationStatusFromError classifies the error of a failed transaction validation
+func txValidationStatusFromError(err error) TxValidationStatus {
+	switch {
+	case err == nil:
+		return TxStatusValid
+	case errors.Is(err, errors.ErrTxMissingParent), errors.Is(err, errors.ErrTxNotFound):
+		return TxStatusTxNotFound
+	case errors.Is(err, errors.ErrTxInvalidDoubleSpend), errors.Is(err, errors.ErrSpent):
+		return TxStatusDoubleSpend
+	case errors.Is(err, errors.ErrTxPolicy):
+		return TxStatusPolicyViolation
+	case validator.IsScriptValidationError(err):
+		return TxStatusScriptInvalid
+	case errors.Is(err, errors.ErrTxInvalid):
+		return TxStatusInvalid
+	default:
+		return TxStatusError
+	}
+}
+
+// TxValidationResult is the outcome of the validation of a single transaction of a subtree
+type TxValidationResult struct {
+	// Index is the index of the transaction in the subtree
+	Index int
+
+	// TxHash is the hash of the transaction
+	TxHash chainhash.Hash
+
+	// Status is the outcome of the validation
+	Status TxValidationStatus
+
+	// Err is the reason the transaction failed, nil when it did not fail
+	Err error
+}
+
+// SubtreeValidationResult records the outcome of the validation of every transaction of a subtree, which lets the
+// caller decide whether a single failing transaction fails the whole subtree, or is quarantined.
+type SubtreeValidationResult struct {
+	// SubtreeHash is the hash of the validated subtree
+	SubtreeHash chainhash.Hash
+
+	// Transactions holds the outcome of every transaction, indexed by the index of the transaction in the subtree
+	Transactions []TxValidationResult
+
+	// dependencies is the dependency graph of the subtree, recorded by a full validation of the subtree for
+	// IncrementalValidate
+	dependencies *subtreeDependencies
+}
+
+// newSubtreeValidationResult creates a result for a subtree of the given transaction hashes, with every
+// transaction not validated yet
+func newSubtreeValidationResult(subtreeHash chainhash.Hash, txHashes []chainhash.Hash) *SubtreeValidationResult {
+	result := &SubtreeValidationResult{
+		SubtreeHash:  subtreeHash,
+		Transactions: make([]TxValidationResult, len(txHashes)),
+	}
+
+	for idx, txHash := range txHashes {
+		result.Transactions[idx] = TxValidationResult{
+			Index:  idx,
+			TxHash: txHash,
+		}
+	}
+
+	return result
+}
+
+// record sets the outcome of the transaction at the given index from the error of its validation. Each index is
+// recorded by a single goroutine, so the transactions of a level can be recorded concurrently.
+func (r *SubtreeValidationResult) record(idx int, err error) {
+	if idx < 0 || idx >= len(r.Transactions) {
+		return
+	}
+
+	r.Transactions[idx].Status = txValidationStatusFromError(err)
+	r.Transactions[idx].Err = err
+}
+
+// clone returns a copy of the result, sharing the dependency graph, which is not changed after the full validation
+func (r *SubtreeValidationResult) clone() *SubtreeValidationResult {
+	return &SubtreeValidationResult{
+		SubtreeHash:  r.SubtreeHash,
+		Transactions: slices.Clone(r.Transactions),
+		dependencies: r.dependencies,
+	}
+}
+
+// HasFailures returns whether any transaction of the subtree failed validation.
+func (r *SubtreeValidationResult) HasFailures() bool {
+	return r.FirstFailure() != nil
+}
+
+// FirstFailure returns the failing transaction with the lowest index in the subtree, or nil when no transaction failed.
+func (r *SubtreeValidationResult) FirstFailure() *TxValidationResult {
+	for idx := range r.Transactions {
+		if r.Transactions[idx].Status.Failed() {
+			return &r.Transactions[idx]
+		}
+	}
+
+	return nil
+}
+
+// Failures returns all failing transactions, in subtree order.
+func (r *SubtreeValidationResult) Failures() []TxValidationResult {
+	failures := make([]TxValidationResult, 0)
+
+	for _, txResult := range r.Transactions {
+		if txResult.Status.Failed() {
+			failures = append(failures, txResult)
+		}
+	}
+
+	return failures
+}
+
+// Err returns an error wrapping the first failure when any transaction failed validation, for the callers that
+// fail the whole subtree on a single failing transaction.
+func (r *SubtreeValidationResult) Err() error {
+	failure := r.FirstFailure()
+	if failure == nil {
+		return nil
+	}
+
+	return errors.NewProcessingError("[SubtreeValidationResult][%s] %d transactions failed validation, first failure at index %d: %s is %s",
+		r.SubtreeHash.String(), len(r.Failures()), failure.Index, failure.TxHash.String(), failure.Status, failure.Err)
+}
@@ -0,0 +1,93 @@
+package subtreevalidation
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxValidationStatusFromError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected TxValidationStatus
+	}{
+		{"valid", nil, TxStatusValid},
+		{"missing parent", errors.NewTxMissingParentError("parent not found"), TxStatusTxNotFound},
+		{"tx not found", errors.NewTxNotFoundError("not found"), TxStatusTxNotFound},
+		{"double spend", errors.NewTxInvalidDoubleSpendError("double spend"), TxStatusDoubleSpend},
+		{"spent", errors.NewProcessingError("failed to spend", errors.ErrSpent), TxStatusDoubleSpend},
+		{"policy", errors.NewTxPolicyError("too large"), TxStatusPolicyViolation},
+		{"script", errors.NewProcessingError("[Validate][tx] error validating transaction scripts", errors.NewTxInvalidError("script failed")), TxStatusScriptInvalid},
+		{"invalid", errors.NewProcessingError("[Validate][tx] error validating transaction", errors.NewTxInvalidError("negative output")), TxStatusInvalid},
+		{"storage", errors.NewStorageError("connection refused"), TxStatusError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, txValidationStatusFromError(tt.err))
+		})
+	}
+}
+
+func TestSubtreeValidationResult(t *testing.T) {
+	txHashes := []chainhash.Hash{
+		chainhash.HashH([]byte("tx 0")),
+		chainhash.HashH([]byte("tx 1")),
+		chainhash.HashH([]byte("tx 2")),
+		chainhash.HashH([]byte("tx 3")),
+	}
+
+	t.Run("no failures", func(t *testing.T) {
+		result := newSubtreeValidationResult(chainhash.HashH([]byte("subtree")), txHashes)
+
+		// transactions that were not validated did not fail
+		result.record(1, nil)
+
+		assert.False(t, result.HasFailures())
+		assert.Nil(t, result.FirstFailure())
+		assert.Empty(t, result.Failures())
+		assert.NoError(t, result.Err())
+	})
+
+	t.Run("first failure in subtree order", func(t *testing.T) {
+		result := newSubtreeValidationResult(chainhash.HashH([]byte("subtree")), txHashes)
+
+		result.record(0, nil)
+		result.record(3, errors.NewTxPolicyError("too large"))
+		result.record(2, errors.NewTxMissingParentError("parent not found"))
+		result.record(1, nil)
+
+		// out of range indexes are ignored
+		result.record(4, errors.NewTxInvalidError("invalid"))
+
+		require.True(t, result.HasFailures())
+
+		firstFailure := result.FirstFailure()
+		require.NotNil(t, firstFailure)
+		assert.Equal(t, 2, firstFailure.Index)
+		assert.Equal(t, txHashes[2], firstFailure.TxHash)
+		assert.Equal(t, TxStatusTxNotFound, firstFailure.Status)
+		assert.True(t, errors.Is(firstFailure.Err, errors.ErrTxMissingParent))
+
+		failures := result.Failures()
+		require.Len(t, failures, 2)
+		assert.Equal(t, TxStatusPolicyViolation, failures[1].Status)
+
+		err := result.Err()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxMissingParent))
+		assert.Contains(t, err.Error(), "2 transactions failed validation, first failure at index 2")
+	})
+
+	t.Run("status names", func(t *testing.T) {
+		assert.Equal(t, "valid", TxStatusValid.String())
+		assert.Equal(t, "tx-not-found", TxStatusTxNotFound.String())
+		assert.Equal(t, "script-invalid", TxStatusScriptInvalid.String())
+		assert.Equal(t, "double-spend", TxStatusDoubleSpend.String())
+		assert.Equal(t, "policy-violation", TxStatusPolicyViolation.String())
+	})
+}
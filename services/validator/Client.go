@@ -201,12 +201,13 @@ func (c *Client) ValidateWithOptions(ctx context.Context, tx *bt.Tx, blockHeight
 	if c.batchSize == 0 {
 		// Non-batch mode: direct validation
 		response, err := c.client.ValidateTransaction(ctx, &validator_api.ValidateTransactionRequest{
-			TransactionData:      tx.SerializeBytes(),
-			BlockHeight:          blockHeight,
-			SkipUtxoCreation:     &validationOptions.SkipUtxoCreation,
-			AddTxToBlockAssembly: &validationOptions.AddTXToBlockAssembly,
-			SkipPolicyChecks:     &validationOptions.SkipPolicyChecks,
-			CreateConflicting:    &validationOptions.CreateConflicting,
+			TransactionData:        tx.SerializeBytes(),
+			BlockHeight:            blockHeight,
+			SkipUtxoCreation:       &validationOptions.SkipUtxoCreation,
+			AddTxToBlockAssembly:   &validationOptions.AddTXToBlockAssembly,
+			SkipPolicyChecks:       &validationOptions.SkipPolicyChecks,
+			CreateConflicting:      &validationOptions.CreateConflicting,
+			SkipScriptVerification: &validationOptions.SkipScriptVerification,
 		})
 		if err != nil {
 			c.logger.Errorf("[ValidateWithOptions] failed to validate non-batched transaction: %v", err)
@@ -227,12 +228,13 @@ func (c *Client) ValidateWithOptions(ctx context.Context, tx *bt.Tx, blockHeight
 	doneCh := make(chan validateBatchResponse)
 	c.batcher.Put(&batchItem{
 		req: &validator_api.ValidateTransactionRequest{
-			TransactionData:      tx.SerializeBytes(),
-			BlockHeight:          blockHeight,
-			SkipUtxoCreation:     &validationOptions.SkipUtxoCreation,
-			AddTxToBlockAssembly: &validationOptions.AddTXToBlockAssembly,
-			SkipPolicyChecks:     &validationOptions.SkipPolicyChecks,
-			CreateConflicting:    &validationOptions.CreateConflicting,
+			TransactionData:        tx.SerializeBytes(),
+			BlockHeight:            blockHeight,
+			SkipUtxoCreation:       &validationOptions.SkipUtxoCreation,
+			AddTxToBlockAssembly:   &validationOptions.AddTXToBlockAssembly,
+			SkipPolicyChecks:       &validationOptions.SkipPolicyChecks,
+			CreateConflicting:      &validationOptions.CreateConflicting,
+			SkipScriptVerification: &validationOptions.SkipScriptVerification,
 		},
 		done: doneCh,
 	})
@@ -336,10 +338,11 @@ func (c *Client) handleBatchHTTPFallback(ctx context.Context, batch []*batchItem
 
 		// Create options from the request
 		options := &Options{
-			SkipUtxoCreation:     *txReq.SkipUtxoCreation,
-			AddTXToBlockAssembly: *txReq.AddTxToBlockAssembly,
-			SkipPolicyChecks:     *txReq.SkipPolicyChecks,
-			CreateConflicting:    *txReq.CreateConflicting,
+			SkipUtxoCreation:       *txReq.SkipUtxoCreation,
+			AddTXToBlockAssembly:   *txReq.AddTxToBlockAssembly,
+			SkipPolicyChecks:       *txReq.SkipPolicyChecks,
+			CreateConflicting:      *txReq.CreateConflicting,
+			SkipScriptVerification: txReq.GetSkipScriptVerification(),
 		}
 
 		// Try HTTP fallback for this individual transaction
@@ -409,6 +412,10 @@ func (c *Client) validateTransactionViaHTTP(ctx context.Context, tx *bt.Tx, bloc
 		queryParams.Add("createConflicting", "true")
 	}
 
+	if validationOptions.SkipScriptVerification {
+		queryParams.Add("skipScriptVerification", "true")
+	}
+
 	if blockHeight > 0 {
 		queryParams.Add("blockHeight", fmt.Sprintf("%d", blockHeight))
 	}
@@ -96,13 +96,17 @@ func newScriptVerifierGoBDK(l ulogger.Logger, po *settings.PolicySettings, pa *c
 		l.Fatalf("unable to create script engine for network %v", network)
 	}
 
+	// the engine applies the rules of the height itself, it is configured with the activation heights of the
+	// same resolver the other interpreters take their rules from
+	rules := NewConsensusRulesResolver(pa)
+
 	// #nosec G115 -- blockHeight won't overflow
-	if err := se.SetGenesisActivationHeight(int32(pa.GenesisActivationHeight)); err != nil {
+	if err := se.SetGenesisActivationHeight(int32(rules.GenesisActivationHeight())); err != nil {
 		panic(err)
 	}
 
 	// #nosec G115 -- blockHeight won't overflow
-	if err := se.SetChronicleActivationHeight(int32(pa.ChronicleActivationHeight)); err != nil {
+	if err := se.SetChronicleActivationHeight(int32(rules.ChronicleActivationHeight())); err != nil {
 		panic(err)
 	}
 
@@ -33,6 +33,7 @@ func newScriptVerifierGoBt(l ulogger.Logger, po *settings.PolicySettings, pa *ch
 		logger: l,
 		policy: po,
 		params: pa,
+		rules:  NewConsensusRulesResolver(pa),
 	}
 }
 
@@ -41,6 +42,7 @@ type scriptVerifierGoBt struct {
 	logger ulogger.Logger
 	policy *settings.PolicySettings
 	params *chaincfg.Params
+	rules  *ConsensusRulesResolver
 }
 
 // VerifyScript implements script verification using the Go-BT library
@@ -79,6 +81,8 @@ func (v *scriptVerifierGoBt) VerifyScript(tx *bt.Tx, blockHeight uint32, consens
 	// TODO add the utxo heights to the tx verifier
 	_ = utxoHeights
 
+	rules := v.rules.Rules(blockHeight)
+
 	// Verify each input's script
 	for i, in := range tx.Inputs {
 		prevOutput := &bt.Output{
@@ -91,12 +95,12 @@ func (v *scriptVerifierGoBt) VerifyScript(tx *bt.Tx, blockHeight uint32, consens
 		opts = append(opts, interpreter.WithTx(tx, i, prevOutput))
 
 		// Add UAHF fork ID if after fork height
-		if blockHeight > v.params.UahfForkHeight {
+		if rules.ForkID {
 			opts = append(opts, interpreter.WithForkID())
 		}
 
 		// Add Genesis activation options if after genesis height
-		if blockHeight >= v.params.GenesisActivationHeight {
+		if rules.Genesis {
 			opts = append(opts, interpreter.WithAfterGenesis())
 		}
 
@@ -104,7 +108,7 @@ func (v *scriptVerifierGoBt) VerifyScript(tx *bt.Tx, blockHeight uint32, consens
 
 		// Execute script verification
 		if err = interpreter.NewEngine().Execute(opts...); err != nil {
-			return errors.NewTxInvalidError("script execution error", err)
+			return inputError(errors.NewTxInvalidError("script execution error", err), i)
 		}
 	}
 
@@ -41,6 +41,7 @@ func newScriptVerifierGoSDK(l ulogger.Logger, po *settings.PolicySettings, pa *c
 		logger: l,
 		policy: po,
 		params: pa,
+		rules:  NewConsensusRulesResolver(pa),
 	}
 }
 
@@ -49,6 +50,7 @@ type scriptVerifierGoSDK struct {
 	logger ulogger.Logger
 	policy *settings.PolicySettings
 	params *chaincfg.Params
+	rules  *ConsensusRulesResolver
 }
 
 // VerifyScript implements script verification using the Go-SDK
@@ -87,6 +89,8 @@ func (v *scriptVerifierGoSDK) VerifyScript(tx *bt.Tx, blockHeight uint32, consen
 	// TODO add the utxo heights to the tx verifier
 	_ = utxoHeights
 
+	rules := v.rules.Rules(blockHeight)
+
 	sdkTx := goBt2GoSDKTransaction(tx)
 	// sdkTx, _ := transaction.NewTransactionFromBytes(tx.Bytes())
 
@@ -99,18 +103,18 @@ func (v *scriptVerifierGoSDK) VerifyScript(tx *bt.Tx, blockHeight uint32, consen
 		opts := make([]interpreter_sdk.ExecutionOptionFunc, 0, 3)
 		opts = append(opts, interpreter_sdk.WithTx(sdkTx, i, prevOutput))
 
-		if blockHeight > v.params.UahfForkHeight {
+		if rules.ForkID {
 			opts = append(opts, interpreter_sdk.WithForkID())
 		}
 
-		if blockHeight >= v.params.GenesisActivationHeight {
+		if rules.Genesis {
 			opts = append(opts, interpreter_sdk.WithAfterGenesis())
 		}
 
 		// opts = append(opts, interpreter.WithDebugger(&LogDebugger{}),
 
 		if err = interpreter_sdk.NewEngine().Execute(opts...); err != nil {
-			return errors.NewTxInvalidError("script execution error", err)
+			return inputError(errors.NewTxInvalidError("script execution error", err), i)
 		}
 	}
 
@@ -331,6 +331,14 @@ func (v *Server) Start(ctx context.Context, readyCh chan<- struct{}) error {
 	}
 
 	kafkaMessageHandler := func(msg *kafka.KafkaMessage) error {
+		msgCtx := ctx
+
+		for _, header := range msg.Headers {
+			if header != nil && string(header.Key) == tracing.CorrelationIDHeader {
+				msgCtx = v.correlationContext(ctx, string(header.Value))
+			}
+		}
+
 		var kafkaMsg kafkamessage.KafkaTxValidationTopicMessage
 		if err := proto.Unmarshal(msg.Value, &kafkaMsg); err != nil {
 			v.logger.Errorf("Failed to unmarshal kafka message: %v", err)
@@ -353,10 +361,15 @@ func (v *Server) Start(ctx context.Context, readyCh chan<- struct{}) error {
 			AddTXToBlockAssembly: kafkaMsg.Options.AddTXToBlockAssembly,
 			SkipPolicyChecks:     kafkaMsg.Options.SkipPolicyChecks,
 			CreateConflicting:    kafkaMsg.Options.CreateConflicting,
+			BacklogExempt:        true,
 		}
 
+		// a message consumed from Kafka is already queued, it counts towards the backlog but is never rejected
+		addBacklog(1)
+		defer releaseBacklog(1)
+
 		// should not pass in a height when validating from Kafka, should just be current utxo store height
-		if _, err = v.validator.ValidateWithOptions(ctx, tx, height, options); err != nil {
+		if _, err = v.validator.ValidateWithOptions(msgCtx, tx, height, options); err != nil {
 			prometheusInvalidTransactions.Inc()
 			v.logger.Errorf("[Validator] Invalid tx: %s", err)
 
@@ -385,6 +398,24 @@ func (v *Server) Start(ctx context.Context, readyCh chan<- struct{}) error {
 	return nil
 }
 
+// correlationContext returns a context carrying the correlation id received with a transaction, which is passed on
+// to block assembly and logged at every stage of the validation of the transaction. The id is ignored when
+// correlation ids are disabled.
+//
+// Parameters:
+//   - ctx: Context of the request
+//   - id: Correlation id received with the request, may be empty
+//
+// Returns:
+//   - context.Context: Context carrying the correlation id
+func (v *Server) correlationContext(ctx context.Context, id string) context.Context {
+	if !v.settings.TracingCorrelationIDsEnabled {
+		return ctx
+	}
+
+	return tracing.ContextWithCorrelationID(ctx, id)
+}
+
 // Stop gracefully shuts down the validator server and all associated components.
 // This method performs an orderly shutdown of all server resources, including Kafka
 // producers/consumers and any background tasks. It sends termination signals to Kafka
@@ -428,7 +459,12 @@ func (v *Server) Stop(_ context.Context) error {
 //   - *validator_api.ValidateTransactionResponse: Validation results including success status
 //   - error: Any validation errors wrapped appropriately for gRPC transmission
 func (v *Server) ValidateTransaction(ctx context.Context, req *validator_api.ValidateTransactionRequest) (*validator_api.ValidateTransactionResponse, error) {
-	response, err := v.validateTransaction(ctx, req)
+	if err := acquireBacklog(v.settings.Validator.MaxBacklog, 1); err != nil {
+		return nil, errors.WrapGRPC(err)
+	}
+	defer releaseBacklog(1)
+
+	response, err := v.validateTransaction(v.correlationContext(ctx, tracing.IncomingCorrelationID(ctx)), req)
 	return response, errors.WrapGRPC(err)
 }
 
@@ -483,6 +519,13 @@ func (v *Server) validateTransaction(ctx context.Context, req *validator_api.Val
 		validationOptions.CreateConflicting = *req.CreateConflicting
 	}
 
+	if req.SkipScriptVerification != nil {
+		validationOptions.SkipScriptVerification = *req.SkipScriptVerification
+	}
+
+	// the callers have already added the transaction to the validation backlog
+	validationOptions.BacklogExempt = true
+
 	txMetaData, err := v.validator.ValidateWithOptions(ctx, tx, req.BlockHeight, validationOptions)
 	if err != nil {
 		prometheusInvalidTransactions.Inc()
@@ -537,6 +580,8 @@ func (v *Server) ValidateTransactionBatch(ctx context.Context, req *validator_ap
 	)
 	defer deferFn()
 
+	ctx = v.correlationContext(ctx, tracing.IncomingCorrelationID(ctx))
+
 	g, gCtx := errgroup.WithContext(ctx)
 
 	// we create a slice for all transactions we just batched, in the same order as we got them
@@ -546,7 +591,17 @@ func (v *Server) ValidateTransactionBatch(ctx context.Context, req *validator_ap
 	for idx, reqItem := range req.GetTransactions() {
 		idx, reqItem := idx, reqItem
 
+		// every transaction is added to the backlog on its own, the transactions that do not fit are rejected
+		if err := acquireBacklog(v.settings.Validator.MaxBacklog, 1); err != nil {
+			errReasons[idx] = errors.Wrap(err)
+			continue
+		}
+
+		// the checks of the validation wait for a slot of the node wide validation limiter, the batch goroutines
+		// do not hold a slot while waiting for them
 		g.Go(func() error {
+			defer releaseBacklog(1)
+
 			validatorResponse, err := v.validateTransaction(gCtx, reqItem)
 			metaData[idx] = validatorResponse.Metadata
 			errReasons[idx] = errors.Wrap(err)
@@ -685,6 +740,11 @@ func extractValidationParams(c echo.Context) (uint32, *Options) {
 		options.CreateConflicting = boolVal
 	}
 
+	if skipScriptVerificationStr := c.QueryParam("skipScriptVerification"); skipScriptVerificationStr != "" {
+		boolVal := skipScriptVerificationStr == trueString || skipScriptVerificationStr == "1"
+		options.SkipScriptVerification = boolVal
+	}
+
 	return blockHeight, options
 }
 
@@ -710,6 +770,7 @@ func extractValidationParams(c echo.Context) (uint32, *Options) {
 //   - 200 OK: Transaction is valid
 //   - 400 Bad Request: Invalid request body
 //   - 500 Internal Server Error: Validation failed with specific reason
+//   - 503 Service Unavailable: The validation backlog is full
 func (v *Server) handleSingleTx(ctx context.Context) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		body, err := io.ReadAll(c.Request().Body)
@@ -722,16 +783,22 @@ func (v *Server) handleSingleTx(ctx context.Context) echo.HandlerFunc {
 
 		// Create the request with transaction data and parameters
 		req := &validator_api.ValidateTransactionRequest{
-			TransactionData:      body,
-			BlockHeight:          blockHeight,
-			SkipUtxoCreation:     &options.SkipUtxoCreation,
-			AddTxToBlockAssembly: &options.AddTXToBlockAssembly,
-			SkipPolicyChecks:     &options.SkipPolicyChecks,
-			CreateConflicting:    &options.CreateConflicting,
+			TransactionData:        body,
+			BlockHeight:            blockHeight,
+			SkipUtxoCreation:       &options.SkipUtxoCreation,
+			AddTxToBlockAssembly:   &options.AddTXToBlockAssembly,
+			SkipPolicyChecks:       &options.SkipPolicyChecks,
+			CreateConflicting:      &options.CreateConflicting,
+			SkipScriptVerification: &options.SkipScriptVerification,
+		}
+
+		if err = acquireBacklog(v.settings.Validator.MaxBacklog, 1); err != nil {
+			return c.String(http.StatusServiceUnavailable, "[handleSingleTx] "+err.Error())
 		}
+		defer releaseBacklog(1)
 
 		// Process the transaction and return appropriate response
-		response, err := v.validateTransaction(ctx, req)
+		response, err := v.validateTransaction(v.correlationContext(ctx, c.Request().Header.Get(tracing.CorrelationIDHeader)), req)
 		if err != nil {
 			return c.String(http.StatusInternalServerError, "[handleSingleTx] Failed to process transaction: "+err.Error())
 		}
@@ -768,11 +835,14 @@ func (v *Server) handleSingleTx(ctx context.Context) echo.HandlerFunc {
 //   - 200 OK: All transactions are valid
 //   - 400 Bad Request: Invalid request body or transaction format
 //   - 500 Internal Server Error: Validation failed with specific reason
+//   - 503 Service Unavailable: The validation backlog is full
 func (v *Server) handleMultipleTx(ctx context.Context) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		// Extract validation parameters from query string
 		blockHeight, options := extractValidationParams(c)
 
+		reqCtx := v.correlationContext(ctx, c.Request().Header.Get(tracing.CorrelationIDHeader))
+
 		// Read transactions with the bt reader in a loop
 		for {
 			tx := &bt.Tx{}
@@ -790,15 +860,23 @@ func (v *Server) handleMultipleTx(ctx context.Context) echo.HandlerFunc {
 
 			// Process the transaction
 			req := &validator_api.ValidateTransactionRequest{
-				TransactionData:      tx.SerializeBytes(),
-				BlockHeight:          blockHeight,
-				SkipUtxoCreation:     &options.SkipUtxoCreation,
-				AddTxToBlockAssembly: &options.AddTXToBlockAssembly,
-				SkipPolicyChecks:     &options.SkipPolicyChecks,
-				CreateConflicting:    &options.CreateConflicting,
+				TransactionData:        tx.SerializeBytes(),
+				BlockHeight:            blockHeight,
+				SkipUtxoCreation:       &options.SkipUtxoCreation,
+				AddTxToBlockAssembly:   &options.AddTXToBlockAssembly,
+				SkipPolicyChecks:       &options.SkipPolicyChecks,
+				CreateConflicting:      &options.CreateConflicting,
+				SkipScriptVerification: &options.SkipScriptVerification,
 			}
 
-			response, err := v.validateTransaction(ctx, req)
+			if err = acquireBacklog(v.settings.Validator.MaxBacklog, 1); err != nil {
+				return c.String(http.StatusServiceUnavailable, "[handleMultipleTx] "+err.Error())
+			}
+
+			response, err := v.validateTransaction(reqCtx, req)
+
+			releaseBacklog(1)
+
 			if err != nil {
 				return c.String(http.StatusInternalServerError, "[handleMultipleTx] Failed to process transaction: "+err.Error())
 			}
@@ -261,7 +261,7 @@ func TestServerValidateTransaction(t *testing.T) {
 		server := NewServer(logger, tSettings, nil, nil, nil, nil, nil, nil)
 
 		txid, _ := chainhash.NewHashFromStr("63f7f771376f9f9369e650d7a72d1f0328c2e5582eb3381b913a4a36dc78ec6e")
-		server.validator = &TestMockValidator{
+		mockValidator := &TestMockValidator{
 			validateTxFunc: func(ctx context.Context, tx *bt.Tx) (*meta.Data, error) {
 				return &meta.Data{
 					Fee:         32279815860,
@@ -270,25 +270,36 @@ func TestServerValidateTransaction(t *testing.T) {
 				}, nil
 			},
 		}
+		server.validator = mockValidator
 
 		skipUtxo := true
 		addToBlock := true
 		skipPolicy := true
 		createConflict := true
+		skipScripts := true
 
 		req := &validator_api.ValidateTransactionRequest{
-			TransactionData:      sampleTx,
-			BlockHeight:          100,
-			SkipUtxoCreation:     &skipUtxo,
-			AddTxToBlockAssembly: &addToBlock,
-			SkipPolicyChecks:     &skipPolicy,
-			CreateConflicting:    &createConflict,
+			TransactionData:        sampleTx,
+			BlockHeight:            100,
+			SkipUtxoCreation:       &skipUtxo,
+			AddTxToBlockAssembly:   &addToBlock,
+			SkipPolicyChecks:       &skipPolicy,
+			CreateConflicting:      &createConflict,
+			SkipScriptVerification: &skipScripts,
 		}
 
 		response, err := server.ValidateTransaction(context.Background(), req)
 		require.NoError(t, err)
 		require.NotNil(t, response)
 		require.True(t, response.Valid)
+
+		// the options of the request are passed to the validator
+		require.NotNil(t, mockValidator.validationOptions)
+		require.True(t, mockValidator.validationOptions.SkipUtxoCreation)
+		require.True(t, mockValidator.validationOptions.AddTXToBlockAssembly)
+		require.True(t, mockValidator.validationOptions.SkipPolicyChecks)
+		require.True(t, mockValidator.validationOptions.CreateConflicting)
+		require.True(t, mockValidator.validationOptions.SkipScriptVerification)
 	})
 
 	t.Run("validation error", func(t *testing.T) {
@@ -486,10 +497,11 @@ func TestExtractValidationParams(t *testing.T) {
 		require.True(t, options.AddTXToBlockAssembly) // Default is true
 		require.False(t, options.SkipPolicyChecks)
 		require.False(t, options.CreateConflicting)
+		require.False(t, options.SkipScriptVerification)
 	})
 
 	t.Run("all parameters true", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodPost, "/tx?blockHeight=100&skipUtxoCreation=true&addTxToBlockAssembly=true&skipPolicyChecks=true&createConflicting=true", nil)
+		req := httptest.NewRequest(http.MethodPost, "/tx?blockHeight=100&skipUtxoCreation=true&addTxToBlockAssembly=true&skipPolicyChecks=true&createConflicting=true&skipScriptVerification=true", nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
 
@@ -499,6 +511,7 @@ func TestExtractValidationParams(t *testing.T) {
 		require.True(t, options.AddTXToBlockAssembly)
 		require.True(t, options.SkipPolicyChecks)
 		require.True(t, options.CreateConflicting)
+		require.True(t, options.SkipScriptVerification)
 	})
 
 	t.Run("parameters with 1", func(t *testing.T) {
@@ -412,6 +412,9 @@ func TestHTTPServerHandlers(t *testing.T) {
 // TestMockValidator provides a test double for validator functionality.
 type TestMockValidator struct {
 	validateTxFunc func(ctx context.Context, tx *bt.Tx) (*meta.Data, error)
+
+	// validationOptions are the options of the last ValidateWithOptions call
+	validationOptions *Options
 }
 
 func (m *TestMockValidator) Init(ctx context.Context) error {
@@ -447,6 +450,8 @@ func (m *TestMockValidator) Validate(ctx context.Context, tx *bt.Tx, blockHeight
 }
 
 func (m *TestMockValidator) ValidateWithOptions(ctx context.Context, tx *bt.Tx, blockHeight uint32, validationOptions *Options) (*meta.Data, error) {
+	m.validationOptions = validationOptions
+
 	if m.validateTxFunc != nil {
 		return m.validateTxFunc(ctx, tx)
 	}
@@ -52,6 +52,17 @@ const (
 	TxInterpreterGoBDK TxInterpreter = "GoBDK"
 )
 
+// ErrDataInputIndex is the error data key holding the index of the failing input, set on the validation errors
+// specific to one input of a transaction
+const ErrDataInputIndex = "input_index"
+
+// inputError annotates a validation error specific to one input of a transaction with the index of the input.
+func inputError(err *errors.Error, index int) *errors.Error {
+	err.SetData(ErrDataInputIndex, index)
+
+	return err
+}
+
 // TxValidatorI defines the interface for transaction validation operations.
 // This interface serves as the contract for all transaction validators, abstracting
 // the implementation details from the rest of the system. This enables different
@@ -185,29 +196,53 @@ func (tv *TxValidator) ValidateTransaction(tx *bt.Tx, blockHeight uint32, utxoHe
 	//
 	// Each node will verify every transaction against a long checklist of criteria:
 	//
+	// Every check reports its error through validationOptions.fail, which ends the validation with the error, or
+	// collects it in the stage of the check and continues with the next check in diagnostic mode.
+	//
 	txSize := tx.Size()
 
+	rules := tv.consensusRules(blockHeight)
+
 	// 1) Neither lists of inputs nor outputs are empty
 	if len(tx.Inputs) == 0 || len(tx.Outputs) == 0 {
-		return errors.NewTxInvalidError("transaction has no inputs or outputs")
+		if err := validationOptions.fail(DiagnosticStageStructural, errors.NewTxInvalidError("transaction has no inputs or outputs")); err != nil {
+			return err
+		}
 	}
 
 	// 2) The transaction size in bytes is less than maxtxsizepolicy.
 	if !validationOptions.SkipPolicyChecks {
-		if err := tv.checkTxSize(txSize); err != nil {
+		if err := validationOptions.fail(DiagnosticStagePolicy, tv.checkTxSize(txSize)); err != nil {
+			return err
+		}
+	}
+
+	// The transaction has at least minoutputs outputs, enforcing the transaction shapes accepted by the node
+	if !validationOptions.SkipPolicyChecks {
+		if err := validationOptions.fail(DiagnosticStagePolicy, tv.checkMinOutputs(tx)); err != nil {
 			return err
 		}
 	}
 
+	// The transaction and the scripts it executes are not larger than the consensus limits active at the block height
+	if err := validationOptions.fail(DiagnosticStageStructural, checkConsensusSizes(tx, txSize, rules)); err != nil {
+		return err
+	}
+
 	// 3) check that each input value, as well as the sum, are in the allowed range of values (less than 21m coins)
 	// 5) None of the inputs have hash=0, N=–1 (coinbase transactions should not be relayed)
-	if err := tv.checkInputs(tx, blockHeight); err != nil {
+	if err := validationOptions.fail(DiagnosticStageStructural, tv.checkInputs(tx, blockHeight)); err != nil {
 		return err
 	}
 
 	// 4) Each output value, as well as the total, must be within the allowed range of values (less than 21m coins,
 	//    more than the dust threshold if 1 unless it's OP_RETURN, which is allowed to be 0)
-	if err := tv.checkOutputs(tx, blockHeight, validationOptions); err != nil {
+	if err := tv.checkOutputsByStage(tx, blockHeight, validationOptions); err != nil {
+		return err
+	}
+
+	// The inputs and outputs follow the canonical ordering required by the network, when one is configured
+	if err := validationOptions.fail(DiagnosticStageStructural, tv.checkCanonicalOrdering(tx)); err != nil {
 		return err
 	}
 
@@ -227,9 +262,25 @@ func (tv *TxValidator) ValidateTransaction(tx *bt.Tx, blockHeight uint32, utxoHe
 	// SAO - The rule enforcing that unlocking scripts must be "push only" became more relevant and started being enforced with the
 	//       introduction of Segregated Witness (SegWit) which activated at height 481824.  BCH Forked before this at height 478559
 	//       and therefore let's not enforce this check until then.
-	if tv.interpreter.Interpreter() != TxInterpreterGoBDK && blockHeight > tv.settings.ChainCfgParams.UahfForkHeight {
+	if tv.interpreter.Interpreter() != TxInterpreterGoBDK && rules.PushOnlyUnlockingScripts {
 		// 9) The unlocking script (scriptSig) can only push numbers on the stack
-		if err := tv.pushDataCheck(tx); err != nil {
+		if err := validationOptions.fail(DiagnosticStageStructural, tv.pushDataCheck(tx)); err != nil {
+			return err
+		}
+	}
+
+	// The conditional blocks in the scripts of each input are not nested deeper than maxscriptnestingdepthpolicy,
+	// deeply nested scripts are expensive to evaluate
+	if !validationOptions.SkipPolicyChecks {
+		if err := validationOptions.fail(DiagnosticStagePolicy, tv.checkScriptNestingDepth(tx)); err != nil {
+			return err
+		}
+	}
+
+	// The scripts of each input do not contain more signature operations than maxsigopsperinputpolicy,
+	// every signature check is expensive to evaluate
+	if !validationOptions.SkipPolicyChecks {
+		if err := validationOptions.fail(DiagnosticStagePolicy, tv.checkSigOpsPerInput(tx)); err != nil {
 			return err
 		}
 	}
@@ -237,7 +288,14 @@ func (tv *TxValidator) ValidateTransaction(tx *bt.Tx, blockHeight uint32, utxoHe
 	// 10) Reject if the sum of input values is less than sum of output values
 	// 11) Reject if transaction fee would be too low (minRelayTxFee) to get into an empty block.
 	if !validationOptions.SkipPolicyChecks {
-		if err := tv.checkFees(tx, blockHeight, utxoHeights); err != nil {
+		if err := validationOptions.fail(DiagnosticStageFee, tv.checkFees(tx, blockHeight, utxoHeights)); err != nil {
+			return err
+		}
+	}
+
+	// Reject if the transaction fee is higher than maxabsolutefee, protecting against mistakenly huge fees
+	if !validationOptions.SkipPolicyChecks {
+		if err := validationOptions.fail(DiagnosticStageFee, tv.checkMaxAbsoluteFee(tx)); err != nil {
 			return err
 		}
 	}
@@ -245,6 +303,24 @@ func (tv *TxValidator) ValidateTransaction(tx *bt.Tx, blockHeight uint32, utxoHe
 	return nil
 }
 
+// checkOutputsByStage checks the outputs of the transaction. In diagnostic mode the consensus rules of the outputs
+// are reported in the structural stage and the policy rules in the policy stage, otherwise all rules are checked
+// at once, as the options require.
+func (tv *TxValidator) checkOutputsByStage(tx *bt.Tx, blockHeight uint32, validationOptions *Options) error {
+	if validationOptions.Diagnostics == nil {
+		return tv.checkOutputs(tx, blockHeight, validationOptions)
+	}
+
+	consensusErr := tv.checkOutputs(tx, blockHeight, &Options{SkipPolicyChecks: true})
+	_ = validationOptions.fail(DiagnosticStageStructural, consensusErr)
+
+	if consensusErr == nil && !validationOptions.SkipPolicyChecks {
+		_ = validationOptions.fail(DiagnosticStagePolicy, tv.checkOutputs(tx, blockHeight, validationOptions))
+	}
+
+	return nil
+}
+
 // ValidateTransactionScripts performs script validation for all transaction inputs.
 func (tv *TxValidator) ValidateTransactionScripts(tx *bt.Tx, blockHeight uint32, utxoHeights []uint32, validationOptions *Options) error {
 	if tv == nil {
@@ -290,9 +366,9 @@ func isUnspendableOutput(script *bscript.Script) bool {
 // Standard input scripts should only contain data pushes (no other opcodes)
 // This uses the same interpreter as the SV node for consistency
 // Before UAHF height, all scripts are considered standard (no push-only requirement)
-func isStandardInputScript(script *bscript.Script, blockHeight uint32, uahfHeight uint32) bool {
+func isStandardInputScript(script *bscript.Script, rules ConsensusRules) bool {
 	// Before UAHF, there was no push-only requirement for input scripts
-	if blockHeight <= uahfHeight {
+	if !rules.PushOnlyUnlockingScripts {
 		// Any parseable script is considered standard before UAHF
 		// Return true unless script is nil
 		return script != nil
@@ -319,9 +395,10 @@ func isStandardInputScript(script *bscript.Script, blockHeight uint32, uahfHeigh
 func (tv *TxValidator) checkOutputs(tx *bt.Tx, blockHeight uint32, validationOptions *Options) error {
 	total := uint64(0)
 
-	// Note: We use > instead of >= to exclude the Genesis activation block itself
+	// The Genesis output rules exclude the Genesis activation block itself,
 	// because transactions in block 620538 were created before Genesis rules existed
-	isGenesisActivated := blockHeight > tv.settings.ChainCfgParams.GenesisActivationHeight
+	isGenesisActivated := tv.consensusRules(blockHeight).GenesisOutputs
+	dataCarrierSize := tv.maxDataCarrierSize(validationOptions)
 
 	for index, output := range tx.Outputs {
 		// Check P2SH output after genesis activation
@@ -334,6 +411,11 @@ func (tv *TxValidator) checkOutputs(tx *bt.Tx, blockHeight uint32, validationOpt
 			return errors.NewTxInvalidError("transaction output %d satoshis is invalid", index)
 		}
 
+		// Data outputs do not exceed the data carrier size, unless the policy checks are skipped
+		if err := checkDataCarrierSize(index, output.LockingScript, dataCarrierSize); err != nil {
+			return err
+		}
+
 		// Check dust limit after genesis activation
 		// Dust checks are policy rules, not consensus rules - they only apply to mempool/relay
 		if !validationOptions.SkipPolicyChecks && isGenesisActivated {
@@ -341,6 +423,18 @@ func (tv *TxValidator) checkOutputs(tx *bt.Tx, blockHeight uint32, validationOpt
 			if tv.settings.ChainCfgParams.RequireStandard && output.Satoshis < DustLimit && !isUnspendableOutput(output.LockingScript) {
 				return errors.NewTxInvalidError("zero-satoshi outputs require 'OP_FALSE OP_RETURN' prefix")
 			}
+
+			// Apply the policy limits configured for the script type of this output
+			if tv.settings.ChainCfgParams.RequireStandard {
+				if err := tv.checkScriptTypePolicy(index, output); err != nil {
+					return err
+				}
+			}
+
+			// Only accept data outputs with an allowed protocol prefix, when an allowlist is configured
+			if err := tv.checkOpReturnPrefix(index, output.LockingScript); err != nil {
+				return err
+			}
 		}
 
 		total += output.Satoshis
@@ -353,6 +447,59 @@ func (tv *TxValidator) checkOutputs(tx *bt.Tx, blockHeight uint32, validationOpt
 	return nil
 }
 
+// outputScriptType returns the policy script type of the given locking script,
+// or an empty string if the script is not one of the configurable script types.
+func outputScriptType(script *bscript.Script) string {
+	if script == nil {
+		return ""
+	}
+
+	switch {
+	case script.IsP2PKH():
+		return settings.ScriptTypeP2PKH
+	case script.IsP2SH():
+		return settings.ScriptTypeP2SH
+	case script.IsData():
+		return settings.ScriptTypeOpReturn
+	case script.IsMultiSigOut():
+		return settings.ScriptTypeMultisig
+	default:
+		return ""
+	}
+}
+
+// checkScriptTypePolicy validates an output against the policy limits configured for its script type.
+// Outputs of script types without a configured policy are not checked.
+func (tv *TxValidator) checkScriptTypePolicy(index int, output *bt.Output) error {
+	if tv.settings.Policy == nil {
+		return nil
+	}
+
+	scriptType := outputScriptType(output.LockingScript)
+	if scriptType == "" {
+		return nil
+	}
+
+	policy, ok := tv.settings.Policy.GetScriptTypePolicy(scriptType)
+	if !ok {
+		return nil
+	}
+
+	if !policy.Standard {
+		return errors.NewTxPolicyError("transaction output %d has non-standard script type %s", index, scriptType)
+	}
+
+	if output.Satoshis < policy.DustLimit {
+		return errors.NewTxPolicyError("transaction output %d of script type %s is below the dust limit of %d satoshis", index, scriptType, policy.DustLimit)
+	}
+
+	if policy.MaxScriptSize > 0 && len(*output.LockingScript) > policy.MaxScriptSize {
+		return errors.NewTxPolicyError("transaction output %d of script type %s exceeds the maximum script size of %d bytes", index, scriptType, policy.MaxScriptSize)
+	}
+
+	return nil
+}
+
 // checkInputs validates transaction inputs according to consensus rules.
 func (tv *TxValidator) checkInputs(tx *bt.Tx, blockHeight uint32) error {
 	total := uint64(0)
@@ -378,14 +525,14 @@ func (tv *TxValidator) checkInputs(tx *bt.Tx, blockHeight uint32) error {
 
 		// Check if we've seen this input before
 		if _, exists := seenInputs[key]; exists {
-			return errors.NewTxInvalidError("duplicate input found at index %d", index)
+			return inputError(errors.NewTxInvalidError("duplicate input found at index %d", index), index)
 		}
 
 		// Mark this input as seen
 		seenInputs[key] = struct{}{}
 
 		if input.PreviousTxIDStr() == coinbaseTxID {
-			return errors.NewTxInvalidError("transaction input %d is a coinbase input", index)
+			return inputError(errors.NewTxInvalidError("transaction input %d is a coinbase input", index), index)
 		}
 		/* lots of our valid test transactions have this sequence number, is this not allowed?
 		if input.SequenceNumber == 0xffffffff {
@@ -395,7 +542,7 @@ func (tv *TxValidator) checkInputs(tx *bt.Tx, blockHeight uint32) error {
 		*/
 
 		if input.PreviousTxSatoshis > MaxSatoshis {
-			return errors.NewTxInvalidError("transaction input %d satoshis is too high", index)
+			return inputError(errors.NewTxInvalidError("transaction input %d satoshis is too high", index), index)
 		}
 
 		total += input.PreviousTxSatoshis
@@ -413,6 +560,34 @@ func (tv *TxValidator) checkInputs(tx *bt.Tx, blockHeight uint32) error {
 	return nil
 }
 
+// consensusRules returns the consensus rules that are active at the given block height.
+func (tv *TxValidator) consensusRules(blockHeight uint32) ConsensusRules {
+	return NewConsensusRulesResolver(tv.settings.ChainCfgParams).Rules(blockHeight)
+}
+
+// checkConsensusSizes validates that the transaction, and the scripts executed when validating its inputs, are
+// not larger than the consensus limits of the given rules. The output scripts are not checked, outputs with
+// larger scripts are valid but cannot be spent while the limit is active.
+func checkConsensusSizes(tx *bt.Tx, txSize int, rules ConsensusRules) error {
+	if txSize > rules.MaxTxSize {
+		return errors.NewTxInvalidError("transaction size in bytes is greater than max tx size consensus %d at height %d", rules.MaxTxSize, rules.Height)
+	}
+
+	if rules.MaxScriptSize == 0 {
+		return nil
+	}
+
+	for index, input := range tx.Inputs {
+		for _, script := range []*bscript.Script{input.UnlockingScript, input.PreviousTxScript} {
+			if script != nil && len(*script) > rules.MaxScriptSize {
+				return inputError(errors.NewTxInvalidError("transaction input %d script size is greater than max script size consensus %d at height %d", index, rules.MaxScriptSize, rules.Height), index)
+			}
+		}
+	}
+
+	return nil
+}
+
 // checkTxSize validates that the transaction size complies with policy limits.
 func (tv *TxValidator) checkTxSize(txSize int) error {
 	maxTxSizePolicy := tv.settings.Policy.GetMaxTxSizePolicy()
@@ -428,6 +603,21 @@ func (tv *TxValidator) checkTxSize(txSize int) error {
 	return nil
 }
 
+// checkMinOutputs validates that the transaction has at least the number of outputs of the min outputs policy.
+// Coinbase transactions are not checked, their outputs are decided by the miner.
+func (tv *TxValidator) checkMinOutputs(tx *bt.Tx) error {
+	minOutputs := tv.settings.Policy.GetMinOutputs()
+	if minOutputs <= 1 || tx.IsCoinbase() {
+		return nil
+	}
+
+	if len(tx.Outputs) < minOutputs {
+		return errors.NewTxPolicyError("transaction has %d outputs, less than the %d min outputs policy", len(tx.Outputs), minOutputs)
+	}
+
+	return nil
+}
+
 // checkFees validates transaction fees according to policy requirements.
 func (tv *TxValidator) checkFees(tx *bt.Tx, blockHeight uint32, utxoHeights []uint32) error {
 	// Check for consolidation transaction with proper UTXO height verification
@@ -473,6 +663,27 @@ func (tv *TxValidator) checkFees(tx *bt.Tx, blockHeight uint32, utxoHeights []ui
 	return nil
 }
 
+// checkMaxAbsoluteFee validates that the transaction fee is not higher than the max absolute fee policy.
+func (tv *TxValidator) checkMaxAbsoluteFee(tx *bt.Tx) error {
+	maxFee := tv.settings.Policy.GetMaxAbsoluteFee()
+	if maxFee == 0 {
+		return nil
+	}
+
+	inputSats := tx.TotalInputSatoshis()
+	outputSats := tx.TotalOutputSatoshis()
+
+	if inputSats <= outputSats {
+		return nil
+	}
+
+	if fee := inputSats - outputSats; fee > maxFee {
+		return errors.NewTxPolicyError("transaction fee is too high: %d > %d max absolute fee", fee, maxFee)
+	}
+
+	return nil
+}
+
 // isDustReturnTx checks if a transaction is a dust return transaction.
 // A dust return transaction has a single output with 0 satoshis and an unspendable script
 // (OP_FALSE OP_RETURN pattern). These transactions are used to clean up dust UTXOs.
@@ -582,6 +793,7 @@ func (tv *TxValidator) isConsolidationTx(tx *bt.Tx, utxoHeights []uint32, curren
 	minConf := tv.settings.Policy.GetMinConfConsolidationInput()
 	maxInputScriptSize := tv.settings.Policy.GetMaxConsolidationInputScriptSize()
 	acceptNonStdInputs := tv.settings.Policy.GetAcceptNonStdConsolidationInput()
+	rules := tv.consensusRules(currentHeight)
 
 	// Dust return transactions don't require confirmations
 	if isDustReturn {
@@ -611,7 +823,7 @@ func (tv *TxValidator) isConsolidationTx(tx *bt.Tx, utxoHeights []uint32, curren
 
 		// Rule 5: Standard Script Rule
 		// If acceptNonStdConsolidationInput = 0, all inputs must use standard scripts
-		if !acceptNonStdInputs && !isStandardInputScript(input.UnlockingScript, currentHeight, tv.settings.ChainCfgParams.UahfForkHeight) {
+		if !acceptNonStdInputs && !isStandardInputScript(input.UnlockingScript, rules) {
 			return false
 		}
 	}
@@ -651,11 +863,119 @@ func (tv *TxValidator) sigOpsCheck(tx *bt.Tx, validationOptions *Options) error
 	return nil
 }
 
+// checkScriptNestingDepth validates that the conditional blocks in the unlocking and locking scripts of the
+// transaction inputs are not nested deeper than the max script nesting depth policy.
+func (tv *TxValidator) checkScriptNestingDepth(tx *bt.Tx) error {
+	maxDepth := tv.settings.Policy.GetMaxScriptNestingDepthPolicy()
+	if maxDepth <= 0 {
+		return nil
+	}
+
+	for index, input := range tx.Inputs {
+		for _, script := range []*bscript.Script{input.UnlockingScript, input.PreviousTxScript} {
+			if depth := scriptNestingDepth(script); depth > maxDepth {
+				return inputError(errors.NewTxPolicyError("transaction input %d script nesting depth %d is greater than max script nesting depth policy %d", index, depth, maxDepth), index)
+			}
+		}
+	}
+
+	return nil
+}
+
+// scriptNestingDepth returns the maximum nesting depth of the OP_IF and OP_NOTIF blocks in the script.
+// Scripts that cannot be parsed return 0, these are rejected by the script interpreter.
+func scriptNestingDepth(script *bscript.Script) int {
+	if script == nil {
+		return 0
+	}
+
+	parser := interpreter.DefaultOpcodeParser{}
+	parsedScript, err := parser.Parse(script)
+
+	if err != nil {
+		return 0
+	}
+
+	depth := 0
+	maxDepth := 0
+
+	for _, op := range parsedScript {
+		switch op.Value() {
+		case bscript.OpIF, bscript.OpNOTIF:
+			depth++
+			if depth > maxDepth {
+				maxDepth = depth
+			}
+		case bscript.OpENDIF:
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+
+	return maxDepth
+}
+
+// checkSigOpsPerInput validates that the unlocking and locking scripts of each transaction input together
+// do not contain more signature operations than the max sigops per input policy.
+func (tv *TxValidator) checkSigOpsPerInput(tx *bt.Tx) error {
+	maxSigOps := tv.settings.Policy.GetMaxSigOpsPerInputPolicy()
+	if maxSigOps <= 0 {
+		return nil
+	}
+
+	for index, input := range tx.Inputs {
+		if sigOps := scriptSigOps(input.UnlockingScript) + scriptSigOps(input.PreviousTxScript); sigOps > maxSigOps {
+			return inputError(errors.NewTxPolicyError("transaction input %d has %d sigops, greater than max sigops per input policy %d", index, sigOps, maxSigOps), index)
+		}
+	}
+
+	return nil
+}
+
+// scriptSigOps returns the number of signature operations in the script. OP_CHECKMULTISIG(VERIFY) counts the
+// number of public keys when preceded by OP_1 to OP_16, and 20 (the legacy max public keys per multisig) otherwise.
+// Scripts that cannot be parsed return 0, these are rejected by the script interpreter.
+func scriptSigOps(script *bscript.Script) int64 {
+	if script == nil {
+		return 0
+	}
+
+	parser := interpreter.DefaultOpcodeParser{}
+	parsedScript, err := parser.Parse(script)
+
+	if err != nil {
+		return 0
+	}
+
+	var (
+		sigOps int64
+		lastOp byte
+	)
+
+	for _, op := range parsedScript {
+		switch op.Value() {
+		case bscript.OpCHECKSIG, bscript.OpCHECKSIGVERIFY:
+			sigOps++
+		case bscript.OpCHECKMULTISIG, bscript.OpCHECKMULTISIGVERIFY:
+			if lastOp >= bscript.Op1 && lastOp <= bscript.Op16 {
+				sigOps += int64(lastOp-bscript.Op1) + 1
+			} else {
+				sigOps += 20
+			}
+		}
+
+		lastOp = op.Value()
+	}
+
+	return sigOps
+}
+
 // pushDataCheck validates that transaction input scripts contain only data pushes.
 func (tv *TxValidator) pushDataCheck(tx *bt.Tx) error {
 	for index, input := range tx.Inputs {
 		if input.UnlockingScript == nil {
-			return errors.NewTxInvalidError("transaction input %d unlocking script is empty", index)
+			return inputError(errors.NewTxInvalidError("transaction input %d unlocking script is empty", index), index)
 		}
 
 		parser := interpreter.DefaultOpcodeParser{}
@@ -666,7 +986,7 @@ func (tv *TxValidator) pushDataCheck(tx *bt.Tx) error {
 		}
 
 		if !parsedUnlockingScript.IsPushOnly() {
-			return errors.NewTxInvalidError("transaction input %d unlocking script is not push only", index)
+			return inputError(errors.NewTxInvalidError("transaction input %d unlocking script is not push only", index), index)
 		}
 	}
 
@@ -107,8 +107,7 @@ func BenchmarkIsConsolidationTxFull(b *testing.B) {
 
 // BenchmarkIsStandardInputScript measures the performance of script validation
 func BenchmarkIsStandardInputScript(b *testing.B) {
-	uahfHeight := uint32(478559)
-	postUAHFHeight := uint32(500000)
+	postUAHFRules := NewConsensusRulesResolver(&chaincfg.MainNetParams).Rules(500000)
 
 	benchmarks := []struct {
 		name   string
@@ -148,7 +147,7 @@ func BenchmarkIsStandardInputScript(b *testing.B) {
 		b.Run(bm.name, func(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				_ = isStandardInputScript(bm.script, postUAHFHeight, uahfHeight)
+				_ = isStandardInputScript(bm.script, postUAHFRules)
 			}
 		})
 	}
@@ -4,13 +4,13 @@ import (
 	"testing"
 
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-chaincfg"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestIsStandardInputScript(t *testing.T) {
 	// Use a height after UAHF for standard tests
-	postUAHFHeight := uint32(500000)
-	uahfHeight := uint32(478559)
+	postUAHFRules := NewConsensusRulesResolver(&chaincfg.MainNetParams).Rules(500000)
 
 	tests := []struct {
 		name     string
@@ -147,7 +147,7 @@ func TestIsStandardInputScript(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isStandardInputScript(tt.script, postUAHFHeight, uahfHeight)
+			result := isStandardInputScript(tt.script, postUAHFRules)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -156,8 +156,7 @@ func TestIsStandardInputScript(t *testing.T) {
 // TestIsStandardInputScript_PreUAHF tests that before UAHF height, all scripts are considered standard
 func TestIsStandardInputScript_PreUAHF(t *testing.T) {
 	// Use a height before UAHF
-	preUAHFHeight := uint32(470000)
-	uahfHeight := uint32(478559)
+	preUAHFRules := NewConsensusRulesResolver(&chaincfg.MainNetParams).Rules(470000)
 
 	tests := []struct {
 		name     string
@@ -198,7 +197,7 @@ func TestIsStandardInputScript_PreUAHF(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isStandardInputScript(tt.script, preUAHFHeight, uahfHeight)
+			result := isStandardInputScript(tt.script, preUAHFRules)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
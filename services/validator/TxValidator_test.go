@@ -837,6 +837,120 @@ func TestZeroSatoshiOutputRequiresOpFalseOpReturn(t *testing.T) {
 	})
 }
 
+func TestScriptTypePolicy(t *testing.T) {
+	privKey, err := bec.NewPrivateKey()
+	require.NoError(t, err)
+
+	p2pkhScript, err := bscript.NewP2PKHFromPubKeyEC(privKey.PubKey())
+	require.NoError(t, err)
+
+	// OP_1 <pubkey> OP_1 OP_CHECKMULTISIG
+	multisigBytes := append([]byte{bscript.Op1, bscript.OpDATA33}, privKey.PubKey().Compressed()...)
+	multisigScript := bscript.NewFromBytes(append(multisigBytes, bscript.Op1, bscript.OpCHECKMULTISIG))
+
+	opReturnScript := bscript.NewFromBytes([]byte{bscript.OpFALSE, bscript.OpRETURN, 0x04, 0xde, 0xad, 0xbe, 0xef})
+
+	newTxWithOutput := func(satoshis uint64, script *bscript.Script) *bt.Tx {
+		tx := bt.NewTx()
+		tx.AddOutput(&bt.Output{Satoshis: satoshis, LockingScript: script})
+
+		return tx
+	}
+
+	newValidator := func(t *testing.T, policies map[string]settings.ScriptTypePolicy) *TxValidator {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.ChainCfgParams.RequireStandard = true
+
+		for scriptType, policy := range policies {
+			tSettings.Policy.SetScriptTypePolicy(scriptType, policy)
+		}
+
+		return &TxValidator{settings: tSettings}
+	}
+
+	t.Run("script types are detected", func(t *testing.T) {
+		assert.Equal(t, settings.ScriptTypeP2PKH, outputScriptType(p2pkhScript))
+		assert.Equal(t, settings.ScriptTypeMultisig, outputScriptType(multisigScript))
+		assert.Equal(t, settings.ScriptTypeOpReturn, outputScriptType(opReturnScript))
+		assert.Equal(t, "", outputScriptType(bscript.NewFromBytes([]byte{bscript.OpTRUE})))
+	})
+
+	t.Run("dust limits are applied per script type", func(t *testing.T) {
+		tv := newValidator(t, map[string]settings.ScriptTypePolicy{
+			settings.ScriptTypeP2PKH:    {Standard: true, DustLimit: 1000},
+			settings.ScriptTypeMultisig: {Standard: true, DustLimit: 100},
+		})
+
+		height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+		err := tv.checkOutputs(newTxWithOutput(500, p2pkhScript), height, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+
+		err = tv.checkOutputs(newTxWithOutput(1000, p2pkhScript), height, &Options{})
+		require.NoError(t, err)
+
+		// the multisig dust limit is independent of the p2pkh dust limit
+		err = tv.checkOutputs(newTxWithOutput(500, multisigScript), height, &Options{})
+		require.NoError(t, err)
+
+		err = tv.checkOutputs(newTxWithOutput(50, multisigScript), height, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+
+		// policy checks are not applied in block validation
+		err = tv.checkOutputs(newTxWithOutput(500, p2pkhScript), height, &Options{SkipPolicyChecks: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("standardness is applied per script type", func(t *testing.T) {
+		tv := newValidator(t, map[string]settings.ScriptTypePolicy{
+			settings.ScriptTypeOpReturn: {Standard: false},
+			settings.ScriptTypeMultisig: {Standard: false},
+		})
+
+		height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+		err := tv.checkOutputs(newTxWithOutput(0, opReturnScript), height, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+
+		err = tv.checkOutputs(newTxWithOutput(1000, multisigScript), height, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+
+		err = tv.checkOutputs(newTxWithOutput(1000, p2pkhScript), height, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("max script size is applied per script type", func(t *testing.T) {
+		tv := newValidator(t, map[string]settings.ScriptTypePolicy{
+			settings.ScriptTypeOpReturn: {Standard: true, MaxScriptSize: 4},
+		})
+
+		height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+		err := tv.checkOutputs(newTxWithOutput(0, opReturnScript), height, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+
+		err = tv.checkOutputs(newTxWithOutput(1000, p2pkhScript), height, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("script type policies are not applied when standard is not required", func(t *testing.T) {
+		tv := newValidator(t, map[string]settings.ScriptTypePolicy{
+			settings.ScriptTypeP2PKH: {Standard: false},
+		})
+		tv.settings.ChainCfgParams.RequireStandard = false
+
+		height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+		err := tv.checkOutputs(newTxWithOutput(1000, p2pkhScript), height, &Options{})
+		require.NoError(t, err)
+	})
+}
+
 func Test_isConsolidationTx(t *testing.T) {
 	tSettings := test.CreateBaseTestSettings(t)
 	txValidator := NewTxValidator(ulogger.TestLogger{}, tSettings)
@@ -895,3 +1009,275 @@ func TestTx5f37c7a38b5e0bc177a4c353481f30c6de1bc46db534019846d7bc829f58254a(t *t
 	})
 	require.NoError(t, err)
 }
+
+func TestMaxAbsoluteFeePolicy(t *testing.T) {
+	privKey, err := bec.NewPrivateKey()
+	require.NoError(t, err)
+
+	parentTx := transactions.Create(t,
+		transactions.WithCoinbaseData(100, "/Test miner/"),
+		transactions.WithP2PKHOutputs(1, 100000, privKey.PubKey()),
+	)
+
+	// pays a fee of 10000 satoshis
+	childTx := transactions.Create(t,
+		transactions.WithPrivateKey(privKey),
+		transactions.WithInput(parentTx, 0, privKey),
+		transactions.WithP2PKHOutputs(1, 90000, privKey.PubKey()),
+	)
+
+	newTxValidator := func(maxAbsoluteFee uint64) *TxValidator {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.MaxAbsoluteFee = maxAbsoluteFee
+
+		return NewTxValidator(ulogger.TestLogger{}, tSettings)
+	}
+
+	blockHeight := test.CreateBaseTestSettings(t).ChainCfgParams.GenesisActivationHeight + 1
+
+	t.Run("fee at the limit", func(t *testing.T) {
+		err := newTxValidator(10000).ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("fee exceeding the limit", func(t *testing.T) {
+		err := newTxValidator(9999).ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+		assert.Contains(t, err.Error(), "transaction fee is too high: 10000 > 9999 max absolute fee")
+	})
+
+	t.Run("not applied when skipping policy checks", func(t *testing.T) {
+		err := newTxValidator(1).ValidateTransaction(childTx, blockHeight, nil, &Options{SkipPolicyChecks: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		err := newTxValidator(0).ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+}
+
+func TestMinOutputsPolicy(t *testing.T) {
+	privKey, err := bec.NewPrivateKey()
+	require.NoError(t, err)
+
+	parentTx := transactions.Create(t,
+		transactions.WithCoinbaseData(100, "/Test miner/"),
+		transactions.WithP2PKHOutputs(1, 100000, privKey.PubKey()),
+	)
+
+	// childTx creates the given number of outputs
+	childTx := func(outputs int) *bt.Tx {
+		return transactions.Create(t,
+			transactions.WithPrivateKey(privKey),
+			transactions.WithInput(parentTx, 0, privKey),
+			transactions.WithP2PKHOutputs(outputs, 10000, privKey.PubKey()),
+		)
+	}
+
+	newTxValidator := func(minOutputs int) *TxValidator {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.MinOutputs = minOutputs
+
+		return NewTxValidator(ulogger.TestLogger{}, tSettings)
+	}
+
+	blockHeight := test.CreateBaseTestSettings(t).ChainCfgParams.GenesisActivationHeight + 1
+
+	t.Run("outputs at the limit", func(t *testing.T) {
+		err := newTxValidator(3).ValidateTransaction(childTx(3), blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("outputs above the limit", func(t *testing.T) {
+		err := newTxValidator(3).ValidateTransaction(childTx(4), blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("outputs below the limit", func(t *testing.T) {
+		err := newTxValidator(3).ValidateTransaction(childTx(2), blockHeight, nil, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+		assert.Contains(t, err.Error(), "transaction has 2 outputs, less than the 3 min outputs policy")
+	})
+
+	t.Run("not applied when skipping policy checks", func(t *testing.T) {
+		err := newTxValidator(3).ValidateTransaction(childTx(1), blockHeight, nil, &Options{SkipPolicyChecks: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("coinbase is not checked", func(t *testing.T) {
+		require.NoError(t, newTxValidator(3).checkMinOutputs(parentTx))
+	})
+
+	t.Run("zero outputs are rejected by the consensus rules without a limit", func(t *testing.T) {
+		for _, minOutputs := range []int{0, 1} {
+			tx := childTx(1)
+			require.NoError(t, newTxValidator(minOutputs).checkMinOutputs(tx))
+
+			tx.Outputs = nil
+
+			err := newTxValidator(minOutputs).ValidateTransaction(tx, blockHeight, nil, &Options{})
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errors.ErrTxInvalid)
+			assert.Contains(t, err.Error(), "transaction has no inputs or outputs")
+		}
+	})
+}
+
+func TestMaxSigOpsPerInputPolicy(t *testing.T) {
+	privKey, err := bec.NewPrivateKey()
+	require.NoError(t, err)
+
+	parentTx := transactions.Create(t,
+		transactions.WithCoinbaseData(100, "/Test miner/"),
+		transactions.WithP2PKHOutputs(1, 100000, privKey.PubKey()),
+	)
+
+	childTx := transactions.Create(t,
+		transactions.WithPrivateKey(privKey),
+		transactions.WithInput(parentTx, 0, privKey),
+		transactions.WithP2PKHOutputs(1, 90000, privKey.PubKey()),
+	)
+
+	// checkSigScript returns OP_1 followed by the given number of OP_CHECKSIGVERIFY opcodes
+	checkSigScript := func(sigOps int) *bscript.Script {
+		b := make([]byte, 0, sigOps+1)
+		b = append(b, bscript.Op1)
+
+		for i := 0; i < sigOps; i++ {
+			b = append(b, bscript.OpCHECKSIGVERIFY)
+		}
+
+		return bscript.NewFromBytes(b)
+	}
+
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Policy.MaxSigOpsPerInputPolicy = 10
+	blockHeight := tSettings.ChainCfgParams.GenesisActivationHeight + 1
+
+	txValidator := NewTxValidator(ulogger.TestLogger{}, tSettings)
+
+	t.Run("sigops at the limit", func(t *testing.T) {
+		childTx.Inputs[0].PreviousTxScript = checkSigScript(10)
+
+		err := txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("sigops exceeding the limit", func(t *testing.T) {
+		childTx.Inputs[0].PreviousTxScript = checkSigScript(11)
+
+		err := txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+		assert.Contains(t, err.Error(), "transaction input 0 has 11 sigops, greater than max sigops per input policy 10")
+		assert.Equal(t, 0, errors.GetData(err, ErrDataInputIndex))
+	})
+
+	t.Run("multisig counts its public keys", func(t *testing.T) {
+		// OP_11 OP_CHECKMULTISIG counts as 11 sigops, a bare OP_CHECKMULTISIG as 20
+		childTx.Inputs[0].PreviousTxScript = bscript.NewFromBytes([]byte{bscript.Op11, bscript.OpCHECKMULTISIG})
+
+		err := txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transaction input 0 has 11 sigops")
+
+		childTx.Inputs[0].PreviousTxScript = bscript.NewFromBytes([]byte{bscript.Op1, bscript.OpCHECKMULTISIG})
+
+		err = txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(20), scriptSigOps(bscript.NewFromBytes([]byte{bscript.OpDROP, bscript.OpCHECKMULTISIG})))
+	})
+
+	t.Run("not applied when skipping policy checks", func(t *testing.T) {
+		childTx.Inputs[0].PreviousTxScript = checkSigScript(1000)
+
+		err := txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{SkipPolicyChecks: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.MaxSigOpsPerInputPolicy = 0
+
+		childTx.Inputs[0].PreviousTxScript = checkSigScript(1000)
+
+		err := NewTxValidator(ulogger.TestLogger{}, tSettings).ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+}
+
+func TestMaxScriptNestingDepthPolicy(t *testing.T) {
+	privKey, err := bec.NewPrivateKey()
+	require.NoError(t, err)
+
+	parentTx := transactions.Create(t,
+		transactions.WithCoinbaseData(100, "/Test miner/"),
+		transactions.WithP2PKHOutputs(1, 100000, privKey.PubKey()),
+	)
+
+	childTx := transactions.Create(t,
+		transactions.WithPrivateKey(privKey),
+		transactions.WithInput(parentTx, 0, privKey),
+		transactions.WithP2PKHOutputs(1, 90000, privKey.PubKey()),
+	)
+
+	// nestedScript returns OP_1 OP_IF ... OP_1 OP_IF OP_1 OP_ENDIF ... OP_ENDIF with the given nesting depth
+	nestedScript := func(depth int) *bscript.Script {
+		b := make([]byte, 0, depth*3+1)
+
+		for i := 0; i < depth; i++ {
+			b = append(b, bscript.Op1, bscript.OpIF)
+		}
+
+		b = append(b, bscript.Op1)
+
+		for i := 0; i < depth; i++ {
+			b = append(b, bscript.OpENDIF)
+		}
+
+		return bscript.NewFromBytes(b)
+	}
+
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Policy.MaxScriptNestingDepthPolicy = 10
+	blockHeight := tSettings.ChainCfgParams.GenesisActivationHeight + 1
+
+	txValidator := NewTxValidator(ulogger.TestLogger{}, tSettings)
+
+	t.Run("nesting depth at the limit", func(t *testing.T) {
+		childTx.Inputs[0].PreviousTxScript = nestedScript(10)
+
+		err := txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("nesting depth exceeding the limit", func(t *testing.T) {
+		childTx.Inputs[0].PreviousTxScript = nestedScript(11)
+
+		err := txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxPolicy)
+		assert.Contains(t, err.Error(), "transaction input 0 script nesting depth 11 is greater than max script nesting depth policy 10")
+	})
+
+	t.Run("not applied when skipping policy checks", func(t *testing.T) {
+		childTx.Inputs[0].PreviousTxScript = nestedScript(1000)
+
+		err := txValidator.ValidateTransaction(childTx, blockHeight, nil, &Options{SkipPolicyChecks: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.MaxScriptNestingDepthPolicy = 0
+
+		childTx.Inputs[0].PreviousTxScript = nestedScript(1000)
+
+		err := NewTxValidator(ulogger.TestLogger{}, tSettings).ValidateTransaction(childTx, blockHeight, nil, &Options{})
+		require.NoError(t, err)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2"
@@ -27,10 +28,12 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/failurestream"
 	"github.com/bsv-blockchain/teranode/util/health"
 	"github.com/bsv-blockchain/teranode/util/kafka"
 	kafkamessage "github.com/bsv-blockchain/teranode/util/kafka/kafka_message"
 	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/ordishs/go-utils/expiringmap"
 	"github.com/ordishs/gocore"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
@@ -67,8 +70,23 @@ const (
 	// not spendable (OP_FALSE OP_RETURN).  This applies to outputs after the
 	// Genesis upgrade.
 	DustLimit = uint64(1)
+
+	// validationResultBufferSize is the number of validation results buffered for publishing to Kafka.
+	// Results are dropped when the buffer is full, so publishing never slows down validation.
+	validationResultBufferSize = 10_000
+
+	// scriptValidationErrorMessage is the message of the error wrapping a failed verification of the scripts of a
+	// transaction, it identifies script failures, which have no error code of their own.
+	scriptValidationErrorMessage = "error validating transaction scripts"
 )
 
+// IsScriptValidationError returns whether the validation of a transaction failed on the verification of its
+// scripts and signatures. The messages of the wrapped errors survive the transport of the error over gRPC, so
+// the check holds for errors returned by a remote validator as well.
+func IsScriptValidationError(err error) bool {
+	return err != nil && errors.Is(err, errors.ErrTxInvalid) && strings.Contains(err.Error(), scriptValidationErrorMessage)
+}
+
 // Validator implements comprehensive Bitcoin SV transaction validation and manages the complete lifecycle
 // of transactions from initial validation through block assembly integration. This struct serves as the
 // primary validation engine, coordinating between multiple components to ensure transaction validity
@@ -123,6 +141,41 @@ type Validator struct {
 
 	// rejectedTxKafkaProducerClient publishes rejected transaction events
 	rejectedTxKafkaProducerClient kafka.KafkaAsyncProducerI
+
+	// validationResultKafkaProducerClient publishes the result of every transaction validation,
+	// nil when kafka_validationResultsConfig is not set
+	validationResultKafkaProducerClient kafka.KafkaAsyncProducerI
+
+	// validationResultCh buffers the validation results to publish, decoupling the publishing from validation
+	validationResultCh chan *kafkamessage.KafkaTxValidationResultTopicMessage
+
+	// prevoutResolver resolves the parent transactions of validated transactions,
+	// nil resolves them from the UTXO store
+	prevoutResolver PrevoutResolver
+
+	// acceptancePolicy evaluates custom business rules on transactions validated with policy checks,
+	// nil accepts all transactions passing the standard validation
+	acceptancePolicy AcceptancePolicy
+
+	// failureEmitter emits the failed validations to the validation failure stream
+	failureEmitter failurestream.Emitter
+
+	// txMetaNotified holds the transactions announced on the txmeta topic within the deduplication window,
+	// nil when validator_txMetaDedupWindow is not set
+	txMetaNotified     *expiringmap.ExpiringMap[chainhash.Hash, struct{}]
+	txMetaNotifiedLock sync.Mutex
+
+	// validationCache caches the validation results by the state of the spent outputs,
+	// nil when validator_validationCacheTTL is not set
+	validationCache *validationCache
+
+	// seenInvalidCache rejects the transactions recently seen invalid without validating them again,
+	// nil when validator_seenInvalidCacheSize is not set
+	seenInvalidCache *seenInvalidCache
+
+	// feeDistribution keeps the fees of the last accepted transactions for the fee distribution metrics,
+	// nil when validator_feeDistributionWindow is not set
+	feeDistribution *feeDistribution
 }
 
 // New creates a new Validator instance with the provided configuration.
@@ -130,9 +183,17 @@ type Validator struct {
 // Returns an error if initialization fails.
 func New(ctx context.Context, logger ulogger.Logger, tSettings *settings.Settings, store utxo.Store,
 	txMetaKafkaProducerClient kafka.KafkaAsyncProducerI, rejectedTxKafkaProducerClient kafka.KafkaAsyncProducerI,
-	blockAssemblyClient blockassembly.ClientI, blockchainClient blockchain.ClientI) (Interface, error) {
+	blockAssemblyClient blockassembly.ClientI, blockchainClient blockchain.ClientI, opts ...func(*Validator)) (Interface, error) {
 	initPrometheusMetrics()
 
+	if err := checkCanonicalTxOrderingSetting(tSettings.Validator.CanonicalTxOrdering); err != nil {
+		return nil, err
+	}
+
+	if _, err := parseOpReturnPrefixAllowlist(tSettings.Policy.OpReturnPrefixAllowlist); err != nil {
+		return nil, err
+	}
+
 	var ba blockassembly.Store
 
 	if !tSettings.BlockAssembly.Disabled {
@@ -152,6 +213,43 @@ func New(ctx context.Context, logger ulogger.Logger, tSettings *settings.Setting
 		blockchainClient:              blockchainClient,
 	}
 
+	if tSettings.Validator.TxMetaDedupWindow > 0 {
+		v.txMetaNotified = expiringmap.New[chainhash.Hash, struct{}](tSettings.Validator.TxMetaDedupWindow)
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if tSettings.Validator.ValidationCacheTTL > 0 {
+		v.validationCache = newValidationCache(v.utxoStore, tSettings.Validator.ValidationCacheTTL)
+	}
+
+	if tSettings.Validator.FeeDistributionWindow > 0 {
+		v.feeDistribution = registerFeeDistribution(tSettings.Validator.FeeDistributionWindow)
+	}
+
+	if tSettings.Validator.SeenInvalidCacheSize > 0 {
+		v.seenInvalidCache = newSeenInvalidCache(tSettings.Validator.SeenInvalidCacheSize)
+
+		if v.blockchainClient != nil {
+			if err := v.listenForReorgs(ctx); err != nil {
+				return nil, err
+			}
+		} else {
+			logger.Warnf("[Validator] no blockchain client, transactions seen spending spent outputs are not cleared from the seen-invalid cache on a reorg")
+		}
+	}
+
+	if v.failureEmitter == nil {
+		failureEmitter, err := failurestream.New(ctx, logger, tSettings, "validator")
+		if err != nil {
+			return nil, errors.NewServiceError("could not create validation failure stream", err)
+		}
+
+		v.failureEmitter = failureEmitter
+	}
+
 	txmetaKafkaURL := v.settings.Kafka.TxMetaConfig
 	if txmetaKafkaURL == nil {
 		return nil, errors.NewConfigurationError("missing Kafka URL for txmeta")
@@ -165,9 +263,73 @@ func New(ctx context.Context, logger ulogger.Logger, tSettings *settings.Setting
 		v.rejectedTxKafkaProducerClient.Start(ctx, make(chan *kafka.Message, 10_000))
 	}
 
+	if validationResultsKafkaURL := v.settings.Kafka.ValidationResultsConfig; validationResultsKafkaURL != nil {
+		logger.Infof("[Validator] publishing validation results to kafka topic %s", validationResultsKafkaURL.Path)
+
+		validationResultKafkaProducerClient, err := kafka.NewKafkaAsyncProducerFromURL(ctx, logger, validationResultsKafkaURL, &v.settings.Kafka)
+		if err != nil {
+			return nil, errors.NewServiceError("could not create validation results kafka producer", err)
+		}
+
+		v.startValidationResultPublisher(ctx, validationResultKafkaProducerClient)
+	}
+
 	return v, nil
 }
 
+// startValidationResultPublisher starts the producer for the validation results and a goroutine that
+// publishes the buffered results until the context is done.
+func (v *Validator) startValidationResultPublisher(ctx context.Context, producer kafka.KafkaAsyncProducerI) {
+	v.validationResultKafkaProducerClient = producer
+	v.validationResultCh = make(chan *kafkamessage.KafkaTxValidationResultTopicMessage, validationResultBufferSize)
+
+	producer.Start(ctx, make(chan *kafka.Message, 10_000))
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m := <-v.validationResultCh:
+				value, err := proto.Marshal(m)
+				if err != nil {
+					v.logger.Errorf("[Validator] failed to marshal validation result of tx %s: %v", m.TxHash, err)
+					continue
+				}
+
+				producer.Publish(&kafka.Message{
+					Key:   []byte(m.TxHash),
+					Value: value,
+				})
+			}
+		}
+	}()
+}
+
+// publishValidationResult queues the result of a transaction validation for publishing to Kafka. It never
+// blocks, the result is dropped when the publish buffer is full.
+func (v *Validator) publishValidationResult(tx *bt.Tx, validationErr error) {
+	if v.validationResultCh == nil {
+		return
+	}
+
+	m := &kafkamessage.KafkaTxValidationResultTopicMessage{
+		TxHash:    tx.TxIDChainHash().String(),
+		Valid:     validationErr == nil,
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	if validationErr != nil {
+		m.Reason = validationErr.Error()
+	}
+
+	select {
+	case v.validationResultCh <- m:
+	default:
+		prometheusValidatorValidationResultsDropped.Inc()
+	}
+}
+
 // Health performs health checks on the validator and its dependencies.
 // When checkLiveness is true, only checks service liveness.
 // When false, performs full readiness check including dependencies.
@@ -269,6 +431,13 @@ func (v *Validator) Validate(ctx context.Context, tx *bt.Tx, blockHeight uint32,
 //
 // When validation fails with errors other than storage or service errors, the transaction
 // is reported to the rejected transaction Kafka topic for monitoring and analysis.
+// When a validation results topic is configured, the result of every validation is published
+// to it as well, without blocking the validation.
+// Failed validations are emitted to the validation failure stream, when one is configured.
+// When the validation cache is enabled, the cached result of the transaction is returned for an unchanged
+// UTXO set instead of validating the transaction again.
+// Unless the options exempt it, the transaction is added to the validation backlog, and rejected with a
+// ServiceUnavailableError when the backlog is full.
 //
 // Parameters:
 //   - ctx: Context for the validation operation, used for tracing and cancellation
@@ -280,7 +449,41 @@ func (v *Validator) Validate(ctx context.Context, tx *bt.Tx, blockHeight uint32,
 //   - *meta.Data: Transaction metadata if validation succeeds, includes fee calculations
 //   - error: Detailed validation error if validation fails, nil on success
 func (v *Validator) ValidateWithOptions(ctx context.Context, tx *bt.Tx, blockHeight uint32, validationOptions *Options) (txMetaData *meta.Data, err error) {
-	if txMetaData, err = v.validateInternal(ctx, tx, blockHeight, validationOptions); err != nil {
+	// new submissions of in-process clients count towards the node wide validation backlog, like the
+	// submissions to the validator server
+	if !validationOptions.BacklogExempt {
+		if err = acquireBacklog(v.settings.Validator.MaxBacklog, 1); err != nil {
+			return nil, err
+		}
+		defer releaseBacklog(1)
+	}
+
+	validate := v.validateInternal
+	if v.validationCache != nil {
+		validate = func(ctx context.Context, tx *bt.Tx, blockHeight uint32, validationOptions *Options) (*meta.Data, error) {
+			return v.validationCache.validate(ctx, tx, blockHeight, validationOptions, v.validateInternal)
+		}
+	}
+
+	if v.seenInvalidCache != nil {
+		txMetaData, err = v.seenInvalidCache.validate(ctx, tx, blockHeight, validationOptions, validate)
+	} else {
+		txMetaData, err = validate(ctx, tx, blockHeight, validationOptions)
+	}
+
+	if correlationID := tracing.CorrelationIDFromContext(ctx); correlationID != "" {
+		if err != nil {
+			v.logger.Infof("[Validate][%s] rejected transaction with correlation id %s: %v", tx.TxIDChainHash(), correlationID, err)
+		} else {
+			v.logger.Infof("[Validate][%s] accepted transaction with correlation id %s", tx.TxIDChainHash(), correlationID)
+		}
+	}
+
+	v.publishValidationResult(tx, err)
+
+	if err != nil {
+		v.emitValidationFailure(tx, err)
+
 		if v.rejectedTxKafkaProducerClient != nil { // tests may not set this
 			// TODO which errors should we be sending here?
 			if !errors.Is(err, errors.ErrStorageError) && !errors.Is(err, errors.ErrServiceError) && !errors.Is(err, errors.ErrTxMissingParent) {
@@ -330,6 +533,43 @@ func (v *Validator) ValidateWithOptions(ctx context.Context, tx *bt.Tx, blockHei
 	return txMetaData, err
 }
 
+// checkFinalInNextBlock checks that the transaction is final in the next block, at the height following the
+// current best block and at the current median time past. Transactions entering the mempool must be final in the
+// next block, whatever the block height they are validated at, otherwise they cannot be mined yet.
+func checkFinalInNextBlock(tx *bt.Tx, blockState utxo.BlockState) error {
+	nextBlockHeight := blockState.Height + 1
+
+	if err := util.IsTransactionFinal(tx, nextBlockHeight, blockState.MedianTime); err != nil {
+		return errors.NewTxLockTimeError("not final in the next block at height %d, median time past %d", nextBlockHeight, blockState.MedianTime, err)
+	}
+
+	return nil
+}
+
+// getKnownTxMeta returns the meta data of the transaction if it already exists in the utxo store, either mined
+// or accepted as unconfirmed, in which case it does not need to be validated again. Conflicting and locked
+// transactions are not considered known, since they still need to go through the full validation.
+// Any error from the utxo store is ignored, falling back to the normal validation path.
+func (v *Validator) getKnownTxMeta(ctx context.Context, tx *bt.Tx, txID string) *meta.Data {
+	txMeta, err := v.utxoStore.GetMeta(ctx, tx.TxIDChainHash())
+	if err != nil {
+		if !errors.Is(err, errors.ErrTxNotFound) {
+			v.logger.Warnf("[Validate][%s] failed to check for known transaction: %v", txID, err)
+		}
+
+		return nil
+	}
+
+	if txMeta == nil || txMeta.Conflicting || txMeta.Locked {
+		return nil
+	}
+
+	v.logger.Debugf("[Validate][%s] transaction already known, skipping validation", txID)
+	prometheusKnownTransactions.Inc()
+
+	return txMeta
+}
+
 // validateInternal performs the core validation logic for a transaction.
 // This method contains the detailed step-by-step transaction validation workflow and manages
 // the entire lifecycle of a transaction from initial validation through UTXO updates and
@@ -350,6 +590,10 @@ func (v *Validator) ValidateWithOptions(ctx context.Context, tx *bt.Tx, blockHei
 // any validation step fails, ensuring UTXO database consistency even during partial
 // validation failures.
 //
+// When validationOptions.Diagnostics is set, the validation runs in diagnostic mode: the errors
+// of every stage are collected in the diagnostics instead of ending the validation, and the
+// validation stops after the acceptance policy, before any output is spent, see DiagnoseTransaction.
+//
 // Parameters:
 //   - ctx: Context for the validation operation, used for tracing and cancellation
 //   - tx: Transaction to validate, must be properly initialized
@@ -366,6 +610,9 @@ func (v *Validator) validateInternal(ctx context.Context, tx *bt.Tx, blockHeight
 	tx.SetTxHash(tx.TxIDChainHash())
 	txID := tx.TxIDChainHash().String()
 
+	// validation must always see the latest state of the utxos, regardless of what the caller requested
+	ctx = utxo.WithReadConsistency(ctx, utxo.ReadConsistencyStrong)
+
 	ctx, span, deferFn := tracing.Tracer("validator").Start(
 		ctx,
 		"validateInternal",
@@ -388,6 +635,8 @@ func (v *Validator) validateInternal(ctx context.Context, tx *bt.Tx, blockHeight
 
 	var spentUtxos []*utxo.Spend
 
+	diagnostics := validationOptions.Diagnostics
+
 	// Get atomic block state to prevent race conditions between height and median time reads
 	blockState := v.GetBlockState()
 
@@ -397,7 +646,7 @@ func (v *Validator) validateInternal(ctx context.Context, tx *bt.Tx, blockHeight
 
 	// We do not check IsFinal for transactions before BIP113 change (block height 419328)
 	// This is an exception for transactions before the media block time was used
-	if blockHeight > v.settings.ChainCfgParams.CSVHeight {
+	if NewConsensusRulesResolver(v.settings.ChainCfgParams).Rules(blockHeight).MedianTimePastFinality {
 
 		utxoStoreMedianBlockTime := blockState.MedianTime
 		if utxoStoreMedianBlockTime == 0 {
@@ -409,34 +658,74 @@ func (v *Validator) validateInternal(ctx context.Context, tx *bt.Tx, blockHeight
 
 		// this function should be moved into go-bt
 		if err = util.IsTransactionFinal(tx, blockHeight, utxoStoreMedianBlockTime); err != nil {
-			err = errors.NewUtxoNonFinalError("[Validate][%s] transaction is not final", txID, err)
-			span.RecordError(err)
+			if err = validationOptions.fail(DiagnosticStageStructural, errors.NewUtxoNonFinalError("[Validate][%s] transaction is not final", txID, err)); err != nil {
+				span.RecordError(err)
 
-			return nil, err
+				return nil, err
+			}
+		}
+	}
+
+	if v.settings.Validator.RejectNonFinal && !validationOptions.SkipPolicyChecks {
+		if err = checkFinalInNextBlock(tx, blockState); err != nil {
+			if err = validationOptions.fail(DiagnosticStagePolicy, errors.NewUtxoNonFinalError("[Validate][%s] transaction is not final", txID, err)); err != nil {
+				span.RecordError(err)
+
+				return nil, err
+			}
 		}
 	}
 
 	if tx.IsCoinbase() {
 		err = errors.NewProcessingError("[Validate][%s] coinbase transactions are not supported", txID)
+
+		if diagnostics != nil {
+			diagnostics.failInputs(err)
+
+			return nil, nil
+		}
+
 		span.RecordError(err)
 
 		return nil, err
 	}
 
-	var utxoHeights []uint32
+	// a diagnosis validates the transaction even when it is known
+	if v.settings.Validator.SkipKnownTransactions && diagnostics == nil {
+		if txMetaData = v.getKnownTxMeta(ctx, tx, txID); txMetaData != nil {
+			return txMetaData, nil
+		}
+	}
+
+	var (
+		utxoHeights       []uint32
+		unconfirmedInputs int
+	)
 
 	// check whether the transaction is extended, extend it if not
 	// we also get the block heights of the inputs of the transaction since we are doing a DB lookup
-	if !tx.IsExtended() {
+	// a diagnosis always resolves the inputs first, the other stages need the outputs they spend
+	if !tx.IsExtended() || diagnostics != nil {
 		// get the block heights of all inputs of the transaction and extend the inputs of not extended transaction.
 		// utxoHeights is a slice of block heights for each input
 		// txInpoints is a struct containing the parent tx hashes and the vout indexes of each input
-		if utxoHeights, err = v.getTransactionInputBlockHeightsAndExtendTx(ctx, tx, txID); err != nil {
+		if utxoHeights, unconfirmedInputs, err = v.getTransactionInputBlockHeightsAndExtendTx(ctx, tx, txID, validationOptions); err != nil {
+			if diagnostics != nil {
+				diagnostics.failInputs(err)
+
+				return nil, nil
+			}
+
 			err = errors.NewProcessingError("[Validate][%s] error getting transaction input block heights", txID, err)
 			span.RecordError(err)
 
 			return nil, err
 		}
+
+		// spending is checked by the utxo store when the outputs are spent, a diagnosis spends nothing
+		if diagnostics != nil {
+			_ = validationOptions.fail(DiagnosticStageInputs, v.checkOutputsUnspent(ctx, tx))
+		}
 	}
 
 	// validate the transaction format, consensus rules etc.
@@ -451,7 +740,7 @@ func (v *Validator) validateInternal(ctx context.Context, tx *bt.Tx, blockHeight
 	// if the transaction was extended, we still need to get the block heights of the inputs
 	// since that processing did not happen before the validateTransaction step
 	if len(utxoHeights) == 0 {
-		if utxoHeights, err = v.getTransactionInputBlockHeightsAndExtendTx(ctx, tx, txID); err != nil {
+		if utxoHeights, unconfirmedInputs, err = v.getTransactionInputBlockHeightsAndExtendTx(ctx, tx, txID, validationOptions); err != nil {
 			err = errors.NewProcessingError("[Validate][%s] error getting transaction input block heights", txID, err)
 			span.RecordError(err)
 
@@ -459,12 +748,40 @@ func (v *Validator) validateInternal(ctx context.Context, tx *bt.Tx, blockHeight
 		}
 	}
 
-	// validate the transaction scripts and signatures
-	if err = v.validateTransactionScripts(ctx, tx, blockHeight, utxoHeights, validationOptions); err != nil {
-		err = errors.NewProcessingError("[Validate][%s] error validating transaction scripts", txID, err)
-		span.RecordError(err)
+	// account for the inputs spending outputs of transactions that are not mined yet, and limit them by policy
+	prometheusValidatorUnconfirmedInputs.Observe(float64(unconfirmedInputs))
 
-		return nil, err
+	if !validationOptions.SkipPolicyChecks {
+		if err = validationOptions.fail(DiagnosticStagePolicy, v.checkUnconfirmedInputs(unconfirmedInputs)); err != nil {
+			err = errors.NewProcessingError("[Validate][%s] error validating transaction", txID, err)
+			span.RecordError(err)
+
+			return nil, err
+		}
+	}
+
+	// validate the transaction scripts and signatures, unless the transaction was validated before
+	if !validationOptions.SkipScriptVerification {
+		if err = validationOptions.fail(DiagnosticStageScript, v.validateTransactionScripts(ctx, tx, blockHeight, utxoHeights, validationOptions)); err != nil {
+			err = errors.NewProcessingError("[Validate][%s] "+scriptValidationErrorMessage, txID, err)
+			span.RecordError(err)
+
+			return nil, err
+		}
+	}
+
+	if !validationOptions.SkipPolicyChecks {
+		if err = validationOptions.fail(DiagnosticStagePolicy, v.checkAcceptancePolicy(tx)); err != nil {
+			err = errors.NewProcessingError("[Validate][%s] error validating transaction", txID, err)
+			span.RecordError(err)
+
+			return nil, err
+		}
+	}
+
+	// a diagnosis ends before any output is spent, the result of the stages is in the diagnostics
+	if diagnostics != nil {
+		return nil, nil
 	}
 
 	// decouple the tracing context to not cancel the context when finalize the block assembly
@@ -632,24 +949,37 @@ func (v *Validator) validateInternal(ctx context.Context, tx *bt.Tx, blockHeight
 		txMetaData.Locked = false
 	}
 
+	v.feeDistribution.observe(txMetaData.Fee)
+
 	return txMetaData, nil
 }
 
 // getTransactionInputBlockHeights returns the block heights for each input of the transaction
-func (v *Validator) getTransactionInputBlockHeightsAndExtendTx(ctx context.Context, tx *bt.Tx, txID string) ([]uint32, error) {
+func (v *Validator) getTransactionInputBlockHeightsAndExtendTx(ctx context.Context, tx *bt.Tx, txID string, validationOptions *Options) ([]uint32, int, error) {
 	ctx, span, endSpan := tracing.Tracer("validator").Start(ctx, "getTransactionInputBlockHeightsAndExtendTx",
 		tracing.WithHistogram(getTransactionInputBlockHeights),
 	)
 	defer endSpan()
 
 	// get the utxo heights for each input
-	utxoHeights, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, txID)
+	utxoHeights, unconfirmedInputs, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, txID, validationOptions)
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, 0, err
 	}
 
-	return utxoHeights, nil
+	return utxoHeights, unconfirmedInputs, nil
+}
+
+// checkUnconfirmedInputs validates that the transaction does not have more inputs spending outputs of transactions
+// that are not mined yet than the max unconfirmed inputs policy.
+func (v *Validator) checkUnconfirmedInputs(unconfirmedInputs int) error {
+	maxUnconfirmedInputs := v.settings.Policy.GetMaxUnconfirmedInputsPolicy()
+	if maxUnconfirmedInputs <= 0 || unconfirmedInputs <= maxUnconfirmedInputs {
+		return nil
+	}
+
+	return errors.NewTxPolicyError("transaction has %d inputs spending unconfirmed outputs, greater than max unconfirmed inputs policy %d", unconfirmedInputs, maxUnconfirmedInputs)
 }
 
 // twoPhaseCommitTransaction marks the transaction as spendable
@@ -672,14 +1002,16 @@ func (v *Validator) twoPhaseCommitTransaction(ctx context.Context, tx *bt.Tx, tx
 	return nil
 }
 
-// getUtxoBlockHeightsAndExtendTx returns the block heights for each input of the transaction
-func (v *Validator) getUtxoBlockHeightsAndExtendTx(ctx context.Context, tx *bt.Tx, txID string) ([]uint32, error) {
+// getUtxoBlockHeightsAndExtendTx returns the block heights for each input of the transaction, and the number of
+// inputs spending outputs of transactions that are not mined yet
+func (v *Validator) getUtxoBlockHeightsAndExtendTx(ctx context.Context, tx *bt.Tx, txID string, validationOptions *Options) ([]uint32, int, error) {
 	// get the block heights of the input transactions of the transaction
 	g, gCtx := errgroup.WithContext(ctx)
 	util.SafeSetLimit(g, v.settings.UtxoStore.GetBatcherSize)
 
 	parentTxHashes := make(map[chainhash.Hash][]int)
 	utxoHeights := make([]uint32, len(tx.Inputs))
+	unconfirmed := make([]bool, len(tx.Inputs))
 
 	for inputIdx, input := range tx.Inputs {
 		parentTxHash := input.PreviousTxIDChainHash()
@@ -698,12 +1030,13 @@ func (v *Validator) getUtxoBlockHeightsAndExtendTx(ctx context.Context, tx *bt.T
 		inputIdxs := idxs
 
 		g.Go(func() error {
-			if err := v.getUtxoBlockHeightAndExtendForParentTx(gCtx, parentTxHash, inputIdxs, utxoHeights, tx, extend); err != nil {
+			if err := v.getUtxoBlockHeightAndExtendForParentTx(gCtx, parentTxHash, inputIdxs, utxoHeights, unconfirmed, tx, extend, validationOptions); err != nil {
+				// the first input spending the parent is reported as the failing input
 				if errors.Is(err, errors.ErrTxNotFound) {
-					return errors.NewTxMissingParentError("[Validate][%s] error getting parent transaction %s", txID, parentTxHash, err)
+					return inputError(errors.NewTxMissingParentError("[Validate][%s] error getting parent transaction %s", txID, parentTxHash, err), inputIdxs[0])
 				}
 
-				return errors.NewProcessingError("[Validate][%s] error getting parent transaction %s", txID, parentTxHash, err)
+				return inputError(errors.NewProcessingError("[Validate][%s] error getting parent transaction %s", txID, parentTxHash, err), inputIdxs[0])
 			}
 
 			return nil
@@ -711,33 +1044,59 @@ func (v *Validator) getUtxoBlockHeightsAndExtendTx(ctx context.Context, tx *bt.T
 	}
 
 	if err := g.Wait(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return utxoHeights, nil
+	unconfirmedInputs := 0
+
+	for _, isUnconfirmed := range unconfirmed {
+		if isUnconfirmed {
+			unconfirmedInputs++
+		}
+	}
+
+	return utxoHeights, unconfirmedInputs, nil
 }
 
-// getUtxoBlockHeightAndExtendForParentTx retrieves the block height for a parent transaction
-// and extends the inputs of the transaction if it is not already extended.
+// getUtxoBlockHeightAndExtendForParentTx retrieves the block height for a parent transaction, marks the inputs
+// spending it as unconfirmed when it is not mined yet, and extends the inputs of the transaction if it is not
+// already extended.
 func (v *Validator) getUtxoBlockHeightAndExtendForParentTx(gCtx context.Context, parentTxHash chainhash.Hash, idxs []int,
-	utxoHeights []uint32, tx *bt.Tx, extend bool) error {
-	f := []fields.FieldName{fields.BlockIDs, fields.BlockHeights}
+	utxoHeights []uint32, unconfirmed []bool, tx *bt.Tx, extend bool, validationOptions *Options) error {
+	f := []fields.FieldName{fields.BlockIDs, fields.BlockHeights, fields.IsCoinbase}
 
 	if extend {
 		// add the parent tx outputs to the fields, to be able to extend the transaction
 		f = append(f, fields.Tx)
 	}
 
-	txMeta, err := v.utxoStore.Get(gCtx, &parentTxHash, f...)
+	var (
+		txMeta *meta.Data
+		err    error
+	)
+
+	if v.prevoutResolver != nil {
+		txMeta, err = v.prevoutResolver.ResolveParent(gCtx, &parentTxHash, f...)
+	} else {
+		txMeta, err = v.utxoStore.Get(gCtx, &parentTxHash, f...)
+	}
+
 	if err != nil {
 		return err
 	}
 
+	if txMeta.IsCoinbase && !validationOptions.SkipPolicyChecks {
+		if err = v.checkCoinbaseOnCurrentChain(gCtx, &parentTxHash, txMeta); err != nil {
+			return err
+		}
+	}
+
 	if len(txMeta.BlockHeights) == 0 {
 		// Get atomic block state to ensure consistency
 		blockState := v.utxoStore.GetBlockState()
 		for _, idx := range idxs {
 			utxoHeights[idx] = blockState.Height
+			unconfirmed[idx] = true
 		}
 	} else {
 		for _, idx := range idxs {
@@ -753,7 +1112,7 @@ func (v *Validator) getUtxoBlockHeightAndExtendForParentTx(gCtx context.Context,
 					tx.TxIDChainHash().String(), idx, len(tx.Inputs))
 			}
 
-			if txMeta.Tx == nil || txMeta.Tx.Outputs == nil || txMeta.Tx.Outputs[tx.Inputs[idx].PreviousTxOutIndex] == nil {
+			if txMeta.Tx == nil || int(tx.Inputs[idx].PreviousTxOutIndex) >= len(txMeta.Tx.Outputs) || txMeta.Tx.Outputs[tx.Inputs[idx].PreviousTxOutIndex] == nil {
 				return errors.NewProcessingError("[Validate][%s] parent transaction %s does not have outputs for input index %d",
 					tx.TxIDChainHash().String(), parentTxHash.String(), idx)
 			}
@@ -767,6 +1126,45 @@ func (v *Validator) getUtxoBlockHeightAndExtendForParentTx(gCtx context.Context,
 	return nil
 }
 
+// checkCoinbaseOnCurrentChain checks that the block of a coinbase transaction spent by the transaction being
+// validated for the mempool is on the current chain.
+//
+// The UTXO store checks the maturity of a coinbase spend with the height of the block the coinbase was mined in.
+// A coinbase only exists in its own block, so after a reorg removing that block from the current chain the
+// coinbase outputs do not exist anymore and spending them must be rejected, even when the spend was mature
+// before the reorg. Spends of a coinbase on the current chain keep the height of its block on that chain.
+//
+// The check only applies to the transactions validated for the mempool. The transactions of subtrees and blocks
+// skip it, the block they are validated for is not necessarily on the current chain.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - coinbaseTxHash: Hash of the coinbase transaction being spent
+//   - txMeta: Metadata of the coinbase transaction, including the IDs of the blocks it was mined in
+//
+// Returns:
+//   - error: errors.ErrTxInvalid when the coinbase is not in a block on the current chain
+func (v *Validator) checkCoinbaseOnCurrentChain(ctx context.Context, coinbaseTxHash *chainhash.Hash, txMeta *meta.Data) error {
+	if !v.settings.Validator.CheckCoinbaseOnChain || v.blockchainClient == nil {
+		return nil
+	}
+
+	if len(txMeta.BlockIDs) == 0 {
+		return errors.NewTxInvalidError("coinbase transaction %s is not mined in any block", coinbaseTxHash.String())
+	}
+
+	onCurrentChain, err := v.blockchainClient.CheckBlockIsInCurrentChain(ctx, txMeta.BlockIDs)
+	if err != nil {
+		return errors.NewServiceError("failed to check whether the block of coinbase transaction %s is on the current chain", coinbaseTxHash.String(), err)
+	}
+
+	if !onCurrentChain {
+		return errors.NewTxInvalidError("coinbase transaction %s is mined in block(s) %v that are not on the current chain", coinbaseTxHash.String(), txMeta.BlockIDs)
+	}
+
+	return nil
+}
+
 func (v *Validator) TriggerBatcher() {
 	// Noop
 }
@@ -797,6 +1195,11 @@ func (v *Validator) CreateInUtxoStore(ctx context.Context, tx *bt.Tx, blockHeigh
 }
 
 func (v *Validator) sendTxMetaToKafka(data *meta.Data, txHash *chainhash.Hash) error {
+	if v.txMetaAlreadyNotified(txHash) {
+		v.logger.Debugf("[sendTxMetaToKafka][%s] skipping duplicate txmeta notification", txHash.String())
+		return nil
+	}
+
 	startKafka := time.Now()
 
 	metaBytes, err := data.MetaBytes()
@@ -827,6 +1230,25 @@ func (v *Validator) sendTxMetaToKafka(data *meta.Data, txHash *chainhash.Hash) e
 	return nil
 }
 
+// txMetaAlreadyNotified returns whether the txmeta notification of the transaction was already sent within the
+// deduplication window, marking the transaction as notified when it was not.
+func (v *Validator) txMetaAlreadyNotified(txHash *chainhash.Hash) bool {
+	if v.txMetaNotified == nil {
+		return false
+	}
+
+	v.txMetaNotifiedLock.Lock()
+	defer v.txMetaNotifiedLock.Unlock()
+
+	if _, ok := v.txMetaNotified.Get(*txHash); ok {
+		return true
+	}
+
+	v.txMetaNotified.Set(*txHash, struct{}{})
+
+	return false
+}
+
 // spendUtxos attempts to spend the UTXOs referenced by transaction inputs.
 // Returns the spent UTXOs and error if spending fails.
 func (v *Validator) spendUtxos(ctx context.Context, tx *bt.Tx, blockHeight uint32, ignoreLocked bool) ([]*utxo.Spend, error) {
@@ -866,6 +1288,10 @@ func (v *Validator) sendToBlockAssembler(ctx context.Context, bData *blockassemb
 		v.logger.Debugf("[Validator] sending tx %s to block assembler", bData.TxIDChainHash.String())
 	}
 
+	if correlationID := tracing.CorrelationIDFromContext(ctx); correlationID != "" {
+		v.logger.Infof("[Validator][%s] sending transaction with correlation id %s to block assembly", bData.TxIDChainHash.String(), correlationID)
+	}
+
 	if _, err := v.blockAssembler.Store(ctx, &bData.TxIDChainHash, bData.Fee, bData.Size, bData.TxInpoints); err != nil {
 		e := errors.NewServiceError("error calling blockAssembler Store()", err)
 		span.RecordError(e)
@@ -952,7 +1378,9 @@ func (v *Validator) validateTransaction(ctx context.Context, tx *bt.Tx, blockHei
 	}
 
 	// run the internal tx validation, checking policies, scripts, signatures etc.
-	return v.txValidator.ValidateTransaction(tx, blockHeight, utxoHeights, validationOptions)
+	return runLimitedValidation(ctx, func() error {
+		return v.txValidator.ValidateTransaction(tx, blockHeight, utxoHeights, validationOptions)
+	})
 }
 
 // validateTransactionScripts performs script validation for a transaction
@@ -976,5 +1404,20 @@ func (v *Validator) validateTransactionScripts(ctx context.Context, tx *bt.Tx, b
 	}
 
 	// run the internal tx validation, checking policies, scripts, signatures etc.
-	return v.txValidator.ValidateTransactionScripts(tx, blockHeight, utxoHeights, validationOptions)
+	return runLimitedValidation(ctx, func() error {
+		return v.txValidator.ValidateTransactionScripts(tx, blockHeight, utxoHeights, validationOptions)
+	})
+}
+
+// runLimitedValidation runs the CPU bound checks of a transaction once the node wide validation limiter has a free
+// slot. The slot is only held while the checks run, never while waiting on other validations, so validations nested
+// in other validations, like the batches subtree validation sends to the validator, cannot deadlock.
+func runLimitedValidation(ctx context.Context, validate func() error) error {
+	release, err := util.AcquireValidation(ctx)
+	if err != nil {
+		return errors.NewContextCanceledError("transaction validation cancelled while waiting for a validation slot", err)
+	}
+	defer release()
+
+	return validate()
 }
@@ -815,6 +815,129 @@ func TestValidator_ValidateInternal_GeneralSpendError(t *testing.T) {
 	mockStore.AssertExpectations(t)
 }
 
+func TestValidator_ValidateInternal_SkipKnownTransaction(t *testing.T) {
+	ctx := context.Background()
+	logger := ulogger.TestLogger{}
+	mockStore := &utxo.MockUtxostore{}
+	settings := test.CreateBaseTestSettings(t)
+	settings.Validator.SkipKnownTransactions = true
+
+	validator, err := New(ctx, logger, settings, mockStore, nil, nil, nil, nil)
+	require.NoError(t, err)
+	v := validator.(*Validator)
+
+	// Create transaction
+	privateKey, publicKey := bec.PrivateKeyFromBytes([]byte("THIS_IS_A_DETERMINISTIC_PRIVATE_KEY"))
+	coinbaseTx := transactions.Create(t,
+		transactions.WithCoinbaseData(100, "/Test miner/"),
+		transactions.WithP2PKHOutputs(1, 50e8, publicKey),
+	)
+	tx := transactions.Create(t,
+		transactions.WithPrivateKey(privateKey),
+		transactions.WithInput(coinbaseTx, 0),
+		transactions.WithP2PKHOutputs(1, 1000),
+		transactions.WithChangeOutput(),
+	)
+
+	mockStore.On("GetBlockState").Return(utxo.BlockState{Height: 100, MedianTime: 1000000000})
+
+	// the tx is already known, no extension, spending or storing should take place
+	knownTxMeta := &meta.Data{Tx: tx, BlockIDs: []uint32{1}}
+	mockStore.On("GetMeta", mock.Anything, tx.TxIDChainHash()).Return(knownTxMeta, nil).Once()
+
+	options := &Options{}
+	txMetaData, err := v.validateInternal(ctx, tx, 100, options)
+
+	require.NoError(t, err)
+	assert.Equal(t, knownTxMeta, txMetaData)
+	mockStore.AssertExpectations(t)
+	mockStore.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+	mockStore.AssertNotCalled(t, "Spend", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestValidator_ValidateInternal_SkipKnownTransaction_Conflicting(t *testing.T) {
+	ctx := context.Background()
+	logger := ulogger.TestLogger{}
+	mockStore := &utxo.MockUtxostore{}
+	settings := test.CreateBaseTestSettings(t)
+	settings.Validator.SkipKnownTransactions = true
+
+	validator, err := New(ctx, logger, settings, mockStore, nil, nil, nil, nil)
+	require.NoError(t, err)
+	v := validator.(*Validator)
+
+	// Create transaction
+	privateKey, publicKey := bec.PrivateKeyFromBytes([]byte("THIS_IS_A_DETERMINISTIC_PRIVATE_KEY"))
+	coinbaseTx := transactions.Create(t,
+		transactions.WithCoinbaseData(100, "/Test miner/"),
+		transactions.WithP2PKHOutputs(1, 50e8, publicKey),
+	)
+	tx := transactions.Create(t,
+		transactions.WithPrivateKey(privateKey),
+		transactions.WithInput(coinbaseTx, 0),
+		transactions.WithP2PKHOutputs(1, 1000),
+		transactions.WithChangeOutput(),
+	)
+
+	// Mock parent tx extension
+	parentTxMeta := &meta.Data{Tx: coinbaseTx, BlockHeights: []uint32{}}
+	mockStore.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(parentTxMeta, nil)
+	mockStore.On("GetBlockState").Return(utxo.BlockState{Height: 100, MedianTime: 1000000000})
+
+	// a conflicting tx is not considered known and must go through the full validation
+	mockStore.On("GetMeta", mock.Anything, tx.TxIDChainHash()).Return(&meta.Data{Tx: tx, Conflicting: true}, nil).Once()
+
+	generalErr := errors.NewProcessingError("general spending error")
+	mockStore.On("Spend", mock.Anything, tx, mock.Anything, mock.Anything).Return([]*utxo.Spend{}, generalErr)
+
+	options := &Options{}
+	_, err = v.validateInternal(ctx, tx, 100, options)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error spending utxos")
+	mockStore.AssertExpectations(t)
+}
+
+func TestValidator_ValidateInternal_StrongReadConsistency(t *testing.T) {
+	logger := ulogger.TestLogger{}
+	mockStore := &utxo.MockUtxostore{}
+	settings := test.CreateBaseTestSettings(t)
+	settings.Validator.SkipKnownTransactions = true
+
+	validator, err := New(context.Background(), logger, settings, mockStore, nil, nil, nil, nil)
+	require.NoError(t, err)
+	v := validator.(*Validator)
+
+	// Create transaction
+	privateKey, publicKey := bec.PrivateKeyFromBytes([]byte("THIS_IS_A_DETERMINISTIC_PRIVATE_KEY"))
+	coinbaseTx := transactions.Create(t,
+		transactions.WithCoinbaseData(100, "/Test miner/"),
+		transactions.WithP2PKHOutputs(1, 50e8, publicKey),
+	)
+	tx := transactions.Create(t,
+		transactions.WithPrivateKey(privateKey),
+		transactions.WithInput(coinbaseTx, 0),
+		transactions.WithP2PKHOutputs(1, 1000),
+		transactions.WithChangeOutput(),
+	)
+
+	mockStore.On("GetBlockState").Return(utxo.BlockState{Height: 100, MedianTime: 1000000000})
+
+	// the store read must be done with strong consistency, even though the caller requested eventual
+	strongCtx := mock.MatchedBy(func(ctx context.Context) bool {
+		return utxo.ReadConsistencyFromContext(ctx) == utxo.ReadConsistencyStrong
+	})
+	knownTxMeta := &meta.Data{Tx: tx, BlockIDs: []uint32{1}}
+	mockStore.On("GetMeta", strongCtx, tx.TxIDChainHash()).Return(knownTxMeta, nil).Once()
+
+	ctx := utxo.WithReadConsistency(context.Background(), utxo.ReadConsistencyEventual)
+
+	txMetaData, err := v.validateInternal(ctx, tx, 100, &Options{})
+	require.NoError(t, err)
+	assert.Equal(t, knownTxMeta, txMetaData)
+	mockStore.AssertExpectations(t)
+}
+
 // Test coverage for Health function cases: blockHeight <= 0, default case, and err != nil return
 
 func TestValidator_Health_BlockHeight_Zero_Coverage(t *testing.T) {
@@ -880,3 +1003,66 @@ func TestValidator_Health_BlockHeight_Valid_Default_Coverage(t *testing.T) {
 // 1. GetBlockHeight() returns uint32, which can't be negative
 // 2. The blockHeight == 0 case (lines 198-200) comes first in the switch
 // This is dead code that should be removed, but we've covered the reachable cases above.
+
+func TestCheckFinalInNextBlock(t *testing.T) {
+	blockState := utxo.BlockState{Height: 100, MedianTime: 1_600_000_000}
+
+	newTx := func(lockTime uint32, sequence uint32) *bt.Tx {
+		tx := bt.NewTx()
+		require.NoError(t, tx.From("0000000000000000000000000000000000000000000000000000000000000001", 0, "51", 1000))
+		tx.LockTime = lockTime
+		tx.Inputs[0].SequenceNumber = sequence
+
+		return tx
+	}
+
+	tests := []struct {
+		name     string
+		tx       *bt.Tx
+		expected bool
+	}{
+		{name: "final sequence numbers", tx: newTx(1_000, bt.DefaultSequenceNumber), expected: true},
+		{name: "no lock time", tx: newTx(0, 0), expected: true},
+		{name: "lock height reached by the next block", tx: newTx(101, 0), expected: true},
+		{name: "lock height after the next block", tx: newTx(102, 0), expected: false},
+		{name: "lock time reached by the median time past", tx: newTx(1_600_000_000, 0), expected: true},
+		{name: "lock time after the median time past", tx: newTx(1_600_000_001, 0), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFinalInNextBlock(tt.tx, blockState)
+			if tt.expected {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, errors.ErrTxLockTime))
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateInternal_RejectsNonFinal(t *testing.T) {
+	ctx := context.Background()
+	logger := ulogger.TestLogger{}
+	mockStore := &utxo.MockUtxostore{}
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Validator.RejectNonFinal = true
+
+	validator, err := New(ctx, logger, tSettings, mockStore, nil, nil, nil, nil)
+	require.NoError(t, err)
+	v := validator.(*Validator)
+
+	mockStore.On("GetBlockState").Return(utxo.BlockState{Height: 100, MedianTime: 1_600_000_000})
+
+	// the transaction is final at the requested block height, but not yet in the next block
+	tx := bt.NewTx()
+	require.NoError(t, tx.From("0000000000000000000000000000000000000000000000000000000000000001", 0, "51", 1000))
+	tx.LockTime = 500
+	tx.Inputs[0].SequenceNumber = 0
+
+	_, err = v.validateInternal(ctx, tx, 1_000, NewDefaultOptions())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrNonFinal))
+	assert.Contains(t, err.Error(), "not final in the next block at height 101")
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/bsv-blockchain/teranode/errors"
 	"github.com/bsv-blockchain/teranode/model"
 	"github.com/bsv-blockchain/teranode/services/blockassembly"
+	"github.com/bsv-blockchain/teranode/services/blockchain"
 	"github.com/bsv-blockchain/teranode/settings"
 	"github.com/bsv-blockchain/teranode/stores/blob/memory"
 	utxostore "github.com/bsv-blockchain/teranode/stores/utxo"
@@ -57,7 +58,9 @@ import (
 	kafkamessage "github.com/bsv-blockchain/teranode/util/kafka/kafka_message"
 	"github.com/bsv-blockchain/teranode/util/test"
 	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/ordishs/go-utils/expiringmap"
 	"github.com/ordishs/gocore"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -298,6 +301,116 @@ func TestValidate_RejectedTransactionChannel(t *testing.T) {
 	require.Equal(t, 1, len(rejectedTxKafkaProducerClient.PublishChannel()), "rejectedTxKafkaChan should have 1 message")
 }
 
+func TestValidate_ValidationResultChannel(t *testing.T) {
+	tracing.SetupMockTracer()
+
+	txHex := "010000000000000000ef01febe0cbd7d87d44cbd4b5adac0a5bfcdbd2b672c9113f5d74a6459a2b85569db010000008b48304502207ec38d0a4ef79c3a4286ba3e5a5b6ede1fa678af9242465140d78a901af9e4e0022100c26c377d44b761469cf0bdcdbf4931418f2c5a02ce6b72bbb7af52facd7228c1014104bc9eb4fe4cb53e35df7e7734c4c3cd91c6af7840be80f4a1fff283e2cd6ae8f7713cb263a4590263240e3c01ec36bc603c32281ac08773484dc69b8152e48cecffffffff60b74700000000001976a9148ac9bdc626352d16e18c26f431e834f9aae30e2888ac0230424700000000001976a9148ac9bdc626352d16e18c26f431e834f9aae30e2888ac1027000000000000166a148ac9bdc626352d16e18c26f431e834f9aae30e2800000000"
+
+	newValidator := func(t *testing.T) *Validator {
+		utxoStore, _ := nullstore.NewNullStore()
+		_ = utxoStore.SetBlockHeight(257727)
+		//nolint:gosec
+		_ = utxoStore.SetMedianBlockTime(uint32(time.Now().Unix()))
+
+		initPrometheusMetrics()
+
+		tSettings := settings.NewSettings()
+		tSettings.ChainCfgParams = &chaincfg.MainNetParams
+
+		return &Validator{
+			logger:                    ulogger.TestLogger{},
+			settings:                  tSettings,
+			txValidator:               NewTxValidator(ulogger.TestLogger{}, tSettings),
+			utxoStore:                 utxoStore,
+			blockAssembler:            &MockBlockAssemblyStore{},
+			saveInParallel:            true,
+			stats:                     gocore.NewStat("validator"),
+			txmetaKafkaProducerClient: kafka.NewKafkaAsyncProducerMock(),
+		}
+	}
+
+	readResult := func(t *testing.T, producer *kafka.KafkaAsyncProducerMock) *kafkamessage.KafkaTxValidationResultTopicMessage {
+		select {
+		case msg := <-producer.PublishChannel():
+			var result kafkamessage.KafkaTxValidationResultTopicMessage
+			require.NoError(t, proto.Unmarshal(msg.Value, &result))
+			assert.Equal(t, result.TxHash, string(msg.Key))
+
+			return &result
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for validation result")
+			return nil
+		}
+	}
+
+	t.Run("valid and invalid transactions are published", func(t *testing.T) {
+		v := newValidator(t)
+
+		validationResultProducer := kafka.NewKafkaAsyncProducerMock()
+		v.startValidationResultPublisher(t.Context(), validationResultProducer)
+
+		tx, err := bt.NewTxFromString(txHex)
+		require.NoError(t, err)
+
+		start := time.Now().UnixMilli()
+
+		_, err = v.Validate(t.Context(), tx, 257727, WithSkipPolicyChecks(true))
+		require.NoError(t, err)
+
+		result := readResult(t, validationResultProducer)
+		assert.Equal(t, tx.TxID(), result.TxHash)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Reason)
+		assert.GreaterOrEqual(t, result.Timestamp, start)
+
+		// set previous sats to 0, which makes the tx invalid
+		invalidTx, err := bt.NewTxFromString(txHex)
+		require.NoError(t, err)
+
+		invalidTx.Inputs[0].PreviousTxSatoshis = 0
+
+		_, err = v.Validate(t.Context(), invalidTx, 257727, WithSkipPolicyChecks(false))
+		require.Error(t, err)
+
+		result = readResult(t, validationResultProducer)
+		assert.Equal(t, invalidTx.TxID(), result.TxHash)
+		assert.False(t, result.Valid)
+		assert.Equal(t, err.Error(), result.Reason)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		v := newValidator(t)
+
+		tx, err := bt.NewTxFromString(txHex)
+		require.NoError(t, err)
+
+		_, err = v.Validate(t.Context(), tx, 257727, WithSkipPolicyChecks(true))
+		require.NoError(t, err)
+
+		assert.Nil(t, v.validationResultCh)
+	})
+
+	t.Run("full buffer does not block validation", func(t *testing.T) {
+		v := newValidator(t)
+
+		// no publisher is draining the buffer
+		v.validationResultCh = make(chan *kafkamessage.KafkaTxValidationResultTopicMessage, 1)
+
+		tx, err := bt.NewTxFromString(txHex)
+		require.NoError(t, err)
+
+		dropped := testutil.ToFloat64(prometheusValidatorValidationResultsDropped)
+
+		for i := 0; i < 3; i++ {
+			_, err = v.Validate(t.Context(), tx, 257727, WithSkipPolicyChecks(true))
+			require.NoError(t, err)
+		}
+
+		assert.Len(t, v.validationResultCh, 1)
+		assert.Equal(t, dropped+2, testutil.ToFloat64(prometheusValidatorValidationResultsDropped))
+	})
+}
+
 func TestValidate_InValidDoubleSpendTx(t *testing.T) {
 }
 
@@ -819,8 +932,9 @@ func Test_getUtxoBlockHeights(t *testing.T) {
 			BlockHeights: make([]uint32, 0),
 		}, nil)
 
-		utxoHashes, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID())
+		utxoHashes, unconfirmedInputs, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
 		require.NoError(t, err)
+		assert.Equal(t, 3, unconfirmedInputs)
 
 		expected := []uint32{1000, 1000, 1000}
 
@@ -857,8 +971,9 @@ func Test_getUtxoBlockHeights(t *testing.T) {
 			BlockHeights: []uint32{768, 769},
 		}, nil).Once()
 
-		utxoHashes, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID())
+		utxoHashes, unconfirmedInputs, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
 		require.NoError(t, err)
+		assert.Equal(t, 1, unconfirmedInputs)
 
 		expected := []uint32{125, 1000, 768}
 
@@ -924,8 +1039,9 @@ func Test_getUtxoBlockHeights(t *testing.T) {
 			},
 		}, nil).Once()
 
-		utxoHashes, err := v.getUtxoBlockHeightsAndExtendTx(ctx, txNonExtended, txNonExtended.TxID())
+		utxoHashes, unconfirmedInputs, err := v.getUtxoBlockHeightsAndExtendTx(ctx, txNonExtended, txNonExtended.TxID(), &Options{})
 		require.NoError(t, err)
+		assert.Equal(t, 1, unconfirmedInputs)
 
 		expected := []uint32{125, 1000, 768}
 
@@ -945,6 +1061,31 @@ func Test_getUtxoBlockHeights(t *testing.T) {
 	})
 }
 
+func TestValidator_checkUnconfirmedInputs(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+
+	v := &Validator{settings: tSettings}
+
+	t.Run("unlimited", func(t *testing.T) {
+		tSettings.Policy.SetMaxUnconfirmedInputsPolicy(0)
+
+		require.NoError(t, v.checkUnconfirmedInputs(0))
+		require.NoError(t, v.checkUnconfirmedInputs(10_000))
+	})
+
+	t.Run("cap boundary", func(t *testing.T) {
+		tSettings.Policy.SetMaxUnconfirmedInputsPolicy(3)
+
+		require.NoError(t, v.checkUnconfirmedInputs(0))
+		require.NoError(t, v.checkUnconfirmedInputs(2))
+		require.NoError(t, v.checkUnconfirmedInputs(3))
+
+		err := v.checkUnconfirmedInputs(4)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxPolicy))
+	})
+}
+
 var tx, _ = bt.NewTxFromString("010000000000000000ef01c2945d5f275f6eee3a4e0c98382f0851a670e839e7e56453fbe6c78ddc093ab7000000006a4730440220633afe2995ed52b7f67c8c01efc2e4db73490de57e9a619319987e8f850c661b022032f59a4987b5ecee94f1f7c1e0411bea8c7709cdcf358499bc92dffad5646523412103184f5441e86260412485efa64e31b7a6f9f7c078078abe685ca53db35701471effffffff00f2052a010000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88acfdf40180969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac80969800000000001976a914c362d5af234dd4e1f2a1bfbcab90036d38b0aa9f88ac00000000")
 
 func TestFalseOrEmptyTopStackElementScriptError(t *testing.T) {
@@ -1226,3 +1367,210 @@ func TestValidator_LockedFlagNotChangedIfBlockAssemblyDidNotStoreTx(t *testing.T
 	require.NoError(t, err)
 	assert.True(t, meta.Locked, "Flag should be set if block assembly did not store tx")
 }
+
+func TestCoinbaseSpendAcrossReorg(t *testing.T) {
+	ctx := context.Background()
+
+	coinbaseTxHash := chainhash.HashH([]byte("coinbase"))
+	coinbaseOutput := &bt.Output{Satoshis: 50_0000_0000, LockingScript: bscript.NewFromBytes([]byte{0x51})}
+
+	newTx := func() *bt.Tx {
+		tx := bt.NewTx()
+
+		input := &bt.Input{PreviousTxOutIndex: 0, SequenceNumber: bt.DefaultSequenceNumber}
+		require.NoError(t, input.PreviousTxIDAdd(&coinbaseTxHash))
+
+		tx.Inputs = append(tx.Inputs, input)
+
+		return tx
+	}
+
+	newValidator := func(t *testing.T, checkCoinbaseOnChain bool) (*Validator, *blockchain.Mock) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Validator.CheckCoinbaseOnChain = checkCoinbaseOnChain
+
+		mockUtxoStore := &utxostore.MockUtxostore{}
+		mockUtxoStore.On("GetBlockState").Return(utxostore.BlockState{Height: 200, MedianTime: 1000000000})
+		mockUtxoStore.On("Get", mock.Anything, &coinbaseTxHash, mock.Anything).Return(&meta.Data{
+			BlockIDs:     []uint32{7},
+			BlockHeights: []uint32{100},
+			IsCoinbase:   true,
+			Tx:           &bt.Tx{Outputs: []*bt.Output{coinbaseOutput}},
+		}, nil)
+
+		mockBlockchainClient := &blockchain.Mock{}
+
+		return &Validator{
+			settings:         tSettings,
+			utxoStore:        mockUtxoStore,
+			blockchainClient: mockBlockchainClient,
+		}, mockBlockchainClient
+	}
+
+	t.Run("mature coinbase spend on the current chain", func(t *testing.T) {
+		v, mockBlockchainClient := newValidator(t, true)
+		mockBlockchainClient.On("CheckBlockIsInCurrentChain", mock.Anything, []uint32{7}).Return(true, nil)
+
+		tx := newTx()
+
+		utxoHeights, _, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
+		require.NoError(t, err)
+
+		// the maturity is checked with the height of the coinbase block on the current chain
+		assert.Equal(t, []uint32{100}, utxoHeights)
+		assert.Equal(t, coinbaseOutput.Satoshis, tx.Inputs[0].PreviousTxSatoshis)
+	})
+
+	t.Run("coinbase reorged out of the current chain", func(t *testing.T) {
+		v, mockBlockchainClient := newValidator(t, true)
+
+		// the spend is valid before the reorg, the block of the coinbase is not on the current chain after it
+		mockBlockchainClient.On("CheckBlockIsInCurrentChain", mock.Anything, []uint32{7}).Return(true, nil).Once()
+		mockBlockchainClient.On("CheckBlockIsInCurrentChain", mock.Anything, []uint32{7}).Return(false, nil)
+
+		tx := newTx()
+
+		_, _, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
+		require.NoError(t, err)
+
+		tx = newTx()
+
+		_, _, err = v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxInvalid))
+		assert.Contains(t, err.Error(), "not on the current chain")
+	})
+
+	t.Run("not checked for the transactions of blocks", func(t *testing.T) {
+		v, mockBlockchainClient := newValidator(t, true)
+
+		// the block of the transaction can be on another chain than the current chain
+		tx := newTx()
+
+		utxoHeights, _, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{SkipPolicyChecks: true})
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{100}, utxoHeights)
+
+		mockBlockchainClient.AssertNotCalled(t, "CheckBlockIsInCurrentChain", mock.Anything, mock.Anything)
+	})
+
+	t.Run("check disabled", func(t *testing.T) {
+		v, mockBlockchainClient := newValidator(t, false)
+
+		tx := newTx()
+
+		_, _, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
+		require.NoError(t, err)
+
+		mockBlockchainClient.AssertNotCalled(t, "CheckBlockIsInCurrentChain", mock.Anything, mock.Anything)
+	})
+}
+
+func TestValidate_TxMetaNotificationDedup(t *testing.T) {
+	tracing.SetupMockTracer()
+
+	txHex := "010000000000000000ef01febe0cbd7d87d44cbd4b5adac0a5bfcdbd2b672c9113f5d74a6459a2b85569db010000008b48304502207ec38d0a4ef79c3a4286ba3e5a5b6ede1fa678af9242465140d78a901af9e4e0022100c26c377d44b761469cf0bdcdbf4931418f2c5a02ce6b72bbb7af52facd7228c1014104bc9eb4fe4cb53e35df7e7734c4c3cd91c6af7840be80f4a1fff283e2cd6ae8f7713cb263a4590263240e3c01ec36bc603c32281ac08773484dc69b8152e48cecffffffff60b74700000000001976a9148ac9bdc626352d16e18c26f431e834f9aae30e2888ac0230424700000000001976a9148ac9bdc626352d16e18c26f431e834f9aae30e2888ac1027000000000000166a148ac9bdc626352d16e18c26f431e834f9aae30e2800000000"
+
+	initPrometheusMetrics()
+
+	// validates the transaction twice, as when it is resubmitted, and returns the number of txmeta notifications
+	validateTwice := func(t *testing.T, dedupWindow time.Duration) int {
+		tx, err := bt.NewTxFromString(txHex)
+		require.NoError(t, err)
+
+		utxoStore, _ := nullstore.NewNullStore()
+		_ = utxoStore.SetBlockHeight(257727)
+		//nolint:gosec
+		_ = utxoStore.SetMedianBlockTime(uint32(time.Now().Unix()))
+
+		tSettings := settings.NewSettings()
+		tSettings.ChainCfgParams = &chaincfg.MainNetParams
+		tSettings.Validator.TxMetaDedupWindow = dedupWindow
+
+		txmetaKafkaProducerClient := kafka.NewKafkaAsyncProducerMock()
+
+		v := &Validator{
+			logger:                        ulogger.TestLogger{},
+			settings:                      tSettings,
+			txValidator:                   NewTxValidator(ulogger.TestLogger{}, tSettings),
+			utxoStore:                     utxoStore,
+			blockAssembler:                &MockBlockAssemblyStore{},
+			saveInParallel:                true,
+			stats:                         gocore.NewStat("validator"),
+			txmetaKafkaProducerClient:     txmetaKafkaProducerClient,
+			rejectedTxKafkaProducerClient: kafka.NewKafkaAsyncProducerMock(),
+		}
+
+		if dedupWindow > 0 {
+			v.txMetaNotified = expiringmap.New[chainhash.Hash, struct{}](dedupWindow)
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err = v.Validate(t.Context(), tx, 257727, WithSkipPolicyChecks(true))
+			require.NoError(t, err)
+		}
+
+		return len(txmetaKafkaProducerClient.PublishChannel())
+	}
+
+	t.Run("re-accepted transaction notifies once", func(t *testing.T) {
+		assert.Equal(t, 1, validateTwice(t, time.Minute))
+	})
+
+	t.Run("deduplication disabled", func(t *testing.T) {
+		assert.Equal(t, 2, validateTwice(t, 0))
+	})
+}
+
+func TestValidate_SkipScriptVerification(t *testing.T) {
+	tracing.SetupMockTracer()
+
+	txHex := "010000000000000000ef01febe0cbd7d87d44cbd4b5adac0a5bfcdbd2b672c9113f5d74a6459a2b85569db010000008b48304502207ec38d0a4ef79c3a4286ba3e5a5b6ede1fa678af9242465140d78a901af9e4e0022100c26c377d44b761469cf0bdcdbf4931418f2c5a02ce6b72bbb7af52facd7228c1014104bc9eb4fe4cb53e35df7e7734c4c3cd91c6af7840be80f4a1fff283e2cd6ae8f7713cb263a4590263240e3c01ec36bc603c32281ac08773484dc69b8152e48cecffffffff60b74700000000001976a9148ac9bdc626352d16e18c26f431e834f9aae30e2888ac0230424700000000001976a9148ac9bdc626352d16e18c26f431e834f9aae30e2888ac1027000000000000166a148ac9bdc626352d16e18c26f431e834f9aae30e2800000000"
+
+	newValidator := func() *Validator {
+		utxoStore, _ := nullstore.NewNullStore()
+		_ = utxoStore.SetBlockHeight(257727)
+		//nolint:gosec
+		_ = utxoStore.SetMedianBlockTime(uint32(time.Now().Unix()))
+
+		initPrometheusMetrics()
+
+		tSettings := settings.NewSettings()
+		tSettings.ChainCfgParams = &chaincfg.MainNetParams
+
+		return &Validator{
+			logger:                        ulogger.TestLogger{},
+			settings:                      tSettings,
+			txValidator:                   NewTxValidator(ulogger.TestLogger{}, tSettings),
+			utxoStore:                     utxoStore,
+			blockAssembler:                &MockBlockAssemblyStore{},
+			saveInParallel:                true,
+			stats:                         gocore.NewStat("validator"),
+			txmetaKafkaProducerClient:     kafka.NewKafkaAsyncProducerMock(),
+			rejectedTxKafkaProducerClient: kafka.NewKafkaAsyncProducerMock(),
+		}
+	}
+
+	// newBadSignatureTx returns the transaction with a signature that does not verify against the spent output
+	newBadSignatureTx := func(t *testing.T) *bt.Tx {
+		tx, err := bt.NewTxFromString(txHex)
+		require.NoError(t, err)
+
+		lockingScript := append(bscript.Script{}, *tx.Inputs[0].PreviousTxScript...)
+		lockingScript[3] ^= 0xff
+		tx.Inputs[0].PreviousTxScript = &lockingScript
+
+		return tx
+	}
+
+	t.Run("scripts are verified by default", func(t *testing.T) {
+		_, err := newValidator().Validate(t.Context(), newBadSignatureTx(t), 257727, WithSkipPolicyChecks(true))
+		require.Error(t, err)
+		assert.True(t, IsScriptValidationError(err))
+	})
+
+	t.Run("scripts are not verified when skipped", func(t *testing.T) {
+		_, err := newValidator().Validate(t.Context(), newBadSignatureTx(t), 257727, WithSkipPolicyChecks(true), WithSkipScriptVerification(true))
+		require.NoError(t, err)
+	})
+}
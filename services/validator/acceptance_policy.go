@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// AcceptancePolicy evaluates custom business rules on transactions, e.g. rejecting transactions with
+// outputs that are not tagged as required by a deployment, without forking the validator.
+//
+// The policy is evaluated after a transaction passed the standard validation, including the scripts, and
+// before its inputs are spent. Like the other policy checks it is skipped when validating with
+// WithSkipPolicyChecks, e.g. for the transactions of a block, which are accepted by consensus rules only.
+type AcceptancePolicy interface {
+	// Evaluate returns an error when the transaction must be rejected, nil to accept it.
+	//
+	// The transaction is extended with the outputs spent by its inputs and must not be modified.
+	Evaluate(tx *bt.Tx) error
+}
+
+// AcceptancePolicyFunc is a function implementing AcceptancePolicy.
+type AcceptancePolicyFunc func(tx *bt.Tx) error
+
+// Evaluate calls f(tx).
+func (f AcceptancePolicyFunc) Evaluate(tx *bt.Tx) error {
+	return f(tx)
+}
+
+// WithAcceptancePolicy sets the acceptance policy evaluated on every transaction validated with policy checks.
+func WithAcceptancePolicy(policy AcceptancePolicy) func(*Validator) {
+	return func(v *Validator) {
+		v.acceptancePolicy = policy
+	}
+}
+
+// checkAcceptancePolicy evaluates the acceptance policy, if any, on the transaction, returning a policy
+// error when the policy rejects it.
+func (v *Validator) checkAcceptancePolicy(tx *bt.Tx) error {
+	if v.acceptancePolicy == nil {
+		return nil
+	}
+
+	if err := v.acceptancePolicy.Evaluate(tx); err != nil {
+		return errors.NewTxPolicyError("transaction rejected by acceptance policy", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/stores/utxo/tests"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptancePolicy(t *testing.T) {
+	tracing.SetupMockTracer()
+
+	rejectedTxHash := *tests.Tx.TxIDChainHash()
+
+	// rejects a specific transaction, as a deployment could reject transactions without tagged outputs
+	var evaluated []chainhash.Hash
+
+	policy := AcceptancePolicyFunc(func(tx *bt.Tx) error {
+		evaluated = append(evaluated, *tx.TxIDChainHash())
+
+		if tx.TxIDChainHash().Equal(rejectedTxHash) {
+			return errors.NewError("transaction is blocked by the operator")
+		}
+
+		return nil
+	})
+
+	newValidator := func(t *testing.T, opts ...func(*Validator)) Interface {
+		ctx := context.Background()
+		logger := ulogger.NewErrorTestLogger(t)
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.BlockAssembly.Disabled = true
+
+		utxoStoreURL, err := url.Parse("sqlitememory:///test")
+		require.NoError(t, err)
+
+		utxoStore, err := sql.New(ctx, logger, tSettings, utxoStoreURL)
+		require.NoError(t, err)
+
+		_, err = utxoStore.Create(ctx, tests.ParentTx, 122)
+		require.NoError(t, err)
+
+		v, err := New(ctx, logger, tSettings, utxoStore, nil, nil, nil, nil, opts...)
+		require.NoError(t, err)
+
+		return v
+	}
+
+	t.Run("custom hook rejects the transaction", func(t *testing.T) {
+		evaluated = nil
+		v := newValidator(t, WithAcceptancePolicy(policy))
+
+		_, err := v.Validate(t.Context(), tests.Tx.Clone(), 123)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxPolicy))
+		assert.Contains(t, err.Error(), "transaction is blocked by the operator")
+		assert.Equal(t, []chainhash.Hash{rejectedTxHash}, evaluated)
+	})
+
+	t.Run("not evaluated without policy checks", func(t *testing.T) {
+		evaluated = nil
+		v := newValidator(t, WithAcceptancePolicy(policy))
+
+		_, err := v.Validate(t.Context(), tests.Tx.Clone(), 123, WithSkipPolicyChecks(true))
+		require.NoError(t, err)
+		assert.Empty(t, evaluated)
+	})
+
+	t.Run("without a hook the transaction is accepted", func(t *testing.T) {
+		v := newValidator(t)
+
+		_, err := v.Validate(t.Context(), tests.Tx.Clone(), 123)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"sync/atomic"
+
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// validationBacklog is the number of transactions awaiting validation in this process. It is shared by the
+// validator server and the in-process validators created with New, so the maximum backlog applies to all
+// submissions of the node, whichever way they reach a validator.
+var validationBacklog atomic.Int64
+
+// acquireBacklog adds n transactions to the validation backlog. When maxBacklog is set and the backlog would
+// exceed it, none of the transactions are added and a ServiceUnavailableError is returned, so the client can
+// retry the submission later instead of the transactions queuing without bound.
+//
+// Every successful call must be followed by a call to releaseBacklog with the same n once the transactions are
+// validated.
+//
+// Parameters:
+//   - maxBacklog: Maximum number of transactions awaiting validation, 0 is unlimited
+//   - n: Number of transactions submitted
+//
+// Returns:
+//   - error: ServiceUnavailableError when the backlog is full, nil otherwise
+func acquireBacklog(maxBacklog int, n int) error {
+	initPrometheusMetrics()
+
+	for {
+		backlog := validationBacklog.Load()
+
+		if maxBacklog > 0 && backlog+int64(n) > int64(maxBacklog) {
+			prometheusValidatorBacklogRejected.Add(float64(n))
+
+			return errors.NewServiceUnavailableError("[Validator] validation backlog is full, %d transactions awaiting validation, max backlog %d", backlog, maxBacklog)
+		}
+
+		if validationBacklog.CompareAndSwap(backlog, backlog+int64(n)) {
+			prometheusValidatorBacklog.Add(float64(n))
+
+			return nil
+		}
+	}
+}
+
+// addBacklog adds n transactions to the validation backlog without checking the maximum backlog. It is used for
+// transactions that are already queued elsewhere, like the messages consumed from Kafka, which cannot be rejected
+// back to the client.
+func addBacklog(n int) {
+	initPrometheusMetrics()
+
+	validationBacklog.Add(int64(n))
+	prometheusValidatorBacklog.Add(float64(n))
+}
+
+// releaseBacklog removes n validated transactions from the validation backlog.
+func releaseBacklog(n int) {
+	validationBacklog.Add(-int64(n))
+	prometheusValidatorBacklog.Sub(float64(n))
+}
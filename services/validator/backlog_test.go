@@ -0,0 +1,203 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator/validator_api"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_MaxBacklog(t *testing.T) {
+	ctx := context.Background()
+
+	// validations block until released, so the submissions stay in the backlog
+	release := make(chan struct{})
+
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Validator.MaxBacklog = 2
+
+	server := &Server{
+		logger:   ulogger.TestLogger{},
+		settings: tSettings,
+		validator: &TestMockValidator{
+			validateTxFunc: func(ctx context.Context, tx *bt.Tx) (*meta.Data, error) {
+				<-release
+				return &meta.Data{}, nil
+			},
+		},
+	}
+
+	testTx, err := bt.NewTxFromBytes(sampleTx)
+	require.NoError(t, err)
+
+	req := &validator_api.ValidateTransactionRequest{TransactionData: testTx.ExtendedBytes()}
+
+	// fill the backlog
+	var wg sync.WaitGroup
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := server.ValidateTransaction(ctx, req)
+			assert.NoError(t, err)
+		}()
+	}
+
+	require.Eventually(t, func() bool {
+		return validationBacklog.Load() == 2
+	}, time.Second, time.Millisecond)
+
+	// submissions beyond the cap are rejected
+	_, err = server.ValidateTransaction(ctx, req)
+	require.Error(t, err)
+	assert.True(t, errors.Is(errors.UnwrapGRPC(err), errors.ErrServiceUnavailable))
+
+	batchResponse, err := server.ValidateTransactionBatch(ctx, &validator_api.ValidateTransactionBatchRequest{
+		Transactions: []*validator_api.ValidateTransactionRequest{req},
+	})
+	require.NoError(t, err)
+	require.Len(t, batchResponse.Errors, 1)
+	assert.True(t, errors.Is(batchResponse.Errors[0], errors.ErrServiceUnavailable))
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/tx", bytes.NewReader(testTx.ExtendedBytes())), rec)
+
+	require.NoError(t, server.handleSingleTx(ctx)(c))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	// rejected submissions do not count towards the backlog
+	assert.Equal(t, int64(2), validationBacklog.Load())
+
+	// drain the backlog
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(0), validationBacklog.Load())
+
+	// submissions are accepted again
+	_, err = server.ValidateTransaction(ctx, req)
+	require.NoError(t, err)
+
+	response, err := server.ValidateTransactionBatch(ctx, &validator_api.ValidateTransactionBatchRequest{
+		Transactions: []*validator_api.ValidateTransactionRequest{req, req},
+	})
+	require.NoError(t, err)
+	assert.True(t, response.Valid)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodPost, "/tx", bytes.NewReader(testTx.ExtendedBytes())), rec)
+
+	require.NoError(t, server.handleSingleTx(ctx)(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, int64(0), validationBacklog.Load())
+}
+
+func TestServer_MaxBacklogBatch(t *testing.T) {
+	ctx := context.Background()
+
+	release := make(chan struct{})
+
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Validator.MaxBacklog = 2
+
+	server := &Server{
+		logger:   ulogger.TestLogger{},
+		settings: tSettings,
+		validator: &TestMockValidator{
+			validateTxFunc: func(ctx context.Context, tx *bt.Tx) (*meta.Data, error) {
+				<-release
+				return &meta.Data{}, nil
+			},
+		},
+	}
+
+	testTx, err := bt.NewTxFromBytes(sampleTx)
+	require.NoError(t, err)
+
+	req := &validator_api.ValidateTransactionRequest{TransactionData: testTx.ExtendedBytes()}
+
+	responseCh := make(chan *validator_api.ValidateTransactionBatchResponse, 1)
+
+	// a batch larger than the maximum backlog is accepted up to the maximum
+	go func() {
+		response, err := server.ValidateTransactionBatch(ctx, &validator_api.ValidateTransactionBatchRequest{
+			Transactions: []*validator_api.ValidateTransactionRequest{req, req, req},
+		})
+		assert.NoError(t, err)
+
+		responseCh <- response
+	}()
+
+	require.Eventually(t, func() bool {
+		return validationBacklog.Load() == 2
+	}, time.Second, time.Millisecond)
+
+	close(release)
+
+	response := <-responseCh
+	require.Len(t, response.Errors, 3)
+
+	rejected := 0
+
+	for _, txErr := range response.Errors {
+		if txErr != nil {
+			assert.True(t, errors.Is(txErr, errors.ErrServiceUnavailable))
+			rejected++
+		}
+	}
+
+	assert.Equal(t, 1, rejected)
+	assert.Equal(t, int64(0), validationBacklog.Load())
+}
+
+func TestValidator_MaxBacklog(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.Validator.MaxBacklog = 1
+
+	v := &Validator{logger: ulogger.TestLogger{}, settings: tSettings}
+
+	testTx, err := bt.NewTxFromBytes(sampleTx)
+	require.NoError(t, err)
+
+	// another submission fills the backlog
+	require.NoError(t, acquireBacklog(tSettings.Validator.MaxBacklog, 1))
+
+	// an in-process submission is rejected like a submission to the server
+	_, err = v.ValidateWithOptions(context.Background(), testTx, 0, NewDefaultOptions())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrServiceUnavailable))
+
+	releaseBacklog(1)
+
+	assert.Equal(t, int64(0), validationBacklog.Load())
+}
+
+func TestServer_MaxBacklogUnlimited(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, acquireBacklog(0, 1))
+	}
+
+	assert.Equal(t, int64(1000), validationBacklog.Load())
+
+	releaseBacklog(1000)
+
+	assert.Equal(t, int64(0), validationBacklog.Load())
+}
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"bytes"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// Canonical orderings of the inputs and outputs of a transaction, configured with validator_canonicalTxOrdering
+const (
+	// CanonicalTxOrderingNone does not require any ordering of the inputs and outputs
+	CanonicalTxOrderingNone = "none"
+
+	// CanonicalTxOrderingBIP69 requires the inputs and outputs to be ordered as specified in BIP69: the inputs
+	// by previous txid, in the byte order of its hex representation, then by previous output index, and the
+	// outputs by amount, then by locking script bytes
+	CanonicalTxOrderingBIP69 = "bip69"
+)
+
+// checkCanonicalTxOrderingSetting returns an error when the configured canonical transaction ordering is unknown.
+func checkCanonicalTxOrderingSetting(ordering string) error {
+	switch ordering {
+	case "", CanonicalTxOrderingNone, CanonicalTxOrderingBIP69:
+		return nil
+	default:
+		return errors.NewConfigurationError("unknown canonical transaction ordering %q in validator_canonicalTxOrdering, expected %q or %q",
+			ordering, CanonicalTxOrderingNone, CanonicalTxOrderingBIP69)
+	}
+}
+
+// checkCanonicalOrdering validates that the inputs and outputs of the transaction follow the canonical ordering
+// configured in the validator_canonicalTxOrdering setting. No ordering is required when none is configured.
+func (tv *TxValidator) checkCanonicalOrdering(tx *bt.Tx) error {
+	switch ordering := tv.settings.Validator.CanonicalTxOrdering; ordering {
+	case "", CanonicalTxOrderingNone:
+		return nil
+	case CanonicalTxOrderingBIP69:
+		return checkBIP69Ordering(tx)
+	default:
+		return checkCanonicalTxOrderingSetting(ordering)
+	}
+}
+
+// checkBIP69Ordering validates that the inputs and outputs of the transaction are ordered as specified in BIP69.
+func checkBIP69Ordering(tx *bt.Tx) error {
+	for index := 1; index < len(tx.Inputs); index++ {
+		prev, input := tx.Inputs[index-1], tx.Inputs[index]
+
+		cmp := compareTxIDs(prev.PreviousTxIDChainHash(), input.PreviousTxIDChainHash())
+		if cmp > 0 || (cmp == 0 && prev.PreviousTxOutIndex > input.PreviousTxOutIndex) {
+			return inputError(errors.NewTxInvalidError("transaction input %d is not in canonical %s order", index, CanonicalTxOrderingBIP69), index)
+		}
+	}
+
+	for index := 1; index < len(tx.Outputs); index++ {
+		prev, output := tx.Outputs[index-1], tx.Outputs[index]
+
+		if prev.Satoshis > output.Satoshis ||
+			(prev.Satoshis == output.Satoshis && bytes.Compare(outputScriptBytes(prev), outputScriptBytes(output)) > 0) {
+			return errors.NewTxInvalidError("transaction output %d is not in canonical %s order", index, CanonicalTxOrderingBIP69)
+		}
+	}
+
+	return nil
+}
+
+// compareTxIDs compares the txids in the byte order of their hex representation, which is the reverse of the
+// byte order of the hashes.
+func compareTxIDs(a, b *chainhash.Hash) int {
+	for i := chainhash.HashSize - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// outputScriptBytes returns the bytes of the locking script of the output, nil when the output has no script
+func outputScriptBytes(output *bt.Output) []byte {
+	if output.LockingScript == nil {
+		return nil
+	}
+
+	return *output.LockingScript
+}
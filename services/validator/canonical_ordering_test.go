@@ -0,0 +1,149 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBIP69Ordering(t *testing.T) {
+	// txids in the byte order of their hex representation, the internal byte order is reversed, so lowTxID has
+	// the higher first internal byte
+	const (
+		lowTxID  = "0000000000000000000000000000000000000000000000000000000000000001"
+		highTxID = "ff00000000000000000000000000000000000000000000000000000000000000"
+	)
+
+	newTx := func(t *testing.T, inputs [][2]interface{}, outputs [][2]interface{}) *bt.Tx {
+		tx := bt.NewTx()
+
+		for _, input := range inputs {
+			require.NoError(t, tx.From(input[0].(string), input[1].(uint32), "51", 1000))
+		}
+
+		for _, output := range outputs {
+			script, err := bscript.NewFromHexString(output[1].(string))
+			require.NoError(t, err)
+
+			tx.AddOutput(&bt.Output{Satoshis: output[0].(uint64), LockingScript: script})
+		}
+
+		return tx
+	}
+
+	tests := []struct {
+		name    string
+		inputs  [][2]interface{}
+		outputs [][2]interface{}
+		errMsg  string
+	}{
+		{
+			name:    "canonical",
+			inputs:  [][2]interface{}{{lowTxID, uint32(0)}, {lowTxID, uint32(1)}, {highTxID, uint32(0)}},
+			outputs: [][2]interface{}{{uint64(100), "51"}, {uint64(100), "52"}, {uint64(200), "00"}},
+		},
+		{
+			name:    "equal inputs and outputs",
+			inputs:  [][2]interface{}{{lowTxID, uint32(0)}, {lowTxID, uint32(0)}},
+			outputs: [][2]interface{}{{uint64(100), "51"}, {uint64(100), "51"}},
+		},
+		{
+			name:    "single input and output",
+			inputs:  [][2]interface{}{{highTxID, uint32(5)}},
+			outputs: [][2]interface{}{{uint64(100), "51"}},
+		},
+		{
+			name:    "inputs not ordered by txid",
+			inputs:  [][2]interface{}{{highTxID, uint32(0)}, {lowTxID, uint32(0)}},
+			outputs: [][2]interface{}{{uint64(100), "51"}},
+			errMsg:  "transaction input 1 is not in canonical bip69 order",
+		},
+		{
+			name:    "inputs not ordered by output index",
+			inputs:  [][2]interface{}{{lowTxID, uint32(0)}, {lowTxID, uint32(2)}, {lowTxID, uint32(1)}},
+			outputs: [][2]interface{}{{uint64(100), "51"}},
+			errMsg:  "transaction input 2 is not in canonical bip69 order",
+		},
+		{
+			name:    "outputs not ordered by amount",
+			inputs:  [][2]interface{}{{lowTxID, uint32(0)}},
+			outputs: [][2]interface{}{{uint64(200), "00"}, {uint64(100), "51"}},
+			errMsg:  "transaction output 1 is not in canonical bip69 order",
+		},
+		{
+			name:    "outputs not ordered by script",
+			inputs:  [][2]interface{}{{lowTxID, uint32(0)}},
+			outputs: [][2]interface{}{{uint64(100), "52"}, {uint64(100), "5151"}},
+			errMsg:  "transaction output 1 is not in canonical bip69 order",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBIP69Ordering(newTx(t, tt.inputs, tt.outputs))
+
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errors.ErrTxInvalid)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	t.Run("txids compared in hex order", func(t *testing.T) {
+		tx := newTx(t, [][2]interface{}{{lowTxID, uint32(0)}, {highTxID, uint32(0)}}, [][2]interface{}{{uint64(100), "51"}})
+
+		// in the internal byte order the first txid is the higher one
+		assert.Greater(t, tx.Inputs[0].PreviousTxIDChainHash()[0], tx.Inputs[1].PreviousTxIDChainHash()[0])
+		assert.Equal(t, -1, compareTxIDs(tx.Inputs[0].PreviousTxIDChainHash(), tx.Inputs[1].PreviousTxIDChainHash()))
+	})
+}
+
+func TestTxValidator_checkCanonicalOrdering(t *testing.T) {
+	tx := bt.NewTx()
+	require.NoError(t, tx.From("ff00000000000000000000000000000000000000000000000000000000000000", 0, "51", 1000))
+	require.NoError(t, tx.From("0000000000000000000000000000000000000000000000000000000000000001", 0, "51", 1000))
+	tx.AddOutput(&bt.Output{Satoshis: 100, LockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE})})
+
+	for _, ordering := range []string{"", CanonicalTxOrderingNone} {
+		t.Run("disabled "+ordering, func(t *testing.T) {
+			tSettings := test.CreateBaseTestSettings(t)
+			tSettings.Validator.CanonicalTxOrdering = ordering
+
+			require.NoError(t, NewTxValidator(ulogger.TestLogger{}, tSettings).checkCanonicalOrdering(tx))
+		})
+	}
+
+	t.Run("bip69", func(t *testing.T) {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Validator.CanonicalTxOrdering = CanonicalTxOrderingBIP69
+
+		txValidator := NewTxValidator(ulogger.TestLogger{}, tSettings)
+
+		err := txValidator.checkCanonicalOrdering(tx)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrTxInvalid)
+
+		// the check is not skipped with the policy checks
+		err = txValidator.ValidateTransaction(tx, tSettings.ChainCfgParams.GenesisActivationHeight+1, nil, &Options{SkipPolicyChecks: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transaction input 1 is not in canonical bip69 order")
+	})
+
+	t.Run("unknown ordering", func(t *testing.T) {
+		require.NoError(t, checkCanonicalTxOrderingSetting(CanonicalTxOrderingBIP69))
+
+		err := checkCanonicalTxOrderingSetting("lexicographic")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrConfiguration)
+	})
+}
@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"github.com/bsv-blockchain/go-chaincfg"
+)
+
+const (
+	// MaxTxSizeConsensusBeforeGenesis defines the maximum size of a transaction in bytes before the Genesis upgrade (1MB)
+	MaxTxSizeConsensusBeforeGenesis = 1_000_000
+
+	// MaxTxSizeConsensusAfterGenesis defines the maximum size of a transaction in bytes after the Genesis upgrade (1GB)
+	MaxTxSizeConsensusAfterGenesis = 1_000_000_000
+
+	// MaxScriptSizeConsensusBeforeGenesis defines the maximum size of a script in bytes before the Genesis upgrade.
+	// After the Genesis upgrade the script size is only limited by the transaction size.
+	MaxScriptSizeConsensusBeforeGenesis = 10_000
+)
+
+// ConsensusRules is the set of consensus rules that is active at a block height. Validation uses the rule set of
+// the height a transaction is validated at, instead of comparing the height with activation heights itself, so
+// all height-gated behaviour is decided in a single place.
+type ConsensusRules struct {
+	// Height is the block height the rules are active at
+	Height uint32
+
+	// MedianTimePastFinality checks the finality of transactions against the median time past of the previous
+	// blocks (BIP113), active after the CSV fork
+	MedianTimePastFinality bool
+
+	// ForkID requires signatures to commit to the fork id, active after the UAHF fork
+	ForkID bool
+
+	// PushOnlyUnlockingScripts requires unlocking scripts to only push data, active after the UAHF fork
+	PushOnlyUnlockingScripts bool
+
+	// Genesis enables the script rules of the Genesis upgrade, active from the Genesis activation height
+	Genesis bool
+
+	// GenesisOutputs enables the output rules of the Genesis upgrade, no P2SH outputs and the dust limit. These are
+	// active after the Genesis activation height, the transactions of the activation block itself were created
+	// before the Genesis rules existed.
+	GenesisOutputs bool
+
+	// MaxTxSize is the maximum size of a transaction in bytes
+	MaxTxSize int
+
+	// MaxScriptSize is the maximum size of a script in bytes, 0 when only limited by the transaction size
+	MaxScriptSize int
+}
+
+// ConsensusRulesResolver resolves the consensus rules that are active at a block height from the activation
+// heights of the network.
+type ConsensusRulesResolver struct {
+	params *chaincfg.Params
+}
+
+// NewConsensusRulesResolver creates a resolver for the activation heights of the given network.
+//
+// Parameters:
+//   - params: Network parameters with the activation heights
+//
+// Returns:
+//   - *ConsensusRulesResolver: The created resolver
+func NewConsensusRulesResolver(params *chaincfg.Params) *ConsensusRulesResolver {
+	return &ConsensusRulesResolver{
+		params: params,
+	}
+}
+
+// Rules returns the consensus rules that are active at the given block height.
+//
+// Parameters:
+//   - blockHeight: The block height to resolve the rules for
+//
+// Returns:
+//   - ConsensusRules: The active rule set
+func (r *ConsensusRulesResolver) Rules(blockHeight uint32) ConsensusRules {
+	rules := ConsensusRules{
+		Height:                   blockHeight,
+		MedianTimePastFinality:   blockHeight > r.params.CSVHeight,
+		ForkID:                   blockHeight > r.params.UahfForkHeight,
+		PushOnlyUnlockingScripts: blockHeight > r.params.UahfForkHeight,
+		Genesis:                  blockHeight >= r.params.GenesisActivationHeight,
+		GenesisOutputs:           blockHeight > r.params.GenesisActivationHeight,
+	}
+
+	if rules.Genesis {
+		rules.MaxTxSize = MaxTxSizeConsensusAfterGenesis
+	} else {
+		rules.MaxTxSize = MaxTxSizeConsensusBeforeGenesis
+		rules.MaxScriptSize = MaxScriptSizeConsensusBeforeGenesis
+	}
+
+	return rules
+}
+
+// GenesisActivationHeight returns the first block height the Genesis script rules are active at. Script engines
+// applying the rules of a height themselves, like GoBDK, are configured with this height.
+func (r *ConsensusRulesResolver) GenesisActivationHeight() uint32 {
+	return r.params.GenesisActivationHeight
+}
+
+// ChronicleActivationHeight returns the first block height the rules of the Chronicle upgrade are active at.
+// Chronicle only changes the script rules, which are applied by the script engine configured with this height.
+func (r *ConsensusRulesResolver) ChronicleActivationHeight() uint32 {
+	return r.params.ChronicleActivationHeight
+}
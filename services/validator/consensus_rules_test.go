@@ -0,0 +1,97 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-chaincfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusRulesResolver(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	resolver := NewConsensusRulesResolver(params)
+
+	t.Run("CSV boundary", func(t *testing.T) {
+		assert.False(t, resolver.Rules(params.CSVHeight).MedianTimePastFinality)
+		assert.True(t, resolver.Rules(params.CSVHeight+1).MedianTimePastFinality)
+	})
+
+	t.Run("UAHF boundary", func(t *testing.T) {
+		before := resolver.Rules(params.UahfForkHeight)
+		after := resolver.Rules(params.UahfForkHeight + 1)
+
+		assert.False(t, before.ForkID)
+		assert.False(t, before.PushOnlyUnlockingScripts)
+
+		assert.True(t, after.ForkID)
+		assert.True(t, after.PushOnlyUnlockingScripts)
+	})
+
+	t.Run("Genesis boundary", func(t *testing.T) {
+		before := resolver.Rules(params.GenesisActivationHeight - 1)
+		activation := resolver.Rules(params.GenesisActivationHeight)
+		after := resolver.Rules(params.GenesisActivationHeight + 1)
+
+		assert.False(t, before.Genesis)
+		assert.False(t, before.GenesisOutputs)
+		assert.Equal(t, MaxTxSizeConsensusBeforeGenesis, before.MaxTxSize)
+		assert.Equal(t, MaxScriptSizeConsensusBeforeGenesis, before.MaxScriptSize)
+
+		// the script rules are active in the activation block, the output rules only after it
+		assert.True(t, activation.Genesis)
+		assert.False(t, activation.GenesisOutputs)
+		assert.Equal(t, MaxTxSizeConsensusAfterGenesis, activation.MaxTxSize)
+		assert.Equal(t, 0, activation.MaxScriptSize)
+
+		assert.True(t, after.Genesis)
+		assert.True(t, after.GenesisOutputs)
+
+		assert.Equal(t, params.GenesisActivationHeight, resolver.GenesisActivationHeight())
+	})
+
+	t.Run("Chronicle activation height", func(t *testing.T) {
+		assert.Equal(t, params.ChronicleActivationHeight, resolver.ChronicleActivationHeight())
+	})
+
+	t.Run("rules follow the network", func(t *testing.T) {
+		regtest := NewConsensusRulesResolver(&chaincfg.RegressionNetParams)
+
+		assert.True(t, regtest.Rules(1).ForkID)
+		assert.False(t, resolver.Rules(1).ForkID)
+	})
+}
+
+func TestCheckConsensusSizes(t *testing.T) {
+	resolver := NewConsensusRulesResolver(&chaincfg.MainNetParams)
+	genesisHeight := chaincfg.MainNetParams.GenesisActivationHeight
+
+	largeScript := bscript.Script(make([]byte, MaxScriptSizeConsensusBeforeGenesis+1))
+
+	tx := bt.NewTx()
+	tx.Inputs = append(tx.Inputs, &bt.Input{
+		UnlockingScript:  &largeScript,
+		PreviousTxScript: bscript.NewFromBytes([]byte{bscript.OpTRUE}),
+	})
+
+	t.Run("script size", func(t *testing.T) {
+		err := checkConsensusSizes(tx, tx.Size(), resolver.Rules(genesisHeight-1))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max script size consensus")
+
+		require.NoError(t, checkConsensusSizes(tx, tx.Size(), resolver.Rules(genesisHeight)))
+	})
+
+	t.Run("transaction size", func(t *testing.T) {
+		err := checkConsensusSizes(bt.NewTx(), MaxTxSizeConsensusBeforeGenesis+1, resolver.Rules(genesisHeight-1))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max tx size consensus")
+
+		require.NoError(t, checkConsensusSizes(bt.NewTx(), MaxTxSizeConsensusBeforeGenesis+1, resolver.Rules(genesisHeight)))
+
+		err = checkConsensusSizes(bt.NewTx(), MaxTxSizeConsensusAfterGenesis+1, resolver.Rules(genesisHeight))
+		require.Error(t, err)
+	})
+}
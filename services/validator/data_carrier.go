@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+var (
+	// ErrDataCarrierTooLarge is wrapped by the error of a data output exceeding the data carrier size
+	ErrDataCarrierTooLarge = errors.NewTxPolicyError("data carrier too large")
+)
+
+// maxDataCarrierSize returns the maximum size in bytes of the locking script of a data output, or 0 when it is
+// unlimited.
+//
+// The datacarriersize policy only applies when the policy checks apply, it defaults to maxscriptsizepolicy when it is
+// not configured. Subtree and block validation skip the policy checks, data outputs are then only limited by the
+// consensus rules, so a block is never rejected for the local policy of the node.
+func (tv *TxValidator) maxDataCarrierSize(validationOptions *Options) int64 {
+	if tv.settings.Policy == nil || validationOptions.SkipPolicyChecks {
+		return 0
+	}
+
+	if dataCarrierSize := tv.settings.Policy.GetDataCarrierSize(); dataCarrierSize > 0 {
+		return dataCarrierSize
+	}
+
+	return int64(tv.settings.Policy.GetMaxScriptSizePolicy())
+}
+
+// checkDataCarrierSize validates that the locking script of a data output does not exceed the maximum data carrier
+// size, other outputs are not checked.
+func checkDataCarrierSize(index int, script *bscript.Script, maxSize int64) error {
+	if maxSize <= 0 || script == nil || !script.IsData() {
+		return nil
+	}
+
+	if size := int64(len(*script)); size > maxSize {
+		return errors.NewTxPolicyError("transaction output %d data carrier size %d exceeds the maximum of %d bytes", index, size, maxSize, ErrDataCarrierTooLarge)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataCarrierSize(t *testing.T) {
+	newTx := func(script []byte) *bt.Tx {
+		tx := bt.NewTx()
+		tx.AddOutput(&bt.Output{Satoshis: 0, LockingScript: bscript.NewFromBytes(script)})
+
+		return tx
+	}
+
+	// dataTx returns a transaction with an OP_FALSE OP_RETURN data output of scriptSize bytes
+	dataTx := func(t *testing.T, scriptSize int) *bt.Tx {
+		script := []byte{bscript.OpFALSE, bscript.OpRETURN, bscript.OpPUSHDATA2, 0, 0}
+		binary.LittleEndian.PutUint16(script[3:], uint16(scriptSize-len(script))) //nolint:gosec // G115: small test sizes
+
+		tx := newTx(append(script, make([]byte, scriptSize-len(script))...))
+		require.Len(t, *tx.Outputs[0].LockingScript, scriptSize)
+
+		return tx
+	}
+
+	newValidator := func(t *testing.T, dataCarrierSize int64, maxScriptSizePolicy int) *TxValidator {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.Policy.SetDataCarrierSize(dataCarrierSize)
+		tSettings.Policy.SetMaxScriptSizePolicy(maxScriptSizePolicy)
+
+		return &TxValidator{settings: tSettings}
+	}
+
+	tests := []struct {
+		name                string
+		dataCarrierSize     int64
+		maxScriptSizePolicy int
+		skipPolicyChecks    bool
+		scriptSize          int
+		allowed             bool
+	}{
+		{
+			name:            "data output up to the data carrier size",
+			dataCarrierSize: 1000,
+			scriptSize:      1000,
+			allowed:         true,
+		},
+		{
+			name:            "data output over the data carrier size",
+			dataCarrierSize: 1000,
+			scriptSize:      1001,
+		},
+		{
+			name:             "data carrier size is not applied when the policy checks are skipped",
+			dataCarrierSize:  1000,
+			skipPolicyChecks: true,
+			scriptSize:       1001,
+			allowed:          true,
+		},
+		{
+			name:                "data carrier size defaults to the max script size policy",
+			maxScriptSizePolicy: 1000,
+			scriptSize:          1001,
+		},
+		{
+			name:                "data output up to the max script size policy",
+			maxScriptSizePolicy: 1000,
+			scriptSize:          1000,
+			allowed:             true,
+		},
+		{
+			name:                "default is not applied when the policy checks are skipped",
+			maxScriptSizePolicy: 1000,
+			skipPolicyChecks:    true,
+			scriptSize:          1001,
+			allowed:             true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tv := newValidator(t, tt.dataCarrierSize, tt.maxScriptSizePolicy)
+			height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+			err := tv.checkOutputs(dataTx(t, tt.scriptSize), height, &Options{SkipPolicyChecks: tt.skipPolicyChecks})
+			if tt.allowed {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errors.ErrTxPolicy)
+			assert.ErrorIs(t, err, ErrDataCarrierTooLarge)
+			assert.Contains(t, err.Error(), "transaction output 0 data carrier size")
+		})
+	}
+
+	t.Run("outputs other than data outputs are not checked", func(t *testing.T) {
+		tv := newValidator(t, 10, 0)
+		height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+		script, err := bscript.NewFromHexString("76a914000000000000000000000000000000000000000088ac")
+		require.NoError(t, err)
+
+		tx := newTx(*script)
+		tx.Outputs[0].Satoshis = 1000
+
+		require.NoError(t, tv.checkOutputs(tx, height, &Options{}))
+	})
+}
@@ -0,0 +1,269 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/ordishs/gocore"
+)
+
+// Stages of a transaction validation reported by DiagnoseTransaction, in the order they are run
+const (
+	// DiagnosticStageInputs checks that the outputs spent by the transaction exist and are unspent
+	DiagnosticStageInputs = "inputs"
+
+	// DiagnosticStageStructural checks the consensus rules of the transaction format, values and sizes
+	DiagnosticStageStructural = "structural"
+
+	// DiagnosticStagePolicy checks the policy rules of the transaction, like the size, dust and script limits
+	DiagnosticStagePolicy = "policy"
+
+	// DiagnosticStageFee checks that the transaction pays the minimum fee and not more than the maximum fee
+	DiagnosticStageFee = "fee"
+
+	// DiagnosticStageScript verifies the scripts and signatures of the inputs
+	DiagnosticStageScript = "script"
+)
+
+// DiagnosticStage is the result of a single stage of a transaction validation
+type DiagnosticStage struct {
+	// Stage is the name of the stage
+	Stage string
+
+	// Passed is true when the transaction passed all checks of the stage
+	Passed bool
+
+	// Skipped is true when the stage was not run, because it needs the outputs spent by the transaction
+	// and these could not be found
+	Skipped bool
+
+	// Error is the reason the stage was skipped, or the errors of all the failing checks of the stage, separated by
+	// semicolons
+	Error string
+}
+
+// TxDiagnostics is the full validation breakdown of a transaction
+type TxDiagnostics struct {
+	// BlockHeight is the block height the transaction was validated at
+	BlockHeight uint32
+
+	// Accepted is true when the transaction passed all stages
+	Accepted bool
+
+	// Stages holds the result of every stage, in the order the stages were run
+	Stages []DiagnosticStage
+}
+
+// diagnosticStages are the stages of a diagnosis, in the order they are reported
+var diagnosticStages = []string{
+	DiagnosticStageInputs,
+	DiagnosticStageStructural,
+	DiagnosticStagePolicy,
+	DiagnosticStageFee,
+	DiagnosticStageScript,
+}
+
+// newTxDiagnostics creates the diagnostics of a transaction validated at the given block height, with every stage
+// passed until a check of the stage fails
+func newTxDiagnostics(blockHeight uint32) *TxDiagnostics {
+	d := &TxDiagnostics{
+		BlockHeight: blockHeight,
+		Stages:      make([]DiagnosticStage, 0, len(diagnosticStages)),
+	}
+
+	for _, stage := range diagnosticStages {
+		d.Stages = append(d.Stages, DiagnosticStage{Stage: stage, Passed: true})
+	}
+
+	return d
+}
+
+// Stage returns the result of the stage with the given name, nil when the stage is unknown.
+func (d *TxDiagnostics) Stage(name string) *DiagnosticStage {
+	for i := range d.Stages {
+		if d.Stages[i].Stage == name {
+			return &d.Stages[i]
+		}
+	}
+
+	return nil
+}
+
+// fail records the error of a failing check of the stage, the errors of all failing checks of a stage are kept
+func (d *TxDiagnostics) fail(stage string, err error) {
+	result := d.Stage(stage)
+	if result == nil {
+		return
+	}
+
+	result.Passed = false
+
+	if result.Error != "" {
+		result.Error += "; "
+	}
+
+	result.Error += err.Error()
+}
+
+// failInputs records the error of the inputs stage when the outputs spent by the transaction cannot be resolved,
+// the stages that have not failed yet are skipped since they need these outputs
+func (d *TxDiagnostics) failInputs(err error) {
+	d.fail(DiagnosticStageInputs, err)
+	d.skip("the outputs spent by the transaction are not available")
+}
+
+// skip marks the stages that have not failed as skipped for the given reason
+func (d *TxDiagnostics) skip(reason string) {
+	for i := range d.Stages {
+		if d.Stages[i].Passed {
+			d.Stages[i] = DiagnosticStage{
+				Stage:   d.Stages[i].Stage,
+				Skipped: true,
+				Error:   reason,
+			}
+		}
+	}
+}
+
+// DiagnoseTransaction validates the transaction in diagnostic mode, with the validation pipeline of the validator.
+// Instead of stopping at the first failing check, every stage of the validation is run and the errors of all the
+// failing checks of each stage are returned. Nothing is spent, stored or sent to block assembly, the transaction is
+// only validated against the current state of the UTXO store.
+//
+// The transaction is extended with the outputs it spends when it is not extended yet. When these outputs cannot be
+// found, the stages that need them are skipped.
+//
+// Parameters:
+//   - ctx: Context for the UTXO store lookups
+//   - tx: The transaction to diagnose
+//
+// Returns:
+//   - *TxDiagnostics: The result of every validation stage
+func (v *Validator) DiagnoseTransaction(ctx context.Context, tx *bt.Tx) *TxDiagnostics {
+	diagnostics := newTxDiagnostics(v.GetBlockState().Height + 1)
+
+	if _, err := v.validateInternal(ctx, tx, diagnostics.BlockHeight, &Options{Diagnostics: diagnostics}); err != nil {
+		// the validation could not be completed, the stages that have not failed could not be checked
+		diagnostics.skip(err.Error())
+	}
+
+	diagnostics.Accepted = true
+
+	for _, stage := range diagnostics.Stages {
+		if !stage.Passed {
+			diagnostics.Accepted = false
+		}
+	}
+
+	return diagnostics
+}
+
+// NewDiagnosticValidator creates a validator used to diagnose transactions with DiagnoseTransaction, validating
+// with the policy and consensus settings of the node and the outputs in the UTXO store. It has no block assembly,
+// Kafka producers or caches, since diagnosed transactions are never spent, stored or forwarded.
+func NewDiagnosticValidator(logger ulogger.Logger, tSettings *settings.Settings, store utxo.Store) *Validator {
+	initPrometheusMetrics()
+
+	return &Validator{
+		logger:      logger,
+		settings:    tSettings,
+		txValidator: NewTxValidator(logger, tSettings),
+		utxoStore:   store,
+		stats:       gocore.NewStat("validator"),
+	}
+}
+
+// checkOutputsUnspent checks that the outputs spent by the extended transaction are unspent, without spending them.
+func (v *Validator) checkOutputsUnspent(ctx context.Context, tx *bt.Tx) error {
+	for idx, input := range tx.Inputs {
+		utxoHash, err := util.UTXOHashFromInput(input)
+		if err != nil {
+			return inputError(errors.NewProcessingError("input %d failed to calculate utxo hash", idx, err), idx)
+		}
+
+		spend, err := v.utxoStore.GetSpend(ctx, &utxo.Spend{
+			TxID:     input.PreviousTxIDChainHash(),
+			Vout:     input.PreviousTxOutIndex,
+			UTXOHash: utxoHash,
+		})
+		if err != nil {
+			return inputError(errors.NewProcessingError("input %d failed to get the state of the spent output", idx, err), idx)
+		}
+
+		if spend.Status != int(utxo.Status_OK) {
+			return inputError(errors.NewUtxoError("input %d spends an output with status %s", idx, utxo.Status(spend.Status).String()), idx)
+		}
+	}
+
+	return nil
+}
+
+// DiagnoseConsensus checks the extended transaction against the consensus rules only, the way the transactions of
+// a block are validated: first the structure and finality of the transaction, then the scripts. Nothing is read from
+// or written to any store, the outputs spent by the transaction have to be checked by the caller.
+//
+// Parameters:
+//   - tx: The extended transaction to check
+//   - blockHeight: Height of the block the transaction is validated for
+//   - medianTime: Median time past of the block the transaction is validated for
+//   - utxoHeights: Block heights of the outputs spent by the transaction
+//
+// Returns:
+//   - string: The failing stage, DiagnosticStageStructural or DiagnosticStageScript, empty when the transaction is valid
+//   - int: The index of the input failing script verification, -1 when the failure is not specific to one input
+//   - error: The reason the transaction is invalid
+func (tv *TxValidator) DiagnoseConsensus(tx *bt.Tx, blockHeight uint32, medianTime uint32, utxoHeights []uint32) (string, int, error) {
+	if err := tv.diagnoseStructure(tx, blockHeight, medianTime, utxoHeights); err != nil {
+		return DiagnosticStageStructural, -1, err
+	}
+
+	options := &Options{SkipPolicyChecks: true}
+
+	if err := tv.ValidateTransactionScripts(tx, blockHeight, utxoHeights, options); err != nil {
+		return DiagnosticStageScript, tv.failingScriptInput(tx, blockHeight, utxoHeights, options), err
+	}
+
+	return "", -1, nil
+}
+
+// failingScriptInput returns the index of the first input whose script fails verification on its own, -1 when no
+// single input fails. Each input is verified in a copy of the transaction in which all other inputs spend an
+// always true output, which does not change the signature hash of the verified input.
+func (tv *TxValidator) failingScriptInput(tx *bt.Tx, blockHeight uint32, utxoHeights []uint32, options *Options) int {
+	alwaysTrue := bscript.NewFromBytes([]byte{bscript.OpTRUE})
+
+	for idx := range tx.Inputs {
+		isolated := tx.Clone()
+
+		for otherIdx, input := range isolated.Inputs {
+			if otherIdx != idx {
+				input.PreviousTxScript = alwaysTrue
+				input.UnlockingScript = &bscript.Script{}
+			}
+		}
+
+		if err := tv.ValidateTransactionScripts(isolated, blockHeight, utxoHeights, options); err != nil {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// diagnoseStructure checks the consensus rules of the transaction, as checked by the validator before the scripts
+// are verified.
+func (tv *TxValidator) diagnoseStructure(tx *bt.Tx, blockHeight uint32, medianTime uint32, utxoHeights []uint32) error {
+	if tv.consensusRules(blockHeight).MedianTimePastFinality {
+		if err := util.IsTransactionFinal(tx, blockHeight, medianTime); err != nil {
+			return errors.NewUtxoNonFinalError("transaction is not final", err)
+		}
+	}
+
+	return tv.ValidateTransaction(tx, blockHeight, utxoHeights, &Options{SkipPolicyChecks: true})
+}
@@ -0,0 +1,183 @@
+package validator
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/stores/utxo/tests"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseTransaction(t *testing.T) {
+	setup := func(t *testing.T, createParent bool) (*settings.Settings, utxo.Store) {
+		tSettings := test.CreateBaseTestSettings(t)
+
+		utxoStoreURL, err := url.Parse("sqlitememory:///test")
+		require.NoError(t, err)
+
+		utxoStore, err := sql.New(t.Context(), ulogger.TestLogger{}, tSettings, utxoStoreURL)
+		require.NoError(t, err)
+
+		require.NoError(t, utxoStore.SetBlockHeight(122))
+
+		if createParent {
+			_, err = utxoStore.Create(t.Context(), tests.ParentTx, 122)
+			require.NoError(t, err)
+		}
+
+		return tSettings, utxoStore
+	}
+
+	// unextended returns a copy of the test transaction without the outputs it spends
+	unextended := func(t *testing.T) *bt.Tx {
+		tx, err := bt.NewTxFromBytes(tests.Tx.Bytes())
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	assertStages := func(t *testing.T, diagnostics *TxDiagnostics, failed ...string) {
+		require.Len(t, diagnostics.Stages, 5)
+
+		for i, name := range []string{DiagnosticStageInputs, DiagnosticStageStructural, DiagnosticStagePolicy, DiagnosticStageFee, DiagnosticStageScript} {
+			stage := diagnostics.Stages[i]
+			assert.Equal(t, name, stage.Stage)
+			assert.False(t, stage.Skipped, name)
+
+			if slices.Contains(failed, name) {
+				assert.False(t, stage.Passed, name)
+				assert.NotEmpty(t, stage.Error, name)
+			} else {
+				assert.True(t, stage.Passed, "%s: %s", name, stage.Error)
+				assert.Empty(t, stage.Error, name)
+			}
+		}
+	}
+
+	t.Run("all stages pass", func(t *testing.T) {
+		tSettings, utxoStore := setup(t, true)
+
+		diagnostics := NewDiagnosticValidator(ulogger.TestLogger{}, tSettings, utxoStore).DiagnoseTransaction(t.Context(), unextended(t))
+
+		assert.True(t, diagnostics.Accepted)
+		assert.Equal(t, uint32(123), diagnostics.BlockHeight)
+		assertStages(t, diagnostics)
+	})
+
+	t.Run("fee stage fails", func(t *testing.T) {
+		tSettings, utxoStore := setup(t, true)
+		tSettings.Policy.MinMiningTxFee = 1
+
+		diagnostics := NewDiagnosticValidator(ulogger.TestLogger{}, tSettings, utxoStore).DiagnoseTransaction(t.Context(), unextended(t))
+
+		assert.False(t, diagnostics.Accepted)
+		assertStages(t, diagnostics, DiagnosticStageFee)
+	})
+
+	t.Run("policy stage fails", func(t *testing.T) {
+		tSettings, utxoStore := setup(t, true)
+		tSettings.Policy.MaxTxSizePolicy = 10
+
+		diagnostics := NewDiagnosticValidator(ulogger.TestLogger{}, tSettings, utxoStore).DiagnoseTransaction(t.Context(), unextended(t))
+
+		assert.False(t, diagnostics.Accepted)
+		assertStages(t, diagnostics, DiagnosticStagePolicy)
+	})
+
+	t.Run("errors of all stages are collected", func(t *testing.T) {
+		tSettings, utxoStore := setup(t, true)
+		tSettings.Policy.MaxTxSizePolicy = 10
+		tSettings.Policy.MinMiningTxFee = 1
+
+		diagnostics := NewDiagnosticValidator(ulogger.TestLogger{}, tSettings, utxoStore).DiagnoseTransaction(t.Context(), unextended(t))
+
+		assert.False(t, diagnostics.Accepted)
+		assertStages(t, diagnostics, DiagnosticStagePolicy, DiagnosticStageFee)
+	})
+
+	t.Run("spent output fails the inputs stage only", func(t *testing.T) {
+		tSettings, utxoStore := setup(t, true)
+
+		_, err := utxoStore.Spend(t.Context(), tests.Tx, 123)
+		require.NoError(t, err)
+
+		diagnostics := NewDiagnosticValidator(ulogger.TestLogger{}, tSettings, utxoStore).DiagnoseTransaction(t.Context(), unextended(t))
+
+		assert.False(t, diagnostics.Accepted)
+		assertStages(t, diagnostics, DiagnosticStageInputs)
+	})
+
+	t.Run("missing parent skips the remaining stages", func(t *testing.T) {
+		tSettings, utxoStore := setup(t, false)
+
+		diagnostics := NewDiagnosticValidator(ulogger.TestLogger{}, tSettings, utxoStore).DiagnoseTransaction(t.Context(), unextended(t))
+
+		assert.False(t, diagnostics.Accepted)
+		require.Len(t, diagnostics.Stages, 5)
+
+		inputs := diagnostics.Stage(DiagnosticStageInputs)
+		require.NotNil(t, inputs)
+		assert.False(t, inputs.Passed)
+		assert.Contains(t, inputs.Error, "error getting parent transaction")
+
+		for _, stage := range diagnostics.Stages[1:] {
+			assert.True(t, stage.Skipped, stage.Stage)
+			assert.False(t, stage.Passed, stage.Stage)
+		}
+	})
+}
+
+func TestDiagnoseConsensus(t *testing.T) {
+	tSettings := test.CreateBaseTestSettings(t)
+	tv := NewTxValidator(ulogger.TestLogger{}, tSettings)
+
+	t.Run("valid transaction", func(t *testing.T) {
+		stage, inputIdx, err := tv.DiagnoseConsensus(tests.Tx.Clone(), 123, 0, []uint32{122})
+		require.NoError(t, err)
+		assert.Empty(t, stage)
+		assert.Equal(t, -1, inputIdx)
+	})
+
+	t.Run("script failure reports the failing input", func(t *testing.T) {
+		tx := tests.Tx.Clone()
+		tx.Inputs[0].PreviousTxScript = tests.Tx.Outputs[1].LockingScript
+
+		stage, inputIdx, err := tv.DiagnoseConsensus(tx, 123, 0, []uint32{122})
+		require.Error(t, err)
+		assert.Equal(t, DiagnosticStageScript, stage)
+		assert.Equal(t, 0, inputIdx)
+	})
+
+	t.Run("failing input among valid inputs", func(t *testing.T) {
+		tx := bt.NewTx()
+
+		for idx, lockingScript := range []byte{bscript.OpTRUE, bscript.OpTRUE, bscript.OpFALSE} {
+			input := &bt.Input{
+				PreviousTxSatoshis: 1000,
+				PreviousTxOutIndex: uint32(idx),
+				PreviousTxScript:   bscript.NewFromBytes([]byte{lockingScript}),
+				UnlockingScript:    &bscript.Script{},
+				SequenceNumber:     bt.DefaultSequenceNumber,
+			}
+			require.NoError(t, input.PreviousTxIDAdd(tests.ParentTx.TxIDChainHash()))
+
+			tx.Inputs = append(tx.Inputs, input)
+		}
+
+		tx.AddOutput(&bt.Output{Satoshis: 2000, LockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE})})
+
+		assert.Equal(t, 2, tv.failingScriptInput(tx, 123, []uint32{122, 122, 122}, &Options{SkipPolicyChecks: true}))
+
+		tx.Inputs[2].PreviousTxScript = bscript.NewFromBytes([]byte{bscript.OpTRUE})
+		assert.Equal(t, -1, tv.failingScriptInput(tx, 123, []uint32{122, 122, 122}, &Options{SkipPolicyChecks: true}))
+	})
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/util/failurestream"
+)
+
+// Stages of the transaction validation reported on the validation failure stream
+const (
+	failureStageInputs     = "inputs"
+	failureStageSpend      = "spend"
+	failureStagePolicy     = "policy"
+	failureStageConsensus  = "consensus"
+	failureStageProcessing = "processing"
+)
+
+// WithFailureEmitter sets the emitter the validation failures are emitted to, instead of the emitter of the
+// sink configured in the validation_failureSink setting.
+func WithFailureEmitter(emitter failurestream.Emitter) func(*Validator) {
+	return func(v *Validator) {
+		v.failureEmitter = emitter
+	}
+}
+
+// emitValidationFailure emits a failed transaction validation to the validation failure stream. Storage and
+// service errors are not emitted, they are not failures of the transaction itself.
+func (v *Validator) emitValidationFailure(tx *bt.Tx, validationErr error) {
+	if v.failureEmitter == nil || errors.Is(validationErr, errors.ErrStorageError) || errors.Is(validationErr, errors.ErrServiceError) {
+		return
+	}
+
+	v.failureEmitter.Emit(&failurestream.Failure{
+		TxID:   tx.TxIDChainHash().String(),
+		Stage:  validationFailureStage(validationErr),
+		Reason: validationErr.Error(),
+	})
+}
+
+// validationFailureStage returns the stage of the transaction validation the error was returned from
+func validationFailureStage(err error) string {
+	switch {
+	case errors.Is(err, errors.ErrTxMissingParent), errors.Is(err, errors.ErrTxNotFound):
+		return failureStageInputs
+	case errors.Is(err, errors.ErrTxConflicting), errors.Is(err, errors.ErrTxInvalidDoubleSpend),
+		errors.Is(err, errors.ErrSpent), errors.Is(err, errors.ErrFrozen), errors.Is(err, errors.ErrTxLocked):
+		return failureStageSpend
+	case errors.Is(err, errors.ErrTxPolicy):
+		return failureStagePolicy
+	case errors.Is(err, errors.ErrTxInvalid), errors.Is(err, errors.ErrTxConsensus),
+		errors.Is(err, errors.ErrTxCoinbaseImmature), errors.Is(err, errors.ErrTxLockTime), errors.Is(err, errors.ErrNonFinal):
+		return failureStageConsensus
+	default:
+		return failureStageProcessing
+	}
+}
@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/stores/utxo/tests"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/failurestream"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturingEmitter captures the emitted failures
+type capturingEmitter struct {
+	mu       sync.Mutex
+	failures []*failurestream.Failure
+}
+
+func (e *capturingEmitter) Emit(failure *failurestream.Failure) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failures = append(e.failures, failure)
+}
+
+func TestValidationFailureStream(t *testing.T) {
+	tracing.SetupMockTracer()
+
+	newValidator := func(t *testing.T, withParent bool, opts ...func(*Validator)) Interface {
+		ctx := context.Background()
+		logger := ulogger.NewErrorTestLogger(t)
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.BlockAssembly.Disabled = true
+
+		utxoStoreURL, err := url.Parse("sqlitememory:///test")
+		require.NoError(t, err)
+
+		utxoStore, err := sql.New(ctx, logger, tSettings, utxoStoreURL)
+		require.NoError(t, err)
+
+		if withParent {
+			_, err = utxoStore.Create(ctx, tests.ParentTx, 122)
+			require.NoError(t, err)
+		}
+
+		v, err := New(ctx, logger, tSettings, utxoStore, nil, nil, nil, nil, opts...)
+		require.NoError(t, err)
+
+		return v
+	}
+
+	t.Run("policy failure", func(t *testing.T) {
+		emitter := &capturingEmitter{}
+		v := newValidator(t, true, WithFailureEmitter(emitter), WithAcceptancePolicy(AcceptancePolicyFunc(func(*bt.Tx) error {
+			return errors.NewError("blocked")
+		})))
+
+		_, err := v.Validate(t.Context(), tests.Tx.Clone(), 123)
+		require.Error(t, err)
+
+		require.Len(t, emitter.failures, 1)
+		assert.Equal(t, tests.Tx.TxIDChainHash().String(), emitter.failures[0].TxID)
+		assert.Equal(t, failureStagePolicy, emitter.failures[0].Stage)
+		assert.Equal(t, err.Error(), emitter.failures[0].Reason)
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		emitter := &capturingEmitter{}
+		v := newValidator(t, false, WithFailureEmitter(emitter))
+
+		tx, err := bt.NewTxFromBytes(tests.Tx.Bytes()) // not extended, the parent has to be looked up
+		require.NoError(t, err)
+
+		_, err = v.Validate(t.Context(), tx, 123)
+		require.Error(t, err)
+
+		require.Len(t, emitter.failures, 1)
+		assert.Equal(t, failureStageInputs, emitter.failures[0].Stage)
+	})
+
+	t.Run("valid transaction", func(t *testing.T) {
+		emitter := &capturingEmitter{}
+		v := newValidator(t, true, WithFailureEmitter(emitter))
+
+		_, err := v.Validate(t.Context(), tests.Tx.Clone(), 123)
+		require.NoError(t, err)
+
+		assert.Empty(t, emitter.failures)
+	})
+}
+
+func TestValidationFailureStage(t *testing.T) {
+	assert.Equal(t, failureStageInputs, validationFailureStage(errors.NewTxMissingParentError("missing")))
+	assert.Equal(t, failureStageSpend, validationFailureStage(errors.NewTxConflictingError("conflicting")))
+	assert.Equal(t, failureStagePolicy, validationFailureStage(errors.NewTxPolicyError("policy")))
+	assert.Equal(t, failureStageConsensus, validationFailureStage(errors.NewTxInvalidError("invalid")))
+	assert.Equal(t, failureStageProcessing, validationFailureStage(errors.NewProcessingError("processing")))
+}
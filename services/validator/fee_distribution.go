@@ -0,0 +1,116 @@
+package validator
+
+import (
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// feeDistributionQuantiles are the quantiles of the fees reported by the fee distribution
+var feeDistributionQuantiles = []float64{0.5, 0.9, 0.99}
+
+// feeDistribution keeps the fees paid by the last accepted transactions in a sliding window, and exposes their
+// distribution as the teranode_validator_fee_distribution Prometheus summary: the fee quantiles, and the count and
+// sum of the fees in the window, the mean fee being the sum divided by the count.
+type feeDistribution struct {
+	desc *prometheus.Desc
+
+	mu   sync.Mutex
+	fees []uint64 // ring buffer of the fees in the window, in satoshis
+	next int      // position of the next fee in the ring buffer
+	full bool     // whether the ring buffer wrapped around
+}
+
+// newFeeDistribution creates a fee distribution over the fees of the last window accepted transactions.
+func newFeeDistribution(window int) *feeDistribution {
+	return &feeDistribution{
+		desc: prometheus.NewDesc("teranode_validator_fee_distribution",
+			"Distribution of the fees in satoshis paid by the last accepted transactions, over a sliding window of validator_feeDistributionWindow transactions",
+			nil, nil,
+		),
+		fees: make([]uint64, window),
+	}
+}
+
+// registerFeeDistribution registers a fee distribution with the default Prometheus registry, returning the fee
+// distribution registered before by another validator of the process, if any, so all validators share one window.
+func registerFeeDistribution(window int) *feeDistribution {
+	d := newFeeDistribution(window)
+
+	if err := prometheus.Register(d); err != nil {
+		if registered, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			if existing, ok := registered.ExistingCollector.(*feeDistribution); ok {
+				return existing
+			}
+		}
+	}
+
+	return d
+}
+
+// observe adds the fee of an accepted transaction to the window, replacing the oldest fee once the window is
+// full. It is a no-op on a nil fee distribution.
+func (d *feeDistribution) observe(fee uint64) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fees[d.next] = fee
+	d.next++
+
+	if d.next == len(d.fees) {
+		d.next = 0
+		d.full = true
+	}
+}
+
+// snapshot returns the number of fees in the window, their sum and their quantiles, using the nearest rank
+// method, so every quantile is a fee actually paid.
+func (d *feeDistribution) snapshot() (count uint64, sum float64, quantiles map[float64]float64) {
+	d.mu.Lock()
+
+	n := d.next
+	if d.full {
+		n = len(d.fees)
+	}
+
+	fees := slices.Clone(d.fees[:n])
+
+	d.mu.Unlock()
+
+	quantiles = make(map[float64]float64, len(feeDistributionQuantiles))
+
+	if len(fees) == 0 {
+		return 0, 0, quantiles
+	}
+
+	slices.Sort(fees)
+
+	for _, fee := range fees {
+		sum += float64(fee)
+	}
+
+	for _, q := range feeDistributionQuantiles {
+		rank := int(math.Ceil(q * float64(len(fees))))
+		quantiles[q] = float64(fees[max(rank, 1)-1])
+	}
+
+	return uint64(len(fees)), sum, quantiles
+}
+
+// Describe implements prometheus.Collector.
+func (d *feeDistribution) Describe(ch chan<- *prometheus.Desc) {
+	ch <- d.desc
+}
+
+// Collect implements prometheus.Collector, computing the quantiles of the fees in the window when scraped.
+func (d *feeDistribution) Collect(ch chan<- prometheus.Metric) {
+	count, sum, quantiles := d.snapshot()
+
+	ch <- prometheus.MustNewConstSummary(d.desc, count, sum, quantiles)
+}
@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeDistribution(t *testing.T) {
+	t.Run("percentiles and mean of known fees", func(t *testing.T) {
+		d := newFeeDistribution(100)
+
+		// fees 1 to 100 in reverse order, so the quantiles do not depend on the order of the fees
+		for fee := uint64(100); fee >= 1; fee-- {
+			d.observe(fee)
+		}
+
+		count, sum, quantiles := d.snapshot()
+		assert.Equal(t, uint64(100), count)
+		assert.InDelta(t, 50.5, sum/float64(count), 0.0001)
+		assert.Equal(t, map[float64]float64{0.5: 50, 0.9: 90, 0.99: 99}, quantiles)
+	})
+
+	t.Run("only the last fees are in the window", func(t *testing.T) {
+		d := newFeeDistribution(10)
+
+		// the first 10 fees are pushed out of the window by the fees 11 to 20
+		for fee := uint64(1); fee <= 20; fee++ {
+			d.observe(fee)
+		}
+
+		count, sum, quantiles := d.snapshot()
+		assert.Equal(t, uint64(10), count)
+		assert.InDelta(t, 155, sum, 0.0001)
+		assert.Equal(t, map[float64]float64{0.5: 15, 0.9: 19, 0.99: 20}, quantiles)
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		count, sum, quantiles := newFeeDistribution(10).snapshot()
+		assert.Equal(t, uint64(0), count)
+		assert.Zero(t, sum)
+		assert.Empty(t, quantiles)
+	})
+
+	t.Run("nil fee distribution", func(t *testing.T) {
+		var d *feeDistribution
+
+		assert.NotPanics(t, func() { d.observe(1000) })
+	})
+
+	t.Run("exposed as a prometheus summary", func(t *testing.T) {
+		d := newFeeDistribution(100)
+
+		for fee := uint64(1); fee <= 100; fee++ {
+			d.observe(fee * 1000)
+		}
+
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registry.Register(d))
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		require.Len(t, families, 1)
+		assert.Equal(t, "teranode_validator_fee_distribution", families[0].GetName())
+
+		require.Len(t, families[0].GetMetric(), 1)
+		summary := families[0].GetMetric()[0].GetSummary()
+
+		assert.Equal(t, uint64(100), summary.GetSampleCount())
+		assert.InDelta(t, 5_050_000, summary.GetSampleSum(), 0.0001)
+
+		quantiles := make(map[float64]float64)
+		for _, q := range summary.GetQuantile() {
+			quantiles[q.GetQuantile()] = q.GetValue()
+		}
+
+		assert.Equal(t, map[float64]float64{0.5: 50_000, 0.9: 90_000, 0.99: 99_000}, quantiles)
+	})
+}
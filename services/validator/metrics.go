@@ -43,6 +43,18 @@ var (
 	// or fails structural validation checks. High values may indicate network attacks or client issues.
 	prometheusInvalidTransactions prometheus.Counter
 
+	// prometheusKnownTransactions counts the transactions that were not revalidated because they
+	// already exist in the UTXO store. Only incremented when validator_skipKnownTransactions is enabled.
+	prometheusKnownTransactions prometheus.Counter
+
+	// prometheusValidationCacheHits counts the validations answered from the validation cache, for the
+	// same state of the spent outputs. Only incremented when validator_validationCacheTTL is set.
+	prometheusValidationCacheHits prometheus.Counter
+
+	// prometheusValidatorSeenInvalidRejections counts the transactions rejected as seen invalid before, without
+	// validating them again. Only incremented when validator_seenInvalidCacheSize is set.
+	prometheusValidatorSeenInvalidRejections prometheus.Counter
+
 	// prometheusTransactionValidateTotal measures the complete end-to-end validation time for transactions.
 	// This histogram tracks the total time spent validating a transaction from initial receipt through
 	// final validation completion, including all validation steps and database operations. Units: seconds.
@@ -108,6 +120,20 @@ var (
 	// This histogram tracks database operations for storing and updating transaction metadata,
 	// including validation status, processing timestamps, and related transaction information. Units: seconds.
 	prometheusValidatorSetTxMeta prometheus.Histogram
+
+	// prometheusValidatorValidationResultsDropped counts the validation results that were not published to
+	// the validation results Kafka topic, because the publish buffer was full.
+	prometheusValidatorValidationResultsDropped prometheus.Counter
+
+	// prometheusValidatorUnconfirmedInputs tracks the number of inputs per transaction spending outputs of
+	// transactions that are not mined yet, for tuning the max unconfirmed inputs policy.
+	prometheusValidatorUnconfirmedInputs prometheus.Histogram
+
+	// prometheusValidatorBacklog tracks the number of transactions awaiting validation by the validator server
+	prometheusValidatorBacklog prometheus.Gauge
+
+	// prometheusValidatorBacklogRejected counts the transactions rejected because the validation backlog was full
+	prometheusValidatorBacklogRejected prometheus.Counter
 )
 
 // Synchronization primitives
@@ -145,6 +171,35 @@ func _initPrometheusMetrics() {
 		},
 	)
 
+	// Known transactions counter
+	prometheusKnownTransactions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "validator",
+			Name:      "known_transactions",
+			Help:      "Number of transactions skipped by the validator service because they were already known",
+		},
+	)
+
+	// Validation cache hits counter
+	prometheusValidationCacheHits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "validator",
+			Name:      "validation_cache_hits",
+			Help:      "Number of validations answered from the validation cache of the validator service",
+		},
+	)
+
+	prometheusValidatorSeenInvalidRejections = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "validator",
+			Name:      "seen_invalid_rejections",
+			Help:      "Number of transactions rejected as seen invalid before, without validating them again",
+		},
+	)
+
 	// Total validation time histogram
 	prometheusTransactionValidateTotal = promauto.NewHistogram(
 		prometheus.HistogramOpts{
@@ -287,6 +342,43 @@ func _initPrometheusMetrics() {
 		},
 	)
 
+	prometheusValidatorValidationResultsDropped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "validator",
+			Name:      "validation_results_dropped",
+			Help:      "Number of transaction validation results not published to kafka because the publish buffer was full",
+		},
+	)
+
+	prometheusValidatorUnconfirmedInputs = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "validator",
+			Name:      "unconfirmed_inputs",
+			Help:      "Number of inputs per transaction spending outputs of unconfirmed transactions",
+			Buckets:   []float64{0, 1, 2, 5, 10, 25, 50, 100, 250, 1000},
+		},
+	)
+
+	prometheusValidatorBacklog = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "teranode",
+			Subsystem: "validator",
+			Name:      "backlog",
+			Help:      "Number of transactions awaiting validation by the validator server",
+		},
+	)
+
+	prometheusValidatorBacklogRejected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "validator",
+			Name:      "backlog_rejected",
+			Help:      "Number of transactions rejected because the validation backlog was full",
+		},
+	)
+
 	// Transaction metadata operations histogram
 	prometheusValidatorSetTxMeta = promauto.NewHistogram(
 		prometheus.HistogramOpts{
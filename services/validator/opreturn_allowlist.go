@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// parseOpReturnPrefixAllowlist decodes the hex encoded OP_RETURN protocol prefixes configured in
+// policy_opreturn_prefixallowlist, returning a configuration error when a prefix is empty or not valid hex.
+func parseOpReturnPrefixAllowlist(allowlist []string) ([][]byte, error) {
+	prefixes := make([][]byte, 0, len(allowlist))
+
+	for _, prefixHex := range allowlist {
+		prefix, err := hex.DecodeString(prefixHex)
+		if err != nil {
+			return nil, errors.NewConfigurationError("invalid OP_RETURN protocol prefix %q in policy_opreturn_prefixallowlist, expected a hex string", prefixHex, err)
+		}
+
+		if len(prefix) == 0 {
+			return nil, errors.NewConfigurationError("empty OP_RETURN protocol prefix in policy_opreturn_prefixallowlist")
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+// opReturnProtocolPrefix returns the first data push following the OP_RETURN of a data output, which holds the
+// protocol prefix by convention, or nil when the OP_RETURN is not followed by a data push.
+func opReturnProtocolPrefix(script *bscript.Script) []byte {
+	b := []byte(*script)
+
+	// skip OP_RETURN, or OP_FALSE OP_RETURN
+	if b[0] == bscript.OpFALSE {
+		b = b[1:]
+	}
+
+	b = b[1:]
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	var (
+		length int
+		header int
+	)
+
+	switch op := b[0]; {
+	case op >= bscript.OpDATA1 && op <= bscript.OpDATA75:
+		length, header = int(op), 1
+	case op == bscript.OpPUSHDATA1 && len(b) >= 2:
+		length, header = int(b[1]), 2
+	case op == bscript.OpPUSHDATA2 && len(b) >= 3:
+		length, header = int(binary.LittleEndian.Uint16(b[1:])), 3
+	case op == bscript.OpPUSHDATA4 && len(b) >= 5:
+		length, header = int(binary.LittleEndian.Uint32(b[1:])), 5
+	default:
+		return nil
+	}
+
+	if length > len(b)-header {
+		return nil
+	}
+
+	return b[header : header+length]
+}
+
+// checkOpReturnPrefix validates that a data output carries one of the protocol prefixes configured in
+// policy_opreturn_prefixallowlist. Any data output is accepted when no prefixes are configured.
+func (tv *TxValidator) checkOpReturnPrefix(index int, script *bscript.Script) error {
+	if tv.settings.Policy == nil || len(tv.settings.Policy.OpReturnPrefixAllowlist) == 0 || !script.IsData() {
+		return nil
+	}
+
+	allowed, err := parseOpReturnPrefixAllowlist(tv.settings.Policy.OpReturnPrefixAllowlist)
+	if err != nil {
+		return err
+	}
+
+	protocolPrefix := opReturnProtocolPrefix(script)
+
+	for _, prefix := range allowed {
+		if bytes.HasPrefix(protocolPrefix, prefix) {
+			return nil
+		}
+	}
+
+	return errors.NewTxPolicyError("transaction output %d OP_RETURN protocol prefix is not allowed", index)
+}
@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpReturnPrefixAllowlist(t *testing.T) {
+	// the first bytes of the B:// and MAP protocol prefixes, "19Hxi" and "1PuQa"
+	const (
+		bProtocol   = "3139487869"
+		mapProtocol = "3150755161"
+	)
+
+	newTx := func(t *testing.T, satoshis uint64, scriptHex string) *bt.Tx {
+		script, err := bscript.NewFromHexString(scriptHex)
+		require.NoError(t, err)
+
+		tx := bt.NewTx()
+		tx.AddOutput(&bt.Output{Satoshis: satoshis, LockingScript: script})
+
+		return tx
+	}
+
+	newValidator := func(t *testing.T, allowlist ...string) *TxValidator {
+		tSettings := test.CreateBaseTestSettings(t)
+		tSettings.ChainCfgParams.RequireStandard = true
+		tSettings.Policy.SetOpReturnPrefixAllowlist(allowlist)
+
+		return &TxValidator{settings: tSettings}
+	}
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		scriptHex string
+		allowed   bool
+	}{
+		{
+			name:      "allowed prefix after OP_FALSE OP_RETURN",
+			allowlist: []string{bProtocol, mapProtocol},
+			scriptHex: "006a05" + bProtocol + "0401020304",
+			allowed:   true,
+		},
+		{
+			name:      "allowed prefix after OP_RETURN",
+			allowlist: []string{bProtocol},
+			scriptHex: "6a05" + bProtocol,
+			allowed:   true,
+		},
+		{
+			name:      "allowed prefix of a longer push",
+			allowlist: []string{"3139"},
+			scriptHex: "006a05" + bProtocol,
+			allowed:   true,
+		},
+		{
+			name:      "allowed prefix in a OP_PUSHDATA1 push",
+			allowlist: []string{bProtocol},
+			scriptHex: "006a4c05" + bProtocol,
+			allowed:   true,
+		},
+		{
+			name:      "disallowed prefix",
+			allowlist: []string{bProtocol},
+			scriptHex: "006a05" + mapProtocol,
+		},
+		{
+			name:      "allowed prefix not in the first push",
+			allowlist: []string{bProtocol},
+			scriptHex: "006a05" + mapProtocol + "05" + bProtocol,
+		},
+		{
+			name:      "no data push",
+			allowlist: []string{bProtocol},
+			scriptHex: "006a",
+		},
+		{
+			name:      "truncated data push",
+			allowlist: []string{bProtocol},
+			scriptHex: "006a08" + bProtocol,
+		},
+		{
+			name:      "allowlist disabled",
+			scriptHex: "006a05" + mapProtocol,
+			allowed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tv := newValidator(t, tt.allowlist...)
+			height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+			err := tv.checkOutputs(newTx(t, 0, tt.scriptHex), height, &Options{})
+			if tt.allowed {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errors.ErrTxPolicy)
+			assert.Contains(t, err.Error(), "transaction output 0 OP_RETURN protocol prefix is not allowed")
+		})
+	}
+
+	t.Run("outputs other than data outputs are not checked", func(t *testing.T) {
+		tv := newValidator(t, bProtocol)
+		height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+		err := tv.checkOutputs(newTx(t, 1000, "76a914000000000000000000000000000000000000000088ac"), height, &Options{})
+		require.NoError(t, err)
+	})
+
+	t.Run("allowlist is not applied in block validation", func(t *testing.T) {
+		tv := newValidator(t, bProtocol)
+		height := tv.settings.ChainCfgParams.GenesisActivationHeight + 1
+
+		err := tv.checkOutputs(newTx(t, 0, "006a05"+mapProtocol), height, &Options{SkipPolicyChecks: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid prefixes are rejected", func(t *testing.T) {
+		for _, allowlist := range [][]string{{"zz"}, {bProtocol, ""}, {"313"}} {
+			_, err := parseOpReturnPrefixAllowlist(allowlist)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errors.ErrConfiguration)
+		}
+
+		prefixes, err := parseOpReturnPrefixAllowlist([]string{bProtocol, mapProtocol})
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("19Hxi"), []byte("1PuQa")}, prefixes)
+	})
+}
@@ -30,6 +30,32 @@ type Options struct {
 
 	// IgnoreLocked determines whether to ignore transactions marked as locked when spending
 	IgnoreLocked bool
+
+	// SkipScriptVerification determines whether the verification of the scripts and signatures should be skipped
+	// this is done for transactions that were validated before, the outputs they spend are still checked
+	SkipScriptVerification bool
+
+	// BacklogExempt determines whether the transaction is admitted to the validation backlog by the validator
+	// this is done for transactions already added to the backlog by the validator server, and for the
+	// transactions of subtrees and blocks, which must never be rejected as busy
+	BacklogExempt bool
+
+	// Diagnostics runs the validation in diagnostic mode when set, see DiagnoseTransaction
+	// Every stage is run and the errors are collected in Diagnostics instead of ending the validation,
+	// and the validation stops before any output is spent or the transaction is stored
+	Diagnostics *TxDiagnostics
+}
+
+// fail returns the error of a check of the given stage, ending the validation. In diagnostic mode the error is
+// collected in the diagnostics instead and nil is returned, so the validation continues with the next check.
+func (o *Options) fail(stage string, err error) error {
+	if err == nil || o == nil || o.Diagnostics == nil {
+		return err
+	}
+
+	o.Diagnostics.fail(stage, err)
+
+	return nil
 }
 
 // Option defines a function type for setting options
@@ -139,6 +165,30 @@ func WithIgnoreLocked(ignoreLocked bool) Option {
 	}
 }
 
+// WithSkipScriptVerification creates an option to control the verification of the scripts and signatures
+// Parameters:
+//   - skip: When true, the scripts and signatures will not be verified
+//
+// Returns:
+//   - Option: Function that sets the skipScriptVerification option
+func WithSkipScriptVerification(skip bool) Option {
+	return func(o *Options) {
+		o.SkipScriptVerification = skip
+	}
+}
+
+// WithBacklogExempt creates an option to exempt the transaction from the validation backlog
+// Parameters:
+//   - exempt: When true, the transaction is neither counted in the backlog nor rejected when it is full
+//
+// Returns:
+//   - Option: Function that sets the backlogExempt option
+func WithBacklogExempt(exempt bool) Option {
+	return func(o *Options) {
+		o.BacklogExempt = exempt
+	}
+}
+
 // TxValidatorOptions defines configuration options specific to transaction validation
 type TxValidatorOptions struct {
 	skipPolicyChecks bool
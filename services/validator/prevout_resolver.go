@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+)
+
+// PrevoutResolver resolves the parent transactions of the inputs of a transaction being validated.
+//
+// The validator resolves every parent transaction once, getting the block heights the parent was mined at
+// and, for transactions that are not extended, the parent transaction itself to extend the inputs with the
+// outputs they spend. A resolver returns an errors.ErrTxNotFound error for a parent it does not know.
+//
+// Resolved prevouts only feed validation, spending the inputs still requires the outpoints in the UTXO store,
+// so an external resolver is meant for validations that do not spend, like script checks and diagnostics.
+type PrevoutResolver interface {
+	// ResolveParent returns the requested fields of the parent transaction with the given hash.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - parentTxHash: Hash of the parent transaction
+	//   - fields: Fields of the parent transaction to return, fields.BlockHeights and optionally fields.Tx
+	//
+	// Returns:
+	//   - *meta.Data: The requested fields of the parent transaction
+	//   - error: errors.ErrTxNotFound when the parent transaction is not known to the resolver
+	ResolveParent(ctx context.Context, parentTxHash *chainhash.Hash, fields ...fields.FieldName) (*meta.Data, error)
+}
+
+// storePrevoutResolver resolves parent transactions from the local UTXO store, the default resolver.
+type storePrevoutResolver struct {
+	utxoStore utxo.Store
+}
+
+// NewStorePrevoutResolver creates a PrevoutResolver resolving parent transactions from the local UTXO store.
+func NewStorePrevoutResolver(utxoStore utxo.Store) PrevoutResolver {
+	return &storePrevoutResolver{utxoStore: utxoStore}
+}
+
+// ResolveParent gets the parent transaction from the UTXO store.
+func (r *storePrevoutResolver) ResolveParent(ctx context.Context, parentTxHash *chainhash.Hash, f ...fields.FieldName) (*meta.Data, error) {
+	return r.utxoStore.Get(ctx, parentTxHash, f...)
+}
+
+// chainedPrevoutResolver resolves parent transactions with the first resolver that knows them.
+type chainedPrevoutResolver struct {
+	resolvers []PrevoutResolver
+}
+
+// NewChainedPrevoutResolver creates a PrevoutResolver asking each resolver in turn, moving on to the next
+// resolver only when a resolver does not know the parent transaction. Any other error is returned as is.
+//
+// A typical chain puts the local store first and an external oracle second, so only outpoints not found
+// locally are resolved externally:
+//
+//	NewChainedPrevoutResolver(NewStorePrevoutResolver(utxoStore), oracleResolver)
+func NewChainedPrevoutResolver(resolvers ...PrevoutResolver) PrevoutResolver {
+	return &chainedPrevoutResolver{resolvers: resolvers}
+}
+
+// ResolveParent returns the parent transaction from the first resolver that knows it.
+func (r *chainedPrevoutResolver) ResolveParent(ctx context.Context, parentTxHash *chainhash.Hash, f ...fields.FieldName) (*meta.Data, error) {
+	var err error
+
+	for _, resolver := range r.resolvers {
+		var data *meta.Data
+
+		if data, err = resolver.ResolveParent(ctx, parentTxHash, f...); err == nil {
+			return data, nil
+		}
+
+		if !errors.Is(err, errors.ErrTxNotFound) {
+			return nil, err
+		}
+	}
+
+	if err == nil {
+		err = errors.NewTxNotFoundError("parent transaction %s not found, no prevout resolvers configured", parentTxHash)
+	}
+
+	return nil, err
+}
+
+// WithPrevoutResolver sets the resolver of the parent transactions of validated transactions, replacing
+// the default resolver reading from the local UTXO store.
+func WithPrevoutResolver(resolver PrevoutResolver) func(*Validator) {
+	return func(v *Validator) {
+		v.prevoutResolver = resolver
+	}
+}
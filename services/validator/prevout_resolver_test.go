@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/settings"
+	utxostore "github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPrevoutResolver is an external prevout resolver knowing a fixed set of parent transactions
+type stubPrevoutResolver struct {
+	parents map[chainhash.Hash]*meta.Data
+	calls   int
+}
+
+func (r *stubPrevoutResolver) ResolveParent(_ context.Context, parentTxHash *chainhash.Hash, _ ...fields.FieldName) (*meta.Data, error) {
+	r.calls++
+
+	data, ok := r.parents[*parentTxHash]
+	if !ok {
+		return nil, errors.NewTxNotFoundError("parent transaction %s not known to the oracle", parentTxHash)
+	}
+
+	return data, nil
+}
+
+func TestChainedPrevoutResolver(t *testing.T) {
+	ctx := context.Background()
+
+	localParent := chainhash.HashH([]byte("local parent"))
+	externalParent := chainhash.HashH([]byte("external parent"))
+	unknownParent := chainhash.HashH([]byte("unknown parent"))
+
+	localOutput := &bt.Output{Satoshis: 1000, LockingScript: bscript.NewFromBytes([]byte{0x51})}
+	externalOutputs := []*bt.Output{
+		{Satoshis: 1, LockingScript: bscript.NewFromBytes([]byte{0x52})},
+		{Satoshis: 2000, LockingScript: bscript.NewFromBytes([]byte{0x53})},
+	}
+
+	newTx := func() *bt.Tx {
+		tx := bt.NewTx()
+
+		for _, prevout := range []struct {
+			hash chainhash.Hash
+			vout uint32
+		}{{localParent, 0}, {externalParent, 1}} {
+			input := &bt.Input{PreviousTxOutIndex: prevout.vout, SequenceNumber: bt.DefaultSequenceNumber}
+			require.NoError(t, input.PreviousTxIDAdd(&prevout.hash))
+
+			tx.Inputs = append(tx.Inputs, input)
+		}
+
+		return tx
+	}
+
+	newValidator := func(external PrevoutResolver) (*Validator, *utxostore.MockUtxostore) {
+		mockUtxoStore := &utxostore.MockUtxostore{}
+		mockUtxoStore.On("GetBlockState").Return(utxostore.BlockState{Height: 1000, MedianTime: 1000000000})
+		mockUtxoStore.On("Get", mock.Anything, &localParent, mock.Anything).Return(&meta.Data{
+			BlockHeights: []uint32{500},
+			Tx:           &bt.Tx{Outputs: []*bt.Output{localOutput}},
+		}, nil)
+		mockUtxoStore.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.NewTxNotFoundError("not found"))
+
+		v := &Validator{
+			settings:  settings.NewSettings(),
+			utxoStore: mockUtxoStore,
+		}
+
+		if external != nil {
+			WithPrevoutResolver(NewChainedPrevoutResolver(NewStorePrevoutResolver(mockUtxoStore), external))(v)
+		}
+
+		return v, mockUtxoStore
+	}
+
+	t.Run("external resolver supplies a prevout missing locally", func(t *testing.T) {
+		external := &stubPrevoutResolver{parents: map[chainhash.Hash]*meta.Data{
+			externalParent: {BlockHeights: []uint32{700}, Tx: &bt.Tx{Outputs: externalOutputs}},
+		}}
+
+		v, _ := newValidator(external)
+		tx := newTx()
+
+		utxoHeights, unconfirmedInputs, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []uint32{500, 700}, utxoHeights)
+		assert.Equal(t, 0, unconfirmedInputs)
+
+		// the local parent is resolved from the store, only the missing parent from the external resolver
+		assert.Equal(t, 1, external.calls)
+
+		assert.Equal(t, localOutput.Satoshis, tx.Inputs[0].PreviousTxSatoshis)
+		assert.Equal(t, localOutput.LockingScript, tx.Inputs[0].PreviousTxScript)
+		assert.Equal(t, externalOutputs[1].Satoshis, tx.Inputs[1].PreviousTxSatoshis)
+		assert.Equal(t, externalOutputs[1].LockingScript, tx.Inputs[1].PreviousTxScript)
+		assert.True(t, tx.IsExtended())
+	})
+
+	t.Run("prevout unknown to all resolvers", func(t *testing.T) {
+		external := &stubPrevoutResolver{parents: map[chainhash.Hash]*meta.Data{
+			unknownParent: {BlockHeights: []uint32{700}, Tx: &bt.Tx{Outputs: externalOutputs}},
+		}}
+
+		v, _ := newValidator(external)
+		tx := newTx()
+
+		_, _, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxMissingParent))
+	})
+
+	t.Run("without an external resolver the store is used", func(t *testing.T) {
+		v, mockUtxoStore := newValidator(nil)
+		tx := newTx()
+
+		_, _, err := v.getUtxoBlockHeightsAndExtendTx(ctx, tx, tx.TxID(), &Options{})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrTxMissingParent))
+
+		mockUtxoStore.AssertNumberOfCalls(t, "Get", 2)
+	})
+
+	t.Run("errors other than not found are not passed to the next resolver", func(t *testing.T) {
+		failing := &stubPrevoutResolver{}
+		external := &stubPrevoutResolver{parents: map[chainhash.Hash]*meta.Data{
+			externalParent: {BlockHeights: []uint32{700}, Tx: &bt.Tx{Outputs: externalOutputs}},
+		}}
+
+		mockUtxoStore := &utxostore.MockUtxostore{}
+		mockUtxoStore.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.NewStorageUnavailableError("connection refused"))
+
+		resolver := NewChainedPrevoutResolver(NewStorePrevoutResolver(mockUtxoStore), failing, external)
+
+		_, err := resolver.ResolveParent(ctx, &externalParent, fields.Tx)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrStorageUnavailable))
+		assert.Equal(t, 0, failing.calls)
+		assert.Equal(t, 0, external.calls)
+	})
+}
@@ -0,0 +1,254 @@
+package validator
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+)
+
+// chainDependentValidationErrors are the validation errors caused by the outputs the transaction spends being
+// spent or conflicting. The transaction may become valid when the chain reorganises, so these errors are
+// removed from the seen-invalid cache on a reorg.
+var chainDependentValidationErrors = []error{
+	errors.ErrTxInvalidDoubleSpend,
+	errors.ErrSpent,
+	errors.ErrTxConflicting,
+}
+
+// retryableValidationErrors are the validation errors that may resolve without a reorg, e.g. a parent arriving,
+// the lock time passing, the coinbase maturing or the outputs being unfrozen, or that depend on the policy of the
+// node. They are never cached in the seen-invalid cache.
+var retryableValidationErrors = []error{
+	errors.ErrTxPolicy,
+	errors.ErrTxMissingParent,
+	errors.ErrTxLockTime,
+	errors.ErrTxCoinbaseImmature,
+	errors.ErrTxLocked,
+	errors.ErrFrozen,
+}
+
+// seenInvalidEntry is a transaction seen invalid before
+type seenInvalidEntry struct {
+	key            chainhash.Hash
+	err            error
+	chainDependent bool
+}
+
+// seenInvalidCache is a fixed size FIFO cache of the transactions recently seen invalid, so a transaction submitted
+// again is rejected without validating it again. When the cache is full, adding a transaction evicts the
+// transaction seen invalid first.
+//
+// A transaction is keyed by its txid, an extended transaction by the hash of its extended bytes, so an extended
+// transaction carrying bogus previous outputs does not get the honest transaction with the same txid rejected.
+type seenInvalidCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[chainhash.Hash]*list.Element
+	order   *list.List
+
+	// bestBlockHash is the best block the cache last saw, to detect a reorg
+	bestBlockHash *chainhash.Hash
+}
+
+// newSeenInvalidCache creates a seen-invalid cache holding at most size transactions
+func newSeenInvalidCache(size int) *seenInvalidCache {
+	return &seenInvalidCache{
+		size:    size,
+		entries: make(map[chainhash.Hash]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// seenInvalidKey returns the key of the transaction in the seen-invalid cache
+func seenInvalidKey(tx *bt.Tx) chainhash.Hash {
+	if tx.IsExtended() {
+		return chainhash.DoubleHashH(tx.ExtendedBytes())
+	}
+
+	return *tx.TxIDChainHash()
+}
+
+// get returns the entry of the transaction seen invalid before
+func (c *seenInvalidCache) get(key chainhash.Hash) (seenInvalidEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return seenInvalidEntry{}, false
+	}
+
+	return *element.Value.(*seenInvalidEntry), true
+}
+
+// add adds the transaction seen invalid, evicting the transaction seen invalid first when the cache is full
+func (c *seenInvalidCache) add(key chainhash.Hash, err error, chainDependent bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*seenInvalidEntry)
+		entry.err = err
+		entry.chainDependent = chainDependent
+
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		if oldest := c.order.Front(); oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*seenInvalidEntry).key)
+		}
+	}
+
+	c.entries[key] = c.order.PushBack(&seenInvalidEntry{key: key, err: err, chainDependent: chainDependent})
+}
+
+// clearChainDependent removes the transactions that may have become valid by a reorg
+func (c *seenInvalidCache) clearChainDependent() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+
+		if entry := element.Value.(*seenInvalidEntry); entry.chainDependent {
+			c.order.Remove(element)
+			delete(c.entries, entry.key)
+			removed++
+		}
+
+		element = next
+	}
+
+	return removed
+}
+
+// len returns the number of transactions in the cache
+func (c *seenInvalidCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// classifySeenInvalid returns whether the validation error is cached in the seen-invalid cache, and whether the
+// transaction may become valid on a reorg. Only errors of an invalid transaction are cached, infrastructure errors
+// and errors that may resolve by themselves are validated again.
+func classifySeenInvalid(err error) (cacheable bool, chainDependent bool) {
+	if err == nil || !errors.Is(err, errors.ErrTxInvalid) && !errors.Is(err, errors.ErrTxConflicting) && !errors.Is(err, errors.ErrSpent) {
+		return false, false
+	}
+
+	for _, retryableErr := range retryableValidationErrors {
+		if errors.Is(err, retryableErr) {
+			return false, false
+		}
+	}
+
+	for _, chainDependentErr := range chainDependentValidationErrors {
+		if errors.Is(err, chainDependentErr) {
+			return true, true
+		}
+	}
+
+	return true, false
+}
+
+// validate rejects the transaction when it was seen invalid before, or validates the transaction with the validate
+// function and adds it to the cache when it is invalid. Transactions seen invalid for spending spent or conflicting
+// outputs are validated again when the validation options allow creating conflicting transactions.
+//
+// The cache only applies to validations with policy checks. A transaction rejected by the policy of this node may be
+// mined by another miner, so subtree and block validation, which skip the policy checks, always validate the
+// transaction and never add it to the cache.
+func (c *seenInvalidCache) validate(ctx context.Context, tx *bt.Tx, blockHeight uint32, validationOptions *Options,
+	validate func(ctx context.Context, tx *bt.Tx, blockHeight uint32, validationOptions *Options) (*meta.Data, error)) (*meta.Data, error) {
+	if validationOptions != nil && validationOptions.SkipPolicyChecks {
+		return validate(ctx, tx, blockHeight, validationOptions)
+	}
+
+	key := seenInvalidKey(tx)
+
+	if entry, ok := c.get(key); ok && (!entry.chainDependent || validationOptions == nil || !validationOptions.CreateConflicting) {
+		prometheusValidatorSeenInvalidRejections.Inc()
+
+		return nil, errors.NewTxInvalidError("[Validate][%s] transaction seen invalid before", tx.TxIDChainHash().String(), entry.err)
+	}
+
+	txMeta, err := validate(ctx, tx, blockHeight, validationOptions)
+
+	if cacheable, chainDependent := classifySeenInvalid(err); cacheable {
+		c.add(key, err, chainDependent)
+	}
+
+	return txMeta, err
+}
+
+// setBestBlock records the new best block, removing the transactions that may have become valid when the best
+// chain reorganised, i.e. when the new best block neither is the last seen best block nor extends it.
+func (c *seenInvalidCache) setBestBlock(header *model.BlockHeader) (reorg bool) {
+	c.mu.Lock()
+	hash := header.Hash()
+	previous := c.bestBlockHash
+	c.bestBlockHash = hash
+	c.mu.Unlock()
+
+	if previous == nil || previous.IsEqual(hash) || previous.IsEqual(header.HashPrevBlock) {
+		return false
+	}
+
+	c.clearChainDependent()
+
+	return true
+}
+
+// listenForReorgs clears the transactions that may have become valid from the seen-invalid cache whenever the best
+// chain reorganises, until the context is done
+func (v *Validator) listenForReorgs(ctx context.Context) error {
+	notifications, err := v.blockchainClient.Subscribe(ctx, "validator-seen-invalid")
+	if err != nil {
+		return errors.NewServiceError("could not subscribe to blockchain notifications", err)
+	}
+
+	if header, _, err := v.blockchainClient.GetBestBlockHeader(ctx); err == nil {
+		v.seenInvalidCache.setBestBlock(header)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification, ok := <-notifications:
+				if !ok {
+					return
+				}
+
+				if notification == nil || notification.Type != model.NotificationType_Block {
+					continue
+				}
+
+				header, _, err := v.blockchainClient.GetBestBlockHeader(ctx)
+				if err != nil {
+					v.logger.Warnf("[Validator] failed to get best block header for the seen-invalid cache: %v", err)
+					continue
+				}
+
+				if v.seenInvalidCache.setBestBlock(header) {
+					v.logger.Infof("[Validator] best chain reorganised to %s, cleared transactions seen spending spent outputs", header.Hash())
+				}
+			}
+		}
+	}()
+
+	return nil
+}
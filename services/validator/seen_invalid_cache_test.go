@@ -0,0 +1,202 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/model"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeenInvalidCache(t *testing.T) {
+	initPrometheusMetrics()
+
+	ctx := context.Background()
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.From("0000000000000000000000000000000000000000000000000000000000000001", 0, "51", 1000))
+
+	countingValidate := func(validations *int, err error) func(context.Context, *bt.Tx, uint32, *Options) (*meta.Data, error) {
+		return func(context.Context, *bt.Tx, uint32, *Options) (*meta.Data, error) {
+			*validations++
+			return nil, err
+		}
+	}
+
+	t.Run("a known invalid transaction is fast-rejected", func(t *testing.T) {
+		cache := newSeenInvalidCache(10)
+
+		validations := 0
+		validate := countingValidate(&validations, errors.NewTxInvalidError("script verification failed"))
+
+		for i := 0; i < 3; i++ {
+			_, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, errors.ErrTxInvalid))
+		}
+
+		// only the first submission was validated
+		assert.Equal(t, 1, validations)
+	})
+
+	t.Run("retryable and infrastructure errors are validated again", func(t *testing.T) {
+		for _, err := range []error{
+			errors.NewTxPolicyError("fee too low"),
+			errors.NewTxMissingParentError("parent not found"),
+			errors.NewTxInvalidError("not final", errors.NewTxLockTimeError("lock time")),
+			errors.NewStorageError("connection refused"),
+			errors.NewContextCanceledError("cancelled"),
+		} {
+			cache := newSeenInvalidCache(10)
+
+			validations := 0
+			validate := countingValidate(&validations, err)
+
+			_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+			_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+
+			assert.Equal(t, 2, validations, err.Error())
+			assert.Equal(t, 0, cache.len())
+		}
+	})
+
+	t.Run("validations without policy checks bypass the cache", func(t *testing.T) {
+		cache := newSeenInvalidCache(10)
+
+		validations := 0
+
+		// invalid by the policy of this node, e.g. a fee too low, but valid when mined by another miner
+		validate := func(_ context.Context, _ *bt.Tx, _ uint32, validationOptions *Options) (*meta.Data, error) {
+			validations++
+
+			if !validationOptions.SkipPolicyChecks {
+				return nil, errors.NewTxInvalidError("transaction fee is too low")
+			}
+
+			return &meta.Data{}, nil
+		}
+
+		_, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		require.Error(t, err)
+		assert.Equal(t, 1, cache.len())
+
+		// subtree and block validation validate the transaction instead of rejecting it from the cache
+		txMeta, err := cache.validate(ctx, tx, 100, ProcessOptions(WithSkipPolicyChecks(true)), validate)
+		require.NoError(t, err)
+		require.NotNil(t, txMeta)
+		assert.Equal(t, 2, validations)
+
+		// failures without policy checks are not added to the cache
+		cache = newSeenInvalidCache(10)
+
+		_, err = cache.validate(ctx, tx, 100, ProcessOptions(WithSkipPolicyChecks(true)), countingValidate(&validations, errors.NewTxInvalidError("script verification failed")))
+		require.Error(t, err)
+		assert.Equal(t, 0, cache.len())
+	})
+
+	t.Run("valid transactions are not cached", func(t *testing.T) {
+		cache := newSeenInvalidCache(10)
+
+		validations := 0
+		validate := countingValidate(&validations, nil)
+
+		_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+
+		assert.Equal(t, 2, validations)
+	})
+
+	t.Run("the transaction seen invalid first is evicted", func(t *testing.T) {
+		cache := newSeenInvalidCache(2)
+
+		hash1 := chainhash.HashH([]byte("tx 1"))
+		hash2 := chainhash.HashH([]byte("tx 2"))
+		hash3 := chainhash.HashH([]byte("tx 3"))
+
+		cache.add(hash1, errors.NewTxInvalidError("invalid"), false)
+		cache.add(hash2, errors.NewTxInvalidError("invalid"), false)
+		cache.add(hash3, errors.NewTxInvalidError("invalid"), false)
+
+		assert.Equal(t, 2, cache.len())
+
+		_, ok := cache.get(hash1)
+		assert.False(t, ok)
+
+		_, ok = cache.get(hash3)
+		assert.True(t, ok)
+	})
+
+	t.Run("extended transactions are keyed by their extended bytes", func(t *testing.T) {
+		bogus := tx.Clone()
+		bogus.Inputs[0].PreviousTxSatoshis = 2000
+
+		assert.Equal(t, *tx.TxIDChainHash(), *bogus.TxIDChainHash())
+		assert.NotEqual(t, seenInvalidKey(tx), seenInvalidKey(bogus))
+
+		cache := newSeenInvalidCache(10)
+
+		validations := 0
+		_, _ = cache.validate(ctx, bogus, 100, NewDefaultOptions(), countingValidate(&validations, errors.NewTxInvalidError("fee mismatch")))
+		_, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), countingValidate(&validations, nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, validations)
+	})
+
+	t.Run("a reorg clears the double spends", func(t *testing.T) {
+		cache := newSeenInvalidCache(10)
+
+		doubleSpend := chainhash.HashH([]byte("double spend"))
+		badScript := chainhash.HashH([]byte("bad script"))
+
+		cacheable, chainDependent := classifySeenInvalid(errors.NewTxInvalidError("double spend", errors.NewUtxoSpentError(chainhash.Hash{}, 0, chainhash.Hash{}, nil)))
+		require.True(t, cacheable)
+		require.True(t, chainDependent)
+
+		cache.add(doubleSpend, errors.NewTxInvalidError("double spend"), true)
+		cache.add(badScript, errors.NewTxInvalidError("script verification failed"), false)
+
+		newHeader := func(prev *chainhash.Hash, nonce uint32) *model.BlockHeader {
+			return &model.BlockHeader{
+				Version:        1,
+				HashPrevBlock:  prev,
+				HashMerkleRoot: &chainhash.Hash{},
+				Bits:           model.NBit{},
+				Nonce:          nonce,
+			}
+		}
+
+		header1 := newHeader(&chainhash.Hash{}, 1)
+		header2 := newHeader(header1.Hash(), 2)
+		forkHeader := newHeader(header1.Hash(), 3)
+
+		assert.False(t, cache.setBestBlock(header1))
+		assert.False(t, cache.setBestBlock(header2))
+		assert.False(t, cache.setBestBlock(header2))
+		assert.Equal(t, 2, cache.len())
+
+		assert.True(t, cache.setBestBlock(forkHeader))
+		assert.Equal(t, 1, cache.len())
+
+		_, ok := cache.get(badScript)
+		assert.True(t, ok)
+	})
+
+	t.Run("double spends are validated again when conflicting transactions are created", func(t *testing.T) {
+		cache := newSeenInvalidCache(10)
+
+		validations := 0
+		validate := countingValidate(&validations, errors.NewTxConflictingError("conflicting"))
+
+		_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		_, _ = cache.validate(ctx, tx, 100, &Options{CreateConflicting: true}, validate)
+		_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+
+		assert.Equal(t, 2, validations)
+	})
+}
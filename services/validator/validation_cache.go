@@ -0,0 +1,218 @@
+package validator
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/ordishs/go-utils/expiringmap"
+)
+
+// spentOutputsStateFields are the fields of the parent transactions that the validation of a spend depends on
+var spentOutputsStateFields = []fields.FieldName{
+	fields.Utxos,
+	fields.BlockIDs,
+	fields.BlockHeights,
+	fields.Conflicting,
+	fields.Locked,
+}
+
+// validationCacheKey identifies a validation result. The result of a transaction only holds for the state of the
+// outputs it spends, identified by a hash of that state, and for the block state and options it was validated with.
+type validationCacheKey struct {
+	txHash      chainhash.Hash
+	stateHash   chainhash.Hash
+	blockHeight uint32
+	medianTime  uint32
+	flags       validationCacheFlags
+}
+
+// validationCacheFlags are the validation options that change the result of a validation, the other options,
+// like the backlog exemption, do not.
+type validationCacheFlags struct {
+	skipUtxoCreation       bool
+	addTXToBlockAssembly   bool
+	skipPolicyChecks       bool
+	createConflicting      bool
+	ignoreConflicting      bool
+	ignoreLocked           bool
+	skipScriptVerification bool
+}
+
+// newValidationCacheFlags returns the flags of the validation options that change the result of a validation
+func newValidationCacheFlags(validationOptions *Options) validationCacheFlags {
+	return validationCacheFlags{
+		skipUtxoCreation:       validationOptions.SkipUtxoCreation,
+		addTXToBlockAssembly:   validationOptions.AddTXToBlockAssembly,
+		skipPolicyChecks:       validationOptions.SkipPolicyChecks,
+		createConflicting:      validationOptions.CreateConflicting,
+		ignoreConflicting:      validationOptions.IgnoreConflicting,
+		ignoreLocked:           validationOptions.IgnoreLocked,
+		skipScriptVerification: validationOptions.SkipScriptVerification,
+	}
+}
+
+// validationCacheEntry is a cached validation result
+type validationCacheEntry struct {
+	txMeta *meta.Data
+	err    error
+}
+
+// validationCache caches the results of transaction validations by the state of the outputs they spend. The state
+// is read from the UTXO store, so a cached result is never returned once one of the spent outputs was spent, its
+// transaction was mined, reorged, frozen, locked or marked as conflicting, by any process sharing the store.
+//
+// A result is only cached when the state of the spent outputs did not change while the transaction was validated,
+// the result of a validation that spent the outputs itself is therefore never cached. Transactions with missing
+// parents are never cached, their parents can be created at any time.
+type validationCache struct {
+	utxoStore utxo.Store
+	results   *expiringmap.ExpiringMap[validationCacheKey, validationCacheEntry]
+}
+
+// newValidationCache creates a validation cache keeping the results for the given ttl, reading the state of the
+// spent outputs from the given UTXO store.
+func newValidationCache(utxoStore utxo.Store, ttl time.Duration) *validationCache {
+	return &validationCache{
+		utxoStore: utxoStore,
+		results:   expiringmap.New[validationCacheKey, validationCacheEntry](ttl),
+	}
+}
+
+// validate returns the cached result of the transaction for the current state of the outputs it spends, or
+// validates the transaction with the validate function and caches its result when that state did not change
+// meanwhile. Diagnostic validations are never cached.
+func (c *validationCache) validate(ctx context.Context, tx *bt.Tx, blockHeight uint32, validationOptions *Options,
+	validate func(ctx context.Context, tx *bt.Tx, blockHeight uint32, validationOptions *Options) (*meta.Data, error)) (*meta.Data, error) {
+	if validationOptions == nil || validationOptions.Diagnostics != nil {
+		return validate(ctx, tx, blockHeight, validationOptions)
+	}
+
+	stateHash, ok := c.spentOutputsState(ctx, tx)
+	if !ok {
+		return validate(ctx, tx, blockHeight, validationOptions)
+	}
+
+	key := validationCacheKey{
+		txHash:      *tx.TxIDChainHash(),
+		stateHash:   stateHash,
+		blockHeight: blockHeight,
+		medianTime:  c.utxoStore.GetBlockState().MedianTime,
+		flags:       newValidationCacheFlags(validationOptions),
+	}
+
+	if entry, ok := c.results.Get(key); ok {
+		prometheusValidationCacheHits.Inc()
+
+		return entry.txMeta, entry.err
+	}
+
+	txMeta, err := validate(ctx, tx, blockHeight, validationOptions)
+
+	if isCacheableValidationResult(err) {
+		if stateHashAfter, ok := c.spentOutputsState(ctx, tx); ok && stateHashAfter == key.stateHash {
+			c.results.Set(key, validationCacheEntry{txMeta: txMeta, err: err})
+		}
+	}
+
+	return txMeta, err
+}
+
+// spentOutputsState returns a hash of the state of the outputs spent by the transaction in the UTXO store, covering
+// the spend of every output and the blocks, conflicting, locked and frozen flags of its transaction. It returns
+// false when the state cannot be read, including when a parent transaction does not exist.
+func (c *validationCache) spentOutputsState(ctx context.Context, tx *bt.Tx) (chainhash.Hash, bool) {
+	parents := make(map[chainhash.Hash]*meta.Data)
+	state := make([]byte, 0, len(tx.Inputs)*(chainhash.HashSize+16))
+
+	for _, input := range tx.Inputs {
+		parentTxHash := input.PreviousTxIDChainHash()
+
+		parent, ok := parents[*parentTxHash]
+		if !ok {
+			var err error
+
+			if parent, err = c.utxoStore.Get(ctx, parentTxHash, spentOutputsStateFields...); err != nil || parent == nil {
+				return chainhash.Hash{}, false
+			}
+
+			parents[*parentTxHash] = parent
+		}
+
+		state = append(state, parentTxHash[:]...)
+		state = binary.LittleEndian.AppendUint32(state, input.PreviousTxOutIndex)
+
+		// the marker keeps spent, unspent and unknown outputs apart
+		switch vout := int(input.PreviousTxOutIndex); {
+		case vout >= len(parent.SpendingDatas):
+			state = append(state, 0)
+		case parent.SpendingDatas[vout] == nil:
+			state = append(state, 1)
+		default:
+			state = append(state, 2)
+			state = append(state, parent.SpendingDatas[vout].Bytes()...)
+		}
+
+		state = append(state, boolByte(parent.Conflicting), boolByte(parent.Locked), boolByte(parent.Frozen))
+		state = binary.LittleEndian.AppendUint32(state, uint32(len(parent.BlockIDs))) //nolint:gosec // G115: block counts are small
+
+		for _, blockID := range parent.BlockIDs {
+			state = binary.LittleEndian.AppendUint32(state, blockID)
+		}
+
+		for _, height := range parent.BlockHeights {
+			state = binary.LittleEndian.AppendUint32(state, height)
+		}
+	}
+
+	return chainhash.HashH(state), true
+}
+
+// boolByte returns 1 for true and 0 for false
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// cacheableValidationErrors are the validation errors caused by the transaction and the state of the outputs it
+// spends. Errors of the infrastructure, like storage errors or cancelled contexts, are not cached, and neither are
+// missing parents, which are not part of the state of the spent outputs.
+var cacheableValidationErrors = []error{
+	errors.ErrTxInvalid,
+	errors.ErrTxPolicy,
+	errors.ErrTxCoinbaseImmature,
+	errors.ErrTxLockTime,
+	errors.ErrTxConflicting,
+	errors.ErrTxLocked,
+	errors.ErrSpent,
+	errors.ErrFrozen,
+}
+
+// isCacheableValidationResult returns whether the validation result only depends on the transaction and the state
+// of the outputs it spends
+func isCacheableValidationResult(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, errors.ErrTxMissingParent) {
+		return false
+	}
+
+	for _, cacheableErr := range cacheableValidationErrors {
+		if errors.Is(err, cacheableErr) {
+			return true
+		}
+	}
+
+	return false
+}
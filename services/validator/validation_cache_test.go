@@ -0,0 +1,179 @@
+package validator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/stores/utxo/spend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationCache(t *testing.T) {
+	initPrometheusMetrics()
+
+	ctx := context.Background()
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.From("0000000000000000000000000000000000000000000000000000000000000001", 0, "51", 1000))
+
+	parentTxHash := tx.Inputs[0].PreviousTxIDChainHash()
+
+	// newCache returns a cache over a store holding the parent of tx with an unspent output
+	newCache := func(t *testing.T) (*validationCache, *meta.Data) {
+		parent := &meta.Data{SpendingDatas: []*spend.SpendingData{nil}}
+
+		mockStore := &utxo.MockUtxostore{}
+		mockStore.On("Get", mock.Anything, parentTxHash, mock.Anything).Return(parent, nil)
+		mockStore.On("GetBlockState").Return(utxo.BlockState{Height: 100, MedianTime: 1000000000})
+
+		return newValidationCache(mockStore, time.Minute), parent
+	}
+
+	rejectPolicy := func(validations *int) func(context.Context, *bt.Tx, uint32, *Options) (*meta.Data, error) {
+		return func(context.Context, *bt.Tx, uint32, *Options) (*meta.Data, error) {
+			*validations++
+			return nil, errors.NewTxPolicyError("fee too low")
+		}
+	}
+
+	t.Run("change of the spent outputs invalidates cached result", func(t *testing.T) {
+		cache, parent := newCache(t)
+
+		validations := 0
+		validate := rejectPolicy(&validations)
+
+		for i := 0; i < 3; i++ {
+			_, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, errors.ErrTxPolicy))
+		}
+
+		// the result is cached for the unchanged spent outputs
+		assert.Equal(t, 1, validations)
+
+		// the spent output is spent by another transaction, the cached result is stale
+		parent.SpendingDatas[0] = spend.NewSpendingData(&chainhash.Hash{1}, 0)
+
+		_, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		require.Error(t, err)
+		assert.Equal(t, 2, validations)
+
+		// the parent is mined, the cached result is stale
+		parent.BlockIDs = []uint32{7}
+		parent.BlockHeights = []uint32{99}
+
+		_, err = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		require.Error(t, err)
+		assert.Equal(t, 3, validations)
+
+		// the parent is frozen, the cached result is stale
+		parent.Frozen = true
+
+		_, err = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		require.Error(t, err)
+		assert.Equal(t, 4, validations)
+	})
+
+	t.Run("result keyed by relevant options and block height", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		validations := 0
+		validate := rejectPolicy(&validations)
+
+		_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		_, _ = cache.validate(ctx, tx, 100, &Options{SkipPolicyChecks: true}, validate)
+		_, _ = cache.validate(ctx, tx, 101, NewDefaultOptions(), validate)
+		_, _ = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+
+		assert.Equal(t, 3, validations)
+
+		// the backlog exemption does not change the result
+		_, _ = cache.validate(ctx, tx, 100, ProcessOptions(WithBacklogExempt(true)), validate)
+		assert.Equal(t, 3, validations)
+	})
+
+	t.Run("result of a validation spending the outputs is not cached", func(t *testing.T) {
+		cache, parent := newCache(t)
+
+		validations := 0
+		validate := func(context.Context, *bt.Tx, uint32, *Options) (*meta.Data, error) {
+			validations++
+
+			parent.SpendingDatas[0] = spend.NewSpendingData(tx.TxIDChainHash(), 0)
+
+			return &meta.Data{Fee: 1}, nil
+		}
+
+		txMeta, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), txMeta.Fee)
+
+		// the spend is reverted, e.g. by a reorg, the transaction must be validated and spent again
+		parent.SpendingDatas[0] = nil
+
+		_, err = cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, validations)
+	})
+
+	t.Run("missing parents are not cached", func(t *testing.T) {
+		mockStore := &utxo.MockUtxostore{}
+		mockStore.On("Get", mock.Anything, parentTxHash, mock.Anything).Return(nil, errors.NewTxNotFoundError("not found"))
+
+		cache := newValidationCache(mockStore, time.Minute)
+
+		validations := 0
+		validate := func(context.Context, *bt.Tx, uint32, *Options) (*meta.Data, error) {
+			validations++
+			return nil, errors.NewTxMissingParentError("parent not found")
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, errors.ErrTxMissingParent))
+		}
+
+		assert.Equal(t, 2, validations)
+		assert.False(t, isCacheableValidationResult(errors.NewTxInvalidError("invalid", errors.NewTxMissingParentError("parent not found"))))
+	})
+
+	t.Run("diagnostic validations are not cached", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		validations := 0
+		validate := rejectPolicy(&validations)
+
+		for i := 0; i < 2; i++ {
+			_, _ = cache.validate(ctx, tx, 100, &Options{Diagnostics: &TxDiagnostics{}}, validate)
+		}
+
+		assert.Equal(t, 2, validations)
+	})
+
+	t.Run("infrastructure errors are not cached", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		validations := 0
+		validate := func(context.Context, *bt.Tx, uint32, *Options) (*meta.Data, error) {
+			validations++
+			return nil, errors.NewStorageError("connection refused")
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := cache.validate(ctx, tx, 100, NewDefaultOptions(), validate)
+			require.Error(t, err)
+		}
+
+		assert.Equal(t, 2, validations)
+	})
+}
@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/services/validator/validator_api"
+	"github.com/bsv-blockchain/teranode/stores/utxo/sql"
+	"github.com/bsv-blockchain/teranode/stores/utxo/tests"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/bsv-blockchain/teranode/util/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+// TestServer_ValidationLimiterNestedBatch tests that batches sent to the validator by concurrent subtree transactions
+// of the same process complete when there are fewer validation slots than subtree transactions
+func TestServer_ValidationLimiterNestedBatch(t *testing.T) {
+	tracing.SetupMockTracer()
+
+	const (
+		maxGoroutines = 2
+		subtreeTxs    = 8
+		batchSize     = 3
+	)
+
+	util.InitValidationLimiter(maxGoroutines)
+	defer util.InitValidationLimiter(0)
+
+	ctx := context.Background()
+	logger := ulogger.TestLogger{}
+	tSettings := test.CreateBaseTestSettings(t)
+	tSettings.BlockAssembly.Disabled = true
+
+	utxoStoreURL, err := url.Parse("sqlitememory:///test")
+	require.NoError(t, err)
+
+	utxoStore, err := sql.New(ctx, logger, tSettings, utxoStoreURL)
+	require.NoError(t, err)
+
+	_, err = utxoStore.Create(ctx, tests.ParentTx, 122)
+	require.NoError(t, err)
+
+	v, err := New(ctx, logger, tSettings, utxoStore, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	server := &Server{
+		logger:    logger,
+		settings:  tSettings,
+		validator: v,
+	}
+
+	skipUtxoCreation := true
+
+	req := &validator_api.ValidateTransactionBatchRequest{}
+	for i := 0; i < batchSize; i++ {
+		req.Transactions = append(req.Transactions, &validator_api.ValidateTransactionRequest{
+			TransactionData:  tests.Tx.ExtendedBytes(),
+			BlockHeight:      123,
+			SkipUtxoCreation: &skipUtxoCreation,
+		})
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// every subtree transaction sends a batch to the validator, like subtree validation does with a remote validator
+	// running in the same process
+	g, gCtx := errgroup.WithContext(timeoutCtx)
+
+	responses := make([]*validator_api.ValidateTransactionBatchResponse, subtreeTxs)
+
+	for i := 0; i < subtreeTxs; i++ {
+		g.Go(func() error {
+			response, err := server.ValidateTransactionBatch(gCtx, req)
+			responses[i] = response
+
+			return err
+		})
+	}
+
+	require.NoError(t, g.Wait())
+	require.NoError(t, timeoutCtx.Err(), "the batches did not complete")
+
+	for _, response := range responses {
+		require.NotNil(t, response)
+		require.Len(t, response.Errors, batchSize)
+
+		for _, reason := range response.Errors {
+			// no validation was stuck waiting for a validation slot
+			if reason != nil {
+				assert.False(t, errors.Is(reason, errors.ErrContextCanceled), reason.Error())
+			}
+		}
+	}
+}
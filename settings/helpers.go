@@ -118,3 +118,15 @@ func getIntSlice(key string, defaultValue []int, alternativeContext ...string) [
 
 	return result
 }
+
+// getScriptTypePolicy reads the policy limits for a single output script type,
+// using the keys policy_<scriptType>_standard, policy_<scriptType>_dustlimit and policy_<scriptType>_maxscriptsize.
+func getScriptTypePolicy(scriptType string, defaultDustLimit uint64, alternativeContext ...string) ScriptTypePolicy {
+	prefix := "policy_" + scriptType + "_"
+
+	return ScriptTypePolicy{
+		Standard:      getBool(prefix+"standard", true, alternativeContext...),
+		DustLimit:     getUint64(prefix+"dustlimit", defaultDustLimit, alternativeContext...),
+		MaxScriptSize: getInt(prefix+"maxscriptsize", 0, alternativeContext...),
+	}
+}
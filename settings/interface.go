@@ -13,6 +13,19 @@ const (
 	ListenModeListenOnly = "listen_only"
 )
 
+// transaction ordering constants
+const (
+	TxOrderingFIFO        = "fifo"
+	TxOrderingFeePriority = "fee_priority"
+)
+
+// transaction serialization format constants
+const (
+	TxFormatAuto     = "auto"
+	TxFormatStandard = "standard"
+	TxFormatExtended = "extended"
+)
+
 type Settings struct {
 	Commit                       string
 	Version                      string
@@ -22,6 +35,7 @@ type Settings struct {
 	TracingEnabled               bool
 	TracingSampleRate            float64
 	TracingCollectorURL          *url.URL
+	TracingCorrelationIDsEnabled bool // accept correlation ids with transactions and log them at every processing stage
 	ClientName                   string
 	DataFolder                   string
 	SecurityLevelHTTP            int
@@ -34,7 +48,14 @@ type Settings struct {
 	ProfilerAddr                 string
 	StatsPrefix                  string
 	PrometheusEndpoint           string
+	StatsDEndpoint               string        // host:port of the StatsD server to export metrics to, "" disables the export
+	StatsDPrefix                 string        // prefix added to the name of every metric exported to StatsD
+	StatsDMetrics                []string      // Prometheus metric name prefixes of the metrics exported to StatsD, empty exports all metrics
+	StatsDFlushInterval          time.Duration // time between exports of the metrics to StatsD
+	ValidationFailureSink        *url.URL      // file:// or Kafka URL validation failures are emitted to for offline analysis, nil disables the stream
 	HealthCheckHTTPListenAddress string
+	GracefulRestartEnabled       bool          // hand off the listening sockets to a replacement process on SIGUSR2
+	GracefulRestartReadyTimeout  time.Duration // how long to wait for the replacement process to start its services before giving up the restart
 	UseDatadogProfiler           bool
 	LocalTestStartFromState      string
 	PostgresCheckAddress         string
@@ -92,6 +113,23 @@ func (s *Settings) GetSubtreeValidationBlockHeightRetention() uint32 {
 	return uint32(result)
 }
 
+// ProofOfWorkCheckRequired returns whether block headers must meet their target difficulty. The check can
+// only be skipped with blockvalidation_skipProofOfWorkCheck on a network allowed by ProofOfWorkCheckSkipAllowed.
+func (s *Settings) ProofOfWorkCheckRequired() bool {
+	return !s.BlockValidation.SkipProofOfWorkCheck || !s.ProofOfWorkCheckSkipAllowed()
+}
+
+// ProofOfWorkCheckSkipAllowed returns whether the proof of work check of block headers may be skipped on the
+// network: on regtest, or on a network declared private with blockvalidation_privateNetwork, never on mainnet,
+// testnet or any other public network.
+func (s *Settings) ProofOfWorkCheckSkipAllowed() bool {
+	if s.ChainCfgParams == nil || s.ChainCfgParams.Net == chaincfg.MainNetParams.Net {
+		return false
+	}
+
+	return s.ChainCfgParams.Net == chaincfg.RegressionNetParams.Net || s.BlockValidation.PrivateNetwork
+}
+
 type DashboardSettings struct {
 	Enabled        bool
 	DevServerPorts []int  // Vite dev server ports (e.g., 517, 417)
@@ -123,6 +161,12 @@ type KafkaSettings struct {
 	InvalidSubtreesConfig *url.URL
 	SubtreesConfig        *url.URL
 	BlocksConfig          *url.URL
+	// ValidationResultsConfig is the optional topic the validator publishes the result of every transaction
+	// validation to, publishing is disabled when not set
+	ValidationResultsConfig *url.URL
+	// SubtreeValidationResultsConfig is the optional topic the subtree validation service publishes an audit
+	// record of every subtree validation to, publishing is disabled when not set
+	SubtreeValidationResultsConfig *url.URL
 	// TLS settings
 	EnableTLS     bool
 	TLSSkipVerify bool
@@ -158,15 +202,20 @@ type AlertSettings struct {
 }
 
 type AssetSettings struct {
-	APIPrefix               string
-	CentrifugeListenAddress string
-	CentrifugeDisable       bool
-	HTTPAddress             string
-	HTTPPublicAddress       string
-	HTTPListenAddress       string
-	HTTPPort                int
-	SignHTTPResponses       bool
-	EchoDebug               bool
+	APIPrefix                 string
+	CentrifugeListenAddress   string
+	CentrifugeDisable         bool
+	HTTPAddress               string
+	HTTPPublicAddress         string
+	HTTPListenAddress         string
+	HTTPPort                  int
+	SignHTTPResponses         bool
+	EchoDebug                 bool
+	ProofRateLimitPerClient   float64       // Max merkle proof requests per second served to a single client (default: 0 = unlimited)
+	ProofRateLimitBurst       int           // Max burst of merkle proof requests served to a single client (default: 0 = rate limit rounded up)
+	MaxConcurrentProofs       int           // Max merkle proofs constructed concurrently by the node (default: 0 = unlimited)
+	ProofQueueTimeout         time.Duration // Max wait for a free merkle proof construction slot (default: 5s)
+	MaxBlockHeadersPerRequest int           // Max block headers returned per HTTP block headers request, larger requests are truncated (default: 10000)
 }
 
 type BlockSettings struct {
@@ -193,10 +242,12 @@ type BlockSettings struct {
 	UTXOPersisterDirect                   bool
 	BlockPersisterPersistAge              uint32
 	BlockPersisterPersistSleep            time.Duration
+	BlockPersisterPruneDepth              uint32 // Depth below the persisted tip beyond which full block data is deleted, 0 disables pruning
 	UtxoStore                             *url.URL
 	FileStoreReadConcurrency              int
 	FileStoreWriteConcurrency             int
 	FileStoreUseSystemLimits              bool
+	BIP34ActivationHeight                 uint32 // Height from which the coinbase must encode the block height (BIP34), defaults to the BIP34 height of the network
 }
 
 type BlockChainSettings struct {
@@ -242,6 +293,11 @@ type BlockAssemblySettings struct {
 	BlockchainSubscriptionTimeout       time.Duration
 	ValidateParentChainOnRestart        bool
 	ParentValidationBatchSize           int
+	CoinbaseTag                         string        // Tag embedded in the coinbase scriptSig after the block height, max 32 bytes, default ""
+	MaxPriorityTxs                      int           // Maximum number of transactions marked as priority at the same time, 0 disables prioritisation, default 1000
+	MoveBackBlockPrefetch               int           // Number of blocks whose subtrees are loaded ahead when moving back blocks in a reorg, 0 disables prefetching, default 0
+	MiningCandidateMaxAge               time.Duration // Age after which a cached mining candidate is stale and rebuilt on the next request, whatever the cache timeout, 0 disables, default 0
+	TxSelectionStrategy                 string        // Order in which queued transactions are added to the subtrees, "fifo" or "confirmed-parents-first", default "fifo"
 }
 
 type BlockValidationSettings struct {
@@ -287,15 +343,22 @@ type BlockValidationSettings struct {
 	CatchupIterationTimeout      int // Timeout in seconds for each catchup iteration
 	CatchupOperationTimeout      int // Timeout in seconds for the entire catchup operation
 	CatchupMaxAccumulatedHeaders int // Maximum headers to accumulate during catchup (default: 100000)
+	CatchupMaxQueuedBlocks       int // Maximum blocks fetched ahead of validation during catchup, 0 is unbounded (default: 500)
 	// Circuit breaker configuration
 	CircuitBreakerFailureThreshold int // Number of consecutive failures before opening circuit
 	CircuitBreakerSuccessThreshold int // Number of consecutive successes before closing circuit
 	CircuitBreakerTimeoutSeconds   int // Timeout in seconds before transitioning from open to half-open
 	// Block fetching configuration
-	FetchLargeBatchSize     int // Large batches for maximum HTTP efficiency (default: 100, peer limit)
-	FetchNumWorkers         int // Number of worker goroutines for parallel processing (default: 16)
-	FetchBufferSize         int // Buffer size for channels (default: 50)
-	SubtreeFetchConcurrency int // Concurrent subtree fetches per block (default: 8)
+	FetchLargeBatchSize            int           // Large batches for maximum HTTP efficiency (default: 100, peer limit)
+	FetchNumWorkers                int           // Number of worker goroutines for parallel processing (default: 16)
+	FetchBufferSize                int           // Buffer size for channels (default: 50)
+	FetchBlockMaxRetries           int           // Retries of a block that could not be fetched from any peer before it is given up on, 0 is unlimited (default: 10)
+	FetchBlockRetryDelay           time.Duration // Delay before a block that could not be fetched from any peer is retried (default: 5s)
+	SubtreeFetchConcurrency        int           // Concurrent subtree fetches per block (default: 8)
+	SubtreeFetchConcurrencyPerPeer int           // Concurrent subtree requests per peer across all blocks, excess subtrees move to other peers, 0 is unlimited (default: 16)
+	// Per peer download budget
+	PeerDownloadBudgetBytes    uint64        // Bytes of blocks and subtrees downloaded per peer per interval, 0 is unlimited (default: 0)
+	PeerDownloadBudgetInterval time.Duration // Interval after which the download budget of a peer is reset (default: 1m)
 	// Transaction extension timeout
 	ExtendTransactionTimeout time.Duration // Timeout for extending transactions (default: 120s)
 	// Concurrency limits
@@ -304,6 +367,11 @@ type BlockValidationSettings struct {
 	NearForkThreshold int // Heights within this range are considered "near" forks (default: coinbase maturity / 2)
 	MaxParallelForks  int // Maximum number of forks to process in parallel (default: 4)
 	MaxTrackedForks   int // Maximum total number of forks to track (default: 1000)
+	// Validation result cache
+	ValidationResultCacheTTL time.Duration // How long the outcome of a block validation is cached for duplicate blocks, 0 disables the cache (default: 1m)
+	// Proof of work
+	SkipProofOfWorkCheck bool // Skip the proof of work check of block headers on networks without proof of work, only allowed on regtest or a private network (default: false)
+	PrivateNetwork       bool // Declares the network a private permissioned network, allowing SkipProofOfWorkCheck on it (default: false)
 }
 
 type ValidatorSettings struct {
@@ -322,6 +390,16 @@ type ValidatorSettings struct {
 	HTTPRateLimit             int
 	KafkaMaxMessageBytes      int // Maximum Kafka message size in bytes for transaction validation
 	UseLocalValidator         bool
+	SkipKnownTransactions     bool          // Skip revalidation of transactions that already exist in the utxo store, default false
+	MaxBacklog                int           // Max transactions awaiting validation before new submissions are rejected as busy, default 0 (unlimited)
+	MaxValidationGoroutines   int           // Max transactions of the node validated at the same time, shared by all validations, default 0 (unlimited)
+	CheckCoinbaseOnChain      bool          // Reject mempool spends of coinbase outputs whose block is not on the current chain, e.g. after a reorg, default true
+	RejectNonFinal            bool          // Reject transactions entering the mempool that are not final in the next block, default true
+	TxMetaDedupWindow         time.Duration // Window within which the txmeta notification of a transaction is sent at most once, default 0 (disabled)
+	CanonicalTxOrdering       string        // Canonical ordering of transaction inputs and outputs to enforce, "none" or "bip69", default "none"
+	ValidationCacheTTL        time.Duration // Time the validation results are cached for unchanged spent outputs, default 0 (disabled)
+	SeenInvalidCacheSize      int           // Number of transactions recently seen invalid that are rejected without validating them again, default 0 (disabled)
+	FeeDistributionWindow     int           // Number of last accepted transactions over which the fee distribution metrics are computed, default 0 (disabled)
 }
 
 type RegionSettings struct {
@@ -361,12 +439,15 @@ type UtxoStoreSettings struct {
 	ExternalizeAllTransactions        bool
 	PostgresMaxIdleConns              int
 	PostgresMaxOpenConns              int
+	PostgresPoolFailFast              bool // Reject operations immediately instead of waiting when all connections are in use
 	VerboseDebug                      bool
 	UpdateTxMinedStatus               bool
 	MaxMinedRoutines                  int
 	MaxMinedBatchSize                 int
-	BlockHeightRetentionAdjustment    int32 // Adjustment to GlobalBlockHeightRetention (can be positive or negative)
-	DisableDAHCleaner                 bool  // Disable the DAH cleaner process completely
+	BlockHeightRetentionAdjustment    int32         // Adjustment to GlobalBlockHeightRetention (can be positive or negative)
+	DisableDAHCleaner                 bool          // Disable the DAH cleaner process completely
+	ReconnectProbeInterval            time.Duration // Interval of the health probe of the utxo store connection, default 0 (automatic reconnection disabled)
+	ReconnectMaxBackoff               time.Duration // Max wait between two reconnection attempts while the utxo store is unreachable
 	// Cleanup-specific settings
 	CleanupParentUpdateBatcherSize           int // Batch size for parent record updates during cleanup
 	CleanupParentUpdateBatcherDurationMillis int // Batch duration for parent record updates during cleanup (ms)
@@ -401,6 +482,11 @@ type P2PSettings struct {
 	StaticPeers []string
 	RelayPeers  []string // Relay peers for NAT traversal (multiaddr strings)
 
+	// DNS seed peer discovery
+	DNSSeeds        []string      // DNS seed hosts publishing peer multiaddrs in "dnsaddr=" TXT records
+	DNSSeedInterval time.Duration // Interval between DNS seed queries after startup, 0 only queries at startup (default: 30m)
+	DisableDNSSeeds bool          // Disables DNS seed peer discovery (default: false)
+
 	// Peer persistence (from go-p2p improvements)
 	PeerCacheDir string // Directory for peer cache file (empty = binary directory)
 
@@ -479,9 +565,30 @@ type SubtreeValidationSettings struct {
 	BlockHeightRetentionAdjustment int32 // Adjustment to GlobalBlockHeightRetention (can be positive or negative)
 	OrphanageTimeout               time.Duration
 	OrphanageMaxSize               int // Maximum number of transactions that can be stored in the orphanage
+	OrphanageMaxPerPeer            int // Maximum number of transactions a single peer can have in the orphanage, 0 is unlimited
 	// Concurrency limits
-	CheckBlockSubtreesConcurrency int           // Concurrency limit for CheckBlockSubtrees operations (default: 32)
-	PauseTimeout                  time.Duration // Maximum duration for subtree processing pauses during block validation (default: 5 minutes)
+	CheckBlockSubtreesConcurrency  int           // Concurrency limit for CheckBlockSubtrees operations (default: 32)
+	SubtreeFetchConcurrencyPerPeer int           // Concurrent subtree requests per peer across all subtrees, 0 is unlimited (default: 16)
+	PauseTimeout                   time.Duration // Maximum duration for subtree processing pauses during block validation (default: 5 minutes)
+	ReuseValidationArena           bool          // Reuse pooled memory for transient data structures across subtree validations (default: true)
+	TransientErrorMaxRetries       int           // Retries of a block subtree validation failing on a transient store or network error, 0 disables retries (default: 3)
+	TransientErrorRetryBackoff     time.Duration // Base backoff between retries of a block subtree validation, increasing linearly per retry (default: 1 second)
+	SubtreeDeadlineFactor          float64       // Multiple of its proportional share of the block validation deadline a single block subtree validation may use, 0 disables (default: 2)
+	BlockPrevoutCacheEnabled       bool          // Extend block transactions spending outputs created earlier in the same block from memory (default: true)
+	VerifySubtreeRootOnIngress     bool          // Recompute the merkle root of a received subtree and reject it when it does not match its hash (default: true)
+	ValidationConcurrency          int           // Transactions of a dependency level validated concurrently, 0 uses GOMAXPROCS (default: 0)
+	ParentRetryMaxAttempts         int           // Attempts to validate a transaction failing on a missing parent, 1 disables retries (default: 1)
+	ParentRetryBaseDelay           time.Duration // Delay before the first retry of a missing parent, doubling per retry (default: 10ms)
+	ParentPrecheckBatchSize        int           // External parent transactions looked up per UTXO store request before the level validation (default: 1024)
+	StreamWindowSize               int           // Transactions read and validated at once when validating a subtree from its subtreeData stream, 0 reads the whole subtree (default: 16384)
+	PreValidatedAllowlistTTL       time.Duration // Time transactions validated by this node stay on the allowlist of which the scripts are not verified again in subtrees, 0 disables (default: 0)
+	ResultCacheSize                int           // Validated subtrees of which the verdict is cached by merkle root, so a subtree presented again is not validated again, 0 disables (default: 1000)
+	FetchMissingParentsMaxAttempts int           // Attempts to fetch the external parents missing from the UTXO store with the parent fetcher before the level validation, 0 disables (default: 2)
+	FetchMissingParentsTimeout     time.Duration // Timeout of fetching a single missing parent with the parent fetcher, 0 waits without a timeout (default: 5s)
+	MaxSubtreeTxCount              int           // Transactions a subtree may hold, larger subtrees are rejected before their transactions are validated, 0 is unlimited (default: 16777216)
+	MaxDependencyDepth             int           // Dependency levels the transactions of a subtree may be chained in, deeper subtrees are rejected before their transactions are validated, 0 is unlimited (default: 100000)
+	ProgressInterval               time.Duration // Interval at which the progress of a level still being validated is reported to the progress callback of a subtree validation, 0 only reports completed levels (default: 1 second)
+	MaxBacklog                     int           // Subtrees awaiting validation before announced subtrees are no longer validated until their block is received, 0 is unlimited (default: 0)
 }
 
 type LegacySettings struct {
@@ -504,6 +611,15 @@ type LegacySettings struct {
 	TempStore                        *url.URL
 	PeerIdleTimeout                  time.Duration
 	PeerProcessingTimeout            time.Duration
+	TipAdvertisementInterval         time.Duration // Interval for proactively announcing the best block to the peers that are behind it (0 = disabled)
+	TxRelayAfterSubtree              bool          // Only relay transactions once they are included in an assembled subtree (default: false)
+	UserAgentName                    string        // User agent name advertised in the version handshake ("" = teranode-legacy-p2p)
+	UserAgentVersion                 string        // User agent version advertised in the version handshake ("" = teranode version)
+	Services                         uint64        // Service bits advertised in the version handshake (0 = determined automatically)
+	MaxHeadersPerGetHeaders          int           // Max headers sent in response to a getheaders message, capped at the protocol maximum of 2000 (default: 2000)
+	IBDFetchAheadBlocks              int           // Blocks read from a peer ahead of their validation during the initial block download (0 = disabled, read after each validation)
+	TxRateLimitPerPeer               float64       // Max transactions per second accepted from a single peer address (default: 0 = unlimited)
+	TxRateLimitBurst                 int           // Max burst of transactions accepted from a single peer address (default: 0 = rate limit rounded up)
 }
 
 type PropagationSettings struct {
@@ -518,19 +634,34 @@ type PropagationSettings struct {
 	SendBatchTimeout     int
 	GRPCAddresses        []string
 	GRPCListenAddress    string
+	TxRateLimitPerSource float64 // Max transactions per second accepted from a single client (default: 0 = unlimited)
+	TxRateLimitBurst     int     // Max burst of transactions accepted from a single client (default: 0 = rate limit rounded up)
+	TxOrdering           string  // Order in which pending transactions are validated, TxOrderingFIFO or TxOrderingFeePriority (default: fifo)
+	TxOrderingWorkers    int     // Number of transactions validated concurrently in fee priority mode (default: 0 = GOMAXPROCS)
+	TxFormat             string  // Serialization format of the transactions accepted, TxFormatAuto, TxFormatStandard or TxFormatExtended (default: auto)
+	TrustExtendedTx      bool    // Validate transactions in extended format with their own previous outputs instead of the utxo store (default: true)
 }
 
 type RPCSettings struct {
-	RPCUser           string
-	RPCPass           string
-	RPCLimitUser      string
-	RPCLimitPass      string
-	RPCMaxClients     int
-	RPCQuirks         bool
-	RPCListenerURL    *url.URL
-	CacheEnabled      bool
-	RPCTimeout        time.Duration
-	ClientCallTimeout time.Duration
+	RPCUser                     string
+	RPCPass                     string
+	RPCLimitUser                string
+	RPCLimitPass                string
+	RPCMaxClients               int
+	RPCQuirks                   bool
+	RPCListenerURL              *url.URL
+	CacheEnabled                bool
+	RPCTimeout                  time.Duration
+	ClientCallTimeout           time.Duration
+	TxRateLimitPerClient        float64       // Max transactions per second accepted via sendrawtransaction from a single client (default: 0 = unlimited)
+	TxRateLimitBurst            int           // Max burst of transactions accepted via sendrawtransaction from a single client (default: 0 = rate limit rounded up)
+	OutputValueIndexEnabled     bool          // Maintain an in-memory index of unspent outputs by value for getoutputsbyvalue (default: false)
+	OutputValueIndexStartHeight uint32        // Block height from which outputs are indexed (default: 0)
+	OutputValueIndexMaxResults  int           // Max outputs returned by a getoutputsbyvalue call, more are returned with a cursor (default: 1000, 0 = unlimited)
+	OutputValueIndexFile        string        // File the output value index is saved to and loaded from on restart (default: "" = rpc_output_value_index.gob in dataFolder)
+	UnconfirmedTxAlertAge       time.Duration // Alert when a transaction sent via sendrawtransaction is not mined after this time (default: 0 = disabled)
+	UnconfirmedTxMaxAlerts      int           // Max unconfirmed transaction alerts raised per UnconfirmedTxAlertInterval, the others are coalesced into a count (default: 0 = unlimited)
+	UnconfirmedTxAlertInterval  time.Duration // Interval over which UnconfirmedTxMaxAlerts applies (default: 1m)
 }
 
 type FaucetSettings struct {
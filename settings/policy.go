@@ -1,16 +1,38 @@
 package settings
 
+// Output script types that can be configured with their own policy limits
+const (
+	ScriptTypeP2PKH    = "p2pkh"
+	ScriptTypeP2SH     = "p2sh"
+	ScriptTypeMultisig = "multisig"
+	ScriptTypeOpReturn = "opreturn"
+)
+
+// ScriptTypePolicy contains the policy limits applied to outputs of a single locking script type.
+type ScriptTypePolicy struct {
+	Standard      bool   `json:"standard"`      // whether outputs of this script type are considered standard
+	DustLimit     uint64 `json:"dustlimit"`     // minimum number of satoshis for an output of this script type
+	MaxScriptSize int    `json:"maxscriptsize"` // maximum locking script size in bytes, 0 is unlimited
+}
+
 type PolicySettings struct {
 	ExcessiveBlockSize              int     `json:"excessiveblocksize"`
 	BlockMaxSize                    int     `json:"blockmaxsize"`
+	ExcessiveBlockWeight            uint64  `json:"excessiveblockweight"` // maximum weight of an accepted block, 0 only applies the size limit
+	BlockMaxWeight                  uint64  `json:"blockmaxweight"`       // maximum weight of an assembled block, 0 only applies the size limit
+	ExcessiveTxPerBlock             uint64  `json:"excessivetxperblock"`  // maximum number of transactions of an accepted block, 0 is unlimited
+	MaxTxPerBlock                   uint64  `json:"maxtxperblock"`        // maximum number of transactions of an assembled block, 0 is unlimited
 	MaxTxSizePolicy                 int     `json:"maxtxsizepolicy"`
 	MaxOrphanTxSize                 int     `json:"maxorphantxsize"`
 	DataCarrierSize                 int64   `json:"datacarriersize"`
 	MaxScriptSizePolicy             int     `json:"maxscriptsizepolicy"`
 	MaxOpsPerScriptPolicy           int64   `json:"maxopsperscriptpolicy"`
 	MaxScriptNumLengthPolicy        int     `json:"maxscriptnumlengthpolicy"`
+	MaxScriptNestingDepthPolicy     int     `json:"maxscriptnestingdepthpolicy"`
 	MaxPubKeysPerMultisigPolicy     int64   `json:"maxpubkeyspermultisigpolicy"`
 	MaxTxSigopsCountsPolicy         int64   `json:"maxtxsigopscountspolicy"`
+	MaxSigOpsPerInputPolicy         int64   `json:"maxsigopsperinputpolicy"`
+	MaxUnconfirmedInputsPolicy      int     `json:"maxunconfirmedinputspolicy"`
 	MaxStackMemoryUsagePolicy       int     `json:"maxstackmemoryusagepolicy"`
 	MaxStackMemoryUsageConsensus    int     `json:"maxstackmemoryusageconsensus"`
 	LimitAncestorCount              int     `json:"limitancestorcount"`
@@ -18,6 +40,8 @@ type PolicySettings struct {
 	AcceptNonStdOutputs             bool    `json:"acceptnonstdoutputs"`
 	DataCarrier                     bool    `json:"datacarrier"`
 	MinMiningTxFee                  float64 `json:"minminingtxfee"`
+	MaxAbsoluteFee                  uint64  `json:"maxabsolutefee"`
+	MinOutputs                      int     `json:"minoutputs"` // minimum number of outputs of a transaction, 0 only applies the consensus minimum of 1
 	MaxStdTxValidationDuration      int     `json:"maxstdtxvalidationduration"`
 	MaxNonStdTxValidationDuration   int     `json:"maxnonstdtxvalidationduration"`
 	MaxTxChainValidationBudget      int     `json:"maxtxchainvalidationbudget"`
@@ -27,6 +51,10 @@ type PolicySettings struct {
 	MinConfConsolidationInput       int     `json:"minconfconsolidationinput"`
 	MinConsolidationInputMaturity   int     `json:"minconsolidationinputmaturity"`
 	AcceptNonStdConsolidationInput  bool    `json:"acceptnonstdconsolidationinput"`
+	// ScriptTypePolicies contains the policy limits per output script type, keyed by the ScriptType constants
+	ScriptTypePolicies map[string]ScriptTypePolicy `json:"scripttypepolicies"`
+	// OpReturnPrefixAllowlist contains the hex encoded protocol prefixes accepted in OP_RETURN outputs, empty accepts any
+	OpReturnPrefixAllowlist []string `json:"opreturnprefixallowlist"`
 }
 
 func NewPolicySettings() *PolicySettings {
@@ -43,6 +71,22 @@ func (ps *PolicySettings) SetBlockMaxSize(size int) {
 	ps.BlockMaxSize = size
 }
 
+func (ps *PolicySettings) SetExcessiveBlockWeight(weight uint64) {
+	ps.ExcessiveBlockWeight = weight
+}
+
+func (ps *PolicySettings) SetBlockMaxWeight(weight uint64) {
+	ps.BlockMaxWeight = weight
+}
+
+func (ps *PolicySettings) SetExcessiveTxPerBlock(count uint64) {
+	ps.ExcessiveTxPerBlock = count
+}
+
+func (ps *PolicySettings) SetMaxTxPerBlock(count uint64) {
+	ps.MaxTxPerBlock = count
+}
+
 func (ps *PolicySettings) SetMaxTxSizePolicy(size int) {
 	ps.MaxTxSizePolicy = size
 }
@@ -67,6 +111,10 @@ func (ps *PolicySettings) SetMaxScriptNumLengthPolicy(size int) {
 	ps.MaxScriptNumLengthPolicy = size
 }
 
+func (ps *PolicySettings) SetMaxScriptNestingDepthPolicy(depth int) {
+	ps.MaxScriptNestingDepthPolicy = depth
+}
+
 func (ps *PolicySettings) SetMaxPubKeysPerMultisigPolicy(size int64) {
 	ps.MaxPubKeysPerMultisigPolicy = size
 }
@@ -75,6 +123,14 @@ func (ps *PolicySettings) SetMaxTxSigopsCountsPolicy(size int64) {
 	ps.MaxTxSigopsCountsPolicy = size
 }
 
+func (ps *PolicySettings) SetMaxSigOpsPerInputPolicy(count int64) {
+	ps.MaxSigOpsPerInputPolicy = count
+}
+
+func (ps *PolicySettings) SetMaxUnconfirmedInputsPolicy(count int) {
+	ps.MaxUnconfirmedInputsPolicy = count
+}
+
 func (ps *PolicySettings) SetMaxStackMemoryUsagePolicy(size int) {
 	ps.MaxStackMemoryUsagePolicy = size
 }
@@ -103,6 +159,14 @@ func (ps *PolicySettings) SetMinMiningTxFee(fee float64) {
 	ps.MinMiningTxFee = fee
 }
 
+func (ps *PolicySettings) SetMaxAbsoluteFee(fee uint64) {
+	ps.MaxAbsoluteFee = fee
+}
+
+func (ps *PolicySettings) SetMinOutputs(count int) {
+	ps.MinOutputs = count
+}
+
 func (ps *PolicySettings) SetMaxStdTxValidationDuration(duration int) {
 	ps.MaxStdTxValidationDuration = duration
 }
@@ -139,6 +203,18 @@ func (ps *PolicySettings) SetAcceptNonStdConsolidationInput(accept bool) {
 	ps.AcceptNonStdConsolidationInput = accept
 }
 
+func (ps *PolicySettings) SetScriptTypePolicy(scriptType string, policy ScriptTypePolicy) {
+	if ps.ScriptTypePolicies == nil {
+		ps.ScriptTypePolicies = make(map[string]ScriptTypePolicy)
+	}
+
+	ps.ScriptTypePolicies[scriptType] = policy
+}
+
+func (ps *PolicySettings) SetOpReturnPrefixAllowlist(prefixes []string) {
+	ps.OpReturnPrefixAllowlist = prefixes
+}
+
 func (ps *PolicySettings) GetExcessiveBlockSize() int {
 	return ps.ExcessiveBlockSize
 }
@@ -147,6 +223,22 @@ func (ps *PolicySettings) GetBlockMaxSize() int {
 	return ps.BlockMaxSize
 }
 
+func (ps *PolicySettings) GetExcessiveBlockWeight() uint64 {
+	return ps.ExcessiveBlockWeight
+}
+
+func (ps *PolicySettings) GetBlockMaxWeight() uint64 {
+	return ps.BlockMaxWeight
+}
+
+func (ps *PolicySettings) GetExcessiveTxPerBlock() uint64 {
+	return ps.ExcessiveTxPerBlock
+}
+
+func (ps *PolicySettings) GetMaxTxPerBlock() uint64 {
+	return ps.MaxTxPerBlock
+}
+
 func (ps *PolicySettings) GetMaxTxSizePolicy() int {
 	return ps.MaxTxSizePolicy
 }
@@ -171,6 +263,10 @@ func (ps *PolicySettings) GetMaxScriptNumLengthPolicy() int {
 	return ps.MaxScriptNumLengthPolicy
 }
 
+func (ps *PolicySettings) GetMaxScriptNestingDepthPolicy() int {
+	return ps.MaxScriptNestingDepthPolicy
+}
+
 func (ps *PolicySettings) GetMaxPubKeysPerMultisigPolicy() int64 {
 	return ps.MaxPubKeysPerMultisigPolicy
 }
@@ -179,6 +275,14 @@ func (ps *PolicySettings) GetMaxTxSigopsCountsPolicy() int64 {
 	return ps.MaxTxSigopsCountsPolicy
 }
 
+func (ps *PolicySettings) GetMaxSigOpsPerInputPolicy() int64 {
+	return ps.MaxSigOpsPerInputPolicy
+}
+
+func (ps *PolicySettings) GetMaxUnconfirmedInputsPolicy() int {
+	return ps.MaxUnconfirmedInputsPolicy
+}
+
 func (ps *PolicySettings) GetMaxStackMemoryUsagePolicy() int {
 	return ps.MaxStackMemoryUsagePolicy
 }
@@ -207,6 +311,14 @@ func (ps *PolicySettings) GetMinMiningTxFee() float64 {
 	return ps.MinMiningTxFee
 }
 
+func (ps *PolicySettings) GetMaxAbsoluteFee() uint64 {
+	return ps.MaxAbsoluteFee
+}
+
+func (ps *PolicySettings) GetMinOutputs() int {
+	return ps.MinOutputs
+}
+
 func (ps *PolicySettings) GetMaxStdTxValidationDuration() int {
 	return ps.MaxStdTxValidationDuration
 }
@@ -242,3 +354,14 @@ func (ps *PolicySettings) GetMinConsolidationInputMaturity() int {
 func (ps *PolicySettings) GetAcceptNonStdConsolidationInput() bool {
 	return ps.AcceptNonStdConsolidationInput
 }
+
+// GetScriptTypePolicy returns the policy configured for the given output script type,
+// and whether a policy has been configured for that script type at all.
+func (ps *PolicySettings) GetScriptTypePolicy(scriptType string) (ScriptTypePolicy, bool) {
+	policy, ok := ps.ScriptTypePolicies[scriptType]
+	return policy, ok
+}
+
+func (ps *PolicySettings) GetOpReturnPrefixAllowlist() []string {
+	return ps.OpReturnPrefixAllowlist
+}
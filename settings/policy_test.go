@@ -153,6 +153,24 @@ func TestPolicySettings_ScriptSettings(t *testing.T) {
 		assert.Equal(t, testValue, ps.GetMaxScriptNumLengthPolicy())
 	})
 
+	t.Run("SetAndGetMaxScriptNestingDepthPolicy", func(t *testing.T) {
+		testValue := 100
+		ps.SetMaxScriptNestingDepthPolicy(testValue)
+		assert.Equal(t, testValue, ps.GetMaxScriptNestingDepthPolicy())
+	})
+
+	t.Run("SetAndGetMaxSigOpsPerInputPolicy", func(t *testing.T) {
+		testValue := int64(500)
+		ps.SetMaxSigOpsPerInputPolicy(testValue)
+		assert.Equal(t, testValue, ps.GetMaxSigOpsPerInputPolicy())
+	})
+
+	t.Run("SetAndGetMaxUnconfirmedInputsPolicy", func(t *testing.T) {
+		testValue := 25
+		ps.SetMaxUnconfirmedInputsPolicy(testValue)
+		assert.Equal(t, testValue, ps.GetMaxUnconfirmedInputsPolicy())
+	})
+
 	t.Run("SetAndGetMaxPubKeysPerMultisigPolicy", func(t *testing.T) {
 		testValue := int64(2147483647)
 		ps.SetMaxPubKeysPerMultisigPolicy(testValue)
@@ -257,6 +275,18 @@ func TestPolicySettings_FeeSettings(t *testing.T) {
 		assert.Equal(t, testValue, ps.GetMinMiningTxFee())
 	})
 
+	t.Run("SetAndGetMaxAbsoluteFee", func(t *testing.T) {
+		testValue := uint64(100_000_000)
+		ps.SetMaxAbsoluteFee(testValue)
+		assert.Equal(t, testValue, ps.GetMaxAbsoluteFee())
+	})
+
+	t.Run("SetAndGetMinOutputs", func(t *testing.T) {
+		testValue := 2
+		ps.SetMinOutputs(testValue)
+		assert.Equal(t, testValue, ps.GetMinOutputs())
+	})
+
 	t.Run("MinMiningTxFeeZeroValue", func(t *testing.T) {
 		ps.SetMinMiningTxFee(0.0)
 		assert.Equal(t, 0.0, ps.GetMinMiningTxFee())
@@ -531,3 +561,31 @@ func TestPolicySettings_DataTypes(t *testing.T) {
 		assert.Equal(t, testValue, ps.GetMinMiningTxFee(), "MinMiningTxFee should store and retrieve float64 values correctly")
 	})
 }
+
+func TestPolicySettings_ScriptTypePolicies(t *testing.T) {
+	ps := NewPolicySettings()
+
+	t.Run("UnconfiguredScriptType", func(t *testing.T) {
+		_, ok := ps.GetScriptTypePolicy(ScriptTypeP2PKH)
+		assert.False(t, ok)
+	})
+
+	t.Run("SetAndGetScriptTypePolicy", func(t *testing.T) {
+		p2pkhPolicy := ScriptTypePolicy{Standard: true, DustLimit: 546}
+		opReturnPolicy := ScriptTypePolicy{Standard: false, MaxScriptSize: 220}
+
+		ps.SetScriptTypePolicy(ScriptTypeP2PKH, p2pkhPolicy)
+		ps.SetScriptTypePolicy(ScriptTypeOpReturn, opReturnPolicy)
+
+		policy, ok := ps.GetScriptTypePolicy(ScriptTypeP2PKH)
+		require.True(t, ok)
+		assert.Equal(t, p2pkhPolicy, policy)
+
+		policy, ok = ps.GetScriptTypePolicy(ScriptTypeOpReturn)
+		require.True(t, ok)
+		assert.Equal(t, opReturnPolicy, policy)
+
+		_, ok = ps.GetScriptTypePolicy(ScriptTypeMultisig)
+		assert.False(t, ok)
+	})
+}
@@ -41,6 +41,7 @@ func NewSettings(alternativeContext ...string) *Settings {
 		TracingEnabled:               getBool("tracing_enabled", false, alternativeContext...),
 		TracingSampleRate:            getFloat64("tracing_SampleRate", 0.01, alternativeContext...),
 		TracingCollectorURL:          getURL("tracing_collector_url", "http://localhost:4318", alternativeContext...),
+		TracingCorrelationIDsEnabled: getBool("tracing_correlation_ids_enabled", true, alternativeContext...),
 		ClientName:                   getString("clientName", "defaultClientName", alternativeContext...),
 		DataFolder:                   getString("dataFolder", "data", alternativeContext...),
 		SecurityLevelHTTP:            getInt("securityLevelHTTP", 0, alternativeContext...),
@@ -53,7 +54,14 @@ func NewSettings(alternativeContext ...string) *Settings {
 		ProfilerAddr:                 getString("profilerAddr", "", alternativeContext...),
 		StatsPrefix:                  getString("stats_prefix", "gocore", alternativeContext...),
 		PrometheusEndpoint:           getString("prometheusEndpoint", "", alternativeContext...),
+		StatsDEndpoint:               getString("statsd_endpoint", "", alternativeContext...),
+		StatsDPrefix:                 getString("statsd_prefix", "teranode", alternativeContext...),
+		StatsDMetrics:                getMultiString("statsd_metrics", "|", []string{"teranode_validator_", "teranode_propagation_", "teranode_blockvalidation_"}, alternativeContext...),
+		StatsDFlushInterval:          getDuration("statsd_flush_interval", 10*time.Second, alternativeContext...),
+		ValidationFailureSink:        getURL("validation_failureSink", "", alternativeContext...),
 		HealthCheckHTTPListenAddress: getString("health_check_httpListenAddress", ":8000", alternativeContext...),
+		GracefulRestartEnabled:       getBool("graceful_restart_enabled", false, alternativeContext...),
+		GracefulRestartReadyTimeout:  getDuration("graceful_restart_ready_timeout", 2*time.Minute, alternativeContext...),
 		UseDatadogProfiler:           getBool("use_datadog_profiler", false, alternativeContext...),
 		LocalTestStartFromState:      getString("local_test_start_from_state", "", alternativeContext...),
 		PostgresCheckAddress:         getString("postgres_check_address", "localhost:5432", alternativeContext...),
@@ -69,19 +77,30 @@ func NewSettings(alternativeContext ...string) *Settings {
 		ChainCfgParams: params,
 		Policy: &PolicySettings{
 			ExcessiveBlockSize: getInt("excessiveblocksize", 4294967296, alternativeContext...), // 4GB
+			// block weight limits, 0 only applies the block size limits
+			ExcessiveBlockWeight: getUint64("excessiveblockweight", 0, alternativeContext...),
+			BlockMaxWeight:       getUint64("blockmaxweight", 0, alternativeContext...),
+			// transaction count limits, 0 is unlimited, the excessive count is a consensus rule set per network
+			ExcessiveTxPerBlock: getUint64("excessivetxperblock", 0, alternativeContext...),
+			MaxTxPerBlock:       getUint64("maxtxperblock", 0, alternativeContext...),
 			// TODO: change BlockMaxSize to uint64
 			//nolint:gosec // G115: integer overflow conversion uint64 -> int (gosec)
 			BlockMaxSize:    int(blockMaxSize),
 			MaxTxSizePolicy: getInt("maxtxsizepolicy", 10485760, alternativeContext...), // 10MB
 			MinMiningTxFee:  getFloat64("minminingtxfee", 0.00000500, alternativeContext...),
+			MaxAbsoluteFee:  getUint64("maxabsolutefee", 0, alternativeContext...), // satoshis, 0 is unlimited
+			MinOutputs:      getInt("minoutputs", 0, alternativeContext...),        // 0 is the consensus minimum of 1
 			// MaxOrphanTxSize:                 getInt("maxorphantxsize", 1000000, alternativeContext...),
-			// DataCarrierSize:                 int64(getInt("datacarriersize", 1000000, alternativeContext...)),
+			DataCarrierSize:     int64(getInt("datacarriersize", 0, alternativeContext...)),   // 0 is the maxscriptsizepolicy
 			MaxScriptSizePolicy: getInt("maxscriptsizepolicy", 500000, alternativeContext...), // 500KB
 			// TODO: what should this be?
 			// MaxOpsPerScriptPolicy:           int64(getInt("maxopsperscriptpolicy", 1000000, alternativeContext...)),
 			MaxScriptNumLengthPolicy:     getInt("maxscriptnumlengthpolicy", 10000, alternativeContext...),       // 10K
+			MaxScriptNestingDepthPolicy:  getInt("maxscriptnestingdepthpolicy", 0, alternativeContext...),        // 0 is unlimited
 			MaxPubKeysPerMultisigPolicy:  int64(getInt("maxpubkeyspermultisigpolicy", 0, alternativeContext...)), // 0 is unlimited
 			MaxTxSigopsCountsPolicy:      int64(getInt("maxtxsigopscountspolicy", 0, alternativeContext...)),     // 0 is unlimited
+			MaxSigOpsPerInputPolicy:      int64(getInt("maxsigopsperinputpolicy", 0, alternativeContext...)),     // 0 is unlimited
+			MaxUnconfirmedInputsPolicy:   getInt("maxunconfirmedinputspolicy", 0, alternativeContext...),         // 0 is unlimited
 			MaxStackMemoryUsagePolicy:    getInt("maxstackmemoryusagepolicy", 104857600, alternativeContext...),  // 100MB
 			MaxStackMemoryUsageConsensus: getInt("maxstackmemoryusageconsensus", 0, alternativeContext...),       // 0 is unlimited
 			// LimitAncestorCount:              getInt("limitancestorcount", 1000000, alternativeContext...),
@@ -97,30 +116,39 @@ func NewSettings(alternativeContext ...string) *Settings {
 			MinConfConsolidationInput:       getInt("minconfconsolidationinput", 6, alternativeContext...),
 			MinConsolidationInputMaturity:   getInt("minconsolidationinputmaturity", 6, alternativeContext...),
 			AcceptNonStdConsolidationInput:  getBool("acceptnonstdconsolidationinput", false, alternativeContext...),
+			ScriptTypePolicies: map[string]ScriptTypePolicy{
+				ScriptTypeP2PKH:    getScriptTypePolicy(ScriptTypeP2PKH, 1, alternativeContext...),
+				ScriptTypeP2SH:     getScriptTypePolicy(ScriptTypeP2SH, 1, alternativeContext...),
+				ScriptTypeMultisig: getScriptTypePolicy(ScriptTypeMultisig, 1, alternativeContext...),
+				ScriptTypeOpReturn: getScriptTypePolicy(ScriptTypeOpReturn, 0, alternativeContext...),
+			},
+			OpReturnPrefixAllowlist: getMultiString("policy_opreturn_prefixallowlist", "|", []string{}, alternativeContext...), // empty accepts any prefix
 		},
 		Kafka: KafkaSettings{
-			Blocks:                getString("KAFKA_BLOCKS", "blocks", alternativeContext...),
-			BlocksFinal:           getString("KAFKA_BLOCKS_FINAL", "blocks-final", alternativeContext...),
-			Hosts:                 getString("KAFKA_HOSTS", "localhost:9092", alternativeContext...),
-			InvalidBlocks:         getString("KAFKA_INVALID_BLOCKS", "invalid-blocks", alternativeContext...),
-			InvalidSubtrees:       getString("KAFKA_INVALID_SUBTREES", "invalid-subtrees", alternativeContext...),
-			LegacyInv:             getString("KAFKA_LEGACY_INV", "legacy-inv", alternativeContext...),
-			Partitions:            getInt("KAFKA_PARTITIONS", 1, alternativeContext...),
-			Port:                  getInt("KAFKA_PORT", 9092, alternativeContext...),
-			RejectedTx:            getString("KAFKA_REJECTEDTX", "rejectedtx", alternativeContext...),
-			ReplicationFactor:     getInt("KAFKA_REPLICATION_FACTOR", 1, alternativeContext...),
-			Subtrees:              getString("KAFKA_SUBTREES", "subtrees", alternativeContext...),
-			TxMeta:                getString("KAFKA_TXMETA", "txmeta", alternativeContext...),
-			UnitTest:              getString("KAFKA_UNITTEST", "unittest", alternativeContext...),
-			ValidatorTxsConfig:    getURL("kafka_validatortxsConfig", "", alternativeContext...),
-			TxMetaConfig:          getURL("kafka_txmetaConfig", "", alternativeContext...),
-			LegacyInvConfig:       getURL("kafka_legacyInvConfig", "", alternativeContext...),
-			BlocksFinalConfig:     getURL("kafka_blocksFinalConfig", "", alternativeContext...),
-			RejectedTxConfig:      getURL("kafka_rejectedTxConfig", "", alternativeContext...),
-			InvalidBlocksConfig:   getURL("kafka_invalidBlocksConfig", "", alternativeContext...),
-			InvalidSubtreesConfig: getURL("kafka_invalidSubtreesConfig", "", alternativeContext...),
-			SubtreesConfig:        getURL("kafka_subtreesConfig", "", alternativeContext...),
-			BlocksConfig:          getURL("kafka_blocksConfig", "", alternativeContext...),
+			Blocks:                         getString("KAFKA_BLOCKS", "blocks", alternativeContext...),
+			BlocksFinal:                    getString("KAFKA_BLOCKS_FINAL", "blocks-final", alternativeContext...),
+			Hosts:                          getString("KAFKA_HOSTS", "localhost:9092", alternativeContext...),
+			InvalidBlocks:                  getString("KAFKA_INVALID_BLOCKS", "invalid-blocks", alternativeContext...),
+			InvalidSubtrees:                getString("KAFKA_INVALID_SUBTREES", "invalid-subtrees", alternativeContext...),
+			LegacyInv:                      getString("KAFKA_LEGACY_INV", "legacy-inv", alternativeContext...),
+			Partitions:                     getInt("KAFKA_PARTITIONS", 1, alternativeContext...),
+			Port:                           getInt("KAFKA_PORT", 9092, alternativeContext...),
+			RejectedTx:                     getString("KAFKA_REJECTEDTX", "rejectedtx", alternativeContext...),
+			ReplicationFactor:              getInt("KAFKA_REPLICATION_FACTOR", 1, alternativeContext...),
+			Subtrees:                       getString("KAFKA_SUBTREES", "subtrees", alternativeContext...),
+			TxMeta:                         getString("KAFKA_TXMETA", "txmeta", alternativeContext...),
+			UnitTest:                       getString("KAFKA_UNITTEST", "unittest", alternativeContext...),
+			ValidatorTxsConfig:             getURL("kafka_validatortxsConfig", "", alternativeContext...),
+			TxMetaConfig:                   getURL("kafka_txmetaConfig", "", alternativeContext...),
+			LegacyInvConfig:                getURL("kafka_legacyInvConfig", "", alternativeContext...),
+			BlocksFinalConfig:              getURL("kafka_blocksFinalConfig", "", alternativeContext...),
+			RejectedTxConfig:               getURL("kafka_rejectedTxConfig", "", alternativeContext...),
+			InvalidBlocksConfig:            getURL("kafka_invalidBlocksConfig", "", alternativeContext...),
+			InvalidSubtreesConfig:          getURL("kafka_invalidSubtreesConfig", "", alternativeContext...),
+			SubtreesConfig:                 getURL("kafka_subtreesConfig", "", alternativeContext...),
+			BlocksConfig:                   getURL("kafka_blocksConfig", "", alternativeContext...),
+			ValidationResultsConfig:        getURL("kafka_validationResultsConfig", "", alternativeContext...),
+			SubtreeValidationResultsConfig: getURL("kafka_subtreeValidationResultsConfig", "", alternativeContext...),
 			// TLS settings
 			EnableTLS:     getBool("KAFKA_ENABLE_TLS", false, alternativeContext...),
 			TLSSkipVerify: getBool("KAFKA_TLS_SKIP_VERIFY", false, alternativeContext...),
@@ -153,15 +181,20 @@ func NewSettings(alternativeContext ...string) *Settings {
 			P2PPort:       getPort("ALERT_P2P_PORT", 9908, alternativeContext...),
 		},
 		Asset: AssetSettings{
-			APIPrefix:               getString("asset_apiPrefix", "/api/v1", alternativeContext...),
-			CentrifugeListenAddress: getString("asset_centrifugeListenAddress", ":8892", alternativeContext...),
-			CentrifugeDisable:       getBool("asset_centrifuge_disable", false, alternativeContext...),
-			HTTPAddress:             getString("asset_httpAddress", "http://localhost:8090/api/v1", alternativeContext...),
-			HTTPPublicAddress:       getString("asset_httpPublicAddress", "", alternativeContext...),
-			HTTPListenAddress:       getString("asset_httpListenAddress", ":8090", alternativeContext...),
-			HTTPPort:                getPort("ASSET_HTTP_PORT", 8090, alternativeContext...),
-			SignHTTPResponses:       getBool("asset_sign_http_responses", false, alternativeContext...),
-			EchoDebug:               getBool("ECHO_DEBUG", false, alternativeContext...),
+			APIPrefix:                 getString("asset_apiPrefix", "/api/v1", alternativeContext...),
+			CentrifugeListenAddress:   getString("asset_centrifugeListenAddress", ":8892", alternativeContext...),
+			CentrifugeDisable:         getBool("asset_centrifuge_disable", false, alternativeContext...),
+			HTTPAddress:               getString("asset_httpAddress", "http://localhost:8090/api/v1", alternativeContext...),
+			HTTPPublicAddress:         getString("asset_httpPublicAddress", "", alternativeContext...),
+			HTTPListenAddress:         getString("asset_httpListenAddress", ":8090", alternativeContext...),
+			HTTPPort:                  getPort("ASSET_HTTP_PORT", 8090, alternativeContext...),
+			SignHTTPResponses:         getBool("asset_sign_http_responses", false, alternativeContext...),
+			EchoDebug:                 getBool("ECHO_DEBUG", false, alternativeContext...),
+			ProofRateLimitPerClient:   getFloat64("asset_proofRateLimitPerClient", 0, alternativeContext...),
+			ProofRateLimitBurst:       getInt("asset_proofRateLimitBurst", 0, alternativeContext...),
+			MaxConcurrentProofs:       getInt("asset_maxConcurrentProofs", 0, alternativeContext...),
+			ProofQueueTimeout:         getDuration("asset_proofQueueTimeout", 5*time.Second, alternativeContext...),
+			MaxBlockHeadersPerRequest: getInt("asset_maxBlockHeadersPerRequest", 10_000, alternativeContext...),
 		},
 		Block: BlockSettings{
 			MinedCacheMaxMB:                       getInt("blockMinedCacheMaxMB", 256, alternativeContext...),
@@ -187,10 +220,12 @@ func NewSettings(alternativeContext ...string) *Settings {
 			TxStore:                               getURL("txstore", "", alternativeContext...),
 			BlockPersisterPersistAge:              uint32(getInt("blockpersister_persistAge", 2, alternativeContext...)), //nolint:gosec // G115: integer overflow conversion int -> uint32 (gosec)
 			BlockPersisterPersistSleep:            getDuration("blockPersister_persistSleep", time.Minute, alternativeContext...),
+			BlockPersisterPruneDepth:              uint32(getInt("blockpersister_pruneDepth", 0, alternativeContext...)), //nolint:gosec // G115: integer overflow conversion int -> uint32 (gosec)
 			UtxoStore:                             getURL("txmeta_store", "", alternativeContext...),
 			FileStoreReadConcurrency:              getInt("filestore_read_concurrency", 768, alternativeContext...),
 			FileStoreWriteConcurrency:             getInt("filestore_write_concurrency", 256, alternativeContext...),
 			FileStoreUseSystemLimits:              getBool("filestore_use_system_limits", true, alternativeContext...),
+			BIP34ActivationHeight:                 getUint32("block_bip34ActivationHeight", uint32(params.BIP0034Height), alternativeContext...), //nolint:gosec // G115: the BIP34 height of a network is never negative
 		},
 		BlockAssembly: BlockAssemblySettings{
 			Disabled:                            getBool("blockassembly_disabled", false, alternativeContext...),
@@ -222,6 +257,11 @@ func NewSettings(alternativeContext ...string) *Settings {
 			BlockchainSubscriptionTimeout:       getDuration("blockassembly_blockchainSubscriptionTimeout", 5*time.Minute, alternativeContext...),
 			ValidateParentChainOnRestart:        getBool("blockassembly_validateParentChainOnRestart", true, alternativeContext...),
 			ParentValidationBatchSize:           getInt("blockassembly_parentValidationBatchSize", 1000, alternativeContext...),
+			CoinbaseTag:                         getString("blockassembly_coinbaseTag", "", alternativeContext...),
+			MaxPriorityTxs:                      getInt("blockassembly_maxPriorityTxs", 1000, alternativeContext...),
+			MoveBackBlockPrefetch:               getInt("blockassembly_moveBackBlockPrefetch", 0, alternativeContext...),
+			MiningCandidateMaxAge:               getDuration("blockassembly_miningCandidateMaxAge", 0, alternativeContext...),
+			TxSelectionStrategy:                 getString("blockassembly_txSelectionStrategy", "fifo", alternativeContext...),
 		},
 		BlockChain: BlockChainSettings{
 			GRPCAddress:           getString("blockchain_grpcAddress", "localhost:8087", alternativeContext...),
@@ -278,6 +318,7 @@ func NewSettings(alternativeContext ...string) *Settings {
 			CatchupIterationTimeout:      getInt("blockvalidation_catchup_iteration_timeout", 30, alternativeContext...),
 			CatchupOperationTimeout:      getInt("blockvalidation_catchup_operation_timeout", 300, alternativeContext...),
 			CatchupMaxAccumulatedHeaders: getInt("blockvalidation_max_accumulated_headers", 100000, alternativeContext...),
+			CatchupMaxQueuedBlocks:       getInt("blockvalidation_catchup_max_queued_blocks", 500, alternativeContext...),
 			// Catchup circuit breaker configuration
 			CircuitBreakerFailureThreshold: getInt("blockvalidation_circuit_breaker_failure_threshold", 5, alternativeContext...),
 			CircuitBreakerSuccessThreshold: getInt("blockvalidation_circuit_breaker_success_threshold", 2, alternativeContext...),
@@ -286,13 +327,23 @@ func NewSettings(alternativeContext ...string) *Settings {
 			FetchLargeBatchSize:             getInt("blockvalidation_fetch_large_batch_size", 100, alternativeContext...),
 			FetchNumWorkers:                 getInt("blockvalidation_fetch_num_workers", 16, alternativeContext...),
 			FetchBufferSize:                 getInt("blockvalidation_fetch_buffer_size", 50, alternativeContext...),
+			FetchBlockMaxRetries:            getInt("blockvalidation_fetch_block_max_retries", 10, alternativeContext...),
+			FetchBlockRetryDelay:            getDuration("blockvalidation_fetch_block_retry_delay", 5*time.Second, alternativeContext...),
 			SubtreeFetchConcurrency:         getInt("blockvalidation_subtree_fetch_concurrency", 8, alternativeContext...),
+			SubtreeFetchConcurrencyPerPeer:  getInt("blockvalidation_subtree_fetch_concurrency_per_peer", 16, alternativeContext...),
+			PeerDownloadBudgetBytes:         getUint64("blockvalidation_peer_download_budget_bytes", 0, alternativeContext...),
+			PeerDownloadBudgetInterval:      getDuration("blockvalidation_peer_download_budget_interval", time.Minute, alternativeContext...),
 			ExtendTransactionTimeout:        getDuration("blockvalidation_extend_transaction_timeout", 120*time.Second, alternativeContext...),
 			GetBlockTransactionsConcurrency: getInt("blockvalidation_get_block_transactions_concurrency", 64, alternativeContext...),
 			// Priority queue and fork processing settings
 			NearForkThreshold: getInt("blockvalidation_near_fork_threshold", 0, alternativeContext...), // 0 means use default (coinbase maturity / 2)
 			MaxParallelForks:  getInt("blockvalidation_max_parallel_forks", 4, alternativeContext...),
 			MaxTrackedForks:   getInt("blockvalidation_max_tracked_forks", 1000, alternativeContext...),
+			// Validation result cache
+			ValidationResultCacheTTL: getDuration("blockvalidation_validation_result_cache_ttl", time.Minute, alternativeContext...),
+			// Proof of work
+			SkipProofOfWorkCheck: getBool("blockvalidation_skipProofOfWorkCheck", false, alternativeContext...),
+			PrivateNetwork:       getBool("blockvalidation_privateNetwork", false, alternativeContext...),
 		},
 		Validator: ValidatorSettings{
 			GRPCAddress:               getString("validator_grpcAddress", "localhost:8081", alternativeContext...),
@@ -310,6 +361,16 @@ func NewSettings(alternativeContext ...string) *Settings {
 			HTTPRateLimit:             getInt("validator_httpRateLimit", 1024, alternativeContext...),
 			KafkaMaxMessageBytes:      getInt("validator_kafka_maxMessageBytes", 1024*1024, alternativeContext...), // Default 1MB
 			UseLocalValidator:         getBool("useLocalValidator", false, alternativeContext...),
+			SkipKnownTransactions:     getBool("validator_skipKnownTransactions", false, alternativeContext...),
+			MaxBacklog:                getInt("validator_maxBacklog", 0, alternativeContext...),
+			MaxValidationGoroutines:   getInt("validator_maxValidationGoroutines", 0, alternativeContext...),
+			CheckCoinbaseOnChain:      getBool("validator_checkCoinbaseOnChain", true, alternativeContext...),
+			RejectNonFinal:            getBool("validator_rejectNonFinal", true, alternativeContext...),
+			TxMetaDedupWindow:         getDuration("validator_txMetaDedupWindow", 0, alternativeContext...),
+			CanonicalTxOrdering:       getString("validator_canonicalTxOrdering", "none", alternativeContext...),
+			ValidationCacheTTL:        getDuration("validator_validationCacheTTL", 0, alternativeContext...),
+			SeenInvalidCacheSize:      getInt("validator_seenInvalidCacheSize", 0, alternativeContext...),
+			FeeDistributionWindow:     getInt("validator_feeDistributionWindow", 0, alternativeContext...),
 		},
 		Region: RegionSettings{
 			Name: getString("regionName", "defaultRegionName", alternativeContext...),
@@ -346,12 +407,15 @@ func NewSettings(alternativeContext ...string) *Settings {
 			ExternalizeAllTransactions:        getBool("utxostore_externalizeAllTransactions", false, alternativeContext...),
 			PostgresMaxIdleConns:              getInt("utxostore_utxo_postgresMaxIdleConns", 10, alternativeContext...),
 			PostgresMaxOpenConns:              getInt("utxostore_utxo_postgresMaxOpenConns", 80, alternativeContext...),
+			PostgresPoolFailFast:              getBool("utxostore_utxo_postgresPoolFailFast", false, alternativeContext...),
 			VerboseDebug:                      getBool("utxostore_verbose_debug", false, alternativeContext...),
 			UpdateTxMinedStatus:               getBool("utxostore_updateTxMinedStatus", true, alternativeContext...),
 			MaxMinedRoutines:                  getInt("utxostore_maxMinedRoutines", 128, alternativeContext...),
 			MaxMinedBatchSize:                 getInt("utxostore_maxMinedBatchSize", 1024, alternativeContext...),
 			BlockHeightRetentionAdjustment:    getInt32("utxostore_blockHeightRetentionAdjustment", 0, alternativeContext...),
 			DisableDAHCleaner:                 getBool("utxostore_disableDAHCleaner", false, alternativeContext...),
+			ReconnectProbeInterval:            getDuration("utxostore_reconnectProbeInterval", 0, alternativeContext...),
+			ReconnectMaxBackoff:               getDuration("utxostore_reconnectMaxBackoff", time.Minute, alternativeContext...),
 			// Cleanup-specific settings with reasonable defaults
 			CleanupParentUpdateBatcherSize:           getInt("utxostore_cleanupParentUpdateBatcherSize", 100, alternativeContext...),
 			CleanupParentUpdateBatcherDurationMillis: getInt("utxostore_cleanupParentUpdateBatcherDurationMillis", 10, alternativeContext...),
@@ -376,6 +440,9 @@ func NewSettings(alternativeContext ...string) *Settings {
 			RejectedTxTopic:    getString("p2p_rejected_tx_topic", "", alternativeContext...),
 			StaticPeers:        getMultiString("p2p_static_peers", "|", []string{}, alternativeContext...),
 			RelayPeers:         getMultiString("p2p_relay_peers", "|", []string{}, alternativeContext...),
+			DNSSeeds:           getMultiString("p2p_dns_seeds", "|", []string{}, alternativeContext...),
+			DNSSeedInterval:    getDuration("p2p_dns_seed_interval", 30*time.Minute, alternativeContext...),
+			DisableDNSSeeds:    getBool("p2p_disable_dns_seeds", false, alternativeContext...),
 			// Peer persistence
 			PeerCacheDir: getString("p2p_peer_cache_dir", "", alternativeContext...), // Empty = binary directory
 			BanThreshold: getInt("p2p_ban_threshold", 100, alternativeContext...),
@@ -435,8 +502,29 @@ func NewSettings(alternativeContext ...string) *Settings {
 			BlockHeightRetentionAdjustment:            getInt32("subtreevalidation_blockHeightRetentionAdjustment", 0, alternativeContext...),
 			OrphanageTimeout:                          getDuration("subtreevalidation_orphanageTimeout", 15*time.Minute, alternativeContext...),
 			OrphanageMaxSize:                          getInt("subtreevalidation_orphanageMaxSize", 100_000, alternativeContext...),
+			OrphanageMaxPerPeer:                       getInt("subtreevalidation_orphanageMaxPerPeer", 0, alternativeContext...),
 			CheckBlockSubtreesConcurrency:             getInt("subtreevalidation_check_block_subtrees_concurrency", 32, alternativeContext...),
+			SubtreeFetchConcurrencyPerPeer:            getInt("subtreevalidation_subtree_fetch_concurrency_per_peer", 16, alternativeContext...),
 			PauseTimeout:                              getDuration("subtreevalidation_pauseTimeout", 5*time.Minute, alternativeContext...),
+			ReuseValidationArena:                      getBool("subtreevalidation_reuseValidationArena", true, alternativeContext...),
+			TransientErrorMaxRetries:                  getInt("subtreevalidation_transientErrorMaxRetries", 3, alternativeContext...),
+			TransientErrorRetryBackoff:                getDuration("subtreevalidation_transientErrorRetryBackoff", time.Second, alternativeContext...),
+			SubtreeDeadlineFactor:                     getFloat64("subtreevalidation_subtreeDeadlineFactor", 2, alternativeContext...),
+			BlockPrevoutCacheEnabled:                  getBool("subtreevalidation_blockPrevoutCacheEnabled", true, alternativeContext...),
+			VerifySubtreeRootOnIngress:                getBool("subtreevalidation_verifySubtreeRootOnIngress", true, alternativeContext...),
+			ValidationConcurrency:                     getInt("subtreevalidation_validationConcurrency", 0, alternativeContext...),
+			ParentRetryMaxAttempts:                    getInt("subtreevalidation_parentRetryMaxAttempts", 1, alternativeContext...),
+			ParentRetryBaseDelay:                      getDuration("subtreevalidation_parentRetryBaseDelay", 10*time.Millisecond, alternativeContext...),
+			ParentPrecheckBatchSize:                   getInt("subtreevalidation_parentPrecheckBatchSize", 1024, alternativeContext...),
+			StreamWindowSize:                          getInt("subtreevalidation_streamWindowSize", 16384, alternativeContext...),
+			PreValidatedAllowlistTTL:                  getDuration("subtreevalidation_preValidatedAllowlistTTL", 0, alternativeContext...),
+			ResultCacheSize:                           getInt("subtreevalidation_resultCacheSize", 1000, alternativeContext...),
+			FetchMissingParentsMaxAttempts:            getInt("subtreevalidation_fetchMissingParentsMaxAttempts", 2, alternativeContext...),
+			FetchMissingParentsTimeout:                getDuration("subtreevalidation_fetchMissingParentsTimeout", 5*time.Second, alternativeContext...),
+			MaxSubtreeTxCount:                         getInt("subtreevalidation_maxSubtreeTxCount", 16_777_216, alternativeContext...),
+			MaxDependencyDepth:                        getInt("subtreevalidation_maxDependencyDepth", 100_000, alternativeContext...),
+			ProgressInterval:                          getDuration("subtreevalidation_progressInterval", time.Second, alternativeContext...),
+			MaxBacklog:                                getInt("subtreevalidation_maxBacklog", 0, alternativeContext...),
 		},
 		Legacy: LegacySettings{
 			WorkingDir:                       getString("legacy_workingDir", "../../data", alternativeContext...),
@@ -458,6 +546,15 @@ func NewSettings(alternativeContext ...string) *Settings {
 			TempStore:                        getURL("temp_store", "file://./data/tempstore", alternativeContext...),
 			PeerIdleTimeout:                  getDuration("legacy_peerIdleTimeout", 125*time.Second, alternativeContext...),     // ping/pong interval is 2 mins, so we set this to 125s to be sure
 			PeerProcessingTimeout:            getDuration("legacy_peerProcessingTimeout", 3*time.Minute, alternativeContext...), // processing a block will be the largest message to process
+			TipAdvertisementInterval:         getDuration("legacy_tipAdvertisementInterval", 0, alternativeContext...),
+			TxRelayAfterSubtree:              getBool("legacy_txRelayAfterSubtree", false, alternativeContext...),
+			UserAgentName:                    getString("legacy_userAgentName", "", alternativeContext...),
+			UserAgentVersion:                 getString("legacy_userAgentVersion", "", alternativeContext...),
+			Services:                         getUint64("legacy_services", 0, alternativeContext...),
+			MaxHeadersPerGetHeaders:          getInt("legacy_maxHeadersPerGetHeaders", 2000, alternativeContext...),
+			IBDFetchAheadBlocks:              getInt("legacy_ibdFetchAheadBlocks", 0, alternativeContext...),
+			TxRateLimitPerPeer:               getFloat64("legacy_txRateLimitPerPeer", 0, alternativeContext...),
+			TxRateLimitBurst:                 getInt("legacy_txRateLimitBurst", 0, alternativeContext...),
 		},
 		Propagation: PropagationSettings{
 			IPv6Addresses:        getString("ipv6_addresses", "", alternativeContext...),
@@ -471,18 +568,33 @@ func NewSettings(alternativeContext ...string) *Settings {
 			SendBatchTimeout:     getInt("propagation_sendBatchTimeout", 5, alternativeContext...),
 			GRPCAddresses:        getMultiString("propagation_grpcAddresses", "|", []string{}, alternativeContext...),
 			GRPCListenAddress:    getString("propagation_grpcListenAddress", "", alternativeContext...),
+			TxRateLimitPerSource: getFloat64("propagation_txRateLimitPerSource", 0, alternativeContext...),
+			TxRateLimitBurst:     getInt("propagation_txRateLimitBurst", 0, alternativeContext...),
+			TxOrdering:           getString("propagation_txOrdering", TxOrderingFIFO, alternativeContext...),
+			TxOrderingWorkers:    getInt("propagation_txOrderingWorkers", 0, alternativeContext...),
+			TxFormat:             getString("propagation_txFormat", TxFormatAuto, alternativeContext...),
+			TrustExtendedTx:      getBool("propagation_trustExtendedTx", true, alternativeContext...),
 		},
 		RPC: RPCSettings{
-			RPCUser:           getString("rpc_user", "", alternativeContext...),
-			RPCPass:           getString("rpc_pass", "", alternativeContext...),
-			RPCLimitUser:      getString("rpc_limit_user", "", alternativeContext...),
-			RPCLimitPass:      getString("rpc_limit_pass", "", alternativeContext...),
-			RPCMaxClients:     getInt("rpc_max_clients", 1, alternativeContext...),
-			RPCQuirks:         getBool("rpc_quirks", true, alternativeContext...),
-			RPCListenerURL:    getURL("rpc_listener_url", "", alternativeContext...),
-			CacheEnabled:      getBool("rpc_cache_enabled", true, alternativeContext...),
-			RPCTimeout:        getDuration("rpc_timeout", 30*time.Second, alternativeContext...),
-			ClientCallTimeout: getDuration("rpc_client_call_timeout", 5*time.Second, alternativeContext...),
+			RPCUser:                     getString("rpc_user", "", alternativeContext...),
+			RPCPass:                     getString("rpc_pass", "", alternativeContext...),
+			RPCLimitUser:                getString("rpc_limit_user", "", alternativeContext...),
+			RPCLimitPass:                getString("rpc_limit_pass", "", alternativeContext...),
+			RPCMaxClients:               getInt("rpc_max_clients", 1, alternativeContext...),
+			RPCQuirks:                   getBool("rpc_quirks", true, alternativeContext...),
+			RPCListenerURL:              getURL("rpc_listener_url", "", alternativeContext...),
+			CacheEnabled:                getBool("rpc_cache_enabled", true, alternativeContext...),
+			RPCTimeout:                  getDuration("rpc_timeout", 30*time.Second, alternativeContext...),
+			ClientCallTimeout:           getDuration("rpc_client_call_timeout", 5*time.Second, alternativeContext...),
+			TxRateLimitPerClient:        getFloat64("rpc_txRateLimitPerClient", 0, alternativeContext...),
+			TxRateLimitBurst:            getInt("rpc_txRateLimitBurst", 0, alternativeContext...),
+			OutputValueIndexEnabled:     getBool("rpc_outputValueIndexEnabled", false, alternativeContext...),
+			OutputValueIndexStartHeight: getUint32("rpc_outputValueIndexStartHeight", 0, alternativeContext...),
+			OutputValueIndexMaxResults:  getInt("rpc_outputValueIndexMaxResults", 1000, alternativeContext...),
+			OutputValueIndexFile:        getString("rpc_outputValueIndexFile", "", alternativeContext...),
+			UnconfirmedTxAlertAge:       getDuration("rpc_unconfirmedTxAlertAge", 0, alternativeContext...),
+			UnconfirmedTxMaxAlerts:      getInt("rpc_unconfirmedTxMaxAlerts", 0, alternativeContext...),
+			UnconfirmedTxAlertInterval:  getDuration("rpc_unconfirmedTxAlertInterval", time.Minute, alternativeContext...),
 		},
 		Faucet: FaucetSettings{
 			HTTPListenAddress: getString("faucet_httpListenAddress", "", alternativeContext...),
@@ -49,6 +49,36 @@ func TestGenesisActivationHeight(t *testing.T) {
 	}
 }
 
+func TestProofOfWorkCheckRequired(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   *chaincfg.Params
+		skip     bool
+		private  bool
+		required bool
+	}{
+		{"MainNet", &chaincfg.MainNetParams, false, false, true},
+		{"MainNet skip is not allowed", &chaincfg.MainNetParams, true, false, true},
+		{"MainNet private skip is not allowed", &chaincfg.MainNetParams, true, true, true},
+		{"RegressionNet", &chaincfg.RegressionNetParams, false, false, true},
+		{"RegressionNet skip", &chaincfg.RegressionNetParams, true, false, false},
+		{"TestNet skip is not allowed", &chaincfg.TestNetParams, true, false, true},
+		{"TeraTestNet skip is not allowed", &chaincfg.TeraTestNetParams, true, false, true},
+		{"Private network skip", &chaincfg.TeraTestNetParams, true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tSettings := NewSettings()
+			tSettings.ChainCfgParams = tt.params
+			tSettings.BlockValidation.SkipProofOfWorkCheck = tt.skip
+			tSettings.BlockValidation.PrivateNetwork = tt.private
+
+			require.Equal(t, tt.required, tSettings.ProofOfWorkCheckRequired())
+		})
+	}
+}
+
 func TestBlockHeightRetentionAdjustments(t *testing.T) {
 	t.Run("DefaultAdjustmentValues", func(t *testing.T) {
 		tSettings := NewSettings()
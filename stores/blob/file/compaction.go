@@ -0,0 +1,271 @@
+package file
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// compactionMinAge is the minimum age of a leftover file or an empty directory before compaction removes it,
+// so files and directories of writes that are still in progress are never removed
+const compactionMinAge = 10 * time.Minute
+
+// Compaction runs at a low priority, it pauses for compactionPause after every compactionPauseEvery entries it
+// has examined, leaving the disk and the file semaphores to the foreground reads and writes
+const (
+	compactionPauseEvery = 1000
+	compactionPause      = 10 * time.Millisecond
+)
+
+// compactionResult holds what a single compaction run has removed
+type compactionResult struct {
+	// tmpFiles is the number of leftover temporary files of failed or interrupted writes
+	tmpFiles int
+	// orphanFiles is the number of checksum and DAH files of blobs that no longer exist
+	orphanFiles int
+	// directories is the number of empty directories
+	directories int
+	// reclaimedBytes is the total size of the removed files and directories
+	reclaimedBytes int64
+}
+
+func (r compactionResult) removed() int {
+	return r.tmpFiles + r.orphanFiles + r.directories
+}
+
+// compactor runs a compaction of the store at every interval, until the context is cancelled.
+func (s *File) compactor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := s.compact(ctx, time.Now())
+			if err != nil {
+				s.logger.Warnf("[File] compaction of %s failed: %v", s.path, err)
+			}
+
+			if result.removed() > 0 {
+				s.logger.Infof("[File] compaction of %s removed %d tmp files, %d orphaned files and %d empty directories, reclaimed %d bytes",
+					s.path, result.tmpFiles, result.orphanFiles, result.directories, result.reclaimedBytes)
+			}
+		}
+	}
+}
+
+// compact reclaims the space left behind by deleted blobs and failed writes. Blobs are removed as soon as they are
+// deleted or expire, but a write that fails or is interrupted leaves its temporary file behind, a crash while
+// removing a blob can leave its checksum or DAH file behind, and the hash prefix directories of deleted blobs are
+// never removed. Compaction removes these leftovers when they are older than compactionMinAge.
+//
+// Every removal is protected by the write semaphore, like all other file operations of the store. Directories are
+// removed while holding dirMu, which writes hold while they create the directory of a blob and its first file.
+//
+// Parameters:
+//   - ctx: Context to cancel the compaction
+//   - now: The time the age of the files and directories is compared to
+//
+// Returns:
+//   - compactionResult: What was removed, also when the compaction was cancelled halfway
+//   - error: Any error that stopped the compaction
+func (s *File) compact(ctx context.Context, now time.Time) (compactionResult, error) {
+	start := time.Now()
+
+	var (
+		result   compactionResult
+		dirs     []os.FileInfo
+		dirPaths []string
+		examined int
+	)
+
+	// the walk itself is not semaphore protected, see findFilesByExtension
+	err := filepath.WalkDir(s.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// removed while walking
+				return nil
+			}
+
+			return err
+		}
+
+		examined++
+		if examined%compactionPauseEvery == 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(compactionPause):
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if d.IsDir() {
+			if path == s.path {
+				return nil
+			}
+
+			// the age of a directory is taken before its leftover files are removed, which updates its modification time
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+
+			dirs = append(dirs, info)
+			dirPaths = append(dirPaths, path)
+
+			return nil
+		}
+
+		s.compactFile(ctx, path, now, &result)
+
+		return nil
+	})
+
+	// directories are walked in lexical order, remove them in reverse so subdirectories are removed before their
+	// parent is checked
+	for i := len(dirs) - 1; i >= 0 && err == nil && ctx.Err() == nil; i-- {
+		s.compactDir(ctx, dirPaths[i], dirs[i], now, &result)
+	}
+
+	initPrometheusMetrics()
+	prometheusFileCompactionDuration.Observe(time.Since(start).Seconds())
+	prometheusFileCompactionReclaimedBytes.Add(float64(result.reclaimedBytes))
+	prometheusFileCompactionRemoved.WithLabelValues("tmp").Add(float64(result.tmpFiles))
+	prometheusFileCompactionRemoved.WithLabelValues("orphan").Add(float64(result.orphanFiles))
+	prometheusFileCompactionRemoved.WithLabelValues("directory").Add(float64(result.directories))
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return result, errors.NewContextCanceledError("[File] compaction of %s cancelled", s.path, err)
+		}
+
+		return result, errors.NewStorageError("[File] failed to compact %s", s.path, err)
+	}
+
+	return result, nil
+}
+
+// compactFile removes the file when it is a leftover temporary file, or a checksum or DAH file of a blob that no
+// longer exists.
+func (s *File) compactFile(ctx context.Context, path string, now time.Time, result *compactionResult) {
+	var blobPath string
+
+	switch {
+	case strings.HasSuffix(path, ".tmp"):
+	case strings.HasSuffix(path, checksumExtension):
+		blobPath = strings.TrimSuffix(path, checksumExtension)
+	case strings.HasSuffix(path, ".dah"):
+		blobPath = strings.TrimSuffix(path, ".dah")
+	default:
+		return
+	}
+
+	info, ok := s.statForCompaction(ctx, path, blobPath)
+	if !ok || now.Sub(info.ModTime()) < compactionMinAge {
+		return
+	}
+
+	if !s.removeForCompaction(ctx, path) {
+		return
+	}
+
+	if blobPath == "" {
+		result.tmpFiles++
+	} else {
+		result.orphanFiles++
+
+		if strings.HasSuffix(path, ".dah") {
+			s.removeDAHFromMap(blobPath)
+		}
+	}
+
+	result.reclaimedBytes += info.Size()
+}
+
+// statForCompaction returns the file info of the file, and whether it is a compaction candidate. A checksum or
+// DAH file is only a candidate when its blob does not exist.
+func (s *File) statForCompaction(ctx context.Context, path string, blobPath string) (os.FileInfo, bool) {
+	if err := acquireReadPermit(ctx); err != nil {
+		return nil, false
+	}
+	defer releaseReadPermit()
+
+	if blobPath != "" {
+		if _, err := os.Stat(blobPath); !errors.Is(err, os.ErrNotExist) {
+			return nil, false
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return info, true
+}
+
+// compactDir removes the directory when it is empty. The base directory and the configured subdirectories are
+// never removed.
+func (s *File) compactDir(ctx context.Context, path string, info os.FileInfo, now time.Time, result *compactionResult) {
+	if path == filepath.Join(s.path, s.options.SubDirectory) || (s.persistSubDir != "" && path == filepath.Join(s.path, s.persistSubDir)) {
+		return
+	}
+
+	if now.Sub(info.ModTime()) < compactionMinAge {
+		return
+	}
+
+	if err := acquireWritePermit(ctx); err != nil {
+		return
+	}
+	defer releaseWritePermit()
+
+	// writes hold dirMu for reading from creating the directory until their file exists in it, so the directory
+	// cannot be recreated and written to between checking that it is empty and removing it
+	s.dirMu.Lock()
+	defer s.dirMu.Unlock()
+
+	entries, err := os.ReadDir(path)
+	if err != nil || len(entries) > 0 {
+		return
+	}
+
+	if err = os.Remove(path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warnf("[File] compaction failed to remove %s: %v", path, err)
+		}
+
+		return
+	}
+
+	result.directories++
+	result.reclaimedBytes += info.Size()
+}
+
+// removeForCompaction removes the file, protected by the write semaphore.
+func (s *File) removeForCompaction(ctx context.Context, path string) bool {
+	if err := acquireWritePermit(ctx); err != nil {
+		return false
+	}
+	defer releaseWritePermit()
+
+	if err := os.Remove(path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warnf("[File] compaction failed to remove %s: %v", path, err)
+		}
+
+		return false
+	}
+
+	return true
+}
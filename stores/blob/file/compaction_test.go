@@ -0,0 +1,201 @@
+package file
+
+import (
+	"context"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/pkg/fileformat"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCompaction(t *testing.T) {
+	// storeSize returns the total size of the files and directories in the store
+	storeSize := func(t *testing.T, root string) int64 {
+		var size int64
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+
+			if path == root {
+				return nil
+			}
+
+			info, err := d.Info()
+			require.NoError(t, err)
+
+			size += info.Size()
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		return size
+	}
+
+	// age sets the modification time of all files and directories in the store to an hour ago
+	age := func(t *testing.T, root string) {
+		old := time.Now().Add(-time.Hour)
+
+		err := filepath.WalkDir(root, func(path string, _ fs.DirEntry, err error) error {
+			require.NoError(t, err)
+
+			return os.Chtimes(path, old, old)
+		})
+		require.NoError(t, err)
+	}
+
+	tempDir := t.TempDir()
+
+	storeURL, err := url.Parse("file://" + tempDir + "?hashPrefix=2")
+	require.NoError(t, err)
+
+	f, err := New(ulogger.TestLogger{}, storeURL)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = f.Close(context.Background())
+	}()
+
+	ctx := context.Background()
+	data := make([]byte, 1024)
+
+	keys := make([][]byte, 0, 20)
+	for i := 0; i < 20; i++ {
+		key := chainhash.HashB([]byte{byte(i)})
+		keys = append(keys, key)
+
+		require.NoError(t, f.Set(ctx, key, fileformat.FileTypeTesting, data))
+	}
+
+	// a write that failed halfway and a crash while removing a blob leave files behind
+	keptFilename, err := f.constructFilename(keys[0], fileformat.FileTypeTesting, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(keptFilename+".1234.tmp", make([]byte, 4096), 0600))
+
+	deletedFilename, err := f.constructFilename(keys[1], fileformat.FileTypeTesting, nil)
+	require.NoError(t, err)
+
+	// delete all blobs except the first
+	for _, key := range keys[1:] {
+		require.NoError(t, f.Del(ctx, key, fileformat.FileTypeTesting))
+	}
+
+	require.NoError(t, os.WriteFile(deletedFilename+checksumExtension, make([]byte, 64), 0600))
+
+	age(t, tempDir)
+
+	// a write in progress must not be touched
+	require.NoError(t, os.WriteFile(keptFilename+".5678.tmp", make([]byte, 4096), 0600))
+
+	sizeBefore := storeSize(t, tempDir)
+
+	result, err := f.compact(ctx, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.tmpFiles)
+	assert.Equal(t, 1, result.orphanFiles)
+	assert.Positive(t, result.directories)
+	assert.Positive(t, result.reclaimedBytes)
+
+	sizeAfter := storeSize(t, tempDir)
+	assert.Less(t, sizeAfter, sizeBefore)
+
+	assert.NoFileExists(t, keptFilename+".1234.tmp")
+	assert.NoFileExists(t, deletedFilename+checksumExtension)
+	assert.NoDirExists(t, filepath.Dir(deletedFilename))
+	assert.FileExists(t, keptFilename+".5678.tmp")
+
+	// the remaining blob is untouched
+	value, err := f.Get(ctx, keys[0], fileformat.FileTypeTesting)
+	require.NoError(t, err)
+	assert.Equal(t, data, value)
+	assert.FileExists(t, keptFilename+checksumExtension)
+
+	// nothing is left to compact
+	result, err = f.compact(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.removed())
+}
+
+func TestFileCompactionConcurrentWrites(t *testing.T) {
+	storeURL, err := url.Parse("file://" + t.TempDir() + "?hashPrefix=2")
+	require.NoError(t, err)
+
+	f, err := New(ulogger.TestLogger{}, storeURL)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = f.Close(context.Background())
+	}()
+
+	ctx := context.Background()
+
+	for i := 0; i < 200; i++ {
+		key := chainhash.HashB([]byte{byte(i), byte(i >> 8)})
+
+		// an empty hash prefix directory, old enough to be removed
+		filename, err := f.constructFilename(key, fileformat.FileTypeTesting, nil)
+		require.NoError(t, err)
+
+		info, err := os.Stat(filepath.Dir(filename))
+		require.NoError(t, err)
+
+		var (
+			wg     sync.WaitGroup
+			setErr error
+		)
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			setErr = f.Set(ctx, key, fileformat.FileTypeTesting, []byte("data"))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			var result compactionResult
+			f.compactDir(ctx, filepath.Dir(filename), info, time.Now().Add(time.Hour), &result)
+		}()
+
+		wg.Wait()
+
+		// the directory is either removed before the write creates it again, or kept because the write is in it
+		require.NoError(t, setErr)
+
+		exists, err := f.Exists(ctx, key, fileformat.FileTypeTesting)
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+}
+
+func TestFileCompactionInterval(t *testing.T) {
+	t.Run("invalid interval", func(t *testing.T) {
+		storeURL, err := url.Parse("file://" + t.TempDir() + "?compactionInterval=often")
+		require.NoError(t, err)
+
+		_, err = New(ulogger.TestLogger{}, storeURL)
+		require.Error(t, err)
+	})
+
+	t.Run("valid interval", func(t *testing.T) {
+		storeURL, err := url.Parse("file://" + t.TempDir() + "?compactionInterval=1h")
+		require.NoError(t, err)
+
+		f, err := New(ulogger.TestLogger{}, storeURL)
+		require.NoError(t, err)
+		require.NoError(t, f.Close(context.Background()))
+	})
+}
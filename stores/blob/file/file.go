@@ -43,6 +43,7 @@ import (
 	"github.com/bsv-blockchain/teranode/errors"
 	"github.com/bsv-blockchain/teranode/pkg/fileformat"
 	"github.com/bsv-blockchain/teranode/stores/blob/options"
+	"github.com/bsv-blockchain/teranode/stores/schemaversion"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/ordishs/go-utils"
 	"golang.org/x/sync/semaphore"
@@ -50,6 +51,10 @@ import (
 
 const checksumExtension = ".sha256"
 
+// SchemaVersion is the version of the on-disk format of the file blob store.
+// It must be incremented whenever a change is made to the format that requires existing stores to be upgraded.
+const SchemaVersion uint32 = 1
+
 // File implements the blob.Store interface using the local filesystem for storage.
 // It provides a robust, persistent blob storage solution with features like automatic
 // cleanup of expired blobs, data integrity verification, and efficient handling of
@@ -87,6 +92,9 @@ type File struct {
 	// longtermClient is an optional secondary storage backend for hybrid storage models
 	longtermClient longtermStore
 	cleanupCh      chan struct{}
+	// dirMu is held for reading by writes from creating the directory of a blob until its temporary file exists,
+	// and for writing by compaction while it removes an empty directory
+	dirMu sync.RWMutex
 }
 
 // longtermStore defines the interface for a secondary storage backend that can be used
@@ -320,6 +328,8 @@ func releaseWritePermit() {
 // - header: Custom header to prepend to blobs (can be hex-encoded or plain text)
 // - eofmarker: Custom footer marker to append to blobs (can be hex-encoded or plain text)
 // - checksum: When set to "true", enables SHA256 checksumming of blobs
+// - skipSchemaVersionCheck: When set to "true", skips the startup validation of the schema version
+// - compactionInterval: Interval of the background compaction of leftover files and empty directories (e.g. "1h")
 //
 // Parameters:
 //   - logger: Logger instance for recording operations and errors
@@ -358,6 +368,12 @@ func newStore(logger ulogger.Logger, storeURL *url.URL, opts ...options.StoreOpt
 		if err := os.MkdirAll(path, 0755); err != nil {
 			return nil, errors.NewStorageError("[File] failed to create directory", err)
 		}
+
+		if !schemaversion.SkipCheck(storeURL) {
+			if err := schemaversion.CheckFile(path, "blob", SchemaVersion); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	options := options.NewStoreOptions(opts...)
@@ -380,6 +396,17 @@ func newStore(logger ulogger.Logger, storeURL *url.URL, opts ...options.StoreOpt
 		options.HashPrefix = -int(val)
 	}
 
+	var compactionInterval time.Duration
+
+	if interval := storeURL.Query().Get("compactionInterval"); len(interval) > 0 {
+		val, err := time.ParseDuration(interval)
+		if err != nil {
+			return nil, errors.NewStorageError("[File] failed to parse compactionInterval", err)
+		}
+
+		compactionInterval = val
+	}
+
 	if len(options.SubDirectory) > 0 {
 		if err := os.MkdirAll(filepath.Join(path, options.SubDirectory), 0755); err != nil {
 			return nil, errors.NewStorageError("[File] failed to create sub directory", err)
@@ -437,6 +464,13 @@ func newStore(logger ulogger.Logger, storeURL *url.URL, opts ...options.StoreOpt
 				fileStore.logger.Warnf("[File] failed to load dahs: %v", err)
 			}
 		}()
+
+		// compact the store in the background, once per path like the loading of the dah's
+		if compactionInterval > 0 {
+			initPrometheusMetrics()
+
+			go fileStore.compactor(fileDAHsCtx, compactionInterval)
+		}
 	}
 
 	// start the dah cleaner
@@ -926,27 +960,36 @@ func (s *File) SetFromReader(ctx context.Context, key []byte, fileType fileforma
 	}
 	defer releaseWritePermit()
 
+	// compaction must not remove the directory of the blob before the temporary file has been created in it
+	s.dirMu.RLock()
+
 	filename, err := s.constructFilename(key, fileType, opts)
 	if err != nil {
+		s.dirMu.RUnlock()
 		return errors.NewStorageError("[File][SetFromReader] [%s] failed to get file name", utils.ReverseAndHexEncodeSlice(key), err)
 	}
 
 	merged := options.MergeOptions(s.options, opts)
 
 	if err := s.errorOnOverwrite(filename, merged); err != nil {
+		s.dirMu.RUnlock()
 		return err
 	}
 
 	// Generate a cryptographically secure random number
 	randNum, err := rand.Int(rand.Reader, big.NewInt(1<<63-1))
 	if err != nil {
+		s.dirMu.RUnlock()
 		return errors.NewStorageError("[File][SetFromReader] failed to generate random number", err)
 	}
 
 	tmpFilename := fmt.Sprintf("%s.%d.tmp", filename, randNum)
 
-	// Create the file first
+	// Create the file first, once it exists the directory is no longer empty and is not removed by compaction
 	file, err := os.Create(tmpFilename)
+
+	s.dirMu.RUnlock()
+
 	if err != nil {
 		return errors.NewStorageError("[File][SetFromReader] [%s] failed to create file", filename, err)
 	}
@@ -0,0 +1,58 @@
+package file
+
+import (
+	"sync"
+
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Total number of bytes reclaimed by compaction of the file store
+	prometheusFileCompactionReclaimedBytes prometheus.Counter
+	// Total number of files and directories removed by compaction of the file store
+	prometheusFileCompactionRemoved *prometheus.CounterVec
+	// Duration of a compaction run of the file store
+	prometheusFileCompactionDuration prometheus.Histogram
+)
+
+var prometheusMetricsInitOnce sync.Once
+
+// initPrometheusMetrics initializes the Prometheus metrics of the file store exactly once.
+func initPrometheusMetrics() {
+	prometheusMetricsInitOnce.Do(_initPrometheusMetrics)
+}
+
+func _initPrometheusMetrics() {
+	prometheusFileCompactionReclaimedBytes = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "blob_file",
+			Name:      "compaction_reclaimed_bytes",
+			Help:      "Number of bytes reclaimed by compaction of the file blob store",
+		},
+	)
+
+	prometheusFileCompactionRemoved = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "blob_file",
+			Name:      "compaction_removed",
+			Help:      "Number of leftover files and empty directories removed by compaction of the file blob store",
+		},
+		[]string{
+			"kind", // tmp, orphan or directory
+		},
+	)
+
+	prometheusFileCompactionDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "blob_file",
+			Name:      "compaction_duration_seconds",
+			Help:      "Duration of a compaction run of the file blob store",
+			Buckets:   util.MetricsBucketsSeconds,
+		},
+	)
+}
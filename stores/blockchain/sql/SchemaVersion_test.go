@@ -0,0 +1,43 @@
+package sql
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchemaVersionMismatch(t *testing.T) {
+	tSettings := settings.NewSettings()
+	tSettings.DataFolder = t.TempDir()
+
+	storeURL, err := url.Parse("sqlite:///blockchain_schema_version")
+	require.NoError(t, err)
+
+	store, err := New(ulogger.TestLogger{}, storeURL, tSettings)
+	require.NoError(t, err)
+
+	var version uint32
+
+	require.NoError(t, store.db.QueryRow(`SELECT version FROM store_schema_version WHERE store_name = 'blockchain'`).Scan(&version))
+	assert.Equal(t, SchemaVersion, version)
+
+	_, err = store.db.Exec(`UPDATE store_schema_version SET version = $1 WHERE store_name = 'blockchain'`, SchemaVersion+1)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Close())
+
+	_, err = New(ulogger.TestLogger{}, storeURL, tSettings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "blockchain store schema version 2 is newer than version 1")
+
+	skipURL, err := url.Parse("sqlite:///blockchain_schema_version?skipSchemaVersionCheck=true")
+	require.NoError(t, err)
+
+	store, err = New(ulogger.TestLogger{}, skipURL, tSettings)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/bsv-blockchain/teranode/model"
 	"github.com/bsv-blockchain/teranode/settings"
 	"github.com/bsv-blockchain/teranode/stores/blockchain/options"
+	"github.com/bsv-blockchain/teranode/stores/schemaversion"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util"
 	"github.com/bsv-blockchain/teranode/util/usql"
@@ -41,6 +42,10 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// SchemaVersion is the version of the database schema of the blockchain store.
+// It must be incremented whenever a change is made to the schema that requires existing stores to be upgraded.
+const SchemaVersion uint32 = 1
+
 // SQL implements the blockchain.Store interface using SQL database backends.
 // It provides a complete implementation of blockchain data storage and retrieval
 // operations with support for different SQL engines, caching mechanisms, and
@@ -139,6 +144,13 @@ func New(logger ulogger.Logger, storeURL *url.URL, tSettings *settings.Settings)
 		return nil, errors.NewStorageError("unknown database engine: %s", storeURL.Scheme)
 	}
 
+	if !schemaversion.SkipCheck(storeURL) {
+		if err = schemaversion.CheckSQL(context.Background(), db, "blockchain", SchemaVersion); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
 	s := &SQL{
 		db:            db,
 		engine:        util.SQLEngine(storeURL.Scheme),
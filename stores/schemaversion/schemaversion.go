@@ -0,0 +1,151 @@
+// Package schemaversion provides startup validation of the on-disk format version of the stores.
+//
+// Every store that persists data records the version of its storage format the first time it is
+// opened. On every subsequent start the recorded version is compared with the version expected by
+// the running binary, failing fast with a clear upgrade message when they do not match, instead of
+// running against data in a format the code does not understand.
+//
+// Stores that were created before versioning was introduced have no recorded version. These are
+// assumed to be in the current format, and the current version is recorded for them.
+//
+// The check can be disabled per store by adding skipSchemaVersionCheck=true to the store URL.
+package schemaversion
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	safeconversion "github.com/bsv-blockchain/go-safe-conversion"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/util/usql"
+)
+
+const (
+	// SkipCheckQueryParam is the store URL query parameter that disables the schema version check
+	SkipCheckQueryParam = "skipSchemaVersionCheck"
+
+	// FileName is the name of the file that holds the schema version of file based stores
+	FileName = ".schema_version"
+)
+
+// SkipCheck returns whether the schema version check has been disabled in the store URL.
+func SkipCheck(storeURL *url.URL) bool {
+	if storeURL == nil {
+		return false
+	}
+
+	return storeURL.Query().Get(SkipCheckQueryParam) == "true"
+}
+
+// NewMismatchError returns the error that is reported when the schema version of a store does not match
+// the version expected by this version of teranode.
+func NewMismatchError(storeName string, found, expected uint32) error {
+	if found > expected {
+		return errors.NewStorageError("%s store schema version %d is newer than version %d supported by this version of teranode, upgrade teranode or point it to a compatible store (add %s=true to the store URL to skip this check)",
+			storeName, found, expected, SkipCheckQueryParam)
+	}
+
+	return errors.NewStorageError("%s store schema version %d is older than version %d required by this version of teranode, the store must be upgraded or recreated before starting (add %s=true to the store URL to skip this check)",
+		storeName, found, expected, SkipCheckQueryParam)
+}
+
+// CheckSQL validates the schema version of a store recorded in the store_schema_version table of an SQL
+// database, recording the expected version if none has been recorded yet. The versions are recorded per store
+// name, so stores sharing a database are versioned independently.
+//
+// Parameters:
+//   - ctx: Context for the database operations
+//   - db: Database of the store, postgres or sqlite
+//   - storeName: Name of the store, the key of its version and used in the error message
+//   - expected: Schema version expected by this version of teranode
+//
+// Returns:
+//   - error: StorageError when the versions do not match or the version could not be read or written
+func CheckSQL(ctx context.Context, db *usql.DB, storeName string, expected uint32) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS store_schema_version (
+			store_name VARCHAR(64) NOT NULL PRIMARY KEY,
+			version    BIGINT NOT NULL
+		);
+	`); err != nil {
+		return errors.NewStorageError("%s store: could not create store_schema_version table", storeName, err)
+	}
+
+	var found int64
+
+	err := db.QueryRowContext(ctx, `SELECT version FROM store_schema_version WHERE store_name = $1`, storeName).Scan(&found)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return errors.NewStorageError("%s store: could not read schema version", storeName, err)
+		}
+
+		// another process opening the same store may record the version at the same time, both read it back
+		if _, err = db.ExecContext(ctx, `INSERT INTO store_schema_version (store_name, version) VALUES ($1, $2) ON CONFLICT (store_name) DO NOTHING`,
+			storeName, expected); err != nil {
+			return errors.NewStorageError("%s store: could not write schema version", storeName, err)
+		}
+
+		if err = db.QueryRowContext(ctx, `SELECT version FROM store_schema_version WHERE store_name = $1`, storeName).Scan(&found); err != nil {
+			return errors.NewStorageError("%s store: could not read schema version", storeName, err)
+		}
+	}
+
+	foundVersion, err := safeconversion.Int64ToUint32(found)
+	if err != nil {
+		return errors.NewStorageError("%s store: invalid schema version %d", storeName, found, err)
+	}
+
+	if foundVersion != expected {
+		return NewMismatchError(storeName, foundVersion, expected)
+	}
+
+	return nil
+}
+
+// CheckFile validates the schema version recorded in the schema version file in the given directory,
+// writing the expected version if the file does not exist yet.
+//
+// Parameters:
+//   - dir: Root directory of the store
+//   - storeName: Name of the store, used in the error message
+//   - expected: Schema version expected by this version of teranode
+//
+// Returns:
+//   - error: StorageError when the versions do not match or the version file could not be read or written
+func CheckFile(dir string, storeName string, expected uint32) error {
+	fileName := filepath.Join(dir, FileName)
+
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return errors.NewStorageError("%s store: could not read schema version file %s", storeName, fileName, err)
+		}
+
+		if err = os.WriteFile(fileName, []byte(strconv.FormatUint(uint64(expected), 10)), 0600); err != nil {
+			return errors.NewStorageError("%s store: could not write schema version file %s", storeName, fileName, err)
+		}
+
+		return nil
+	}
+
+	found, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 32)
+	if err != nil {
+		return errors.NewStorageError("%s store: invalid schema version in %s", storeName, fileName, err)
+	}
+
+	foundVersion, err := safeconversion.Uint64ToUint32(found)
+	if err != nil {
+		return errors.NewStorageError("%s store: invalid schema version in %s", storeName, fileName, err)
+	}
+
+	if foundVersion != expected {
+		return NewMismatchError(storeName, foundVersion, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package schemaversion
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipCheck(t *testing.T) {
+	t.Run("nil url", func(t *testing.T) {
+		assert.False(t, SkipCheck(nil))
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		storeURL, err := url.Parse("file:///data/blobs")
+		require.NoError(t, err)
+
+		assert.False(t, SkipCheck(storeURL))
+	})
+
+	t.Run("set", func(t *testing.T) {
+		storeURL, err := url.Parse("file:///data/blobs?skipSchemaVersionCheck=true")
+		require.NoError(t, err)
+
+		assert.True(t, SkipCheck(storeURL))
+	})
+}
+
+func TestCheckFile(t *testing.T) {
+	t.Run("records version of new store", func(t *testing.T) {
+		dir := t.TempDir()
+
+		require.NoError(t, CheckFile(dir, "blob", 1))
+
+		b, err := os.ReadFile(filepath.Join(dir, FileName))
+		require.NoError(t, err)
+		assert.Equal(t, "1", string(b))
+
+		// a second start with the same version succeeds
+		require.NoError(t, CheckFile(dir, "blob", 1))
+	})
+
+	t.Run("mismatched version", func(t *testing.T) {
+		dir := t.TempDir()
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("2\n"), 0600))
+
+		err := CheckFile(dir, "blob", 1)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrStorageError))
+		assert.Contains(t, err.Error(), "blob store schema version 2 is newer than version 1")
+
+		err = CheckFile(dir, "blob", 3)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "blob store schema version 2 is older than version 3")
+		assert.Contains(t, err.Error(), "must be upgraded")
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		dir := t.TempDir()
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("abc"), 0600))
+
+		err := CheckFile(dir, "blob", 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid schema version")
+	})
+}
+
+func TestCheckSQL(t *testing.T) {
+	storeURL, err := url.Parse("sqlitememory:///schemaversion")
+	require.NoError(t, err)
+
+	db, err := util.InitSQLDB(ulogger.TestLogger{}, storeURL, settings.NewSettings())
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	ctx := context.Background()
+
+	// records the version of a new store
+	require.NoError(t, CheckSQL(ctx, db, "utxo", 1))
+
+	var version int64
+
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT version FROM store_schema_version WHERE store_name = 'utxo'`).Scan(&version))
+	assert.Equal(t, int64(1), version)
+
+	// a second start with the same version succeeds, without recording the version again
+	require.NoError(t, CheckSQL(ctx, db, "utxo", 1))
+
+	var count int
+
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM store_schema_version`).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// another store in the same database is versioned independently
+	require.NoError(t, CheckSQL(ctx, db, "blockchain", 3))
+	require.NoError(t, CheckSQL(ctx, db, "utxo", 1))
+
+	// a mismatched version fails with an upgrade message
+	_, err = db.ExecContext(ctx, `UPDATE store_schema_version SET version = 2 WHERE store_name = 'utxo'`)
+	require.NoError(t, err)
+
+	err = CheckSQL(ctx, db, "utxo", 1)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrStorageError))
+	assert.Contains(t, err.Error(), "utxo store schema version 2 is newer than version 1")
+
+	require.NoError(t, CheckSQL(ctx, db, "blockchain", 3))
+}
@@ -76,6 +76,7 @@ import (
 	"github.com/bsv-blockchain/teranode/settings"
 	"github.com/bsv-blockchain/teranode/stores/blob"
 	"github.com/bsv-blockchain/teranode/stores/blob/options"
+	"github.com/bsv-blockchain/teranode/stores/schemaversion"
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/bsv-blockchain/teranode/stores/utxo/aerospike/cleanup"
 	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
@@ -140,6 +141,7 @@ type Store struct {
 // The URL format is: aerospike://host:port/namespace?set=setname&
 // URL parameters:
 //   - set: Aerospike set name (default: txmeta)
+//   - skipSchemaVersionCheck: Skip the startup validation of the schema version
 //   - or blob storage of large transactions
 func New(ctx context.Context, logger ulogger.Logger, tSettings *settings.Settings, aerospikeURL *url.URL) (*Store, error) {
 	InitPrometheusMetrics()
@@ -204,6 +206,12 @@ func New(ctx context.Context, logger ulogger.Logger, tSettings *settings.Setting
 		externalTxCache: externalTxCache,
 	}
 
+	if !schemaversion.SkipCheck(aerospikeURL) {
+		if err = s.checkSchemaVersion(SchemaVersion); err != nil {
+			return nil, err
+		}
+	}
+
 	// Ensure index creation/wait is only done once per process
 	if cleanup.IndexName != "" {
 		s.indexOnce.Do(func() {
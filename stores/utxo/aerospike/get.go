@@ -94,9 +94,10 @@ type batchGetItemData struct {
 
 // batchGetItem represents a single item in a batch get operation
 type batchGetItem struct {
-	hash   chainhash.Hash        // Transaction hash
-	fields []fields.FieldName    // Fields to retrieve
-	done   chan batchGetItemData // Channel for result
+	hash        chainhash.Hash        // Transaction hash
+	fields      []fields.FieldName    // Fields to retrieve
+	consistency utxo.ReadConsistency  // Requested read consistency
+	done        chan batchGetItemData // Channel for result
 }
 
 // batchOutpoint represents a single outpoint in a batch previous output operation.
@@ -130,7 +131,7 @@ type batchOutpoint struct {
 //   - UTXO hash matches the expected value
 //   - Frozen status for compliance operations
 //   - Current spend state and spending transaction details
-func (s *Store) GetSpend(_ context.Context, spend *utxo.Spend) (*utxo.SpendResponse, error) {
+func (s *Store) GetSpend(ctx context.Context, spend *utxo.Spend) (*utxo.SpendResponse, error) {
 	prometheusUtxoMapGet.Inc()
 
 	keySource := uaerospike.CalculateKeySource(spend.TxID, spend.Vout, s.utxoBatchSize)
@@ -150,7 +151,7 @@ func (s *Store) GetSpend(_ context.Context, spend *utxo.Spend) (*utxo.SpendRespo
 	policy := util.GetAerospikeReadPolicy(s.settings)
 	// we only want to read from the master for tx metadata, due to blockIDs being updated
 	// however we still want to read from the replica for the utxos in case of aerospike failures
-	policy.ReplicaPolicy = aerospike.SEQUENCE
+	policy.ReplicaPolicy = readReplicaPolicyWithMetrics(ctx)
 
 	value, aErr := s.client.Get(policy, key, fields.FieldNamesToStrings(binNames)...)
 	if aErr != nil {
@@ -322,7 +323,7 @@ func (s *Store) GetMeta(ctx context.Context, hash *chainhash.Hash) (*meta.Data,
 // collected and processed together in a single database operation.
 //
 // Parameters:
-//   - ctx: Context for cancellation (currently unused but kept for interface consistency)
+//   - ctx: Context carrying the requested read consistency
 //   - hash: Transaction hash to retrieve data for
 //   - bins: Field names to retrieve from the database (specific Aerospike bins)
 //
@@ -333,9 +334,9 @@ func (s *Store) GetMeta(ctx context.Context, hash *chainhash.Hash) (*meta.Data,
 // Implementation Details:
 // The method creates a batchGetItem with the request parameters and sends it to the
 // getBatcher for processing. It then waits on a done channel for the result.
-func (s *Store) get(_ context.Context, hash *chainhash.Hash, bins []fields.FieldName) (*meta.Data, error) {
+func (s *Store) get(ctx context.Context, hash *chainhash.Hash, bins []fields.FieldName) (*meta.Data, error) {
 	done := make(chan batchGetItemData)
-	item := &batchGetItem{hash: *hash, fields: bins, done: done}
+	item := &batchGetItem{hash: *hash, fields: bins, consistency: utxo.ReadConsistencyFromContext(ctx), done: done}
 
 	if s.getBatcher != nil {
 		s.getBatcher.Put(item)
@@ -521,7 +522,7 @@ func (s *Store) BatchDecorate(ctx context.Context, items []*utxo.UnresolvedMetaD
 	batchPolicy := util.GetAerospikeBatchPolicy(s.settings)
 	// we only want to read from the master for tx metadata, due to blockIDs being updated
 	// however we still want to read from the replica for the utxos in case of aerospike failures
-	batchPolicy.ReplicaPolicy = aerospike.SEQUENCE
+	batchPolicy.ReplicaPolicy = readReplicaPolicyWithMetrics(ctx)
 
 	policy := util.GetAerospikeBatchReadPolicy(s.settings)
 
@@ -1380,10 +1381,13 @@ func (s *Store) sendGetBatch(batch []*batchGetItem) {
 		})
 	}
 
+	// the batch can only be read with eventual consistency if none of the callers requested strong consistency
+	ctx := utxo.WithReadConsistency(s.ctx, batchReadConsistency(batch))
+
 	retries := 0
 
 	for {
-		if err := s.BatchDecorate(s.ctx, items); err != nil {
+		if err := s.BatchDecorate(ctx, items); err != nil {
 			if retries < 3 {
 				retries++
 
@@ -1432,3 +1436,38 @@ func sendErrorAndClose(errCh chan error, err error) {
 	}
 	close(errCh)
 }
+
+// batchReadConsistency returns the consistency level that satisfies all items in the batch.
+// Strong consistency is used as soon as a single item requests it.
+func batchReadConsistency(batch []*batchGetItem) utxo.ReadConsistency {
+	for _, item := range batch {
+		if item.consistency == utxo.ReadConsistencyStrong {
+			return utxo.ReadConsistencyStrong
+		}
+	}
+
+	if len(batch) == 0 {
+		return utxo.ReadConsistencyStrong
+	}
+
+	return utxo.ReadConsistencyEventual
+}
+
+// readReplicaPolicy returns the aerospike replica policy for the read consistency requested in the context.
+// Strong reads go to the master first and only fall back to a replica on failure, eventual reads are
+// distributed over the master and the replicas, which may return slightly stale data.
+func readReplicaPolicy(ctx context.Context) aerospike.ReplicaPolicy {
+	if utxo.ReadConsistencyFromContext(ctx) == utxo.ReadConsistencyEventual {
+		return aerospike.MASTER_PROLES
+	}
+
+	return aerospike.SEQUENCE
+}
+
+// readReplicaPolicyWithMetrics returns the aerospike replica policy for the read consistency requested in the
+// context and counts the read for that consistency.
+func readReplicaPolicyWithMetrics(ctx context.Context) aerospike.ReplicaPolicy {
+	prometheusUtxoReadConsistency.WithLabelValues(utxo.ReadConsistencyFromContext(ctx).String()).Inc()
+
+	return readReplicaPolicy(ctx)
+}
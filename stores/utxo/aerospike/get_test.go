@@ -25,11 +25,13 @@ import (
 	"github.com/bsv-blockchain/teranode/stores/blob/file"
 	"github.com/bsv-blockchain/teranode/stores/blob/memory"
 	"github.com/bsv-blockchain/teranode/stores/blockchain"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
 	teranode_aerospike "github.com/bsv-blockchain/teranode/stores/utxo/aerospike"
 	"github.com/bsv-blockchain/teranode/ulogger"
 	"github.com/bsv-blockchain/teranode/util"
 	"github.com/bsv-blockchain/teranode/util/test"
 	"github.com/ordishs/go-bitcoin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
@@ -399,3 +401,75 @@ func fetchTransaction(ctx context.Context, txStore blob.Store, b *bitcoin.Bitcoi
 
 	return tx, nil
 }
+
+// readConsistencyCount returns the number of reads done to aerospike with the given read consistency
+func readConsistencyCount(t *testing.T, consistency utxo.ReadConsistency) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "teranode_aerospike_utxo_read_consistency" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "consistency" && label.GetValue() == consistency.String() {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	return 0
+}
+
+func TestStore_GetReadConsistency(t *testing.T) {
+	logger := ulogger.NewErrorTestLogger(t)
+	tSettings := test.CreateBaseTestSettings(t)
+
+	client, store, ctx, deferFn := initAerospike(t, tSettings, logger)
+
+	t.Cleanup(func() {
+		deferFn()
+	})
+
+	cleanDB(t, client)
+
+	_, err := store.Create(ctx, tx, 0)
+	require.NoError(t, err)
+
+	t.Run("eventual get", func(t *testing.T) {
+		eventualBefore := readConsistencyCount(t, utxo.ReadConsistencyEventual)
+		strongBefore := readConsistencyCount(t, utxo.ReadConsistencyStrong)
+
+		txMeta, err := store.Get(utxo.WithReadConsistency(ctx, utxo.ReadConsistencyEventual), tx.TxIDChainHash())
+		require.NoError(t, err)
+		assert.Equal(t, tx.TxIDChainHash(), txMeta.Tx.TxIDChainHash())
+
+		assert.Equal(t, float64(1), readConsistencyCount(t, utxo.ReadConsistencyEventual)-eventualBefore)
+		assert.Equal(t, strongBefore, readConsistencyCount(t, utxo.ReadConsistencyStrong))
+	})
+
+	t.Run("strong get by default", func(t *testing.T) {
+		eventualBefore := readConsistencyCount(t, utxo.ReadConsistencyEventual)
+		strongBefore := readConsistencyCount(t, utxo.ReadConsistencyStrong)
+
+		txMeta, err := store.Get(ctx, tx.TxIDChainHash())
+		require.NoError(t, err)
+		assert.Equal(t, tx.TxIDChainHash(), txMeta.Tx.TxIDChainHash())
+
+		assert.Equal(t, float64(1), readConsistencyCount(t, utxo.ReadConsistencyStrong)-strongBefore)
+		assert.Equal(t, eventualBefore, readConsistencyCount(t, utxo.ReadConsistencyEventual))
+	})
+
+	t.Run("eventual get spend", func(t *testing.T) {
+		eventualBefore := readConsistencyCount(t, utxo.ReadConsistencyEventual)
+
+		spendResponse, err := store.GetSpend(utxo.WithReadConsistency(ctx, utxo.ReadConsistencyEventual), spend)
+		require.NoError(t, err)
+		assert.Equal(t, int(utxo.Status_OK), spendResponse.Status)
+
+		assert.Equal(t, float64(1), readConsistencyCount(t, utxo.ReadConsistencyEventual)-eventualBefore)
+	})
+}
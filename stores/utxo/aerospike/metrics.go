@@ -69,6 +69,8 @@ var (
 	prometheusUtxoMapDelete prometheus.Counter
 	prometheusUtxoMapErrors *prometheus.CounterVec
 
+	prometheusUtxoReadConsistency *prometheus.CounterVec
+
 	prometheusUtxoCreateBatch     prometheus.Histogram
 	prometheusUtxoCreateBatchSize prometheus.Histogram
 	prometheusUtxoSpendBatch      prometheus.Histogram
@@ -195,6 +197,18 @@ func _initPrometheusMetrics() {
 		},
 	)
 
+	prometheusUtxoReadConsistency = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "aerospike",
+			Name:      "utxo_read_consistency",
+			Help:      "Number of reads done to aerospike per requested read consistency",
+		},
+		[]string{
+			"consistency", // read consistency level
+		},
+	)
+
 	prometheusUtxoMapGet = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "teranode",
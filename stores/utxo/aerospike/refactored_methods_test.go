@@ -1,11 +1,14 @@
 package aerospike
 
 import (
+	"context"
 	"testing"
 
+	"github.com/aerospike/aerospike-client-go/v8"
 	"github.com/aerospike/aerospike-client-go/v8/types"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -123,3 +126,20 @@ func TestAerospikeResultCodes(t *testing.T) {
 	assert.NotEqual(t, types.TIMEOUT, 0)
 	assert.NotEqual(t, types.PARAMETER_ERROR, 0)
 }
+
+// Test the read consistency is mapped to the expected replica policy
+func TestReadReplicaPolicy(t *testing.T) {
+	assert.Equal(t, aerospike.SEQUENCE, readReplicaPolicy(context.Background()))
+	assert.Equal(t, aerospike.SEQUENCE, readReplicaPolicy(utxo.WithReadConsistency(context.Background(), utxo.ReadConsistencyStrong)))
+	assert.Equal(t, aerospike.MASTER_PROLES, readReplicaPolicy(utxo.WithReadConsistency(context.Background(), utxo.ReadConsistencyEventual)))
+}
+
+// Test a batch is only read with eventual consistency when all items allow it
+func TestBatchReadConsistency(t *testing.T) {
+	eventual := &batchGetItem{consistency: utxo.ReadConsistencyEventual}
+	strong := &batchGetItem{consistency: utxo.ReadConsistencyStrong}
+
+	assert.Equal(t, utxo.ReadConsistencyStrong, batchReadConsistency(nil))
+	assert.Equal(t, utxo.ReadConsistencyEventual, batchReadConsistency([]*batchGetItem{eventual, eventual}))
+	assert.Equal(t, utxo.ReadConsistencyStrong, batchReadConsistency([]*batchGetItem{eventual, strong, eventual}))
+}
@@ -0,0 +1,72 @@
+package aerospike
+
+import (
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	safeconversion "github.com/bsv-blockchain/go-safe-conversion"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/schemaversion"
+	"github.com/bsv-blockchain/teranode/util"
+)
+
+// SchemaVersion is the version of the record layout of the Aerospike UTXO store.
+// It must be incremented whenever a change is made to the layout that requires existing stores to be upgraded.
+const SchemaVersion uint32 = 1
+
+const (
+	// schemaVersionSet is the set holding the schema versions of the stores of a namespace, keyed by set name
+	schemaVersionSet = "schema_version"
+
+	// schemaVersionBin is the bin holding the schema version
+	schemaVersionBin = "version"
+)
+
+// checkSchemaVersion validates the schema version recorded for the set of the store, recording the expected version
+// if none has been recorded yet. The version is kept in the schema_version set of the namespace, in a record keyed by
+// the set name of the store, so stores sharing a namespace are versioned independently.
+func (s *Store) checkSchemaVersion(expected uint32) error {
+	key, err := aerospike.NewKey(s.namespace, schemaVersionSet, s.setName)
+	if err != nil {
+		return errors.NewStorageError("utxo store: could not create schema version key", err)
+	}
+
+	record, aErr := s.client.Get(util.GetAerospikeReadPolicy(s.settings), key, schemaVersionBin)
+	if aErr != nil {
+		if !aErr.Matches(types.KEY_NOT_FOUND_ERROR) {
+			return errors.NewStorageError("utxo store: could not read schema version", aErr)
+		}
+
+		writePolicy := util.GetAerospikeWritePolicy(s.settings, 0)
+		writePolicy.RecordExistsAction = aerospike.CREATE_ONLY
+
+		aErr = s.client.PutBins(writePolicy, key, aerospike.NewBin(schemaVersionBin, int64(expected)))
+		if aErr == nil {
+			return nil
+		}
+
+		// another process opening the same store recorded the version at the same time
+		if !aErr.Matches(types.KEY_EXISTS_ERROR) {
+			return errors.NewStorageError("utxo store: could not write schema version", aErr)
+		}
+
+		if record, aErr = s.client.Get(util.GetAerospikeReadPolicy(s.settings), key, schemaVersionBin); aErr != nil {
+			return errors.NewStorageError("utxo store: could not read schema version", aErr)
+		}
+	}
+
+	found, ok := record.Bins[schemaVersionBin].(int)
+	if !ok {
+		return errors.NewStorageError("utxo store: invalid schema version %v", record.Bins[schemaVersionBin])
+	}
+
+	foundVersion, err := safeconversion.IntToUint32(found)
+	if err != nil {
+		return errors.NewStorageError("utxo store: invalid schema version %d", found, err)
+	}
+
+	if foundVersion != expected {
+		return schemaversion.NewMismatchError("utxo", foundVersion, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package aerospike_test
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/bsv-blockchain/teranode/errors"
+	teranode_aerospike "github.com/bsv-blockchain/teranode/stores/utxo/aerospike"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util"
+	"github.com/bsv-blockchain/teranode/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	logger := ulogger.TestLogger{}
+	tSettings := test.CreateBaseTestSettings(t)
+
+	client, _, ctx, deferFn := initAerospike(t, tSettings, logger)
+	defer deferFn()
+
+	// the version of the store opened by initAerospike is recorded in a record keyed by its set name
+	key, err := aerospike.NewKey(aerospikeNamespace, "schema_version", aerospikeSet)
+	require.NoError(t, err)
+
+	record, err := client.Get(util.GetAerospikeReadPolicy(tSettings), key, "version")
+	require.NoError(t, err)
+	assert.Equal(t, int(teranode_aerospike.SchemaVersion), record.Bins["version"])
+
+	host := client.Cluster().GetNodes()[0].GetHost()
+
+	storeURL := func(t *testing.T, set string, query string) *url.URL {
+		storeURL, err := url.Parse(fmt.Sprintf("aerospike://%s:%d/%s?set=%s&externalStore=memory://%s", host.Name, host.Port, aerospikeNamespace, set, query))
+		require.NoError(t, err)
+
+		return storeURL
+	}
+
+	// a mismatched version fails with an upgrade message
+	require.NoError(t, client.PutBins(util.GetAerospikeWritePolicy(tSettings, 0), key, aerospike.NewBin("version", int64(teranode_aerospike.SchemaVersion+1))))
+
+	_, err = teranode_aerospike.New(ctx, logger, tSettings, storeURL(t, aerospikeSet, ""))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrStorageError))
+	assert.Contains(t, err.Error(), "is newer than version")
+
+	// the check can be skipped
+	_, err = teranode_aerospike.New(ctx, logger, tSettings, storeURL(t, aerospikeSet, "&skipSchemaVersionCheck=true"))
+	require.NoError(t, err)
+
+	// another set of the namespace is versioned independently
+	_, err = teranode_aerospike.New(ctx, logger, tSettings, storeURL(t, aerospikeSet+"-other", ""))
+	require.NoError(t, err)
+}
@@ -0,0 +1,55 @@
+package utxo
+
+import (
+	"context"
+)
+
+// ReadConsistency defines the consistency level requested for reads from the UTXO store.
+//
+// Some reads can tolerate slightly stale data, for instance when reporting statistics, while
+// validation always needs the latest state. Backends that support it can use the level to trade
+// consistency for performance, for instance by allowing reads from replicas. Backends that do not
+// support relaxed reads always read with strong consistency.
+type ReadConsistency int
+
+const (
+	// ReadConsistencyStrong reads the latest committed state of a record. This is the default.
+	ReadConsistencyStrong ReadConsistency = iota
+
+	// ReadConsistencyEventual allows the backend to return slightly stale data, for instance from a replica.
+	ReadConsistencyEventual
+)
+
+// String returns the name of the read consistency level.
+func (c ReadConsistency) String() string {
+	switch c {
+	case ReadConsistencyStrong:
+		return "strong"
+	case ReadConsistencyEventual:
+		return "eventual"
+	default:
+		return "unknown"
+	}
+}
+
+type readConsistencyKey struct{}
+
+// WithReadConsistency returns a copy of the context that requests the given consistency level
+// for all UTXO store reads that are made with it.
+func WithReadConsistency(ctx context.Context, consistency ReadConsistency) context.Context {
+	return context.WithValue(ctx, readConsistencyKey{}, consistency)
+}
+
+// ReadConsistencyFromContext returns the read consistency level requested in the context,
+// defaulting to ReadConsistencyStrong when none has been set.
+func ReadConsistencyFromContext(ctx context.Context) ReadConsistency {
+	if ctx == nil {
+		return ReadConsistencyStrong
+	}
+
+	if consistency, ok := ctx.Value(readConsistencyKey{}).(ReadConsistency); ok {
+		return consistency
+	}
+
+	return ReadConsistencyStrong
+}
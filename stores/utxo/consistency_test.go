@@ -0,0 +1,66 @@
+package utxo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// consistencyMockStore is a mock backend that serves stale data for eventual reads
+// and the latest data for strong reads.
+type consistencyMockStore struct {
+	MockUtxostore
+	stale  *meta.Data
+	latest *meta.Data
+}
+
+func (s *consistencyMockStore) Get(ctx context.Context, _ *chainhash.Hash, _ ...fields.FieldName) (*meta.Data, error) {
+	if ReadConsistencyFromContext(ctx) == ReadConsistencyEventual {
+		return s.stale, nil
+	}
+
+	return s.latest, nil
+}
+
+func TestReadConsistencyFromContext(t *testing.T) {
+	t.Run("defaults to strong", func(t *testing.T) {
+		assert.Equal(t, ReadConsistencyStrong, ReadConsistencyFromContext(context.Background()))
+	})
+
+	t.Run("returns requested level", func(t *testing.T) {
+		ctx := WithReadConsistency(context.Background(), ReadConsistencyEventual)
+		assert.Equal(t, ReadConsistencyEventual, ReadConsistencyFromContext(ctx))
+
+		// a nested context can request a stronger level again
+		ctx = WithReadConsistency(ctx, ReadConsistencyStrong)
+		assert.Equal(t, ReadConsistencyStrong, ReadConsistencyFromContext(ctx))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		assert.Equal(t, "strong", ReadConsistencyStrong.String())
+		assert.Equal(t, "eventual", ReadConsistencyEventual.String())
+		assert.Equal(t, "unknown", ReadConsistency(99).String())
+	})
+}
+
+func TestReadConsistency_HonoredByBackend(t *testing.T) {
+	store := &consistencyMockStore{
+		stale:  &meta.Data{BlockIDs: []uint32{}},
+		latest: &meta.Data{BlockIDs: []uint32{1}},
+	}
+
+	var s Store = store
+
+	data, err := s.Get(context.Background(), &chainhash.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, store.latest, data)
+
+	data, err = s.Get(WithReadConsistency(context.Background(), ReadConsistencyEventual), &chainhash.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, store.stale, data)
+}
@@ -31,6 +31,7 @@
 // The factory provides:
 //   - Automatic database connection management
 //   - Optional logging via URL query parameter "logging=true"
+//   - Automatic reconnection when the connection to the database drops
 //   - Automatic block height updates via blockchain subscription
 //   - Graceful shutdown handling
 //
@@ -73,6 +74,7 @@ import (
 	"github.com/bsv-blockchain/teranode/settings"
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	storelogger "github.com/bsv-blockchain/teranode/stores/utxo/logger"
+	"github.com/bsv-blockchain/teranode/stores/utxo/reconnect"
 	"github.com/bsv-blockchain/teranode/ulogger"
 )
 
@@ -108,13 +110,26 @@ func NewStore(ctx context.Context, logger ulogger.Logger, tSettings *settings.Se
 
 		logger.Infof("[UTXOStore] connecting to %s service at %s:%d", storeURL.Scheme, storeURL.Hostname(), port)
 
-		utxoStore, err = dbInit(ctx, logger, tSettings, storeURL)
+		connect := func(ctx context.Context) (utxo.Store, error) {
+			store, err := dbInit(ctx, logger, tSettings, storeURL)
+			if err != nil {
+				return nil, err
+			}
+
+			if storeURL.Query().Get("logging") == "true" {
+				store = storelogger.New(ctx, logger, store)
+			}
+
+			return store, nil
+		}
+
+		utxoStore, err = connect(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		if storeURL.Query().Get("logging") == "true" {
-			utxoStore = storelogger.New(ctx, logger, utxoStore)
+		if tSettings.UtxoStore.ReconnectProbeInterval > 0 {
+			utxoStore = reconnect.New(ctx, logger, utxoStore, connect, tSettings.UtxoStore.ReconnectProbeInterval, tSettings.UtxoStore.ReconnectMaxBackoff)
 		}
 
 		startBlockchain := true
@@ -15,6 +15,7 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -324,3 +325,16 @@ func (s *Store) ProcessExpiredPreservations(ctx context.Context, currentHeight u
 
 	return err
 }
+
+// Close closes the wrapped store when it holds resources of its own, see io.Closer.
+func (s *Store) Close() error {
+	closer, ok := s.store.(io.Closer)
+	if !ok {
+		return nil
+	}
+
+	err := closer.Close()
+	s.logger.Debugf("[UTXOStore][logger][Close] err %v : %s", err, caller())
+
+	return err
+}
@@ -0,0 +1,464 @@
+// Package reconnect provides a UTXO store wrapper that reconnects to the store when its connection drops.
+//
+// The reconnect package implements a decorator pattern that wraps any utxo.Store implementation. The health of
+// the wrapped store is probed at a fixed interval. When a probe fails, the store is reported unhealthy, so the
+// readiness of the services using it flips, and the wrapper tries to recover the connection with a capped
+// exponential backoff: first by probing the existing store again, then by connecting a new store. A new store
+// replaces the existing one for all operations once it is healthy, so the node recovers from an outage of the
+// UTXO store without a restart. The replaced store is closed once the operations still running on it completed.
+package reconnect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/stores/cleanup"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/retry"
+)
+
+var _ cleanup.CleanupServiceProvider = (*Store)(nil)
+
+// ConnectFunc connects a new UTXO store to replace a store that lost its connection.
+type ConnectFunc func(ctx context.Context) (utxo.Store, error)
+
+// Store wraps a utxo.Store implementation, replacing it with a newly connected store when its connection drops.
+type Store struct {
+	logger        ulogger.Logger
+	connect       ConnectFunc
+	probeInterval time.Duration
+	maxBackoff    time.Duration
+
+	// store is the store all operations are delegated to, replaced on reconnection
+	store atomic.Pointer[storeHolder]
+
+	// healthy is false from a failed probe until the connection is recovered
+	healthy atomic.Bool
+
+	// blockStateMu protects the block height and median block time, which are set on a new store on reconnection
+	blockStateMu sync.Mutex
+}
+
+// storeHolder holds the current store, atomic.Pointer needs a concrete type
+type storeHolder struct {
+	utxo.Store
+
+	// inFlight is read locked by every operation running on the store, and write locked to close the store once
+	// it was replaced
+	inFlight sync.RWMutex
+}
+
+// New creates a new reconnecting wrapper around the provided UTXO store and starts probing its health in the
+// background until the context is cancelled.
+//
+// Parameters:
+//   - ctx: Context of the health probe, cancel it to stop probing
+//   - logger: Logger for connection loss and recovery
+//   - store: The connected UTXO store
+//   - connect: Connects a new UTXO store with the same configuration
+//   - probeInterval: Interval of the health probe, also the first wait between reconnection attempts
+//   - maxBackoff: Max wait between two reconnection attempts
+//
+// Returns:
+//   - *Store: The wrapped store
+func New(ctx context.Context, logger ulogger.Logger, store utxo.Store, connect ConnectFunc, probeInterval, maxBackoff time.Duration) *Store {
+	s := &Store{
+		logger:        logger,
+		connect:       connect,
+		probeInterval: probeInterval,
+		maxBackoff:    maxBackoff,
+	}
+
+	if s.maxBackoff < s.probeInterval {
+		s.maxBackoff = s.probeInterval
+	}
+
+	s.store.Store(&storeHolder{Store: store})
+	s.healthy.Store(true)
+
+	go s.probe(ctx)
+
+	return s
+}
+
+func (s *Store) current() utxo.Store {
+	return s.store.Load().Store
+}
+
+// acquire returns the current store and a function to call once the operation on it completed. A replaced store is
+// only closed after all operations acquired on it completed, an operation never starts on a closed store.
+func (s *Store) acquire() (utxo.Store, func()) {
+	for {
+		holder := s.store.Load()
+		holder.inFlight.RLock()
+
+		// the store may have been replaced, and closed, while waiting for the lock
+		if s.store.Load() == holder {
+			return holder.Store, holder.inFlight.RUnlock
+		}
+
+		holder.inFlight.RUnlock()
+	}
+}
+
+// probe checks the health of the store at every probe interval, and recovers the connection when a check fails.
+func (s *Store) probe(ctx context.Context) {
+	ticker := time.NewTicker(s.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.check(ctx, s.current()); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				s.healthy.Store(false)
+				s.logger.Errorf("[UTXOStore][reconnect] lost connection to the utxo store: %v", err)
+
+				s.reconnect(ctx)
+
+				ticker.Reset(s.probeInterval)
+			}
+		}
+	}
+}
+
+// reconnect recovers the connection to the store, waiting with a capped exponential backoff between attempts,
+// until the connection is recovered or the context is cancelled.
+func (s *Store) reconnect(ctx context.Context) {
+	backoff := s.probeInterval
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		// the connection of the existing store may have recovered by itself
+		if err := s.check(ctx, s.current()); err == nil {
+			s.healthy.Store(true)
+			s.logger.Infof("[UTXOStore][reconnect] connection to the utxo store recovered after %d attempts", attempt)
+
+			return
+		}
+
+		store, err := s.connect(ctx)
+		if err == nil {
+			err = s.check(ctx, store)
+		}
+
+		if err == nil {
+			s.replace(store)
+			s.healthy.Store(true)
+			s.logger.Infof("[UTXOStore][reconnect] reconnected to the utxo store after %d attempts", attempt)
+
+			return
+		}
+
+		backoff = retry.CappedExponentialBackoff(backoff, 2, s.maxBackoff)
+
+		s.logger.Warnf("[UTXOStore][reconnect] attempt %d to reconnect to the utxo store failed, retrying in %s: %v", attempt, backoff, err)
+	}
+}
+
+// check probes the readiness of the store, with the probe interval as timeout.
+func (s *Store) check(ctx context.Context, store utxo.Store) error {
+	probeCtx, cancel := context.WithTimeout(ctx, s.probeInterval)
+	defer cancel()
+
+	status, message, err := store.Health(probeCtx, false)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return errors.NewServiceUnavailableError("utxo store health check returned status %d: %s", status, message)
+	}
+
+	return nil
+}
+
+// replace makes the new store the store all operations are delegated to, with the block state of the current store.
+func (s *Store) replace(store utxo.Store) {
+	s.blockStateMu.Lock()
+	defer s.blockStateMu.Unlock()
+
+	blockState := s.current().GetBlockState()
+
+	if err := store.SetBlockHeight(blockState.Height); err != nil {
+		s.logger.Errorf("[UTXOStore][reconnect] error setting block height on the reconnected utxo store: %v", err)
+	}
+
+	if err := store.SetMedianBlockTime(blockState.MedianTime); err != nil {
+		s.logger.Errorf("[UTXOStore][reconnect] error setting median block time on the reconnected utxo store: %v", err)
+	}
+
+	replaced := s.store.Swap(&storeHolder{Store: store})
+
+	go s.close(replaced)
+}
+
+// close closes a replaced store once the operations running on it completed. Stores without resources of their
+// own to release, like the stores sharing a client with other stores, do not implement io.Closer and are left as is.
+func (s *Store) close(replaced *storeHolder) {
+	closer, ok := replaced.Store.(io.Closer)
+	if !ok {
+		return
+	}
+
+	replaced.inFlight.Lock()
+	defer replaced.inFlight.Unlock()
+
+	if err := closer.Close(); err != nil {
+		s.logger.Warnf("[UTXOStore][reconnect] error closing the replaced utxo store: %v", err)
+	}
+}
+
+// Health reports the store not ready while its connection is being recovered, and otherwise returns the health of
+// the wrapped store. Liveness is not affected by a lost connection, the service should not be restarted for it.
+func (s *Store) Health(ctx context.Context, checkLiveness bool) (int, string, error) {
+	if !checkLiveness && !s.healthy.Load() {
+		return http.StatusServiceUnavailable, "UTXO store connection lost, reconnecting",
+			errors.NewServiceUnavailableError("utxo store connection lost, reconnecting")
+	}
+
+	store, release := s.acquire()
+	defer release()
+
+	return store.Health(ctx, checkLiveness)
+}
+
+func (s *Store) SetBlockHeight(height uint32) error {
+	s.blockStateMu.Lock()
+	defer s.blockStateMu.Unlock()
+
+	store, release := s.acquire()
+	defer release()
+
+	return store.SetBlockHeight(height)
+}
+
+func (s *Store) GetBlockHeight() uint32 {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetBlockHeight()
+}
+
+func (s *Store) SetMedianBlockTime(medianTime uint32) error {
+	s.blockStateMu.Lock()
+	defer s.blockStateMu.Unlock()
+
+	store, release := s.acquire()
+	defer release()
+
+	return store.SetMedianBlockTime(medianTime)
+}
+
+func (s *Store) GetMedianBlockTime() uint32 {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetMedianBlockTime()
+}
+
+func (s *Store) GetBlockState() utxo.BlockState {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetBlockState()
+}
+
+func (s *Store) Create(ctx context.Context, tx *bt.Tx, blockHeight uint32, opts ...utxo.CreateOption) (*meta.Data, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.Create(ctx, tx, blockHeight, opts...)
+}
+
+func (s *Store) Get(ctx context.Context, hash *chainhash.Hash, fields ...fields.FieldName) (*meta.Data, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.Get(ctx, hash, fields...)
+}
+
+func (s *Store) Delete(ctx context.Context, hash *chainhash.Hash) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.Delete(ctx, hash)
+}
+
+func (s *Store) GetSpend(ctx context.Context, spend *utxo.Spend) (*utxo.SpendResponse, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetSpend(ctx, spend)
+}
+
+func (s *Store) GetMeta(ctx context.Context, hash *chainhash.Hash) (*meta.Data, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetMeta(ctx, hash)
+}
+
+func (s *Store) Spend(ctx context.Context, tx *bt.Tx, blockHeight uint32, ignoreFlags ...utxo.IgnoreFlags) ([]*utxo.Spend, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.Spend(ctx, tx, blockHeight, ignoreFlags...)
+}
+
+func (s *Store) Unspend(ctx context.Context, spends []*utxo.Spend, flagAsLocked ...bool) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.Unspend(ctx, spends, flagAsLocked...)
+}
+
+func (s *Store) SetMinedMulti(ctx context.Context, hashes []*chainhash.Hash, minedBlockInfo utxo.MinedBlockInfo) (map[chainhash.Hash][]uint32, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.SetMinedMulti(ctx, hashes, minedBlockInfo)
+}
+
+func (s *Store) GetUnminedTxIterator(fullScan bool) (utxo.UnminedTxIterator, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetUnminedTxIterator(fullScan)
+}
+
+func (s *Store) QueryOldUnminedTransactions(ctx context.Context, cutoffBlockHeight uint32) ([]chainhash.Hash, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.QueryOldUnminedTransactions(ctx, cutoffBlockHeight)
+}
+
+func (s *Store) PreserveTransactions(ctx context.Context, txIDs []chainhash.Hash, preserveUntilHeight uint32) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.PreserveTransactions(ctx, txIDs, preserveUntilHeight)
+}
+
+func (s *Store) ProcessExpiredPreservations(ctx context.Context, currentHeight uint32) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.ProcessExpiredPreservations(ctx, currentHeight)
+}
+
+func (s *Store) BatchDecorate(ctx context.Context, unresolvedMetaDataSlice []*utxo.UnresolvedMetaData, fields ...fields.FieldName) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.BatchDecorate(ctx, unresolvedMetaDataSlice, fields...)
+}
+
+func (s *Store) PreviousOutputsDecorate(ctx context.Context, tx *bt.Tx) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.PreviousOutputsDecorate(ctx, tx)
+}
+
+func (s *Store) FreezeUTXOs(ctx context.Context, spends []*utxo.Spend, tSettings *settings.Settings) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.FreezeUTXOs(ctx, spends, tSettings)
+}
+
+func (s *Store) UnFreezeUTXOs(ctx context.Context, spends []*utxo.Spend, tSettings *settings.Settings) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.UnFreezeUTXOs(ctx, spends, tSettings)
+}
+
+func (s *Store) ReAssignUTXO(ctx context.Context, utxo *utxo.Spend, newUtxo *utxo.Spend, tSettings *settings.Settings) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.ReAssignUTXO(ctx, utxo, newUtxo, tSettings)
+}
+
+func (s *Store) GetCounterConflicting(ctx context.Context, txHash chainhash.Hash) ([]chainhash.Hash, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetCounterConflicting(ctx, txHash)
+}
+
+func (s *Store) GetConflictingChildren(ctx context.Context, txHash chainhash.Hash) ([]chainhash.Hash, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.GetConflictingChildren(ctx, txHash)
+}
+
+func (s *Store) SetConflicting(ctx context.Context, txHashes []chainhash.Hash, value bool) ([]*utxo.Spend, []chainhash.Hash, error) {
+	store, release := s.acquire()
+	defer release()
+
+	return store.SetConflicting(ctx, txHashes, value)
+}
+
+func (s *Store) SetLocked(ctx context.Context, txHashes []chainhash.Hash, value bool) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.SetLocked(ctx, txHashes, value)
+}
+
+func (s *Store) MarkTransactionsOnLongestChain(ctx context.Context, txHashes []chainhash.Hash, onLongestChain bool) error {
+	store, release := s.acquire()
+	defer release()
+
+	return store.MarkTransactionsOnLongestChain(ctx, txHashes, onLongestChain)
+}
+
+// GetCleanupService returns the cleanup service of the wrapped store, or nil when the store does not provide one.
+// The cleanup service keeps using the store it was created from, also once that store was replaced and closed.
+func (s *Store) GetCleanupService() (cleanup.Service, error) {
+	if provider, ok := s.current().(cleanup.CleanupServiceProvider); ok {
+		return provider.GetCleanupService()
+	}
+
+	return nil, nil
+}
+
+// WaitForIndexReady waits for the index of the wrapped store to be ready, stores without indexes are always ready.
+func (s *Store) WaitForIndexReady(ctx context.Context, indexName string) error {
+	store, release := s.acquire()
+	defer release()
+
+	if indexWaiter, ok := store.(interface {
+		WaitForIndexReady(ctx context.Context, indexName string) error
+	}); ok {
+		return indexWaiter.WaitForIndexReady(ctx, indexName)
+	}
+
+	return nil
+}
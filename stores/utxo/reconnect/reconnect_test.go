@@ -0,0 +1,247 @@
+package reconnect
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/stores/utxo"
+	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
+	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connectionStore is a utxo store whose connection can be dropped, only the methods used by the tests are implemented
+type connectionStore struct {
+	utxo.Store
+
+	id         int
+	connected  atomic.Bool
+	closed     atomic.Bool
+	blockState atomic.Pointer[utxo.BlockState]
+
+	// getStarted and getRelease hold the calls to Get until getRelease is closed, when set
+	getStarted chan struct{}
+	getRelease chan struct{}
+}
+
+func newConnectionStore(id int) *connectionStore {
+	s := &connectionStore{id: id}
+	s.connected.Store(true)
+	s.blockState.Store(&utxo.BlockState{})
+
+	return s
+}
+
+func (s *connectionStore) Health(_ context.Context, checkLiveness bool) (int, string, error) {
+	if !checkLiveness && !s.connected.Load() {
+		return http.StatusServiceUnavailable, "connection refused", errors.NewStorageUnavailableError("connection refused")
+	}
+
+	return http.StatusOK, "OK", nil
+}
+
+func (s *connectionStore) Get(_ context.Context, _ *chainhash.Hash, _ ...fields.FieldName) (*meta.Data, error) {
+	if s.getRelease != nil {
+		s.getStarted <- struct{}{}
+		<-s.getRelease
+	}
+
+	if s.closed.Load() {
+		return nil, errors.NewStorageError("store is closed")
+	}
+
+	if !s.connected.Load() {
+		return nil, errors.NewStorageUnavailableError("connection refused")
+	}
+
+	return &meta.Data{Fee: uint64(s.id)}, nil
+}
+
+func (s *connectionStore) Close() error {
+	s.closed.Store(true)
+
+	return nil
+}
+
+func (s *connectionStore) SetBlockHeight(height uint32) error {
+	blockState := *s.blockState.Load()
+	blockState.Height = height
+	s.blockState.Store(&blockState)
+
+	return nil
+}
+
+func (s *connectionStore) SetMedianBlockTime(medianTime uint32) error {
+	blockState := *s.blockState.Load()
+	blockState.MedianTime = medianTime
+	s.blockState.Store(&blockState)
+
+	return nil
+}
+
+func (s *connectionStore) GetBlockState() utxo.BlockState {
+	return *s.blockState.Load()
+}
+
+func TestStore_Reconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	original := newConnectionStore(1)
+
+	// the database stays unreachable for the first reconnection attempt
+	var (
+		attempts    atomic.Int32
+		reconnected atomic.Pointer[connectionStore]
+	)
+
+	connect := func(ctx context.Context) (utxo.Store, error) {
+		if attempts.Add(1) == 1 {
+			return nil, errors.NewStorageUnavailableError("connection refused")
+		}
+
+		store := newConnectionStore(2)
+		reconnected.Store(store)
+
+		return store, nil
+	}
+
+	s := New(ctx, ulogger.TestLogger{}, original, connect, 10*time.Millisecond, 20*time.Millisecond)
+
+	require.NoError(t, s.SetBlockHeight(100))
+	require.NoError(t, s.SetMedianBlockTime(12345))
+
+	status, _, err := s.Health(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	data, err := s.Get(ctx, &chainhash.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), data.Fee)
+
+	// drop the connection, the store must report not ready
+	original.connected.Store(false)
+
+	require.Eventually(t, func() bool {
+		status, _, _ := s.Health(ctx, false)
+		return status == http.StatusServiceUnavailable
+	}, time.Second, time.Millisecond)
+
+	// liveness is not affected by the outage
+	status, _, err = s.Health(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	// the store is replaced by a reconnected store once it is healthy
+	require.Eventually(t, func() bool {
+		status, _, _ := s.Health(ctx, false)
+		return status == http.StatusOK
+	}, time.Second, time.Millisecond)
+
+	assert.GreaterOrEqual(t, attempts.Load(), int32(2))
+	require.NotNil(t, reconnected.Load())
+
+	data, err = s.Get(ctx, &chainhash.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), data.Fee)
+
+	// the block state is carried over to the reconnected store
+	assert.Equal(t, utxo.BlockState{Height: 100, MedianTime: 12345}, reconnected.Load().GetBlockState())
+
+	// the replaced store is closed, the reconnected store is not
+	require.Eventually(t, original.closed.Load, time.Second, time.Millisecond)
+	assert.False(t, reconnected.Load().closed.Load())
+}
+
+func TestStore_ClosesReplacedStoreAfterInFlightCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	original := newConnectionStore(1)
+	original.getStarted = make(chan struct{}, 1)
+	original.getRelease = make(chan struct{})
+
+	connect := func(ctx context.Context) (utxo.Store, error) {
+		return newConnectionStore(2), nil
+	}
+
+	s := New(ctx, ulogger.TestLogger{}, original, connect, 10*time.Millisecond, 20*time.Millisecond)
+
+	// a call is still running on the original store when it is replaced
+	inFlightErr := make(chan error, 1)
+
+	go func() {
+		_, err := s.Get(ctx, &chainhash.Hash{})
+		inFlightErr <- err
+	}()
+
+	<-original.getStarted
+
+	original.connected.Store(false)
+
+	require.Eventually(t, func() bool {
+		return s.current() != utxo.Store(original)
+	}, time.Second, time.Millisecond)
+
+	data, err := s.Get(ctx, &chainhash.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), data.Fee)
+
+	// the original store is only closed once the call running on it completed
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, original.closed.Load())
+
+	close(original.getRelease)
+
+	err = <-inFlightErr
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "store is closed")
+
+	require.Eventually(t, original.closed.Load, time.Second, time.Millisecond)
+}
+
+func TestStore_ConnectionRecoversByItself(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	original := newConnectionStore(1)
+
+	var attempts atomic.Int32
+
+	connect := func(ctx context.Context) (utxo.Store, error) {
+		attempts.Add(1)
+		return nil, errors.NewStorageUnavailableError("connection refused")
+	}
+
+	s := New(ctx, ulogger.TestLogger{}, original, connect, 10*time.Millisecond, 20*time.Millisecond)
+
+	original.connected.Store(false)
+
+	require.Eventually(t, func() bool {
+		status, _, _ := s.Health(ctx, false)
+		return status == http.StatusServiceUnavailable
+	}, time.Second, time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return attempts.Load() > 0
+	}, time.Second, time.Millisecond)
+
+	original.connected.Store(true)
+
+	require.Eventually(t, func() bool {
+		status, _, _ := s.Health(ctx, false)
+		return status == http.StatusOK
+	}, time.Second, time.Millisecond)
+
+	// the original store is kept
+	data, err := s.Get(ctx, &chainhash.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), data.Fee)
+}
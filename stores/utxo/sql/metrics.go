@@ -38,6 +38,10 @@
 //   - teranode_sql_utxo_reset: Number of UTXO reset operations
 //   - teranode_sql_utxo_delete: Number of UTXO delete operations
 //   - teranode_sql_utxo_errors: Number of errors by function and type
+//   - teranode_sql_utxo_pool_size: Number of connection pool slots
+//   - teranode_sql_utxo_pool_in_use: Number of connection pool slots currently in use
+//   - teranode_sql_utxo_pool_wait: Time spent waiting for a connection pool slot
+//   - teranode_sql_utxo_pool_rejected: Number of operations rejected by a saturated connection pool
 package sql
 
 import (
@@ -54,6 +58,11 @@ var (
 	prometheusUtxoDelete prometheus.Counter
 	prometheusUtxoErrors *prometheus.CounterVec
 
+	prometheusUtxoPoolSize     prometheus.Gauge
+	prometheusUtxoPoolInUse    prometheus.Gauge
+	prometheusUtxoPoolWait     prometheus.Histogram
+	prometheusUtxoPoolRejected prometheus.Counter
+
 	prometheusSQLUtxoGetCounterConflicting prometheus.Histogram
 	prometheusSQLUtxoGetConflicting        prometheus.Histogram
 
@@ -128,4 +137,40 @@ func _initPrometheusMetrics() {
 			Help:      "Histogram of utxo get conflicting calls done to sql",
 		},
 	)
+
+	prometheusUtxoPoolSize = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "teranode",
+			Subsystem: "sql",
+			Name:      "utxo_pool_size",
+			Help:      "Number of connection pool slots of the sql utxo store",
+		},
+	)
+
+	prometheusUtxoPoolInUse = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "teranode",
+			Subsystem: "sql",
+			Name:      "utxo_pool_in_use",
+			Help:      "Number of connection pool slots of the sql utxo store currently in use",
+		},
+	)
+
+	prometheusUtxoPoolWait = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "teranode",
+			Subsystem: "sql",
+			Name:      "utxo_pool_wait",
+			Help:      "Time in seconds spent waiting for a connection pool slot of the sql utxo store",
+		},
+	)
+
+	prometheusUtxoPoolRejected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "sql",
+			Name:      "utxo_pool_rejected",
+			Help:      "Number of sql utxo store operations rejected because the connection pool was saturated",
+		},
+	)
 }
@@ -0,0 +1,88 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+)
+
+// connPool bounds the number of store operations that may hold a backend connection at the same time.
+// When all slots are in use, callers either wait for a slot to be released or, in fail-fast mode,
+// are rejected immediately with a storage unavailable error.
+type connPool struct {
+	slots    chan struct{}
+	failFast bool
+}
+
+// newConnPool creates a pool with the given number of slots.
+// A size of zero or less disables the limit and a nil pool is returned.
+func newConnPool(size int, failFast bool) *connPool {
+	if size <= 0 {
+		return nil
+	}
+
+	prometheusUtxoPoolSize.Set(float64(size))
+
+	return &connPool{
+		slots:    make(chan struct{}, size),
+		failFast: failFast,
+	}
+}
+
+// acquire reserves a slot in the pool, returning a function that must be called to release it.
+// A nil pool never blocks.
+func (p *connPool) acquire(ctx context.Context) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		return p.acquired(), nil
+	default:
+	}
+
+	if p.failFast {
+		prometheusUtxoPoolRejected.Inc()
+		return nil, errors.NewStorageUnavailableError("utxo store connection pool exhausted (%d connections in use)", cap(p.slots))
+	}
+
+	start := time.Now()
+
+	select {
+	case p.slots <- struct{}{}:
+		prometheusUtxoPoolWait.Observe(time.Since(start).Seconds())
+		return p.acquired(), nil
+	case <-ctx.Done():
+		prometheusUtxoPoolRejected.Inc()
+		return nil, errors.NewContextCanceledError("timed out waiting for a utxo store connection", ctx.Err())
+	}
+}
+
+func (p *connPool) acquired() func() {
+	prometheusUtxoPoolInUse.Inc()
+
+	return func() {
+		prometheusUtxoPoolInUse.Dec()
+		<-p.slots
+	}
+}
+
+// inUse returns the number of slots currently held.
+func (p *connPool) inUse() int {
+	if p == nil {
+		return 0
+	}
+
+	return len(p.slots)
+}
+
+// utilization returns the fraction of the pool currently in use, between 0 and 1.
+func (p *connPool) utilization() float64 {
+	if p == nil {
+		return 0
+	}
+
+	return float64(len(p.slots)) / float64(cap(p.slots))
+}
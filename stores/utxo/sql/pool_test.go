@@ -0,0 +1,129 @@
+package sql
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnPool(t *testing.T) {
+	initPrometheusMetrics()
+
+	t.Run("disabled pool never blocks", func(t *testing.T) {
+		pool := newConnPool(0, true)
+		require.Nil(t, pool)
+
+		release, err := pool.acquire(context.Background())
+		require.NoError(t, err)
+
+		release()
+
+		assert.Equal(t, 0, pool.inUse())
+		assert.Equal(t, float64(0), pool.utilization())
+	})
+
+	t.Run("bounds concurrency and reports utilization", func(t *testing.T) {
+		pool := newConnPool(4, false)
+
+		var (
+			current atomic.Int32
+			peak    atomic.Int32
+			wg      sync.WaitGroup
+		)
+
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				release, err := pool.acquire(context.Background())
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				defer release()
+
+				n := current.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+
+				time.Sleep(5 * time.Millisecond)
+				current.Add(-1)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.LessOrEqual(t, peak.Load(), int32(4))
+		assert.Equal(t, 0, pool.inUse())
+
+		releases := make([]func(), 0, 4)
+
+		for i := 0; i < 3; i++ {
+			release, err := pool.acquire(context.Background())
+			require.NoError(t, err)
+
+			releases = append(releases, release)
+		}
+
+		assert.Equal(t, 3, pool.inUse())
+		assert.InDelta(t, 0.75, pool.utilization(), 0.0001)
+
+		for _, release := range releases {
+			release()
+		}
+
+		assert.Equal(t, float64(0), pool.utilization())
+	})
+
+	t.Run("wait mode blocks until context is done", func(t *testing.T) {
+		pool := newConnPool(1, false)
+
+		release, err := pool.acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err = pool.acquire(ctx)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrContextCanceled))
+
+		release()
+
+		release, err = pool.acquire(context.Background())
+		require.NoError(t, err)
+
+		release()
+	})
+
+	t.Run("fail fast mode rejects when saturated", func(t *testing.T) {
+		pool := newConnPool(1, true)
+
+		release, err := pool.acquire(context.Background())
+		require.NoError(t, err)
+
+		_, err = pool.acquire(context.Background())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrStorageUnavailable))
+		assert.Equal(t, float64(1), pool.utilization())
+
+		release()
+
+		release, err = pool.acquire(context.Background())
+		require.NoError(t, err)
+
+		release()
+	})
+}
@@ -38,6 +38,10 @@
 //   - teranode_sql_utxo_reset: Number of UTXO reset operations
 //   - teranode_sql_utxo_delete: Number of UTXO delete operations
 //   - teranode_sql_utxo_errors: Number of errors by function and type
+//   - teranode_sql_utxo_pool_size: Number of connection pool slots
+//   - teranode_sql_utxo_pool_in_use: Number of connection pool slots currently in use
+//   - teranode_sql_utxo_pool_wait: Time spent waiting for a connection pool slot
+//   - teranode_sql_utxo_pool_rejected: Number of operations rejected by a saturated connection pool
 package sql
 
 import (
@@ -58,6 +62,7 @@ import (
 	"github.com/bsv-blockchain/go-subtree"
 	"github.com/bsv-blockchain/teranode/errors"
 	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/stores/schemaversion"
 	"github.com/bsv-blockchain/teranode/stores/utxo"
 	"github.com/bsv-blockchain/teranode/stores/utxo/fields"
 	"github.com/bsv-blockchain/teranode/stores/utxo/meta"
@@ -71,6 +76,10 @@ import (
 	sqlite3 "modernc.org/sqlite/lib"
 )
 
+// SchemaVersion is the version of the database schema of the UTXO store.
+// It must be incremented whenever a change is made to the schema that requires existing stores to be upgraded.
+const SchemaVersion uint32 = 1
+
 // Store implements the UTXO store interface using a SQL database backend.
 type Store struct {
 	logger          ulogger.Logger
@@ -78,6 +87,7 @@ type Store struct {
 	db              *usql.DB
 	storeURL        *url.URL
 	engine          string
+	pool            *connPool
 	blockHeight     atomic.Uint32
 	medianBlockTime atomic.Uint32
 }
@@ -93,6 +103,7 @@ type Store struct {
 // URL parameters:
 //   - expiration: Duration after which spent UTXOs are cleaned up
 //   - logging: Enable SQL query logging
+//   - skipSchemaVersionCheck: Skip the startup validation of the schema version
 //
 // Example URLs:
 //
@@ -122,12 +133,25 @@ func New(ctx context.Context, logger ulogger.Logger, tSettings *settings.Setting
 		return nil, errors.NewStorageError("unknown database engine: %s", storeURL.Scheme)
 	}
 
+	if !schemaversion.SkipCheck(storeURL) {
+		if err = schemaversion.CheckSQL(ctx, db, "utxo", SchemaVersion); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
+	var pool *connPool
+	if storeURL.Scheme == "postgres" {
+		pool = newConnPool(tSettings.UtxoStore.PostgresMaxOpenConns, tSettings.UtxoStore.PostgresPoolFailFast)
+	}
+
 	s := &Store{
 		logger:          logger,
 		settings:        tSettings,
 		db:              db,
 		storeURL:        storeURL,
 		engine:          storeURL.Scheme,
+		pool:            pool,
 		blockHeight:     atomic.Uint32{},
 		medianBlockTime: atomic.Uint32{},
 	}
@@ -189,12 +213,18 @@ func (s *Store) Create(ctx context.Context, tx *bt.Tx, blockHeight uint32, opts
 	ctx, _, deferFn := tracing.Tracer("utxo").Start(ctx, "sql:Create")
 	defer deferFn()
 
+	release, err := s.pool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	// ctx, cancelTimeout := context.WithTimeout(ctx, s.settings.UtxoStore.DBTimeout)
 	// defer cancelTimeout()
 
 	// Try the operation with retry logic for lock errors
 	var txMeta *meta.Data
-	var err error
 
 	for attempt := 0; attempt <= 3; attempt++ {
 		txMeta, err = s.createWithRetry(ctx, tx, blockHeight, options)
@@ -516,6 +546,13 @@ func (s *Store) get(ctx context.Context, hash *chainhash.Hash, bins []fields.Fie
 	ctx, cancelTimeout := context.WithTimeout(ctx, s.settings.UtxoStore.DBTimeout)
 	defer cancelTimeout()
 
+	release, err := s.pool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	// Always get the transaction row
 
 	q := `
@@ -544,7 +581,7 @@ func (s *Store) get(ctx context.Context, hash *chainhash.Hash, bins []fields.Fie
 		unminedSince      sql.NullInt64
 	)
 
-	err := s.db.QueryRowContext(ctx, q, hash[:]).Scan(&id, &version, &lockTime, &data.Fee, &data.SizeInBytes, &data.IsCoinbase, &data.Frozen, &data.Conflicting, &data.Locked, &unminedSince)
+	err = s.db.QueryRowContext(ctx, q, hash[:]).Scan(&id, &version, &lockTime, &data.Fee, &data.SizeInBytes, &data.IsCoinbase, &data.Frozen, &data.Conflicting, &data.Locked, &unminedSince)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.NewTxNotFoundError("transaction %s not found", hash, err)
@@ -777,6 +814,13 @@ func (s *Store) Spend(ctx context.Context, tx *bt.Tx, blockHeight uint32, ignore
 	ctx, cancelTimeout := context.WithTimeout(ctx, s.settings.UtxoStore.DBTimeout)
 	defer cancelTimeout()
 
+	release, err := s.pool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	defer func() {
 		if recoverErr := recover(); recoverErr != nil {
 			prometheusUtxoErrors.WithLabelValues("Spend", "Failed Spend Cleaning").Inc()
@@ -786,7 +830,6 @@ func (s *Store) Spend(ctx context.Context, tx *bt.Tx, blockHeight uint32, ignore
 
 	// try the operation with retry logic for lock errors
 	var spends []*utxo.Spend
-	var err error
 
 	for attempt := 0; attempt <= 3; attempt++ {
 		spends, err = s.spendWithRetry(ctx, tx, blockHeight, ignoreFlags...)
@@ -1064,6 +1107,13 @@ func (s *Store) Unspend(ctx context.Context, spends []*utxo.Spend, flagAsLocked
 	ctx, cancelTimeout := context.WithTimeout(ctx, s.settings.UtxoStore.DBTimeout)
 	defer cancelTimeout()
 
+	release, err := s.pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	txn, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -1141,6 +1191,13 @@ func (s *Store) Delete(ctx context.Context, hash *chainhash.Hash) error {
 	ctx, cancelTimeout := context.WithTimeout(ctx, s.settings.UtxoStore.DBTimeout)
 	defer cancelTimeout()
 
+	release, err := s.pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	// Start a database transaction
 	txn, err := s.db.Begin()
 	if err != nil {
@@ -1212,6 +1269,13 @@ func (s *Store) Delete(ctx context.Context, hash *chainhash.Hash) error {
 }
 
 func (s *Store) SetMinedMulti(ctx context.Context, hashes []*chainhash.Hash, minedBlockInfo utxo.MinedBlockInfo) (map[chainhash.Hash][]uint32, error) {
+	release, err := s.pool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	// Check if we're using PostgreSQL or SQLite
 	isPostgres := s.storeURL.Scheme == "postgres"
 
@@ -1615,6 +1679,13 @@ func (s *Store) GetSpend(ctx context.Context, spend *utxo.Spend) (*utxo.SpendRes
 	ctx, cancelTimeout := context.WithTimeout(ctx, s.settings.UtxoStore.DBTimeout)
 	defer cancelTimeout()
 
+	release, err := s.pool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	q := `
 		SELECT
 		 o.utxo_hash
@@ -1640,7 +1711,7 @@ func (s *Store) GetSpend(ctx context.Context, spend *utxo.Spend) (*utxo.SpendRes
 		locked                 bool
 	)
 
-	err := s.db.QueryRowContext(ctx, q, spend.TxID[:], spend.Vout).Scan(&utxoHash, &coinbaseSpendingHeight, &spendingDataBytes, &frozen, &spendableIn, &conflicting, &locked)
+	err = s.db.QueryRowContext(ctx, q, spend.TxID[:], spend.Vout).Scan(&utxoHash, &coinbaseSpendingHeight, &spendingDataBytes, &frozen, &spendableIn, &conflicting, &locked)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.NewNotFoundError("utxo not found for %s:%d", spend.TxID, spend.Vout)
@@ -2725,6 +2796,11 @@ func (s *Store) ProcessExpiredPreservations(ctx context.Context, currentHeight u
 	return nil
 }
 
+// Close closes the database connection of the store, the store cannot be used afterwards.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
 // RawDB returns the underlying *usql.DB connection. For test/debug use only.
 func (s *Store) RawDB() *usql.DB {
 	return s.db
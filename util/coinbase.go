@@ -45,6 +45,11 @@ func extractCoinbaseHeightAndText(sigScript bscript.Script) (uint32, string, err
 		return 0, "", errors.NewBlockCoinbaseMissingHeightError("the coinbase signature script must start with the length of the serialized block height")
 	}
 
+	// heights 1 to 16 are pushed as OP_1 to OP_16 when the height is encoded as a minimal script number
+	if op := sigScript[0]; op >= bscript.Op1 && op <= bscript.Op16 {
+		return uint32(op-bscript.Op1) + 1, extractMiner(string(sigScript[1:])), nil
+	}
+
 	serializedLen := int(sigScript[0])
 
 	if len(sigScript[1:]) < serializedLen {
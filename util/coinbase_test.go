@@ -119,6 +119,18 @@ func TestExtractCoinbaseHeightAndTextScripts(t *testing.T) {
 			expectedHeight: 1,
 			expectedMiner:  "/satoshi/",
 		},
+		{
+			name:           "height 1 as OP_1",
+			script:         "512f7361746f7368692f",
+			expectedHeight: 1,
+			expectedMiner:  "/satoshi/",
+		},
+		{
+			name:           "height 16 as OP_16",
+			script:         "60",
+			expectedHeight: 16,
+			expectedMiner:  "",
+		},
 		{
 			name:        "empty script",
 			script:      "",
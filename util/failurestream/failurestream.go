@@ -0,0 +1,216 @@
+// Package failurestream emits structured records of validation failures to a dedicated stream, separate from
+// the operational logs, for offline analysis. The stream is configured with the validation_failureSink setting:
+//
+//   - file:///path/to/failures.jsonl appends every failure as a line of JSON to the file
+//   - kafka://host:port/topic (or memory://topic in tests) publishes every failure as a JSON message to the
+//     Kafka topic, keyed by the transaction id or block hash
+//
+// When no sink is configured, failures are discarded.
+package failurestream
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/bsv-blockchain/teranode/util/kafka"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// bufferSize is the number of failures buffered for writing, failures are dropped when the buffer is full
+const bufferSize = 10_000
+
+var (
+	prometheusFailuresDropped prometheus.Counter
+	prometheusMetricsInitOnce sync.Once
+)
+
+func initPrometheusMetrics() {
+	prometheusMetricsInitOnce.Do(func() {
+		prometheusFailuresDropped = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "teranode",
+				Subsystem: "failurestream",
+				Name:      "dropped",
+				Help:      "Number of validation failures dropped because the failure stream buffer was full",
+			},
+		)
+	})
+}
+
+// Failure is the record of a single validation failure.
+type Failure struct {
+	// Timestamp is the time of the failure, set by Emit when zero
+	Timestamp time.Time `json:"timestamp"`
+
+	// Service is the name of the service the validation failed in, set by Emit
+	Service string `json:"service"`
+
+	// TxID is the id of the failing transaction, empty for block failures
+	TxID string `json:"txid,omitempty"`
+
+	// BlockHash is the hash of the failing block, or the block the failing transaction was validated for
+	BlockHash string `json:"blockHash,omitempty"`
+
+	// Stage is the stage of the validation that failed, e.g. policy or consensus
+	Stage string `json:"stage"`
+
+	// Reason is the full error of the failure
+	Reason string `json:"reason"`
+}
+
+// key returns the key of the failure on the stream
+func (f *Failure) key() string {
+	if f.TxID != "" {
+		return f.TxID
+	}
+
+	return f.BlockHash
+}
+
+// Emitter emits validation failures to the failure stream.
+type Emitter interface {
+	// Emit queues the failure for the stream. It never blocks, the failure is dropped when the stream
+	// does not keep up.
+	Emit(failure *Failure)
+}
+
+// nopEmitter discards all failures, used when no sink is configured
+type nopEmitter struct{}
+
+// Emit discards the failure.
+func (nopEmitter) Emit(*Failure) {}
+
+// streamEmitter writes the emitted failures to a sink in the background
+type streamEmitter struct {
+	logger  ulogger.Logger
+	service string
+	ch      chan *Failure
+	write   func(failure *Failure, data []byte) error
+}
+
+// New creates the emitter of the validation failures of the given service, writing to the sink configured in
+// the validation_failureSink setting until the context is done. When no sink is configured, the returned
+// emitter discards all failures.
+func New(ctx context.Context, logger ulogger.Logger, tSettings *settings.Settings, service string) (Emitter, error) {
+	sinkURL := tSettings.ValidationFailureSink
+	if sinkURL == nil {
+		return nopEmitter{}, nil
+	}
+
+	initPrometheusMetrics()
+
+	e := &streamEmitter{
+		logger:  logger,
+		service: service,
+		ch:      make(chan *Failure, bufferSize),
+	}
+
+	var closeSink func()
+
+	switch sinkURL.Scheme {
+	case "file":
+		file, err := openFileSink(sinkURL)
+		if err != nil {
+			return nil, err
+		}
+
+		e.write = func(_ *Failure, data []byte) error {
+			_, err := file.Write(append(data, '\n'))
+			return err
+		}
+
+		closeSink = func() {
+			_ = file.Close()
+		}
+	default:
+		producer, err := kafka.NewKafkaAsyncProducerFromURL(ctx, logger, sinkURL, &tSettings.Kafka)
+		if err != nil {
+			return nil, errors.NewServiceError("could not create validation failure kafka producer", err)
+		}
+
+		producer.Start(ctx, make(chan *kafka.Message, bufferSize))
+
+		e.write = func(failure *Failure, data []byte) error {
+			producer.Publish(&kafka.Message{
+				Key:   []byte(failure.key()),
+				Value: data,
+			})
+
+			return nil
+		}
+
+		closeSink = func() {}
+	}
+
+	logger.Infof("[failurestream] emitting %s validation failures to %s", service, sinkURL.Redacted())
+
+	go e.run(ctx, closeSink)
+
+	return e, nil
+}
+
+// openFileSink opens the file of a file:// sink for appending, creating the file and its directory if needed
+func openFileSink(sinkURL *url.URL) (*os.File, error) {
+	// file://relative/path parses the first path element as the host
+	path := sinkURL.Host + sinkURL.Path
+	if path == "" {
+		return nil, errors.NewConfigurationError("validation failure sink %s has no file path", sinkURL.String())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.NewStorageError("could not create directory of validation failure file %s", path, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec // path is configured by the operator
+	if err != nil {
+		return nil, errors.NewStorageError("could not open validation failure file %s", path, err)
+	}
+
+	return file, nil
+}
+
+// Emit queues the failure for writing to the sink.
+func (e *streamEmitter) Emit(failure *Failure) {
+	if failure.Timestamp.IsZero() {
+		failure.Timestamp = time.Now()
+	}
+
+	failure.Service = e.service
+
+	select {
+	case e.ch <- failure:
+	default:
+		prometheusFailuresDropped.Inc()
+	}
+}
+
+// run writes the queued failures to the sink until the context is done
+func (e *streamEmitter) run(ctx context.Context, closeSink func()) {
+	defer closeSink()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case failure := <-e.ch:
+			data, err := json.Marshal(failure)
+			if err != nil {
+				e.logger.Errorf("[failurestream] failed to marshal validation failure of %s: %v", failure.key(), err)
+				continue
+			}
+
+			if err = e.write(failure, data); err != nil {
+				e.logger.Errorf("[failurestream] failed to write validation failure of %s: %v", failure.key(), err)
+			}
+		}
+	}
+}
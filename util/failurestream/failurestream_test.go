@@ -0,0 +1,87 @@
+package failurestream
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/settings"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("no sink", func(t *testing.T) {
+		tSettings := settings.NewSettings()
+		tSettings.ValidationFailureSink = nil
+
+		emitter, err := New(t.Context(), ulogger.TestLogger{}, tSettings, "validator")
+		require.NoError(t, err)
+
+		assert.IsType(t, nopEmitter{}, emitter)
+		emitter.Emit(&Failure{TxID: "tx"})
+	})
+
+	t.Run("file sink", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "failures", "failures.jsonl")
+
+		tSettings := settings.NewSettings()
+		tSettings.ValidationFailureSink = &url.URL{Scheme: "file", Path: path}
+
+		emitter, err := New(t.Context(), ulogger.TestLogger{}, tSettings, "validator")
+		require.NoError(t, err)
+
+		emitter.Emit(&Failure{TxID: "tx1", Stage: "policy", Reason: "fee too low"})
+		emitter.Emit(&Failure{BlockHash: "block1", Stage: "subtrees", Reason: "invalid transaction"})
+
+		var failures []Failure
+
+		require.Eventually(t, func() bool {
+			failures = readFailures(t, path)
+			return len(failures) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, "tx1", failures[0].TxID)
+		assert.Equal(t, "policy", failures[0].Stage)
+		assert.Equal(t, "fee too low", failures[0].Reason)
+		assert.Equal(t, "validator", failures[0].Service)
+		assert.False(t, failures[0].Timestamp.IsZero())
+
+		assert.Empty(t, failures[1].TxID)
+		assert.Equal(t, "block1", failures[1].BlockHash)
+		assert.Equal(t, "subtrees", failures[1].Stage)
+	})
+
+	t.Run("file sink without path", func(t *testing.T) {
+		tSettings := settings.NewSettings()
+		tSettings.ValidationFailureSink = &url.URL{Scheme: "file"}
+
+		_, err := New(t.Context(), ulogger.TestLogger{}, tSettings, "validator")
+		require.Error(t, err)
+	})
+}
+
+// readFailures reads the failures written to the file so far
+func readFailures(t *testing.T, path string) []Failure {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	var failures []Failure
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var failure Failure
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &failure))
+
+		failures = append(failures, failure)
+	}
+
+	return failures
+}
@@ -122,6 +122,7 @@ var listeners sync.Map
 // GetListener creates or retrieves a cached TCP listener for the specified service.
 // Returns the listener, listen address, client address, and any error.
 // Listeners are cached by context, service name, and schema to enable reuse.
+// Listeners handed off by a previous process (see StartReplacementProcess) are used instead of opening a new one.
 func GetListener(settingsContext string, serviceName string, schema string, listenerAddress string) (net.Listener, string, string, error) {
 	key := listenerKey(settingsContext, serviceName, schema)
 
@@ -134,9 +135,17 @@ func GetListener(settingsContext string, serviceName string, schema string, list
 		return lis, listenAddress, clientAddress, nil
 	}
 
-	lis, err := net.Listen("tcp", listenerAddress)
+	// use the listener handed off by the process this process is replacing, if there is one
+	lis, err := takeInheritedListener(key)
 	if err != nil {
-		return nil, "", "", errors.NewServiceError("[%s] failed to start a new listener", serviceName, err)
+		return nil, "", "", errors.NewServiceError("[%s] failed to use inherited listener", serviceName, err)
+	}
+
+	if lis == nil {
+		lis, err = net.Listen("tcp", listenerAddress)
+		if err != nil {
+			return nil, "", "", errors.NewServiceError("[%s] failed to start a new listener", serviceName, err)
+		}
 	}
 
 	listenAddress, clientAddress := addresses(schema, lis)
@@ -84,6 +84,10 @@ type KafkaConsumerConfig struct {
 	ChannelBufferSize int           // Number of messages buffered in internal channels (Sarama default: 256)
 	ConsumerTimeout   time.Duration // Max time without messages before watchdog triggers recovery (default: 90s)
 
+	// Reconnection configuration (query params: reconnectBackoff, maxReconnectBackoff)
+	ReconnectBackoff    time.Duration // Initial time to wait before reconnecting after Consume() failed, e.g. when the brokers are unavailable (default: 5s)
+	MaxReconnectBackoff time.Duration // Max time to wait before reconnecting, the backoff doubles while reconnecting keeps failing (default: 60s)
+
 	// OffsetReset controls what to do when offset is out of range (query param: offsetReset)
 	// Values: "latest" (default, skip to newest), "earliest" (reprocess from oldest), "" (use Replay setting)
 	OffsetReset string // Strategy for handling offset out of range errors
@@ -255,6 +259,10 @@ func NewKafkaConsumerGroupFromURL(logger ulogger.Logger, url *url.URL, consumerG
 	channelBufferSize := util.GetQueryParamInt(url, "channelBufferSize", 256)    // Sarama default: 256
 	consumerTimeoutMs := util.GetQueryParamInt(url, "consumerTimeout", 90000)    // Default: 90s (watchdog timeout for no messages)
 
+	// Extract reconnection backoff configuration (in milliseconds), used when the brokers are unavailable
+	reconnectBackoffMs := util.GetQueryParamInt(url, "reconnectBackoff", 5000)        // Default: 5s
+	maxReconnectBackoffMs := util.GetQueryParamInt(url, "maxReconnectBackoff", 60000) // Default: 60s
+
 	// Extract offset reset strategy (how to handle offset out of range errors)
 	// Values: "latest" (default), "earliest", or "" (empty uses Replay setting)
 	offsetReset := url.Query().Get("offsetReset")
@@ -290,6 +298,9 @@ func NewKafkaConsumerGroupFromURL(logger ulogger.Logger, url *url.URL, consumerG
 		ChannelBufferSize: channelBufferSize,
 		ConsumerTimeout:   time.Duration(consumerTimeoutMs) * time.Millisecond,
 		OffsetReset:       offsetReset,
+		// Reconnection configuration
+		ReconnectBackoff:    time.Duration(reconnectBackoffMs) * time.Millisecond,
+		MaxReconnectBackoff: time.Duration(maxReconnectBackoffMs) * time.Millisecond,
 		// TLS/Auth configuration
 		EnableTLS:          enableTLS,
 		TLSSkipVerify:      tlsSkipVerify,
@@ -819,6 +830,8 @@ func (k *KafkaConsumerGroup) Start(ctx context.Context, consumerFn func(message
 
 		// Only spawn one consumer goroutine - Sarama handles partition concurrency internally
 		go func() {
+			reconnectBackoff := k.newReconnectBackoff()
+
 			k.Config.Logger.Debugf("[kafka] starting consumer for group %s on topic %s (partition-based concurrency)", k.Config.ConsumerGroupID, topics[0])
 
 			for {
@@ -878,11 +891,21 @@ func (k *KafkaConsumerGroup) Start(ctx context.Context, consumerFn func(message
 							k.Config.Logger.Infof("[kafka] Consumer for group %s cancelled", k.Config.ConsumerGroupID)
 							return
 						default:
-							// Log error and wait before retrying to prevent tight loop when broker is down
-							k.Config.Logger.Errorf("Error from consumer: %v (after %v), retrying in 5s...", err, consumeDuration)
-							time.Sleep(5 * time.Second)
+							// Log error and wait before reconnecting to prevent tight loop when broker is down,
+							// backing off further while the broker stays unavailable
+							wait := reconnectBackoff.next()
+							k.Config.Logger.Errorf("Error from consumer: %v (after %v), reconnecting in %v...", err, consumeDuration, wait)
+							prometheusKafkaConsumerReconnects.WithLabelValues(k.Config.Topic, k.Config.ConsumerGroupID).Inc()
+
+							select {
+							case <-internalCtx.Done():
+								return
+							case <-time.After(wait):
+							}
 						}
 					} else {
+						reconnectBackoff.reset()
+
 						// Consume() returned successfully - this is normal (rebalance, coordinator change, etc.)
 						// Continue looping to call Consume() again
 						k.Config.Logger.Debugf("[kafka] Consumer for group %s Consume() completed successfully after %v", k.Config.ConsumerGroupID, consumeDuration)
@@ -920,6 +943,51 @@ func (k *KafkaConsumerGroup) Start(ctx context.Context, consumerFn func(message
 	}()
 }
 
+// reconnectBackoff computes the time to wait between reconnection attempts of a consumer,
+// doubling the wait after every failed attempt up to a maximum.
+type reconnectBackoff struct {
+	initial time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+// newReconnectBackoff creates the reconnection backoff from the consumer configuration.
+func (k *KafkaConsumerGroup) newReconnectBackoff() *reconnectBackoff {
+	initial := k.Config.ReconnectBackoff
+	if initial <= 0 {
+		initial = 5 * time.Second
+	}
+
+	maxBackoff := k.Config.MaxReconnectBackoff
+	if maxBackoff < initial {
+		maxBackoff = initial
+	}
+
+	return &reconnectBackoff{
+		initial: initial,
+		max:     maxBackoff,
+	}
+}
+
+// next returns the time to wait before the next reconnection attempt.
+func (b *reconnectBackoff) next() time.Duration {
+	if b.current == 0 {
+		b.current = b.initial
+	} else {
+		b.current *= 2
+		if b.current > b.max {
+			b.current = b.max
+		}
+	}
+
+	return b.current
+}
+
+// reset resets the backoff after a successful attempt.
+func (b *reconnectBackoff) reset() {
+	b.current = 0
+}
+
 func (k *KafkaConsumerGroup) BrokersURL() []string {
 	return k.Config.BrokersURL
 }
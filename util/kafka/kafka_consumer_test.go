@@ -3,11 +3,13 @@ package kafka
 import (
 	"context"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -567,3 +569,99 @@ func TestForceRecovery_MutexProtectsConcurrentCalls(t *testing.T) {
 	// Should have closed the consumer (at least once)
 	assert.True(t, mockConsumerGroup.closed)
 }
+
+// unavailableBrokerConsumerGroup simulates brokers that are unavailable for the first failures calls to Consume()
+type unavailableBrokerConsumerGroup struct {
+	mockSaramaConsumerGroup
+	mu        sync.Mutex
+	failures  int
+	attempts  int
+	connected chan struct{}
+}
+
+// Consume implements sarama.ConsumerGroup interface
+func (m *unavailableBrokerConsumerGroup) Consume(ctx context.Context, _ []string, _ sarama.ConsumerGroupHandler) error {
+	m.mu.Lock()
+	m.attempts++
+	attempt := m.attempts
+	m.mu.Unlock()
+
+	if attempt <= m.failures {
+		return sarama.ErrOutOfBrokers
+	}
+
+	if attempt == m.failures+1 {
+		close(m.connected)
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestKafkaConsumerGroup_ReconnectsAfterBrokerUnavailable(t *testing.T) {
+	InitPrometheusMetrics()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumerGroup := &unavailableBrokerConsumerGroup{
+		failures:  3,
+		connected: make(chan struct{}),
+	}
+
+	consumer := &KafkaConsumerGroup{
+		Config: KafkaConsumerConfig{
+			Logger:              ulogger.TestLogger{},
+			Topic:               "test-reconnect-topic",
+			ConsumerGroupID:     "test-reconnect-group",
+			BrokersURL:          []string{"localhost:9092"},
+			ReconnectBackoff:    10 * time.Millisecond,
+			MaxReconnectBackoff: 20 * time.Millisecond,
+		},
+		ConsumerGroup: consumerGroup,
+		watchdog:      &consumeWatchdog{},
+	}
+
+	reconnects := prometheusKafkaConsumerReconnects.WithLabelValues("test-reconnect-topic", "test-reconnect-group")
+	reconnectsBefore := testutil.ToFloat64(reconnects)
+
+	consumer.Start(ctx, func(*KafkaMessage) error { return nil })
+
+	select {
+	case <-consumerGroup.connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("consumer did not reconnect after the brokers became available")
+	}
+
+	consumerGroup.mu.Lock()
+	assert.Equal(t, 4, consumerGroup.attempts)
+	consumerGroup.mu.Unlock()
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(reconnects)-reconnectsBefore)
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	consumer := &KafkaConsumerGroup{
+		Config: KafkaConsumerConfig{
+			ReconnectBackoff:    time.Second,
+			MaxReconnectBackoff: 5 * time.Second,
+		},
+	}
+
+	backoff := consumer.newReconnectBackoff()
+
+	assert.Equal(t, time.Second, backoff.next())
+	assert.Equal(t, 2*time.Second, backoff.next())
+	assert.Equal(t, 4*time.Second, backoff.next())
+	assert.Equal(t, 5*time.Second, backoff.next())
+	assert.Equal(t, 5*time.Second, backoff.next())
+
+	backoff.reset()
+	assert.Equal(t, time.Second, backoff.next())
+
+	// defaults when not configured
+	backoff = (&KafkaConsumerGroup{}).newReconnectBackoff()
+	assert.Equal(t, 5*time.Second, backoff.next())
+	assert.Equal(t, 5*time.Second, backoff.next())
+}
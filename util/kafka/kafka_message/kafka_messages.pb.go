@@ -119,6 +119,58 @@ func (InvType) EnumDescriptor() ([]byte, []int) {
 	return file_util_kafka_kafka_message_kafka_messages_proto_rawDescGZIP(), []int{1}
 }
 
+type SubtreeValidationOutcome int32
+
+const (
+	SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_VALID     SubtreeValidationOutcome = 0
+	SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_INVALID   SubtreeValidationOutcome = 1
+	SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_ERROR     SubtreeValidationOutcome = 2
+	SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_CANCELLED SubtreeValidationOutcome = 3
+)
+
+// Enum value maps for SubtreeValidationOutcome.
+var (
+	SubtreeValidationOutcome_name = map[int32]string{
+		0: "SUBTREE_VALIDATION_OUTCOME_VALID",
+		1: "SUBTREE_VALIDATION_OUTCOME_INVALID",
+		2: "SUBTREE_VALIDATION_OUTCOME_ERROR",
+		3: "SUBTREE_VALIDATION_OUTCOME_CANCELLED",
+	}
+	SubtreeValidationOutcome_value = map[string]int32{
+		"SUBTREE_VALIDATION_OUTCOME_VALID":     0,
+		"SUBTREE_VALIDATION_OUTCOME_INVALID":   1,
+		"SUBTREE_VALIDATION_OUTCOME_ERROR":     2,
+		"SUBTREE_VALIDATION_OUTCOME_CANCELLED": 3,
+	}
+)
+
+func (x SubtreeValidationOutcome) Enum() *SubtreeValidationOutcome {
+	p := new(SubtreeValidationOutcome)
+	*p = x
+	return p
+}
+
+func (x SubtreeValidationOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubtreeValidationOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_util_kafka_kafka_message_kafka_messages_proto_enumTypes[2].Descriptor()
+}
+
+func (SubtreeValidationOutcome) Type() protoreflect.EnumType {
+	return &file_util_kafka_kafka_message_kafka_messages_proto_enumTypes[2]
+}
+
+func (x SubtreeValidationOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubtreeValidationOutcome.Descriptor instead.
+func (SubtreeValidationOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_util_kafka_kafka_message_kafka_messages_proto_rawDescGZIP(), []int{2}
+}
+
 type KafkaBlockTopicMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
@@ -787,6 +839,166 @@ func (x *KafkaBlocksFinalTopicMessage) GetHeight() uint32 {
 	return 0
 }
 
+type KafkaTxValidationResultTopicMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxHash        string                 `protobuf:"bytes,1,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	Valid         bool                   `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`        // Empty when the transaction is valid
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp in milliseconds of the validation
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KafkaTxValidationResultTopicMessage) Reset() {
+	*x = KafkaTxValidationResultTopicMessage{}
+	mi := &file_util_kafka_kafka_message_kafka_messages_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KafkaTxValidationResultTopicMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KafkaTxValidationResultTopicMessage) ProtoMessage() {}
+
+func (x *KafkaTxValidationResultTopicMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_util_kafka_kafka_message_kafka_messages_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KafkaTxValidationResultTopicMessage.ProtoReflect.Descriptor instead.
+func (*KafkaTxValidationResultTopicMessage) Descriptor() ([]byte, []int) {
+	return file_util_kafka_kafka_message_kafka_messages_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *KafkaTxValidationResultTopicMessage) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *KafkaTxValidationResultTopicMessage) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *KafkaTxValidationResultTopicMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *KafkaTxValidationResultTopicMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type KafkaSubtreeValidationResultTopicMessage struct {
+	state          protoimpl.MessageState   `protogen:"open.v1"`
+	SubtreeHash    string                   `protobuf:"bytes,1,opt,name=subtreeHash,proto3" json:"subtreeHash,omitempty"`
+	TxCount        uint64                   `protobuf:"varint,2,opt,name=txCount,proto3" json:"txCount,omitempty"`
+	Valid          bool                     `protobuf:"varint,3,opt,name=valid,proto3" json:"valid,omitempty"`
+	Reason         string                   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                               // Empty when the subtree is valid
+	DurationMillis int64                    `protobuf:"varint,5,opt,name=durationMillis,proto3" json:"durationMillis,omitempty"`                              // Duration of the validation in milliseconds
+	Timestamp      int64                    `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                        // Unix timestamp in milliseconds of the validation
+	Outcome        SubtreeValidationOutcome `protobuf:"varint,7,opt,name=outcome,proto3,enum=kafkamessage.SubtreeValidationOutcome" json:"outcome,omitempty"` // Outcome of the validation
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) Reset() {
+	*x = KafkaSubtreeValidationResultTopicMessage{}
+	mi := &file_util_kafka_kafka_message_kafka_messages_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KafkaSubtreeValidationResultTopicMessage) ProtoMessage() {}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_util_kafka_kafka_message_kafka_messages_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KafkaSubtreeValidationResultTopicMessage.ProtoReflect.Descriptor instead.
+func (*KafkaSubtreeValidationResultTopicMessage) Descriptor() ([]byte, []int) {
+	return file_util_kafka_kafka_message_kafka_messages_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) GetSubtreeHash() string {
+	if x != nil {
+		return x.SubtreeHash
+	}
+	return ""
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) GetTxCount() uint64 {
+	if x != nil {
+		return x.TxCount
+	}
+	return 0
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) GetDurationMillis() int64 {
+	if x != nil {
+		return x.DurationMillis
+	}
+	return 0
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *KafkaSubtreeValidationResultTopicMessage) GetOutcome() SubtreeValidationOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return SubtreeValidationOutcome_SUBTREE_VALIDATION_OUTCOME_VALID
+}
+
 var File_util_kafka_kafka_message_kafka_messages_proto protoreflect.FileDescriptor
 
 const file_util_kafka_kafka_message_kafka_messages_proto_rawDesc = "" +
@@ -837,7 +1049,20 @@ const file_util_kafka_kafka_message_kafka_messages_proto_rawDesc = "" +
 	"\x0esubtree_hashes\x18\x04 \x03(\fR\rsubtreeHashes\x12\x1f\n" +
 	"\vcoinbase_tx\x18\x05 \x01(\fR\n" +
 	"coinbaseTx\x12\x16\n" +
-	"\x06height\x18\x06 \x01(\rR\x06height*,\n" +
+	"\x06height\x18\x06 \x01(\rR\x06height\"\x89\x01\n" +
+	"#KafkaTxValidationResultTopicMessage\x12\x16\n" +
+	"\x06txHash\x18\x01 \x01(\tR\x06txHash\x12\x14\n" +
+	"\x05valid\x18\x02 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\"\x9c\x02\n" +
+	"(KafkaSubtreeValidationResultTopicMessage\x12 \n" +
+	"\vsubtreeHash\x18\x01 \x01(\tR\vsubtreeHash\x12\x18\n" +
+	"\atxCount\x18\x02 \x01(\x04R\atxCount\x12\x14\n" +
+	"\x05valid\x18\x03 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12&\n" +
+	"\x0edurationMillis\x18\x05 \x01(\x03R\x0edurationMillis\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\x12@\n" +
+	"\aoutcome\x18\a \x01(\x0e2&.kafkamessage.SubtreeValidationOutcomeR\aoutcome*,\n" +
 	"\x15KafkaTxMetaActionType\x12\a\n" +
 	"\x03ADD\x10\x00\x12\n" +
 	"\n" +
@@ -846,7 +1071,12 @@ const file_util_kafka_kafka_message_kafka_messages_proto_rawDesc = "" +
 	"\x05Error\x10\x00\x12\x06\n" +
 	"\x02Tx\x10\x01\x12\t\n" +
 	"\x05Block\x10\x02\x12\x11\n" +
-	"\rFilteredBlock\x10\x03B\x11Z\x0f./;kafkamessageb\x06proto3"
+	"\rFilteredBlock\x10\x03*\xb8\x01\n" +
+	"\x18SubtreeValidationOutcome\x12$\n" +
+	" SUBTREE_VALIDATION_OUTCOME_VALID\x10\x00\x12&\n" +
+	"\"SUBTREE_VALIDATION_OUTCOME_INVALID\x10\x01\x12$\n" +
+	" SUBTREE_VALIDATION_OUTCOME_ERROR\x10\x02\x12(\n" +
+	"$SUBTREE_VALIDATION_OUTCOME_CANCELLED\x10\x03B\x11Z\x0f./;kafkamessageb\x06proto3"
 
 var (
 	file_util_kafka_kafka_message_kafka_messages_proto_rawDescOnce sync.Once
@@ -860,33 +1090,37 @@ func file_util_kafka_kafka_message_kafka_messages_proto_rawDescGZIP() []byte {
 	return file_util_kafka_kafka_message_kafka_messages_proto_rawDescData
 }
 
-var file_util_kafka_kafka_message_kafka_messages_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_util_kafka_kafka_message_kafka_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_util_kafka_kafka_message_kafka_messages_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_util_kafka_kafka_message_kafka_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_util_kafka_kafka_message_kafka_messages_proto_goTypes = []any{
-	(KafkaTxMetaActionType)(0),              // 0: kafkamessage.KafkaTxMetaActionType
-	(InvType)(0),                            // 1: kafkamessage.InvType
-	(*KafkaBlockTopicMessage)(nil),          // 2: kafkamessage.KafkaBlockTopicMessage
-	(*KafkaInvalidBlockTopicMessage)(nil),   // 3: kafkamessage.KafkaInvalidBlockTopicMessage
-	(*KafkaInvalidSubtreeTopicMessage)(nil), // 4: kafkamessage.KafkaInvalidSubtreeTopicMessage
-	(*KafkaSubtreeTopicMessage)(nil),        // 5: kafkamessage.KafkaSubtreeTopicMessage
-	(*KafkaTxValidationTopicMessage)(nil),   // 6: kafkamessage.KafkaTxValidationTopicMessage
-	(*KafkaTxValidationOptions)(nil),        // 7: kafkamessage.KafkaTxValidationOptions
-	(*KafkaRejectedTxTopicMessage)(nil),     // 8: kafkamessage.KafkaRejectedTxTopicMessage
-	(*KafkaTxMetaTopicMessage)(nil),         // 9: kafkamessage.KafkaTxMetaTopicMessage
-	(*KafkaInvTopicMessage)(nil),            // 10: kafkamessage.KafkaInvTopicMessage
-	(*Inv)(nil),                             // 11: kafkamessage.Inv
-	(*KafkaBlocksFinalTopicMessage)(nil),    // 12: kafkamessage.KafkaBlocksFinalTopicMessage
+	(KafkaTxMetaActionType)(0),                       // 0: kafkamessage.KafkaTxMetaActionType
+	(InvType)(0),                                     // 1: kafkamessage.InvType
+	(SubtreeValidationOutcome)(0),                    // 2: kafkamessage.SubtreeValidationOutcome
+	(*KafkaBlockTopicMessage)(nil),                   // 3: kafkamessage.KafkaBlockTopicMessage
+	(*KafkaInvalidBlockTopicMessage)(nil),            // 4: kafkamessage.KafkaInvalidBlockTopicMessage
+	(*KafkaInvalidSubtreeTopicMessage)(nil),          // 5: kafkamessage.KafkaInvalidSubtreeTopicMessage
+	(*KafkaSubtreeTopicMessage)(nil),                 // 6: kafkamessage.KafkaSubtreeTopicMessage
+	(*KafkaTxValidationTopicMessage)(nil),            // 7: kafkamessage.KafkaTxValidationTopicMessage
+	(*KafkaTxValidationOptions)(nil),                 // 8: kafkamessage.KafkaTxValidationOptions
+	(*KafkaRejectedTxTopicMessage)(nil),              // 9: kafkamessage.KafkaRejectedTxTopicMessage
+	(*KafkaTxMetaTopicMessage)(nil),                  // 10: kafkamessage.KafkaTxMetaTopicMessage
+	(*KafkaInvTopicMessage)(nil),                     // 11: kafkamessage.KafkaInvTopicMessage
+	(*Inv)(nil),                                      // 12: kafkamessage.Inv
+	(*KafkaBlocksFinalTopicMessage)(nil),             // 13: kafkamessage.KafkaBlocksFinalTopicMessage
+	(*KafkaTxValidationResultTopicMessage)(nil),      // 14: kafkamessage.KafkaTxValidationResultTopicMessage
+	(*KafkaSubtreeValidationResultTopicMessage)(nil), // 15: kafkamessage.KafkaSubtreeValidationResultTopicMessage
 }
 var file_util_kafka_kafka_message_kafka_messages_proto_depIdxs = []int32{
-	7,  // 0: kafkamessage.KafkaTxValidationTopicMessage.options:type_name -> kafkamessage.KafkaTxValidationOptions
+	8,  // 0: kafkamessage.KafkaTxValidationTopicMessage.options:type_name -> kafkamessage.KafkaTxValidationOptions
 	0,  // 1: kafkamessage.KafkaTxMetaTopicMessage.action:type_name -> kafkamessage.KafkaTxMetaActionType
-	11, // 2: kafkamessage.KafkaInvTopicMessage.inv:type_name -> kafkamessage.Inv
+	12, // 2: kafkamessage.KafkaInvTopicMessage.inv:type_name -> kafkamessage.Inv
 	1,  // 3: kafkamessage.Inv.type:type_name -> kafkamessage.InvType
-	4,  // [4:4] is the sub-list for method output_type
-	4,  // [4:4] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	2,  // 4: kafkamessage.KafkaSubtreeValidationResultTopicMessage.outcome:type_name -> kafkamessage.SubtreeValidationOutcome
+	5,  // [5:5] is the sub-list for method output_type
+	5,  // [5:5] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_util_kafka_kafka_message_kafka_messages_proto_init() }
@@ -899,8 +1133,8 @@ func file_util_kafka_kafka_message_kafka_messages_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_util_kafka_kafka_message_kafka_messages_proto_rawDesc), len(file_util_kafka_kafka_message_kafka_messages_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   11,
+			NumEnums:      3,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -60,6 +60,11 @@ type KafkaProducerConfig struct {
 	FlushMessages         int            // Number of messages before flush
 	FlushFrequency        time.Duration  // Time between flushes
 
+	// Broker unavailability handling (query params: retry_buffer_size, retry_backoff, max_retry_backoff)
+	RetryBufferSize int           // Max number of undelivered messages held for redelivery, 0 (default) drops undelivered messages
+	RetryBackoff    time.Duration // Initial time to wait before redelivering undelivered messages
+	MaxRetryBackoff time.Duration // Max time to wait before redelivering, the backoff doubles while delivery keeps failing
+
 	// TLS/Authentication configuration
 	EnableTLS     bool   // Enable TLS for Kafka connection
 	TLSSkipVerify bool   // Skip TLS certificate verification (for testing)
@@ -78,10 +83,11 @@ type MessageStatus struct {
 	Time    time.Time
 }
 
-// Message represents a Kafka message with key and value.
+// Message represents a Kafka message with key, value and optional headers.
 type Message struct {
-	Key   []byte
-	Value []byte
+	Key     []byte
+	Value   []byte
+	Headers []sarama.RecordHeader
 }
 
 // KafkaAsyncProducer implements asynchronous Kafka producer functionality.
@@ -92,6 +98,11 @@ type KafkaAsyncProducer struct {
 	closed         atomic.Bool          // Flag indicating if producer is closed
 	channelMu      sync.RWMutex         // Mutex to protect publishChannel access
 	publishWg      sync.WaitGroup       // WaitGroup to track publish goroutine
+
+	// retryBuffer holds messages that could not be delivered, e.g. because the brokers are unavailable,
+	// until they are redelivered. While it is full, publishing blocks, applying backpressure to the callers.
+	// It is nil when redelivery is disabled.
+	retryBuffer *retryBuffer
 }
 
 // NewKafkaAsyncProducerFromURL creates a new async producer from a URL configuration.
@@ -142,6 +153,9 @@ func NewKafkaAsyncProducerFromURL(ctx context.Context, logger ulogger.Logger, ur
 		FlushBytes:            util.GetQueryParamInt(url, "flush_bytes", 1024*1024),
 		FlushMessages:         util.GetQueryParamInt(url, "flush_messages", 50_000),
 		FlushFrequency:        util.GetQueryParamDuration(url, "flush_frequency", 10*time.Second),
+		RetryBufferSize:       util.GetQueryParamInt(url, "retry_buffer_size", 0),
+		RetryBackoff:          util.GetQueryParamDuration(url, "retry_backoff", time.Second),
+		MaxRetryBackoff:       util.GetQueryParamDuration(url, "max_retry_backoff", 30*time.Second),
 		// TLS/Auth configuration
 		EnableTLS:          enableTLS,
 		TLSSkipVerify:      tlsSkipVerify,
@@ -174,6 +188,8 @@ func NewKafkaAsyncProducerFromURL(ctx context.Context, logger ulogger.Logger, ur
 func NewKafkaAsyncProducer(logger ulogger.Logger, cfg KafkaProducerConfig) (*KafkaAsyncProducer, error) {
 	logger.Debugf("Starting async kafka producer for %v", cfg.URL)
 
+	InitPrometheusMetrics()
+
 	if cfg.URL.Scheme == memoryScheme {
 		// --- Use the in-memory implementation ---
 		broker := inmemorykafka.GetSharedBroker() // Use alias 'imk'
@@ -185,8 +201,9 @@ func NewKafkaAsyncProducer(logger ulogger.Logger, cfg KafkaProducerConfig) (*Kaf
 		// No error expected from mock creation
 
 		client := &KafkaAsyncProducer{
-			Producer: producer,
-			Config:   cfg,
+			Producer:    producer,
+			Config:      cfg,
+			retryBuffer: newRetryBuffer(cfg),
 		}
 
 		return client, nil
@@ -242,13 +259,92 @@ func NewKafkaAsyncProducer(logger ulogger.Logger, cfg KafkaProducerConfig) (*Kaf
 	}
 
 	client := &KafkaAsyncProducer{
-		Producer: producer,
-		Config:   cfg,
+		Producer:    producer,
+		Config:      cfg,
+		retryBuffer: newRetryBuffer(cfg),
 	}
 
 	return client, nil
 }
 
+// retryBuffer holds the undelivered messages of a producer until they are redelivered. Publishers wait on
+// the condition while the buffer is full, they are woken when messages are taken out for redelivery or the
+// buffer is closed.
+type retryBuffer struct {
+	messages chan *sarama.ProducerMessage
+	mu       sync.Mutex
+	cond     *sync.Cond
+	closed   bool
+}
+
+// newRetryBuffer creates the buffer for undelivered messages, returns nil when redelivery is disabled.
+func newRetryBuffer(cfg KafkaProducerConfig) *retryBuffer {
+	if cfg.RetryBufferSize <= 0 {
+		return nil
+	}
+
+	b := &retryBuffer{
+		messages: make(chan *sarama.ProducerMessage, cfg.RetryBufferSize),
+	}
+
+	b.cond = sync.NewCond(&b.mu)
+
+	return b
+}
+
+// add adds a message to the buffer, returns false when the buffer is full.
+func (b *retryBuffer) add(msg *sarama.ProducerMessage) bool {
+	select {
+	case b.messages <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// take takes the given number of messages out of the buffer, waking the publishers waiting for space.
+func (b *retryBuffer) take(n int) []*sarama.ProducerMessage {
+	msgs := make([]*sarama.ProducerMessage, 0, n)
+
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-b.messages:
+			msgs = append(msgs, msg)
+		default:
+		}
+	}
+
+	// taking the lock orders the wake up after the check of a publisher about to wait, so it is not missed
+	b.mu.Lock()
+	b.cond.Broadcast()
+	b.mu.Unlock()
+
+	return msgs
+}
+
+// waitForSpace blocks while the buffer is full, until messages are taken out or the buffer is closed.
+func (b *retryBuffer) waitForSpace() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for len(b.messages) >= cap(b.messages) && !b.closed {
+		b.cond.Wait()
+	}
+}
+
+// close wakes the publishers waiting for space, they do not wait anymore.
+func (b *retryBuffer) close() {
+	b.mu.Lock()
+	b.closed = true
+	b.cond.Broadcast()
+	b.mu.Unlock()
+}
+
+// len returns the number of messages in the buffer.
+func (b *retryBuffer) len() int {
+	return len(b.messages)
+}
+
 func (c *KafkaAsyncProducer) decodeKeyOrValue(encoder sarama.Encoder) string {
 	if encoder == nil {
 		return ""
@@ -300,9 +396,15 @@ func (c *KafkaAsyncProducer) Start(ctx context.Context, ch chan *Message) {
 
 				c.Config.Logger.Errorf("Failed to deliver message to topic %s: %v, Key: %v, Value: %v",
 					err.Msg.Topic, err.Err, key, value)
+
+				c.bufferForRetry(err.Msg)
 			}
 		}()
 
+		if c.retryBuffer != nil {
+			go c.redeliverMessages(context)
+		}
+
 		go func() {
 			defer c.publishWg.Done()
 			wg.Done()
@@ -322,27 +424,22 @@ func (c *KafkaAsyncProducer) Start(ctx context.Context, ch chan *Message) {
 				}
 
 				message := &sarama.ProducerMessage{
-					Topic: c.Config.Topic,
-					Key:   key,
-					Value: sarama.ByteEncoder(msgBytes.Value),
+					Topic:   c.Config.Topic,
+					Key:     key,
+					Value:   sarama.ByteEncoder(msgBytes.Value),
+					Headers: msgBytes.Headers,
 				}
 
+				// Apply backpressure while undelivered messages are waiting for the brokers to become available
+				c.waitForRetryBuffer()
+
 				// Check if closed again right before sending to avoid race condition
 				// where Close() is called between the check above and the send below
 				if c.closed.Load() {
 					break
 				}
 
-				// Use a function with recover to safely handle sends to potentially closed channel
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							// Channel was closed during send, this is expected during shutdown
-							c.Config.Logger.Debugf("[kafka] Recovered from send to closed channel during shutdown")
-						}
-					}()
-					c.Producer.Input() <- message
-				}()
+				c.send(message)
 			}
 		}()
 
@@ -368,6 +465,105 @@ func (c *KafkaAsyncProducer) Start(ctx context.Context, ch chan *Message) {
 	wg.Wait() // don't continue until we know we know the go func has started and is ready to accept messages on the PublishChannel
 }
 
+// send sends a message to the underlying producer.
+func (c *KafkaAsyncProducer) send(message *sarama.ProducerMessage) {
+	// Use a function with recover to safely handle sends to potentially closed channel
+	defer func() {
+		if r := recover(); r != nil {
+			// Channel was closed during send, this is expected during shutdown
+			c.Config.Logger.Debugf("[kafka] Recovered from send to closed channel during shutdown")
+		}
+	}()
+
+	c.Producer.Input() <- message
+}
+
+// bufferForRetry holds an undelivered message for redelivery. The message is dropped when redelivery is
+// disabled, the producer is shutting down or the retry buffer is full.
+func (c *KafkaAsyncProducer) bufferForRetry(msg *sarama.ProducerMessage) {
+	if c.retryBuffer == nil || c.closed.Load() {
+		return
+	}
+
+	// copy the message, Sarama does not support producing the same message twice
+	retryMsg := &sarama.ProducerMessage{
+		Topic:    msg.Topic,
+		Key:      msg.Key,
+		Value:    msg.Value,
+		Headers:  msg.Headers,
+		Metadata: msg.Metadata,
+	}
+
+	if c.retryBuffer.add(retryMsg) {
+		prometheusKafkaProducerBufferedMessages.WithLabelValues(c.Config.Topic).Inc()
+	} else {
+		prometheusKafkaProducerDroppedMessages.WithLabelValues(c.Config.Topic).Inc()
+		c.Config.Logger.Errorf("[kafka] retry buffer for topic %s is full (%d messages), dropping undelivered message", c.Config.Topic, cap(c.retryBuffer.messages))
+	}
+}
+
+// waitForRetryBuffer blocks while the retry buffer is full, until messages have been redelivered
+// or the producer is stopped.
+func (c *KafkaAsyncProducer) waitForRetryBuffer() {
+	if c.retryBuffer == nil {
+		return
+	}
+
+	c.retryBuffer.waitForSpace()
+}
+
+// redeliverMessages periodically sends the undelivered messages to the brokers again. The time between
+// attempts doubles, up to MaxRetryBackoff, while messages keep failing and is reset once all messages
+// have been delivered.
+func (c *KafkaAsyncProducer) redeliverMessages(ctx context.Context) {
+	initialBackoff := c.Config.RetryBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = time.Second
+	}
+
+	maxBackoff := c.Config.MaxRetryBackoff
+	if maxBackoff < initialBackoff {
+		maxBackoff = initialBackoff
+	}
+
+	backoff := initialBackoff
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if c.closed.Load() {
+			return
+		}
+
+		pending := c.retryBuffer.len()
+		if pending == 0 {
+			backoff = initialBackoff
+			continue
+		}
+
+		c.Config.Logger.Infof("[kafka] redelivering %d undelivered messages to topic %s", pending, c.Config.Topic)
+
+		for _, msg := range c.retryBuffer.take(pending) {
+			prometheusKafkaProducerBufferedMessages.WithLabelValues(c.Config.Topic).Dec()
+
+			if c.closed.Load() {
+				return
+			}
+
+			c.send(msg)
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
 // Stop gracefully shuts down the async producer.
 func (c *KafkaAsyncProducer) Stop() error {
 	if c == nil {
@@ -380,6 +576,11 @@ func (c *KafkaAsyncProducer) Stop() error {
 
 	c.closed.Store(true)
 
+	// Wake the publish goroutine when it is waiting for space in the retry buffer
+	if c.retryBuffer != nil {
+		c.retryBuffer.close()
+	}
+
 	// Close the publish channel to signal the publish goroutine to exit
 	c.channelMu.Lock()
 	if c.publishChannel != nil {
@@ -3,11 +3,13 @@ package kafka
 import (
 	"context"
 	"net/url"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -442,3 +444,189 @@ func TestKafkaAsyncProducerStopBeforeStart(t *testing.T) {
 	err = producer.Stop()
 	assert.NoError(t, err)
 }
+
+// unavailableBrokerProducer simulates brokers that fail every message until they become available
+type unavailableBrokerProducer struct {
+	sarama.AsyncProducer
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errors    chan *sarama.ProducerError
+	available atomic.Bool
+	attempts  atomic.Int32
+	delivered chan *sarama.ProducerMessage
+	done      chan struct{}
+}
+
+func newUnavailableBrokerProducer() *unavailableBrokerProducer {
+	p := &unavailableBrokerProducer{
+		input:     make(chan *sarama.ProducerMessage),
+		successes: make(chan *sarama.ProducerMessage),
+		errors:    make(chan *sarama.ProducerError, 100),
+		delivered: make(chan *sarama.ProducerMessage, 100),
+		done:      make(chan struct{}),
+	}
+
+	go func() {
+		defer close(p.done)
+
+		for msg := range p.input {
+			p.attempts.Add(1)
+
+			if p.available.Load() {
+				p.delivered <- msg
+			} else {
+				p.errors <- &sarama.ProducerError{Msg: msg, Err: sarama.ErrOutOfBrokers}
+			}
+		}
+	}()
+
+	return p
+}
+
+func (p *unavailableBrokerProducer) Input() chan<- *sarama.ProducerMessage     { return p.input }
+func (p *unavailableBrokerProducer) Successes() <-chan *sarama.ProducerMessage { return p.successes }
+func (p *unavailableBrokerProducer) Errors() <-chan *sarama.ProducerError      { return p.errors }
+func (p *unavailableBrokerProducer) Close() error {
+	close(p.input)
+	<-p.done
+	close(p.successes)
+	close(p.errors)
+
+	return nil
+}
+
+func TestKafkaAsyncProducer_BrokerUnavailable(t *testing.T) {
+	InitPrometheusMetrics()
+
+	newProducer := func(topic string, retryBufferSize int, retryBackoff time.Duration) (*KafkaAsyncProducer, *unavailableBrokerProducer) {
+		broker := newUnavailableBrokerProducer()
+
+		cfg := KafkaProducerConfig{
+			Logger:          ulogger.TestLogger{},
+			Topic:           topic,
+			RetryBufferSize: retryBufferSize,
+			RetryBackoff:    retryBackoff,
+			MaxRetryBackoff: 2 * retryBackoff,
+		}
+
+		return &KafkaAsyncProducer{
+			Producer:    broker,
+			Config:      cfg,
+			retryBuffer: newRetryBuffer(cfg),
+		}, broker
+	}
+
+	t.Run("messages are redelivered when the brokers recover", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		producer, broker := newProducer("test-redeliver-topic", 10, 10*time.Millisecond)
+		producer.Start(ctx, make(chan *Message, 10))
+
+		for i := 0; i < 3; i++ {
+			producer.Publish(&Message{Value: []byte{byte(i)}})
+		}
+
+		// the brokers are unavailable, every message fails and is retried
+		require.Eventually(t, func() bool {
+			return broker.attempts.Load() > 6
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Empty(t, broker.delivered)
+
+		broker.available.Store(true)
+
+		delivered := make(map[byte]bool)
+
+		for len(delivered) < 3 {
+			select {
+			case msg := <-broker.delivered:
+				value, err := msg.Value.Encode()
+				require.NoError(t, err)
+
+				delivered[value[0]] = true
+			case <-time.After(5 * time.Second):
+				t.Fatalf("only %d of 3 messages were delivered after the brokers recovered", len(delivered))
+			}
+		}
+
+		require.NoError(t, producer.Stop())
+		assert.Equal(t, float64(0), testutil.ToFloat64(prometheusKafkaProducerBufferedMessages.WithLabelValues("test-redeliver-topic")))
+	})
+
+	t.Run("publishing blocks while the retry buffer is full", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		producer, broker := newProducer("test-backpressure-topic", 2, time.Hour)
+		producer.Start(ctx, make(chan *Message, 10))
+
+		for i := 0; i < 4; i++ {
+			producer.Publish(&Message{Value: []byte{byte(i)}})
+		}
+
+		require.Eventually(t, func() bool {
+			return producer.retryBuffer.len() == 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// no more messages are sent to the unavailable brokers until the buffer has been drained
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(2), broker.attempts.Load())
+
+		// undelivered messages that do not fit in the buffer are dropped
+		dropped := prometheusKafkaProducerDroppedMessages.WithLabelValues("test-backpressure-topic")
+		droppedBefore := testutil.ToFloat64(dropped)
+
+		producer.bufferForRetry(&sarama.ProducerMessage{Topic: "test-backpressure-topic", Value: sarama.ByteEncoder{0xff}})
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(dropped)-droppedBefore)
+		assert.Equal(t, 2, producer.retryBuffer.len())
+
+		require.NoError(t, producer.Stop())
+	})
+
+	t.Run("undelivered messages keep their headers", func(t *testing.T) {
+		producer, _ := newProducer("test-headers-topic", 1, time.Hour)
+
+		headers := []sarama.RecordHeader{{Key: []byte("key"), Value: []byte("value")}}
+		producer.bufferForRetry(&sarama.ProducerMessage{Topic: "test-headers-topic", Value: sarama.ByteEncoder{0xff}, Headers: headers})
+
+		msgs := producer.retryBuffer.take(1)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, headers, msgs[0].Headers)
+	})
+
+	t.Run("waiting publisher is woken when messages are taken out", func(t *testing.T) {
+		producer, _ := newProducer("test-wake-topic", 1, time.Hour)
+		producer.bufferForRetry(&sarama.ProducerMessage{Topic: "test-wake-topic", Value: sarama.ByteEncoder{0xff}})
+
+		waited := make(chan struct{})
+
+		go func() {
+			producer.waitForRetryBuffer()
+			close(waited)
+		}()
+
+		select {
+		case <-waited:
+			t.Fatal("publisher did not wait while the retry buffer is full")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		producer.retryBuffer.take(1)
+
+		select {
+		case <-waited:
+		case <-time.After(5 * time.Second):
+			t.Fatal("publisher was not woken after messages were taken out of the retry buffer")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		producer, _ := newProducer("test-disabled-topic", 0, time.Millisecond)
+		require.Nil(t, producer.retryBuffer)
+
+		// undelivered messages are dropped without being buffered
+		producer.bufferForRetry(&sarama.ProducerMessage{Topic: "test-disabled-topic", Value: sarama.ByteEncoder{0xff}})
+		producer.waitForRetryBuffer()
+	})
+}
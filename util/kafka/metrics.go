@@ -19,6 +19,7 @@ import (
 // Metric Categories:
 //   - Watchdog metrics: Recovery attempts and stuck consumer detection
 //   - Duration metrics: Time spent in various consumer states
+//   - Broker unavailability metrics: Consumer reconnections and producer buffered/dropped messages
 var (
 	// prometheusKafkaWatchdogRecoveryAttempts counts the number of times the watchdog
 	// triggered a force recovery due to a stuck Consume() call.
@@ -33,6 +34,21 @@ var (
 	// This histogram measures the duration between when Consume() was called and
 	// when the watchdog detected it as stuck, helping diagnose consumer hangs.
 	prometheusKafkaWatchdogStuckDuration *prometheus.HistogramVec
+
+	// prometheusKafkaConsumerReconnects counts the number of times a consumer reconnected after
+	// Consume() failed, e.g. because the brokers were unavailable.
+	// Labels: topic, consumer_group
+	prometheusKafkaConsumerReconnects *prometheus.CounterVec
+
+	// prometheusKafkaProducerBufferedMessages tracks the number of undelivered messages held by a
+	// producer for redelivery once the brokers are available again.
+	// Labels: topic
+	prometheusKafkaProducerBufferedMessages *prometheus.GaugeVec
+
+	// prometheusKafkaProducerDroppedMessages counts the undelivered messages that were dropped because
+	// the retry buffer of the producer was full.
+	// Labels: topic
+	prometheusKafkaProducerDroppedMessages *prometheus.CounterVec
 )
 
 var (
@@ -66,4 +82,34 @@ func _initPrometheusMetrics() {
 		},
 		[]string{"topic"},
 	)
+
+	prometheusKafkaConsumerReconnects = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "kafka",
+			Name:      "consumer_reconnects_total",
+			Help:      "Number of times a consumer reconnected after failing to consume, e.g. when the brokers were unavailable",
+		},
+		[]string{"topic", "consumer_group"},
+	)
+
+	prometheusKafkaProducerBufferedMessages = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "teranode",
+			Subsystem: "kafka",
+			Name:      "producer_buffered_messages",
+			Help:      "Number of undelivered messages buffered by the producer for redelivery",
+		},
+		[]string{"topic"},
+	)
+
+	prometheusKafkaProducerDroppedMessages = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "teranode",
+			Subsystem: "kafka",
+			Name:      "producer_dropped_messages_total",
+			Help:      "Number of undelivered messages dropped because the producer retry buffer was full",
+		},
+		[]string{"topic"},
+	)
 }
@@ -0,0 +1,335 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/ulogger"
+)
+
+// InheritedListenersEnv is the environment variable through which a replacement process receives the
+// listening sockets of the process it replaces, as a comma separated list of listener key=fd pairs.
+const InheritedListenersEnv = "TERANODE_INHERITED_LISTENERS"
+
+// ReplacementReadyEnv is the environment variable holding the file descriptor through which a replacement process
+// reports to the process it replaces that all its services are started.
+const ReplacementReadyEnv = "TERANODE_REPLACEMENT_READY_FD"
+
+// replacementReady is the byte written by a replacement process once its services are started
+const replacementReady = 'R'
+
+var (
+	inheritedOnce      sync.Once
+	inheritedMu        sync.Mutex
+	inheritedListeners map[string]uintptr
+)
+
+// parseInheritedListeners parses the value of InheritedListenersEnv into a map of listener key to file descriptor.
+func parseInheritedListeners(value string) (map[string]uintptr, error) {
+	fds := make(map[string]uintptr)
+
+	if value == "" {
+		return fds, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		key, fdStr, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, errors.NewConfigurationError("invalid inherited listener %q", pair)
+		}
+
+		fd, err := strconv.ParseUint(fdStr, 10, 32)
+		if err != nil {
+			return nil, errors.NewConfigurationError("invalid file descriptor for inherited listener %q", pair, err)
+		}
+
+		fds[key] = uintptr(fd)
+	}
+
+	return fds, nil
+}
+
+// takeInheritedListener returns the listener handed off by the previous process for the given key,
+// or nil when no listener was inherited. Every inherited listener can only be taken once.
+func takeInheritedListener(key string) (net.Listener, error) {
+	inheritedOnce.Do(func() {
+		fds, err := parseInheritedListeners(os.Getenv(InheritedListenersEnv))
+		if err != nil {
+			// fall back to opening new listeners, the previous process keeps serving until it stops
+			fds = make(map[string]uintptr)
+		}
+
+		inheritedMu.Lock()
+		inheritedListeners = fds
+		inheritedMu.Unlock()
+	})
+
+	inheritedMu.Lock()
+	fd, ok := inheritedListeners[key]
+	delete(inheritedListeners, key)
+	inheritedMu.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	f := os.NewFile(fd, key)
+	if f == nil {
+		return nil, errors.NewServiceError("invalid inherited file descriptor %d for listener %s", fd, key)
+	}
+
+	// net.FileListener duplicates the file descriptor, the original is no longer needed
+	defer f.Close()
+
+	lis, err := net.FileListener(f)
+	if err != nil {
+		return nil, errors.NewServiceError("failed to use inherited file descriptor %d for listener %s", fd, key, err)
+	}
+
+	return lis, nil
+}
+
+// listenerFiles returns duplicates of the file descriptors of all cached listeners of the settings context,
+// together with the value of InheritedListenersEnv describing them for a child process.
+func listenerFiles(settingsContext string) ([]*os.File, string, error) {
+	keys := make([]string, 0)
+
+	listeners.Range(func(k, _ interface{}) bool {
+		if strings.Contains(k.(string), settingsContext) {
+			keys = append(keys, k.(string))
+		}
+
+		return true
+	})
+
+	sort.Strings(keys)
+
+	files := make([]*os.File, 0, len(keys))
+	pairs := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		val, ok := listeners.Load(key)
+		if !ok {
+			continue
+		}
+
+		fileListener, ok := val.(interface{ File() (*os.File, error) })
+		if !ok {
+			continue
+		}
+
+		f, err := fileListener.File()
+		if err != nil {
+			for _, file := range files {
+				_ = file.Close()
+			}
+
+			return nil, "", errors.NewServiceError("failed to get file descriptor of listener %s", key, err)
+		}
+
+		// the child process receives the extra files from file descriptor 3 onwards
+		pairs = append(pairs, fmt.Sprintf("%s=%d", key, 3+len(files)))
+		files = append(files, f)
+	}
+
+	return files, strings.Join(pairs, ","), nil
+}
+
+// ListenWithHandoff opens a listener of the given network and address for a service that is not served through
+// GetListener, such as the legacy peer to peer protocol, using the listener handed off by the previous process
+// when there is one. The listener is handed off in turn by StartReplacementProcess until it is closed.
+func ListenWithHandoff(settingsContext string, serviceName string, network string, address string) (net.Listener, error) {
+	key := listenerKey(settingsContext, serviceName, network+"/"+address)
+
+	lis, err := takeInheritedListener(key)
+	if err != nil {
+		return nil, errors.NewServiceError("[%s] failed to use inherited listener", serviceName, err)
+	}
+
+	if lis == nil {
+		if lis, err = net.Listen(network, address); err != nil {
+			return nil, errors.NewServiceError("[%s] failed to start a new listener on %s", serviceName, address, err)
+		}
+	}
+
+	handedOff := &handoffListener{Listener: lis, key: key}
+	listeners.Store(key, handedOff)
+
+	return handedOff, nil
+}
+
+// handoffListener is a listener opened with ListenWithHandoff, which stops being handed off once it is closed
+type handoffListener struct {
+	net.Listener
+	key string
+}
+
+// Close closes the listener and removes it from the listeners handed off.
+func (l *handoffListener) Close() error {
+	listeners.CompareAndDelete(l.key, l)
+
+	return l.Listener.Close()
+}
+
+// File returns a duplicate of the file descriptor of the listener.
+func (l *handoffListener) File() (*os.File, error) {
+	fileListener, ok := l.Listener.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, errors.NewServiceError("listener %s has no file descriptor", l.key)
+	}
+
+	return fileListener.File()
+}
+
+// ReplacementProcess is a process started by StartReplacementProcess to replace the running process
+type ReplacementProcess struct {
+	Process *os.Process
+	ready   chan error
+}
+
+// WaitReady waits until the replacement process reports that all its services are started, see
+// NotifyReplacementReady. The replacement process is killed when it does not report ready within the timeout, or
+// when it exits before reporting ready.
+func (r *ReplacementProcess) WaitReady(ctx context.Context, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+
+	select {
+	case err = <-r.ready:
+	case <-timer.C:
+		err = errors.NewServiceError("replacement process %d did not report ready within %s", r.Process.Pid, timeout)
+	case <-ctx.Done():
+		err = errors.NewContextCanceledError("stopped waiting for replacement process %d", r.Process.Pid, ctx.Err())
+	}
+
+	if err != nil {
+		_ = r.Process.Kill()
+		_, _ = r.Process.Wait()
+	}
+
+	return err
+}
+
+// NotifyReplacementReady reports to the process this process replaces that all services are started, so it can
+// stop. It does nothing when this process was not started by StartReplacementProcess.
+func NotifyReplacementReady() error {
+	fdStr := os.Getenv(ReplacementReadyEnv)
+	if fdStr == "" {
+		return nil
+	}
+
+	_ = os.Unsetenv(ReplacementReadyEnv)
+
+	fd, err := strconv.ParseUint(fdStr, 10, 32)
+	if err != nil {
+		return errors.NewConfigurationError("invalid file descriptor %q in %s", fdStr, ReplacementReadyEnv, err)
+	}
+
+	f := os.NewFile(uintptr(fd), "replacement-ready")
+	if f == nil {
+		return errors.NewServiceError("invalid file descriptor %d in %s", fd, ReplacementReadyEnv)
+	}
+
+	defer f.Close()
+
+	if _, err = f.Write([]byte{replacementReady}); err != nil {
+		return errors.NewServiceError("failed to report ready to the replaced process", err)
+	}
+
+	return nil
+}
+
+// StartReplacementProcess starts a new instance of the running executable, with the same arguments, and hands
+// off the listening sockets of the settings context to it. The replacement accepts connections on the same sockets
+// as soon as its services are started, so the current process can be stopped gracefully, once the replacement
+// reports ready, without refusing any connections.
+//
+// The libp2p listeners are not opened by teranode and can not be handed off, libp2p opens its TCP listeners with
+// SO_REUSEPORT, which lets the replacement listen on the same ports while the current process is still running.
+func StartReplacementProcess(logger ulogger.Logger, settingsContext string) (*ReplacementProcess, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, errors.NewServiceError("failed to determine the running executable", err)
+	}
+
+	files, inherited, err := listenerFiles(settingsContext)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return nil, errors.NewServiceError("failed to create the ready pipe of the replacement process", err)
+	}
+
+	// the child process receives the extra files from file descriptor 3 onwards, the ready pipe after the listeners
+	cmd := exec.Command(executable, os.Args[1:]...) // nolint:gosec
+	cmd.Env = append(os.Environ(),
+		InheritedListenersEnv+"="+inherited,
+		fmt.Sprintf("%s=%d", ReplacementReadyEnv, 3+len(files)),
+	)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, readyWriter)
+
+	err = cmd.Start()
+
+	// the write end belongs to the child, reading from the pipe returns EOF when the child exits without reporting
+	_ = readyWriter.Close()
+
+	if err != nil {
+		_ = readyReader.Close()
+
+		return nil, errors.NewServiceError("failed to start replacement process", err)
+	}
+
+	if reuse, err := strconv.ParseBool(os.Getenv("LIBP2P_TCP_REUSEPORT")); err == nil && !reuse {
+		logger.Warnf("LIBP2P_TCP_REUSEPORT is disabled, the replacement process can not listen on the p2p ports while this process is running")
+	}
+
+	logger.Infof("Started replacement process %d with %d inherited listeners", cmd.Process.Pid, len(files))
+
+	replacement := &ReplacementProcess{
+		Process: cmd.Process,
+		ready:   make(chan error, 1),
+	}
+
+	go func() {
+		defer readyReader.Close()
+
+		buf := make([]byte, 1)
+
+		if _, err := io.ReadFull(readyReader, buf); err != nil {
+			replacement.ready <- errors.NewServiceError("replacement process %d exited before reporting ready", cmd.Process.Pid, err)
+			return
+		}
+
+		if buf[0] != replacementReady {
+			replacement.ready <- errors.NewServiceError("replacement process %d reported an invalid ready state", cmd.Process.Pid)
+			return
+		}
+
+		replacement.ready <- nil
+	}()
+
+	return replacement, nil
+}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInheritedListeners(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		fds, err := parseInheritedListeners("")
+		require.NoError(t, err)
+		assert.Empty(t, fds)
+	})
+
+	t.Run("multiple listeners", func(t *testing.T) {
+		fds, err := parseInheritedListeners("ctx!asset!http=3,ctx!propagation!=4")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]uintptr{"ctx!asset!http": 3, "ctx!propagation!": 4}, fds)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, value := range []string{"ctx!asset!http", "=3", "ctx!asset!http=abc"} {
+			_, err := parseInheritedListeners(value)
+			assert.Error(t, err, value)
+		}
+	})
+}
+
+func TestInheritedListener(t *testing.T) {
+	const settingsContext = "listenerhandoff"
+
+	defer CleanupListeners(settingsContext)
+
+	listener, _, _, err := GetListener(settingsContext, "asset", "http://", "localhost:0")
+	require.NoError(t, err)
+
+	address := listener.Addr().String()
+
+	files, inherited, err := listenerFiles(settingsContext)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	key := listenerKey(settingsContext, "asset", "http://")
+	assert.Equal(t, key+"=3", inherited)
+
+	// keep a duplicate of the descriptor, as a child process would have, and stop the original listener
+	fd, err := syscall.Dup(int(files[0].Fd()))
+	require.NoError(t, err)
+
+	_ = files[0].Close()
+
+	RemoveListener(settingsContext, "asset", "http://")
+
+	// hand the descriptor to this process, as if it was started as the replacement
+	_, err = takeInheritedListener("")
+	require.NoError(t, err)
+
+	inheritedMu.Lock()
+	inheritedListeners[key] = uintptr(fd)
+	inheritedMu.Unlock()
+
+	replacement, _, _, err := GetListener(settingsContext, "asset", "http://", "localhost:0")
+	require.NoError(t, err)
+
+	assert.Equal(t, address, replacement.Addr().String())
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("replacement"))
+		}),
+	}
+
+	go func() {
+		_ = server.Serve(replacement)
+	}()
+
+	defer server.Close()
+
+	resp, err := http.Get("http://" + address) // nolint:noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "replacement", string(body))
+
+	// an inherited listener is only used once
+	inheritedMu.Lock()
+	_, ok := inheritedListeners[key]
+	inheritedMu.Unlock()
+	assert.False(t, ok)
+}
+
+func TestListenWithHandoff(t *testing.T) {
+	const settingsContext = "listenwithhandoff"
+
+	listener, err := ListenWithHandoff(settingsContext, "legacy", "tcp", "localhost:0")
+	require.NoError(t, err)
+
+	files, inherited, err := listenerFiles(settingsContext)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	_ = files[0].Close()
+
+	assert.Equal(t, listenerKey(settingsContext, "legacy", "tcp/localhost:0")+"=3", inherited)
+
+	// a closed listener is not handed off anymore
+	require.NoError(t, listener.Close())
+
+	files, inherited, err = listenerFiles(settingsContext)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+	assert.Empty(t, inherited)
+}
+
+func TestNotifyReplacementReady(t *testing.T) {
+	t.Run("not a replacement", func(t *testing.T) {
+		t.Setenv(ReplacementReadyEnv, "")
+
+		require.NoError(t, NotifyReplacementReady())
+	})
+
+	t.Run("replacement reports ready", func(t *testing.T) {
+		readyReader, readyWriter, err := os.Pipe()
+		require.NoError(t, err)
+
+		defer readyReader.Close()
+
+		// the replacement owns its own descriptor of the write end, as a child process would
+		fd, err := syscall.Dup(int(readyWriter.Fd()))
+		require.NoError(t, err)
+
+		_ = readyWriter.Close()
+
+		t.Setenv(ReplacementReadyEnv, strconv.Itoa(fd))
+
+		require.NoError(t, NotifyReplacementReady())
+
+		ready, err := io.ReadAll(readyReader)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{replacementReady}, ready)
+
+		// the ready state is only reported once
+		assert.Empty(t, os.Getenv(ReplacementReadyEnv))
+	})
+}
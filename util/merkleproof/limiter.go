@@ -0,0 +1,82 @@
+package merkleproof
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"golang.org/x/sync/semaphore"
+)
+
+// Limiter caps the number of merkle proofs constructed concurrently. Constructing a proof reads the block
+// and its subtrees, so many concurrent proofs compete for store IO and memory with the rest of the node.
+// Proofs over the cap wait for a free slot, up to the queue timeout.
+//
+// A nil Limiter does not limit anything.
+type Limiter struct {
+	slots        *semaphore.Weighted
+	queueTimeout time.Duration
+}
+
+// NewLimiter creates a limiter allowing maxConcurrent concurrent proof constructions, waiting at most
+// queueTimeout for a free slot, or until the context is done when queueTimeout is not positive.
+// Returns nil, a limiter that does not limit anything, when maxConcurrent is not positive.
+func NewLimiter(maxConcurrent int, queueTimeout time.Duration) *Limiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+
+	return &Limiter{
+		slots:        semaphore.NewWeighted(int64(maxConcurrent)),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Acquire waits for a free slot to construct a proof. The returned release function must be called when the
+// proof has been constructed.
+//
+// Returns:
+//   - func(): Releases the slot
+//   - error: A threshold exceeded error if no slot became free within the queue timeout, or a context
+//     cancelled error if the context is done while waiting
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	waitCtx := ctx
+
+	if l.queueTimeout > 0 {
+		var cancel context.CancelFunc
+
+		waitCtx, cancel = context.WithTimeout(ctx, l.queueTimeout)
+		defer cancel()
+	}
+
+	if err := l.slots.Acquire(waitCtx, 1); err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.NewContextCanceledError("cancelled while waiting to construct merkle proof", ctx.Err())
+		}
+
+		return nil, errors.NewThresholdExceededError("no merkle proof construction slot available within %s, retry later", l.queueTimeout)
+	}
+
+	return func() {
+		l.slots.Release(1)
+	}, nil
+}
+
+// limiter is the node wide limiter shared by all proof constructions, nil until initialized.
+var limiter atomic.Pointer[Limiter]
+
+// InitLimiter sets the node wide cap on concurrent merkle proof constructions. A maxConcurrent that is not
+// positive removes the cap. It should be called at startup, before proofs are served.
+func InitLimiter(maxConcurrent int, queueTimeout time.Duration) {
+	limiter.Store(NewLimiter(maxConcurrent, queueTimeout))
+}
+
+// AcquireSlot waits for a free slot of the node wide limiter to construct a proof, see Limiter.Acquire.
+func AcquireSlot(ctx context.Context) (func(), error) {
+	return limiter.Load().Acquire(ctx)
+}
@@ -0,0 +1,116 @@
+package merkleproof
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("concurrent proofs respect the cap", func(t *testing.T) {
+		const maxConcurrent = 3
+
+		l := NewLimiter(maxConcurrent, time.Minute)
+
+		var (
+			running    atomic.Int32
+			maxRunning atomic.Int32
+			wg         sync.WaitGroup
+		)
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				release, err := l.Acquire(context.Background())
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				defer release()
+
+				current := running.Add(1)
+				for {
+					highest := maxRunning.Load()
+					if current <= highest || maxRunning.CompareAndSwap(highest, current) {
+						break
+					}
+				}
+
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.LessOrEqual(t, maxRunning.Load(), int32(maxConcurrent))
+		assert.Positive(t, maxRunning.Load())
+		assert.Equal(t, int32(0), running.Load())
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		l := NewLimiter(1, 10*time.Millisecond)
+
+		release, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+
+		_, err = l.Acquire(context.Background())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrThresholdExceeded))
+
+		// the slot is available again once released
+		release()
+
+		release, err = l.Acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		l := NewLimiter(1, 0)
+
+		release, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = l.Acquire(ctx)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrContextCanceled))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		l := NewLimiter(0, time.Second)
+		assert.Nil(t, l)
+
+		for i := 0; i < 10; i++ {
+			_, err := l.Acquire(context.Background())
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("node wide limiter", func(t *testing.T) {
+		InitLimiter(1, 10*time.Millisecond)
+		defer InitLimiter(0, 0)
+
+		release, err := AcquireSlot(context.Background())
+		require.NoError(t, err)
+
+		_, err = AcquireSlot(context.Background())
+		require.Error(t, err)
+
+		release()
+	})
+}
@@ -0,0 +1,153 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PeerByteBudget tracks the number of bytes downloaded from every peer within a fixed time interval. A peer
+// whose budget is exhausted should not be used for new downloads until its interval ends, when the budget of
+// the peer is reset, so downloads are spread across peers instead of saturating the bandwidth with a single one.
+//
+// A nil PeerByteBudget does not limit anything.
+type PeerByteBudget struct {
+	mu          sync.Mutex
+	maxBytes    uint64
+	interval    time.Duration
+	peers       map[string]*peerBudget
+	lastCleanup time.Time
+}
+
+type peerBudget struct {
+	used        uint64
+	windowStart time.Time
+}
+
+// NewPeerByteBudget creates a budget of maxBytes per peer per interval. When interval is not positive, it
+// defaults to one minute.
+//
+// Returns nil, a budget that does not limit anything, when maxBytes is 0.
+func NewPeerByteBudget(maxBytes uint64, interval time.Duration) *PeerByteBudget {
+	if maxBytes == 0 {
+		return nil
+	}
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &PeerByteBudget{
+		maxBytes:    maxBytes,
+		interval:    interval,
+		peers:       make(map[string]*peerBudget),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Record counts n downloaded bytes against the budget of the given peer.
+func (b *PeerByteBudget) Record(peer string, n uint64) {
+	b.recordAt(peer, time.Now(), n)
+}
+
+// Exhausted reports whether the budget of the given peer for the current interval has been used up.
+func (b *PeerByteBudget) Exhausted(peer string) bool {
+	if b == nil {
+		return false
+	}
+
+	return b.usedAt(peer, time.Now()) >= b.maxBytes
+}
+
+// Wait blocks until the budget of the given peer is no longer exhausted, which is when the current interval of
+// the peer ends, or until the context is done. Returns the error of the context when it is done first.
+func (b *PeerByteBudget) Wait(ctx context.Context, peer string) error {
+	for {
+		wait := b.exhaustedForAt(peer, time.Now())
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Used returns the number of bytes downloaded from the given peer in the current interval.
+func (b *PeerByteBudget) Used(peer string) uint64 {
+	return b.usedAt(peer, time.Now())
+}
+
+func (b *PeerByteBudget) recordAt(peer string, now time.Time, n uint64) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.peerAt(peer, now).used += n
+}
+
+func (b *PeerByteBudget) usedAt(peer string, now time.Time) uint64 {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p, ok := b.peers[peer]
+	if !ok || now.Sub(p.windowStart) >= b.interval {
+		return 0
+	}
+
+	return p.used
+}
+
+// exhaustedForAt returns how long the budget of the peer stays exhausted after now, 0 when it is not exhausted.
+func (b *PeerByteBudget) exhaustedForAt(peer string, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p, ok := b.peers[peer]
+	if !ok || p.used < b.maxBytes {
+		return 0
+	}
+
+	return p.windowStart.Add(b.interval).Sub(now)
+}
+
+// peerAt returns the budget of the peer for the interval containing now, starting a new interval when the
+// previous one has ended. Must be called with the lock held.
+func (b *PeerByteBudget) peerAt(peer string, now time.Time) *peerBudget {
+	if now.Sub(b.lastCleanup) > b.interval {
+		for key, p := range b.peers {
+			if now.Sub(p.windowStart) >= b.interval {
+				delete(b.peers, key)
+			}
+		}
+
+		b.lastCleanup = now
+	}
+
+	p, ok := b.peers[peer]
+	if !ok || now.Sub(p.windowStart) >= b.interval {
+		p = &peerBudget{
+			windowStart: now,
+		}
+		b.peers[peer] = p
+	}
+
+	return p
+}
@@ -0,0 +1,92 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerByteBudget(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		b := NewPeerByteBudget(0, time.Minute)
+		require.Nil(t, b)
+
+		b.Record("peer1", 1000)
+
+		assert.False(t, b.Exhausted("peer1"))
+		assert.Equal(t, uint64(0), b.Used("peer1"))
+	})
+
+	t.Run("budget per peer", func(t *testing.T) {
+		b := NewPeerByteBudget(100, time.Minute)
+
+		b.Record("peer1", 60)
+		assert.False(t, b.Exhausted("peer1"))
+
+		b.Record("peer1", 40)
+		assert.True(t, b.Exhausted("peer1"))
+		assert.Equal(t, uint64(100), b.Used("peer1"))
+
+		// other peers have their own budget
+		assert.False(t, b.Exhausted("peer2"))
+		assert.Equal(t, uint64(0), b.Used("peer2"))
+	})
+
+	t.Run("budget is reset after the interval", func(t *testing.T) {
+		b := NewPeerByteBudget(100, time.Minute)
+		now := time.Now()
+
+		b.recordAt("peer1", now, 150)
+		assert.Equal(t, uint64(150), b.usedAt("peer1", now.Add(59*time.Second)))
+		assert.Equal(t, uint64(0), b.usedAt("peer1", now.Add(time.Minute)))
+
+		b.recordAt("peer1", now.Add(time.Minute), 10)
+		assert.Equal(t, uint64(10), b.usedAt("peer1", now.Add(time.Minute)))
+	})
+
+	t.Run("idle peers are dropped", func(t *testing.T) {
+		b := NewPeerByteBudget(100, time.Minute)
+		now := time.Now()
+
+		b.recordAt("peer1", now, 10)
+		b.recordAt("peer2", now.Add(2*time.Minute), 10)
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		assert.Len(t, b.peers, 1)
+	})
+	t.Run("wait until the interval ends", func(t *testing.T) {
+		b := NewPeerByteBudget(100, 50*time.Millisecond)
+
+		// a peer with budget left does not wait
+		require.NoError(t, b.Wait(context.Background(), "peer1"))
+
+		b.Record("peer1", 100)
+		require.True(t, b.Exhausted("peer1"))
+
+		start := time.Now()
+		require.NoError(t, b.Wait(context.Background(), "peer1"))
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+		assert.False(t, b.Exhausted("peer1"))
+	})
+
+	t.Run("wait is cancelled with the context", func(t *testing.T) {
+		b := NewPeerByteBudget(100, time.Minute)
+		b.Record("peer1", 100)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, b.Wait(ctx, "peer1"), context.DeadlineExceeded)
+	})
+
+	t.Run("disabled budget never waits", func(t *testing.T) {
+		var b *PeerByteBudget
+
+		require.NoError(t, b.Wait(context.Background(), "peer1"))
+	})
+}
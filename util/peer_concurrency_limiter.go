@@ -0,0 +1,224 @@
+package util
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// PeerConcurrencyLimiter limits the number of concurrent requests made to a single peer. Every peer,
+// identified by a key like a peer ID or its base URL, gets its own slots, so requests are capped per
+// peer while requests to different peers are still made concurrently.
+//
+// When the same work can be done by several peers, Assign spreads it across them, so work in excess of
+// the cap of one peer moves to another peer instead of waiting for the slots of the first peer.
+//
+// A nil PeerConcurrencyLimiter does not limit anything.
+type PeerConcurrencyLimiter struct {
+	maxPerPeer int
+	mu         sync.Mutex
+	peers      map[string]*peerSlots
+}
+
+type peerSlots struct {
+	slots    chan struct{}
+	users    int // number of requests holding or waiting for a slot
+	assigned int // number of pieces of work assigned to the peer, the peer is dropped when both are 0
+}
+
+// NewPeerConcurrencyLimiter creates a limiter that allows maxPerPeer concurrent requests per peer.
+// Returns nil, a limiter that does not limit anything, when maxPerPeer is not positive.
+func NewPeerConcurrencyLimiter(maxPerPeer int) *PeerConcurrencyLimiter {
+	if maxPerPeer <= 0 {
+		return nil
+	}
+
+	return &PeerConcurrencyLimiter{
+		maxPerPeer: maxPerPeer,
+		peers:      make(map[string]*peerSlots),
+	}
+}
+
+// Acquire reserves a request slot for the given peer, blocking while all slots of the peer are taken.
+// The returned release function must be called once the request has completed, it is safe to call
+// it more than once.
+//
+// Returns:
+//   - func(): Function that releases the slot
+//   - error: The context error if the context is cancelled while waiting for a slot
+func (l *PeerConcurrencyLimiter) Acquire(ctx context.Context, peer string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+
+	p := l.getPeer(peer)
+	p.users++
+
+	l.mu.Unlock()
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		l.done(peer, p)
+		return func() {}, ctx.Err()
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			<-p.slots
+			l.done(peer, p)
+		})
+	}, nil
+}
+
+// Assign picks the peer to do the next piece of work from the given peers, which can all do the work, and
+// assigns the work to it. The preferred peer is listed first: it gets the work until the work assigned to
+// it reaches the cap of a peer, after which the work moves to the next peer below the cap. When all peers
+// are at the cap, the work is assigned to the peer with the least work assigned.
+//
+// Assign does not take a request slot, the requests of the work still call Acquire on the returned peer.
+// The returned done function must be called once the work has completed, it is safe to call it more
+// than once.
+//
+// Returns:
+//   - string: The peer to do the work, "" when no peers are given
+//   - func(): Function that marks the work as done
+func (l *PeerConcurrencyLimiter) Assign(peers []string) (string, func()) {
+	if len(peers) == 0 {
+		return "", func() {}
+	}
+
+	if l == nil || len(peers) == 1 {
+		return peers[0], func() {}
+	}
+
+	l.mu.Lock()
+
+	var (
+		peer string
+		p    *peerSlots
+	)
+
+	for _, candidate := range peers {
+		candidateSlots := l.getPeer(candidate)
+
+		if candidateSlots.assigned < l.maxPerPeer {
+			peer, p = candidate, candidateSlots
+			break
+		}
+
+		if p == nil || candidateSlots.assigned < p.assigned {
+			peer, p = candidate, candidateSlots
+		}
+	}
+
+	p.assigned++
+
+	// drop the candidates that were not picked and have no requests or work
+	for _, candidate := range peers {
+		if candidateSlots, ok := l.peers[candidate]; ok {
+			l.dropIfUnused(candidate, candidateSlots)
+		}
+	}
+
+	l.mu.Unlock()
+
+	var once sync.Once
+
+	return peer, func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			p.assigned--
+			l.dropIfUnused(peer, p)
+		})
+	}
+}
+
+// getPeer returns the slots of the peer, creating them when the peer has none.
+// Must be called with the lock held.
+func (l *PeerConcurrencyLimiter) getPeer(peer string) *peerSlots {
+	p, ok := l.peers[peer]
+	if !ok {
+		p = &peerSlots{
+			slots: make(chan struct{}, l.maxPerPeer),
+		}
+		l.peers[peer] = p
+	}
+
+	return p
+}
+
+// done drops the slots of the peer when no requests are holding or waiting for them anymore.
+func (l *PeerConcurrencyLimiter) done(peer string, p *peerSlots) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p.users--
+	l.dropIfUnused(peer, p)
+}
+
+// dropIfUnused drops the slots of the peer when no requests and no work use them anymore.
+// Must be called with the lock held.
+func (l *PeerConcurrencyLimiter) dropIfUnused(peer string, p *peerSlots) {
+	if p.users == 0 && p.assigned == 0 {
+		delete(l.peers, peer)
+	}
+}
+
+// InFlight returns the number of requests currently holding a slot of the given peer.
+func (l *PeerConcurrencyLimiter) InFlight(peer string) int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p, ok := l.peers[peer]; ok {
+		return len(p.slots)
+	}
+
+	return 0
+}
+
+// Assigned returns the number of pieces of work currently assigned to the given peer.
+func (l *PeerConcurrencyLimiter) Assigned(peer string) int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p, ok := l.peers[peer]; ok {
+		return p.assigned
+	}
+
+	return 0
+}
+
+// ReleaseOnClose returns a ReadCloser that calls release when the reader is closed, to hold a request
+// slot until a streamed response has been consumed.
+func ReleaseOnClose(rc io.ReadCloser, release func()) io.ReadCloser {
+	return &releasingReadCloser{
+		ReadCloser: rc,
+		release:    release,
+	}
+}
+
+type releasingReadCloser struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releasingReadCloser) Close() error {
+	defer r.release()
+
+	return r.ReadCloser.Close()
+}
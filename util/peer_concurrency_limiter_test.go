@@ -0,0 +1,245 @@
+package util
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerConcurrencyLimiter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		l := NewPeerConcurrencyLimiter(0)
+		require.Nil(t, l)
+
+		release, err := l.Acquire(context.Background(), "peer1")
+		require.NoError(t, err)
+		release()
+
+		assert.Equal(t, 0, l.InFlight("peer1"))
+	})
+
+	t.Run("per peer cap with concurrency across peers", func(t *testing.T) {
+		const maxPerPeer = 2
+
+		l := NewPeerConcurrencyLimiter(maxPerPeer)
+		peers := []string{"peer1", "peer2", "peer3"}
+
+		var (
+			mu          sync.Mutex
+			inFlight    = make(map[string]int)
+			maxInFlight = make(map[string]int)
+			total       int
+			maxTotal    int
+			wg          sync.WaitGroup
+		)
+
+		for _, peer := range peers {
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+
+				go func(peer string) {
+					defer wg.Done()
+
+					release, err := l.Acquire(context.Background(), peer)
+					assert.NoError(t, err)
+
+					defer release()
+
+					mu.Lock()
+					inFlight[peer]++
+					total++
+					maxInFlight[peer] = max(maxInFlight[peer], inFlight[peer])
+					maxTotal = max(maxTotal, total)
+					mu.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mu.Lock()
+					inFlight[peer]--
+					total--
+					mu.Unlock()
+				}(peer)
+			}
+		}
+
+		wg.Wait()
+
+		for _, peer := range peers {
+			assert.LessOrEqual(t, maxInFlight[peer], maxPerPeer, peer)
+		}
+
+		// requests to the different peers were made concurrently
+		assert.Greater(t, maxTotal, maxPerPeer)
+
+		// peers without requests are dropped
+		assert.Empty(t, l.peers)
+	})
+
+	t.Run("excess work moves to other peers", func(t *testing.T) {
+		l := NewPeerConcurrencyLimiter(2)
+		peers := []string{"peer1", "peer2", "peer3"}
+
+		assigned := make([]string, 0, 7)
+		dones := make([]func(), 0, 7)
+
+		for i := 0; i < 7; i++ {
+			peer, done := l.Assign(peers)
+			assigned = append(assigned, peer)
+			dones = append(dones, done)
+		}
+
+		// the preferred peer gets the work up to the cap, all peers at the cap get it evenly
+		assert.Equal(t, []string{"peer1", "peer1", "peer2", "peer2", "peer3", "peer3", "peer1"}, assigned)
+
+		// work moves back to the preferred peer once it is below the cap
+		dones[0]()
+		dones[6]()
+		dones[6]()
+
+		peer, done := l.Assign(peers)
+		assert.Equal(t, "peer1", peer)
+		assert.Equal(t, 2, l.Assigned("peer1"))
+
+		done()
+
+		for _, done := range dones {
+			done()
+		}
+
+		for _, peer := range peers {
+			assert.Equal(t, 0, l.Assigned(peer))
+		}
+
+		assert.Empty(t, l.peers)
+	})
+
+	t.Run("assigned work respects the per peer cap while using multiple peers", func(t *testing.T) {
+		const maxPerPeer = 2
+
+		l := NewPeerConcurrencyLimiter(maxPerPeer)
+		peers := []string{"peer1", "peer2", "peer3"}
+
+		var (
+			mu          sync.Mutex
+			inFlight    = make(map[string]int)
+			maxInFlight = make(map[string]int)
+			total       int
+			maxTotal    int
+			wg          sync.WaitGroup
+		)
+
+		for i := 0; i < 30; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				peer, done := l.Assign(peers)
+				defer done()
+
+				release, err := l.Acquire(context.Background(), peer)
+				assert.NoError(t, err)
+
+				defer release()
+
+				mu.Lock()
+				inFlight[peer]++
+				total++
+				maxInFlight[peer] = max(maxInFlight[peer], inFlight[peer])
+				maxTotal = max(maxTotal, total)
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				inFlight[peer]--
+				total--
+				mu.Unlock()
+			}()
+		}
+
+		wg.Wait()
+
+		for _, peer := range peers {
+			assert.LessOrEqual(t, maxInFlight[peer], maxPerPeer, peer)
+			assert.Positive(t, maxInFlight[peer], peer)
+		}
+
+		// the work was spread over the peers
+		assert.Greater(t, maxTotal, maxPerPeer)
+		assert.Empty(t, l.peers)
+	})
+
+	t.Run("assign without limit or alternatives", func(t *testing.T) {
+		var disabled *PeerConcurrencyLimiter
+
+		peer, done := disabled.Assign([]string{"peer1", "peer2"})
+		assert.Equal(t, "peer1", peer)
+		done()
+
+		l := NewPeerConcurrencyLimiter(1)
+
+		peer, done = l.Assign(nil)
+		assert.Equal(t, "", peer)
+		done()
+
+		for i := 0; i < 3; i++ {
+			peer, _ = l.Assign([]string{"peer1"})
+			assert.Equal(t, "peer1", peer)
+		}
+
+		assert.Empty(t, l.peers)
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		l := NewPeerConcurrencyLimiter(1)
+
+		release, err := l.Acquire(context.Background(), "peer1")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = l.Acquire(ctx, "peer1")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, l.InFlight("peer1"))
+
+		// releasing more than once only frees a single slot
+		release()
+		release()
+
+		assert.Equal(t, 0, l.InFlight("peer1"))
+		assert.Empty(t, l.peers)
+	})
+
+	t.Run("release on close", func(t *testing.T) {
+		l := NewPeerConcurrencyLimiter(1)
+
+		release, err := l.Acquire(context.Background(), "peer1")
+		require.NoError(t, err)
+
+		var released atomic.Bool
+
+		rc := ReleaseOnClose(io.NopCloser(strings.NewReader("data")), func() {
+			released.Store(true)
+			release()
+		})
+
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(b))
+		assert.False(t, released.Load())
+		assert.Equal(t, 1, l.InFlight("peer1"))
+
+		require.NoError(t, rc.Close())
+		assert.True(t, released.Load())
+		assert.Equal(t, 0, l.InFlight("peer1"))
+	})
+}
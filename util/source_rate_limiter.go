@@ -0,0 +1,134 @@
+package util
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// sourceRateLimiterIdleTimeout is the time after which the limiter of a source that has not been seen
+// is dropped, keeping the number of tracked sources bounded.
+const sourceRateLimiterIdleTimeout = 10 * time.Minute
+
+// SourceRateLimiter limits the rate of events, for instance accepted transactions, per source.
+// Every source, identified by a key like a peer ID or a client IP address, gets its own token
+// bucket, so a single abusive source can be throttled without affecting any of the others.
+//
+// A nil SourceRateLimiter allows everything.
+type SourceRateLimiter struct {
+	mu          sync.Mutex
+	limit       rate.Limit
+	burst       int
+	limiters    map[string]*sourceLimiter
+	lastCleanup time.Time
+}
+
+type sourceLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewSourceRateLimiter creates a limiter that allows perSecond events per second per source, with bursts
+// of up to burst events. When burst is not positive, it defaults to perSecond rounded up.
+//
+// Returns nil, a limiter that allows everything, when perSecond is not positive.
+func NewSourceRateLimiter(perSecond float64, burst int) *SourceRateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	if burst <= 0 {
+		burst = int(math.Ceil(perSecond))
+	}
+
+	return &SourceRateLimiter{
+		limit:       rate.Limit(perSecond),
+		burst:       burst,
+		limiters:    make(map[string]*sourceLimiter),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Allow reports whether a single event from the given source is allowed now.
+func (l *SourceRateLimiter) Allow(source string) bool {
+	return l.AllowN(source, 1)
+}
+
+// AllowN reports whether n events from the given source are allowed now. When they are not,
+// none of the events are counted against the rate of the source.
+func (l *SourceRateLimiter) AllowN(source string, n int) bool {
+	return l.allowAt(source, time.Now(), n)
+}
+
+// AllowUpTo returns how many of n events from the given source are allowed now, only those count against
+// the rate of the source. AllowN never allows a batch larger than the burst, AllowUpTo allows as many of
+// its events as the source has tokens left.
+func (l *SourceRateLimiter) AllowUpTo(source string, n int) int {
+	return l.allowUpToAt(source, time.Now(), n)
+}
+
+func (l *SourceRateLimiter) allowAt(source string, now time.Time, n int) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.limiterAt(source, now).AllowN(now, n)
+}
+
+func (l *SourceRateLimiter) allowUpToAt(source string, now time.Time, n int) int {
+	if l == nil || n <= 0 {
+		return max(n, 0)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter := l.limiterAt(source, now)
+
+	allowed := min(n, int(math.Floor(limiter.TokensAt(now))))
+	if allowed <= 0 || !limiter.AllowN(now, allowed) {
+		return 0
+	}
+
+	return allowed
+}
+
+// limiterAt returns the limiter of the source, dropping the limiters of the sources that have been idle.
+// The caller holds the lock.
+func (l *SourceRateLimiter) limiterAt(source string, now time.Time) *rate.Limiter {
+	if now.Sub(l.lastCleanup) > sourceRateLimiterIdleTimeout {
+		for key, sl := range l.limiters {
+			if now.Sub(sl.lastSeen) > sourceRateLimiterIdleTimeout {
+				delete(l.limiters, key)
+			}
+		}
+
+		l.lastCleanup = now
+	}
+
+	sl, ok := l.limiters[source]
+	if !ok {
+		sl = &sourceLimiter{
+			limiter: rate.NewLimiter(l.limit, l.burst),
+		}
+		l.limiters[source] = sl
+	}
+
+	sl.lastSeen = now
+
+	return sl.limiter
+}
+
+// Limit returns the number of events per second allowed per source, 0 when unlimited.
+func (l *SourceRateLimiter) Limit() float64 {
+	if l == nil {
+		return 0
+	}
+
+	return float64(l.limit)
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceRateLimiter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		l := NewSourceRateLimiter(0, 10)
+		require.Nil(t, l)
+
+		for i := 0; i < 1000; i++ {
+			assert.True(t, l.Allow("peer1"))
+		}
+
+		assert.Equal(t, float64(0), l.Limit())
+	})
+
+	t.Run("throttled and recovers", func(t *testing.T) {
+		l := NewSourceRateLimiter(10, 5)
+		now := time.Now()
+
+		// the burst is allowed, after which the source is throttled
+		for i := 0; i < 5; i++ {
+			assert.True(t, l.allowAt("peer1", now, 1))
+		}
+
+		assert.False(t, l.allowAt("peer1", now, 1))
+
+		// other sources are not affected
+		assert.True(t, l.allowAt("peer2", now, 1))
+
+		// after 100ms one more token is available at 10 per second
+		assert.True(t, l.allowAt("peer1", now.Add(100*time.Millisecond), 1))
+		assert.False(t, l.allowAt("peer1", now.Add(100*time.Millisecond), 1))
+
+		// after a second the full burst is available again
+		assert.True(t, l.allowAt("peer1", now.Add(1100*time.Millisecond), 5))
+	})
+
+	t.Run("default burst", func(t *testing.T) {
+		l := NewSourceRateLimiter(2.5, 0)
+		now := time.Now()
+
+		assert.True(t, l.allowAt("peer1", now, 3))
+		assert.False(t, l.allowAt("peer1", now, 1))
+	})
+
+	t.Run("allow up to", func(t *testing.T) {
+		l := NewSourceRateLimiter(10, 5)
+		now := time.Now()
+
+		// a batch larger than the burst is partially allowed
+		assert.Equal(t, 5, l.allowUpToAt("peer1", now, 8))
+		assert.Equal(t, 0, l.allowUpToAt("peer1", now, 8))
+
+		// after 200ms two more tokens are available at 10 per second
+		assert.Equal(t, 2, l.allowUpToAt("peer1", now.Add(200*time.Millisecond), 8))
+
+		// a batch within the tokens left is fully allowed
+		assert.Equal(t, 3, l.allowUpToAt("peer2", now, 3))
+
+		var disabled *SourceRateLimiter
+		assert.Equal(t, 8, disabled.AllowUpTo("peer1", 8))
+	})
+
+	t.Run("idle sources are dropped", func(t *testing.T) {
+		l := NewSourceRateLimiter(10, 5)
+		now := time.Now()
+
+		assert.True(t, l.allowAt("peer1", now, 1))
+		assert.Len(t, l.limiters, 1)
+
+		assert.True(t, l.allowAt("peer2", now.Add(2*sourceRateLimiterIdleTimeout), 1))
+		assert.Len(t, l.limiters, 1)
+	})
+}
@@ -0,0 +1,292 @@
+// Package statsd exports the Prometheus metrics of the node to a StatsD server.
+//
+// The exporter periodically gathers the registered Prometheus metrics and sends the ones matching
+// the configured name prefixes to a StatsD server over UDP, so nodes can be monitored with StatsD
+// based tooling without duplicating the instrumentation:
+//
+//   - Counters are sent as StatsD counters with the increase since the previous flush
+//   - Gauges are sent as StatsD gauges with their current value
+//   - Histograms and summaries are sent as a counter with the number of observations since the
+//     previous flush and a gauge with the mean of these observations, e.g. the mean latency
+//
+// Labels are appended to the metric name, for instance the Prometheus metric
+// teranode_validator_transactions{status="ok"} is sent as <prefix>.teranode_validator_transactions.ok
+package statsd
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/errors"
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// maxPacketSize is the maximum size of a single UDP packet sent to the StatsD server, small enough
+// to not be fragmented on common networks.
+const maxPacketSize = 1432
+
+// Exporter sends Prometheus metrics to a StatsD server.
+type Exporter struct {
+	logger   ulogger.Logger
+	gatherer prometheus.Gatherer
+	conn     net.Conn
+	prefix   string
+	metrics  []string
+	interval time.Duration
+
+	mu sync.Mutex
+	// previous holds the cumulative values of the counters, histograms and summaries at the previous
+	// flush, keyed by StatsD name, to calculate the increase since that flush
+	previous map[string]float64
+}
+
+// NewExporter creates an exporter that sends metrics to the StatsD server at the given endpoint.
+//
+// Parameters:
+//   - logger: Logger instance
+//   - endpoint: Address of the StatsD server, host:port
+//   - prefix: Prefix added to the name of every metric sent, can be empty
+//   - metrics: Prometheus metric name prefixes of the metrics to send, all metrics are sent when empty
+//   - interval: Time between flushes of the metrics to the StatsD server
+//   - gatherer: Gatherer to collect the metrics from, prometheus.DefaultGatherer when nil
+//
+// Returns:
+//   - *Exporter: The exporter, not started yet
+//   - error: ConfigurationError when the endpoint is invalid
+func NewExporter(logger ulogger.Logger, endpoint string, prefix string, metrics []string, interval time.Duration, gatherer prometheus.Gatherer) (*Exporter, error) {
+	if interval <= 0 {
+		return nil, errors.NewConfigurationError("statsd flush interval must be positive, got %v", interval)
+	}
+
+	conn, err := net.Dial("udp", endpoint)
+	if err != nil {
+		return nil, errors.NewConfigurationError("invalid statsd endpoint %s", endpoint, err)
+	}
+
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+
+	filter := make([]string, 0, len(metrics))
+
+	for _, metric := range metrics {
+		if metric = strings.TrimSpace(metric); metric != "" {
+			filter = append(filter, metric)
+		}
+	}
+
+	return &Exporter{
+		logger:   logger,
+		gatherer: gatherer,
+		conn:     conn,
+		prefix:   strings.TrimSuffix(prefix, "."),
+		metrics:  filter,
+		interval: interval,
+		previous: make(map[string]float64),
+	}, nil
+}
+
+// Start flushes the metrics to the StatsD server every interval, until the context is done.
+func (e *Exporter) Start(ctx context.Context) {
+	e.logger.Infof("[StatsD] exporting metrics to %s every %v", e.conn.RemoteAddr().String(), e.interval)
+
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				if err := e.Flush(); err != nil {
+					e.logger.Warnf("[StatsD] failed to flush metrics: %v", err)
+				}
+
+				_ = e.conn.Close()
+
+				return
+			case <-ticker.C:
+				if err := e.Flush(); err != nil {
+					e.logger.Warnf("[StatsD] failed to flush metrics: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Flush gathers the metrics and sends them to the StatsD server.
+func (e *Exporter) Flush() error {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		// Gather returns the metrics it could collect, even when some collectors failed
+		e.logger.Warnf("[StatsD] failed to gather some metrics: %v", err)
+	}
+
+	e.mu.Lock()
+	lines := make([]string, 0, len(families))
+
+	for _, family := range families {
+		if !e.exported(family.GetName()) {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			lines = e.appendLines(lines, family.GetType(), e.statName(family.GetName(), metric), metric)
+		}
+	}
+	e.mu.Unlock()
+
+	return e.send(lines)
+}
+
+// exported returns whether the metric with the given name is exported to StatsD.
+func (e *Exporter) exported(name string) bool {
+	if len(e.metrics) == 0 {
+		return true
+	}
+
+	for _, prefix := range e.metrics {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendLines appends the StatsD lines of a single metric to the lines.
+func (e *Exporter) appendLines(lines []string, metricType dto.MetricType, name string, metric *dto.Metric) []string {
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		if delta := e.delta(name, metric.GetCounter().GetValue()); delta > 0 {
+			lines = append(lines, name+":"+formatValue(delta)+"|c")
+		}
+	case dto.MetricType_GAUGE:
+		lines = appendGauge(lines, name, metric.GetGauge().GetValue())
+	case dto.MetricType_UNTYPED:
+		lines = appendGauge(lines, name, metric.GetUntyped().GetValue())
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		lines = e.appendObservations(lines, name, float64(metric.GetHistogram().GetSampleCount()), metric.GetHistogram().GetSampleSum())
+	case dto.MetricType_SUMMARY:
+		lines = e.appendObservations(lines, name, float64(metric.GetSummary().GetSampleCount()), metric.GetSummary().GetSampleSum())
+	}
+
+	return lines
+}
+
+// appendObservations appends the number of observations since the previous flush, and their mean.
+func (e *Exporter) appendObservations(lines []string, name string, count float64, sum float64) []string {
+	countDelta := e.delta(name+".count", count)
+	sumDelta := e.delta(name+".sum", sum)
+
+	if countDelta <= 0 {
+		return lines
+	}
+
+	lines = append(lines, name+".count:"+formatValue(countDelta)+"|c")
+
+	return appendGauge(lines, name+".mean", sumDelta/countDelta)
+}
+
+// delta returns the increase of a cumulative value since the previous flush. When the value decreased,
+// the metric has been reset and the full value is returned.
+func (e *Exporter) delta(name string, value float64) float64 {
+	previous := e.previous[name]
+	e.previous[name] = value
+
+	if value < previous {
+		return value
+	}
+
+	return value - previous
+}
+
+// appendGauge appends a gauge line. StatsD interprets gauge values with a sign as a change of the
+// gauge, so a negative gauge is first reset to 0.
+func appendGauge(lines []string, name string, value float64) []string {
+	if value < 0 {
+		lines = append(lines, name+":0|g")
+	}
+
+	return append(lines, name+":"+formatValue(value)+"|g")
+}
+
+// statName returns the StatsD name of a metric, the prefix, the metric name and the label values.
+func (e *Exporter) statName(name string, metric *dto.Metric) string {
+	var sb strings.Builder
+
+	if e.prefix != "" {
+		sb.WriteString(sanitize(e.prefix))
+		sb.WriteByte('.')
+	}
+
+	sb.WriteString(sanitize(name))
+
+	for _, label := range metric.GetLabel() {
+		if value := label.GetValue(); value != "" {
+			sb.WriteByte('.')
+			sb.WriteString(sanitize(value))
+		}
+	}
+
+	return sb.String()
+}
+
+// send writes the lines to the StatsD server, batching as many lines as fit in a single packet.
+func (e *Exporter) send(lines []string) error {
+	var packet strings.Builder
+
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+
+		_, err := e.conn.Write([]byte(packet.String()))
+		packet.Reset()
+
+		if err != nil {
+			return errors.NewServiceError("failed to send metrics to statsd", err)
+		}
+
+		return nil
+	}
+
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxPacketSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+
+		packet.WriteString(line)
+	}
+
+	return flush()
+}
+
+// sanitize replaces the characters that have a special meaning in the StatsD protocol, or are not
+// supported in metric names by common StatsD servers, with an underscore.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// formatValue formats a metric value in the shortest representation.
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,188 @@
+package statsd
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/teranode/ulogger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newListener starts a mock StatsD server, returning its address and a function that reads the lines
+// of the next packets received
+func newListener(t *testing.T) (string, func() []string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	read := func() []string {
+		var lines []string
+
+		buf := make([]byte, 65536)
+
+		// read until no more packets arrive, the lines of a single flush can be split over packets
+		deadline := time.Now().Add(2 * time.Second)
+
+		for {
+			require.NoError(t, conn.SetReadDeadline(deadline))
+
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				break
+			}
+
+			lines = append(lines, strings.Split(string(buf[:n]), "\n")...)
+			deadline = time.Now().Add(100 * time.Millisecond)
+		}
+
+		sort.Strings(lines)
+
+		return lines
+	}
+
+	return conn.LocalAddr().String(), read
+}
+
+func TestExporter(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	validated := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "teranode",
+		Subsystem: "validator",
+		Name:      "transactions",
+	}, []string{"status"})
+
+	queued := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "teranode",
+		Subsystem: "validator",
+		Name:      "queued",
+	})
+
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "teranode",
+		Subsystem: "validator",
+		Name:      "transactions_validate",
+	})
+
+	ignored := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "teranode",
+		Subsystem: "p2p",
+		Name:      "messages",
+	})
+
+	registry.MustRegister(validated, queued, latency, ignored)
+
+	endpoint, read := newListener(t)
+
+	exporter, err := NewExporter(ulogger.TestLogger{}, endpoint, "node1", []string{"teranode_validator_"}, time.Hour, registry)
+	require.NoError(t, err)
+
+	validated.WithLabelValues("ok").Add(3)
+	validated.WithLabelValues("rejected").Inc()
+	queued.Set(-2)
+	latency.Observe(0.25)
+	latency.Observe(0.75)
+	ignored.Inc()
+
+	require.NoError(t, exporter.Flush())
+
+	assert.Equal(t, []string{
+		"node1.teranode_validator_queued:-2|g",
+		"node1.teranode_validator_queued:0|g",
+		"node1.teranode_validator_transactions.ok:3|c",
+		"node1.teranode_validator_transactions.rejected:1|c",
+		"node1.teranode_validator_transactions_validate.count:2|c",
+		"node1.teranode_validator_transactions_validate.mean:0.5|g",
+	}, read())
+
+	// only the increase since the previous flush is sent for counters and histograms
+	validated.WithLabelValues("ok").Add(2)
+	queued.Set(5)
+	latency.Observe(0.125)
+
+	require.NoError(t, exporter.Flush())
+
+	assert.Equal(t, []string{
+		"node1.teranode_validator_queued:5|g",
+		"node1.teranode_validator_transactions.ok:2|c",
+		"node1.teranode_validator_transactions_validate.count:1|c",
+		"node1.teranode_validator_transactions_validate.mean:0.125|g",
+	}, read())
+}
+
+func TestExporterStart(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	rejected := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "teranode",
+		Subsystem: "validator",
+		Name:      "invalid_transactions",
+	})
+	registry.MustRegister(rejected)
+
+	endpoint, read := newListener(t)
+
+	exporter, err := NewExporter(ulogger.TestLogger{}, endpoint, "", nil, 10*time.Millisecond, registry)
+	require.NoError(t, err)
+
+	rejected.Inc()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exporter.Start(ctx)
+
+	assert.Equal(t, []string{"teranode_validator_invalid_transactions:1|c"}, read())
+}
+
+func TestExporterBatchesLinesInPackets(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "teranode",
+		Subsystem: "validator",
+		Name:      "transactions_with_a_long_name_to_fill_packets",
+	}, []string{"peer"})
+	registry.MustRegister(counter)
+
+	for i := 0; i < 100; i++ {
+		counter.WithLabelValues("peer" + string(rune('a'+i%26)) + string(rune('a'+i/26))).Inc()
+	}
+
+	endpoint, read := newListener(t)
+
+	exporter, err := NewExporter(ulogger.TestLogger{}, endpoint, "", nil, time.Hour, registry)
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.Flush())
+
+	lines := read()
+	require.Len(t, lines, 100)
+
+	for _, line := range lines {
+		assert.True(t, strings.HasSuffix(line, ":1|c"), line)
+	}
+}
+
+func TestNewExporterInvalidConfig(t *testing.T) {
+	_, err := NewExporter(ulogger.TestLogger{}, "localhost:8125", "", nil, 0, nil)
+	require.Error(t, err)
+
+	_, err = NewExporter(ulogger.TestLogger{}, "not a valid endpoint", "", nil, time.Second, nil)
+	require.Error(t, err)
+}
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "teranode_validator", sanitize("teranode_validator"))
+	assert.Equal(t, "a_b_c_d", sanitize("a:b|c@d"))
+	assert.Equal(t, "peer_1.2.3.4_8333", sanitize("peer 1.2.3.4:8333"))
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// CorrelationIDHeader is the name of the HTTP header, gRPC metadata key and Kafka message header that carries the
+// correlation id of a transaction, used to follow the transaction through the logs of all services it passes.
+const CorrelationIDHeader = "x-correlation-id"
+
+// maxCorrelationIDLength limits the length in bytes of correlation ids accepted from clients, to keep the logs readable
+const maxCorrelationIDLength = 128
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a context carrying the given correlation id. The id is also added to the
+// outgoing gRPC metadata, so it is passed on to every service called with the returned context.
+//
+// The id is received from clients, it is sanitized before it is logged or passed on: control characters and
+// invalid UTF-8 are removed and an id longer than 128 bytes is truncated on a character boundary. An id that is
+// empty after sanitizing returns the context unchanged.
+//
+// Callers only set the id when correlation ids are enabled, see settings.TracingCorrelationIDsEnabled.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	id = sanitizeCorrelationID(id)
+	if id == "" {
+		return ctx
+	}
+
+	ctx = context.WithValue(ctx, correlationIDKey{}, id)
+
+	return metadata.AppendToOutgoingContext(ctx, CorrelationIDHeader, id)
+}
+
+// CorrelationIDFromContext returns the correlation id carried by the context, set with ContextWithCorrelationID.
+// Returns an empty string when the context does not carry a correlation id.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+
+	return id
+}
+
+// IncomingCorrelationID returns the sanitized correlation id of an incoming gRPC request, or an empty string when
+// the request did not carry one. Servers only use it when correlation ids are enabled, usually to set the id of
+// the context of the request with ContextWithCorrelationID.
+func IncomingCorrelationID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(CorrelationIDHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return sanitizeCorrelationID(values[0])
+}
+
+// sanitizeCorrelationID removes the control characters and invalid UTF-8 of a correlation id, so it cannot forge
+// log lines, and truncates it to maxCorrelationIDLength bytes without splitting a character.
+func sanitizeCorrelationID(id string) string {
+	var sb strings.Builder
+
+	for i, w := 0, 0; i < len(id); i += w {
+		r, width := utf8.DecodeRuneInString(id[i:])
+		w = width
+
+		if (r == utf8.RuneError && width == 1) || unicode.IsControl(r) {
+			continue
+		}
+
+		if sb.Len()+width > maxCorrelationIDLength {
+			break
+		}
+
+		sb.WriteString(id[i : i+width])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCorrelationID(t *testing.T) {
+	t.Run("empty id leaves the context unchanged", func(t *testing.T) {
+		ctx := context.Background()
+
+		assert.Equal(t, ctx, ContextWithCorrelationID(ctx, ""))
+		assert.Empty(t, CorrelationIDFromContext(ctx))
+	})
+
+	t.Run("id is carried by the context", func(t *testing.T) {
+		ctx := ContextWithCorrelationID(context.Background(), "abc")
+
+		assert.Equal(t, "abc", CorrelationIDFromContext(ctx))
+	})
+
+	t.Run("long ids are truncated", func(t *testing.T) {
+		ctx := ContextWithCorrelationID(context.Background(), strings.Repeat("a", 200))
+
+		assert.Len(t, CorrelationIDFromContext(ctx), maxCorrelationIDLength)
+	})
+
+	t.Run("long ids are truncated on a character boundary", func(t *testing.T) {
+		// every character takes 3 bytes, 128 is not a multiple of 3
+		ctx := ContextWithCorrelationID(context.Background(), strings.Repeat("€", 100))
+
+		id := CorrelationIDFromContext(ctx)
+		assert.True(t, utf8.ValidString(id))
+		assert.Equal(t, strings.Repeat("€", maxCorrelationIDLength/3), id)
+	})
+
+	t.Run("control characters are removed", func(t *testing.T) {
+		ctx := ContextWithCorrelationID(context.Background(), "abc\n[ERROR] forged\r\x00\x1b[31m\xff")
+
+		assert.Equal(t, "abc[ERROR] forged[31m", CorrelationIDFromContext(ctx))
+	})
+
+	t.Run("id of only control characters leaves the context unchanged", func(t *testing.T) {
+		ctx := context.Background()
+
+		assert.Equal(t, ctx, ContextWithCorrelationID(ctx, "\n\t"))
+	})
+
+	t.Run("id is passed on to called services", func(t *testing.T) {
+		ctx := ContextWithCorrelationID(context.Background(), "abc")
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		require.True(t, ok)
+
+		// the called service receives the outgoing metadata as incoming metadata, it is only carried by the
+		// context of the request once the service sets it
+		serverCtx := metadata.NewIncomingContext(context.Background(), md)
+		assert.Equal(t, "abc", IncomingCorrelationID(serverCtx))
+		assert.Empty(t, CorrelationIDFromContext(serverCtx))
+
+		serverCtx = ContextWithCorrelationID(serverCtx, IncomingCorrelationID(serverCtx))
+		assert.Equal(t, "abc", CorrelationIDFromContext(serverCtx))
+
+		md, ok = metadata.FromOutgoingContext(serverCtx)
+		require.True(t, ok)
+		assert.Equal(t, []string{"abc"}, md.Get(CorrelationIDHeader))
+	})
+
+	t.Run("incoming ids are sanitized", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CorrelationIDHeader, "abc\ndef"))
+
+		assert.Equal(t, "abcdef", IncomingCorrelationID(ctx))
+		assert.Empty(t, IncomingCorrelationID(context.Background()))
+	})
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// ValidationLimiter caps the number of transaction validations running at the same time. A slot is acquired
+// before the CPU bound checks of a transaction, like its script verification, and released when they are done.
+// Sharing one limiter between validations bounds the CPU contention between them, whichever block or subtree
+// they belong to.
+//
+// Only the leaf validation work holds a slot. Work holding a slot must not wait for other work that needs a
+// slot, like a batch sent to the validator, that could deadlock once all slots are held by waiting work.
+//
+// A nil ValidationLimiter does not limit anything.
+type ValidationLimiter struct {
+	slots *semaphore.Weighted
+}
+
+// NewValidationLimiter creates a limiter allowing maxGoroutines concurrent validations.
+// Returns nil, a limiter that does not limit anything, when maxGoroutines is not positive.
+func NewValidationLimiter(maxGoroutines int) *ValidationLimiter {
+	if maxGoroutines <= 0 {
+		return nil
+	}
+
+	return &ValidationLimiter{
+		slots: semaphore.NewWeighted(int64(maxGoroutines)),
+	}
+}
+
+// Acquire waits for a free slot. The returned release function must be called once the validation work is
+// done, it is safe to call it more than once.
+//
+// Parameters:
+//   - ctx: Context to stop waiting for a slot
+//
+// Returns:
+//   - func(): Function that releases the slot
+//   - error: The context error if the context is cancelled while waiting for a slot
+func (l *ValidationLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if err := l.slots.Acquire(ctx, 1); err != nil {
+		return func() {}, err
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			l.slots.Release(1)
+		})
+	}, nil
+}
+
+// validationLimiter is the node wide limiter shared by all validations, nil until initialized.
+var validationLimiter atomic.Pointer[ValidationLimiter]
+
+// InitValidationLimiter sets the node wide cap on concurrent validations, shared by all validations of the
+// process. A maxGoroutines that is not positive removes the cap. It should be called at startup, before
+// validations are running, validations waiting for a slot keep the limiter they started waiting on.
+func InitValidationLimiter(maxGoroutines int) {
+	validationLimiter.Store(NewValidationLimiter(maxGoroutines))
+}
+
+// AcquireValidation waits for a free slot of the node wide validation limiter, see ValidationLimiter.Acquire.
+func AcquireValidation(ctx context.Context) (func(), error) {
+	return validationLimiter.Load().Acquire(ctx)
+}
@@ -0,0 +1,228 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestValidationLimiter(t *testing.T) {
+	t.Run("concurrent validations share the cap", func(t *testing.T) {
+		const (
+			maxGoroutines = 4
+			validations   = 3 // e.g. subtrees of different blocks validated at the same time
+			txsPerLevel   = 50
+		)
+
+		limiter := NewValidationLimiter(maxGoroutines)
+
+		var (
+			running    atomic.Int32
+			maxRunning atomic.Int32
+			validated  atomic.Int32
+		)
+
+		validate := func() error {
+			release, err := limiter.Acquire(context.Background())
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				observed := maxRunning.Load()
+				if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			validated.Add(1)
+
+			return nil
+		}
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < validations; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				var g errgroup.Group
+
+				for j := 0; j < txsPerLevel; j++ {
+					g.Go(validate)
+				}
+
+				assert.NoError(t, g.Wait())
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(validations*txsPerLevel), validated.Load())
+		assert.LessOrEqual(t, maxRunning.Load(), int32(maxGoroutines))
+		assert.Equal(t, int32(maxGoroutines), maxRunning.Load(), "the cap should be reached with this many validations")
+	})
+
+	t.Run("nested validations do not deadlock", func(t *testing.T) {
+		const (
+			maxGoroutines = 2
+			subtreeTxs    = 20 // more concurrent subtree transactions than slots
+			batchSize     = 3
+		)
+
+		InitValidationLimiter(maxGoroutines)
+		defer InitValidationLimiter(0)
+
+		var validated atomic.Int32
+
+		// the validator batch handler, validating every transaction of the batch in its own goroutine, only the
+		// checks of a transaction hold a slot
+		validateBatch := func(ctx context.Context) error {
+			g, gCtx := errgroup.WithContext(ctx)
+
+			for i := 0; i < batchSize; i++ {
+				g.Go(func() error {
+					release, err := AcquireValidation(gCtx)
+					if err != nil {
+						return err
+					}
+					defer release()
+
+					time.Sleep(time.Millisecond)
+					validated.Add(1)
+
+					return nil
+				})
+			}
+
+			return g.Wait()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// subtree validation sending every transaction to the validator, in the same process
+		g, gCtx := errgroup.WithContext(ctx)
+
+		for i := 0; i < subtreeTxs; i++ {
+			g.Go(func() error {
+				return validateBatch(gCtx)
+			})
+		}
+
+		require.NoError(t, g.Wait())
+		assert.Equal(t, int32(subtreeTxs*batchSize), validated.Load())
+	})
+
+	t.Run("nil limiter does not limit", func(t *testing.T) {
+		limiter := NewValidationLimiter(0)
+		require.Nil(t, limiter)
+
+		var g errgroup.Group
+
+		release := make(chan struct{})
+
+		var started sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			started.Add(1)
+
+			g.Go(func() error {
+				releaseSlot, err := limiter.Acquire(context.Background())
+				if err != nil {
+					return err
+				}
+				defer releaseSlot()
+
+				started.Done()
+				<-release
+
+				return nil
+			})
+		}
+
+		// all validations are running at the same time
+		started.Wait()
+		close(release)
+		require.NoError(t, g.Wait())
+	})
+
+	t.Run("cancelled while waiting for a slot", func(t *testing.T) {
+		limiter := NewValidationLimiter(1)
+
+		release, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = limiter.Acquire(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// releasing more than once only frees a single slot
+		release()
+		release()
+
+		release, err = limiter.Acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = limiter.Acquire(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release()
+	})
+
+	t.Run("node wide limiter", func(t *testing.T) {
+		InitValidationLimiter(2)
+		defer InitValidationLimiter(0)
+
+		var (
+			g          errgroup.Group
+			running    atomic.Int32
+			maxRunning atomic.Int32
+		)
+
+		for i := 0; i < 20; i++ {
+			g.Go(func() error {
+				release, err := AcquireValidation(context.Background())
+				if err != nil {
+					return err
+				}
+				defer release()
+
+				current := running.Add(1)
+				defer running.Add(-1)
+
+				for {
+					observed := maxRunning.Load()
+					if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+						break
+					}
+				}
+
+				time.Sleep(time.Millisecond)
+
+				return nil
+			})
+		}
+
+		require.NoError(t, g.Wait())
+		assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	})
+}